@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"os"
 	"time"
@@ -22,27 +23,43 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	adminnetworkpolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/adminnetworkpolicy"
+	antreapolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/antreapolicy"
+	commonctl "github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	defaultdenycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/defaultdeny"
+	gatewaypolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/gatewaypolicy"
+	idspolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/idspolicy"
 	ippool2 "github.com/vmware-tanzu/nsx-operator/pkg/controllers/ippool"
 	namespacecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/namespace"
 	networkpolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/networkpolicy"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/node"
+	nsxoperatorconfigcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/nsxoperatorconfig"
 	nsxserviceaccountcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/nsxserviceaccount"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/pod"
+	policyprofilecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/policyprofile"
+	securityexclusioncontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/securityexclusion"
 	securitypolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/securitypolicy"
+	serviceexposurecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/serviceexposure"
 	staticroutecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/staticroute"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnet"
+	subnetpolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetpolicy"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetset"
 	vpccontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/vpc"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	"github.com/vmware-tanzu/nsx-operator/pkg/migration"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/catalog"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ippool"
 	nodeservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/node"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/nsxserviceaccount"
+	securityexclusionservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securityexclusion"
+	securitypolicyservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
 	subnetservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+	subnetpolicyservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetpolicy"
 	subnetportservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
@@ -132,7 +149,7 @@ func StartVPCController(mgr ctrl.Manager, vpcService *vpc.VPCService) {
 	}
 }
 
-func StartNamespaceController(mgr ctrl.Manager, cf *config.NSXOperatorConfig, vpcService common.VPCServiceProvider) {
+func StartNamespaceController(mgr ctrl.Manager, cf *config.NSXOperatorConfig, vpcService common.VPCServiceProvider) *namespacecontroller.NamespaceReconciler {
 	nsReconciler := &namespacecontroller.NamespaceReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
@@ -144,6 +161,7 @@ func StartNamespaceController(mgr ctrl.Manager, cf *config.NSXOperatorConfig, vp
 		log.Error(err, "failed to create namespace controller", "controller", "Namespace")
 		os.Exit(1)
 	}
+	return nsReconciler
 }
 
 func main() {
@@ -154,7 +172,7 @@ func main() {
 		Metrics:                 metricsserver.Options{BindAddress: config.MetricsAddr},
 		LeaderElection:          cf.HAEnabled(),
 		LeaderElectionNamespace: nsxOperatorNamespace,
-		LeaderElectionID:        "nsx-operator",
+		LeaderElectionID:        cf.LeaderElectionID(),
 	})
 	if err != nil {
 		log.Error(err, "failed to init manager")
@@ -175,9 +193,45 @@ func main() {
 		NSXConfig: cf,
 	}
 
-	checkLicense(nsxClient, cf.LicenseValidationInterval)
+	checkLicense(nsxClient)
+
+	if err := nsxClient.ValidateDomainAndEnforcementPoint(); err != nil {
+		log.Error(err, "invalid NSX domain or enforcement point")
+		os.Exit(1)
+	}
+	if cf.ValidatePayloads {
+		// the payloads are sent without validation if the spec can't be downloaded, e.g. from an older NSX
+		if err := nsxClient.LoadSchema(); err != nil {
+			log.Error(err, "failed to load NSX OpenAPI schema, payloads are not validated")
+		}
+	}
+
+	enableWebhook := true
+	if _, err := os.Stat(config.WebhookCertDir); errors.Is(err, os.ErrNotExist) {
+		log.Error(err, "server cert not found, disabling webhook server", "cert", config.WebhookCertDir)
+		enableWebhook = false
+	}
+
+	// Clients of the additional NSX sites, the resources are targeted by the nsx.vmware.com/nsx_site annotation.
+	siteClients := nsx.NewSiteClients(cf, nsxClient)
+
+	// Migrate NSX resources created by older nsx-operator before the stores are initialized.
+	if err := migration.Run(context.Background(), commonService); err != nil {
+		log.Error(err, "failed to migrate NSX resources")
+		os.Exit(1)
+	}
+
+	if catalogService, err := catalog.GetCatalogService(commonService); err != nil {
+		log.Error(err, "failed to load NSX catalogs, references will not be validated locally")
+	} else {
+		go catalogService.PeriodicRefresh(make(chan bool), catalog.RefreshInterval)
+	}
 
 	var vpcService *vpc.VPCService
+	// objectCounters report the NSX objects created by nsx-operator
+	var objectCounters []common.ObjectCounter
+	// onboarder onboards a batch of namespaces by the admin API, with VPC only
+	var onboarder commonctl.NamespaceOnboarder
 
 	if cf.CoeConfig.EnableVPCNetwork {
 		// Check NSX version for VPC networking mode
@@ -187,11 +241,14 @@ func main() {
 		}
 		log.Info("VPC mode is enabled")
 
+		// The VPC store is used by the policy shard as well to find the VPCs the policies are realized in.
 		vpcService, err = vpc.InitializeVPC(commonService)
 		if err != nil {
 			log.Error(err, "failed to initialize vpc commonService", "controller", "VPC")
 			os.Exit(1)
 		}
+	}
+	if cf.CoeConfig.EnableVPCNetwork && cf.OwnsShard(config.ShardNetwork) {
 		subnetService, err := subnetservice.InitializeSubnetService(commonService)
 		if err != nil {
 			log.Error(err, "failed to initialize subnet commonService")
@@ -216,49 +273,305 @@ func main() {
 			log.Error(err, "failed to initialize staticroute commonService", "controller", "StaticRoute")
 			os.Exit(1)
 		}
+		subnetPolicyService, err := subnetpolicyservice.InitializeSubnetPolicy(commonService, vpcService)
+		if err != nil {
+			log.Error(err, "failed to initialize subnetpolicy commonService", "controller", "SubnetPolicy")
+			os.Exit(1)
+		}
 		// Start controllers which only supports VPC
 		StartVPCController(mgr, vpcService)
-		StartNamespaceController(mgr, cf, vpcService)
+		onboarder = StartNamespaceController(mgr, cf, vpcService)
 		// Start subnet/subnetset controller.
 		if err := subnet.StartSubnetController(mgr, subnetService, subnetPortService, vpcService); err != nil {
 			os.Exit(1)
 		}
-		enableWebhook := true
-		if _, err := os.Stat(config.WebhookCertDir); errors.Is(err, os.ErrNotExist) {
-			log.Error(err, "server cert not found, disabling webhook server", "cert", config.WebhookCertDir)
-			enableWebhook = false
-		}
 		if err := subnetset.StartSubnetSetController(mgr, subnetService, subnetPortService, vpcService, enableWebhook); err != nil {
 			os.Exit(1)
 		}
 
 		node.StartNodeController(mgr, nodeService)
 		staticroutecontroller.StartStaticRouteController(mgr, staticRouteService)
+		subnetpolicycontroller.StartSubnetPolicyController(mgr, subnetPolicyService,
+			commonctl.NewDeletionGuard(commonctl.MetricResTypeSubnetPolicy, cf, mgr.GetClient(),
+				mgr.GetEventRecorderFor("subnetpolicy-controller"), nsxOperatorNamespace))
 		subnetport.StartSubnetPortController(mgr, subnetPortService, subnetService, vpcService)
 		pod.StartPodController(mgr, subnetPortService, subnetService, vpcService, nodeService)
 		StartIPPoolController(mgr, ipPoolService, vpcService)
-		networkpolicycontroller.StartNetworkPolicyController(mgr, commonService, vpcService)
+		objectCounters = append(objectCounters, subnetPortService)
+	}
+	// securityPolicyReconciler is nil if the policy shard is run by the other replicas
+	var securityPolicyReconciler *securitypolicycontroller.SecurityPolicyReconciler
+	var snapshotter *securitypolicycontroller.Snapshotter
+	if cf.OwnsShard(config.ShardPolicy) {
+		// Adopt the NSX resources realized before the cluster was rebuilt, before the SecurityPolicies are reconciled.
+		snapshotter = &securitypolicycontroller.Snapshotter{
+			Client:    mgr.GetClient(),
+			Reader:    mgr.GetAPIReader(),
+			Service:   securitypolicyservice.GetSecurityService(commonService, vpcService),
+			Namespace: nsxOperatorNamespace,
+			Interval:  time.Duration(cf.SnapshotInterval) * time.Second,
+		}
+		if cf.EnableRestore {
+			if restored, err := snapshotter.Restore(context.Background()); err != nil {
+				log.Error(err, "failed to restore realized NSX resources from snapshot")
+				os.Exit(1)
+			} else {
+				log.Info("restored realized NSX resources from snapshot", "securitypolicies", restored)
+			}
+		}
+		// Start controllers which can run in non-VPC mode
+		// Back up the SecurityPolicies before they are deleted in bulk, it's disabled if neither backup_secret nor backup_dir is set.
+		backuper := securitypolicycontroller.NewBackuper(mgr.GetClient(), mgr.GetAPIReader(), nsxOperatorNamespace, cf.BackupSecret, cf.BackupDir)
+		// Pause the garbage collection deleting too many SecurityPolicies until it's confirmed on the nsx-operator namespace.
+		deletionGuard := commonctl.NewDeletionGuard(commonctl.MetricResTypeSecurityPolicy, cf, mgr.GetClient(),
+			mgr.GetEventRecorderFor("securitypolicy-controller"), nsxOperatorNamespace)
+		// Escalate the CRs stuck deleting due to NSX errors, the orphans are recorded in the nsx-operator namespace.
+		spStuckDeletion := commonctl.NewStuckDeletionPolicy(commonctl.MetricResTypeSecurityPolicy, cf, mgr.GetClient(),
+			mgr.GetEventRecorderFor("securitypolicy-controller"), nsxOperatorNamespace)
+		npStuckDeletion := commonctl.NewStuckDeletionPolicy(commonctl.MetricResTypeNetworkPolicy, cf, mgr.GetClient(),
+			mgr.GetEventRecorderFor("networkpolicy-controller"), nsxOperatorNamespace)
+		securityPolicyReconciler = securitypolicycontroller.StartSecurityPolicyController(mgr, commonService, vpcService, siteClients, backuper, deletionGuard,
+			spStuckDeletion, enableWebhook)
+		if cf.EnableVPCNetwork {
+			networkpolicycontroller.StartNetworkPolicyController(mgr, commonService, vpcService, npStuckDeletion)
+			serviceexposurecontroller.StartServiceExposureController(mgr, commonService, vpcService,
+				commonctl.NewDeletionGuard(commonctl.MetricResTypeServiceExposure, cf, mgr.GetClient(),
+					mgr.GetEventRecorderFor("serviceexposure-controller"), nsxOperatorNamespace))
+		} else if cf.EnableNetworkPolicy {
+			// The NetworkPolicies are realized in the NSX infra like the SecurityPolicies without VPC.
+			log.Info("NetworkPolicy translation is enabled")
+			networkpolicycontroller.StartNetworkPolicyController(mgr, commonService, vpcService, npStuckDeletion)
+		}
+		if cf.EnableAntreaPolicyConversion {
+			antreapolicycontroller.StartAntreaPolicyController(mgr)
+		}
+		// Materialize the SecurityPolicies of the PolicyProfiles in the Namespaces labeled with nsx.vmware.com/policy-profile.
+		policyprofilecontroller.StartPolicyProfileController(mgr)
+		if cf.EnableAdminNetworkPolicy && !cf.EnableVPCNetwork {
+			adminnetworkpolicycontroller.StartAdminNetworkPolicyController(mgr, commonService, vpcService)
+		}
+		// The DFW exclusion list is managed under the NSX infra, the SecurityExclusions are not supported with VPC.
+		if !cf.EnableVPCNetwork {
+			securityExclusionService, err := securityexclusionservice.InitializeSecurityExclusion(commonService)
+			if err != nil {
+				log.Error(err, "failed to initialize securityexclusion commonService", "controller", "SecurityExclusion")
+				os.Exit(1)
+			}
+			securityexclusioncontroller.StartSecurityExclusionController(mgr, securityExclusionService,
+				commonctl.NewDeletionGuard(commonctl.MetricResTypeSecurityExclusion, cf, mgr.GetClient(),
+					mgr.GetEventRecorderFor("securityexclusion-controller"), nsxOperatorNamespace))
+			// The gateway policies are created in the domain of the cluster under the NSX infra as well.
+			gatewaypolicycontroller.StartGatewayPolicyController(mgr, securitypolicyservice.GetSecurityService(commonService, vpcService),
+				commonctl.NewDeletionGuard(commonctl.MetricResTypeGatewayPolicy, cf, mgr.GetClient(),
+					mgr.GetEventRecorderFor("gatewaypolicy-controller"), nsxOperatorNamespace))
+			// So are the distributed IDS/IPS policies with their IDS profiles.
+			idspolicycontroller.StartIDSPolicyController(mgr, securitypolicyservice.GetSecurityService(commonService, vpcService),
+				commonctl.NewDeletionGuard(commonctl.MetricResTypeIDSPolicy, cf, mgr.GetClient(),
+					mgr.GetEventRecorderFor("idspolicy-controller"), nsxOperatorNamespace))
+		}
+		// Deny the traffic of the Pods of the Namespaces annotated with nsx.vmware.com/default_deny.
+		defaultdenycontroller.StartDefaultDenyController(mgr, commonService, vpcService)
+		objectCounters = append(objectCounters, securitypolicyservice.GetSecurityService(commonService, vpcService))
 	}
-	// Start controllers which can run in non-VPC mode
-	securitypolicycontroller.StartSecurityPolicyController(mgr, commonService, vpcService)
 
 	// Start the NSXServiceAccount controller.
-	if cf.EnableAntreaNSXInterworking {
+	if cf.EnableAntreaNSXInterworking && cf.OwnsShard(config.ShardNetwork) {
 		StartNSXServiceAccountController(mgr, commonService)
 	}
 
+	// Start the NSXOperatorConfig controller to apply runtime settings.
+	nsxoperatorconfigcontroller.StartNSXOperatorConfigController(mgr, cf)
+
 	if metrics.AreMetricsExposed(cf) {
 		go updateHealthMetricsPeriodically(nsxClient)
 	}
 
+	// Report the leader status and the leadership transitions, the replicas which are not the leader report 0.
+	metrics.GaugeSet(cf, metrics.LeaderStatus, 0)
+	if err := mgr.Add(&commonctl.LeaderElectionReporter{NSXConfig: cf}); err != nil {
+		log.Error(err, "failed to set up leader election reporter")
+		os.Exit(1)
+	}
+	// Watch the leaders of all the shards when the controllers are split across the replicas, it only runs on the leader.
+	if cf.Shard != "" {
+		if err := mgr.Add(&commonctl.ShardCoordinator{
+			Reader:    mgr.GetAPIReader(),
+			NSXConfig: cf,
+			Namespace: nsxOperatorNamespace,
+		}); err != nil {
+			log.Error(err, "failed to set up shard coordinator")
+			os.Exit(1)
+		}
+	}
+
+	// Summarize the NSX objects created by nsx-operator, it only runs on the leader. Each shard summarizes its own objects.
+	objectCountConfigMapName := commonctl.ObjectCountConfigMapName
+	if cf.Shard != "" {
+		objectCountConfigMapName += "-" + cf.Shard
+	}
+	if err := mgr.Add(&commonctl.ObjectCountReporter{
+		Client:        mgr.GetClient(),
+		Reader:        mgr.GetAPIReader(),
+		NSXConfig:     cf,
+		Namespace:     nsxOperatorNamespace,
+		ConfigMapName: objectCountConfigMapName,
+		Counters:      objectCounters,
+	}); err != nil {
+		log.Error(err, "failed to set up NSX object count reporter")
+		os.Exit(1)
+	}
+
+	// Pause the write operations to NSX while it's in maintenance mode, and resync the SecurityPolicies when it's back.
+	var onResume []func(ctx context.Context) error
+	if securityPolicyReconciler != nil {
+		onResume = append(onResume, func(ctx context.Context) error {
+			_, err := securityPolicyReconciler.Resync(ctx, "", "")
+			return err
+		})
+	}
+	if err := mgr.Add(&commonctl.MaintenanceMonitor{
+		Client:    mgr.GetClient(),
+		NSXConfig: cf,
+		Check:     nsxClient.Cluster.InMaintenanceMode,
+		OnResume:  onResume,
+	}); err != nil {
+		log.Error(err, "failed to set up NSX maintenance monitor")
+		os.Exit(1)
+	}
+
+	if cf.OwnsShard(config.ShardPolicy) {
+		// Generate the cluster-wide EnforcementReport for the compliance dashboards, it only runs on the leader.
+		if err := mgr.Add(&commonctl.EnforcementReporter{
+			Client:   mgr.GetClient(),
+			Reader:   mgr.GetAPIReader(),
+			Counters: objectCounters,
+		}); err != nil {
+			log.Error(err, "failed to set up enforcement reporter")
+			os.Exit(1)
+		}
+		// Generate the NamespaceNetworkStatus of each Namespace for the Namespace owners, it only runs on the leader.
+		if err := mgr.Add(&commonctl.NamespaceStatusReporter{
+			Client:   mgr.GetClient(),
+			Reader:   mgr.GetAPIReader(),
+			Counters: objectCounters,
+		}); err != nil {
+			log.Error(err, "failed to set up namespace status reporter")
+			os.Exit(1)
+		}
+
+		// Materialize the NSX Intelligence recommendations as SecurityPolicies pending for approval, it only runs on the leader.
+		if cf.RecommendationInterval > 0 {
+			if err := mgr.Add(&securitypolicycontroller.RecommendationIngester{
+				Client:    mgr.GetClient(),
+				Reader:    mgr.GetAPIReader(),
+				Source:    securitypolicyservice.GetSecurityService(commonService, vpcService).RecommendationSource(),
+				Namespace: nsxOperatorNamespace,
+				Interval:  time.Duration(cf.RecommendationInterval) * time.Second,
+			}); err != nil {
+				log.Error(err, "failed to set up NSX Intelligence recommendation ingester")
+				os.Exit(1)
+			}
+		}
+
+		// Publish the DFW draft the changes of the SecurityPolicies are staged in, it only runs on the leader.
+		if securityService := securitypolicyservice.GetSecurityService(commonService, vpcService); securityService.DraftEnabled() {
+			if err := mgr.Add(&securitypolicycontroller.DraftPublisher{
+				Client:    mgr.GetClient(),
+				Recorder:  mgr.GetEventRecorderFor("securitypolicy-controller"),
+				Namespace: nsxOperatorNamespace,
+				Publish:   securityService.PublishDraft,
+				Interval:  time.Duration(cf.DFWDraftPublishInterval) * time.Second,
+			}); err != nil {
+				log.Error(err, "failed to set up DFW draft publisher")
+				os.Exit(1)
+			}
+		}
+
+		// Refuse the SecurityPolicies which would exhaust the NSX capacity before NSX rejects them, it only runs on the leader.
+		if cf.CapacityCheckInterval > 0 {
+			if err := mgr.Add(&commonctl.CapacityMonitor{
+				Client:    mgr.GetClient(),
+				NSXConfig: cf,
+				Check:     nsxClient.CapacityUsage,
+				Interval:  time.Duration(cf.CapacityCheckInterval) * time.Second,
+			}); err != nil {
+				log.Error(err, "failed to set up NSX capacity monitor")
+				os.Exit(1)
+			}
+		}
+
+		// Snapshot the NSX resources realized for the SecurityPolicies for disaster recovery, it only runs on the leader.
+		if cf.SnapshotInterval > 0 {
+			if err := mgr.Add(snapshotter); err != nil {
+				log.Error(err, "failed to set up realized NSX resources snapshot")
+				os.Exit(1)
+			}
+		}
+
+		// Serve the admin API with the webhook server cert, it only runs on the leader. The namespace onboarding
+		// is only served if the network shard is run by the same replica.
+		if config.AdminAddr != "" {
+			if !enableWebhook {
+				log.Info("server cert not found, disabling admin API", "cert", config.WebhookCertDir)
+			} else if err := mgr.Add(&securitypolicycontroller.AdminServer{
+				Addr:       config.AdminAddr,
+				CertDir:    config.WebhookCertDir,
+				Client:     mgr.GetClient(),
+				Reconciler: securityPolicyReconciler,
+				Counters:   objectCounters,
+				Onboarder:  onboarder,
+			}); err != nil {
+				log.Error(err, "failed to set up admin API")
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Generate the synthetic load and report the reconcile throughput to validate the NSX config maximums, it only runs on the leader.
+	if config.LoadGenSecurityPolicies > 0 || config.LoadGenSubnets > 0 {
+		subnets := config.LoadGenSubnets
+		if subnets > 0 && !cf.EnableVPCNetwork {
+			log.Info("VPC is not enabled, skipping Subnets in load generation", "subnets", subnets)
+			subnets = 0
+		}
+		if err := mgr.Add(&commonctl.LoadGenerator{
+			Client:           mgr.GetClient(),
+			Namespace:        nsxOperatorNamespace,
+			Namespaces:       config.LoadGenNamespaces,
+			SecurityPolicies: config.LoadGenSecurityPolicies,
+			Subnets:          subnets,
+			Timeout:          config.LoadGenTimeout,
+			Counters:         objectCounters,
+			ReportPath:       config.LoadGenReport,
+			Keep:             config.LoadGenKeep,
+		}); err != nil {
+			log.Error(err, "failed to set up load generator")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", nsxClient.NSXChecker.CheckNSXHealth); err != nil {
 		log.Error(err, "failed to set up health check")
 		os.Exit(1)
 	}
+	if len(siteClients.Sites()) > 0 {
+		if err := mgr.AddHealthzCheck("nsx-sites", siteClients.CheckSitesHealth); err != nil {
+			log.Error(err, "failed to set up NSX sites health check")
+			os.Exit(1)
+		}
+	}
+	if err := mgr.AddHealthzCheck("reconcile-stall", commonctl.CheckReconcileStall); err != nil {
+		log.Error(err, "failed to set up reconcile stall check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		log.Error(err, "failed to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("warmup", commonctl.CheckWarmup); err != nil {
+		log.Error(err, "failed to set up warm-up check")
+		os.Exit(1)
+	}
 
 	log.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -290,11 +603,18 @@ func updateHealthMetricsPeriodically(nsxClient *nsx.Client) {
 	}
 }
 
-func checkLicense(nsxClient *nsx.Client, interval int) {
+func checkLicense(nsxClient *nsx.Client) {
 	err := nsxClient.ValidateLicense(true)
 	if err != nil {
 		os.Exit(1)
 	}
+	go updateLicensePeriodically(nsxClient)
+}
+
+// licenseInterval is computed every time since license_validation_interval can be changed at runtime
+// from NSXOperatorConfig CR.
+func licenseInterval() time.Duration {
+	interval := cf.GetRuntimeConfig().LicenseValidationInterval
 	// if there is no dfw license enabled, check license more frequently
 	// if customer set it in config, use it, else use licenseTimeoutNoDFW
 	if interval == 0 {
@@ -304,13 +624,13 @@ func checkLicense(nsxClient *nsx.Client, interval int) {
 			interval = config.LicenseInterval
 		}
 	}
-	go updateLicensePeriodically(nsxClient, time.Duration(interval)*time.Second)
+	return time.Duration(interval) * time.Second
 }
 
-func updateLicensePeriodically(nsxClient *nsx.Client, interval time.Duration) {
+func updateLicensePeriodically(nsxClient *nsx.Client) {
 	for {
 		select {
-		case <-time.After(interval):
+		case <-time.After(licenseInterval()):
 		}
 		err := nsxClient.ValidateLicense(false)
 		if err != nil {
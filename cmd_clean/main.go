@@ -4,9 +4,12 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,6 +31,9 @@ import (
 // envoy thumbprint mode:
 //
 //	./clean -cluster=domain-c9:d75735a3-2847-45d2-a652-ef2d146afd54 -nsx-user=admin -nsx-passwd='xxx'  -mgr-ip=nsxmanager-ob-22386469-1-dev-integ-nsxt-8791 -envoyhost=localhost -envoyport=1080 -log-level=1 -thumbprint=8bc2fa2b5879c27b1180fa44e5f747832f2ded6be483e3c3d2c4816a38870868
+//
+// -dry-run lists the NSX resources tagged with the cluster without deleting them. -confirm asks to type the
+// cluster name to confirm the deletion, e.g. when running the cleanup by hand.
 var (
 	log         = logger.Log
 	cf          *config.NSXOperatorConfig
@@ -44,8 +50,22 @@ var (
 	cluster     string
 	envoyHost   string
 	envoyPort   int
+	dryRun      bool
+	confirmed   bool
+	batchSize   int
+	timeout     time.Duration
 )
 
+// confirm asks to type the cluster name to confirm the deletion of the NSX resources of the cluster.
+func confirm(cluster string) bool {
+	fmt.Printf("All the NSX resources of cluster %q will be deleted, type the cluster name to confirm: ", cluster)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(answer) == cluster
+}
+
 func main() {
 	flag.StringVar(&vcEndpoint, "vc-endpoint", "", "vc endpoint")
 	flag.StringVar(&vcSsoDomain, "vc-sso-domain", "", "vc sso domain")
@@ -61,6 +81,10 @@ func main() {
 	flag.StringVar(&envoyHost, "envoyhost", "", "envoy host")
 	flag.IntVar(&envoyPort, "envoyport", 0, "envoy port")
 	flag.IntVar(&config.LogLevel, "log-level", 0, "Use zap-core log system.")
+	flag.BoolVar(&dryRun, "dry-run", false, "list the nsx resources to delete without deleting them")
+	flag.BoolVar(&confirmed, "confirm", false, "ask to type the cluster name to confirm the deletion")
+	flag.IntVar(&batchSize, "batch-size", clean.DefaultBatchSize, "max number of nsx resources deleted by one hierarchical api call")
+	flag.DurationVar(&timeout, "timeout", time.Minute*5, "timeout of the cleanup")
 	flag.Parse()
 
 	cf = config.NewNSXOpertorConfig()
@@ -79,10 +103,14 @@ func main() {
 	cf.EnvoyPort = envoyPort
 
 	logf.SetLogger(logger.ZapLogger(cf.DefaultConfig.Debug, config.LogLevel))
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	if !dryRun && confirmed && !confirm(cluster) {
+		log.Info("cleanup is not confirmed, nothing is deleted")
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	err := clean.Clean(ctx, cf)
+	err := clean.Clean(ctx, cf, clean.Options{DryRun: dryRun, BatchSize: batchSize})
 	if err != nil {
 		log.Error(err, "failed to clean nsx resources")
 		os.Exit(1)
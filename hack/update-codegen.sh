@@ -11,7 +11,7 @@ OUTPUT_PKG=github.com/vmware-tanzu/nsx-operator/pkg/client
 GROUP=nsx.vmware.com
 
 SCRIPT_ROOT=$(dirname "${BASH_SOURCE[0]}")/..
-CODEGEN_PKG=$(go env GOMODCACHE)/k8s.io/code-generator@v0.27.1
+CODEGEN_PKG=$(go env GOMODCACHE)/k8s.io/code-generator@v0.28.3
 
 rm -fr "${APIS:?}/${GROUP:?}"
 rm -fr ./pkg/client
@@ -30,6 +30,7 @@ ${GROUP}:v1alpha1,v1alpha2 \
 mv ./${OUTPUT_PKG} ./pkg/
 cd ./pkg/client
 go mod init github.com/vmware-tanzu/nsx-operator/pkg/client
+go mod edit -replace github.com/vmware-tanzu/nsx-operator/pkg/apis=../apis
 go mod tidy
 cd ../../
 rm -rf ./github.com
\ No newline at end of file
@@ -0,0 +1,98 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IDSMode specifies what the IDS/IPS rules do with the traffic matching the signatures.
+type IDSMode string
+
+const (
+	// IDSModeDetect only raises the intrusion events.
+	IDSModeDetect IDSMode = "Detect"
+	// IDSModeDetectPrevent raises the intrusion events and drops the traffic matching the signatures.
+	IDSModeDetectPrevent IDSMode = "DetectPrevent"
+)
+
+// IDSSeverity is the severity of the IDS signatures.
+type IDSSeverity string
+
+const (
+	IDSSeverityCritical   IDSSeverity = "Critical"
+	IDSSeverityHigh       IDSSeverity = "High"
+	IDSSeverityMedium     IDSSeverity = "Medium"
+	IDSSeverityLow        IDSSeverity = "Low"
+	IDSSeveritySuspicious IDSSeverity = "Suspicious"
+)
+
+// IDSPolicySpec defines the desired state of IDSPolicy.
+type IDSPolicySpec struct {
+	// AppliedTo is a list of the Pods in the Namespace the IDS/IPS rules are enforced on.
+	// +kubebuilder:validation:MinItems=1
+	AppliedTo []SecurityPolicyTarget `json:"appliedTo"`
+	// Priority defines the order of policy enforcement.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	Priority int `json:"priority,omitempty"`
+	// Severities are the severities of the signatures the traffic is inspected for, Critical, High and Medium
+	// by default.
+	Severities []IDSSeverity `json:"severities,omitempty"`
+	// Rules is a list of the IDS/IPS rules.
+	// +kubebuilder:validation:MinItems=1
+	Rules []IDSPolicyRule `json:"rules"`
+}
+
+// IDSPolicyRule defines the traffic inspected by the IDS/IPS engine.
+type IDSPolicyRule struct {
+	// Name is the display name of this rule.
+	Name string `json:"name,omitempty"`
+	// Mode is Detect or DetectPrevent, Detect by default.
+	// +kubebuilder:validation:Enum=Detect;DetectPrevent
+	Mode IDSMode `json:"mode,omitempty"`
+	// Direction is the direction of the rule, including 'In' or 'Ingress', 'Out' or 'Egress'.
+	Direction *RuleDirection `json:"direction"`
+	// Sources defines the endpoints where the traffic is from. For ingress rule only.
+	Sources []SecurityPolicyPeer `json:"sources,omitempty"`
+	// Destinations defines the endpoints where the traffic is to. For egress rule only.
+	Destinations []SecurityPolicyPeer `json:"destinations,omitempty"`
+	// Ports is a list of ports to be matched.
+	Ports []SecurityPolicyPort `json:"ports,omitempty"`
+}
+
+// IDSPolicyStatus defines the observed state of IDSPolicy.
+type IDSPolicyStatus struct {
+	// Conditions describes current state of IDSPolicy.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// IDSPolicy is the Schema for the idspolicies API, it's realized as an NSX distributed IDS/IPS policy with
+// its own IDS profile, inspecting the traffic of the Pods it's applied to.
+type IDSPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IDSPolicySpec   `json:"spec"`
+	Status IDSPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IDSPolicyList contains a list of IDSPolicy.
+type IDSPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IDSPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IDSPolicy{}, &IDSPolicyList{})
+}
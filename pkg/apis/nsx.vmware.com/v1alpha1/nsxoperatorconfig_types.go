@@ -0,0 +1,96 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NSXOperatorConfigName is the name of the only NSXOperatorConfig honored by nsx-operator,
+// NSXOperatorConfig with other names are rejected.
+const NSXOperatorConfigName = "default"
+
+// NSXOperatorConfigSpec defines the settings of nsx-operator which can be changed without restarting it.
+// The settings overwrite the ones in the config file, a field not set falls back to the config file. The
+// other settings, e.g. the NSX endpoints and credentials, are only read from the config file on startup.
+type NSXOperatorConfigSpec struct {
+	// ReconcileStallTimeout is the seconds after which a pending or in-flight reconcile is
+	// reported as stalled by the health check.
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	ReconcileStallTimeout int `json:"reconcileStallTimeout,omitempty"`
+	// LicenseValidationInterval is the seconds between two NSX license validations.
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	LicenseValidationInterval int `json:"licenseValidationInterval,omitempty"`
+	// ForbiddenRules describes the traffic no SecurityPolicy may allow, the SecurityPolicy with a rule
+	// allowing the traffic is rejected by the validating webhook.
+	// +optional
+	ForbiddenRules []ForbiddenRule `json:"forbiddenRules,omitempty"`
+}
+
+// ForbiddenRule describes the traffic no SecurityPolicy may allow, an unset field matches any traffic.
+// E.g. the rule with cidr 0.0.0.0/0, protocol TCP and port 22 rejects the SecurityPolicy allowing SSH
+// from or to any IP.
+type ForbiddenRule struct {
+	// Name is the name of the forbidden rule reported in the rejection.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Direction is the direction of the traffic.
+	// +kubebuilder:validation:Enum=In;Ingress;Out;Egress
+	// +optional
+	Direction RuleDirection `json:"direction,omitempty"`
+	// CIDR is the peer of the traffic, an allow rule is forbidden if its peers cover the CIDR.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+	// Protocol is the protocol of the traffic.
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// Port is the port of the traffic.
+	// +optional
+	Port int `json:"port,omitempty"`
+	// EndPort is the end of the range of ports starting from Port.
+	// +optional
+	EndPort int `json:"endPort,omitempty"`
+}
+
+// NSXOperatorConfigStatus defines the observed state of NSXOperatorConfig.
+type NSXOperatorConfigStatus struct {
+	// Conditions describes whether the settings are applied or rejected.
+	Conditions []Condition `json:"conditions"`
+	// ObservedGeneration is the generation of the spec last handled by nsx-operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// NSXOperatorConfig is the Schema for the nsxoperatorconfigs API.
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the settings are applied"
+type NSXOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NSXOperatorConfigSpec   `json:"spec,omitempty"`
+	Status NSXOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NSXOperatorConfigList contains a list of NSXOperatorConfig.
+type NSXOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NSXOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NSXOperatorConfig{}, &NSXOperatorConfigList{})
+}
@@ -0,0 +1,58 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProtectedPolicyReference refers to a protected SecurityPolicy.
+type ProtectedPolicyReference struct {
+	// Namespace is the namespace of the SecurityPolicy.
+	Namespace string `json:"namespace"`
+	// Name is the name of the SecurityPolicy.
+	Name string `json:"name"`
+}
+
+// ProtectedPolicySpec defines the SecurityPolicies protected.
+type ProtectedPolicySpec struct {
+	// SecurityPolicies are the SecurityPolicies whose NSX policies are protected. The changes and the deletion
+	// of the SecurityPolicy CRs, including the deletion of their Namespaces, are not realized on NSX, and the
+	// NSX policies are not removed by the garbage collection.
+	SecurityPolicies []ProtectedPolicyReference `json:"securityPolicies,omitempty"`
+	// Override lifts the protection, so the changes and the deletion of the SecurityPolicies are realized again,
+	// while the ProtectedPolicy is kept for the SecurityPolicies to be protected later.
+	// +optional
+	Override bool `json:"override,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+
+// ProtectedPolicy is the Schema for the protectedpolicies API, it's created by the cluster admins to protect
+// the operator-managed NSX policies from the tenants.
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:printcolumn:name="Override",type=boolean,JSONPath=`.spec.override`,description="Whether the protection is lifted"
+type ProtectedPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProtectedPolicySpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// ProtectedPolicyList contains a list of ProtectedPolicy.
+type ProtectedPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProtectedPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProtectedPolicy{}, &ProtectedPolicyList{})
+}
@@ -21,6 +21,12 @@ const (
 	// RuleActionReject indicates that the traffic matching the rule must be rejected and the
 	// client will receive a response.
 	RuleActionReject RuleAction = "Reject"
+	// RuleActionRedirect indicates that the traffic matching the rule must be redirected to the
+	// NSX partner service in RedirectTo.
+	RuleActionRedirect RuleAction = "Redirect"
+	// RuleActionPass indicates that the traffic matching the rule skips the rest of the admin policies to
+	// the namespace policies. It's only for the rules converted from the AdminNetworkPolicies.
+	RuleActionPass RuleAction = "Pass"
 )
 
 // RuleDirection specifies the direction of traffic.
@@ -48,6 +54,12 @@ type SecurityPolicySpec struct {
 	AppliedTo []SecurityPolicyTarget `json:"appliedTo,omitempty"`
 	// Rules is a list of policy rules.
 	Rules []SecurityPolicyRule `json:"rules,omitempty"`
+	// AllowDNS injects a rule allowing the egress traffic of the policy targets to the cluster DNS service,
+	// which is kept up to date with the IPs and ports of the service. It requires the policy level 'Applied To'.
+	AllowDNS bool `json:"allowDNS,omitempty"`
+	// Logged is the default of the rules' Logged, the traffic matching the rules is logged in the NSX
+	// firewall logs if it's true.
+	Logged bool `json:"logged,omitempty"`
 }
 
 // SecurityPolicyRule defines a rule of SecurityPolicy.
@@ -67,6 +79,24 @@ type SecurityPolicyRule struct {
 	Ports []SecurityPolicyPort `json:"ports,omitempty"`
 	// Name is the display name of this rule.
 	Name string `json:"name,omitempty"`
+	// RedirectTo is the path of the NSX partner service chain the traffic matching the rule is redirected to,
+	// e.g. /infra/service-chains/ngfw-chain. It is required by the Redirect action only.
+	RedirectTo string `json:"redirectTo,omitempty"`
+	// AppIDs is a list of the NSX Layer-7 App IDs, e.g. SSL, DNS, HTTP, the traffic matching the rule is
+	// identified as. It can't be used with the Redirect action.
+	AppIDs []string `json:"appIds,omitempty"`
+	// Services is a list of the existing NSX services matched by the rule along with the ports, referred to by
+	// path, e.g. /infra/services/HTTPS, or by display name, e.g. HTTPS or the custom services created by the
+	// NSX admin.
+	Services []string `json:"services,omitempty"`
+	// RuleTag is set to the tag of the NSX rules, which is printed in the NSX firewall logs, so the logs can be
+	// filtered by the application-defined tags. It's followed by the correlation ID of the rule in the tag, and
+	// truncated to keep the tag within 32 characters.
+	// +kubebuilder:validation:MaxLength=32
+	RuleTag string `json:"ruleTag,omitempty"`
+	// Logged specifies if the traffic matching the rule is logged in the NSX firewall logs, it takes
+	// precedence over the policy level Logged.
+	Logged *bool `json:"logged,omitempty"`
 }
 
 // SecurityPolicyTarget defines the target endpoints to apply SecurityPolicy.
@@ -75,6 +105,9 @@ type SecurityPolicyTarget struct {
 	VMSelector *metav1.LabelSelector `json:"vmSelector,omitempty"`
 	// PodSelector uses label selector to select Pods.
 	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// All selects all the workloads of the cluster, it can't be set with the selectors. Only the users
+	// allowed to 'applyto-all' securitypolicies by RBAC can set it.
+	All bool `json:"all,omitempty"`
 }
 
 // SecurityPolicyPeer defines the source or destination of traffic.
@@ -87,13 +120,63 @@ type SecurityPolicyPeer struct {
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// IPBlocks is a list of IP CIDRs.
 	IPBlocks []IPBlock `json:"ipBlocks,omitempty"`
+	// Networks is a list of the cluster networks, which are expanded to the CIDRs of the networks
+	// configured for nsx-operator.
+	Networks []ClusterNetwork `json:"networks,omitempty"`
+	// IdentityGroups is a list of the paths of NSX Identity Firewall groups, e.g. the groups of Active Directory
+	// users, which match the traffic from the sessions of the users. For rule sources only.
+	IdentityGroups []string `json:"identityGroups,omitempty"`
+	// Workloads is a list of the Services, e.g. the headless Services, and the StatefulSets in the Namespace
+	// of the SecurityPolicy. The Pods of them are matched by their IPs, which are kept while the Pods are
+	// restarted.
+	Workloads []WorkloadReference `json:"workloads,omitempty"`
+	// FQDN is a domain name matched by the egress traffic, e.g. "www.example.com", or "*.example.com" matching
+	// its subdomains. For rule destinations only, and it can't be used with the other fields of the peer.
+	// +kubebuilder:validation:Pattern=`^(\*\.)?([a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}$`
+	FQDN string `json:"fqdn,omitempty"`
 }
 
+// WorkloadKind is the kind of the workload referred to by a SecurityPolicyPeer.
+// +kubebuilder:validation:Enum=Service;StatefulSet
+type WorkloadKind string
+
+const (
+	// WorkloadKindService refers to the Pods selected by a Service.
+	WorkloadKindService WorkloadKind = "Service"
+	// WorkloadKindStatefulSet refers to the Pods of a StatefulSet.
+	WorkloadKindStatefulSet WorkloadKind = "StatefulSet"
+)
+
+// WorkloadReference refers to a workload in the Namespace of the SecurityPolicy.
+type WorkloadReference struct {
+	// Kind is the kind of the workload.
+	Kind WorkloadKind `json:"kind"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+}
+
+// ClusterNetwork is a network of the cluster.
+// +kubebuilder:validation:Enum=ClusterNetwork;NodeNetwork;ServiceNetwork
+type ClusterNetwork string
+
+const (
+	// ClusterNetworkPod is the network of the Pods.
+	ClusterNetworkPod ClusterNetwork = "ClusterNetwork"
+	// ClusterNetworkNode is the network of the Nodes.
+	ClusterNetworkNode ClusterNetwork = "NodeNetwork"
+	// ClusterNetworkService is the network of the Service cluster IPs.
+	ClusterNetworkService ClusterNetwork = "ServiceNetwork"
+)
+
 // IPBlock describes a particular CIDR that is allowed or denied to/from the workloads matched by an AppliedTo.
 type IPBlock struct {
 	// CIDR is a string representing the IP Block.
 	// A valid example is "192.168.1.1/24".
 	CIDR string `json:"cidr"`
+	// Except is a list of the CIDRs within CIDR which are excluded from the IP Block, e.g. the gateway or
+	// the management ranges. Only IPv4 CIDRs support it.
+	// +optional
+	Except []string `json:"except,omitempty"`
 }
 
 // SecurityPolicyPort describes protocol and ports for traffic.
@@ -111,6 +194,170 @@ type SecurityPolicyPort struct {
 type SecurityPolicyStatus struct {
 	// Conditions describes current state of security policy.
 	Conditions []Condition `json:"conditions"`
+	// RuleBudgets reports the NSX objects generated for each rule.
+	RuleBudgets []RuleBudget `json:"ruleBudgets,omitempty"`
+	// Simulation reports the observed flows which would be blocked by the SecurityPolicy,
+	// it is set when the SecurityPolicy is annotated with nsx.vmware.com/simulate_hours.
+	Simulation *SimulationStatus `json:"simulation,omitempty"`
+	// SyncSummary summarizes the last sync attempts of the SecurityPolicy, the attempts are listed by the
+	// admin API of nsx-operator.
+	SyncSummary *SyncSummary `json:"syncSummary,omitempty"`
+	// DryRun previews the changes of the NSX resources which would be patched for the SecurityPolicy, it is set
+	// when the SecurityPolicy is annotated with nsx.vmware.com/dry_run: "true".
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// LintWarnings reports the rules which are realized but most likely not what the author meant, e.g. the rules
+	// shadowed by an earlier rule.
+	LintWarnings []string `json:"lintWarnings,omitempty"`
+	// RuleStats summarizes the hit statistics of the rules collected from NSX, it is set when the rule_stats_status
+	// config of nsx-operator is enabled.
+	RuleStats *RuleStatsSummary `json:"ruleStats,omitempty"`
+	// GroupMembers summarizes the effective members of the NSX groups of the SecurityPolicy read from NSX, it is set
+	// when the group_members_interval config of nsx-operator is set.
+	GroupMembers *GroupMembersSummary `json:"groupMembers,omitempty"`
+}
+
+// DryRunStatus previews the changes of the NSX resources of a SecurityPolicy without patching them.
+type DryRunStatus struct {
+	// ObservedGeneration is the generation of the SecurityPolicy previewed.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Summary counts the changes, e.g. "policy changed, rules 1 added/0 changed/0 removed, 2 groups touched".
+	Summary string `json:"summary,omitempty"`
+	// Changes lists the NSX resources which would be created, updated or deleted, it is truncated to 100 changes.
+	Changes []DryRunChange `json:"changes,omitempty"`
+	// Error describes why the NSX resources can't be built for the SecurityPolicy.
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunChange is a change of an NSX resource previewed by a dry run.
+type DryRunChange struct {
+	// Operation is one of Create, Update and Delete.
+	Operation string `json:"operation"`
+	// Resource is the type and the ID of the NSX resource, e.g. Rule/<id>.
+	Resource string `json:"resource"`
+	// Payload is the NSX resource which would be patched in JSON, it is empty for the deletion.
+	Payload string `json:"payload,omitempty"`
+}
+
+// SyncSummary summarizes the last sync attempts of a SecurityPolicy to NSX.
+type SyncSummary struct {
+	// Attempts is the count of the sync attempts summarized, the last 20 at most.
+	Attempts int `json:"attempts"`
+	// Failures is the count of the failed attempts.
+	Failures int `json:"failures"`
+	// Transitions is the count of the changes between success and failure of the successive attempts.
+	Transitions int `json:"transitions"`
+	// Flapping is true if the attempts change between success and failure 4 times or more.
+	Flapping bool `json:"flapping,omitempty"`
+	// LastFailureTime is the time of the last failed attempt.
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// LastError is the error of the last failed attempt.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// RuleBudget reports how many NSX objects a rule is expanded into.
+type RuleBudget struct {
+	// Name is the name of the rule.
+	Name string `json:"name,omitempty"`
+	// Index is the index of the rule in spec.rules.
+	Index int `json:"index"`
+	// Rules is the count of NSX rules, a rule with named port may expand to multiple NSX rules.
+	Rules int `json:"rules"`
+	// Groups is the count of NSX groups for appliedTo, sources and destinations.
+	Groups int `json:"groups"`
+	// Criteria is the total count of criteria in the NSX groups.
+	Criteria int `json:"criteria"`
+	// ServiceEntries is the total count of service entries in the NSX rules.
+	ServiceEntries int `json:"serviceEntries"`
+	// Warning describes the NSX scale limits the rule is approaching.
+	Warning string `json:"warning,omitempty"`
+}
+
+// RuleStatsSummary summarizes the hit statistics of the rules of a SecurityPolicy.
+type RuleStatsSummary struct {
+	// CollectedTime is the time the statistics are collected from NSX.
+	CollectedTime metav1.Time `json:"collectedTime"`
+	// UnusedRules is the count of the rules which have never been hit.
+	UnusedRules int `json:"unusedRules"`
+	// Rules are the statistics of each rule.
+	Rules []RuleStats `json:"rules,omitempty"`
+}
+
+// RuleStats reports the hit statistics of a rule, summed over the NSX rules it is expanded into. The counters are
+// kept by NSX and are reset when the NSX rules are recreated.
+type RuleStats struct {
+	// Name is the name of the rule.
+	Name string `json:"name,omitempty"`
+	// Index is the index of the rule in spec.rules.
+	Index int `json:"index"`
+	// HitCount is the count of the hits of the rule.
+	HitCount int64 `json:"hitCount"`
+	// PacketCount is the count of the packets processed by the rule.
+	PacketCount int64 `json:"packetCount"`
+	// ByteCount is the count of the bytes processed by the rule.
+	ByteCount int64 `json:"byteCount"`
+	// SessionCount is the count of the sessions processed by the rule.
+	SessionCount int64 `json:"sessionCount"`
+}
+
+// GroupMembersSummary summarizes the effective members of the NSX groups of a SecurityPolicy.
+type GroupMembersSummary struct {
+	// CollectedTime is the time the members are read from NSX.
+	CollectedTime metav1.Time `json:"collectedTime"`
+	// Groups are the members of each NSX group the SecurityPolicy refers to.
+	Groups []GroupMembers `json:"groups,omitempty"`
+}
+
+// GroupMembers reports the effective members of an NSX group, the lists are truncated to 20 members.
+type GroupMembers struct {
+	// Group is the ID of the NSX group.
+	Group string `json:"group"`
+	// Roles are where the group is referred to, e.g. appliedTo, rules[0].appliedTo, rules[0].source and
+	// rules[0].destination.
+	Roles []string `json:"roles,omitempty"`
+	// IPCount is the count of the effective IP addresses of the group.
+	IPCount int64 `json:"ipCount"`
+	// IPs are the first effective IP addresses of the group.
+	IPs []string `json:"ips,omitempty"`
+	// PortCount is the count of the effective ports of the group, i.e. the interfaces of the Pods and the VMs.
+	PortCount int64 `json:"portCount"`
+	// Ports are the display names of the first effective ports of the group.
+	Ports []string `json:"ports,omitempty"`
+	// Error describes why the members of the group can't be read from NSX.
+	Error string `json:"error,omitempty"`
+}
+
+// SimulationStatus reports the simulation of a SecurityPolicy against the flows observed by NSX.
+type SimulationStatus struct {
+	// ObservedGeneration is the generation of the SecurityPolicy simulated.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Hours is the count of hours of observed flows simulated.
+	Hours int `json:"hours"`
+	// StartTime is the start of the observed flows.
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the end of the observed flows.
+	EndTime metav1.Time `json:"endTime,omitempty"`
+	// Flows is the count of observed flows simulated.
+	Flows int `json:"flows"`
+	// BlockedFlowCount is the count of observed flows which would be blocked.
+	BlockedFlowCount int `json:"blockedFlowCount"`
+	// BlockedFlows lists the observed flows which would be blocked, it is truncated to 50 flows.
+	BlockedFlows []SimulatedFlow `json:"blockedFlows,omitempty"`
+	// Error describes why the observed flows can't be simulated.
+	Error string `json:"error,omitempty"`
+}
+
+// SimulatedFlow is an observed flow which would be blocked by a rule.
+type SimulatedFlow struct {
+	// Rule is the name of the rule, or the index in spec.rules if the rule has no name.
+	Rule string `json:"rule"`
+	// Source is the source of the flow, the Pod name in Namespace/Name format or the IP.
+	Source string `json:"source"`
+	// Destination is the destination of the flow, the Pod name in Namespace/Name format or the IP.
+	Destination string `json:"destination"`
+	// Protocol is the protocol of the flow.
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// Port is the destination port of the flow.
+	Port int `json:"port,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,66 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceExposureSpec defines the Service published by the Namespace and the consumers allowed to access it.
+type ServiceExposureSpec struct {
+	// Service is the name of the Service in the Namespace which is published, its Pods are selected by the
+	// selector of the Service and the traffic is allowed to the target ports of the Service.
+	// +kubebuilder:validation:MinLength=1
+	Service string `json:"service"`
+	// Consumers is a list of the consumers allowed to access the Service.
+	// +kubebuilder:validation:MinItems=1
+	Consumers []ServiceExposureConsumer `json:"consumers"`
+}
+
+// ServiceExposureConsumer selects the Pods of the consumer Namespaces allowed to access the Service.
+type ServiceExposureConsumer struct {
+	// NamespaceSelector selects the consumer Namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector selects the Pods in the consumer Namespaces, all the Pods are selected if it's not set.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// ServiceExposureStatus defines the observed state of ServiceExposure.
+type ServiceExposureStatus struct {
+	// Conditions describes current state of ServiceExposure.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// ConsumerNamespaces is the list of the consumer Namespaces the rules are realized in.
+	ConsumerNamespaces []string `json:"consumerNamespaces,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// ServiceExposure is the Schema for the serviceexposures API, it publishes a Service of the Namespace to the
+// selected consumer Namespaces.
+// +kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.service`,description="Service published"
+// +kubebuilder:printcolumn:name="Consumers",type=string,JSONPath=`.status.consumerNamespaces`,description="Consumer Namespaces"
+type ServiceExposure struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceExposureSpec   `json:"spec"`
+	Status ServiceExposureStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceExposureList contains a list of ServiceExposure.
+type ServiceExposureList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceExposure `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceExposure{}, &ServiceExposureList{})
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubnetPolicySpec defines the rules applied to all the traffic of the Subnets, regardless of the
+// Pods or VMs attached to them.
+type SubnetPolicySpec struct {
+	// Priority defines the order of policy enforcement.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	Priority int `json:"priority,omitempty"`
+	// Subnets is a list of the names of the Subnets in the Namespace the rules are applied to.
+	Subnets []string `json:"subnets,omitempty"`
+	// SubnetSets is a list of the names of the SubnetSets in the Namespace, the rules are applied to
+	// all the Subnets of the SubnetSets.
+	SubnetSets []string `json:"subnetSets,omitempty"`
+	// Rules is a list of policy rules.
+	Rules []SubnetPolicyRule `json:"rules,omitempty"`
+}
+
+// SubnetPolicyRule defines a rule of SubnetPolicy.
+type SubnetPolicyRule struct {
+	// Name is the display name of this rule.
+	Name string `json:"name,omitempty"`
+	// Action specifies the action to be applied on the rule.
+	// +kubebuilder:validation:Enum=Allow;Drop;Reject
+	Action *RuleAction `json:"action"`
+	// Direction is the direction of the rule, including 'In' or 'Ingress', 'Out' or 'Egress'.
+	Direction *RuleDirection `json:"direction"`
+	// IPBlocks is a list of the CIDRs the traffic is from for ingress rule, or to for egress rule.
+	// The traffic from or to any address is matched if it's empty.
+	IPBlocks []IPBlock `json:"ipBlocks,omitempty"`
+	// Ports is a list of ports to be matched, the named ports are not supported.
+	Ports []SecurityPolicyPort `json:"ports,omitempty"`
+}
+
+// SubnetPolicyStatus defines the observed state of SubnetPolicy.
+type SubnetPolicyStatus struct {
+	// Conditions describes current state of SubnetPolicy.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// SubnetPolicy is the Schema for the subnetpolicies API.
+// +kubebuilder:printcolumn:name="Subnets",type=string,JSONPath=`.spec.subnets`,description="Subnets the rules are applied to"
+// +kubebuilder:printcolumn:name="SubnetSets",type=string,JSONPath=`.spec.subnetSets`,description="SubnetSets the rules are applied to"
+type SubnetPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubnetPolicySpec   `json:"spec"`
+	Status SubnetPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SubnetPolicyList contains a list of SubnetPolicy.
+type SubnetPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubnetPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubnetPolicy{}, &SubnetPolicyList{})
+}
@@ -41,6 +41,16 @@ type VPCNetworkConfigurationSpec struct {
 	// Must be Public or Private.
 	// +kubebuilder:validation:Enum=Public;Private
 	DefaultSubnetAccessMode string `json:"defaultSubnetAccessMode,omitempty"`
+	// ShortID specifies Identifier to use when displaying VPC context in logs.
+	// Less than equal to 8 characters.
+	// +kubebuilder:validation:MaxLength=8
+	// +optional
+	ShortID string `json:"shortID,omitempty"`
+	// NSXDomain of the NSX-T Project the groups shared by the SecurityPolicies of the Namespace are created in.
+	// Defaults to the domain configured for nsx-operator.
+	// +kubebuilder:validation:Pattern=`^[^/\s]*$`
+	// +optional
+	NSXDomain string `json:"nsxDomain,omitempty"`
 }
 
 // VPCNetworkConfigurationStatus defines the observed state of VPCNetworkConfiguration
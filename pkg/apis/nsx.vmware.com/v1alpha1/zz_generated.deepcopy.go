@@ -82,41 +82,60 @@ func (in *DNSClientConfig) DeepCopy() *DNSClientConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPBlock) DeepCopyInto(out *IPBlock) {
+func (in *DryRunChange) DeepCopyInto(out *DryRunChange) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPBlock.
-func (in *IPBlock) DeepCopy() *IPBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunChange.
+func (in *DryRunChange) DeepCopy() *DryRunChange {
 	if in == nil {
 		return nil
 	}
-	out := new(IPBlock)
+	out := new(DryRunChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPool) DeepCopyInto(out *IPPool) {
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]DryRunChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnforcementReport) DeepCopyInto(out *EnforcementReport) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
-func (in *IPPool) DeepCopy() *IPPool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnforcementReport.
+func (in *EnforcementReport) DeepCopy() *EnforcementReport {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPool)
+	out := new(EnforcementReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IPPool) DeepCopyObject() runtime.Object {
+func (in *EnforcementReport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -124,31 +143,31 @@ func (in *IPPool) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPoolList) DeepCopyInto(out *IPPoolList) {
+func (in *EnforcementReportList) DeepCopyInto(out *EnforcementReportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IPPool, len(*in))
+		*out = make([]EnforcementReport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolList.
-func (in *IPPoolList) DeepCopy() *IPPoolList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnforcementReportList.
+func (in *EnforcementReportList) DeepCopy() *EnforcementReportList {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPoolList)
+	out := new(EnforcementReportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IPPoolList) DeepCopyObject() runtime.Object {
+func (in *EnforcementReportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -156,143 +175,238 @@ func (in *IPPoolList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPoolSpec) DeepCopyInto(out *IPPoolSpec) {
+func (in *EnforcementReportStatus) DeepCopyInto(out *EnforcementReportStatus) {
 	*out = *in
-	if in.Subnets != nil {
-		in, out := &in.Subnets, &out.Subnets
-		*out = make([]SubnetRequest, len(*in))
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceEnforcement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnprotectedNamespaces != nil {
+		in, out := &in.UnprotectedNamespaces, &out.UnprotectedNamespaces
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.Realization = in.Realization
+	if in.NSXObjects != nil {
+		in, out := &in.NSXObjects, &out.NSXObjects
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolSpec.
-func (in *IPPoolSpec) DeepCopy() *IPPoolSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnforcementReportStatus.
+func (in *EnforcementReportStatus) DeepCopy() *EnforcementReportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPoolSpec)
+	out := new(EnforcementReportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPoolStatus) DeepCopyInto(out *IPPoolStatus) {
+func (in *ForbiddenRule) DeepCopyInto(out *ForbiddenRule) {
 	*out = *in
-	if in.Subnets != nil {
-		in, out := &in.Subnets, &out.Subnets
-		*out = make([]SubnetResult, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForbiddenRule.
+func (in *ForbiddenRule) DeepCopy() *ForbiddenRule {
+	if in == nil {
+		return nil
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	out := new(ForbiddenRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPolicy) DeepCopyInto(out *GatewayPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPolicy.
+func (in *GatewayPolicy) DeepCopy() *GatewayPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPolicyList) DeepCopyInto(out *GatewayPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolStatus.
-func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPolicyList.
+func (in *GatewayPolicyList) DeepCopy() *GatewayPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPoolStatus)
+	out := new(GatewayPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXProxyEndpoint) DeepCopyInto(out *NSXProxyEndpoint) {
+func (in *GatewayPolicySpec) DeepCopyInto(out *GatewayPolicySpec) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]NSXProxyEndpointAddress, len(*in))
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]NSXProxyEndpointPort, len(*in))
-		copy(*out, *in)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpoint.
-func (in *NSXProxyEndpoint) DeepCopy() *NSXProxyEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPolicySpec.
+func (in *GatewayPolicySpec) DeepCopy() *GatewayPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXProxyEndpoint)
+	out := new(GatewayPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXProxyEndpointAddress) DeepCopyInto(out *NSXProxyEndpointAddress) {
+func (in *GatewayPolicyStatus) DeepCopyInto(out *GatewayPolicyStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpointAddress.
-func (in *NSXProxyEndpointAddress) DeepCopy() *NSXProxyEndpointAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPolicyStatus.
+func (in *GatewayPolicyStatus) DeepCopy() *GatewayPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXProxyEndpointAddress)
+	out := new(GatewayPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXProxyEndpointPort) DeepCopyInto(out *NSXProxyEndpointPort) {
+func (in *GroupMembers) DeepCopyInto(out *GroupMembers) {
 	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpointPort.
-func (in *NSXProxyEndpointPort) DeepCopy() *NSXProxyEndpointPort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembers.
+func (in *GroupMembers) DeepCopy() *GroupMembers {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXProxyEndpointPort)
+	out := new(GroupMembers)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXSecret) DeepCopyInto(out *NSXSecret) {
+func (in *GroupMembersSummary) DeepCopyInto(out *GroupMembersSummary) {
 	*out = *in
+	in.CollectedTime.DeepCopyInto(&out.CollectedTime)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GroupMembers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXSecret.
-func (in *NSXSecret) DeepCopy() *NSXSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembersSummary.
+func (in *GroupMembersSummary) DeepCopy() *GroupMembersSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXSecret)
+	out := new(GroupMembersSummary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXServiceAccount) DeepCopyInto(out *NSXServiceAccount) {
+func (in *IDSPolicy) DeepCopyInto(out *IDSPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccount.
-func (in *NSXServiceAccount) DeepCopy() *NSXServiceAccount {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDSPolicy.
+func (in *IDSPolicy) DeepCopy() *IDSPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXServiceAccount)
+	out := new(IDSPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NSXServiceAccount) DeepCopyObject() runtime.Object {
+func (in *IDSPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -300,31 +414,31 @@ func (in *NSXServiceAccount) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXServiceAccountList) DeepCopyInto(out *NSXServiceAccountList) {
+func (in *IDSPolicyList) DeepCopyInto(out *IDSPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NSXServiceAccount, len(*in))
+		*out = make([]IDSPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountList.
-func (in *NSXServiceAccountList) DeepCopy() *NSXServiceAccountList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDSPolicyList.
+func (in *IDSPolicyList) DeepCopy() *IDSPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXServiceAccountList)
+	out := new(IDSPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NSXServiceAccountList) DeepCopyObject() runtime.Object {
+func (in *IDSPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -332,70 +446,122 @@ func (in *NSXServiceAccountList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXServiceAccountSpec) DeepCopyInto(out *NSXServiceAccountSpec) {
+func (in *IDSPolicyRule) DeepCopyInto(out *IDSPolicyRule) {
 	*out = *in
+	if in.Direction != nil {
+		in, out := &in.Direction, &out.Direction
+		*out = new(RuleDirection)
+		**out = **in
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SecurityPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]SecurityPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]SecurityPolicyPort, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountSpec.
-func (in *NSXServiceAccountSpec) DeepCopy() *NSXServiceAccountSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDSPolicyRule.
+func (in *IDSPolicyRule) DeepCopy() *IDSPolicyRule {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXServiceAccountSpec)
+	out := new(IDSPolicyRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSXServiceAccountStatus) DeepCopyInto(out *NSXServiceAccountStatus) {
+func (in *IDSPolicySpec) DeepCopyInto(out *IDSPolicySpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]SecurityPolicyTarget, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.NSXManagers != nil {
-		in, out := &in.NSXManagers, &out.NSXManagers
-		*out = make([]string, len(*in))
+	if in.Severities != nil {
+		in, out := &in.Severities, &out.Severities
+		*out = make([]IDSSeverity, len(*in))
 		copy(*out, *in)
 	}
-	in.ProxyEndpoints.DeepCopyInto(&out.ProxyEndpoints)
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]NSXSecret, len(*in))
-		copy(*out, *in)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]IDSPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountStatus.
-func (in *NSXServiceAccountStatus) DeepCopy() *NSXServiceAccountStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDSPolicySpec.
+func (in *IDSPolicySpec) DeepCopy() *IDSPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NSXServiceAccountStatus)
+	out := new(IDSPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NextHop) DeepCopyInto(out *NextHop) {
+func (in *IDSPolicyStatus) DeepCopyInto(out *IDSPolicyStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NextHop.
-func (in *NextHop) DeepCopy() *NextHop {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDSPolicyStatus.
+func (in *IDSPolicyStatus) DeepCopy() *IDSPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NextHop)
+	out := new(IDSPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicy) DeepCopyInto(out *SecurityPolicy) {
+func (in *IPBlock) DeepCopyInto(out *IPBlock) {
+	*out = *in
+	if in.Except != nil {
+		in, out := &in.Except, &out.Except
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPBlock.
+func (in *IPBlock) DeepCopy() *IPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(IPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPool) DeepCopyInto(out *IPPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -403,18 +569,18 @@ func (in *SecurityPolicy) DeepCopyInto(out *SecurityPolicy) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicy.
-func (in *SecurityPolicy) DeepCopy() *SecurityPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
+func (in *IPPool) DeepCopy() *IPPool {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicy)
+	out := new(IPPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecurityPolicy) DeepCopyObject() runtime.Object {
+func (in *IPPool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -422,13 +588,953 @@ func (in *SecurityPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicyList) DeepCopyInto(out *SecurityPolicyList) {
+func (in *IPPoolList) DeepCopyInto(out *IPPoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SecurityPolicy, len(*in))
+		*out = make([]IPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolList.
+func (in *IPPoolList) DeepCopy() *IPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPoolSpec) DeepCopyInto(out *IPPoolSpec) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetRequest, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolSpec.
+func (in *IPPoolSpec) DeepCopy() *IPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPoolStatus) DeepCopyInto(out *IPPoolStatus) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolStatus.
+func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXOperatorConfig) DeepCopyInto(out *NSXOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXOperatorConfig.
+func (in *NSXOperatorConfig) DeepCopy() *NSXOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NSXOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXOperatorConfigList) DeepCopyInto(out *NSXOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NSXOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXOperatorConfigList.
+func (in *NSXOperatorConfigList) DeepCopy() *NSXOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NSXOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXOperatorConfigSpec) DeepCopyInto(out *NSXOperatorConfigSpec) {
+	*out = *in
+	if in.ForbiddenRules != nil {
+		in, out := &in.ForbiddenRules, &out.ForbiddenRules
+		*out = make([]ForbiddenRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXOperatorConfigSpec.
+func (in *NSXOperatorConfigSpec) DeepCopy() *NSXOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXOperatorConfigStatus) DeepCopyInto(out *NSXOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXOperatorConfigStatus.
+func (in *NSXOperatorConfigStatus) DeepCopy() *NSXOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXProxyEndpoint) DeepCopyInto(out *NSXProxyEndpoint) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]NSXProxyEndpointAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]NSXProxyEndpointPort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpoint.
+func (in *NSXProxyEndpoint) DeepCopy() *NSXProxyEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXProxyEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXProxyEndpointAddress) DeepCopyInto(out *NSXProxyEndpointAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpointAddress.
+func (in *NSXProxyEndpointAddress) DeepCopy() *NSXProxyEndpointAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXProxyEndpointAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXProxyEndpointPort) DeepCopyInto(out *NSXProxyEndpointPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXProxyEndpointPort.
+func (in *NSXProxyEndpointPort) DeepCopy() *NSXProxyEndpointPort {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXProxyEndpointPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXSecret) DeepCopyInto(out *NSXSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXSecret.
+func (in *NSXSecret) DeepCopy() *NSXSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXServiceAccount) DeepCopyInto(out *NSXServiceAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccount.
+func (in *NSXServiceAccount) DeepCopy() *NSXServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NSXServiceAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXServiceAccountList) DeepCopyInto(out *NSXServiceAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NSXServiceAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountList.
+func (in *NSXServiceAccountList) DeepCopy() *NSXServiceAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXServiceAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NSXServiceAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXServiceAccountSpec) DeepCopyInto(out *NSXServiceAccountSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountSpec.
+func (in *NSXServiceAccountSpec) DeepCopy() *NSXServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSXServiceAccountStatus) DeepCopyInto(out *NSXServiceAccountStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NSXManagers != nil {
+		in, out := &in.NSXManagers, &out.NSXManagers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ProxyEndpoints.DeepCopyInto(&out.ProxyEndpoints)
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]NSXSecret, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSXServiceAccountStatus.
+func (in *NSXServiceAccountStatus) DeepCopy() *NSXServiceAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NSXServiceAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceEnforcement) DeepCopyInto(out *NamespaceEnforcement) {
+	*out = *in
+	if in.NSXObjects != nil {
+		in, out := &in.NSXObjects, &out.NSXObjects
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceEnforcement.
+func (in *NamespaceEnforcement) DeepCopy() *NamespaceEnforcement {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceEnforcement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceNetworkStatus) DeepCopyInto(out *NamespaceNetworkStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceNetworkStatus.
+func (in *NamespaceNetworkStatus) DeepCopy() *NamespaceNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceNetworkStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceNetworkStatusList) DeepCopyInto(out *NamespaceNetworkStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceNetworkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceNetworkStatusList.
+func (in *NamespaceNetworkStatusList) DeepCopy() *NamespaceNetworkStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceNetworkStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceNetworkStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceNetworkStatusStatus) DeepCopyInto(out *NamespaceNetworkStatusStatus) {
+	*out = *in
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceSyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NSXObjects != nil {
+		in, out := &in.NSXObjects, &out.NSXObjects
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceNetworkStatusStatus.
+func (in *NamespaceNetworkStatusStatus) DeepCopy() *NamespaceNetworkStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceNetworkStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NextHop) DeepCopyInto(out *NextHop) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NextHop.
+func (in *NextHop) DeepCopy() *NextHop {
+	if in == nil {
+		return nil
+	}
+	out := new(NextHop)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyProfile) DeepCopyInto(out *PolicyProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyProfile.
+func (in *PolicyProfile) DeepCopy() *PolicyProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyProfileList) DeepCopyInto(out *PolicyProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PolicyProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyProfileList.
+func (in *PolicyProfileList) DeepCopy() *PolicyProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyProfileSecurityPolicy) DeepCopyInto(out *PolicyProfileSecurityPolicy) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyProfileSecurityPolicy.
+func (in *PolicyProfileSecurityPolicy) DeepCopy() *PolicyProfileSecurityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyProfileSecurityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyProfileSpec) DeepCopyInto(out *PolicyProfileSpec) {
+	*out = *in
+	if in.SecurityPolicies != nil {
+		in, out := &in.SecurityPolicies, &out.SecurityPolicies
+		*out = make([]PolicyProfileSecurityPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyProfileSpec.
+func (in *PolicyProfileSpec) DeepCopy() *PolicyProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyProfileStatus) DeepCopyInto(out *PolicyProfileStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyProfileStatus.
+func (in *PolicyProfileStatus) DeepCopy() *PolicyProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedPolicy) DeepCopyInto(out *ProtectedPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPolicy.
+func (in *ProtectedPolicy) DeepCopy() *ProtectedPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedPolicyList) DeepCopyInto(out *ProtectedPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProtectedPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPolicyList.
+func (in *ProtectedPolicyList) DeepCopy() *ProtectedPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedPolicyReference) DeepCopyInto(out *ProtectedPolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPolicyReference.
+func (in *ProtectedPolicyReference) DeepCopy() *ProtectedPolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedPolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedPolicySpec) DeepCopyInto(out *ProtectedPolicySpec) {
+	*out = *in
+	if in.SecurityPolicies != nil {
+		in, out := &in.SecurityPolicies, &out.SecurityPolicies
+		*out = make([]ProtectedPolicyReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPolicySpec.
+func (in *ProtectedPolicySpec) DeepCopy() *ProtectedPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationSummary) DeepCopyInto(out *RealizationSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationSummary.
+func (in *RealizationSummary) DeepCopy() *RealizationSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncError) DeepCopyInto(out *ResourceSyncError) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncError.
+func (in *ResourceSyncError) DeepCopy() *ResourceSyncError {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncStatus) DeepCopyInto(out *ResourceSyncStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]ResourceSyncError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncStatus.
+func (in *ResourceSyncStatus) DeepCopy() *ResourceSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleBudget) DeepCopyInto(out *RuleBudget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleBudget.
+func (in *RuleBudget) DeepCopy() *RuleBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleStats) DeepCopyInto(out *RuleStats) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleStats.
+func (in *RuleStats) DeepCopy() *RuleStats {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleStatsSummary) DeepCopyInto(out *RuleStatsSummary) {
+	*out = *in
+	in.CollectedTime.DeepCopyInto(&out.CollectedTime)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RuleStats, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleStatsSummary.
+func (in *RuleStatsSummary) DeepCopy() *RuleStatsSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleStatsSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityExclusion) DeepCopyInto(out *SecurityExclusion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityExclusion.
+func (in *SecurityExclusion) DeepCopy() *SecurityExclusion {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityExclusion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityExclusion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityExclusionList) DeepCopyInto(out *SecurityExclusionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecurityExclusion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityExclusionList.
+func (in *SecurityExclusionList) DeepCopy() *SecurityExclusionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityExclusionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityExclusionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityExclusionSpec) DeepCopyInto(out *SecurityExclusionSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityExclusionSpec.
+func (in *SecurityExclusionSpec) DeepCopy() *SecurityExclusionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityExclusionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityExclusionStatus) DeepCopyInto(out *SecurityExclusionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityExclusionStatus.
+func (in *SecurityExclusionStatus) DeepCopy() *SecurityExclusionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityExclusionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicy) DeepCopyInto(out *SecurityPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicy.
+func (in *SecurityPolicy) DeepCopy() *SecurityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicyList) DeepCopyInto(out *SecurityPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecurityPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -471,162 +1577,399 @@ func (in *SecurityPolicyPeer) DeepCopyInto(out *SecurityPolicyPeer) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.IPBlocks != nil {
-		in, out := &in.IPBlocks, &out.IPBlocks
-		*out = make([]IPBlock, len(*in))
-		copy(*out, *in)
-	}
+	if in.IPBlocks != nil {
+		in, out := &in.IPBlocks, &out.IPBlocks
+		*out = make([]IPBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]ClusterNetwork, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdentityGroups != nil {
+		in, out := &in.IdentityGroups, &out.IdentityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyPeer.
+func (in *SecurityPolicyPeer) DeepCopy() *SecurityPolicyPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicyPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicyPort) DeepCopyInto(out *SecurityPolicyPort) {
+	*out = *in
+	out.Port = in.Port
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyPort.
+func (in *SecurityPolicyPort) DeepCopy() *SecurityPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicyRule) DeepCopyInto(out *SecurityPolicyRule) {
+	*out = *in
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(RuleAction)
+		**out = **in
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]SecurityPolicyTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Direction != nil {
+		in, out := &in.Direction, &out.Direction
+		*out = new(RuleDirection)
+		**out = **in
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SecurityPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]SecurityPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]SecurityPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppIDs != nil {
+		in, out := &in.AppIDs, &out.AppIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Logged != nil {
+		in, out := &in.Logged, &out.Logged
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyRule.
+func (in *SecurityPolicyRule) DeepCopy() *SecurityPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicySpec) DeepCopyInto(out *SecurityPolicySpec) {
+	*out = *in
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]SecurityPolicyTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicySpec.
+func (in *SecurityPolicySpec) DeepCopy() *SecurityPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicyStatus) DeepCopyInto(out *SecurityPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RuleBudgets != nil {
+		in, out := &in.RuleBudgets, &out.RuleBudgets
+		*out = make([]RuleBudget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Simulation != nil {
+		in, out := &in.Simulation, &out.Simulation
+		*out = new(SimulationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncSummary != nil {
+		in, out := &in.SyncSummary, &out.SyncSummary
+		*out = new(SyncSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LintWarnings != nil {
+		in, out := &in.LintWarnings, &out.LintWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleStats != nil {
+		in, out := &in.RuleStats, &out.RuleStats
+		*out = new(RuleStatsSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupMembers != nil {
+		in, out := &in.GroupMembers, &out.GroupMembers
+		*out = new(GroupMembersSummary)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyStatus.
+func (in *SecurityPolicyStatus) DeepCopy() *SecurityPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicyTarget) DeepCopyInto(out *SecurityPolicyTarget) {
+	*out = *in
+	if in.VMSelector != nil {
+		in, out := &in.VMSelector, &out.VMSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyTarget.
+func (in *SecurityPolicyTarget) DeepCopy() *SecurityPolicyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExposure) DeepCopyInto(out *ServiceExposure) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExposure.
+func (in *ServiceExposure) DeepCopy() *ServiceExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceExposure) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExposureConsumer) DeepCopyInto(out *ServiceExposureConsumer) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyPeer.
-func (in *SecurityPolicyPeer) DeepCopy() *SecurityPolicyPeer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExposureConsumer.
+func (in *ServiceExposureConsumer) DeepCopy() *ServiceExposureConsumer {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicyPeer)
+	out := new(ServiceExposureConsumer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicyPort) DeepCopyInto(out *SecurityPolicyPort) {
+func (in *ServiceExposureList) DeepCopyInto(out *ServiceExposureList) {
 	*out = *in
-	out.Port = in.Port
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceExposure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyPort.
-func (in *SecurityPolicyPort) DeepCopy() *SecurityPolicyPort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExposureList.
+func (in *ServiceExposureList) DeepCopy() *ServiceExposureList {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicyPort)
+	out := new(ServiceExposureList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceExposureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicyRule) DeepCopyInto(out *SecurityPolicyRule) {
+func (in *ServiceExposureSpec) DeepCopyInto(out *ServiceExposureSpec) {
 	*out = *in
-	if in.Action != nil {
-		in, out := &in.Action, &out.Action
-		*out = new(RuleAction)
-		**out = **in
-	}
-	if in.AppliedTo != nil {
-		in, out := &in.AppliedTo, &out.AppliedTo
-		*out = make([]SecurityPolicyTarget, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Direction != nil {
-		in, out := &in.Direction, &out.Direction
-		*out = new(RuleDirection)
-		**out = **in
-	}
-	if in.Sources != nil {
-		in, out := &in.Sources, &out.Sources
-		*out = make([]SecurityPolicyPeer, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Destinations != nil {
-		in, out := &in.Destinations, &out.Destinations
-		*out = make([]SecurityPolicyPeer, len(*in))
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]ServiceExposureConsumer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]SecurityPolicyPort, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyRule.
-func (in *SecurityPolicyRule) DeepCopy() *SecurityPolicyRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExposureSpec.
+func (in *ServiceExposureSpec) DeepCopy() *ServiceExposureSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicyRule)
+	out := new(ServiceExposureSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicySpec) DeepCopyInto(out *SecurityPolicySpec) {
+func (in *ServiceExposureStatus) DeepCopyInto(out *ServiceExposureStatus) {
 	*out = *in
-	if in.AppliedTo != nil {
-		in, out := &in.AppliedTo, &out.AppliedTo
-		*out = make([]SecurityPolicyTarget, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]SecurityPolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ConsumerNamespaces != nil {
+		in, out := &in.ConsumerNamespaces, &out.ConsumerNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicySpec.
-func (in *SecurityPolicySpec) DeepCopy() *SecurityPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExposureStatus.
+func (in *ServiceExposureStatus) DeepCopy() *ServiceExposureStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicySpec)
+	out := new(ServiceExposureStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicyStatus) DeepCopyInto(out *SecurityPolicyStatus) {
+func (in *SimulatedFlow) DeepCopyInto(out *SimulatedFlow) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyStatus.
-func (in *SecurityPolicyStatus) DeepCopy() *SecurityPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatedFlow.
+func (in *SimulatedFlow) DeepCopy() *SimulatedFlow {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicyStatus)
+	out := new(SimulatedFlow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecurityPolicyTarget) DeepCopyInto(out *SecurityPolicyTarget) {
+func (in *SimulationStatus) DeepCopyInto(out *SimulationStatus) {
 	*out = *in
-	if in.VMSelector != nil {
-		in, out := &in.VMSelector, &out.VMSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodSelector != nil {
-		in, out := &in.PodSelector, &out.PodSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.BlockedFlows != nil {
+		in, out := &in.BlockedFlows, &out.BlockedFlows
+		*out = make([]SimulatedFlow, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicyTarget.
-func (in *SecurityPolicyTarget) DeepCopy() *SecurityPolicyTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulationStatus.
+func (in *SimulationStatus) DeepCopy() *SimulationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecurityPolicyTarget)
+	out := new(SimulationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -842,6 +2185,156 @@ func (in *SubnetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPolicy) DeepCopyInto(out *SubnetPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPolicy.
+func (in *SubnetPolicy) DeepCopy() *SubnetPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubnetPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPolicyList) DeepCopyInto(out *SubnetPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubnetPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPolicyList.
+func (in *SubnetPolicyList) DeepCopy() *SubnetPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubnetPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPolicyRule) DeepCopyInto(out *SubnetPolicyRule) {
+	*out = *in
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(RuleAction)
+		**out = **in
+	}
+	if in.Direction != nil {
+		in, out := &in.Direction, &out.Direction
+		*out = new(RuleDirection)
+		**out = **in
+	}
+	if in.IPBlocks != nil {
+		in, out := &in.IPBlocks, &out.IPBlocks
+		*out = make([]IPBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]SecurityPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPolicyRule.
+func (in *SubnetPolicyRule) DeepCopy() *SubnetPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPolicySpec) DeepCopyInto(out *SubnetPolicySpec) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubnetSets != nil {
+		in, out := &in.SubnetSets, &out.SubnetSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SubnetPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPolicySpec.
+func (in *SubnetPolicySpec) DeepCopy() *SubnetPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPolicyStatus) DeepCopyInto(out *SubnetPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPolicyStatus.
+func (in *SubnetPolicyStatus) DeepCopy() *SubnetPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetPort) DeepCopyInto(out *SubnetPort) {
 	*out = *in
@@ -1142,6 +2635,25 @@ func (in *SubnetStatus) DeepCopy() *SubnetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSummary) DeepCopyInto(out *SyncSummary) {
+	*out = *in
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSummary.
+func (in *SyncSummary) DeepCopy() *SyncSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPC) DeepCopyInto(out *VPC) {
 	*out = *in
@@ -1348,3 +2860,18 @@ func (in *VPCStatus) DeepCopy() *VPCStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -9,6 +9,34 @@ type ConditionType string
 
 const (
 	Ready ConditionType = "Ready"
+	// Realized reports whether NSX has realized the resources created for the CR, its reason is one of
+	// Realized, RealizationError and InProgress.
+	Realized ConditionType = "Realized"
+	// InSync reports whether the NSX resources created for the CR are unchanged in NSX, its reason is one of
+	// InSync and Drifted.
+	InSync ConditionType = "InSync"
+	// PriorityConflict reports whether other CRs declare the same priority as the CR, its reason is one of
+	// PriorityShared and PriorityUnique.
+	PriorityConflict ConditionType = "PriorityConflict"
+)
+
+// The reasons of the Realized condition.
+const (
+	ReasonRealized         = "Realized"
+	ReasonRealizationError = "RealizationError"
+	ReasonInProgress       = "InProgress"
+)
+
+// The reasons of the InSync condition.
+const (
+	ReasonInSync  = "InSync"
+	ReasonDrifted = "Drifted"
+)
+
+// The reasons of the PriorityConflict condition.
+const (
+	ReasonPriorityShared = "PriorityShared"
+	ReasonPriorityUnique = "PriorityUnique"
 )
 
 // Condition defines condition of custom resource.
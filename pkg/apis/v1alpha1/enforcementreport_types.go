@@ -0,0 +1,86 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnforcementReportName is the name of the EnforcementReport generated by nsx-operator.
+const EnforcementReportName = "cluster"
+
+// NamespaceEnforcement summarizes the policies enforced in a Namespace.
+type NamespaceEnforcement struct {
+	// Namespace is the name of the Namespace.
+	Namespace string `json:"namespace"`
+	// SecurityPolicies is the count of the SecurityPolicies in the Namespace.
+	SecurityPolicies int `json:"securityPolicies"`
+	// NotReadySecurityPolicies is the count of the SecurityPolicies in the Namespace which are not realized.
+	// +optional
+	NotReadySecurityPolicies int `json:"notReadySecurityPolicies,omitempty"`
+	// NetworkPolicies is the count of the NetworkPolicies in the Namespace.
+	NetworkPolicies int `json:"networkPolicies"`
+	// NSXObjects is the count of the NSX objects created for the Namespace, keyed by the object type.
+	// +optional
+	NSXObjects map[string]int `json:"nsxObjects,omitempty"`
+}
+
+// RealizationSummary summarizes the realization of the SecurityPolicies in the cluster.
+type RealizationSummary struct {
+	// Ready is the count of the SecurityPolicies realized on NSX.
+	Ready int `json:"ready"`
+	// NotReady is the count of the SecurityPolicies failed to be realized, or not realized yet.
+	NotReady int `json:"notReady"`
+}
+
+// EnforcementReportStatus is the report of the policy enforcement in the cluster.
+type EnforcementReportStatus struct {
+	// GeneratedAt is the time the report was generated.
+	// +optional
+	GeneratedAt metav1.Time `json:"generatedAt,omitempty"`
+	// Namespaces summarizes the policies enforced in each Namespace.
+	// +optional
+	Namespaces []NamespaceEnforcement `json:"namespaces,omitempty"`
+	// UnprotectedNamespaces are the Namespaces with neither SecurityPolicy nor NetworkPolicy.
+	// +optional
+	UnprotectedNamespaces []string `json:"unprotectedNamespaces,omitempty"`
+	// Realization summarizes the realization of the SecurityPolicies in the cluster.
+	Realization RealizationSummary `json:"realization"`
+	// NSXObjects is the count of the NSX objects created by nsx-operator, keyed by the object type, to be
+	// tracked against the NSX config maximums.
+	// +optional
+	NSXObjects map[string]int `json:"nsxObjects,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// EnforcementReport is the Schema for the enforcementreports API, it's generated periodically by nsx-operator
+// to summarize the policy enforcement in the cluster for the compliance dashboards.
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.generatedAt`,description="The time the report was generated"
+// +kubebuilder:printcolumn:name="NotReady",type=integer,JSONPath=`.status.realization.notReady`,description="The count of the SecurityPolicies not realized"
+type EnforcementReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status EnforcementReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EnforcementReportList contains a list of EnforcementReport.
+type EnforcementReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EnforcementReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnforcementReport{}, &EnforcementReportList{})
+}
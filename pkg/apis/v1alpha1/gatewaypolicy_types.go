@@ -0,0 +1,59 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayPolicySpec defines the desired state of GatewayPolicy.
+type GatewayPolicySpec struct {
+	// Gateways is a list of the policy paths of the NSX Tier-0 or Tier-1 gateways the rules are enforced on,
+	// e.g. /infra/tier-1s/t1-cluster.
+	// +kubebuilder:validation:MinItems=1
+	Gateways []string `json:"gateways"`
+	// Priority defines the order of policy enforcement.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	Priority int `json:"priority,omitempty"`
+	// Rules is a list of the north-south rules enforced on the gateways. The rules are defined like the
+	// SecurityPolicy rules, except that appliedTo, redirectTo, appIds, the named ports, and the workloads and
+	// the FQDN of the peers are not supported.
+	Rules []SecurityPolicyRule `json:"rules,omitempty"`
+}
+
+// GatewayPolicyStatus defines the observed state of GatewayPolicy.
+type GatewayPolicyStatus struct {
+	// Conditions describes current state of GatewayPolicy.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// GatewayPolicy is the Schema for the gatewaypolicies API, it's realized as an NSX gateway firewall policy
+// enforcing the north-south traffic on the Tier-0 or Tier-1 gateways.
+type GatewayPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayPolicySpec   `json:"spec"`
+	Status GatewayPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GatewayPolicyList contains a list of GatewayPolicy.
+type GatewayPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayPolicy{}, &GatewayPolicyList{})
+}
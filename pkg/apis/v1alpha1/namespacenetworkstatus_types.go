@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceNetworkStatusName is the name of the NamespaceNetworkStatus generated by nsx-operator in each Namespace.
+const NamespaceNetworkStatusName = "nsx-operator"
+
+// ResourceSyncError is a resource failed to be realized on NSX.
+type ResourceSyncError struct {
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Reason is the reason of the Ready condition of the resource.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is the message of the Ready condition of the resource.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the time the Ready condition of the resource last changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ResourceSyncStatus summarizes the realization of the resources of a kind in the Namespace.
+type ResourceSyncStatus struct {
+	// Kind is the kind of the resources, e.g. SecurityPolicy.
+	Kind string `json:"kind"`
+	// Total is the count of the resources.
+	Total int `json:"total"`
+	// NotReady is the count of the resources failed to be realized, or not realized yet.
+	// +optional
+	NotReady int `json:"notReady,omitempty"`
+	// LastSyncTime is the latest time the Ready condition of a resource changed.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Errors are the resources failed to be realized, the first ones by name.
+	// +optional
+	Errors []ResourceSyncError `json:"errors,omitempty"`
+}
+
+// NamespaceNetworkStatusStatus is the realization of the resources of the Namespace on NSX.
+type NamespaceNetworkStatusStatus struct {
+	// GeneratedAt is the time the status was generated.
+	// +optional
+	GeneratedAt metav1.Time `json:"generatedAt,omitempty"`
+	// Resources summarizes the realization of the resources of each kind.
+	// +optional
+	Resources []ResourceSyncStatus `json:"resources,omitempty"`
+	// NotReady is the count of the resources of all the kinds which are not realized.
+	// +optional
+	NotReady int `json:"notReady,omitempty"`
+	// NSXObjects is the count of the NSX objects created for the Namespace, keyed by the object type.
+	// +optional
+	NSXObjects map[string]int `json:"nsxObjects,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// NamespaceNetworkStatus is the Schema for the namespacenetworkstatuses API, it's generated periodically by
+// nsx-operator in each Namespace with resources realized on NSX, so that the Namespace owners can see the
+// realization of their resources without access to the logs of nsx-operator.
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.generatedAt`,description="The time the status was generated"
+// +kubebuilder:printcolumn:name="NotReady",type=integer,JSONPath=`.status.notReady`,description="The count of the resources not realized"
+type NamespaceNetworkStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NamespaceNetworkStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NamespaceNetworkStatusList contains a list of NamespaceNetworkStatus.
+type NamespaceNetworkStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceNetworkStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceNetworkStatus{}, &NamespaceNetworkStatusList{})
+}
@@ -0,0 +1,62 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyProfileSecurityPolicy is a SecurityPolicy of the policy bundle.
+type PolicyProfileSecurityPolicy struct {
+	// Name is the name of the SecurityPolicy, it's prefixed with the name of the PolicyProfile in the Namespaces.
+	Name string `json:"name"`
+	// Spec is the spec of the SecurityPolicy materialized in the Namespaces.
+	Spec SecurityPolicySpec `json:"spec"`
+}
+
+// PolicyProfileSpec defines the policy bundle of the profile.
+type PolicyProfileSpec struct {
+	// SecurityPolicies are materialized in each Namespace labeled with nsx.vmware.com/policy-profile set to the
+	// name of the PolicyProfile, and kept in sync with the PolicyProfile.
+	SecurityPolicies []PolicyProfileSecurityPolicy `json:"securityPolicies,omitempty"`
+}
+
+// PolicyProfileStatus defines the observed state of PolicyProfile.
+type PolicyProfileStatus struct {
+	// Namespaces is the list of the Namespaces the policy bundle is materialized in.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Conditions describes current state of PolicyProfile.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// PolicyProfile is the Schema for the policyprofiles API, it's created by the cluster admins to define a bundle
+// of SecurityPolicies the Namespaces opt into by a label.
+// +kubebuilder:resource:scope="Cluster"
+type PolicyProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicyProfileSpec   `json:"spec"`
+	Status PolicyProfileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PolicyProfileList contains a list of PolicyProfile.
+type PolicyProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyProfile{}, &PolicyProfileList{})
+}
@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecurityExclusionSpec defines the Pods excluded from the DFW enforcement.
+type SecurityExclusionSpec struct {
+	// NamespaceSelector selects the Namespaces whose Pods are excluded, the Pods of all the Namespaces are
+	// selected if it's not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// PodSelector selects the Pods excluded in the selected Namespaces, all the Pods of the Namespaces are
+	// excluded if it's not set. At least one of NamespaceSelector and PodSelector must be set.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// SecurityExclusionStatus defines the observed state of SecurityExclusion.
+type SecurityExclusionStatus struct {
+	// Conditions describes current state of SecurityExclusion.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// SecurityExclusion is the Schema for the securityexclusions API, it's created by the cluster admins to exclude
+// the infrastructure workloads, e.g. the CNI agents or the monitoring DaemonSets, from the DFW enforcement.
+// +kubebuilder:resource:scope="Cluster"
+type SecurityExclusion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecurityExclusionSpec   `json:"spec"`
+	Status SecurityExclusionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SecurityExclusionList contains a list of SecurityExclusion.
+type SecurityExclusionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityExclusion `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecurityExclusion{}, &SecurityExclusionList{})
+}
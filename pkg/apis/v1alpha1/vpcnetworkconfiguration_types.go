@@ -46,6 +46,11 @@ type VPCNetworkConfigurationSpec struct {
 	// +kubebuilder:validation:MaxLength=8
 	// +optional
 	ShortID string `json:"shortID,omitempty"`
+	// NSXDomain of the NSX-T Project the groups shared by the SecurityPolicies of the Namespace are created in.
+	// Defaults to the domain configured for nsx-operator.
+	// +kubebuilder:validation:Pattern=`^[^/\s]*$`
+	// +optional
+	NSXDomain string `json:"nsxDomain,omitempty"`
 }
 
 // VPCNetworkConfigurationStatus defines the observed state of VPCNetworkConfiguration
@@ -15,9 +15,11 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ippool"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securityexclusion"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
 	sr "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetpolicy"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
@@ -26,17 +28,19 @@ import (
 var log = logger.Log
 
 // Clean cleans up NSX resources,
-// including security policy, static route, subnet, subnet port, subnet set, vpc, ip pool, nsx service account
+// including security policy, security exclusion, static route, subnet policy, subnet, subnet port, subnet set, vpc, ip pool, nsx service account
 // besides, it also cleans up DLB resources, which was previously implemented in nsx-ncp,
 // it is usually used when nsx-operator is uninstalled and remove all the resources created by nsx-operator
+// at last, it deletes the remaining NSX resources tagged with the cluster in batches by the hierarchical API,
+// e.g. when the cluster is decommissioned, in dry-run mode the resources are only listed
 // return error if any, return nil if no error
 // the error type include followings:
 // ValidationFailed 			indicate that the config is incorrect and failed to pass validation
 // GetNSXClientFailed  			indicate that could not retrieve nsx client to perform cleanup operation
 // InitCleanupServiceFailed 	indicate that error happened when trying to initialize cleanup service
 // CleanupResourceFailed    	indicate that the cleanup operation failed at some services, the detailed will in the service logs
-func Clean(ctx context.Context, cf *config.NSXOperatorConfig) error {
-	log.Info("starting NSX cleanup")
+func Clean(ctx context.Context, cf *config.NSXOperatorConfig, opts Options) error {
+	log.Info("starting NSX cleanup", "dryRun", opts.DryRun)
 	if err := cf.ValidateConfigFromCmd(); err != nil {
 		return errors.Join(nsxutil.ValidationFailed, err)
 	}
@@ -44,6 +48,16 @@ func Clean(ctx context.Context, cf *config.NSXOperatorConfig) error {
 	if nsxClient == nil {
 		return nsxutil.GetNSXClientFailed
 	}
+	if opts.DryRun {
+		if err := CleanClusterResources(ctx, nsxClient.Cluster, cf, opts); err != nil {
+			return errors.Join(nsxutil.CleanupResourceFailed, err)
+		}
+		if err := CleanDLB(ctx, nsxClient.Cluster, cf, true); err != nil {
+			return errors.Join(nsxutil.CleanupResourceFailed, err)
+		}
+		log.Info("dry-run of NSX cleanup finished, no resource is deleted")
+		return nil
+	}
 	if cleanupService, err := InitializeCleanupService(cf, nsxClient); err != nil {
 		return errors.Join(nsxutil.InitCleanupServiceFailed, err)
 	} else if cleanupService.err != nil {
@@ -63,7 +77,7 @@ func Clean(ctx context.Context, cf *config.NSXOperatorConfig) error {
 		}
 		return false
 	}, func() error {
-		if err := CleanDLB(ctx, nsxClient.Cluster, cf); err != nil {
+		if err := CleanDLB(ctx, nsxClient.Cluster, cf, false); err != nil {
 			return fmt.Errorf("failed to clean up specific resource: %w", err)
 		}
 		return nil
@@ -71,6 +85,12 @@ func Clean(ctx context.Context, cf *config.NSXOperatorConfig) error {
 		return err
 	}
 
+	if err := retry.OnError(retry.DefaultRetry, retriable, func() error {
+		return CleanClusterResources(ctx, nsxClient.Cluster, cf, opts)
+	}); err != nil {
+		return errors.Join(nsxutil.CleanupResourceFailed, err)
+	}
+
 	log.Info("cleanup NSX resources successfully")
 	return nil
 }
@@ -115,6 +135,11 @@ func InitializeCleanupService(cf *config.NSXOperatorConfig, nsxClient *nsx.Clien
 			return securitypolicy.InitializeSecurityPolicy(service, vpcService)
 		}
 	}
+	wrapInitializeSecurityExclusion := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return securityexclusion.InitializeSecurityExclusion(service)
+		}
+	}
 	wrapInitializeIPPool := func(service common.Service) cleanupFunc {
 		return func() (cleanup, error) {
 			return ippool.InitializeIPPool(service, vpcService)
@@ -133,6 +158,12 @@ func InitializeCleanupService(cf *config.NSXOperatorConfig, nsxClient *nsx.Clien
 		}
 	}
 
+	wrapInitializeSubnetPolicy := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return subnetpolicy.InitializeSubnetPolicy(service, vpcService)
+		}
+	}
+
 	wrapInitializeSubnetPort := func(service common.Service) cleanupFunc {
 		return func() (cleanup, error) {
 			return subnetport.InitializeSubnetPort(service)
@@ -141,8 +172,10 @@ func InitializeCleanupService(cf *config.NSXOperatorConfig, nsxClient *nsx.Clien
 	// TODO: initialize other CR services
 	cleanupService = cleanupService.
 		AddCleanupService(wrapInitializeSubnetPort(commonService)).
+		AddCleanupService(wrapInitializeSubnetPolicy(commonService)).
 		AddCleanupService(wrapInitializeSubnetService(commonService)).
 		AddCleanupService(wrapInitializeSecurityPolicy(commonService)).
+		AddCleanupService(wrapInitializeSecurityExclusion(commonService)).
 		AddCleanupService(wrapInitializeIPPool(commonService)).
 		AddCleanupService(wrapInitializeStaticRoute(commonService)).
 		AddCleanupService(wrapInitializeVPC(commonService))
@@ -0,0 +1,272 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package clean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"sort"
+	"strings"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+const DefaultBatchSize = 100
+
+// Options controls how the NSX resources of the cluster are cleaned up.
+type Options struct {
+	// DryRun lists the NSX resources which would be deleted without deleting them.
+	DryRun bool
+	// BatchSize is the max number of the NSX resources deleted by one hierarchical API call.
+	BatchSize int
+}
+
+// clusterResource is an NSX resource tagged with the cluster.
+type clusterResource struct {
+	ResourceType string
+	Path         string
+}
+
+// collectionTypes maps the collections in the NSX policy paths to the resource types of the objects in them,
+// it's used to build the ChildResourceReference of the parents in the hierarchical API body.
+var collectionTypes = map[string]string{
+	"orgs":              "Org",
+	"projects":          "Project",
+	"vpcs":              "Vpc",
+	"subnets":           "VpcSubnet",
+	"domains":           "Domain",
+	"security-policies": "SecurityPolicy",
+	"gateway-policies":  "GatewayPolicy",
+	"ip-pools":          "IpAddressPool",
+	"tier-1s":           "Tier1",
+}
+
+// deleteRanks orders the deletion, the rules are deleted before the Groups they refer to, and the VPCs are
+// deleted after the resources in them. The resource types not listed have the rank 1.
+var deleteRanks = map[string]int{
+	"SecurityPolicy": 0,
+	"GatewayPolicy":  0,
+	"Rule":           0,
+	"VpcSubnet":      2,
+	"IpAddressPool":  2,
+	"Group":          3,
+	"Share":          3,
+	"SharedResource": 3,
+	"Vpc":            4,
+}
+
+func deleteRank(resourceType string) int {
+	if rank, ok := deleteRanks[resourceType]; ok {
+		return rank
+	}
+	return 1
+}
+
+var luceneEscaper = strings.NewReplacer("/", "\\/", ":", "\\:")
+
+// queryClusterResources returns all the NSX resources tagged with the cluster by the operator.
+func queryClusterResources(cluster *nsx.Cluster, clusterName string) ([]clusterResource, error) {
+	query := fmt.Sprintf("tags.scope:%s AND tags.tag:%s", luceneEscaper.Replace(common.TagScopeCluster), luceneEscaper.Replace(clusterName))
+	var resources []clusterResource
+	cursor := ""
+	for {
+		url := "policy/api/v1/search/query?query=" + neturl.QueryEscape(query)
+		if cursor != "" {
+			url += "&cursor=" + neturl.QueryEscape(cursor)
+		}
+		resp, err := cluster.HttpGet(url)
+		if err != nil {
+			return nil, err
+		}
+		results, _ := resp["results"].([]interface{})
+		for _, item := range results {
+			result, ok := item.(mapInterface)
+			if !ok {
+				continue
+			}
+			path, _ := result["path"].(string)
+			resourceType, _ := result["resource_type"].(string)
+			if path == "" || resourceType == "" {
+				continue
+			}
+			resources = append(resources, clusterResource{ResourceType: resourceType, Path: path})
+		}
+		cursor, _ = resp["cursor"].(string)
+		if cursor == "" || len(results) == 0 {
+			return resources, nil
+		}
+	}
+}
+
+// pruneDescendants drops the resources whose ancestor is also deleted, they're deleted with the ancestor by
+// the hierarchical API. The result is ordered for the deletion.
+func pruneDescendants(resources []clusterResource) []clusterResource {
+	paths := make(map[string]struct{}, len(resources))
+	for _, r := range resources {
+		paths[r.Path] = struct{}{}
+	}
+	var pruned []clusterResource
+	for _, r := range resources {
+		covered := false
+		for parent := r.Path; !covered; {
+			i := strings.LastIndex(parent, "/")
+			if i <= 0 {
+				break
+			}
+			parent = parent[:i]
+			_, covered = paths[parent]
+		}
+		if !covered {
+			pruned = append(pruned, r)
+		}
+	}
+	sort.SliceStable(pruned, func(i, j int) bool {
+		return deleteRank(pruned[i].ResourceType) < deleteRank(pruned[j].ResourceType)
+	})
+	return pruned
+}
+
+// hierarchicalRoot splits the path of the resource into the URL of the hierarchical API the resource is
+// deleted by, and the collection/ID segments from the root to the resource.
+// The paths in the infra of the projects are patched to the project infra, others under /orgs to the org root.
+func hierarchicalRoot(path string) (string, []string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if segment == "infra" {
+			return "policy/api/v1/" + strings.Join(segments[:i+1], "/"), segments[i+1:], nil
+		}
+	}
+	if segments[0] == "orgs" {
+		return "policy/api/v1/org-root", segments, nil
+	}
+	return "", nil, fmt.Errorf("unsupported path %s", path)
+}
+
+// addToHierarchy adds the resource marked for deletion to the children of the root, creating the
+// ChildResourceReference of its parents.
+func addToHierarchy(root mapInterface, resource clusterResource, segments []string) error {
+	if len(segments) < 2 || len(segments)%2 != 0 {
+		return fmt.Errorf("unsupported path %s", resource.Path)
+	}
+	node := root
+	for i := 0; i+2 < len(segments); i += 2 {
+		targetType, ok := collectionTypes[segments[i]]
+		if !ok {
+			return fmt.Errorf("unsupported path %s", resource.Path)
+		}
+		id := segments[i+1]
+		children, _ := node["children"].([]interface{})
+		var child mapInterface
+		for _, c := range children {
+			if ref := c.(mapInterface); ref["resource_type"] == "ChildResourceReference" && ref["id"] == id && ref["target_type"] == targetType {
+				child = ref
+				break
+			}
+		}
+		if child == nil {
+			child = mapInterface{"resource_type": "ChildResourceReference", "id": id, "target_type": targetType}
+			node["children"] = append(children, child)
+		}
+		node = child
+	}
+	children, _ := node["children"].([]interface{})
+	node["children"] = append(children, mapInterface{
+		"resource_type":     "Child" + resource.ResourceType,
+		"marked_for_delete": true,
+		resource.ResourceType: mapInterface{
+			"id":            segments[len(segments)-1],
+			"resource_type": resource.ResourceType,
+		},
+	})
+	return nil
+}
+
+// buildHierarchicalDeletes builds the bodies of the hierarchical API calls deleting the resources, keyed by
+// the URL of the calls. The resources whose path isn't supported are returned to be deleted one by one.
+func buildHierarchicalDeletes(resources []clusterResource) (map[string]mapInterface, []clusterResource) {
+	bodies := make(map[string]mapInterface)
+	var unsupported []clusterResource
+	for _, r := range resources {
+		url, segments, err := hierarchicalRoot(r.Path)
+		if err != nil {
+			unsupported = append(unsupported, r)
+			continue
+		}
+		body, ok := bodies[url]
+		if !ok {
+			resourceType := "Infra"
+			if strings.HasSuffix(url, "org-root") {
+				resourceType = "OrgRoot"
+			}
+			body = mapInterface{"resource_type": resourceType}
+		}
+		if err := addToHierarchy(body, r, segments); err != nil {
+			unsupported = append(unsupported, r)
+			continue
+		}
+		bodies[url] = body
+	}
+	return bodies, unsupported
+}
+
+// CleanClusterResources deletes all the NSX resources tagged with the cluster which are left after the
+// services are cleaned up, e.g. the resources of a decommissioned cluster the stores of the services don't
+// know about. The resources are deleted by the hierarchical API in batches, or only listed in dry-run mode.
+func CleanClusterResources(ctx context.Context, cluster *nsx.Cluster, cf *config.NSXOperatorConfig, opts Options) error {
+	resources, err := queryClusterResources(cluster, cf.Cluster)
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	for _, r := range resources {
+		counts[r.ResourceType]++
+	}
+	log.Info("found NSX resources tagged with the cluster", "cluster", cf.Cluster, "count", len(resources), "resourceTypes", counts)
+	if opts.DryRun {
+		for _, r := range resources {
+			log.Info("dry-run: would delete NSX resource", "resourceType", r.ResourceType, "path", r.Path)
+		}
+		return nil
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	resources = pruneDescendants(resources)
+	for start := 0; start < len(resources); start += batchSize {
+		end := start + batchSize
+		if end > len(resources) {
+			end = len(resources)
+		}
+		bodies, unsupported := buildHierarchicalDeletes(resources[start:end])
+		for url, body := range bodies {
+			select {
+			case <-ctx.Done():
+				return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+			default:
+				if err := cluster.HttpPatch(url, body); err != nil {
+					return fmt.Errorf("failed to delete NSX resources by %s: %w", url, err)
+				}
+			}
+		}
+		for _, r := range unsupported {
+			select {
+			case <-ctx.Done():
+				return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+			default:
+				if err := cluster.HttpDelete("policy/api/v1" + r.Path); err != nil {
+					return err
+				}
+			}
+		}
+		log.Info("deleted a batch of NSX resources", "count", end-start, "remaining", len(resources)-end)
+	}
+	return nil
+}
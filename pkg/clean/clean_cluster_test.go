@@ -0,0 +1,68 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package clean
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneDescendants(t *testing.T) {
+	resources := []clusterResource{
+		{ResourceType: "Vpc", Path: "/orgs/default/projects/p1/vpcs/vpc1"},
+		{ResourceType: "VpcSubnet", Path: "/orgs/default/projects/p1/vpcs/vpc1/subnets/s1"},
+		{ResourceType: "Group", Path: "/infra/domains/default/groups/g1"},
+		{ResourceType: "Rule", Path: "/infra/domains/default/security-policies/sp1/rules/r1"},
+		{ResourceType: "SecurityPolicy", Path: "/infra/domains/default/security-policies/sp1"},
+	}
+	assert.Equal(t, []clusterResource{
+		{ResourceType: "SecurityPolicy", Path: "/infra/domains/default/security-policies/sp1"},
+		{ResourceType: "Group", Path: "/infra/domains/default/groups/g1"},
+		{ResourceType: "Vpc", Path: "/orgs/default/projects/p1/vpcs/vpc1"},
+	}, pruneDescendants(resources))
+}
+
+func TestBuildHierarchicalDeletes(t *testing.T) {
+	resources := []clusterResource{
+		{ResourceType: "SecurityPolicy", Path: "/orgs/default/projects/p1/vpcs/vpc1/security-policies/sp1"},
+		{ResourceType: "VpcSubnet", Path: "/orgs/default/projects/p1/vpcs/vpc1/subnets/s1"},
+		{ResourceType: "Group", Path: "/orgs/default/projects/p1/infra/domains/default/groups/g1"},
+		{ResourceType: "Unknown", Path: "/orgs/default/projects/p1/unknown/u1/items/i1"},
+	}
+	bodies, unsupported := buildHierarchicalDeletes(resources)
+	assert.Equal(t, []clusterResource{resources[3]}, unsupported)
+	assert.Len(t, bodies, 2)
+
+	vpc := mapInterface{
+		"resource_type": "ChildResourceReference", "id": "vpc1", "target_type": "Vpc",
+		"children": []interface{}{
+			mapInterface{
+				"resource_type":     "ChildSecurityPolicy",
+				"marked_for_delete": true,
+				"SecurityPolicy":    mapInterface{"id": "sp1", "resource_type": "SecurityPolicy"},
+			},
+			mapInterface{
+				"resource_type":     "ChildVpcSubnet",
+				"marked_for_delete": true,
+				"VpcSubnet":         mapInterface{"id": "s1", "resource_type": "VpcSubnet"},
+			},
+		},
+	}
+	project := mapInterface{"resource_type": "ChildResourceReference", "id": "p1", "target_type": "Project", "children": []interface{}{vpc}}
+	org := mapInterface{"resource_type": "ChildResourceReference", "id": "default", "target_type": "Org", "children": []interface{}{project}}
+	assert.Equal(t, mapInterface{"resource_type": "OrgRoot", "children": []interface{}{org}}, bodies["policy/api/v1/org-root"])
+
+	domain := mapInterface{
+		"resource_type": "ChildResourceReference", "id": "default", "target_type": "Domain",
+		"children": []interface{}{
+			mapInterface{
+				"resource_type":     "ChildGroup",
+				"marked_for_delete": true,
+				"Group":             mapInterface{"id": "g1", "resource_type": "Group"},
+			},
+		},
+	}
+	assert.Equal(t, mapInterface{"resource_type": "Infra", "children": []interface{}{domain}}, bodies["policy/api/v1/orgs/default/projects/p1/infra"])
+}
@@ -63,7 +63,7 @@ func httpQueryDLBResources(cluster *nsx.Cluster, cf *config.NSXOperatorConfig, r
 	return resourcePath, nil
 }
 
-func CleanDLB(ctx context.Context, cluster *nsx.Cluster, cf *config.NSXOperatorConfig) error {
+func CleanDLB(ctx context.Context, cluster *nsx.Cluster, cf *config.NSXOperatorConfig, dryRun bool) error {
 	log.Info("Deleting DLB resources started")
 
 	resources := []string{"Group", "LBVirtualServer", "LBService", "LBPool", "LBCookiePersistenceProfile"}
@@ -78,6 +78,10 @@ func CleanDLB(ctx context.Context, cluster *nsx.Cluster, cf *config.NSXOperatorC
 		allPaths = append(allPaths, paths...)
 	}
 
+	if dryRun {
+		log.Info("dry-run: would delete DLB resources", "paths", allPaths)
+		return nil
+	}
 	log.Info("Deleting DLB resources", "paths", allPaths)
 	for _, path := range allPaths {
 		url := "policy/api/v1" + path
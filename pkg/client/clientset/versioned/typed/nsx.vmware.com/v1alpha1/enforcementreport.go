@@ -0,0 +1,171 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// EnforcementReportsGetter has a method to return a EnforcementReportInterface.
+// A group's client should implement this interface.
+type EnforcementReportsGetter interface {
+	EnforcementReports() EnforcementReportInterface
+}
+
+// EnforcementReportInterface has methods to work with EnforcementReport resources.
+type EnforcementReportInterface interface {
+	Create(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.CreateOptions) (*v1alpha1.EnforcementReport, error)
+	Update(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (*v1alpha1.EnforcementReport, error)
+	UpdateStatus(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (*v1alpha1.EnforcementReport, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.EnforcementReport, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.EnforcementReportList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.EnforcementReport, err error)
+	EnforcementReportExpansion
+}
+
+// enforcementReports implements EnforcementReportInterface
+type enforcementReports struct {
+	client rest.Interface
+}
+
+// newEnforcementReports returns a EnforcementReports
+func newEnforcementReports(c *NsxV1alpha1Client) *enforcementReports {
+	return &enforcementReports{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the enforcementReport, and returns the corresponding enforcementReport object, and an error if there is any.
+func (c *enforcementReports) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.EnforcementReport, err error) {
+	result = &v1alpha1.EnforcementReport{}
+	err = c.client.Get().
+		Resource("enforcementreports").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of EnforcementReports that match those selectors.
+func (c *enforcementReports) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.EnforcementReportList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.EnforcementReportList{}
+	err = c.client.Get().
+		Resource("enforcementreports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested enforcementReports.
+func (c *enforcementReports) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("enforcementreports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a enforcementReport and creates it.  Returns the server's representation of the enforcementReport, and an error, if there is any.
+func (c *enforcementReports) Create(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.CreateOptions) (result *v1alpha1.EnforcementReport, err error) {
+	result = &v1alpha1.EnforcementReport{}
+	err = c.client.Post().
+		Resource("enforcementreports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(enforcementReport).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a enforcementReport and updates it. Returns the server's representation of the enforcementReport, and an error, if there is any.
+func (c *enforcementReports) Update(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (result *v1alpha1.EnforcementReport, err error) {
+	result = &v1alpha1.EnforcementReport{}
+	err = c.client.Put().
+		Resource("enforcementreports").
+		Name(enforcementReport.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(enforcementReport).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *enforcementReports) UpdateStatus(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (result *v1alpha1.EnforcementReport, err error) {
+	result = &v1alpha1.EnforcementReport{}
+	err = c.client.Put().
+		Resource("enforcementreports").
+		Name(enforcementReport.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(enforcementReport).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the enforcementReport and deletes it. Returns an error if one occurs.
+func (c *enforcementReports) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("enforcementreports").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *enforcementReports) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("enforcementreports").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched enforcementReport.
+func (c *enforcementReports) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.EnforcementReport, err error) {
+	result = &v1alpha1.EnforcementReport{}
+	err = c.client.Patch(pt).
+		Resource("enforcementreports").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
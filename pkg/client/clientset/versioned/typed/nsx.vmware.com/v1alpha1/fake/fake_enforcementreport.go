@@ -0,0 +1,119 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeEnforcementReports implements EnforcementReportInterface
+type FakeEnforcementReports struct {
+	Fake *FakeNsxV1alpha1
+}
+
+var enforcementreportsResource = v1alpha1.SchemeGroupVersion.WithResource("enforcementreports")
+
+var enforcementreportsKind = v1alpha1.SchemeGroupVersion.WithKind("EnforcementReport")
+
+// Get takes name of the enforcementReport, and returns the corresponding enforcementReport object, and an error if there is any.
+func (c *FakeEnforcementReports) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.EnforcementReport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(enforcementreportsResource, name), &v1alpha1.EnforcementReport{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EnforcementReport), err
+}
+
+// List takes label and field selectors, and returns the list of EnforcementReports that match those selectors.
+func (c *FakeEnforcementReports) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.EnforcementReportList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(enforcementreportsResource, enforcementreportsKind, opts), &v1alpha1.EnforcementReportList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.EnforcementReportList{ListMeta: obj.(*v1alpha1.EnforcementReportList).ListMeta}
+	for _, item := range obj.(*v1alpha1.EnforcementReportList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested enforcementReports.
+func (c *FakeEnforcementReports) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(enforcementreportsResource, opts))
+}
+
+// Create takes the representation of a enforcementReport and creates it.  Returns the server's representation of the enforcementReport, and an error, if there is any.
+func (c *FakeEnforcementReports) Create(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.CreateOptions) (result *v1alpha1.EnforcementReport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(enforcementreportsResource, enforcementReport), &v1alpha1.EnforcementReport{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EnforcementReport), err
+}
+
+// Update takes the representation of a enforcementReport and updates it. Returns the server's representation of the enforcementReport, and an error, if there is any.
+func (c *FakeEnforcementReports) Update(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (result *v1alpha1.EnforcementReport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(enforcementreportsResource, enforcementReport), &v1alpha1.EnforcementReport{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EnforcementReport), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeEnforcementReports) UpdateStatus(ctx context.Context, enforcementReport *v1alpha1.EnforcementReport, opts v1.UpdateOptions) (*v1alpha1.EnforcementReport, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(enforcementreportsResource, "status", enforcementReport), &v1alpha1.EnforcementReport{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EnforcementReport), err
+}
+
+// Delete takes name of the enforcementReport and deletes it. Returns an error if one occurs.
+func (c *FakeEnforcementReports) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(enforcementreportsResource, name, opts), &v1alpha1.EnforcementReport{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeEnforcementReports) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(enforcementreportsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.EnforcementReportList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched enforcementReport.
+func (c *FakeEnforcementReports) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.EnforcementReport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(enforcementreportsResource, name, pt, data, subresources...), &v1alpha1.EnforcementReport{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EnforcementReport), err
+}
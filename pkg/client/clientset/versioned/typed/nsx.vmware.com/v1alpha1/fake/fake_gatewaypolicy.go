@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeGatewayPolicies implements GatewayPolicyInterface
+type FakeGatewayPolicies struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var gatewaypoliciesResource = v1alpha1.SchemeGroupVersion.WithResource("gatewaypolicies")
+
+var gatewaypoliciesKind = v1alpha1.SchemeGroupVersion.WithKind("GatewayPolicy")
+
+// Get takes name of the gatewayPolicy, and returns the corresponding gatewayPolicy object, and an error if there is any.
+func (c *FakeGatewayPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.GatewayPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(gatewaypoliciesResource, c.ns, name), &v1alpha1.GatewayPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.GatewayPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of GatewayPolicies that match those selectors.
+func (c *FakeGatewayPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.GatewayPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(gatewaypoliciesResource, gatewaypoliciesKind, c.ns, opts), &v1alpha1.GatewayPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.GatewayPolicyList{ListMeta: obj.(*v1alpha1.GatewayPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.GatewayPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested gatewayPolicies.
+func (c *FakeGatewayPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(gatewaypoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a gatewayPolicy and creates it.  Returns the server's representation of the gatewayPolicy, and an error, if there is any.
+func (c *FakeGatewayPolicies) Create(ctx context.Context, gatewayPolicy *v1alpha1.GatewayPolicy, opts v1.CreateOptions) (result *v1alpha1.GatewayPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(gatewaypoliciesResource, c.ns, gatewayPolicy), &v1alpha1.GatewayPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.GatewayPolicy), err
+}
+
+// Update takes the representation of a gatewayPolicy and updates it. Returns the server's representation of the gatewayPolicy, and an error, if there is any.
+func (c *FakeGatewayPolicies) Update(ctx context.Context, gatewayPolicy *v1alpha1.GatewayPolicy, opts v1.UpdateOptions) (result *v1alpha1.GatewayPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(gatewaypoliciesResource, c.ns, gatewayPolicy), &v1alpha1.GatewayPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.GatewayPolicy), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeGatewayPolicies) UpdateStatus(ctx context.Context, gatewayPolicy *v1alpha1.GatewayPolicy, opts v1.UpdateOptions) (*v1alpha1.GatewayPolicy, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(gatewaypoliciesResource, "status", c.ns, gatewayPolicy), &v1alpha1.GatewayPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.GatewayPolicy), err
+}
+
+// Delete takes name of the gatewayPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeGatewayPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(gatewaypoliciesResource, c.ns, name, opts), &v1alpha1.GatewayPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeGatewayPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(gatewaypoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.GatewayPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched gatewayPolicy.
+func (c *FakeGatewayPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.GatewayPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(gatewaypoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.GatewayPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.GatewayPolicy), err
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeIDSPolicies implements IDSPolicyInterface
+type FakeIDSPolicies struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var idspoliciesResource = v1alpha1.SchemeGroupVersion.WithResource("idspolicies")
+
+var idspoliciesKind = v1alpha1.SchemeGroupVersion.WithKind("IDSPolicy")
+
+// Get takes name of the iDSPolicy, and returns the corresponding iDSPolicy object, and an error if there is any.
+func (c *FakeIDSPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.IDSPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(idspoliciesResource, c.ns, name), &v1alpha1.IDSPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IDSPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of IDSPolicies that match those selectors.
+func (c *FakeIDSPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.IDSPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(idspoliciesResource, idspoliciesKind, c.ns, opts), &v1alpha1.IDSPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.IDSPolicyList{ListMeta: obj.(*v1alpha1.IDSPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.IDSPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested iDSPolicies.
+func (c *FakeIDSPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(idspoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a iDSPolicy and creates it.  Returns the server's representation of the iDSPolicy, and an error, if there is any.
+func (c *FakeIDSPolicies) Create(ctx context.Context, iDSPolicy *v1alpha1.IDSPolicy, opts v1.CreateOptions) (result *v1alpha1.IDSPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(idspoliciesResource, c.ns, iDSPolicy), &v1alpha1.IDSPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IDSPolicy), err
+}
+
+// Update takes the representation of a iDSPolicy and updates it. Returns the server's representation of the iDSPolicy, and an error, if there is any.
+func (c *FakeIDSPolicies) Update(ctx context.Context, iDSPolicy *v1alpha1.IDSPolicy, opts v1.UpdateOptions) (result *v1alpha1.IDSPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(idspoliciesResource, c.ns, iDSPolicy), &v1alpha1.IDSPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IDSPolicy), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeIDSPolicies) UpdateStatus(ctx context.Context, iDSPolicy *v1alpha1.IDSPolicy, opts v1.UpdateOptions) (*v1alpha1.IDSPolicy, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(idspoliciesResource, "status", c.ns, iDSPolicy), &v1alpha1.IDSPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IDSPolicy), err
+}
+
+// Delete takes name of the iDSPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeIDSPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(idspoliciesResource, c.ns, name, opts), &v1alpha1.IDSPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeIDSPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(idspoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.IDSPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched iDSPolicy.
+func (c *FakeIDSPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.IDSPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(idspoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.IDSPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IDSPolicy), err
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNamespaceNetworkStatuses implements NamespaceNetworkStatusInterface
+type FakeNamespaceNetworkStatuses struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var namespacenetworkstatusesResource = v1alpha1.SchemeGroupVersion.WithResource("namespacenetworkstatuses")
+
+var namespacenetworkstatusesKind = v1alpha1.SchemeGroupVersion.WithKind("NamespaceNetworkStatus")
+
+// Get takes name of the namespaceNetworkStatus, and returns the corresponding namespaceNetworkStatus object, and an error if there is any.
+func (c *FakeNamespaceNetworkStatuses) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NamespaceNetworkStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(namespacenetworkstatusesResource, c.ns, name), &v1alpha1.NamespaceNetworkStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), err
+}
+
+// List takes label and field selectors, and returns the list of NamespaceNetworkStatuses that match those selectors.
+func (c *FakeNamespaceNetworkStatuses) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NamespaceNetworkStatusList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(namespacenetworkstatusesResource, namespacenetworkstatusesKind, c.ns, opts), &v1alpha1.NamespaceNetworkStatusList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.NamespaceNetworkStatusList{ListMeta: obj.(*v1alpha1.NamespaceNetworkStatusList).ListMeta}
+	for _, item := range obj.(*v1alpha1.NamespaceNetworkStatusList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested namespaceNetworkStatuses.
+func (c *FakeNamespaceNetworkStatuses) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(namespacenetworkstatusesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a namespaceNetworkStatus and creates it.  Returns the server's representation of the namespaceNetworkStatus, and an error, if there is any.
+func (c *FakeNamespaceNetworkStatuses) Create(ctx context.Context, namespaceNetworkStatus *v1alpha1.NamespaceNetworkStatus, opts v1.CreateOptions) (result *v1alpha1.NamespaceNetworkStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(namespacenetworkstatusesResource, c.ns, namespaceNetworkStatus), &v1alpha1.NamespaceNetworkStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), err
+}
+
+// Update takes the representation of a namespaceNetworkStatus and updates it. Returns the server's representation of the namespaceNetworkStatus, and an error, if there is any.
+func (c *FakeNamespaceNetworkStatuses) Update(ctx context.Context, namespaceNetworkStatus *v1alpha1.NamespaceNetworkStatus, opts v1.UpdateOptions) (result *v1alpha1.NamespaceNetworkStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(namespacenetworkstatusesResource, c.ns, namespaceNetworkStatus), &v1alpha1.NamespaceNetworkStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNamespaceNetworkStatuses) UpdateStatus(ctx context.Context, namespaceNetworkStatus *v1alpha1.NamespaceNetworkStatus, opts v1.UpdateOptions) (*v1alpha1.NamespaceNetworkStatus, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(namespacenetworkstatusesResource, "status", c.ns, namespaceNetworkStatus), &v1alpha1.NamespaceNetworkStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), err
+}
+
+// Delete takes name of the namespaceNetworkStatus and deletes it. Returns an error if one occurs.
+func (c *FakeNamespaceNetworkStatuses) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(namespacenetworkstatusesResource, c.ns, name, opts), &v1alpha1.NamespaceNetworkStatus{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNamespaceNetworkStatuses) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(namespacenetworkstatusesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.NamespaceNetworkStatusList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched namespaceNetworkStatus.
+func (c *FakeNamespaceNetworkStatuses) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NamespaceNetworkStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(namespacenetworkstatusesResource, c.ns, name, pt, data, subresources...), &v1alpha1.NamespaceNetworkStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), err
+}
@@ -15,18 +15,54 @@ type FakeNsxV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeNsxV1alpha1) EnforcementReports() v1alpha1.EnforcementReportInterface {
+	return &FakeEnforcementReports{c}
+}
+
+func (c *FakeNsxV1alpha1) GatewayPolicies(namespace string) v1alpha1.GatewayPolicyInterface {
+	return &FakeGatewayPolicies{c, namespace}
+}
+
+func (c *FakeNsxV1alpha1) IDSPolicies(namespace string) v1alpha1.IDSPolicyInterface {
+	return &FakeIDSPolicies{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) IPPools(namespace string) v1alpha1.IPPoolInterface {
 	return &FakeIPPools{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) NSXOperatorConfigs() v1alpha1.NSXOperatorConfigInterface {
+	return &FakeNSXOperatorConfigs{c}
+}
+
 func (c *FakeNsxV1alpha1) NSXServiceAccounts(namespace string) v1alpha1.NSXServiceAccountInterface {
 	return &FakeNSXServiceAccounts{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) NamespaceNetworkStatuses(namespace string) v1alpha1.NamespaceNetworkStatusInterface {
+	return &FakeNamespaceNetworkStatuses{c, namespace}
+}
+
+func (c *FakeNsxV1alpha1) PolicyProfiles() v1alpha1.PolicyProfileInterface {
+	return &FakePolicyProfiles{c}
+}
+
+func (c *FakeNsxV1alpha1) ProtectedPolicies() v1alpha1.ProtectedPolicyInterface {
+	return &FakeProtectedPolicies{c}
+}
+
+func (c *FakeNsxV1alpha1) SecurityExclusions() v1alpha1.SecurityExclusionInterface {
+	return &FakeSecurityExclusions{c}
+}
+
 func (c *FakeNsxV1alpha1) SecurityPolicies(namespace string) v1alpha1.SecurityPolicyInterface {
 	return &FakeSecurityPolicies{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) ServiceExposures(namespace string) v1alpha1.ServiceExposureInterface {
+	return &FakeServiceExposures{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) StaticRoutes(namespace string) v1alpha1.StaticRouteInterface {
 	return &FakeStaticRoutes{c, namespace}
 }
@@ -35,6 +71,10 @@ func (c *FakeNsxV1alpha1) Subnets(namespace string) v1alpha1.SubnetInterface {
 	return &FakeSubnets{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) SubnetPolicies(namespace string) v1alpha1.SubnetPolicyInterface {
+	return &FakeSubnetPolicies{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) SubnetPorts(namespace string) v1alpha1.SubnetPortInterface {
 	return &FakeSubnetPorts{c, namespace}
 }
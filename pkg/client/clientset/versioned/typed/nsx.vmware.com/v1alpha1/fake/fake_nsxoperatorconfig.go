@@ -0,0 +1,119 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNSXOperatorConfigs implements NSXOperatorConfigInterface
+type FakeNSXOperatorConfigs struct {
+	Fake *FakeNsxV1alpha1
+}
+
+var nsxoperatorconfigsResource = v1alpha1.SchemeGroupVersion.WithResource("nsxoperatorconfigs")
+
+var nsxoperatorconfigsKind = v1alpha1.SchemeGroupVersion.WithKind("NSXOperatorConfig")
+
+// Get takes name of the nSXOperatorConfig, and returns the corresponding nSXOperatorConfig object, and an error if there is any.
+func (c *FakeNSXOperatorConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(nsxoperatorconfigsResource, name), &v1alpha1.NSXOperatorConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), err
+}
+
+// List takes label and field selectors, and returns the list of NSXOperatorConfigs that match those selectors.
+func (c *FakeNSXOperatorConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NSXOperatorConfigList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(nsxoperatorconfigsResource, nsxoperatorconfigsKind, opts), &v1alpha1.NSXOperatorConfigList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.NSXOperatorConfigList{ListMeta: obj.(*v1alpha1.NSXOperatorConfigList).ListMeta}
+	for _, item := range obj.(*v1alpha1.NSXOperatorConfigList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested nSXOperatorConfigs.
+func (c *FakeNSXOperatorConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(nsxoperatorconfigsResource, opts))
+}
+
+// Create takes the representation of a nSXOperatorConfig and creates it.  Returns the server's representation of the nSXOperatorConfig, and an error, if there is any.
+func (c *FakeNSXOperatorConfigs) Create(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.CreateOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(nsxoperatorconfigsResource, nSXOperatorConfig), &v1alpha1.NSXOperatorConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), err
+}
+
+// Update takes the representation of a nSXOperatorConfig and updates it. Returns the server's representation of the nSXOperatorConfig, and an error, if there is any.
+func (c *FakeNSXOperatorConfigs) Update(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(nsxoperatorconfigsResource, nSXOperatorConfig), &v1alpha1.NSXOperatorConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNSXOperatorConfigs) UpdateStatus(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (*v1alpha1.NSXOperatorConfig, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(nsxoperatorconfigsResource, "status", nSXOperatorConfig), &v1alpha1.NSXOperatorConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), err
+}
+
+// Delete takes name of the nSXOperatorConfig and deletes it. Returns an error if one occurs.
+func (c *FakeNSXOperatorConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(nsxoperatorconfigsResource, name, opts), &v1alpha1.NSXOperatorConfig{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNSXOperatorConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(nsxoperatorconfigsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.NSXOperatorConfigList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched nSXOperatorConfig.
+func (c *FakeNSXOperatorConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NSXOperatorConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(nsxoperatorconfigsResource, name, pt, data, subresources...), &v1alpha1.NSXOperatorConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), err
+}
@@ -0,0 +1,119 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakePolicyProfiles implements PolicyProfileInterface
+type FakePolicyProfiles struct {
+	Fake *FakeNsxV1alpha1
+}
+
+var policyprofilesResource = v1alpha1.SchemeGroupVersion.WithResource("policyprofiles")
+
+var policyprofilesKind = v1alpha1.SchemeGroupVersion.WithKind("PolicyProfile")
+
+// Get takes name of the policyProfile, and returns the corresponding policyProfile object, and an error if there is any.
+func (c *FakePolicyProfiles) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.PolicyProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(policyprofilesResource, name), &v1alpha1.PolicyProfile{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PolicyProfile), err
+}
+
+// List takes label and field selectors, and returns the list of PolicyProfiles that match those selectors.
+func (c *FakePolicyProfiles) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.PolicyProfileList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(policyprofilesResource, policyprofilesKind, opts), &v1alpha1.PolicyProfileList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.PolicyProfileList{ListMeta: obj.(*v1alpha1.PolicyProfileList).ListMeta}
+	for _, item := range obj.(*v1alpha1.PolicyProfileList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested policyProfiles.
+func (c *FakePolicyProfiles) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(policyprofilesResource, opts))
+}
+
+// Create takes the representation of a policyProfile and creates it.  Returns the server's representation of the policyProfile, and an error, if there is any.
+func (c *FakePolicyProfiles) Create(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.CreateOptions) (result *v1alpha1.PolicyProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(policyprofilesResource, policyProfile), &v1alpha1.PolicyProfile{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PolicyProfile), err
+}
+
+// Update takes the representation of a policyProfile and updates it. Returns the server's representation of the policyProfile, and an error, if there is any.
+func (c *FakePolicyProfiles) Update(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (result *v1alpha1.PolicyProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(policyprofilesResource, policyProfile), &v1alpha1.PolicyProfile{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PolicyProfile), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakePolicyProfiles) UpdateStatus(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (*v1alpha1.PolicyProfile, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(policyprofilesResource, "status", policyProfile), &v1alpha1.PolicyProfile{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PolicyProfile), err
+}
+
+// Delete takes name of the policyProfile and deletes it. Returns an error if one occurs.
+func (c *FakePolicyProfiles) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(policyprofilesResource, name, opts), &v1alpha1.PolicyProfile{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePolicyProfiles) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(policyprofilesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.PolicyProfileList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched policyProfile.
+func (c *FakePolicyProfiles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PolicyProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(policyprofilesResource, name, pt, data, subresources...), &v1alpha1.PolicyProfile{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PolicyProfile), err
+}
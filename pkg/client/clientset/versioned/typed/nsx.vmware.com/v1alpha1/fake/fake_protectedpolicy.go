@@ -0,0 +1,108 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeProtectedPolicies implements ProtectedPolicyInterface
+type FakeProtectedPolicies struct {
+	Fake *FakeNsxV1alpha1
+}
+
+var protectedpoliciesResource = v1alpha1.SchemeGroupVersion.WithResource("protectedpolicies")
+
+var protectedpoliciesKind = v1alpha1.SchemeGroupVersion.WithKind("ProtectedPolicy")
+
+// Get takes name of the protectedPolicy, and returns the corresponding protectedPolicy object, and an error if there is any.
+func (c *FakeProtectedPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(protectedpoliciesResource, name), &v1alpha1.ProtectedPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ProtectedPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of ProtectedPolicies that match those selectors.
+func (c *FakeProtectedPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ProtectedPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(protectedpoliciesResource, protectedpoliciesKind, opts), &v1alpha1.ProtectedPolicyList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ProtectedPolicyList{ListMeta: obj.(*v1alpha1.ProtectedPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ProtectedPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested protectedPolicies.
+func (c *FakeProtectedPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(protectedpoliciesResource, opts))
+}
+
+// Create takes the representation of a protectedPolicy and creates it.  Returns the server's representation of the protectedPolicy, and an error, if there is any.
+func (c *FakeProtectedPolicies) Create(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.CreateOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(protectedpoliciesResource, protectedPolicy), &v1alpha1.ProtectedPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ProtectedPolicy), err
+}
+
+// Update takes the representation of a protectedPolicy and updates it. Returns the server's representation of the protectedPolicy, and an error, if there is any.
+func (c *FakeProtectedPolicies) Update(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.UpdateOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(protectedpoliciesResource, protectedPolicy), &v1alpha1.ProtectedPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ProtectedPolicy), err
+}
+
+// Delete takes name of the protectedPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeProtectedPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(protectedpoliciesResource, name, opts), &v1alpha1.ProtectedPolicy{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeProtectedPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(protectedpoliciesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ProtectedPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched protectedPolicy.
+func (c *FakeProtectedPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ProtectedPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(protectedpoliciesResource, name, pt, data, subresources...), &v1alpha1.ProtectedPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ProtectedPolicy), err
+}
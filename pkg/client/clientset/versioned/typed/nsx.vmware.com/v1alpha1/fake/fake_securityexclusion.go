@@ -0,0 +1,119 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeSecurityExclusions implements SecurityExclusionInterface
+type FakeSecurityExclusions struct {
+	Fake *FakeNsxV1alpha1
+}
+
+var securityexclusionsResource = v1alpha1.SchemeGroupVersion.WithResource("securityexclusions")
+
+var securityexclusionsKind = v1alpha1.SchemeGroupVersion.WithKind("SecurityExclusion")
+
+// Get takes name of the securityExclusion, and returns the corresponding securityExclusion object, and an error if there is any.
+func (c *FakeSecurityExclusions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(securityexclusionsResource, name), &v1alpha1.SecurityExclusion{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SecurityExclusion), err
+}
+
+// List takes label and field selectors, and returns the list of SecurityExclusions that match those selectors.
+func (c *FakeSecurityExclusions) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SecurityExclusionList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(securityexclusionsResource, securityexclusionsKind, opts), &v1alpha1.SecurityExclusionList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.SecurityExclusionList{ListMeta: obj.(*v1alpha1.SecurityExclusionList).ListMeta}
+	for _, item := range obj.(*v1alpha1.SecurityExclusionList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested securityExclusions.
+func (c *FakeSecurityExclusions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(securityexclusionsResource, opts))
+}
+
+// Create takes the representation of a securityExclusion and creates it.  Returns the server's representation of the securityExclusion, and an error, if there is any.
+func (c *FakeSecurityExclusions) Create(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.CreateOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(securityexclusionsResource, securityExclusion), &v1alpha1.SecurityExclusion{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SecurityExclusion), err
+}
+
+// Update takes the representation of a securityExclusion and updates it. Returns the server's representation of the securityExclusion, and an error, if there is any.
+func (c *FakeSecurityExclusions) Update(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(securityexclusionsResource, securityExclusion), &v1alpha1.SecurityExclusion{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SecurityExclusion), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeSecurityExclusions) UpdateStatus(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (*v1alpha1.SecurityExclusion, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(securityexclusionsResource, "status", securityExclusion), &v1alpha1.SecurityExclusion{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SecurityExclusion), err
+}
+
+// Delete takes name of the securityExclusion and deletes it. Returns an error if one occurs.
+func (c *FakeSecurityExclusions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(securityexclusionsResource, name, opts), &v1alpha1.SecurityExclusion{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSecurityExclusions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(securityexclusionsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.SecurityExclusionList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched securityExclusion.
+func (c *FakeSecurityExclusions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SecurityExclusion, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(securityexclusionsResource, name, pt, data, subresources...), &v1alpha1.SecurityExclusion{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SecurityExclusion), err
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeServiceExposures implements ServiceExposureInterface
+type FakeServiceExposures struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var serviceexposuresResource = v1alpha1.SchemeGroupVersion.WithResource("serviceexposures")
+
+var serviceexposuresKind = v1alpha1.SchemeGroupVersion.WithKind("ServiceExposure")
+
+// Get takes name of the serviceExposure, and returns the corresponding serviceExposure object, and an error if there is any.
+func (c *FakeServiceExposures) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ServiceExposure, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(serviceexposuresResource, c.ns, name), &v1alpha1.ServiceExposure{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceExposure), err
+}
+
+// List takes label and field selectors, and returns the list of ServiceExposures that match those selectors.
+func (c *FakeServiceExposures) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ServiceExposureList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(serviceexposuresResource, serviceexposuresKind, c.ns, opts), &v1alpha1.ServiceExposureList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ServiceExposureList{ListMeta: obj.(*v1alpha1.ServiceExposureList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ServiceExposureList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested serviceExposures.
+func (c *FakeServiceExposures) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(serviceexposuresResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a serviceExposure and creates it.  Returns the server's representation of the serviceExposure, and an error, if there is any.
+func (c *FakeServiceExposures) Create(ctx context.Context, serviceExposure *v1alpha1.ServiceExposure, opts v1.CreateOptions) (result *v1alpha1.ServiceExposure, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(serviceexposuresResource, c.ns, serviceExposure), &v1alpha1.ServiceExposure{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceExposure), err
+}
+
+// Update takes the representation of a serviceExposure and updates it. Returns the server's representation of the serviceExposure, and an error, if there is any.
+func (c *FakeServiceExposures) Update(ctx context.Context, serviceExposure *v1alpha1.ServiceExposure, opts v1.UpdateOptions) (result *v1alpha1.ServiceExposure, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(serviceexposuresResource, c.ns, serviceExposure), &v1alpha1.ServiceExposure{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceExposure), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeServiceExposures) UpdateStatus(ctx context.Context, serviceExposure *v1alpha1.ServiceExposure, opts v1.UpdateOptions) (*v1alpha1.ServiceExposure, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(serviceexposuresResource, "status", c.ns, serviceExposure), &v1alpha1.ServiceExposure{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceExposure), err
+}
+
+// Delete takes name of the serviceExposure and deletes it. Returns an error if one occurs.
+func (c *FakeServiceExposures) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(serviceexposuresResource, c.ns, name, opts), &v1alpha1.ServiceExposure{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeServiceExposures) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(serviceexposuresResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ServiceExposureList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched serviceExposure.
+func (c *FakeServiceExposures) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceExposure, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(serviceexposuresResource, c.ns, name, pt, data, subresources...), &v1alpha1.ServiceExposure{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceExposure), err
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeSubnetPolicies implements SubnetPolicyInterface
+type FakeSubnetPolicies struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var subnetpoliciesResource = v1alpha1.SchemeGroupVersion.WithResource("subnetpolicies")
+
+var subnetpoliciesKind = v1alpha1.SchemeGroupVersion.WithKind("SubnetPolicy")
+
+// Get takes name of the subnetPolicy, and returns the corresponding subnetPolicy object, and an error if there is any.
+func (c *FakeSubnetPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(subnetpoliciesResource, c.ns, name), &v1alpha1.SubnetPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SubnetPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of SubnetPolicies that match those selectors.
+func (c *FakeSubnetPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SubnetPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(subnetpoliciesResource, subnetpoliciesKind, c.ns, opts), &v1alpha1.SubnetPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.SubnetPolicyList{ListMeta: obj.(*v1alpha1.SubnetPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.SubnetPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested subnetPolicies.
+func (c *FakeSubnetPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(subnetpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a subnetPolicy and creates it.  Returns the server's representation of the subnetPolicy, and an error, if there is any.
+func (c *FakeSubnetPolicies) Create(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.CreateOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(subnetpoliciesResource, c.ns, subnetPolicy), &v1alpha1.SubnetPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SubnetPolicy), err
+}
+
+// Update takes the representation of a subnetPolicy and updates it. Returns the server's representation of the subnetPolicy, and an error, if there is any.
+func (c *FakeSubnetPolicies) Update(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(subnetpoliciesResource, c.ns, subnetPolicy), &v1alpha1.SubnetPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SubnetPolicy), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeSubnetPolicies) UpdateStatus(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (*v1alpha1.SubnetPolicy, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(subnetpoliciesResource, "status", c.ns, subnetPolicy), &v1alpha1.SubnetPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SubnetPolicy), err
+}
+
+// Delete takes name of the subnetPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeSubnetPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(subnetpoliciesResource, c.ns, name, opts), &v1alpha1.SubnetPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSubnetPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(subnetpoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.SubnetPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched subnetPolicy.
+func (c *FakeSubnetPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SubnetPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(subnetpoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.SubnetPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SubnetPolicy), err
+}
@@ -5,16 +5,36 @@
 
 package v1alpha1
 
+type EnforcementReportExpansion interface{}
+
+type GatewayPolicyExpansion interface{}
+
+type IDSPolicyExpansion interface{}
+
 type IPPoolExpansion interface{}
 
+type NSXOperatorConfigExpansion interface{}
+
 type NSXServiceAccountExpansion interface{}
 
+type NamespaceNetworkStatusExpansion interface{}
+
+type PolicyProfileExpansion interface{}
+
+type ProtectedPolicyExpansion interface{}
+
+type SecurityExclusionExpansion interface{}
+
 type SecurityPolicyExpansion interface{}
 
+type ServiceExposureExpansion interface{}
+
 type StaticRouteExpansion interface{}
 
 type SubnetExpansion interface{}
 
+type SubnetPolicyExpansion interface{}
+
 type SubnetPortExpansion interface{}
 
 type SubnetSetExpansion interface{}
@@ -15,11 +15,21 @@ import (
 
 type NsxV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	EnforcementReportsGetter
+	GatewayPoliciesGetter
+	IDSPoliciesGetter
 	IPPoolsGetter
+	NSXOperatorConfigsGetter
 	NSXServiceAccountsGetter
+	NamespaceNetworkStatusesGetter
+	PolicyProfilesGetter
+	ProtectedPoliciesGetter
+	SecurityExclusionsGetter
 	SecurityPoliciesGetter
+	ServiceExposuresGetter
 	StaticRoutesGetter
 	SubnetsGetter
+	SubnetPoliciesGetter
 	SubnetPortsGetter
 	SubnetSetsGetter
 	VPCsGetter
@@ -31,18 +41,54 @@ type NsxV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *NsxV1alpha1Client) EnforcementReports() EnforcementReportInterface {
+	return newEnforcementReports(c)
+}
+
+func (c *NsxV1alpha1Client) GatewayPolicies(namespace string) GatewayPolicyInterface {
+	return newGatewayPolicies(c, namespace)
+}
+
+func (c *NsxV1alpha1Client) IDSPolicies(namespace string) IDSPolicyInterface {
+	return newIDSPolicies(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) IPPools(namespace string) IPPoolInterface {
 	return newIPPools(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) NSXOperatorConfigs() NSXOperatorConfigInterface {
+	return newNSXOperatorConfigs(c)
+}
+
 func (c *NsxV1alpha1Client) NSXServiceAccounts(namespace string) NSXServiceAccountInterface {
 	return newNSXServiceAccounts(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) NamespaceNetworkStatuses(namespace string) NamespaceNetworkStatusInterface {
+	return newNamespaceNetworkStatuses(c, namespace)
+}
+
+func (c *NsxV1alpha1Client) PolicyProfiles() PolicyProfileInterface {
+	return newPolicyProfiles(c)
+}
+
+func (c *NsxV1alpha1Client) ProtectedPolicies() ProtectedPolicyInterface {
+	return newProtectedPolicies(c)
+}
+
+func (c *NsxV1alpha1Client) SecurityExclusions() SecurityExclusionInterface {
+	return newSecurityExclusions(c)
+}
+
 func (c *NsxV1alpha1Client) SecurityPolicies(namespace string) SecurityPolicyInterface {
 	return newSecurityPolicies(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) ServiceExposures(namespace string) ServiceExposureInterface {
+	return newServiceExposures(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) StaticRoutes(namespace string) StaticRouteInterface {
 	return newStaticRoutes(c, namespace)
 }
@@ -51,6 +97,10 @@ func (c *NsxV1alpha1Client) Subnets(namespace string) SubnetInterface {
 	return newSubnets(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) SubnetPolicies(namespace string) SubnetPolicyInterface {
+	return newSubnetPolicies(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) SubnetPorts(namespace string) SubnetPortInterface {
 	return newSubnetPorts(c, namespace)
 }
@@ -0,0 +1,171 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NSXOperatorConfigsGetter has a method to return a NSXOperatorConfigInterface.
+// A group's client should implement this interface.
+type NSXOperatorConfigsGetter interface {
+	NSXOperatorConfigs() NSXOperatorConfigInterface
+}
+
+// NSXOperatorConfigInterface has methods to work with NSXOperatorConfig resources.
+type NSXOperatorConfigInterface interface {
+	Create(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.CreateOptions) (*v1alpha1.NSXOperatorConfig, error)
+	Update(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (*v1alpha1.NSXOperatorConfig, error)
+	UpdateStatus(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (*v1alpha1.NSXOperatorConfig, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.NSXOperatorConfig, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.NSXOperatorConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NSXOperatorConfig, err error)
+	NSXOperatorConfigExpansion
+}
+
+// nSXOperatorConfigs implements NSXOperatorConfigInterface
+type nSXOperatorConfigs struct {
+	client rest.Interface
+}
+
+// newNSXOperatorConfigs returns a NSXOperatorConfigs
+func newNSXOperatorConfigs(c *NsxV1alpha1Client) *nSXOperatorConfigs {
+	return &nSXOperatorConfigs{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the nSXOperatorConfig, and returns the corresponding nSXOperatorConfig object, and an error if there is any.
+func (c *nSXOperatorConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	result = &v1alpha1.NSXOperatorConfig{}
+	err = c.client.Get().
+		Resource("nsxoperatorconfigs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NSXOperatorConfigs that match those selectors.
+func (c *nSXOperatorConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NSXOperatorConfigList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.NSXOperatorConfigList{}
+	err = c.client.Get().
+		Resource("nsxoperatorconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested nSXOperatorConfigs.
+func (c *nSXOperatorConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("nsxoperatorconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a nSXOperatorConfig and creates it.  Returns the server's representation of the nSXOperatorConfig, and an error, if there is any.
+func (c *nSXOperatorConfigs) Create(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.CreateOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	result = &v1alpha1.NSXOperatorConfig{}
+	err = c.client.Post().
+		Resource("nsxoperatorconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(nSXOperatorConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a nSXOperatorConfig and updates it. Returns the server's representation of the nSXOperatorConfig, and an error, if there is any.
+func (c *nSXOperatorConfigs) Update(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	result = &v1alpha1.NSXOperatorConfig{}
+	err = c.client.Put().
+		Resource("nsxoperatorconfigs").
+		Name(nSXOperatorConfig.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(nSXOperatorConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *nSXOperatorConfigs) UpdateStatus(ctx context.Context, nSXOperatorConfig *v1alpha1.NSXOperatorConfig, opts v1.UpdateOptions) (result *v1alpha1.NSXOperatorConfig, err error) {
+	result = &v1alpha1.NSXOperatorConfig{}
+	err = c.client.Put().
+		Resource("nsxoperatorconfigs").
+		Name(nSXOperatorConfig.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(nSXOperatorConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the nSXOperatorConfig and deletes it. Returns an error if one occurs.
+func (c *nSXOperatorConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("nsxoperatorconfigs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *nSXOperatorConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("nsxoperatorconfigs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched nSXOperatorConfig.
+func (c *nSXOperatorConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NSXOperatorConfig, err error) {
+	result = &v1alpha1.NSXOperatorConfig{}
+	err = c.client.Patch(pt).
+		Resource("nsxoperatorconfigs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
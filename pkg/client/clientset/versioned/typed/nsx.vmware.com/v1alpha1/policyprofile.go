@@ -0,0 +1,171 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// PolicyProfilesGetter has a method to return a PolicyProfileInterface.
+// A group's client should implement this interface.
+type PolicyProfilesGetter interface {
+	PolicyProfiles() PolicyProfileInterface
+}
+
+// PolicyProfileInterface has methods to work with PolicyProfile resources.
+type PolicyProfileInterface interface {
+	Create(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.CreateOptions) (*v1alpha1.PolicyProfile, error)
+	Update(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (*v1alpha1.PolicyProfile, error)
+	UpdateStatus(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (*v1alpha1.PolicyProfile, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.PolicyProfile, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.PolicyProfileList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PolicyProfile, err error)
+	PolicyProfileExpansion
+}
+
+// policyProfiles implements PolicyProfileInterface
+type policyProfiles struct {
+	client rest.Interface
+}
+
+// newPolicyProfiles returns a PolicyProfiles
+func newPolicyProfiles(c *NsxV1alpha1Client) *policyProfiles {
+	return &policyProfiles{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the policyProfile, and returns the corresponding policyProfile object, and an error if there is any.
+func (c *policyProfiles) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.PolicyProfile, err error) {
+	result = &v1alpha1.PolicyProfile{}
+	err = c.client.Get().
+		Resource("policyprofiles").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of PolicyProfiles that match those selectors.
+func (c *policyProfiles) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.PolicyProfileList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.PolicyProfileList{}
+	err = c.client.Get().
+		Resource("policyprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested policyProfiles.
+func (c *policyProfiles) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("policyprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a policyProfile and creates it.  Returns the server's representation of the policyProfile, and an error, if there is any.
+func (c *policyProfiles) Create(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.CreateOptions) (result *v1alpha1.PolicyProfile, err error) {
+	result = &v1alpha1.PolicyProfile{}
+	err = c.client.Post().
+		Resource("policyprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(policyProfile).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a policyProfile and updates it. Returns the server's representation of the policyProfile, and an error, if there is any.
+func (c *policyProfiles) Update(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (result *v1alpha1.PolicyProfile, err error) {
+	result = &v1alpha1.PolicyProfile{}
+	err = c.client.Put().
+		Resource("policyprofiles").
+		Name(policyProfile.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(policyProfile).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *policyProfiles) UpdateStatus(ctx context.Context, policyProfile *v1alpha1.PolicyProfile, opts v1.UpdateOptions) (result *v1alpha1.PolicyProfile, err error) {
+	result = &v1alpha1.PolicyProfile{}
+	err = c.client.Put().
+		Resource("policyprofiles").
+		Name(policyProfile.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(policyProfile).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the policyProfile and deletes it. Returns an error if one occurs.
+func (c *policyProfiles) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("policyprofiles").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *policyProfiles) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("policyprofiles").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched policyProfile.
+func (c *policyProfiles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PolicyProfile, err error) {
+	result = &v1alpha1.PolicyProfile{}
+	err = c.client.Patch(pt).
+		Resource("policyprofiles").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,155 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ProtectedPoliciesGetter has a method to return a ProtectedPolicyInterface.
+// A group's client should implement this interface.
+type ProtectedPoliciesGetter interface {
+	ProtectedPolicies() ProtectedPolicyInterface
+}
+
+// ProtectedPolicyInterface has methods to work with ProtectedPolicy resources.
+type ProtectedPolicyInterface interface {
+	Create(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.CreateOptions) (*v1alpha1.ProtectedPolicy, error)
+	Update(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.UpdateOptions) (*v1alpha1.ProtectedPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ProtectedPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ProtectedPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ProtectedPolicy, err error)
+	ProtectedPolicyExpansion
+}
+
+// protectedPolicies implements ProtectedPolicyInterface
+type protectedPolicies struct {
+	client rest.Interface
+}
+
+// newProtectedPolicies returns a ProtectedPolicies
+func newProtectedPolicies(c *NsxV1alpha1Client) *protectedPolicies {
+	return &protectedPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the protectedPolicy, and returns the corresponding protectedPolicy object, and an error if there is any.
+func (c *protectedPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	result = &v1alpha1.ProtectedPolicy{}
+	err = c.client.Get().
+		Resource("protectedpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ProtectedPolicies that match those selectors.
+func (c *protectedPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ProtectedPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ProtectedPolicyList{}
+	err = c.client.Get().
+		Resource("protectedpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested protectedPolicies.
+func (c *protectedPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("protectedpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a protectedPolicy and creates it.  Returns the server's representation of the protectedPolicy, and an error, if there is any.
+func (c *protectedPolicies) Create(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.CreateOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	result = &v1alpha1.ProtectedPolicy{}
+	err = c.client.Post().
+		Resource("protectedpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(protectedPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a protectedPolicy and updates it. Returns the server's representation of the protectedPolicy, and an error, if there is any.
+func (c *protectedPolicies) Update(ctx context.Context, protectedPolicy *v1alpha1.ProtectedPolicy, opts v1.UpdateOptions) (result *v1alpha1.ProtectedPolicy, err error) {
+	result = &v1alpha1.ProtectedPolicy{}
+	err = c.client.Put().
+		Resource("protectedpolicies").
+		Name(protectedPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(protectedPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the protectedPolicy and deletes it. Returns an error if one occurs.
+func (c *protectedPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("protectedpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *protectedPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("protectedpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched protectedPolicy.
+func (c *protectedPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ProtectedPolicy, err error) {
+	result = &v1alpha1.ProtectedPolicy{}
+	err = c.client.Patch(pt).
+		Resource("protectedpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
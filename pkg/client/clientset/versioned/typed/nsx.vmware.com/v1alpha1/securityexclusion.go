@@ -0,0 +1,171 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SecurityExclusionsGetter has a method to return a SecurityExclusionInterface.
+// A group's client should implement this interface.
+type SecurityExclusionsGetter interface {
+	SecurityExclusions() SecurityExclusionInterface
+}
+
+// SecurityExclusionInterface has methods to work with SecurityExclusion resources.
+type SecurityExclusionInterface interface {
+	Create(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.CreateOptions) (*v1alpha1.SecurityExclusion, error)
+	Update(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (*v1alpha1.SecurityExclusion, error)
+	UpdateStatus(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (*v1alpha1.SecurityExclusion, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.SecurityExclusion, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.SecurityExclusionList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SecurityExclusion, err error)
+	SecurityExclusionExpansion
+}
+
+// securityExclusions implements SecurityExclusionInterface
+type securityExclusions struct {
+	client rest.Interface
+}
+
+// newSecurityExclusions returns a SecurityExclusions
+func newSecurityExclusions(c *NsxV1alpha1Client) *securityExclusions {
+	return &securityExclusions{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the securityExclusion, and returns the corresponding securityExclusion object, and an error if there is any.
+func (c *securityExclusions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	result = &v1alpha1.SecurityExclusion{}
+	err = c.client.Get().
+		Resource("securityexclusions").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SecurityExclusions that match those selectors.
+func (c *securityExclusions) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SecurityExclusionList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.SecurityExclusionList{}
+	err = c.client.Get().
+		Resource("securityexclusions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested securityExclusions.
+func (c *securityExclusions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("securityexclusions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a securityExclusion and creates it.  Returns the server's representation of the securityExclusion, and an error, if there is any.
+func (c *securityExclusions) Create(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.CreateOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	result = &v1alpha1.SecurityExclusion{}
+	err = c.client.Post().
+		Resource("securityexclusions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(securityExclusion).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a securityExclusion and updates it. Returns the server's representation of the securityExclusion, and an error, if there is any.
+func (c *securityExclusions) Update(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	result = &v1alpha1.SecurityExclusion{}
+	err = c.client.Put().
+		Resource("securityexclusions").
+		Name(securityExclusion.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(securityExclusion).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *securityExclusions) UpdateStatus(ctx context.Context, securityExclusion *v1alpha1.SecurityExclusion, opts v1.UpdateOptions) (result *v1alpha1.SecurityExclusion, err error) {
+	result = &v1alpha1.SecurityExclusion{}
+	err = c.client.Put().
+		Resource("securityexclusions").
+		Name(securityExclusion.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(securityExclusion).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the securityExclusion and deletes it. Returns an error if one occurs.
+func (c *securityExclusions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("securityexclusions").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *securityExclusions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("securityexclusions").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched securityExclusion.
+func (c *securityExclusions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SecurityExclusion, err error) {
+	result = &v1alpha1.SecurityExclusion{}
+	err = c.client.Patch(pt).
+		Resource("securityexclusions").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
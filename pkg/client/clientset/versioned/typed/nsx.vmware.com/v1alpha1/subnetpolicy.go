@@ -0,0 +1,182 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SubnetPoliciesGetter has a method to return a SubnetPolicyInterface.
+// A group's client should implement this interface.
+type SubnetPoliciesGetter interface {
+	SubnetPolicies(namespace string) SubnetPolicyInterface
+}
+
+// SubnetPolicyInterface has methods to work with SubnetPolicy resources.
+type SubnetPolicyInterface interface {
+	Create(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.CreateOptions) (*v1alpha1.SubnetPolicy, error)
+	Update(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (*v1alpha1.SubnetPolicy, error)
+	UpdateStatus(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (*v1alpha1.SubnetPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.SubnetPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.SubnetPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SubnetPolicy, err error)
+	SubnetPolicyExpansion
+}
+
+// subnetPolicies implements SubnetPolicyInterface
+type subnetPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newSubnetPolicies returns a SubnetPolicies
+func newSubnetPolicies(c *NsxV1alpha1Client, namespace string) *subnetPolicies {
+	return &subnetPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the subnetPolicy, and returns the corresponding subnetPolicy object, and an error if there is any.
+func (c *subnetPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	result = &v1alpha1.SubnetPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SubnetPolicies that match those selectors.
+func (c *subnetPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SubnetPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.SubnetPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested subnetPolicies.
+func (c *subnetPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a subnetPolicy and creates it.  Returns the server's representation of the subnetPolicy, and an error, if there is any.
+func (c *subnetPolicies) Create(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.CreateOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	result = &v1alpha1.SubnetPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(subnetPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a subnetPolicy and updates it. Returns the server's representation of the subnetPolicy, and an error, if there is any.
+func (c *subnetPolicies) Update(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	result = &v1alpha1.SubnetPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		Name(subnetPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(subnetPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *subnetPolicies) UpdateStatus(ctx context.Context, subnetPolicy *v1alpha1.SubnetPolicy, opts v1.UpdateOptions) (result *v1alpha1.SubnetPolicy, err error) {
+	result = &v1alpha1.SubnetPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		Name(subnetPolicy.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(subnetPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the subnetPolicy and deletes it. Returns an error if one occurs.
+func (c *subnetPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *subnetPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched subnetPolicy.
+func (c *subnetPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SubnetPolicy, err error) {
+	result = &v1alpha1.SubnetPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("subnetpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
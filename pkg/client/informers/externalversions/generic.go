@@ -41,16 +41,36 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=nsx.vmware.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("enforcementreports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().EnforcementReports().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("gatewaypolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().GatewayPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("idspolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().IDSPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("ippools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().IPPools().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("nsxoperatorconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NSXOperatorConfigs().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nsxserviceaccounts"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NSXServiceAccounts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("namespacenetworkstatuses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NamespaceNetworkStatuses().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("policyprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().PolicyProfiles().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("protectedpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().ProtectedPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("securityexclusions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().SecurityExclusions().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("securitypolicies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().SecurityPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("serviceexposures"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().ServiceExposures().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("staticroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().StaticRoutes().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("subnets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().Subnets().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("subnetpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().SubnetPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("subnetports"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().SubnetPorts().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("subnetsets"):
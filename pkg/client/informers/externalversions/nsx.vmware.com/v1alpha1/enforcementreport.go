@@ -0,0 +1,76 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	nsxvmwarecomv1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	versioned "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/vmware-tanzu/nsx-operator/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/client/listers/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// EnforcementReportInformer provides access to a shared informer and lister for
+// EnforcementReports.
+type EnforcementReportInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.EnforcementReportLister
+}
+
+type enforcementReportInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewEnforcementReportInformer constructs a new informer for EnforcementReport type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewEnforcementReportInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredEnforcementReportInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredEnforcementReportInformer constructs a new informer for EnforcementReport type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredEnforcementReportInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NsxV1alpha1().EnforcementReports().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NsxV1alpha1().EnforcementReports().Watch(context.TODO(), options)
+			},
+		},
+		&nsxvmwarecomv1alpha1.EnforcementReport{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *enforcementReportInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredEnforcementReportInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *enforcementReportInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&nsxvmwarecomv1alpha1.EnforcementReport{}, f.defaultInformer)
+}
+
+func (f *enforcementReportInformer) Lister() v1alpha1.EnforcementReportLister {
+	return v1alpha1.NewEnforcementReportLister(f.Informer().GetIndexer())
+}
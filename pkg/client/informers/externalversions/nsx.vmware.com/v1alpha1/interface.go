@@ -11,16 +11,36 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// EnforcementReports returns a EnforcementReportInformer.
+	EnforcementReports() EnforcementReportInformer
+	// GatewayPolicies returns a GatewayPolicyInformer.
+	GatewayPolicies() GatewayPolicyInformer
+	// IDSPolicies returns a IDSPolicyInformer.
+	IDSPolicies() IDSPolicyInformer
 	// IPPools returns a IPPoolInformer.
 	IPPools() IPPoolInformer
+	// NSXOperatorConfigs returns a NSXOperatorConfigInformer.
+	NSXOperatorConfigs() NSXOperatorConfigInformer
 	// NSXServiceAccounts returns a NSXServiceAccountInformer.
 	NSXServiceAccounts() NSXServiceAccountInformer
+	// NamespaceNetworkStatuses returns a NamespaceNetworkStatusInformer.
+	NamespaceNetworkStatuses() NamespaceNetworkStatusInformer
+	// PolicyProfiles returns a PolicyProfileInformer.
+	PolicyProfiles() PolicyProfileInformer
+	// ProtectedPolicies returns a ProtectedPolicyInformer.
+	ProtectedPolicies() ProtectedPolicyInformer
+	// SecurityExclusions returns a SecurityExclusionInformer.
+	SecurityExclusions() SecurityExclusionInformer
 	// SecurityPolicies returns a SecurityPolicyInformer.
 	SecurityPolicies() SecurityPolicyInformer
+	// ServiceExposures returns a ServiceExposureInformer.
+	ServiceExposures() ServiceExposureInformer
 	// StaticRoutes returns a StaticRouteInformer.
 	StaticRoutes() StaticRouteInformer
 	// Subnets returns a SubnetInformer.
 	Subnets() SubnetInformer
+	// SubnetPolicies returns a SubnetPolicyInformer.
+	SubnetPolicies() SubnetPolicyInformer
 	// SubnetPorts returns a SubnetPortInformer.
 	SubnetPorts() SubnetPortInformer
 	// SubnetSets returns a SubnetSetInformer.
@@ -42,21 +62,66 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// EnforcementReports returns a EnforcementReportInformer.
+func (v *version) EnforcementReports() EnforcementReportInformer {
+	return &enforcementReportInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// GatewayPolicies returns a GatewayPolicyInformer.
+func (v *version) GatewayPolicies() GatewayPolicyInformer {
+	return &gatewayPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// IDSPolicies returns a IDSPolicyInformer.
+func (v *version) IDSPolicies() IDSPolicyInformer {
+	return &iDSPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // IPPools returns a IPPoolInformer.
 func (v *version) IPPools() IPPoolInformer {
 	return &iPPoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NSXOperatorConfigs returns a NSXOperatorConfigInformer.
+func (v *version) NSXOperatorConfigs() NSXOperatorConfigInformer {
+	return &nSXOperatorConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NSXServiceAccounts returns a NSXServiceAccountInformer.
 func (v *version) NSXServiceAccounts() NSXServiceAccountInformer {
 	return &nSXServiceAccountInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NamespaceNetworkStatuses returns a NamespaceNetworkStatusInformer.
+func (v *version) NamespaceNetworkStatuses() NamespaceNetworkStatusInformer {
+	return &namespaceNetworkStatusInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PolicyProfiles returns a PolicyProfileInformer.
+func (v *version) PolicyProfiles() PolicyProfileInformer {
+	return &policyProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ProtectedPolicies returns a ProtectedPolicyInformer.
+func (v *version) ProtectedPolicies() ProtectedPolicyInformer {
+	return &protectedPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// SecurityExclusions returns a SecurityExclusionInformer.
+func (v *version) SecurityExclusions() SecurityExclusionInformer {
+	return &securityExclusionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // SecurityPolicies returns a SecurityPolicyInformer.
 func (v *version) SecurityPolicies() SecurityPolicyInformer {
 	return &securityPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ServiceExposures returns a ServiceExposureInformer.
+func (v *version) ServiceExposures() ServiceExposureInformer {
+	return &serviceExposureInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // StaticRoutes returns a StaticRouteInformer.
 func (v *version) StaticRoutes() StaticRouteInformer {
 	return &staticRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -67,6 +132,11 @@ func (v *version) Subnets() SubnetInformer {
 	return &subnetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SubnetPolicies returns a SubnetPolicyInformer.
+func (v *version) SubnetPolicies() SubnetPolicyInformer {
+	return &subnetPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // SubnetPorts returns a SubnetPortInformer.
 func (v *version) SubnetPorts() SubnetPortInformer {
 	return &subnetPortInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
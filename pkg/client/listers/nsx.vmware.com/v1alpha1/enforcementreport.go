@@ -0,0 +1,55 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// EnforcementReportLister helps list EnforcementReports.
+// All objects returned here must be treated as read-only.
+type EnforcementReportLister interface {
+	// List lists all EnforcementReports in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.EnforcementReport, err error)
+	// Get retrieves the EnforcementReport from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.EnforcementReport, error)
+	EnforcementReportListerExpansion
+}
+
+// enforcementReportLister implements the EnforcementReportLister interface.
+type enforcementReportLister struct {
+	indexer cache.Indexer
+}
+
+// NewEnforcementReportLister returns a new EnforcementReportLister.
+func NewEnforcementReportLister(indexer cache.Indexer) EnforcementReportLister {
+	return &enforcementReportLister{indexer: indexer}
+}
+
+// List lists all EnforcementReports in the indexer.
+func (s *enforcementReportLister) List(selector labels.Selector) (ret []*v1alpha1.EnforcementReport, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.EnforcementReport))
+	})
+	return ret, err
+}
+
+// Get retrieves the EnforcementReport from the index for a given name.
+func (s *enforcementReportLister) Get(name string) (*v1alpha1.EnforcementReport, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("enforcementreport"), name)
+	}
+	return obj.(*v1alpha1.EnforcementReport), nil
+}
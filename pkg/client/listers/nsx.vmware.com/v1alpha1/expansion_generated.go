@@ -5,6 +5,26 @@
 
 package v1alpha1
 
+// EnforcementReportListerExpansion allows custom methods to be added to
+// EnforcementReportLister.
+type EnforcementReportListerExpansion interface{}
+
+// GatewayPolicyListerExpansion allows custom methods to be added to
+// GatewayPolicyLister.
+type GatewayPolicyListerExpansion interface{}
+
+// GatewayPolicyNamespaceListerExpansion allows custom methods to be added to
+// GatewayPolicyNamespaceLister.
+type GatewayPolicyNamespaceListerExpansion interface{}
+
+// IDSPolicyListerExpansion allows custom methods to be added to
+// IDSPolicyLister.
+type IDSPolicyListerExpansion interface{}
+
+// IDSPolicyNamespaceListerExpansion allows custom methods to be added to
+// IDSPolicyNamespaceLister.
+type IDSPolicyNamespaceListerExpansion interface{}
+
 // IPPoolListerExpansion allows custom methods to be added to
 // IPPoolLister.
 type IPPoolListerExpansion interface{}
@@ -13,6 +33,10 @@ type IPPoolListerExpansion interface{}
 // IPPoolNamespaceLister.
 type IPPoolNamespaceListerExpansion interface{}
 
+// NSXOperatorConfigListerExpansion allows custom methods to be added to
+// NSXOperatorConfigLister.
+type NSXOperatorConfigListerExpansion interface{}
+
 // NSXServiceAccountListerExpansion allows custom methods to be added to
 // NSXServiceAccountLister.
 type NSXServiceAccountListerExpansion interface{}
@@ -21,6 +45,26 @@ type NSXServiceAccountListerExpansion interface{}
 // NSXServiceAccountNamespaceLister.
 type NSXServiceAccountNamespaceListerExpansion interface{}
 
+// NamespaceNetworkStatusListerExpansion allows custom methods to be added to
+// NamespaceNetworkStatusLister.
+type NamespaceNetworkStatusListerExpansion interface{}
+
+// NamespaceNetworkStatusNamespaceListerExpansion allows custom methods to be added to
+// NamespaceNetworkStatusNamespaceLister.
+type NamespaceNetworkStatusNamespaceListerExpansion interface{}
+
+// PolicyProfileListerExpansion allows custom methods to be added to
+// PolicyProfileLister.
+type PolicyProfileListerExpansion interface{}
+
+// ProtectedPolicyListerExpansion allows custom methods to be added to
+// ProtectedPolicyLister.
+type ProtectedPolicyListerExpansion interface{}
+
+// SecurityExclusionListerExpansion allows custom methods to be added to
+// SecurityExclusionLister.
+type SecurityExclusionListerExpansion interface{}
+
 // SecurityPolicyListerExpansion allows custom methods to be added to
 // SecurityPolicyLister.
 type SecurityPolicyListerExpansion interface{}
@@ -29,6 +73,14 @@ type SecurityPolicyListerExpansion interface{}
 // SecurityPolicyNamespaceLister.
 type SecurityPolicyNamespaceListerExpansion interface{}
 
+// ServiceExposureListerExpansion allows custom methods to be added to
+// ServiceExposureLister.
+type ServiceExposureListerExpansion interface{}
+
+// ServiceExposureNamespaceListerExpansion allows custom methods to be added to
+// ServiceExposureNamespaceLister.
+type ServiceExposureNamespaceListerExpansion interface{}
+
 // StaticRouteListerExpansion allows custom methods to be added to
 // StaticRouteLister.
 type StaticRouteListerExpansion interface{}
@@ -45,6 +97,14 @@ type SubnetListerExpansion interface{}
 // SubnetNamespaceLister.
 type SubnetNamespaceListerExpansion interface{}
 
+// SubnetPolicyListerExpansion allows custom methods to be added to
+// SubnetPolicyLister.
+type SubnetPolicyListerExpansion interface{}
+
+// SubnetPolicyNamespaceListerExpansion allows custom methods to be added to
+// SubnetPolicyNamespaceLister.
+type SubnetPolicyNamespaceListerExpansion interface{}
+
 // SubnetPortListerExpansion allows custom methods to be added to
 // SubnetPortLister.
 type SubnetPortListerExpansion interface{}
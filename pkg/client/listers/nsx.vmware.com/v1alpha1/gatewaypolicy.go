@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// GatewayPolicyLister helps list GatewayPolicies.
+// All objects returned here must be treated as read-only.
+type GatewayPolicyLister interface {
+	// List lists all GatewayPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.GatewayPolicy, err error)
+	// GatewayPolicies returns an object that can list and get GatewayPolicies.
+	GatewayPolicies(namespace string) GatewayPolicyNamespaceLister
+	GatewayPolicyListerExpansion
+}
+
+// gatewayPolicyLister implements the GatewayPolicyLister interface.
+type gatewayPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewGatewayPolicyLister returns a new GatewayPolicyLister.
+func NewGatewayPolicyLister(indexer cache.Indexer) GatewayPolicyLister {
+	return &gatewayPolicyLister{indexer: indexer}
+}
+
+// List lists all GatewayPolicies in the indexer.
+func (s *gatewayPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.GatewayPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.GatewayPolicy))
+	})
+	return ret, err
+}
+
+// GatewayPolicies returns an object that can list and get GatewayPolicies.
+func (s *gatewayPolicyLister) GatewayPolicies(namespace string) GatewayPolicyNamespaceLister {
+	return gatewayPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// GatewayPolicyNamespaceLister helps list and get GatewayPolicies.
+// All objects returned here must be treated as read-only.
+type GatewayPolicyNamespaceLister interface {
+	// List lists all GatewayPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.GatewayPolicy, err error)
+	// Get retrieves the GatewayPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.GatewayPolicy, error)
+	GatewayPolicyNamespaceListerExpansion
+}
+
+// gatewayPolicyNamespaceLister implements the GatewayPolicyNamespaceLister
+// interface.
+type gatewayPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all GatewayPolicies in the indexer for a given namespace.
+func (s gatewayPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.GatewayPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.GatewayPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the GatewayPolicy from the indexer for a given namespace and name.
+func (s gatewayPolicyNamespaceLister) Get(name string) (*v1alpha1.GatewayPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("gatewaypolicy"), name)
+	}
+	return obj.(*v1alpha1.GatewayPolicy), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// IDSPolicyLister helps list IDSPolicies.
+// All objects returned here must be treated as read-only.
+type IDSPolicyLister interface {
+	// List lists all IDSPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.IDSPolicy, err error)
+	// IDSPolicies returns an object that can list and get IDSPolicies.
+	IDSPolicies(namespace string) IDSPolicyNamespaceLister
+	IDSPolicyListerExpansion
+}
+
+// iDSPolicyLister implements the IDSPolicyLister interface.
+type iDSPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewIDSPolicyLister returns a new IDSPolicyLister.
+func NewIDSPolicyLister(indexer cache.Indexer) IDSPolicyLister {
+	return &iDSPolicyLister{indexer: indexer}
+}
+
+// List lists all IDSPolicies in the indexer.
+func (s *iDSPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.IDSPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.IDSPolicy))
+	})
+	return ret, err
+}
+
+// IDSPolicies returns an object that can list and get IDSPolicies.
+func (s *iDSPolicyLister) IDSPolicies(namespace string) IDSPolicyNamespaceLister {
+	return iDSPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// IDSPolicyNamespaceLister helps list and get IDSPolicies.
+// All objects returned here must be treated as read-only.
+type IDSPolicyNamespaceLister interface {
+	// List lists all IDSPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.IDSPolicy, err error)
+	// Get retrieves the IDSPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.IDSPolicy, error)
+	IDSPolicyNamespaceListerExpansion
+}
+
+// iDSPolicyNamespaceLister implements the IDSPolicyNamespaceLister
+// interface.
+type iDSPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all IDSPolicies in the indexer for a given namespace.
+func (s iDSPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.IDSPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.IDSPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the IDSPolicy from the indexer for a given namespace and name.
+func (s iDSPolicyNamespaceLister) Get(name string) (*v1alpha1.IDSPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("idspolicy"), name)
+	}
+	return obj.(*v1alpha1.IDSPolicy), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceNetworkStatusLister helps list NamespaceNetworkStatuses.
+// All objects returned here must be treated as read-only.
+type NamespaceNetworkStatusLister interface {
+	// List lists all NamespaceNetworkStatuses in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NamespaceNetworkStatus, err error)
+	// NamespaceNetworkStatuses returns an object that can list and get NamespaceNetworkStatuses.
+	NamespaceNetworkStatuses(namespace string) NamespaceNetworkStatusNamespaceLister
+	NamespaceNetworkStatusListerExpansion
+}
+
+// namespaceNetworkStatusLister implements the NamespaceNetworkStatusLister interface.
+type namespaceNetworkStatusLister struct {
+	indexer cache.Indexer
+}
+
+// NewNamespaceNetworkStatusLister returns a new NamespaceNetworkStatusLister.
+func NewNamespaceNetworkStatusLister(indexer cache.Indexer) NamespaceNetworkStatusLister {
+	return &namespaceNetworkStatusLister{indexer: indexer}
+}
+
+// List lists all NamespaceNetworkStatuses in the indexer.
+func (s *namespaceNetworkStatusLister) List(selector labels.Selector) (ret []*v1alpha1.NamespaceNetworkStatus, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NamespaceNetworkStatus))
+	})
+	return ret, err
+}
+
+// NamespaceNetworkStatuses returns an object that can list and get NamespaceNetworkStatuses.
+func (s *namespaceNetworkStatusLister) NamespaceNetworkStatuses(namespace string) NamespaceNetworkStatusNamespaceLister {
+	return namespaceNetworkStatusNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NamespaceNetworkStatusNamespaceLister helps list and get NamespaceNetworkStatuses.
+// All objects returned here must be treated as read-only.
+type NamespaceNetworkStatusNamespaceLister interface {
+	// List lists all NamespaceNetworkStatuses in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NamespaceNetworkStatus, err error)
+	// Get retrieves the NamespaceNetworkStatus from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.NamespaceNetworkStatus, error)
+	NamespaceNetworkStatusNamespaceListerExpansion
+}
+
+// namespaceNetworkStatusNamespaceLister implements the NamespaceNetworkStatusNamespaceLister
+// interface.
+type namespaceNetworkStatusNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NamespaceNetworkStatuses in the indexer for a given namespace.
+func (s namespaceNetworkStatusNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.NamespaceNetworkStatus, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NamespaceNetworkStatus))
+	})
+	return ret, err
+}
+
+// Get retrieves the NamespaceNetworkStatus from the indexer for a given namespace and name.
+func (s namespaceNetworkStatusNamespaceLister) Get(name string) (*v1alpha1.NamespaceNetworkStatus, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("namespacenetworkstatus"), name)
+	}
+	return obj.(*v1alpha1.NamespaceNetworkStatus), nil
+}
@@ -0,0 +1,55 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NSXOperatorConfigLister helps list NSXOperatorConfigs.
+// All objects returned here must be treated as read-only.
+type NSXOperatorConfigLister interface {
+	// List lists all NSXOperatorConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NSXOperatorConfig, err error)
+	// Get retrieves the NSXOperatorConfig from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.NSXOperatorConfig, error)
+	NSXOperatorConfigListerExpansion
+}
+
+// nSXOperatorConfigLister implements the NSXOperatorConfigLister interface.
+type nSXOperatorConfigLister struct {
+	indexer cache.Indexer
+}
+
+// NewNSXOperatorConfigLister returns a new NSXOperatorConfigLister.
+func NewNSXOperatorConfigLister(indexer cache.Indexer) NSXOperatorConfigLister {
+	return &nSXOperatorConfigLister{indexer: indexer}
+}
+
+// List lists all NSXOperatorConfigs in the indexer.
+func (s *nSXOperatorConfigLister) List(selector labels.Selector) (ret []*v1alpha1.NSXOperatorConfig, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NSXOperatorConfig))
+	})
+	return ret, err
+}
+
+// Get retrieves the NSXOperatorConfig from the index for a given name.
+func (s *nSXOperatorConfigLister) Get(name string) (*v1alpha1.NSXOperatorConfig, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("nsxoperatorconfig"), name)
+	}
+	return obj.(*v1alpha1.NSXOperatorConfig), nil
+}
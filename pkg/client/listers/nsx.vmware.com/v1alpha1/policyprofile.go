@@ -0,0 +1,55 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PolicyProfileLister helps list PolicyProfiles.
+// All objects returned here must be treated as read-only.
+type PolicyProfileLister interface {
+	// List lists all PolicyProfiles in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.PolicyProfile, err error)
+	// Get retrieves the PolicyProfile from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.PolicyProfile, error)
+	PolicyProfileListerExpansion
+}
+
+// policyProfileLister implements the PolicyProfileLister interface.
+type policyProfileLister struct {
+	indexer cache.Indexer
+}
+
+// NewPolicyProfileLister returns a new PolicyProfileLister.
+func NewPolicyProfileLister(indexer cache.Indexer) PolicyProfileLister {
+	return &policyProfileLister{indexer: indexer}
+}
+
+// List lists all PolicyProfiles in the indexer.
+func (s *policyProfileLister) List(selector labels.Selector) (ret []*v1alpha1.PolicyProfile, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.PolicyProfile))
+	})
+	return ret, err
+}
+
+// Get retrieves the PolicyProfile from the index for a given name.
+func (s *policyProfileLister) Get(name string) (*v1alpha1.PolicyProfile, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("policyprofile"), name)
+	}
+	return obj.(*v1alpha1.PolicyProfile), nil
+}
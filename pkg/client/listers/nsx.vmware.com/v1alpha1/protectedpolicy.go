@@ -0,0 +1,55 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ProtectedPolicyLister helps list ProtectedPolicies.
+// All objects returned here must be treated as read-only.
+type ProtectedPolicyLister interface {
+	// List lists all ProtectedPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ProtectedPolicy, err error)
+	// Get retrieves the ProtectedPolicy from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.ProtectedPolicy, error)
+	ProtectedPolicyListerExpansion
+}
+
+// protectedPolicyLister implements the ProtectedPolicyLister interface.
+type protectedPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewProtectedPolicyLister returns a new ProtectedPolicyLister.
+func NewProtectedPolicyLister(indexer cache.Indexer) ProtectedPolicyLister {
+	return &protectedPolicyLister{indexer: indexer}
+}
+
+// List lists all ProtectedPolicies in the indexer.
+func (s *protectedPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.ProtectedPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ProtectedPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the ProtectedPolicy from the index for a given name.
+func (s *protectedPolicyLister) Get(name string) (*v1alpha1.ProtectedPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("protectedpolicy"), name)
+	}
+	return obj.(*v1alpha1.ProtectedPolicy), nil
+}
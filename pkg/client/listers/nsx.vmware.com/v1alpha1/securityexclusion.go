@@ -0,0 +1,55 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecurityExclusionLister helps list SecurityExclusions.
+// All objects returned here must be treated as read-only.
+type SecurityExclusionLister interface {
+	// List lists all SecurityExclusions in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.SecurityExclusion, err error)
+	// Get retrieves the SecurityExclusion from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.SecurityExclusion, error)
+	SecurityExclusionListerExpansion
+}
+
+// securityExclusionLister implements the SecurityExclusionLister interface.
+type securityExclusionLister struct {
+	indexer cache.Indexer
+}
+
+// NewSecurityExclusionLister returns a new SecurityExclusionLister.
+func NewSecurityExclusionLister(indexer cache.Indexer) SecurityExclusionLister {
+	return &securityExclusionLister{indexer: indexer}
+}
+
+// List lists all SecurityExclusions in the indexer.
+func (s *securityExclusionLister) List(selector labels.Selector) (ret []*v1alpha1.SecurityExclusion, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.SecurityExclusion))
+	})
+	return ret, err
+}
+
+// Get retrieves the SecurityExclusion from the index for a given name.
+func (s *securityExclusionLister) Get(name string) (*v1alpha1.SecurityExclusion, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("securityexclusion"), name)
+	}
+	return obj.(*v1alpha1.SecurityExclusion), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ServiceExposureLister helps list ServiceExposures.
+// All objects returned here must be treated as read-only.
+type ServiceExposureLister interface {
+	// List lists all ServiceExposures in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceExposure, err error)
+	// ServiceExposures returns an object that can list and get ServiceExposures.
+	ServiceExposures(namespace string) ServiceExposureNamespaceLister
+	ServiceExposureListerExpansion
+}
+
+// serviceExposureLister implements the ServiceExposureLister interface.
+type serviceExposureLister struct {
+	indexer cache.Indexer
+}
+
+// NewServiceExposureLister returns a new ServiceExposureLister.
+func NewServiceExposureLister(indexer cache.Indexer) ServiceExposureLister {
+	return &serviceExposureLister{indexer: indexer}
+}
+
+// List lists all ServiceExposures in the indexer.
+func (s *serviceExposureLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceExposure, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceExposure))
+	})
+	return ret, err
+}
+
+// ServiceExposures returns an object that can list and get ServiceExposures.
+func (s *serviceExposureLister) ServiceExposures(namespace string) ServiceExposureNamespaceLister {
+	return serviceExposureNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ServiceExposureNamespaceLister helps list and get ServiceExposures.
+// All objects returned here must be treated as read-only.
+type ServiceExposureNamespaceLister interface {
+	// List lists all ServiceExposures in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceExposure, err error)
+	// Get retrieves the ServiceExposure from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.ServiceExposure, error)
+	ServiceExposureNamespaceListerExpansion
+}
+
+// serviceExposureNamespaceLister implements the ServiceExposureNamespaceLister
+// interface.
+type serviceExposureNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ServiceExposures in the indexer for a given namespace.
+func (s serviceExposureNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceExposure, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceExposure))
+	})
+	return ret, err
+}
+
+// Get retrieves the ServiceExposure from the indexer for a given namespace and name.
+func (s serviceExposureNamespaceLister) Get(name string) (*v1alpha1.ServiceExposure, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("serviceexposure"), name)
+	}
+	return obj.(*v1alpha1.ServiceExposure), nil
+}
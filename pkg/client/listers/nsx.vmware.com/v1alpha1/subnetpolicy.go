@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SubnetPolicyLister helps list SubnetPolicies.
+// All objects returned here must be treated as read-only.
+type SubnetPolicyLister interface {
+	// List lists all SubnetPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.SubnetPolicy, err error)
+	// SubnetPolicies returns an object that can list and get SubnetPolicies.
+	SubnetPolicies(namespace string) SubnetPolicyNamespaceLister
+	SubnetPolicyListerExpansion
+}
+
+// subnetPolicyLister implements the SubnetPolicyLister interface.
+type subnetPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewSubnetPolicyLister returns a new SubnetPolicyLister.
+func NewSubnetPolicyLister(indexer cache.Indexer) SubnetPolicyLister {
+	return &subnetPolicyLister{indexer: indexer}
+}
+
+// List lists all SubnetPolicies in the indexer.
+func (s *subnetPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.SubnetPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.SubnetPolicy))
+	})
+	return ret, err
+}
+
+// SubnetPolicies returns an object that can list and get SubnetPolicies.
+func (s *subnetPolicyLister) SubnetPolicies(namespace string) SubnetPolicyNamespaceLister {
+	return subnetPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SubnetPolicyNamespaceLister helps list and get SubnetPolicies.
+// All objects returned here must be treated as read-only.
+type SubnetPolicyNamespaceLister interface {
+	// List lists all SubnetPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.SubnetPolicy, err error)
+	// Get retrieves the SubnetPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.SubnetPolicy, error)
+	SubnetPolicyNamespaceListerExpansion
+}
+
+// subnetPolicyNamespaceLister implements the SubnetPolicyNamespaceLister
+// interface.
+type subnetPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SubnetPolicies in the indexer for a given namespace.
+func (s subnetPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.SubnetPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.SubnetPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the SubnetPolicy from the indexer for a given namespace and name.
+func (s subnetPolicyNamespaceLister) Get(name string) (*v1alpha1.SubnetPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("subnetpolicy"), name)
+	}
+	return obj.(*v1alpha1.SubnetPolicy), nil
+}
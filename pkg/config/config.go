@@ -9,13 +9,19 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	ini "gopkg.in/ini.v1"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/auth"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/auth/jwt"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
 )
 
 // TODO replace to yaml
@@ -28,16 +34,28 @@ const (
 	LicenseIntervalForDFW  = 1800
 	defaultWebhookPort     = 9981
 	defaultWebhookCertPath = "/tmp/k8s-webhook-server/serving-certs"
+	// DefaultSite, DefaultEnforcementPoint and DefaultProjectDomain are the IDs NSX creates by default
+	DefaultSite             = "default"
+	DefaultEnforcementPoint = "default"
+	DefaultProjectDomain    = "default"
 )
 
 var (
 	LogLevel               int
 	ProbeAddr, MetricsAddr string
+	AdminAddr              string
 	WebhookServerPort      int
 	WebhookCertDir         string
-	configFilePath         = ""
-	configLog              *zap.SugaredLogger
-	tokenProvider          auth.TokenProvider
+	// LoadGen* configure the load generation mode, which is enabled if any CR is to be generated.
+	LoadGenNamespaces       int
+	LoadGenSecurityPolicies int
+	LoadGenSubnets          int
+	LoadGenTimeout          time.Duration
+	LoadGenReport           string
+	LoadGenKeep             bool
+	configFilePath          = ""
+	configLog               *zap.SugaredLogger
+	tokenProvider           auth.TokenProvider
 )
 
 // TODO delete unnecessary config
@@ -48,6 +66,8 @@ type NSXOperatorConfig struct {
 	*K8sConfig
 	*VCConfig
 	*HAConfig
+	// NsxSites are the additional NSX endpoints keyed by site name, loaded from [nsx_v3.<site>] sections
+	NsxSites    map[string]*NsxConfig `ini:"-"`
 	configCache configCache
 }
 
@@ -63,6 +83,25 @@ func (operatorConfig *NSXOperatorConfig) HAEnabled() bool {
 	return false
 }
 
+// OwnsShard returns whether the replica runs the controllers of the shard.
+func (operatorConfig *NSXOperatorConfig) OwnsShard(shard string) bool {
+	return operatorConfig.HAConfig == nil || operatorConfig.Shard == "" || operatorConfig.Shard == shard
+}
+
+// LeaderElectionID returns the ID of the leader election of the replica, the replicas of a shard elect their
+// own leader.
+func (operatorConfig *NSXOperatorConfig) LeaderElectionID() string {
+	if operatorConfig.HAConfig == nil || operatorConfig.Shard == "" {
+		return leaderElectionID
+	}
+	return ShardLeaderElectionID(operatorConfig.Shard)
+}
+
+// ShardLeaderElectionID returns the ID of the leader election of the replicas of the shard.
+func ShardLeaderElectionID(shard string) string {
+	return leaderElectionID + "-" + shard
+}
+
 func (operatorConfig *NSXOperatorConfig) GetCACert() []byte {
 	ca := operatorConfig.configCache.nsxCA
 	if ca == nil {
@@ -93,6 +132,10 @@ type DefaultConfig struct {
 type CoeConfig struct {
 	Cluster          string `ini:"cluster"`
 	EnableVPCNetwork bool   `ini:"enable_vpc_network"`
+	// InstanceID identifies the nsx-operator instance owning the NSX objects, the ownership of the NSX objects isn't locked if it's empty
+	InstanceID string `ini:"instance_id"`
+	// OwnershipLease is the seconds the ownership of an NSX object is held after it's written by the owner instance
+	OwnershipLease int `ini:"ownership_lease"`
 }
 
 type NsxConfig struct {
@@ -113,6 +156,38 @@ type NsxConfig struct {
 	EnvoyHost                 string   `ini:"envoy_host"`
 	EnvoyPort                 int      `ini:"envoy_port"`
 	LicenseValidationInterval int      `ini:"license_validation_interval"`
+	// Seconds between the pulls of the NSX Intelligence recommendations, 0 disables the ingestion
+	RecommendationInterval int `ini:"recommendation_interval"`
+	// Shares of the NSX API throughput of the subsystems, e.g. security:4,subnet:2,vpc:1,gc:1,other:1
+	APIRateShares []string `ini:"api_rate_shares"`
+	// Seconds the NSX auth session is used before it's created again, 1500 by default, a negative value disables the
+	// proactive renewal
+	SessionRenewInterval int `ini:"session_renew_interval"`
+	// Domain the groups and SecurityPolicies are created in without VPC, the cluster name by default. With VPC,
+	// it's the domain of the NSX Projects, default by default, unless the VPCNetworkConfiguration overrides it
+	Domain string `ini:"domain"`
+	// Stage the changes of the SecurityPolicies without VPC in an NSX DFW draft instead of patching them directly, the
+	// draft is published every dfw_draft_publish_interval seconds or when it's approved
+	DFWDraft bool `ini:"dfw_draft"`
+	// Seconds between the publications of the DFW draft, 0 publishes it only when it's approved
+	DFWDraftPublishInterval int `ini:"dfw_draft_publish_interval"`
+	// Milliseconds the SecurityPolicies without VPC realized at the same time are gathered to be patched in one
+	// hierarchical call, 0 patches each SecurityPolicy in its own call
+	PatchBatchWindow int `ini:"patch_batch_window"`
+	// Download the OpenAPI spec of NSX Manager at startup and validate the hierarchical payloads of the SecurityPolicies
+	// against it before they are patched
+	ValidatePayloads bool `ini:"validate_payloads"`
+	// Seconds between the polls of the usage of the NSX capacity dashboard, 0 disables the capacity check
+	CapacityCheckInterval int `ini:"capacity_check_interval"`
+	// Percentage of the max supported count of the NSX groups, rules and SecurityPolicies kept free, the SecurityPolicies
+	// which would consume it are refused. 10 by default
+	CapacitySafetyMargin int `ini:"capacity_safety_margin"`
+	// Enforcement backends of the SecurityPolicies of the namespaces, e.g. ns-a:global-manager. The namespaces not
+	// listed are realized on NSX /infra, or on NSX Project/VPC in VPC mode
+	EnforcementBackends []string `ini:"enforcement_backends"`
+	// Share the rule peer groups with the same criteria across the SecurityPolicies without VPC, instead of one group
+	// per rule peer
+	SharedPeerGroups bool `ini:"shared_peer_groups"`
 }
 
 type K8sConfig struct {
@@ -122,8 +197,73 @@ type K8sConfig struct {
 	EnableRestore      bool   `ini:"enable_restore"`
 	EnablePromMetrics  bool   `ini:"enable_prometheus_metrics"`
 	KubeConfigFile     string `ini:"kubeconfig"`
+	// Seconds between the snapshots of the NSX resources realized for the SecurityPolicies, 0 disables the snapshot
+	SnapshotInterval int `ini:"snapshot_interval"`
+	// Secret in the nsx-operator namespace keeping the archives of the SecurityPolicies before they are deleted in bulk
+	BackupSecret string `ini:"backup_secret"`
+	// Directory, e.g. a mounted PVC, the archives of the SecurityPolicies are written to before they are deleted in bulk
+	BackupDir string `ini:"backup_dir"`
+	// Deletions of the NSX resources of a type in the window above which the bulk deletion is paused until confirmed
+	MassDeletionMaxCount int `ini:"mass_deletion_max_count"`
+	// Percentage of the NSX resources of a type deleted in the window above which the bulk deletion is paused until confirmed
+	MassDeletionMaxPercent int `ini:"mass_deletion_max_percent"`
+	// Seconds of the window the deletions are counted in, 600 by default
+	MassDeletionWindow int `ini:"mass_deletion_window"`
+	// Seconds between the scans of NSX for the objects of the SecurityPolicies missing in the stores, they're deleted by
+	// the GC if their CR is gone. 1800 by default, a negative value disables the scan
+	OrphanScanInterval int `ini:"orphan_scan_interval"`
+	// Seconds a CR can be stuck deleting due to NSX errors before it's escalated, 0 disables the escalation
+	StuckDeletionTimeout int `ini:"stuck_deletion_timeout"`
+	// Remove the finalizer of the CR stuck deleting once it's escalated, the NSX resources left are recorded as orphans
+	ForceFinalizerRemoval bool `ini:"force_finalizer_removal"`
+	// Seconds between the checks of the NSX resources of the SecurityPolicies edited in NSX Manager, 0 disables the check
+	DriftCheckInterval int `ini:"drift_check_interval"`
+	// Patch the NSX resources of the SecurityPolicies edited in NSX Manager again, the drift is only reported otherwise
+	DriftRemediation bool `ini:"drift_remediation"`
+	// Seconds between the collections of the hit statistics of the rules of the SecurityPolicies from NSX, 0 disables
+	// the collection
+	RuleStatsInterval int `ini:"rule_stats_interval"`
+	// Report the collected hit statistics of the rules in the SecurityPolicy status besides the metrics
+	RuleStatsStatus bool `ini:"rule_stats_status"`
+	// Seconds between the reads of the effective members of the NSX groups of the SecurityPolicies from NSX, they're
+	// summarized in the SecurityPolicy status. 0 disables the read
+	GroupMembersInterval int `ini:"group_members_interval"`
+	// Prefixes of the SecurityPolicy labels copied to the NSX tags of its policy, rules and groups, e.g.
+	// compliance.example.com/, no label is copied by default
+	LabelTagPrefixes []string `ini:"label_tag_prefixes"`
+	// Spread the NSX sequence numbers of the SecurityPolicies with the same priority by their namespace and name, the
+	// sequence number is the priority times 100 plus the rank of the SecurityPolicy then
+	PrioritySpreading bool `ini:"priority_spreading"`
+	// CIDRs of the Pods, Nodes and Service cluster IPs, the peers of SecurityPolicy rules can refer to them by network
+	ClusterCIDRs []string `ini:"cluster_cidrs"`
+	NodeCIDRs    []string `ini:"node_cidrs"`
+	ServiceCIDRs []string `ini:"service_cidrs"`
+	// Namespaced name of the cluster DNS Service the SecurityPolicies with allowDNS allow, kube-system/kube-dns by default
+	DNSService string `ini:"dns_service"`
+	// Seconds without reconcile progress before the liveness check reports a stalled queue
+	ReconcileStallTimeout int `ini:"reconcile_stall_timeout"`
+	// Milliseconds a resource must be quiet before its successive updates are reconciled, 0 disables the coalescing
+	ReconcileCoalesceWindow int `ini:"reconcile_coalesce_window"`
+	// Seconds after the creation of a namespace its resources are reconciled in tiers: the VPC and Subnets first, then
+	// the SubnetPorts and Pods, the policies and the ServiceExposures last. 0 disables the tiers
+	TieredReconcileWindow int `ini:"tiered_reconcile_window"`
+	// Seconds the Pod events are batched into a single update of the IP address groups of a policy, 0 disables the batching
+	GroupUpdateBatchWindow int `ini:"group_update_batch_window"`
+	// Minimum seconds between the updates of the IP address groups of a policy triggered by Pod events, 0 disables the limit
+	GroupUpdateMinInterval int `ini:"group_update_min_interval"`
+	// Translate the NetworkPolicies to NSX DFW rules without VPC, they're translated with VPC anyway. It must not
+	// be enabled if the NetworkPolicies are enforced by NCP.
+	EnableNetworkPolicy bool `ini:"enable_network_policy"`
 	// Controlled by FSS
 	EnableAntreaNSXInterworking bool `ini:"enable_antrea_nsx_interworking"`
+	// Convert the Antrea-native policies to SecurityPolicies, it requires the Antrea CRDs are installed
+	EnableAntreaPolicyConversion bool `ini:"enable_antrea_policy_conversion"`
+	// Realize the AdminNetworkPolicies and BaselineAdminNetworkPolicies without VPC, it requires the CRDs of the
+	// upstream AdminNetworkPolicy API are installed
+	EnableAdminNetworkPolicy bool `ini:"enable_admin_network_policy"`
+	// DFW category of the AdminNetworkPolicies, Environment or Infrastructure, the Pass action is only supported
+	// in Environment
+	AdminNetworkPolicyCategory string `ini:"admin_network_policy_category"`
 }
 
 type VCConfig struct {
@@ -137,8 +277,26 @@ type VCConfig struct {
 
 type HAConfig struct {
 	EnableHA *bool `ini:"enable"`
+	// Shard is the group of the controllers and their NSX stores run by the replica, policy or network. The replicas
+	// of each shard elect their own leader, so the memory of the stores is split across the replicas. All the
+	// controllers are run if it's empty
+	Shard string `ini:"shard"`
 }
 
+const (
+	// ShardPolicy runs the controllers realizing the DFW policies, i.e. of the SecurityPolicies, NetworkPolicies,
+	// AdminNetworkPolicies, ServiceExposures and the namespace default deny.
+	ShardPolicy = "policy"
+	// ShardNetwork runs the controllers of the VPCs, Subnets, SubnetSets, SubnetPorts, Pods, IPPools and the other
+	// network resources.
+	ShardNetwork = "network"
+
+	leaderElectionID = "nsx-operator"
+)
+
+// Shards are all the shards the controllers are split into.
+var Shards = []string{ShardPolicy, ShardNetwork}
+
 type Validate interface {
 	validate() error
 }
@@ -151,9 +309,16 @@ func AddFlags() {
 	flag.StringVar(&configFilePath, "nsxconfig", nsxOperatorDefaultConf, "NSX Operator configuration file path")
 	flag.StringVar(&ProbeAddr, "health-probe-bind-address", ":8384", "The address the probe endpoint binds to.")
 	flag.StringVar(&MetricsAddr, "metrics-bind-address", ":8093", "The address the metrics endpoint binds to.")
+	flag.StringVar(&AdminAddr, "admin-bind-address", "", "The address the admin API binds to, the admin API is disabled if it's empty.")
 	flag.IntVar(&LogLevel, "log-level", 0, "Use zap-core log system.")
 	flag.IntVar(&WebhookServerPort, "webhook-server-port", defaultWebhookPort, "Port number to expose the controller webhook server")
 	flag.StringVar(&WebhookCertDir, "webhook-cert-dir", defaultWebhookCertPath, "Directory for certificate for webhook server")
+	flag.IntVar(&LoadGenNamespaces, "loadgen-namespaces", 10, "Number of Namespaces the generated CRs are spread across in the load generation mode")
+	flag.IntVar(&LoadGenSecurityPolicies, "loadgen-securitypolicies", 0, "Number of SecurityPolicies to generate, the load generation mode is enabled if it's positive")
+	flag.IntVar(&LoadGenSubnets, "loadgen-subnets", 0, "Number of Subnets to generate with VPC, the load generation mode is enabled if it's positive")
+	flag.DurationVar(&LoadGenTimeout, "loadgen-timeout", 30*time.Minute, "Time to wait for the generated CRs to be realized")
+	flag.StringVar(&LoadGenReport, "loadgen-report", "", "File the load generation report is written to besides the report ConfigMap")
+	flag.BoolVar(&LoadGenKeep, "loadgen-keep", false, "Keep the generated CRs after the load generation instead of deleting them")
 	flag.Parse()
 }
 
@@ -198,6 +363,9 @@ func LoadConfigFromFile() (*NSXOperatorConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := nsxOperatorConfig.loadNsxSites(cfg); err != nil {
+		return nil, err
+	}
 
 	if err := nsxOperatorConfig.validate(); err != nil {
 		return nil, err
@@ -223,6 +391,7 @@ func NewNSXOpertorConfig() *NSXOperatorConfig {
 		&K8sConfig{},
 		&VCConfig{},
 		&HAConfig{},
+		map[string]*NsxConfig{},
 		configCache{},
 	}
 	return defaultNSXOperatorConfig
@@ -235,6 +404,15 @@ func (operatorConfig *NSXOperatorConfig) validate() error {
 	if err := operatorConfig.NsxConfig.validate(operatorConfig.CoeConfig.EnableVPCNetwork); err != nil {
 		return err
 	}
+	if err := operatorConfig.validateNsxSites(); err != nil {
+		return err
+	}
+	if err := operatorConfig.K8sConfig.validate(); err != nil {
+		return err
+	}
+	if err := operatorConfig.HAConfig.validate(); err != nil {
+		return err
+	}
 	// TODO, verify if user&pwd, cert, jwt has any of them provided
 	return nil
 }
@@ -376,18 +554,168 @@ func (nsxConfig *NsxConfig) validate(enableVPC bool) error {
 	if err := nsxConfig.validateCert(); err != nil {
 		return err
 	}
+	if _, err := nsxConfig.GetAPIRateShares(); err != nil {
+		configLog.Error(err, "validate NsxConfig failed", "APIRateShares", nsxConfig.APIRateShares)
+		return err
+	}
+	if _, err := nsxConfig.GetEnforcementBackends(); err != nil {
+		configLog.Error(err, "validate NsxConfig failed", "EnforcementBackends", nsxConfig.EnforcementBackends)
+		return err
+	}
+	if err := ValidateNSXID(nsxConfig.EnforcementPoint); err != nil {
+		configLog.Error(err, "validate NsxConfig failed", "EnforcementPoint", nsxConfig.EnforcementPoint)
+		return err
+	}
+	if err := ValidateNSXID(nsxConfig.Domain); err != nil {
+		configLog.Error(err, "validate NsxConfig failed", "Domain", nsxConfig.Domain)
+		return err
+	}
 	return nil
 }
 
+// ValidateNSXID checks the ID of an NSX policy object, e.g. a domain or an enforcement point, can be
+// used in the policy paths. An empty ID is valid, the default one is used.
+func ValidateNSXID(id string) error {
+	if strings.ContainsAny(id, "/ \t") {
+		return fmt.Errorf("invalid NSX ID %q, '/' and whitespaces are not allowed", id)
+	}
+	return nil
+}
+
+// GetEnforcementPoint returns the enforcement point of the default site the NSX objects are realized on.
+func (nsxConfig *NsxConfig) GetEnforcementPoint() string {
+	if nsxConfig == nil || nsxConfig.EnforcementPoint == "" {
+		return DefaultEnforcementPoint
+	}
+	return nsxConfig.EnforcementPoint
+}
+
+// GetDomain returns the domain the groups and SecurityPolicies are created in without VPC.
+func (operatorConfig *NSXOperatorConfig) GetDomain() string {
+	if operatorConfig.NsxConfig == nil || operatorConfig.NsxConfig.Domain == "" {
+		return operatorConfig.CoeConfig.Cluster
+	}
+	return operatorConfig.NsxConfig.Domain
+}
+
+// GetProjectDomain returns the domain of the NSX Projects the shared groups are created in with VPC.
+func (operatorConfig *NSXOperatorConfig) GetProjectDomain() string {
+	if operatorConfig.NsxConfig == nil || operatorConfig.NsxConfig.Domain == "" {
+		return DefaultProjectDomain
+	}
+	return operatorConfig.NsxConfig.Domain
+}
+
+// GetAPIRateShares parses the shares of the NSX API throughput keyed by subsystem, it returns nil
+// if the shares are not configured.
+func (nsxConfig *NsxConfig) GetAPIRateShares() (map[string]int, error) {
+	items := removeEmptyItem(nsxConfig.APIRateShares)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	shares := map[string]int{}
+	for _, item := range items {
+		subsystem, value, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found || !slices.Contains(ratelimiter.Subsystems, subsystem) {
+			return nil, fmt.Errorf("invalid API rate share %q, the subsystem must be one of %v", item, ratelimiter.Subsystems)
+		}
+		share, err := strconv.Atoi(value)
+		if err != nil || share <= 0 {
+			return nil, fmt.Errorf("invalid API rate share %q, the share must be a positive integer", item)
+		}
+		shares[subsystem] = share
+	}
+	return shares, nil
+}
+
+// GetEnforcementBackends parses the enforcement backends keyed by namespace, it returns nil if the backends
+// are not configured.
+func (nsxConfig *NsxConfig) GetEnforcementBackends() (map[string]string, error) {
+	items := removeEmptyItem(nsxConfig.EnforcementBackends)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	backends := map[string]string{}
+	for _, item := range items {
+		namespace, backend, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found || namespace == "" || backend == "" {
+			return nil, fmt.Errorf("invalid enforcement backend %q, it must be <namespace>:<backend>", item)
+		}
+		backends[namespace] = backend
+	}
+	return backends, nil
+}
+
 func (coeConfig *CoeConfig) validate() error {
 	if len(coeConfig.Cluster) == 0 {
 		err := errors.New("invalid field " + "Cluster")
 		configLog.Error(err, "validate coeConfig failed")
 		return err
 	}
+	if strings.Contains(coeConfig.InstanceID, "|") {
+		err := errors.New("invalid field InstanceID, '|' is not allowed")
+		configLog.Error(err, "validate coeConfig failed")
+		return err
+	}
+	return nil
+}
+
+func (haConfig *HAConfig) validate() error {
+	if haConfig.Shard != "" && !slices.Contains(Shards, haConfig.Shard) {
+		err := fmt.Errorf("invalid shard %s, it must be one of %s", haConfig.Shard, strings.Join(Shards, ", "))
+		configLog.Error(err, "validate haConfig failed")
+		return err
+	}
 	return nil
 }
 
+func (k8sConfig *K8sConfig) validate() error {
+	k8sConfig.ClusterCIDRs = removeEmptyItem(k8sConfig.ClusterCIDRs)
+	k8sConfig.NodeCIDRs = removeEmptyItem(k8sConfig.NodeCIDRs)
+	k8sConfig.ServiceCIDRs = removeEmptyItem(k8sConfig.ServiceCIDRs)
+	for _, cidrs := range [][]string{k8sConfig.ClusterCIDRs, k8sConfig.NodeCIDRs, k8sConfig.ServiceCIDRs} {
+		for _, cidr := range cidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				err = fmt.Errorf("invalid CIDR %s: %w", cidr, err)
+				configLog.Error(err, "validate k8sConfig failed")
+				return err
+			}
+		}
+	}
+	if k8sConfig.DNSService != "" {
+		if namespace, name, ok := strings.Cut(k8sConfig.DNSService, "/"); !ok || namespace == "" || name == "" {
+			err := fmt.Errorf("invalid DNS service %s, it must be <namespace>/<name>", k8sConfig.DNSService)
+			configLog.Error(err, "validate k8sConfig failed")
+			return err
+		}
+	}
+	if k8sConfig.AdminNetworkPolicyCategory != "" && !strings.EqualFold(k8sConfig.AdminNetworkPolicyCategory, "Environment") &&
+		!strings.EqualFold(k8sConfig.AdminNetworkPolicyCategory, "Infrastructure") {
+		err := fmt.Errorf("invalid admin network policy category %s, it must be Environment or Infrastructure", k8sConfig.AdminNetworkPolicyCategory)
+		configLog.Error(err, "validate k8sConfig failed")
+		return err
+	}
+	return nil
+}
+
+// GetDNSService returns the namespace and name of the cluster DNS Service, kube-system/kube-dns if it's not set.
+func (k8sConfig *K8sConfig) GetDNSService() (string, string) {
+	if k8sConfig != nil {
+		if namespace, name, ok := strings.Cut(k8sConfig.DNSService, "/"); ok {
+			return namespace, name
+		}
+	}
+	return "kube-system", "kube-dns"
+}
+
+// GetOwnershipLease returns the lease of the ownership of the NSX objects, 1 hour if it's not set.
+func (coeConfig *CoeConfig) GetOwnershipLease() time.Duration {
+	if coeConfig.OwnershipLease > 0 {
+		return time.Duration(coeConfig.OwnershipLease) * time.Second
+	}
+	return time.Hour
+}
+
 func (nsxConfig *NsxConfig) ValidateConfigFromCmd() error {
 	return nsxConfig.validate(true)
 }
@@ -56,6 +56,31 @@ func TestConfig_CoeConfig(t *testing.T) {
 
 }
 
+func TestConfig_K8sConfig(t *testing.T) {
+	k8sConfig := &K8sConfig{ClusterCIDRs: []string{"10.244.0.0/16", ""}, ServiceCIDRs: []string{"10.96.0.0/12"}}
+	assert.NoError(t, k8sConfig.validate())
+	assert.Equal(t, []string{"10.244.0.0/16"}, k8sConfig.ClusterCIDRs)
+
+	k8sConfig.NodeCIDRs = []string{"192.168.0.1"}
+	assert.EqualError(t, k8sConfig.validate(), "invalid CIDR 192.168.0.1: invalid CIDR address: 192.168.0.1")
+
+	k8sConfig.NodeCIDRs = nil
+	namespace, name := k8sConfig.GetDNSService()
+	assert.Equal(t, "kube-system/kube-dns", namespace+"/"+name)
+	k8sConfig.DNSService = "dns/coredns"
+	assert.NoError(t, k8sConfig.validate())
+	namespace, name = k8sConfig.GetDNSService()
+	assert.Equal(t, "dns/coredns", namespace+"/"+name)
+	k8sConfig.DNSService = "coredns"
+	assert.EqualError(t, k8sConfig.validate(), "invalid DNS service coredns, it must be <namespace>/<name>")
+
+	k8sConfig.DNSService = ""
+	k8sConfig.AdminNetworkPolicyCategory = "infrastructure"
+	assert.NoError(t, k8sConfig.validate())
+	k8sConfig.AdminNetworkPolicyCategory = "Application"
+	assert.EqualError(t, k8sConfig.validate(), "invalid admin network policy category Application, it must be Environment or Infrastructure")
+}
+
 func TestConfig_NsxConfig(t *testing.T) {
 	nsxConfig := &NsxConfig{}
 	expect := errors.New("invalid field " + "NsxApiManagers")
@@ -122,6 +147,23 @@ func TestConfig_GetHA(t *testing.T) {
 	assert.Equal(t, cf.HAEnabled(), true)
 }
 
+func TestConfig_Shard(t *testing.T) {
+	cf := &NSXOperatorConfig{HAConfig: &HAConfig{}}
+	assert.NoError(t, cf.HAConfig.validate())
+	assert.True(t, cf.OwnsShard(ShardPolicy))
+	assert.True(t, cf.OwnsShard(ShardNetwork))
+	assert.Equal(t, "nsx-operator", cf.LeaderElectionID())
+
+	cf.Shard = ShardNetwork
+	assert.NoError(t, cf.HAConfig.validate())
+	assert.False(t, cf.OwnsShard(ShardPolicy))
+	assert.True(t, cf.OwnsShard(ShardNetwork))
+	assert.Equal(t, "nsx-operator-network", cf.LeaderElectionID())
+
+	cf.Shard = "lb"
+	assert.EqualError(t, cf.HAConfig.validate(), "invalid shard lb, it must be one of policy, network")
+}
+
 func TestNSXOperatorConfig_GetCACert(t *testing.T) {
 	caFile, _ := os.CreateTemp("", "config_test")
 	caFile.Write([]byte("dummy file"))
@@ -176,3 +218,56 @@ func TestNSXOperatorConfig_GetCACert(t *testing.T) {
 		})
 	}
 }
+
+func TestNsxConfig_GetAPIRateShares(t *testing.T) {
+	nsxConfig := &NsxConfig{}
+	shares, err := nsxConfig.GetAPIRateShares()
+	assert.NoError(t, err)
+	assert.Nil(t, shares)
+
+	nsxConfig.APIRateShares = []string{"security:4", " subnet:2", ""}
+	shares, err = nsxConfig.GetAPIRateShares()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"security": 4, "subnet": 2}, shares)
+
+	nsxConfig.APIRateShares = []string{"lb:2"}
+	_, err = nsxConfig.GetAPIRateShares()
+	assert.ErrorContains(t, err, "the subsystem must be one of")
+
+	nsxConfig.APIRateShares = []string{"security:0"}
+	_, err = nsxConfig.GetAPIRateShares()
+	assert.ErrorContains(t, err, "the share must be a positive integer")
+}
+
+func TestNsxConfig_GetEnforcementBackends(t *testing.T) {
+	nsxConfig := &NsxConfig{}
+	backends, err := nsxConfig.GetEnforcementBackends()
+	assert.NoError(t, err)
+	assert.Nil(t, backends)
+
+	nsxConfig.EnforcementBackends = []string{"ns-a:global-manager", " ns-b:infra", ""}
+	backends, err = nsxConfig.GetEnforcementBackends()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ns-a": "global-manager", "ns-b": "infra"}, backends)
+
+	nsxConfig.EnforcementBackends = []string{"ns-a"}
+	_, err = nsxConfig.GetEnforcementBackends()
+	assert.ErrorContains(t, err, "it must be <namespace>:<backend>")
+}
+
+func TestNSXOperatorConfig_GetDomain(t *testing.T) {
+	operatorConfig := &NSXOperatorConfig{CoeConfig: &CoeConfig{Cluster: "k8scl-one"}, NsxConfig: &NsxConfig{}}
+	assert.Equal(t, "k8scl-one", operatorConfig.GetDomain())
+	assert.Equal(t, DefaultProjectDomain, operatorConfig.GetProjectDomain())
+	assert.Equal(t, DefaultEnforcementPoint, operatorConfig.GetEnforcementPoint())
+
+	operatorConfig.Domain = "tenant-a"
+	operatorConfig.EnforcementPoint = "vmc-enforcementpoint"
+	assert.Equal(t, "tenant-a", operatorConfig.GetDomain())
+	assert.Equal(t, "tenant-a", operatorConfig.GetProjectDomain())
+	assert.Equal(t, "vmc-enforcementpoint", operatorConfig.GetEnforcementPoint())
+
+	assert.NoError(t, ValidateNSXID(""))
+	assert.NoError(t, ValidateNSXID("tenant-a"))
+	assert.EqualError(t, ValidateNSXID("/infra/domains/tenant-a"), `invalid NSX ID "/infra/domains/tenant-a", '/' and whitespaces are not allowed`)
+}
@@ -0,0 +1,104 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// MinReconcileStallTimeout is the minimal seconds of reconcile_stall_timeout
+	MinReconcileStallTimeout = 60
+	// MinLicenseValidationInterval is the minimal seconds of license_validation_interval
+	MinLicenseValidationInterval = 60
+)
+
+// RuntimeConfig is the part of NSXOperatorConfig which can be changed without restarting nsx-operator,
+// e.g. from the NSXOperatorConfig CR. Zero value of a field means the value from the config file.
+type RuntimeConfig struct {
+	ReconcileStallTimeout     int
+	LicenseValidationInterval int
+}
+
+// ConfigListener is notified before a RuntimeConfig change takes effect, it returns error to reject the change.
+type ConfigListener func(oldConfig, newConfig RuntimeConfig) error
+
+type namedListener struct {
+	name     string
+	listener ConfigListener
+}
+
+var (
+	runtimeLock = &sync.Mutex{}
+	listeners   []namedListener
+)
+
+// RegisterConfigListener registers a listener, the listeners are notified in the order of registration.
+func RegisterConfigListener(name string, listener ConfigListener) {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	listeners = append(listeners, namedListener{name: name, listener: listener})
+}
+
+func (rc *RuntimeConfig) validate() error {
+	if rc.ReconcileStallTimeout != 0 && rc.ReconcileStallTimeout < MinReconcileStallTimeout {
+		return fmt.Errorf("reconcileStallTimeout must be at least %d seconds", MinReconcileStallTimeout)
+	}
+	if rc.LicenseValidationInterval != 0 && rc.LicenseValidationInterval < MinLicenseValidationInterval {
+		return fmt.Errorf("licenseValidationInterval must be at least %d seconds", MinLicenseValidationInterval)
+	}
+	return nil
+}
+
+// GetRuntimeConfig returns the current RuntimeConfig.
+func (operatorConfig *NSXOperatorConfig) GetRuntimeConfig() RuntimeConfig {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	return operatorConfig.getRuntimeConfig()
+}
+
+func (operatorConfig *NSXOperatorConfig) getRuntimeConfig() RuntimeConfig {
+	rc := RuntimeConfig{}
+	if operatorConfig.K8sConfig != nil {
+		rc.ReconcileStallTimeout = operatorConfig.ReconcileStallTimeout
+	}
+	if operatorConfig.NsxConfig != nil {
+		rc.LicenseValidationInterval = operatorConfig.LicenseValidationInterval
+	}
+	return rc
+}
+
+// ApplyRuntimeConfig rolls out the RuntimeConfig to the listeners one by one, if any listener rejects it,
+// the listeners already notified are rolled back and the config is not changed.
+func (operatorConfig *NSXOperatorConfig) ApplyRuntimeConfig(rc RuntimeConfig) error {
+	if err := rc.validate(); err != nil {
+		return err
+	}
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	old := operatorConfig.getRuntimeConfig()
+	if old == rc {
+		return nil
+	}
+	for i, l := range listeners {
+		if err := l.listener(old, rc); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if rollbackErr := listeners[j].listener(rc, old); rollbackErr != nil {
+					configLog.Errorf("failed to roll back config listener %s: %v", listeners[j].name, rollbackErr)
+				}
+			}
+			return errors.Join(fmt.Errorf("config rejected by %s", l.name), err)
+		}
+	}
+	if operatorConfig.K8sConfig != nil {
+		operatorConfig.ReconcileStallTimeout = rc.ReconcileStallTimeout
+	}
+	if operatorConfig.NsxConfig != nil {
+		operatorConfig.LicenseValidationInterval = rc.LicenseValidationInterval
+	}
+	configLog.Infof("runtime config applied: %+v", rc)
+	return nil
+}
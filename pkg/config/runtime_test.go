@@ -0,0 +1,42 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRuntimeConfig(t *testing.T) {
+	defer func() { listeners = nil }()
+	cf := &NSXOperatorConfig{NsxConfig: &NsxConfig{}, K8sConfig: &K8sConfig{}}
+
+	assert.Error(t, cf.ApplyRuntimeConfig(RuntimeConfig{ReconcileStallTimeout: 1}))
+	assert.Error(t, cf.ApplyRuntimeConfig(RuntimeConfig{LicenseValidationInterval: 1}))
+
+	var notified []RuntimeConfig
+	RegisterConfigListener("first", func(_, newConfig RuntimeConfig) error {
+		notified = append(notified, newConfig)
+		return nil
+	})
+	RegisterConfigListener("second", func(_, newConfig RuntimeConfig) error {
+		if newConfig.ReconcileStallTimeout > 3600 {
+			return errors.New("too long")
+		}
+		return nil
+	})
+
+	rc := RuntimeConfig{ReconcileStallTimeout: 120, LicenseValidationInterval: 600}
+	assert.Nil(t, cf.ApplyRuntimeConfig(rc))
+	assert.Equal(t, rc, cf.GetRuntimeConfig())
+	assert.Equal(t, []RuntimeConfig{rc}, notified)
+
+	// rejected by the second listener, the first one is rolled back
+	err := cf.ApplyRuntimeConfig(RuntimeConfig{ReconcileStallTimeout: 7200})
+	assert.ErrorContains(t, err, "config rejected by second")
+	assert.Equal(t, rc, cf.GetRuntimeConfig())
+	assert.Equal(t, rc, notified[len(notified)-1])
+}
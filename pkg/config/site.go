@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	ini "gopkg.in/ini.v1"
+)
+
+// nsxSiteSectionPrefix is the prefix of the sections for additional NSX endpoints, e.g. [nsx_v3.site-a].
+// The keys not set in the section are inherited from [nsx_v3].
+const nsxSiteSectionPrefix = "nsx_v3."
+
+func (operatorConfig *NSXOperatorConfig) loadNsxSites(cfg *ini.File) error {
+	for _, section := range cfg.Sections() {
+		if !strings.HasPrefix(section.Name(), nsxSiteSectionPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(section.Name(), nsxSiteSectionPrefix)
+		siteConfig := *operatorConfig.NsxConfig
+		if err := section.MapTo(&siteConfig); err != nil {
+			return fmt.Errorf("failed to load NSX site %s: %w", name, err)
+		}
+		operatorConfig.NsxSites[name] = &siteConfig
+		configLog.Infof("loaded NSX site %s, managers %v", name, siteConfig.NsxApiManagers)
+	}
+	return nil
+}
+
+func (operatorConfig *NSXOperatorConfig) validateNsxSites() error {
+	if len(operatorConfig.NsxSites) == 0 {
+		return nil
+	}
+	// VPC resources are bound to the NSX project of the default endpoint
+	if operatorConfig.CoeConfig.EnableVPCNetwork {
+		err := errors.New("NSX sites are not supported when VPC network is enabled")
+		configLog.Error(err, "validate NSX sites failed")
+		return err
+	}
+	for name, siteConfig := range operatorConfig.NsxSites {
+		if name == "" {
+			err := errors.New("invalid NSX site name")
+			configLog.Error(err, "validate NSX sites failed")
+			return err
+		}
+		if err := siteConfig.validate(false); err != nil {
+			return fmt.Errorf("invalid NSX site %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// SiteNames returns the sorted names of the additional NSX endpoints.
+func (operatorConfig *NSXOperatorConfig) SiteNames() []string {
+	names := make([]string, 0, len(operatorConfig.NsxSites))
+	for name := range operatorConfig.NsxSites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SiteConfig returns a copy of the config which targets the NSX endpoint of the site,
+// nil if the site is not configured.
+func (operatorConfig *NSXOperatorConfig) SiteConfig(site string) *NSXOperatorConfig {
+	siteConfig, ok := operatorConfig.NsxSites[site]
+	if !ok {
+		return nil
+	}
+	cf := *operatorConfig
+	cf.NsxConfig = siteConfig
+	cf.NsxSites = nil
+	cf.configCache = configCache{}
+	return &cf
+}
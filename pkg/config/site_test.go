@@ -0,0 +1,49 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_NsxSites(t *testing.T) {
+	content := `[coe]
+cluster = k8scl-one
+[nsx_v3]
+nsx_api_managers = 127.0.0.1
+nsx_api_password = admin
+nsx_api_user = admin
+thumbprint = 81:49:DD:B7:E8:79:55:5D:9E:75:A9:FA:A6:7D:CB:EA:A4:CA:12:C6
+[nsx_v3.site-b]
+nsx_api_managers = 127.0.0.3
+[nsx_v3.site-a]
+nsx_api_managers = 127.0.0.2
+nsx_api_user = site-admin
+`
+	file, _ := os.CreateTemp("", "nsxop_sites")
+	defer os.Remove(file.Name())
+	file.WriteString(content)
+	file.Close()
+	configFilePath = file.Name()
+	defer func() { configFilePath = "" }()
+
+	cf, err := NewNSXOperatorConfigFromFile()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"site-a", "site-b"}, cf.SiteNames())
+
+	siteConfig := cf.SiteConfig("site-a")
+	assert.Equal(t, []string{"127.0.0.2"}, siteConfig.NsxApiManagers)
+	assert.Equal(t, "site-admin", siteConfig.NsxApiUser)
+	// inherited from [nsx_v3]
+	assert.Equal(t, "admin", siteConfig.NsxApiPassword)
+	assert.Equal(t, "k8scl-one", siteConfig.Cluster)
+	assert.Equal(t, []string{"127.0.0.1"}, cf.NsxApiManagers)
+	assert.Nil(t, cf.SiteConfig("site-c"))
+
+	cf.EnableVPCNetwork = true
+	assert.NotNil(t, cf.validateNsxSites())
+}
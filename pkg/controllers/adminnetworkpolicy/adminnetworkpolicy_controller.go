@@ -0,0 +1,325 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package adminnetworkpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+const (
+	ReasonConversionFailed = "ConversionFailed"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeAdminNetworkPolicy
+
+	// AdminNetworkPolicyGVK is the upstream AdminNetworkPolicy, it is realized in a DFW category enforced
+	// before the namespace policies.
+	AdminNetworkPolicyGVK = schema.GroupVersionKind{Group: "policy.networking.k8s.io", Version: "v1alpha1", Kind: "AdminNetworkPolicy"}
+	// BaselineAdminNetworkPolicyGVK is the upstream BaselineAdminNetworkPolicy, it is realized after the
+	// namespace policies.
+	BaselineAdminNetworkPolicyGVK = schema.GroupVersionKind{Group: "policy.networking.k8s.io", Version: "v1alpha1", Kind: "BaselineAdminNetworkPolicy"}
+)
+
+// AdminNetworkPolicyReconciler realizes the cluster-scoped admin policies of GVK as the internal SecurityPolicies
+// in the Namespaces of their subjects. A policy which can't be converted is reported as a Warning event, and
+// isn't retried until it's changed.
+type AdminNetworkPolicyReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *securitypolicy.SecurityPolicyService
+	Recorder record.EventRecorder
+	GVK      schema.GroupVersionKind
+}
+
+func (r *AdminNetworkPolicyReconciler) baseline() bool {
+	return r.GVK.Kind == BaselineAdminNetworkPolicyGVK.Kind
+}
+
+func (r *AdminNetworkPolicyReconciler) createdFor() string {
+	if r.baseline() {
+		return servicecommon.ResourceTypeBaselineAdminNetworkPolicy
+	}
+	return servicecommon.ResourceTypeAdminNetworkPolicy
+}
+
+func (r *AdminNetworkPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "kind", r.GVK.Kind, "policy", req.Name)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.GVK)
+	log.Info("reconciling admin network policy", "kind", r.GVK.Kind, "policy", req.Name)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch admin network policy", "kind", r.GVK.Kind, "policy", req.Name)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.AdminNetworkPolicyFinalizerName) {
+			return ResultNormal, nil
+		}
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteAdminNetworkPolicy(obj.GetUID()); err != nil {
+			log.Error(err, "delete failed, would retry exponentially", "kind", r.GVK.Kind, "policy", req.Name)
+			r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonFailDelete, err.Error())
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			return ResultRequeue, err
+		}
+		controllerutil.RemoveFinalizer(obj, servicecommon.AdminNetworkPolicyFinalizerName)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			return ResultRequeue, err
+		}
+		log.V(1).Info("removed finalizer", "kind", r.GVK.Kind, "policy", req.Name)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		return ResultNormal, nil
+	}
+
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+	if !controllerutil.ContainsFinalizer(obj, servicecommon.AdminNetworkPolicyFinalizerName) {
+		controllerutil.AddFinalizer(obj, servicecommon.AdminNetworkPolicyFinalizerName)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			log.Error(err, "add finalizer", "kind", r.GVK.Kind, "policy", req.Name)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+			return ResultRequeue, err
+		}
+		log.V(1).Info("added finalizer", "kind", r.GVK.Kind, "policy", req.Name)
+	}
+
+	internalSecurityPolicies, err := r.convert(ctx, obj)
+	if err != nil {
+		log.Error(err, "failed to convert admin network policy", "kind", r.GVK.Kind, "policy", req.Name)
+		r.Recorder.Event(obj, v1.EventTypeWarning, ReasonConversionFailed, err.Error())
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		// the unsupported spec is not retried until the policy is changed
+		return ResultNormal, nil
+	}
+	if err := r.Service.CreateOrUpdateAdminNetworkPolicy(obj.GetUID(), r.createdFor(), internalSecurityPolicies); err != nil {
+		log.Error(err, "failed to realize admin network policy", "kind", r.GVK.Kind, "policy", req.Name)
+		r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		return ResultRequeue, err
+	}
+	r.Recorder.Event(obj, v1.EventTypeNormal, common.ReasonSuccessfulUpdate,
+		fmt.Sprintf("%s has been successfully realized in %d Namespaces", r.GVK.Kind, len(internalSecurityPolicies)))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+	return ResultNormal, nil
+}
+
+// convert builds an internal SecurityPolicy in each Namespace of the subject, the Namespaces being deleted are
+// skipped. The AdminNetworkPolicy keeps its priority, while the BaselineAdminNetworkPolicy is after the
+// namespace policies.
+func (r *AdminNetworkPolicyReconciler) convert(ctx context.Context, obj *unstructured.Unstructured) ([]*v1alpha1.SecurityPolicy, error) {
+	specMap, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	spec := &adminPolicySpec{}
+	if err := apimachineryruntime.DefaultUnstructuredConverter.FromUnstructured(specMap, spec); err != nil {
+		return nil, err
+	}
+	priority := servicecommon.PriorityBaselineAdminNetworkPolicy
+	if !r.baseline() {
+		if spec.Priority == nil {
+			return nil, errors.New("spec.priority is required")
+		}
+		priority = int(*spec.Priority)
+	}
+	nsSelector, podSelector, err := subjectSelectors(&spec.Subject)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := convertRules(spec, r.baseline())
+	if err != nil {
+		return nil, err
+	}
+
+	nsList := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+		return nil, err
+	}
+	var securityPolicies []*v1alpha1.SecurityPolicy
+	for _, ns := range nsList.Items {
+		if !ns.DeletionTimestamp.IsZero() {
+			continue
+		}
+		sp := &v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns.Name,
+				Name:      obj.GetName(),
+				UID:       types.UID(r.Service.BuildAdminNetworkPolicyID(obj.GetUID(), ns.Name)),
+			},
+			Spec: v1alpha1.SecurityPolicySpec{
+				Priority:  priority,
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: podSelector}},
+				Rules:     rules,
+			},
+		}
+		// the rules are not shared, the peers are sorted in place when the policy is built
+		securityPolicies = append(securityPolicies, sp.DeepCopy())
+	}
+	return securityPolicies, nil
+}
+
+// namespaceMapFunc requeues all the admin policies of GVK when a Namespace is created, deleted or relabeled,
+// the Namespace may join or leave their subjects.
+func (r *AdminNetworkPolicyReconciler) namespaceMapFunc(ctx context.Context, _ client.Object) []reconcile.Request {
+	policyList := &unstructured.UnstructuredList{}
+	policyList.SetGroupVersionKind(r.GVK.GroupVersion().WithKind(r.GVK.Kind + "List"))
+	if err := r.Client.List(ctx, policyList); err != nil {
+		log.Error(err, "failed to list admin network policies in Namespace handler", "kind", r.GVK.Kind)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policyList.Items))
+	for _, policy := range policyList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.GetName()}})
+	}
+	return requests
+}
+
+var predicateFuncsNamespace = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *AdminNetworkPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.GVK)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.GVK.Kind)).
+		For(obj, builder.WithPredicates(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		})).
+		Watches(&v1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.namespaceMapFunc), builder.WithPredicates(predicateFuncsNamespace)).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager
+func (r *AdminNetworkPolicyReconciler) Start(mgr ctrl.Manager) error {
+	return r.setupWithManager(mgr)
+}
+
+// GarbageCollector collects the NSX resources of the admin policies of both kinds which have been removed.
+// cancel is used to break the loop during UT
+func GarbageCollector(c client.Client, service *securitypolicy.SecurityPolicyService, cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		nsxPolicySet := service.ListAdminNetworkPolicyID()
+		if len(nsxPolicySet) == 0 {
+			continue
+		}
+
+		policySet := sets.New[string]()
+		listed := true
+		for _, gvk := range []schema.GroupVersionKind{AdminNetworkPolicyGVK, BaselineAdminNetworkPolicyGVK} {
+			policyList := &unstructured.UnstructuredList{}
+			policyList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := c.List(ctx, policyList); err != nil {
+				log.Error(err, "failed to list admin network policies", "kind", gvk.Kind)
+				listed = false
+				break
+			}
+			for _, policy := range policyList.Items {
+				policySet.Insert(string(policy.GetUID()))
+			}
+		}
+		if !listed {
+			continue
+		}
+
+		gcSet := sets.New[string]()
+		for elem := range nsxPolicySet {
+			if uid := securitypolicy.AdminNetworkPolicyUIDOf(elem); !policySet.Has(uid) {
+				gcSet.Insert(uid)
+			}
+		}
+		for uid := range gcSet {
+			log.V(1).Info("GC collected admin network policy", "UID", uid)
+			metrics.CounterInc(service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := service.DeleteAdminNetworkPolicy(types.UID(uid)); err != nil {
+				metrics.CounterInc(service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+// StartAdminNetworkPolicyController realizes the AdminNetworkPolicies and BaselineAdminNetworkPolicies, it
+// requires the CRDs of the upstream AdminNetworkPolicy API are installed.
+func StartAdminNetworkPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+	service := securitypolicy.GetSecurityService(commonService, vpcService)
+	for _, gvk := range []schema.GroupVersionKind{AdminNetworkPolicyGVK, BaselineAdminNetworkPolicyGVK} {
+		reconciler := &AdminNetworkPolicyReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Service:  service,
+			Recorder: mgr.GetEventRecorderFor("adminnetworkpolicy-controller"),
+			GVK:      gvk,
+		}
+		if err := reconciler.Start(mgr); err != nil {
+			log.Error(err, "failed to create controller", "controller", "AdminNetworkPolicy", "kind", gvk.Kind)
+			os.Exit(1)
+		}
+	}
+	go GarbageCollector(mgr.GetClient(), service, make(chan bool), servicecommon.GCInterval)
+}
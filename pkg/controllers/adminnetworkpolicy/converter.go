@@ -0,0 +1,224 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package adminnetworkpolicy
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// The types of the upstream AdminNetworkPolicy API decoded from the unstructured CRs, the node peers are
+// decoded only to report them as unsupported.
+
+type adminPolicySpec struct {
+	Priority *int32             `json:"priority,omitempty"`
+	Subject  adminPolicySubject `json:"subject"`
+	Ingress  []adminPolicyRule  `json:"ingress,omitempty"`
+	Egress   []adminPolicyRule  `json:"egress,omitempty"`
+}
+
+type adminPolicySubject struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *adminPolicyPods      `json:"pods,omitempty"`
+}
+
+type adminPolicyPods struct {
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	PodSelector       metav1.LabelSelector `json:"podSelector"`
+}
+
+type adminPolicyRule struct {
+	Name   string            `json:"name,omitempty"`
+	Action string            `json:"action"`
+	From   []adminPolicyPeer `json:"from,omitempty"`
+	To     []adminPolicyPeer `json:"to,omitempty"`
+	Ports  []adminPolicyPort `json:"ports,omitempty"`
+}
+
+type adminPolicyPeer struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *adminPolicyPods      `json:"pods,omitempty"`
+	Nodes      *metav1.LabelSelector `json:"nodes,omitempty"`
+	Networks   []string              `json:"networks,omitempty"`
+}
+
+type adminPolicyPort struct {
+	PortNumber *adminPolicyPortNumber `json:"portNumber,omitempty"`
+	NamedPort  *string                `json:"namedPort,omitempty"`
+	PortRange  *adminPolicyPortRange  `json:"portRange,omitempty"`
+}
+
+type adminPolicyPortNumber struct {
+	Protocol v1.Protocol `json:"protocol"`
+	Port     int32       `json:"port"`
+}
+
+type adminPolicyPortRange struct {
+	Protocol v1.Protocol `json:"protocol,omitempty"`
+	Start    int32       `json:"start"`
+	End      int32       `json:"end"`
+}
+
+const (
+	adminActionAllow = "Allow"
+	adminActionDeny  = "Deny"
+	adminActionPass  = "Pass"
+)
+
+// subjectSelectors returns the selector of the Namespaces of the subject, and the selector of the Pods the
+// rules are applied to in each of them.
+func subjectSelectors(subject *adminPolicySubject) (labels.Selector, *metav1.LabelSelector, error) {
+	switch {
+	case subject.Namespaces != nil && subject.Pods == nil:
+		selector, err := metav1.LabelSelectorAsSelector(subject.Namespaces)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid subject namespaces: %w", err)
+		}
+		return selector, &metav1.LabelSelector{}, nil
+	case subject.Namespaces == nil && subject.Pods != nil:
+		selector, err := metav1.LabelSelectorAsSelector(&subject.Pods.NamespaceSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid subject pods.namespaceSelector: %w", err)
+		}
+		return selector, subject.Pods.PodSelector.DeepCopy(), nil
+	default:
+		return nil, nil, errors.New("exactly one of subject namespaces and pods must be set")
+	}
+}
+
+// convertRules converts the ingress and egress rules in their order, which is kept by the sequence numbers of
+// the NSX rules. The Pass action is only allowed by the AdminNetworkPolicy.
+func convertRules(spec *adminPolicySpec, baseline bool) ([]v1alpha1.SecurityPolicyRule, error) {
+	var rules []v1alpha1.SecurityPolicyRule
+	for i := range spec.Ingress {
+		rule, err := convertRule(&spec.Ingress[i], v1alpha1.RuleDirectionIn, baseline)
+		if err != nil {
+			return nil, fmt.Errorf("spec.ingress[%d]: %w", i, err)
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("ingress-%d", i)
+		}
+		rules = append(rules, *rule)
+	}
+	for i := range spec.Egress {
+		rule, err := convertRule(&spec.Egress[i], v1alpha1.RuleDirectionOut, baseline)
+		if err != nil {
+			return nil, fmt.Errorf("spec.egress[%d]: %w", i, err)
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("egress-%d", i)
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+func convertRule(adminRule *adminPolicyRule, direction v1alpha1.RuleDirection, baseline bool) (*v1alpha1.SecurityPolicyRule, error) {
+	var action v1alpha1.RuleAction
+	switch adminRule.Action {
+	case adminActionAllow:
+		action = v1alpha1.RuleActionAllow
+	case adminActionDeny:
+		action = v1alpha1.RuleActionDrop
+	case adminActionPass:
+		if baseline {
+			return nil, errors.New("action Pass is not allowed by BaselineAdminNetworkPolicy")
+		}
+		action = v1alpha1.RuleActionPass
+	default:
+		return nil, fmt.Errorf("unsupported action %q", adminRule.Action)
+	}
+	rule := &v1alpha1.SecurityPolicyRule{
+		Name:      adminRule.Name,
+		Action:    &action,
+		Direction: &direction,
+	}
+
+	adminPeers := adminRule.From
+	if direction == v1alpha1.RuleDirectionOut {
+		adminPeers = adminRule.To
+	}
+	var peers []v1alpha1.SecurityPolicyPeer
+	for i := range adminPeers {
+		peer, err := convertPeer(&adminPeers[i])
+		if err != nil {
+			return nil, fmt.Errorf("peer %d: %w", i, err)
+		}
+		peers = append(peers, *peer)
+	}
+	if direction == v1alpha1.RuleDirectionIn {
+		rule.Sources = peers
+	} else {
+		rule.Destinations = peers
+	}
+
+	for i := range adminRule.Ports {
+		port, err := convertPort(&adminRule.Ports[i])
+		if err != nil {
+			return nil, fmt.Errorf("port %d: %w", i, err)
+		}
+		rule.Ports = append(rule.Ports, *port)
+	}
+	return rule, nil
+}
+
+func convertPeer(adminPeer *adminPolicyPeer) (*v1alpha1.SecurityPolicyPeer, error) {
+	switch {
+	case adminPeer.Namespaces != nil:
+		// all the Pods in the selected Namespaces
+		return &v1alpha1.SecurityPolicyPeer{
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{}},
+			NamespaceSelector: adminPeer.Namespaces,
+		}, nil
+	case adminPeer.Pods != nil:
+		return &v1alpha1.SecurityPolicyPeer{
+			PodSelector:       adminPeer.Pods.PodSelector.DeepCopy(),
+			NamespaceSelector: adminPeer.Pods.NamespaceSelector.DeepCopy(),
+		}, nil
+	case len(adminPeer.Networks) > 0:
+		peer := &v1alpha1.SecurityPolicyPeer{}
+		for _, cidr := range adminPeer.Networks {
+			peer.IPBlocks = append(peer.IPBlocks, v1alpha1.IPBlock{CIDR: cidr})
+		}
+		return peer, nil
+	case adminPeer.Nodes != nil:
+		return nil, errors.New("nodes peer is not supported")
+	default:
+		return nil, errors.New("empty peer")
+	}
+}
+
+func convertPort(adminPort *adminPolicyPort) (*v1alpha1.SecurityPolicyPort, error) {
+	switch {
+	case adminPort.PortNumber != nil:
+		return &v1alpha1.SecurityPolicyPort{
+			Protocol: adminPort.PortNumber.Protocol,
+			Port:     intstr.FromInt(int(adminPort.PortNumber.Port)),
+		}, nil
+	case adminPort.NamedPort != nil:
+		return &v1alpha1.SecurityPolicyPort{
+			Protocol: v1.ProtocolTCP,
+			Port:     intstr.FromString(*adminPort.NamedPort),
+		}, nil
+	case adminPort.PortRange != nil:
+		protocol := adminPort.PortRange.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		return &v1alpha1.SecurityPolicyPort{
+			Protocol: protocol,
+			Port:     intstr.FromInt(int(adminPort.PortRange.Start)),
+			EndPort:  int(adminPort.PortRange.End),
+		}, nil
+	default:
+		return nil, errors.New("empty port")
+	}
+}
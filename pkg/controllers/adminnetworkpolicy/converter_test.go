@@ -0,0 +1,131 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package adminnetworkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func TestSubjectSelectors(t *testing.T) {
+	webSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	prodSelector := metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+
+	nsSelector, podSelector, err := subjectSelectors(&adminPolicySubject{Namespaces: &prodSelector})
+	assert.NoError(t, err)
+	assert.Equal(t, "env=prod", nsSelector.String())
+	assert.Equal(t, &metav1.LabelSelector{}, podSelector)
+
+	nsSelector, podSelector, err = subjectSelectors(&adminPolicySubject{Pods: &adminPolicyPods{NamespaceSelector: prodSelector, PodSelector: webSelector}})
+	assert.NoError(t, err)
+	assert.Equal(t, "env=prod", nsSelector.String())
+	assert.Equal(t, &webSelector, podSelector)
+
+	_, _, err = subjectSelectors(&adminPolicySubject{})
+	assert.EqualError(t, err, "exactly one of subject namespaces and pods must be set")
+}
+
+func TestConvertRules(t *testing.T) {
+	http := "http"
+	prodSelector := metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	spec := &adminPolicySpec{
+		Ingress: []adminPolicyRule{{
+			Name:   "deny-from-prod",
+			Action: adminActionDeny,
+			From:   []adminPolicyPeer{{Namespaces: &prodSelector}},
+			Ports:  []adminPolicyPort{{PortNumber: &adminPolicyPortNumber{Protocol: v1.ProtocolUDP, Port: 53}}},
+		}},
+		Egress: []adminPolicyRule{{
+			Action: adminActionPass,
+			To:     []adminPolicyPeer{{Networks: []string{"10.0.0.0/8"}}},
+			Ports: []adminPolicyPort{
+				{NamedPort: &http},
+				{PortRange: &adminPolicyPortRange{Start: 8000, End: 8080}},
+			},
+		}},
+	}
+	rules, err := convertRules(spec, false)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+
+	assert.Equal(t, "deny-from-prod", rules[0].Name)
+	assert.Equal(t, v1alpha1.RuleActionDrop, *rules[0].Action)
+	assert.Equal(t, v1alpha1.RuleDirectionIn, *rules[0].Direction)
+	assert.Equal(t, &prodSelector, rules[0].Sources[0].NamespaceSelector)
+	assert.Equal(t, []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolUDP, Port: intstr.FromInt(53)}}, rules[0].Ports)
+
+	assert.Equal(t, "egress-0", rules[1].Name)
+	assert.Equal(t, v1alpha1.RuleActionPass, *rules[1].Action)
+	assert.Equal(t, []v1alpha1.IPBlock{{CIDR: "10.0.0.0/8"}}, rules[1].Destinations[0].IPBlocks)
+	assert.Equal(t, []v1alpha1.SecurityPolicyPort{
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromString("http")},
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(8000), EndPort: 8080},
+	}, rules[1].Ports)
+
+	// the Pass action is not allowed by the baseline policy
+	_, err = convertRules(spec, true)
+	assert.EqualError(t, err, "spec.egress[0]: action Pass is not allowed by BaselineAdminNetworkPolicy")
+
+	spec.Egress[0].To = []adminPolicyPeer{{Nodes: &metav1.LabelSelector{}}}
+	_, err = convertRules(spec, false)
+	assert.EqualError(t, err, "spec.egress[0]: peer 0: nodes peer is not supported")
+}
+
+func newAdminPolicy(gvk schema.GroupVersionKind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetUID(types.UID(name + "-uid"))
+	return obj
+}
+
+func TestAdminNetworkPolicyReconciler_convert(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	nsProd := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	nsDev := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nsProd, nsDev).Build()
+	spec := map[string]interface{}{
+		"priority": int64(10),
+		"subject":  map[string]interface{}{"namespaces": map[string]interface{}{"matchLabels": map[string]interface{}{"env": "prod"}}},
+		"ingress": []interface{}{map[string]interface{}{
+			"name":   "allow-from-all",
+			"action": "Allow",
+			"from":   []interface{}{map[string]interface{}{"namespaces": map[string]interface{}{}}},
+		}},
+	}
+	r := &AdminNetworkPolicyReconciler{Client: k8sClient, Service: &securitypolicy.SecurityPolicyService{}, GVK: AdminNetworkPolicyGVK}
+
+	// the policy is converted in the Namespaces of the subject with its priority
+	securityPolicies, err := r.convert(context.TODO(), newAdminPolicy(AdminNetworkPolicyGVK, "guardrail", spec))
+	assert.NoError(t, err)
+	assert.Len(t, securityPolicies, 1)
+	assert.Equal(t, "prod", securityPolicies[0].Namespace)
+	assert.Equal(t, types.UID("guardrail-uid_prod"), securityPolicies[0].UID)
+	assert.Equal(t, 10, securityPolicies[0].Spec.Priority)
+
+	// the baseline policy is after the namespace policies
+	r.GVK = BaselineAdminNetworkPolicyGVK
+	delete(spec, "priority")
+	spec["subject"] = map[string]interface{}{"namespaces": map[string]interface{}{}}
+	securityPolicies, err = r.convert(context.TODO(), newAdminPolicy(BaselineAdminNetworkPolicyGVK, "default", spec))
+	assert.NoError(t, err)
+	assert.Len(t, securityPolicies, 2)
+	assert.Equal(t, servicecommon.PriorityBaselineAdminNetworkPolicy, securityPolicies[0].Spec.Priority)
+}
@@ -0,0 +1,278 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package antreapolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	ReasonConverted        = "Converted"
+	ReasonConversionFailed = "ConversionFailed"
+	ReasonConversionLossy  = "ConversionLossy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+
+	// NetworkPolicyGVK is the Antrea-native NetworkPolicy, it is converted to a SecurityPolicy in its Namespace.
+	NetworkPolicyGVK = schema.GroupVersionKind{Group: "crd.antrea.io", Version: "v1beta1", Kind: "NetworkPolicy"}
+	// ClusterNetworkPolicyGVK is the Antrea ClusterNetworkPolicy, it is converted to a SecurityPolicy in
+	// each Namespace it is applied to.
+	ClusterNetworkPolicyGVK = schema.GroupVersionKind{Group: "crd.antrea.io", Version: "v1beta1", Kind: "ClusterNetworkPolicy"}
+
+	securityPolicyPrefix = map[string]string{
+		NetworkPolicyGVK.Kind:        "antrea-anp-",
+		ClusterNetworkPolicyGVK.Kind: "antrea-acnp-",
+	}
+)
+
+// AntreaPolicyReconciler converts the Antrea-native policies of GVK to SecurityPolicy CRs, which are realized
+// on NSX by the SecurityPolicy controller. The SecurityPolicies are owned by the Antrea policy, so they are
+// garbage collected with it. A policy which can't be fully converted keeps the SecurityPolicies converted
+// from its last supported spec, the reason is reported as a Warning event on the Antrea policy.
+type AntreaPolicyReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Recorder record.EventRecorder
+	GVK      schema.GroupVersionKind
+}
+
+func (r *AntreaPolicyReconciler) clusterScoped() bool {
+	return r.GVK.Kind == ClusterNetworkPolicyGVK.Kind
+}
+
+func (r *AntreaPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.GVK)
+	log.Info("reconciling Antrea policy", "kind", r.GVK.Kind, "policy", req.NamespacedName)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		// the converted SecurityPolicies are garbage collected by the owner reference
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return ResultNormal, nil
+	}
+
+	desired, warnings, err := r.convert(ctx, obj)
+	if err != nil {
+		log.Error(err, "failed to convert Antrea policy", "kind", r.GVK.Kind, "policy", req.NamespacedName)
+		r.Recorder.Event(obj, v1.EventTypeWarning, ReasonConversionFailed, err.Error())
+		// the unsupported spec is not retried until the policy is changed
+		return ResultNormal, nil
+	}
+	if err := r.apply(ctx, obj, desired); err != nil {
+		log.Error(err, "failed to apply converted SecurityPolicies", "kind", r.GVK.Kind, "policy", req.NamespacedName)
+		return ResultRequeue, err
+	}
+	if len(warnings) > 0 {
+		r.Recorder.Event(obj, v1.EventTypeWarning, ReasonConversionLossy, strings.Join(warnings, "; "))
+	}
+	r.Recorder.Event(obj, v1.EventTypeNormal, ReasonConverted, fmt.Sprintf("converted to %d SecurityPolicies", len(desired)))
+	return ResultNormal, nil
+}
+
+// convert builds the SecurityPolicies of the Antrea policy, an Antrea NetworkPolicy is converted to a
+// SecurityPolicy in its Namespace, while a ClusterNetworkPolicy is converted to a SecurityPolicy in each
+// Namespace selected by its appliedTo.
+func (r *AntreaPolicyReconciler) convert(ctx context.Context, obj *unstructured.Unstructured) ([]*v1alpha1.SecurityPolicy, []string, error) {
+	specMap, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, nil, err
+	}
+	spec := &antreaPolicySpec{}
+	if err := apimachineryruntime.DefaultUnstructuredConverter.FromUnstructured(specMap, spec); err != nil {
+		return nil, nil, err
+	}
+
+	if !r.clusterScoped() {
+		converted, warnings, err := convertSpec(spec, nil, false)
+		if err != nil || converted == nil {
+			return nil, warnings, err
+		}
+		return []*v1alpha1.SecurityPolicy{r.buildSecurityPolicy(obj, obj.GetNamespace(), converted)}, warnings, nil
+	}
+
+	nsList := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList); err != nil {
+		return nil, nil, err
+	}
+	var securityPolicies []*v1alpha1.SecurityPolicy
+	var warnings []string
+	for _, ns := range nsList.Items {
+		converted, nsWarnings, err := convertSpec(spec, labels.Set(ns.Labels), true)
+		if err != nil {
+			return nil, nil, err
+		}
+		// the warnings of the tier and priority are the same for all the Namespaces
+		warnings = nsWarnings
+		if converted != nil {
+			securityPolicies = append(securityPolicies, r.buildSecurityPolicy(obj, ns.Name, converted))
+		}
+	}
+	return securityPolicies, warnings, nil
+}
+
+func (r *AntreaPolicyReconciler) buildSecurityPolicy(obj *unstructured.Unstructured, namespace string, spec *v1alpha1.SecurityPolicySpec) *v1alpha1.SecurityPolicy {
+	return &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        securityPolicyPrefix[r.GVK.Kind] + obj.GetName(),
+			Labels:      map[string]string{servicecommon.LabelAntreaPolicyUID: string(obj.GetUID())},
+			Annotations: map[string]string{servicecommon.AnnotationAntreaPolicy: r.GVK.Kind + "/" + obj.GetName()},
+		},
+		Spec: *spec,
+	}
+}
+
+// apply creates or updates the desired SecurityPolicies and deletes the ones converted before but no more
+// desired, e.g. the Namespace is not selected by the ClusterNetworkPolicy anymore.
+func (r *AntreaPolicyReconciler) apply(ctx context.Context, obj *unstructured.Unstructured, desired []*v1alpha1.SecurityPolicy) error {
+	existingList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, existingList, client.MatchingLabels{servicecommon.LabelAntreaPolicyUID: string(obj.GetUID())}); err != nil {
+		return err
+	}
+	existing := make(map[types.NamespacedName]*v1alpha1.SecurityPolicy, len(existingList.Items))
+	for i := range existingList.Items {
+		sp := &existingList.Items[i]
+		existing[types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}] = sp
+	}
+
+	for _, sp := range desired {
+		key := types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}
+		current, found := existing[key]
+		delete(existing, key)
+		if !found {
+			if err := controllerutil.SetControllerReference(obj, sp, r.Scheme); err != nil {
+				return err
+			}
+			if err := r.Client.Create(ctx, sp); err != nil {
+				return err
+			}
+			log.Info("created SecurityPolicy converted from Antrea policy", "kind", r.GVK.Kind, "policy", obj.GetName(), "securitypolicy", key)
+			continue
+		}
+		if reflect.DeepEqual(current.Spec, sp.Spec) {
+			continue
+		}
+		current.Spec = sp.Spec
+		if err := r.Client.Update(ctx, current); err != nil {
+			return err
+		}
+		log.Info("updated SecurityPolicy converted from Antrea policy", "kind", r.GVK.Kind, "policy", obj.GetName(), "securitypolicy", key)
+	}
+
+	for key, stale := range existing {
+		if err := r.Client.Delete(ctx, stale); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("deleted stale SecurityPolicy converted from Antrea policy", "kind", r.GVK.Kind, "policy", obj.GetName(), "securitypolicy", key)
+	}
+	return nil
+}
+
+// namespaceMapFunc requeues all the ClusterNetworkPolicies when the labels of a Namespace are changed or
+// a Namespace is created, since the Namespaces they are applied to may be changed.
+func (r *AntreaPolicyReconciler) namespaceMapFunc(ctx context.Context, _ client.Object) []reconcile.Request {
+	policyList := &unstructured.UnstructuredList{}
+	policyList.SetGroupVersionKind(ClusterNetworkPolicyGVK.GroupVersion().WithKind(ClusterNetworkPolicyGVK.Kind + "List"))
+	if err := r.Client.List(ctx, policyList); err != nil {
+		log.Error(err, "failed to list Antrea ClusterNetworkPolicies in Namespace handler")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policyList.Items))
+	for _, policy := range policyList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.GetName()}})
+	}
+	return requests
+}
+
+var PredicateFuncsNs = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		// the converted SecurityPolicies are deleted with the Namespace
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *AntreaPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.GVK)
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("antrea-"+strings.ToLower(r.GVK.Kind)).
+		For(obj, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// revert the changes on the converted SecurityPolicies
+		Owns(&v1alpha1.SecurityPolicy{}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			})
+	if r.clusterScoped() {
+		b = b.Watches(&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceMapFunc),
+			builder.WithPredicates(PredicateFuncsNs))
+	}
+	return b.Complete(r)
+}
+
+// Start setup manager
+func (r *AntreaPolicyReconciler) Start(mgr ctrl.Manager) error {
+	return r.setupWithManager(mgr)
+}
+
+// StartAntreaPolicyController converts the Antrea NetworkPolicies and ClusterNetworkPolicies to
+// SecurityPolicies, it requires the Antrea CRDs are installed.
+func StartAntreaPolicyController(mgr ctrl.Manager) {
+	for _, gvk := range []schema.GroupVersionKind{NetworkPolicyGVK, ClusterNetworkPolicyGVK} {
+		reconciler := &AntreaPolicyReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("antreapolicy-controller"),
+			GVK:      gvk,
+		}
+		if err := reconciler.Start(mgr); err != nil {
+			log.Error(err, "failed to create controller", "controller", "AntreaPolicy", "kind", gvk.Kind)
+			os.Exit(1)
+		}
+	}
+}
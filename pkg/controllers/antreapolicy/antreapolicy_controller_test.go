@@ -0,0 +1,119 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package antreapolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	for _, gvk := range []schema.GroupVersionKind{NetworkPolicyGVK, ClusterNetworkPolicyGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return scheme
+}
+
+func newAntreaPolicy(gvk schema.GroupVersionKind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID(name + "-uid"))
+	return obj
+}
+
+func TestAntreaPolicyReconciler_NetworkPolicy(t *testing.T) {
+	scheme := newScheme()
+	policy := newAntreaPolicy(NetworkPolicyGVK, "ns1", "web", map[string]interface{}{
+		"tier":      "application",
+		"priority":  int64(5),
+		"appliedTo": []interface{}{map[string]interface{}{"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}}}},
+		"ingress": []interface{}{map[string]interface{}{
+			"action": "Allow",
+			"from":   []interface{}{map[string]interface{}{"ipBlock": map[string]interface{}{"cidr": "10.0.0.0/24"}}},
+			"ports":  []interface{}{map[string]interface{}{"protocol": "TCP", "port": int64(80)}},
+		}},
+	})
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &AntreaPolicyReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder, GVK: NetworkPolicyGVK}
+	ctx := context.TODO()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "web"}})
+	assert.NoError(t, err)
+	sp := &v1alpha1.SecurityPolicy{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "antrea-anp-web"}, sp))
+	assert.Equal(t, 405, sp.Spec.Priority)
+	assert.Equal(t, "web-uid", sp.Labels[servicecommon.LabelAntreaPolicyUID])
+	assert.Equal(t, "NetworkPolicy/web", sp.Annotations[servicecommon.AnnotationAntreaPolicy])
+	assert.Equal(t, "web", sp.OwnerReferences[0].Name)
+	assert.Equal(t, []v1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}, sp.Spec.Rules[0].Sources[0].IPBlocks)
+	assert.Contains(t, <-recorder.Events, ReasonConverted)
+
+	// the unsupported spec keeps the converted SecurityPolicy
+	assert.NoError(t, unstructured.SetNestedSlice(policy.Object, []interface{}{map[string]interface{}{"action": "Pass"}}, "spec", "egress"))
+	assert.NoError(t, k8sClient.Update(ctx, policy))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "web"}})
+	assert.NoError(t, err)
+	assert.Contains(t, <-recorder.Events, ReasonConversionFailed)
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "antrea-anp-web"}, sp))
+}
+
+func TestAntreaPolicyReconciler_ClusterNetworkPolicy(t *testing.T) {
+	scheme := newScheme()
+	policy := newAntreaPolicy(ClusterNetworkPolicyGVK, "", "deny-all", map[string]interface{}{
+		"tier":      "baseline",
+		"priority":  int64(1),
+		"appliedTo": []interface{}{map[string]interface{}{"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"env": "prod"}}}},
+		"ingress":   []interface{}{map[string]interface{}{"action": "Drop"}},
+	})
+	nsProd := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	nsDev := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, nsProd, nsDev).Build()
+	r := &AntreaPolicyReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10), GVK: ClusterNetworkPolicyGVK}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "deny-all"}}
+
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	spList := &v1alpha1.SecurityPolicyList{}
+	assert.NoError(t, k8sClient.List(ctx, spList, client.MatchingLabels{servicecommon.LabelAntreaPolicyUID: "deny-all-uid"}))
+	assert.Len(t, spList.Items, 1)
+	assert.Equal(t, "prod", spList.Items[0].Namespace)
+	assert.Equal(t, "antrea-acnp-deny-all", spList.Items[0].Name)
+	assert.Equal(t, 901, spList.Items[0].Spec.Priority)
+
+	// the Namespace is relabeled, the SecurityPolicy in the Namespace not selected anymore is deleted
+	assert.Len(t, r.namespaceMapFunc(ctx, nsDev), 1)
+	nsProd.Labels["env"] = "staging"
+	nsDev.Labels["env"] = "prod"
+	assert.NoError(t, k8sClient.Update(ctx, nsProd))
+	assert.NoError(t, k8sClient.Update(ctx, nsDev))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, k8sClient.List(ctx, spList, client.MatchingLabels{servicecommon.LabelAntreaPolicyUID: "deny-all-uid"}))
+	assert.Len(t, spList.Items, 1)
+	assert.Equal(t, "dev", spList.Items[0].Namespace)
+}
@@ -0,0 +1,304 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package antreapolicy
+
+import (
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// The Antrea-native policy types decoded from the unstructured CRs, the fields which can't be converted
+// to SecurityPolicy are decoded only to report them as unsupported.
+
+type antreaPolicySpec struct {
+	Tier      string            `json:"tier,omitempty"`
+	Priority  float64           `json:"priority"`
+	AppliedTo []antreaAppliedTo `json:"appliedTo,omitempty"`
+	Ingress   []antreaRule      `json:"ingress,omitempty"`
+	Egress    []antreaRule      `json:"egress,omitempty"`
+}
+
+type antreaAppliedTo struct {
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	Group             string                `json:"group,omitempty"`
+	ExternalEntity    *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	ServiceAccount    *struct{}             `json:"serviceAccount,omitempty"`
+	Service           *struct{}             `json:"service,omitempty"`
+	NodeSelector      *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+type antreaRule struct {
+	Name      string            `json:"name,omitempty"`
+	Action    *string           `json:"action,omitempty"`
+	From      []antreaPeer      `json:"from,omitempty"`
+	To        []antreaPeer      `json:"to,omitempty"`
+	Ports     []antreaPort      `json:"ports,omitempty"`
+	AppliedTo []antreaAppliedTo `json:"appliedTo,omitempty"`
+	ToService []struct{}        `json:"toServices,omitempty"`
+	L7        []struct{}        `json:"l7Protocols,omitempty"`
+	Protocols []struct{}        `json:"protocols,omitempty"`
+}
+
+type antreaPeer struct {
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	Namespaces        *antreaPeerNamespaces `json:"namespaces,omitempty"`
+	IPBlock           *antreaIPBlock        `json:"ipBlock,omitempty"`
+	FQDN              string                `json:"fqdn,omitempty"`
+	Group             string                `json:"group,omitempty"`
+	ExternalEntity    *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	ServiceAccount    *struct{}             `json:"serviceAccount,omitempty"`
+	NodeSelector      *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	Scope             string                `json:"scope,omitempty"`
+}
+
+type antreaPeerNamespaces struct {
+	Match string `json:"match,omitempty"`
+}
+
+type antreaIPBlock struct {
+	CIDR string `json:"cidr"`
+}
+
+type antreaPort struct {
+	Protocol *v1.Protocol        `json:"protocol,omitempty"`
+	Port     *intstr.IntOrString `json:"port,omitempty"`
+	EndPort  *int32              `json:"endPort,omitempty"`
+}
+
+const (
+	antreaActionAllow  = "Allow"
+	antreaActionDrop   = "Drop"
+	antreaActionReject = "Reject"
+
+	antreaNamespaceMatchSelf = "Self"
+
+	// tierPriorityBand is the range of SecurityPolicy priorities each Antrea tier is mapped to.
+	tierPriorityBand = 100
+	defaultTier      = "application"
+)
+
+// tierPriorityBase maps the Antrea static tiers to the bases of SecurityPolicy priority, the policy
+// priority within a tier is added to the base, so the tier order is kept on NSX.
+var tierPriorityBase = map[string]int{
+	"emergency":   0,
+	"securityops": 100,
+	"networkops":  200,
+	"platform":    300,
+	"application": 400,
+	"baseline":    900,
+}
+
+// convertPriority maps the tier and the priority of an Antrea policy to the priority of SecurityPolicy,
+// the priority within a tier is rounded down and capped to the band of the tier.
+func convertPriority(tier string, priority float64) (int, []string) {
+	var warnings []string
+	if tier == "" {
+		tier = defaultTier
+	}
+	base, ok := tierPriorityBase[tier]
+	if !ok {
+		warnings = append(warnings, fmt.Sprintf("custom tier %s is converted as tier %s", tier, defaultTier))
+		base = tierPriorityBase[defaultTier]
+	}
+	offset := int(math.Floor(priority))
+	if offset >= tierPriorityBand {
+		warnings = append(warnings, fmt.Sprintf("priority %v is capped to %d within the tier", priority, tierPriorityBand-1))
+		offset = tierPriorityBand - 1
+	} else if offset < 0 {
+		offset = 0
+	}
+	return base + offset, warnings
+}
+
+// convertAppliedTo converts the appliedTo selecting the Pods in the Namespace with the labels nsLabels,
+// nsLabels is nil for an Antrea NetworkPolicy, since its appliedTo selects the Pods in its own Namespace.
+// It returns false if none of the appliedTo selects the Namespace.
+func convertAppliedTo(appliedTo []antreaAppliedTo, nsLabels labels.Set) ([]v1alpha1.SecurityPolicyTarget, bool, error) {
+	var targets []v1alpha1.SecurityPolicyTarget
+	for i := range appliedTo {
+		at := &appliedTo[i]
+		if at.Group != "" || at.ExternalEntity != nil || at.ServiceAccount != nil || at.Service != nil || at.NodeSelector != nil {
+			return nil, false, fmt.Errorf("appliedTo %d: only podSelector and namespaceSelector are supported", i)
+		}
+		if nsLabels != nil {
+			matched, err := selectorMatches(at.NamespaceSelector, nsLabels)
+			if err != nil {
+				return nil, false, fmt.Errorf("appliedTo %d: %w", i, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		podSelector := at.PodSelector
+		if podSelector == nil {
+			podSelector = &metav1.LabelSelector{}
+		}
+		targets = append(targets, v1alpha1.SecurityPolicyTarget{PodSelector: podSelector.DeepCopy()})
+	}
+	return targets, len(targets) > 0, nil
+}
+
+// selectorMatches checks if the labels match the selector, the nil selector matches any labels.
+func selectorMatches(selector *metav1.LabelSelector, set labels.Set) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(set), nil
+}
+
+// convertPeers converts the peers of a rule, clusterScoped is true for an Antrea ClusterNetworkPolicy,
+// whose podSelector without namespaceSelector selects the Pods in all Namespaces.
+func convertPeers(peers []antreaPeer, clusterScoped bool) ([]v1alpha1.SecurityPolicyPeer, error) {
+	// nil for no peer, so the spec is equal to the one read back from API server
+	var result []v1alpha1.SecurityPolicyPeer
+	for i := range peers {
+		peer := &peers[i]
+		switch {
+		case peer.FQDN != "":
+			return nil, fmt.Errorf("peer %d: fqdn %s is not supported", i, peer.FQDN)
+		case peer.Group != "" || peer.ExternalEntity != nil || peer.ServiceAccount != nil || peer.NodeSelector != nil || peer.Scope != "":
+			return nil, fmt.Errorf("peer %d: only podSelector, namespaceSelector, namespaces and ipBlock are supported", i)
+		case peer.Namespaces != nil && peer.Namespaces.Match != antreaNamespaceMatchSelf:
+			return nil, fmt.Errorf("peer %d: only namespaces match Self is supported", i)
+		}
+		converted := v1alpha1.SecurityPolicyPeer{}
+		if peer.IPBlock != nil {
+			converted.IPBlocks = []v1alpha1.IPBlock{{CIDR: peer.IPBlock.CIDR}}
+		}
+		if peer.PodSelector != nil {
+			converted.PodSelector = peer.PodSelector.DeepCopy()
+		} else if peer.Namespaces != nil {
+			// all the Pods in the Namespace of the SecurityPolicy
+			converted.PodSelector = &metav1.LabelSelector{}
+		}
+		if peer.NamespaceSelector != nil {
+			converted.NamespaceSelector = peer.NamespaceSelector.DeepCopy()
+		} else if clusterScoped && peer.PodSelector != nil && peer.Namespaces == nil {
+			// the Pods in all Namespaces, while the Pods in the Namespace of the SecurityPolicy for "Self"
+			converted.NamespaceSelector = &metav1.LabelSelector{}
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+func convertPorts(ports []antreaPort) []v1alpha1.SecurityPolicyPort {
+	var result []v1alpha1.SecurityPolicyPort
+	for _, port := range ports {
+		converted := v1alpha1.SecurityPolicyPort{}
+		if port.Protocol != nil {
+			converted.Protocol = *port.Protocol
+		}
+		if port.Port != nil {
+			converted.Port = *port.Port
+		}
+		if port.EndPort != nil {
+			converted.EndPort = int(*port.EndPort)
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+func convertAction(action *string) (v1alpha1.RuleAction, error) {
+	if action == nil {
+		return "", fmt.Errorf("action is required")
+	}
+	switch *action {
+	case antreaActionAllow:
+		return v1alpha1.RuleActionAllow, nil
+	case antreaActionDrop:
+		return v1alpha1.RuleActionDrop, nil
+	case antreaActionReject:
+		return v1alpha1.RuleActionReject, nil
+	}
+	return "", fmt.Errorf("action %s is not supported", *action)
+}
+
+// convertRule converts an Antrea rule to the SecurityPolicy rule for the Namespace with the labels nsLabels,
+// it returns nil if the rule level appliedTo doesn't select the Namespace.
+func convertRule(rule *antreaRule, direction v1alpha1.RuleDirection, nsLabels labels.Set, clusterScoped bool) (*v1alpha1.SecurityPolicyRule, error) {
+	if len(rule.ToService) > 0 || len(rule.L7) > 0 || len(rule.Protocols) > 0 {
+		return nil, fmt.Errorf("toServices, l7Protocols and protocols are not supported")
+	}
+	action, err := convertAction(rule.Action)
+	if err != nil {
+		return nil, err
+	}
+	converted := &v1alpha1.SecurityPolicyRule{
+		Name:      rule.Name,
+		Action:    &action,
+		Direction: &direction,
+		Ports:     convertPorts(rule.Ports),
+	}
+	if len(rule.AppliedTo) > 0 {
+		targets, matched, err := convertAppliedTo(rule.AppliedTo, nsLabels)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return nil, nil
+		}
+		converted.AppliedTo = targets
+	}
+	if direction == v1alpha1.RuleDirectionIn {
+		converted.Sources, err = convertPeers(rule.From, clusterScoped)
+	} else {
+		converted.Destinations, err = convertPeers(rule.To, clusterScoped)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// convertSpec converts the spec of an Antrea policy to the SecurityPolicy spec for the Namespace with the
+// labels nsLabels, nsLabels is nil for an Antrea NetworkPolicy. It returns nil if neither the policy nor
+// any rule is applied to the Namespace. A rule which can't be converted fails the whole conversion, since
+// dropping a rule may allow or drop more traffic than the Antrea policy.
+func convertSpec(spec *antreaPolicySpec, nsLabels labels.Set, clusterScoped bool) (*v1alpha1.SecurityPolicySpec, []string, error) {
+	priority, warnings := convertPriority(spec.Tier, spec.Priority)
+	result := &v1alpha1.SecurityPolicySpec{Priority: priority}
+	if len(spec.AppliedTo) > 0 {
+		targets, matched, err := convertAppliedTo(spec.AppliedTo, nsLabels)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !matched {
+			return nil, warnings, nil
+		}
+		result.AppliedTo = targets
+	}
+	for _, rules := range []struct {
+		direction v1alpha1.RuleDirection
+		rules     []antreaRule
+	}{{v1alpha1.RuleDirectionIn, spec.Ingress}, {v1alpha1.RuleDirectionOut, spec.Egress}} {
+		for i := range rules.rules {
+			rule, err := convertRule(&rules.rules[i], rules.direction, nsLabels, clusterScoped)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s rule %d: %w", rules.direction, i, err)
+			}
+			if rule != nil {
+				result.Rules = append(result.Rules, *rule)
+			}
+		}
+	}
+	if len(result.Rules) == 0 {
+		return nil, warnings, nil
+	}
+	return result, warnings, nil
+}
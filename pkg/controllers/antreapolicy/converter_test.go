@@ -0,0 +1,140 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package antreapolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestConvertPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		tier     string
+		priority float64
+		expected int
+		warnings int
+	}{
+		{name: "default tier", priority: 5.5, expected: 405},
+		{name: "emergency", tier: "emergency", priority: 1, expected: 1},
+		{name: "baseline", tier: "baseline", priority: 10, expected: 910},
+		{name: "capped", tier: "securityops", priority: 1000, expected: 199, warnings: 1},
+		{name: "custom tier", tier: "mytier", priority: 1, expected: 401, warnings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, warnings := convertPriority(tt.tier, tt.priority)
+			assert.Equal(t, tt.expected, priority)
+			assert.Len(t, warnings, tt.warnings)
+		})
+	}
+}
+
+func TestConvertSpec(t *testing.T) {
+	allow, drop := antreaActionAllow, antreaActionDrop
+	port := intstr.FromInt(80)
+	endPort := int32(90)
+	tcp := v1.ProtocolTCP
+	webSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	prodSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	spec := &antreaPolicySpec{
+		Tier:      "securityops",
+		Priority:  5,
+		AppliedTo: []antreaAppliedTo{{PodSelector: webSelector, NamespaceSelector: prodSelector}},
+		Ingress: []antreaRule{{
+			Name:   "from-lb",
+			Action: &allow,
+			From: []antreaPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "lb"}}},
+				{Namespaces: &antreaPeerNamespaces{Match: antreaNamespaceMatchSelf}},
+				{IPBlock: &antreaIPBlock{CIDR: "10.0.0.0/24"}},
+			},
+			Ports: []antreaPort{{Protocol: &tcp, Port: &port, EndPort: &endPort}},
+		}},
+		Egress: []antreaRule{{Action: &drop}},
+	}
+
+	converted, warnings, err := convertSpec(spec, labels.Set{"env": "prod"}, true)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+	in, out, allowAction, dropAction := v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionOut, v1alpha1.RuleActionAllow, v1alpha1.RuleActionDrop
+	expected := &v1alpha1.SecurityPolicySpec{
+		Priority:  105,
+		AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: webSelector}},
+		Rules: []v1alpha1.SecurityPolicyRule{
+			{
+				Name:      "from-lb",
+				Action:    &allowAction,
+				Direction: &in,
+				Sources: []v1alpha1.SecurityPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "lb"}}, NamespaceSelector: &metav1.LabelSelector{}},
+					{PodSelector: &metav1.LabelSelector{}},
+					{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}},
+				},
+				Ports: []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolTCP, Port: port, EndPort: 90}},
+			},
+			{Action: &dropAction, Direction: &out},
+		},
+	}
+	assert.Equal(t, expected, converted)
+
+	// the Namespace is not selected by the appliedTo
+	converted, _, err = convertSpec(spec, labels.Set{"env": "dev"}, true)
+	assert.NoError(t, err)
+	assert.Nil(t, converted)
+
+	// the podSelector of an Antrea NetworkPolicy selects the Pods in its Namespace
+	spec.AppliedTo = []antreaAppliedTo{{PodSelector: webSelector}}
+	converted, _, err = convertSpec(spec, nil, false)
+	assert.NoError(t, err)
+	assert.Nil(t, converted.Rules[0].Sources[0].NamespaceSelector)
+}
+
+func TestConvertSpecUnsupported(t *testing.T) {
+	allow, pass := antreaActionAllow, "Pass"
+	tests := []struct {
+		name   string
+		spec   *antreaPolicySpec
+		errMsg string
+	}{
+		{
+			name:   "pass action",
+			spec:   &antreaPolicySpec{Ingress: []antreaRule{{Action: &pass}}},
+			errMsg: "action Pass is not supported",
+		},
+		{
+			name:   "fqdn",
+			spec:   &antreaPolicySpec{Egress: []antreaRule{{Action: &allow, To: []antreaPeer{{FQDN: "*.example.com"}}}}},
+			errMsg: "fqdn *.example.com is not supported",
+		},
+		{
+			name:   "group",
+			spec:   &antreaPolicySpec{AppliedTo: []antreaAppliedTo{{Group: "web"}}},
+			errMsg: "only podSelector and namespaceSelector are supported",
+		},
+		{
+			name:   "namespaces sameLabels",
+			spec:   &antreaPolicySpec{Ingress: []antreaRule{{Action: &allow, From: []antreaPeer{{Namespaces: &antreaPeerNamespaces{}}}}}},
+			errMsg: "only namespaces match Self is supported",
+		},
+		{
+			name:   "toServices",
+			spec:   &antreaPolicySpec{Egress: []antreaRule{{Action: &allow, ToService: []struct{}{{}}}}},
+			errMsg: "toServices, l7Protocols and protocols are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := convertSpec(tt.spec, nil, false)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// adminVerbs maps the HTTP methods of the admin API to the verbs authorized on its non-resource URLs.
+var adminVerbs = map[string]string{
+	http.MethodGet:  "get",
+	http.MethodPost: "create",
+}
+
+// AuthorizeAdminRequest authenticates the bearer token of the admin API request by a TokenReview, and
+// authorizes the user to access the path of the request by a SubjectAccessReview of the non-resource
+// URL, so the access is granted by RBAC, e.g. a ClusterRole with nonResourceURLs ["/admin/v1/*"].
+// It returns the HTTP status code of the rejection with the error.
+func AuthorizeAdminRequest(ctx context.Context, c client.Client, req *http.Request) (int, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, errors.New("bearer token is required")
+	}
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := c.Create(ctx, review); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return http.StatusUnauthorized, errors.New("invalid bearer token")
+	}
+
+	verb, ok := adminVerbs[req.Method]
+	if !ok {
+		return http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", req.Method)
+	}
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:                  user.Username,
+		UID:                   user.UID,
+		Groups:                user.Groups,
+		Extra:                 extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: req.URL.Path, Verb: verb},
+	}}
+	if err := c.Create(ctx, access); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review access: %w", err)
+	}
+	if !access.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %s is not allowed to %s %s", user.Username, verb, req.URL.Path)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mock_client "github.com/vmware-tanzu/nsx-operator/pkg/mock/controller-runtime/client"
+)
+
+func TestAuthorizeAdminRequest(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	k8sClient := mock_client.NewMockClient(mockCtl)
+	ctx := context.TODO()
+
+	// the request without bearer token is rejected
+	req := httptest.NewRequest(http.MethodGet, "/admin/v1/securitypolicies/realized", nil)
+	code, err := AuthorizeAdminRequest(ctx, k8sClient, req)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Error(t, err)
+
+	allowed := false
+	k8sClient.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+		switch review := obj.(type) {
+		case *authenticationv1.TokenReview:
+			assert.Equal(t, "token1", review.Spec.Token)
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "admin", Groups: []string{"support"}}
+		case *authorizationv1.SubjectAccessReview:
+			assert.Equal(t, "admin", review.Spec.User)
+			assert.Equal(t, &authorizationv1.NonResourceAttributes{Path: "/admin/v1/securitypolicies/realized", Verb: "get"}, review.Spec.NonResourceAttributes)
+			review.Status.Allowed = allowed
+		}
+		return nil
+	}).AnyTimes()
+
+	req.Header.Set("Authorization", "Bearer token1")
+	code, err = AuthorizeAdminRequest(ctx, k8sClient, req)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.EqualError(t, err, "user admin is not allowed to get /admin/v1/securitypolicies/realized")
+
+	allowed = true
+	code, err = AuthorizeAdminRequest(ctx, k8sClient, req)
+	assert.Equal(t, http.StatusOK, code)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,118 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	// ConditionNSXCapacityPressure is the condition of the NSXOperatorConfig CR reporting whether the usage of
+	// an NSX object type approaches its max supported count.
+	ConditionNSXCapacityPressure v1alpha1.ConditionType = "NSXCapacityPressure"
+	ReasonNSXCapacityWarning                            = "NSXCapacityWarning"
+	ReasonNSXCapacityExceeded                           = "NSXCapacityExceeded"
+	ReasonNSXCapacityAvailable                          = "NSXCapacityAvailable"
+)
+
+// CapacityMonitor polls the usage of the NSX object types, e.g. the groups, the firewall rules and the load
+// balancer objects, from the NSX capacity dashboard into servicecommon.Capacity, which refuses the SecurityPolicies
+// and the GatewayPolicies consuming the safety margin kept below the max supported counts. The usage is reported
+// by the nsx_capacity_usage_percent and nsx_capacity_pressure metrics, and the usage types approaching their max
+// by the NSXCapacityPressure condition of the NSXOperatorConfig CR. It is added to the manager to only run on
+// the leader.
+type CapacityMonitor struct {
+	Client    client.Client
+	NSXConfig *config.NSXOperatorConfig
+	// Check returns the usage of the capacity dashboard.
+	Check    func() ([]model.CapacityDashboardUsage, error)
+	Interval time.Duration
+
+	// message is the message of the condition last reported.
+	message string
+}
+
+func (m *CapacityMonitor) Start(ctx context.Context) error {
+	for {
+		m.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(m.Interval):
+		}
+	}
+}
+
+// NeedLeaderElection returns true, only the leader writes to NSX.
+func (m *CapacityMonitor) NeedLeaderElection() bool {
+	return true
+}
+
+func (m *CapacityMonitor) poll(ctx context.Context) {
+	usages, err := m.Check()
+	if err != nil {
+		// the last usage is kept until it's known
+		log.Error(err, "failed to get NSX capacity usage")
+		return
+	}
+	servicecommon.Capacity.Update(usages, m.NSXConfig.CapacitySafetyMargin)
+	for _, usage := range servicecommon.Capacity.Usages() {
+		pressure := 0.0
+		if usage.MarginExceeded {
+			pressure = 2
+		} else if usage.Percent() >= usage.WarningPercent {
+			pressure = 1
+		}
+		metrics.GaugeSet(m.NSXConfig, metrics.NSXCapacityUsagePercent, usage.Percent(), usage.UsageType)
+		metrics.GaugeSet(m.NSXConfig, metrics.NSXCapacityPressure, pressure, usage.UsageType)
+	}
+	m.updateCondition(ctx, servicecommon.Capacity.Pressure())
+}
+
+// updateCondition reports the usage types under pressure on the NSXOperatorConfig CR if they changed.
+func (m *CapacityMonitor) updateCondition(ctx context.Context, pressure []servicecommon.CapacityUsage) {
+	condition := v1alpha1.Condition{
+		Type:               ConditionNSXCapacityPressure,
+		Status:             v1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNSXCapacityAvailable,
+		Message:            "NSX capacity is available",
+	}
+	if len(pressure) > 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = ReasonNSXCapacityWarning
+		usages := make([]string, 0, len(pressure))
+		for _, usage := range pressure {
+			if usage.MarginExceeded {
+				condition.Reason = ReasonNSXCapacityExceeded
+			}
+			usages = append(usages, fmt.Sprintf("%s %d of %d", usage.UsageType, usage.Current, usage.Max))
+		}
+		condition.Message = "NSX capacity is approaching its max: " + strings.Join(usages, ", ")
+		if condition.Reason == ReasonNSXCapacityExceeded {
+			condition.Message += ", the objects consuming the safety margin are refused"
+		}
+	}
+	if condition.Message == m.message {
+		return
+	}
+	if condition.Status == v1.ConditionTrue {
+		log.Info("NSX capacity pressure", "message", condition.Message)
+	}
+	m.message = condition.Message
+	updateOperatorConfigCondition(ctx, m.Client, condition)
+}
@@ -0,0 +1,83 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestCapacityMonitor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	operatorConfig := &v1alpha1.NSXOperatorConfig{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.NSXOperatorConfigName}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operatorConfig).
+		WithStatusSubresource(operatorConfig).Build()
+	ctx := context.TODO()
+	defer servicecommon.Capacity.Update(nil, 0)
+
+	groups, checkErr := int64(100), error(nil)
+	m := &CapacityMonitor{
+		Client:    k8sClient,
+		NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{CapacitySafetyMargin: 20}},
+		Check: func() ([]model.CapacityDashboardUsage, error) {
+			return []model.CapacityDashboardUsage{{
+				UsageType:              pointy.String(servicecommon.CapacityUsageGroups),
+				CurrentUsageCount:      pointy.Int64(groups),
+				MaxSupportedCount:      pointy.Int64(1000),
+				MinThresholdPercentage: pointy.Float64(70),
+			}}, checkErr
+		},
+	}
+	getCondition := func() *v1alpha1.Condition {
+		obj := &v1alpha1.NSXOperatorConfig{}
+		assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: v1alpha1.NSXOperatorConfigName}, obj))
+		for i := range obj.Status.Conditions {
+			if obj.Status.Conditions[i].Type == ConditionNSXCapacityPressure {
+				return &obj.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	m.poll(ctx)
+	assert.Equal(t, v1.ConditionFalse, getCondition().Status)
+	assert.NoError(t, servicecommon.Capacity.Reserve(map[string]int64{servicecommon.CapacityUsageGroups: 600}))
+
+	// the usage approaches the max
+	groups = 750
+	m.poll(ctx)
+	condition := getCondition()
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonNSXCapacityWarning, condition.Reason)
+	assert.Equal(t, "NSX capacity is approaching its max: NUMBER_OF_GROUPS 750 of 1000", condition.Message)
+
+	// the usage is kept if NSX is unreachable
+	checkErr = errors.New("connection refused")
+	groups = 0
+	m.poll(ctx)
+	assert.Error(t, servicecommon.Capacity.Reserve(map[string]int64{servicecommon.CapacityUsageGroups: 51}))
+
+	// the safety margin is consumed, the groups are refused
+	checkErr = nil
+	groups = 800
+	m.poll(ctx)
+	condition = getCondition()
+	assert.Equal(t, ReasonNSXCapacityExceeded, condition.Reason)
+	assert.Error(t, servicecommon.Capacity.Reserve(map[string]int64{servicecommon.CapacityUsageGroups: 1}))
+}
@@ -0,0 +1,138 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const (
+	// AnnotationConfirmMassDeletion is annotated on the nsx-operator namespace with the count of the
+	// deletions confirmed to proceed when a bulk deletion is paused.
+	AnnotationConfirmMassDeletion = "nsx.vmware.com/confirm_mass_deletion"
+	defaultMassDeletionWindow     = 10 * time.Minute
+)
+
+// DeletionGuard pauses the bulk deletion of the NSX resources of a type when the deletions in the
+// window configured by mass_deletion_window exceed mass_deletion_max_count and are more than
+// mass_deletion_max_percent of the NSX resources, e.g. the CRs appear gone due to an apiserver hiccup.
+// A paused deletion is alerted by an event on the nsx-operator namespace and the mass_deletion_paused
+// metric, it proceeds once the namespace is annotated with nsx.vmware.com/confirm_mass_deletion set to
+// at least the count of the deletions.
+type DeletionGuard struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+	client    client.Client
+	recorder  record.EventRecorder
+	namespace string
+
+	mu sync.Mutex
+	// deletions are the deletions allowed in the window.
+	deletions []deletion
+	now       func() time.Time
+}
+
+type deletion struct {
+	time  time.Time
+	count int
+}
+
+// NewDeletionGuard creates a guard for the resource type, which is confirmed on the namespace.
+func NewDeletionGuard(resType string, cf *config.NSXOperatorConfig, c client.Client, recorder record.EventRecorder, namespace string) *DeletionGuard {
+	return &DeletionGuard{
+		resType:   resType,
+		nsxConfig: cf,
+		client:    c,
+		recorder:  recorder,
+		namespace: namespace,
+		now:       time.Now,
+	}
+}
+
+// Allow returns nil if the pending deletions of the total NSX resources can proceed, and records them.
+// Otherwise the deletions are alerted, and an error is returned until they are confirmed. Allow is no-op
+// on a nil guard.
+func (g *DeletionGuard) Allow(ctx context.Context, pending, total int) error {
+	if g == nil || pending == 0 {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	window := g.window()
+	recent := 0
+	deletions := g.deletions[:0]
+	for _, d := range g.deletions {
+		if now.Sub(d.time) < window {
+			deletions = append(deletions, d)
+			recent += d.count
+		}
+	}
+	g.deletions = deletions
+
+	if !g.exceeded(recent+pending, recent+total) {
+		g.record(now, pending)
+		return nil
+	}
+	ns := &v1.Namespace{}
+	if err := g.client.Get(ctx, types.NamespacedName{Name: g.namespace}, ns); err != nil {
+		return err
+	}
+	if confirmed, err := strconv.Atoi(ns.Annotations[AnnotationConfirmMassDeletion]); err == nil && confirmed >= pending {
+		// the confirmation is consumed, the next bulk deletion needs to be confirmed again
+		patch := client.MergeFrom(ns.DeepCopy())
+		delete(ns.Annotations, AnnotationConfirmMassDeletion)
+		if err := g.client.Patch(ctx, ns, patch); err != nil {
+			return err
+		}
+		log.Info("mass deletion confirmed", "type", g.resType, "deletions", pending)
+		g.recorder.Eventf(ns, v1.EventTypeNormal, ReasonMassDeletionConfirmed, "Deleting %d NSX %s resources is confirmed", pending, g.resType)
+		g.deletions = nil
+		g.record(now, pending)
+		return nil
+	}
+	err := fmt.Errorf("deleting %d of %d NSX %s resources with %d deleted in last %s is paused, annotate namespace %s with %s=%d to proceed",
+		pending, total, g.resType, recent, window, g.namespace, AnnotationConfirmMassDeletion, pending)
+	log.Error(err, "mass deletion paused", "type", g.resType)
+	g.recorder.Event(ns, v1.EventTypeWarning, ReasonMassDeletionPaused, err.Error())
+	metrics.GaugeSet(g.nsxConfig, metrics.MassDeletionPaused, float64(pending), g.resType)
+	return err
+}
+
+func (g *DeletionGuard) record(now time.Time, pending int) {
+	g.deletions = append(g.deletions, deletion{time: now, count: pending})
+	metrics.GaugeSet(g.nsxConfig, metrics.MassDeletionPaused, 0, g.resType)
+}
+
+// exceeded returns whether the deletions of the resources exceed the thresholds configured by
+// mass_deletion_max_count and mass_deletion_max_percent.
+func (g *DeletionGuard) exceeded(deletions, resources int) bool {
+	if g.nsxConfig == nil || g.nsxConfig.K8sConfig == nil {
+		return false
+	}
+	maxCount, maxPercent := g.nsxConfig.MassDeletionMaxCount, g.nsxConfig.MassDeletionMaxPercent
+	if maxCount <= 0 && maxPercent <= 0 {
+		return false
+	}
+	return deletions > maxCount && deletions*100 > maxPercent*resources
+}
+
+func (g *DeletionGuard) window() time.Duration {
+	if g.nsxConfig != nil && g.nsxConfig.K8sConfig != nil && g.nsxConfig.MassDeletionWindow > 0 {
+		return time.Duration(g.nsxConfig.MassDeletionWindow) * time.Second
+	}
+	return defaultMassDeletionWindow
+}
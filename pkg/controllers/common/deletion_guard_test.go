@@ -0,0 +1,77 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestDeletionGuard(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system"}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{}}
+	g := NewDeletionGuard(MetricResTypeSecurityPolicy, cf, k8sClient, recorder, "nsx-system")
+	now := time.Now()
+	g.now = func() time.Time { return now }
+	ctx := context.TODO()
+
+	// the guard is disabled
+	assert.NoError(t, g.Allow(ctx, 10, 10))
+
+	// the deletions not exceeding both the count and the percentage proceed
+	cf.MassDeletionMaxCount = 2
+	cf.MassDeletionMaxPercent = 20
+	g.deletions = nil
+	assert.NoError(t, g.Allow(ctx, 2, 100))
+	assert.NoError(t, g.Allow(ctx, 3, 100))
+
+	// the deletions in the window are accumulated
+	now = now.Add(time.Minute)
+	err := g.Allow(ctx, 16, 95)
+	assert.EqualError(t, err, "deleting 16 of 95 NSX securitypolicy resources with 5 deleted in last 10m0s is paused, "+
+		"annotate namespace nsx-system with nsx.vmware.com/confirm_mass_deletion=16 to proceed")
+	assert.Contains(t, <-recorder.Events, ReasonMassDeletionPaused)
+
+	// the deletions out of the window are not accumulated
+	now = now.Add(10 * time.Minute)
+	assert.NoError(t, g.Allow(ctx, 16, 95))
+
+	// the deletions proceed once confirmed, and the confirmation is consumed
+	now = now.Add(time.Minute)
+	assert.Error(t, g.Allow(ctx, 60, 79))
+	<-recorder.Events
+	ns := &v1.Namespace{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "nsx-system"}, ns))
+	ns.Annotations = map[string]string{AnnotationConfirmMassDeletion: "50"}
+	assert.NoError(t, k8sClient.Update(ctx, ns))
+	assert.Error(t, g.Allow(ctx, 60, 79))
+	<-recorder.Events
+	ns.Annotations[AnnotationConfirmMassDeletion] = "60"
+	assert.NoError(t, k8sClient.Update(ctx, ns))
+	assert.NoError(t, g.Allow(ctx, 60, 79))
+	assert.Contains(t, <-recorder.Events, ReasonMassDeletionConfirmed)
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "nsx-system"}, ns))
+	assert.NotContains(t, ns.Annotations, AnnotationConfirmMassDeletion)
+	assert.Error(t, g.Allow(ctx, 3, 19))
+
+	var nilGuard *DeletionGuard
+	assert.NoError(t, nilGuard.Allow(ctx, 10, 10))
+}
@@ -0,0 +1,180 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// maxRecentNSXAPIErrors is the count of the most recent NSX API errors kept for the diagnostics.
+const maxRecentNSXAPIErrors = 100
+
+const redacted = "<redacted>"
+
+var (
+	recentNSXAPIErrorsLock = &sync.Mutex{}
+	recentNSXAPIErrors     []NSXAPIErrorRecord
+)
+
+// NSXAPIErrorRecord is an NSX API error returned to a controller.
+type NSXAPIErrorRecord struct {
+	Time      time.Time `json:"time"`
+	ResType   string    `json:"resType"`
+	ErrorCode int64     `json:"errorCode"`
+	Module    string    `json:"module,omitempty"`
+	Error     string    `json:"error"`
+}
+
+func recordRecentNSXAPIError(resType string, apiErr *nsxutil.APIError) {
+	recentNSXAPIErrorsLock.Lock()
+	defer recentNSXAPIErrorsLock.Unlock()
+	recentNSXAPIErrors = append(recentNSXAPIErrors, NSXAPIErrorRecord{
+		Time:      time.Now(),
+		ResType:   resType,
+		ErrorCode: apiErr.ErrorCode,
+		Module:    apiErr.ModuleName,
+		Error:     apiErr.Error(),
+	})
+	if len(recentNSXAPIErrors) > maxRecentNSXAPIErrors {
+		recentNSXAPIErrors = recentNSXAPIErrors[len(recentNSXAPIErrors)-maxRecentNSXAPIErrors:]
+	}
+}
+
+// RecentNSXAPIErrors returns the most recent NSX API errors returned to the controllers, oldest first.
+func RecentNSXAPIErrors() []NSXAPIErrorRecord {
+	recentNSXAPIErrorsLock.Lock()
+	defer recentNSXAPIErrorsLock.Unlock()
+	return append([]NSXAPIErrorRecord(nil), recentNSXAPIErrors...)
+}
+
+// runtimeStats is the summary of the Go runtime collected in the diagnostics.
+type runtimeStats struct {
+	GoVersion     string  `json:"goVersion"`
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+	Goroutines    int     `json:"goroutines"`
+	HeapAlloc     uint64  `json:"heapAlloc"`
+	HeapObjects   uint64  `json:"heapObjects"`
+	NumGC         uint32  `json:"numGC"`
+}
+
+// WriteDiagnostics writes the diagnostics of nsx-operator for the support cases to w as a gzipped tar archive:
+// the goroutine dump and the heap profile, the runtime stats, the NSX objects in the stores of the counters,
+// the recent NSX API errors, the states of the reconcile queues and the config with the passwords redacted.
+func WriteDiagnostics(w io.Writer, cf *config.NSXOperatorConfig, counters []servicecommon.ObjectCounter) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	addFile := func(name string, write func(w io.Writer) error) error {
+		buf := &bytes.Buffer{}
+		if err := write(buf); err != nil {
+			return fmt.Errorf("failed to collect %s: %w", name, err)
+		}
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(buf.Len()), ModTime: now}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(buf.Bytes())
+		return err
+	}
+	addJSON := func(name string, v interface{}) error {
+		return addFile(name, func(w io.Writer) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(v)
+		})
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	objectCounts := map[string]servicecommon.ObjectCounts{}
+	for _, counter := range counters {
+		for objType, objCounts := range counter.CountObjects() {
+			if _, ok := objectCounts[objType]; !ok {
+				objectCounts[objType] = servicecommon.ObjectCounts{}
+			}
+			for ns, count := range objCounts {
+				objectCounts[objType][ns] += count
+			}
+		}
+	}
+	redactedConfig, err := redactConfig(cf)
+	if err != nil {
+		return err
+	}
+	for _, add := range []func() error{
+		func() error {
+			return addFile("goroutines.txt", func(w io.Writer) error { return pprof.Lookup("goroutine").WriteTo(w, 2) })
+		},
+		func() error {
+			return addFile("heap.pprof", func(w io.Writer) error { return pprof.Lookup("heap").WriteTo(w, 0) })
+		},
+		func() error {
+			return addJSON("runtime.json", runtimeStats{
+				GoVersion:     runtime.Version(),
+				UptimeSeconds: now.Sub(processStart).Seconds(),
+				Goroutines:    runtime.NumGoroutine(),
+				HeapAlloc:     memStats.HeapAlloc,
+				HeapObjects:   memStats.HeapObjects,
+				NumGC:         memStats.NumGC,
+			})
+		},
+		func() error { return addJSON("stores.json", objectCounts) },
+		func() error { return addJSON("nsx_api_errors.json", RecentNSXAPIErrors()) },
+		func() error { return addJSON("queues.json", ReconcileQueueStates()) },
+		func() error { return addJSON("config.json", redactedConfig) },
+	} {
+		if err := add(); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// redactConfig returns the config as a JSON object with the values of the password fields redacted.
+func redactConfig(cf *config.NSXOperatorConfig) (interface{}, error) {
+	data, err := json.Marshal(cf)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var redact func(v interface{})
+	redact = func(v interface{}) {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			for k, item := range value {
+				if strings.Contains(strings.ToLower(k), "password") {
+					value[k] = redacted
+					continue
+				}
+				redact(item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				redact(item)
+			}
+		}
+	}
+	redact(v)
+	return v, nil
+}
@@ -0,0 +1,67 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func TestWriteDiagnostics(t *testing.T) {
+	recentNSXAPIErrors = nil
+	defer func() { recentNSXAPIErrors = nil }()
+	for i := 0; i < maxRecentNSXAPIErrors+1; i++ {
+		RecordNSXAPIError(nil, MetricResTypeSecurityPolicy, &nsxutil.APIError{APIErrorDetail: nsxutil.APIErrorDetail{ErrorCode: int64(i), ModuleName: "policy"}})
+	}
+	errs := RecentNSXAPIErrors()
+	assert.Len(t, errs, maxRecentNSXAPIErrors)
+	assert.Equal(t, int64(1), errs[0].ErrorCode)
+
+	cf := &config.NSXOperatorConfig{
+		NsxConfig: &config.NsxConfig{NsxApiUser: "admin", NsxApiPassword: "secret"},
+		VCConfig:  &config.VCConfig{VCUser: "admin", VCPassword: "secret"},
+	}
+	counters := []servicecommon.ObjectCounter{fakeObjectCounter{"Group": {"ns1": 2}}, fakeObjectCounter{"Group": {"ns1": 1}}}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteDiagnostics(buf, cf, counters))
+
+	gz, err := gzip.NewReader(buf)
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		files[header.Name], _ = io.ReadAll(tr)
+	}
+	assert.Len(t, files, 7)
+	assert.Contains(t, string(files["goroutines.txt"]), "TestWriteDiagnostics")
+	assert.NotEmpty(t, files["heap.pprof"])
+	assert.JSONEq(t, `{"Group":{"ns1":3}}`, string(files["stores.json"]))
+
+	var apiErrors []NSXAPIErrorRecord
+	assert.NoError(t, json.Unmarshal(files["nsx_api_errors.json"], &apiErrors))
+	assert.Len(t, apiErrors, maxRecentNSXAPIErrors)
+
+	// the passwords are redacted
+	assert.NotContains(t, string(files["config.json"]), "secret")
+	var redactedConfig map[string]interface{}
+	assert.NoError(t, json.Unmarshal(files["config.json"], &redactedConfig))
+	assert.Equal(t, "admin", redactedConfig["NsxApiUser"])
+	assert.Equal(t, redacted, redactedConfig["NsxApiPassword"])
+	assert.Equal(t, redacted, redactedConfig["VCPassword"])
+}
@@ -0,0 +1,161 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const EnforcementReportInterval = 5 * time.Minute
+
+// EnforcementReporter periodically generates the cluster-scoped EnforcementReport, which summarizes the
+// policies per Namespace, their realization, the Namespaces without any policy and the NSX objects created
+// by nsx-operator, for the compliance dashboards. It is added to the manager to only run on the leader.
+type EnforcementReporter struct {
+	Client   client.Client
+	Reader   client.Reader
+	Counters []servicecommon.ObjectCounter
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// Generate builds the report from the Namespaces, the SecurityPolicies and the NetworkPolicies in the cluster,
+// the Namespaces being deleted are skipped.
+func (r *EnforcementReporter) Generate(ctx context.Context) (*v1alpha1.EnforcementReportStatus, error) {
+	nsList := &v1.NamespaceList{}
+	if err := r.Reader.List(ctx, nsList); err != nil {
+		return nil, err
+	}
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Reader.List(ctx, spList); err != nil {
+		return nil, err
+	}
+	npList := &networkingv1.NetworkPolicyList{}
+	if err := r.Reader.List(ctx, npList); err != nil {
+		return nil, err
+	}
+
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	status := &v1alpha1.EnforcementReportStatus{GeneratedAt: metav1.NewTime(now())}
+	namespaces := map[string]*v1alpha1.NamespaceEnforcement{}
+	for _, ns := range nsList.Items {
+		if ns.DeletionTimestamp.IsZero() {
+			namespaces[ns.Name] = &v1alpha1.NamespaceEnforcement{Namespace: ns.Name}
+		}
+	}
+	for i := range spList.Items {
+		sp := &spList.Items[i]
+		ready := isSecurityPolicyReady(sp)
+		if ready {
+			status.Realization.Ready++
+		} else {
+			status.Realization.NotReady++
+		}
+		if entry, ok := namespaces[sp.Namespace]; ok {
+			entry.SecurityPolicies++
+			if !ready {
+				entry.NotReadySecurityPolicies++
+			}
+		}
+	}
+	for _, np := range npList.Items {
+		if entry, ok := namespaces[np.Namespace]; ok {
+			entry.NetworkPolicies++
+		}
+	}
+
+	counts := (&ObjectCountReporter{Counters: r.Counters}).Count()
+	if len(counts) > 0 {
+		status.NSXObjects = map[string]int{}
+	}
+	for objType, objCounts := range counts {
+		status.NSXObjects[objType] = objCounts.Total()
+		for ns, count := range objCounts {
+			entry, ok := namespaces[ns]
+			if !ok {
+				continue
+			}
+			if entry.NSXObjects == nil {
+				entry.NSXObjects = map[string]int{}
+			}
+			entry.NSXObjects[objType] = count
+		}
+	}
+
+	for _, entry := range namespaces {
+		status.Namespaces = append(status.Namespaces, *entry)
+		if entry.SecurityPolicies == 0 && entry.NetworkPolicies == 0 {
+			status.UnprotectedNamespaces = append(status.UnprotectedNamespaces, entry.Namespace)
+		}
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+	sort.Strings(status.UnprotectedNamespaces)
+	return status, nil
+}
+
+func isSecurityPolicyReady(sp *v1alpha1.SecurityPolicy) bool {
+	for _, condition := range sp.Status.Conditions {
+		if condition.Type == v1alpha1.Ready {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Report generates the report and writes it to the status of the EnforcementReport, which is created if
+// it doesn't exist.
+func (r *EnforcementReporter) Report(ctx context.Context) error {
+	status, err := r.Generate(ctx)
+	if err != nil {
+		return err
+	}
+	report := &v1alpha1.EnforcementReport{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: v1alpha1.EnforcementReportName}, report); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		report = &v1alpha1.EnforcementReport{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.EnforcementReportName}}
+		if err := r.Client.Create(ctx, report); err != nil {
+			return err
+		}
+	}
+	report.Status = *status
+	return r.Client.Status().Update(ctx, report)
+}
+
+// Start implements manager.Runnable, it reports periodically until ctx is done.
+func (r *EnforcementReporter) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval == 0 {
+		interval = EnforcementReportInterval
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		if err := r.Report(ctx); err != nil {
+			log.Error(err, "failed to generate enforcement report")
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestEnforcementReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	readySP := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "ready"},
+		Status: v1alpha1.SecurityPolicyStatus{
+			Conditions: []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}},
+		},
+	}
+	failedSP := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "failed"},
+		Status: v1alpha1.SecurityPolicyStatus{
+			Conditions: []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionFalse}},
+		},
+	}
+	np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "np"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-2"}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-3"}},
+			readySP, failedSP, np).
+		WithStatusSubresource(&v1alpha1.EnforcementReport{}).Build()
+	generatedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	reporter := &EnforcementReporter{
+		Client: k8sClient,
+		Reader: k8sClient,
+		Counters: []servicecommon.ObjectCounter{
+			fakeObjectCounter{servicecommon.ObjectTypeRule: {"ns-1": 3, "ns-2": 2, "": 1}},
+		},
+		now: func() time.Time { return generatedAt },
+	}
+
+	ctx := context.TODO()
+	assert.NoError(t, reporter.Report(ctx))
+	report := &v1alpha1.EnforcementReport{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: v1alpha1.EnforcementReportName}, report))
+	assert.True(t, generatedAt.Equal(report.Status.GeneratedAt.Time))
+	assert.Equal(t, v1alpha1.RealizationSummary{Ready: 1, NotReady: 1}, report.Status.Realization)
+	assert.Equal(t, []string{"ns-3"}, report.Status.UnprotectedNamespaces)
+	assert.Equal(t, map[string]int{servicecommon.ObjectTypeRule: 6}, report.Status.NSXObjects)
+	assert.Equal(t, []v1alpha1.NamespaceEnforcement{
+		{Namespace: "ns-1", SecurityPolicies: 2, NotReadySecurityPolicies: 1, NSXObjects: map[string]int{servicecommon.ObjectTypeRule: 3}},
+		{Namespace: "ns-2", NetworkPolicies: 1, NSXObjects: map[string]int{servicecommon.ObjectTypeRule: 2}},
+		{Namespace: "ns-3"},
+	}, report.Status.Namespaces)
+
+	// the existing report is updated
+	assert.NoError(t, k8sClient.Delete(ctx, np))
+	assert.NoError(t, reporter.Report(ctx))
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: v1alpha1.EnforcementReportName}, report))
+	assert.Equal(t, []string{"ns-2", "ns-3"}, report.Status.UnprotectedNamespaces)
+}
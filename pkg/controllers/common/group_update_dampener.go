@@ -0,0 +1,119 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// GroupUpdateDampener dampens the updates of the IP address groups of a policy, e.g. the groups of the named
+// ports and the workload peers, which follow the IPs of the Pods. The Pod events in the batch window configured
+// by group_update_batch_window are batched into a single reconcile of the policy, and the reconciles of a
+// policy are at least group_update_min_interval apart, so scaling a Deployment by hundreds of Pods per minute
+// doesn't patch the NSX groups hundreds of times. The changes of the policy itself are not dampened.
+type GroupUpdateDampener struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+
+	mu sync.Mutex
+	// scheduled is the time of the last scheduled reconcile of the policy, it's pending if in the future.
+	scheduled map[types.NamespacedName]time.Time
+	now       func() time.Time
+}
+
+// NewGroupUpdateDampener creates a dampener for the resource type.
+func NewGroupUpdateDampener(resType string, cf *config.NSXOperatorConfig) *GroupUpdateDampener {
+	return &GroupUpdateDampener{
+		resType:   resType,
+		nsxConfig: cf,
+		scheduled: make(map[types.NamespacedName]time.Time),
+		now:       time.Now,
+	}
+}
+
+// Delay returns how long the reconcile of the key triggered by a Pod event should be delayed. An event in the
+// batch window of a pending reconcile joins it, which is counted as dampened. Delay is 0 on a nil dampener.
+func (d *GroupUpdateDampener) Delay(key types.NamespacedName) time.Duration {
+	if d == nil {
+		return 0
+	}
+	batchWindow, minInterval := d.settings()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	last, ok := d.scheduled[key]
+	if ok && last.After(now) {
+		metrics.CounterInc(d.nsxConfig, metrics.ControllerGroupUpdateDampenedTotal, d.resType)
+		return last.Sub(now)
+	}
+	if batchWindow == 0 && minInterval == 0 {
+		delete(d.scheduled, key)
+		return 0
+	}
+	next := now.Add(batchWindow)
+	if ok && last.Add(minInterval).After(next) {
+		next = last.Add(minInterval)
+	}
+	d.scheduled[key] = next
+	return next.Sub(now)
+}
+
+// Forget removes the last scheduled reconcile of the key, e.g. the policy is deleted.
+func (d *GroupUpdateDampener) Forget(key types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.scheduled, key)
+}
+
+// Queue wraps the queue so the requests added to it are delayed by the dampener. The workqueue keeps the
+// earliest delay of the same request, so the delayed requests of a policy are reconciled once.
+func (d *GroupUpdateDampener) Queue(q workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+	if d == nil {
+		return q
+	}
+	return &dampenedQueue{RateLimitingInterface: q, dampener: d}
+}
+
+// settings returns the group_update_batch_window and group_update_min_interval, 0 if not configured.
+func (d *GroupUpdateDampener) settings() (batchWindow, minInterval time.Duration) {
+	if d.nsxConfig == nil || d.nsxConfig.K8sConfig == nil {
+		return 0, 0
+	}
+	if d.nsxConfig.GroupUpdateBatchWindow > 0 {
+		batchWindow = time.Duration(d.nsxConfig.GroupUpdateBatchWindow) * time.Second
+	}
+	if d.nsxConfig.GroupUpdateMinInterval > 0 {
+		minInterval = time.Duration(d.nsxConfig.GroupUpdateMinInterval) * time.Second
+	}
+	return batchWindow, minInterval
+}
+
+type dampenedQueue struct {
+	workqueue.RateLimitingInterface
+	dampener *GroupUpdateDampener
+}
+
+func (q *dampenedQueue) Add(item interface{}) {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		q.RateLimitingInterface.Add(item)
+		return
+	}
+	if wait := q.dampener.Delay(req.NamespacedName); wait > 0 {
+		q.RateLimitingInterface.AddAfter(item, wait)
+		return
+	}
+	q.RateLimitingInterface.Add(item)
+}
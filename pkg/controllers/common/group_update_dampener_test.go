@@ -0,0 +1,62 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestGroupUpdateDampener(t *testing.T) {
+	now := time.Now()
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{GroupUpdateBatchWindow: 5, GroupUpdateMinInterval: 30}}
+	d := NewGroupUpdateDampener(MetricResTypeSecurityPolicy, cf)
+	d.now = func() time.Time { return now }
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+
+	// the events in the batch window join the pending reconcile
+	assert.Equal(t, 5*time.Second, d.Delay(key))
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 3*time.Second, d.Delay(key))
+
+	// the next reconcile is at least the min interval after the last one
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, 23*time.Second, d.Delay(key))
+	now = now.Add(60 * time.Second)
+	assert.Equal(t, 5*time.Second, d.Delay(key))
+
+	// the dampening is disabled
+	cf.GroupUpdateBatchWindow = 0
+	cf.GroupUpdateMinInterval = 0
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), d.Delay(key))
+	assert.Empty(t, d.scheduled)
+}
+
+func TestGroupUpdateDampener_Queue(t *testing.T) {
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{GroupUpdateBatchWindow: 60}}
+	d := NewGroupUpdateDampener(MetricResTypeSecurityPolicy, cf)
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "sp1"}}
+
+	// the requests are delayed by the batch window
+	dq := d.Queue(q)
+	dq.Add(req)
+	dq.Add(req)
+	assert.Equal(t, 0, q.Len())
+
+	// the requests are not delayed by a nil dampener
+	var nilDampener *GroupUpdateDampener
+	nilDampener.Queue(q).Add(req)
+	assert.Equal(t, 1, q.Len())
+	nilDampener.Forget(req.NamespacedName)
+}
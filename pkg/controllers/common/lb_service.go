@@ -0,0 +1,42 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func isLoadBalancerService(obj client.Object) bool {
+	svc, ok := obj.(*v1.Service)
+	return ok && svc.Spec.Type == v1.ServiceTypeLoadBalancer
+}
+
+// PredicateFuncsLBService filters the events of the LoadBalancer Services which change the backends or the target
+// ports of the load balancer, the policies allowing its health checks to the backends are reconciled by them.
+var PredicateFuncsLBService = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return isLoadBalancerService(e.Object)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if !isLoadBalancerService(e.ObjectOld) && !isLoadBalancerService(e.ObjectNew) {
+			return false
+		}
+		oldObj := e.ObjectOld.(*v1.Service)
+		newObj := e.ObjectNew.(*v1.Service)
+		return oldObj.Spec.Type != newObj.Spec.Type ||
+			!reflect.DeepEqual(oldObj.Spec.Selector, newObj.Spec.Selector) ||
+			!reflect.DeepEqual(oldObj.Spec.Ports, newObj.Spec.Ports)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return isLoadBalancerService(e.Object)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
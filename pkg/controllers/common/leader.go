@@ -0,0 +1,40 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const (
+	LeaderEventAcquired = "acquired"
+	LeaderEventReleased = "released"
+)
+
+// LeaderElectionReporter exports the leader status of nsx-operator and counts the leadership transitions, so
+// a flapping leadership can be caught. It is added to the manager to only run on the leader, it's started
+// when the leadership is acquired and stopped when it's released.
+type LeaderElectionReporter struct {
+	NSXConfig *config.NSXOperatorConfig
+}
+
+// Start records the leadership is acquired, and released when the context is done.
+func (r *LeaderElectionReporter) Start(ctx context.Context) error {
+	log.Info("leadership acquired")
+	metrics.CounterIncWithLabels(r.NSXConfig, metrics.LeaderElectionTransitionsTotal, LeaderEventAcquired)
+	metrics.GaugeSet(r.NSXConfig, metrics.LeaderStatus, 1)
+	<-ctx.Done()
+	log.Info("leadership released")
+	metrics.CounterIncWithLabels(r.NSXConfig, metrics.LeaderElectionTransitionsTotal, LeaderEventReleased)
+	metrics.GaugeSet(r.NSXConfig, metrics.LeaderStatus, 0)
+	return nil
+}
+
+// NeedLeaderElection returns true, the reporter only runs on the leader.
+func (r *LeaderElectionReporter) NeedLeaderElection() bool {
+	return true
+}
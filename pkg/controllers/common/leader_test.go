@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+func metricValue(t *testing.T, metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	assert.NoError(t, metric.Write(m))
+	if m.Gauge != nil {
+		return m.Gauge.GetValue()
+	}
+	return m.Counter.GetValue()
+}
+
+func TestLeaderElectionReporter(t *testing.T) {
+	reporter := &LeaderElectionReporter{
+		NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"}},
+	}
+	assert.True(t, reporter.NeedLeaderElection())
+	acquired := metricValue(t, metrics.LeaderElectionTransitionsTotal.WithLabelValues(LeaderEventAcquired))
+	released := metricValue(t, metrics.LeaderElectionTransitionsTotal.WithLabelValues(LeaderEventReleased))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- reporter.Start(ctx) }()
+	assert.Eventually(t, func() bool {
+		return metricValue(t, metrics.LeaderStatus.WithLabelValues()) == 1
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, float64(0), metricValue(t, metrics.LeaderStatus.WithLabelValues()))
+	assert.Equal(t, acquired+1, metricValue(t, metrics.LeaderElectionTransitionsTotal.WithLabelValues(LeaderEventAcquired)))
+	assert.Equal(t, released+1, metricValue(t, metrics.LeaderElectionTransitionsTotal.WithLabelValues(LeaderEventReleased)))
+}
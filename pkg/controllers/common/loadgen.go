@@ -0,0 +1,445 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	// LabelLoadGen is labeled on the Namespaces and the CRs generated by the LoadGenerator.
+	LabelLoadGen = "nsx.vmware.com/loadgen"
+	// LoadGenNamespacePrefix is the prefix of the Namespaces the generated CRs are spread across.
+	LoadGenNamespacePrefix = "nsx-loadgen-"
+	// LoadGenReportConfigMapName is the ConfigMap in the namespace of nsx-operator the report is written to.
+	LoadGenReportConfigMapName = "nsx-operator-loadgen-report"
+	LoadGenReportKey           = "report.json"
+	LoadGenPollInterval        = 5 * time.Second
+
+	loadGenKindSecurityPolicy = "SecurityPolicy"
+	loadGenKindSubnet         = "Subnet"
+)
+
+// LoadGenKindReport is the realization of the generated CRs of a kind.
+type LoadGenKindReport struct {
+	Created int `json:"created"`
+	Ready   int `json:"ready"`
+	// Failed is the count of the CRs whose Ready condition is False at the end, Pending the ones without it.
+	Failed  int `json:"failed"`
+	Pending int `json:"pending"`
+	// ThroughputPerSecond is the count of the CRs realized per second, from the creation of the first one to
+	// the realization of the last one.
+	ThroughputPerSecond float64 `json:"throughputPerSecond"`
+	// The latencies from the creation of a CR to its realization observed by the poll, they're accurate to the
+	// poll interval.
+	P50LatencySeconds float64 `json:"p50LatencySeconds"`
+	P99LatencySeconds float64 `json:"p99LatencySeconds"`
+	MaxLatencySeconds float64 `json:"maxLatencySeconds"`
+}
+
+// LoadGenReport is the report of a load generation.
+type LoadGenReport struct {
+	StartTime       metav1.Time                   `json:"startTime"`
+	DurationSeconds float64                       `json:"durationSeconds"`
+	TimedOut        bool                          `json:"timedOut"`
+	Kinds           map[string]*LoadGenKindReport `json:"kinds"`
+	// NSXRequests is the count of the requests sent to NSX during the load generation by HTTP method.
+	NSXRequests          map[string]int64 `json:"nsxRequests"`
+	NSXRequestsPerCR     float64          `json:"nsxRequestsPerCR"`
+	NSXRequestsPerSecond float64          `json:"nsxRequestsPerSecond"`
+	// NSXObjects is the count of the NSX objects created by nsx-operator during the load generation by type.
+	NSXObjects map[string]int `json:"nsxObjects"`
+	// HeapAllocDeltaBytes is the growth of the heap of nsx-operator, mostly the stores of the NSX objects.
+	HeapAllocDeltaBytes int64 `json:"heapAllocDeltaBytes"`
+}
+
+// LoadGenerator synthesizes SecurityPolicies and Subnets spread across the Namespaces labeled with
+// nsx.vmware.com/loadgen, waits for them to be realized, and reports the reconcile throughput, the NSX API
+// usage and the memory growth of the stores, to validate nsx-operator against the NSX config maximums before
+// the production rollouts. It runs once on the leader, and deletes the generated Namespaces unless Keep is set.
+type LoadGenerator struct {
+	Client client.Client
+	// Namespace is the namespace of the report ConfigMap.
+	Namespace        string
+	Namespaces       int
+	SecurityPolicies int
+	// Subnets are only realized with VPC.
+	Subnets  int
+	Timeout  time.Duration
+	Counters []servicecommon.ObjectCounter
+	// ReportPath is the file the report is written to besides the ConfigMap, if it's set.
+	ReportPath   string
+	Keep         bool
+	PollInterval time.Duration
+
+	now func() time.Time
+}
+
+// loadGenObject is a generated CR and the time it's created and realized.
+type loadGenObject struct {
+	kind    string
+	key     types.NamespacedName
+	created time.Time
+	ready   time.Time
+}
+
+func (g *LoadGenerator) clock() time.Time {
+	if g.now != nil {
+		return g.now()
+	}
+	return time.Now()
+}
+
+func (g *LoadGenerator) namespaceName(i int) string {
+	namespaces := g.Namespaces
+	if namespaces <= 0 {
+		namespaces = 1
+	}
+	return fmt.Sprintf("%s%d", LoadGenNamespacePrefix, i%namespaces)
+}
+
+// buildSecurityPolicy builds the i-th SecurityPolicy, each one selects its own Pods and allows a distinct port,
+// so no NSX group is shared between them.
+func buildLoadGenSecurityPolicy(namespace string, i int) *v1alpha1.SecurityPolicy {
+	action, direction := v1alpha1.RuleActionAllow, v1alpha1.RuleDirectionIn
+	return &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("loadgen-sp-%d", i),
+			Labels:    map[string]string{LabelLoadGen: "true"},
+		},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": fmt.Sprintf("loadgen-%d", i)}},
+			}},
+			Rules: []v1alpha1.SecurityPolicyRule{{
+				Name:      "allow-clients",
+				Action:    &action,
+				Direction: &direction,
+				Sources: []v1alpha1.SecurityPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "loadgen-client"}},
+				}},
+				Ports: []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(8000 + i%1000)}},
+			}},
+		},
+	}
+}
+
+func buildLoadGenSubnet(namespace string, i int) *v1alpha1.Subnet {
+	return &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("loadgen-subnet-%d", i),
+			Labels:    map[string]string{LabelLoadGen: "true"},
+		},
+		Spec: v1alpha1.SubnetSpec{IPv4SubnetSize: 16, AccessMode: v1alpha1.AccessMode(v1alpha1.AccessModePrivate)},
+	}
+}
+
+// generate creates the Namespaces and the CRs, the ones left by a former load generation are reused.
+func (g *LoadGenerator) generate(ctx context.Context) ([]*loadGenObject, error) {
+	namespaces := g.Namespaces
+	if namespaces <= 0 {
+		namespaces = 1
+	}
+	for i := 0; i < namespaces; i++ {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: g.namespaceName(i), Labels: map[string]string{LabelLoadGen: "true"}}}
+		if err := g.Client.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create Namespace %s: %w", ns.Name, err)
+		}
+	}
+	var objects []*loadGenObject
+	create := func(kind string, obj client.Object) error {
+		if err := g.Client.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		objects = append(objects, &loadGenObject{
+			kind:    kind,
+			key:     types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+			created: g.clock(),
+		})
+		return nil
+	}
+	for i := 0; i < g.SecurityPolicies; i++ {
+		if err := create(loadGenKindSecurityPolicy, buildLoadGenSecurityPolicy(g.namespaceName(i), i)); err != nil {
+			return objects, err
+		}
+	}
+	for i := 0; i < g.Subnets; i++ {
+		if err := create(loadGenKindSubnet, buildLoadGenSubnet(g.namespaceName(i), i)); err != nil {
+			return objects, err
+		}
+	}
+	return objects, nil
+}
+
+func readyCondition(conditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == v1alpha1.Ready {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// conditions returns the Ready conditions of the generated CRs keyed by kind and NamespacedName.
+func (g *LoadGenerator) conditions(ctx context.Context) (map[string]map[types.NamespacedName]*v1alpha1.Condition, error) {
+	selector := client.MatchingLabels{LabelLoadGen: "true"}
+	conditions := map[string]map[types.NamespacedName]*v1alpha1.Condition{
+		loadGenKindSecurityPolicy: {},
+		loadGenKindSubnet:         {},
+	}
+	if g.SecurityPolicies > 0 {
+		spList := &v1alpha1.SecurityPolicyList{}
+		if err := g.Client.List(ctx, spList, selector); err != nil {
+			return nil, err
+		}
+		for i := range spList.Items {
+			sp := &spList.Items[i]
+			conditions[loadGenKindSecurityPolicy][types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}] = readyCondition(sp.Status.Conditions)
+		}
+	}
+	if g.Subnets > 0 {
+		subnetList := &v1alpha1.SubnetList{}
+		if err := g.Client.List(ctx, subnetList, selector); err != nil {
+			return nil, err
+		}
+		for i := range subnetList.Items {
+			subnet := &subnetList.Items[i]
+			conditions[loadGenKindSubnet][types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name}] = readyCondition(subnet.Status.Conditions)
+		}
+	}
+	return conditions, nil
+}
+
+// wait polls the realization of the CRs until all of them are realized or the timeout expires, it returns the
+// last Ready conditions and whether it timed out.
+func (g *LoadGenerator) wait(ctx context.Context, objects []*loadGenObject) (map[string]map[types.NamespacedName]*v1alpha1.Condition, bool) {
+	interval := g.PollInterval
+	if interval == 0 {
+		interval = LoadGenPollInterval
+	}
+	deadline := g.clock().Add(g.Timeout)
+	var conditions map[string]map[types.NamespacedName]*v1alpha1.Condition
+	for {
+		var err error
+		if conditions, err = g.conditions(ctx); err != nil {
+			log.Error(err, "failed to list generated CRs")
+		} else {
+			now := g.clock()
+			pending := 0
+			for _, obj := range objects {
+				if !obj.ready.IsZero() {
+					continue
+				}
+				if condition := conditions[obj.kind][obj.key]; condition != nil && condition.Status == v1.ConditionTrue {
+					obj.ready = now
+					continue
+				}
+				pending++
+			}
+			log.Info("waiting for generated CRs to be realized", "pending", pending, "total", len(objects))
+			if pending == 0 {
+				return conditions, false
+			}
+		}
+		if g.Timeout > 0 && !g.clock().Before(deadline) {
+			return conditions, true
+		}
+		select {
+		case <-ctx.Done():
+			return conditions, true
+		case <-time.After(interval):
+		}
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx].Seconds()
+}
+
+func (g *LoadGenerator) countObjects() map[string]int {
+	totals := map[string]int{}
+	for objType, counts := range (&ObjectCountReporter{Counters: g.Counters}).Count() {
+		totals[objType] = counts.Total()
+	}
+	return totals
+}
+
+func heapAlloc() int64 {
+	runtime.GC()
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
+
+// Run generates the CRs, waits for them to be realized and returns the report.
+func (g *LoadGenerator) Run(ctx context.Context) (*LoadGenReport, error) {
+	start := g.clock()
+	heapBefore := heapAlloc()
+	requestsBefore := nsx.RequestCounts()
+	objectsBefore := g.countObjects()
+	log.Info("generating load", "namespaces", g.Namespaces, "securityPolicies", g.SecurityPolicies, "subnets", g.Subnets)
+
+	objects, err := g.generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conditions, timedOut := g.wait(ctx, objects)
+	end := g.clock()
+
+	report := &LoadGenReport{
+		StartTime:           metav1.NewTime(start),
+		DurationSeconds:     end.Sub(start).Seconds(),
+		TimedOut:            timedOut,
+		Kinds:               map[string]*LoadGenKindReport{},
+		NSXRequests:         map[string]int64{},
+		NSXObjects:          map[string]int{},
+		HeapAllocDeltaBytes: heapAlloc() - heapBefore,
+	}
+	latencies := map[string][]time.Duration{}
+	first, last := map[string]time.Time{}, map[string]time.Time{}
+	for _, obj := range objects {
+		kindReport, ok := report.Kinds[obj.kind]
+		if !ok {
+			kindReport = &LoadGenKindReport{}
+			report.Kinds[obj.kind] = kindReport
+		}
+		kindReport.Created++
+		if t, ok := first[obj.kind]; !ok || obj.created.Before(t) {
+			first[obj.kind] = obj.created
+		}
+		if !obj.ready.IsZero() {
+			kindReport.Ready++
+			latencies[obj.kind] = append(latencies[obj.kind], obj.ready.Sub(obj.created))
+			if obj.ready.After(last[obj.kind]) {
+				last[obj.kind] = obj.ready
+			}
+		} else if condition := conditions[obj.kind][obj.key]; condition != nil {
+			kindReport.Failed++
+		} else {
+			kindReport.Pending++
+		}
+	}
+	for kind, kindReport := range report.Kinds {
+		sorted := latencies[kind]
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		kindReport.P50LatencySeconds = percentile(sorted, 0.5)
+		kindReport.P99LatencySeconds = percentile(sorted, 0.99)
+		kindReport.MaxLatencySeconds = percentile(sorted, 1)
+		if elapsed := last[kind].Sub(first[kind]).Seconds(); kindReport.Ready > 0 && elapsed > 0 {
+			kindReport.ThroughputPerSecond = float64(kindReport.Ready) / elapsed
+		}
+	}
+	var requests int64
+	for method, count := range nsx.RequestCounts() {
+		if delta := count - requestsBefore[method]; delta > 0 {
+			report.NSXRequests[method] = delta
+			requests += delta
+		}
+	}
+	if len(objects) > 0 {
+		report.NSXRequestsPerCR = float64(requests) / float64(len(objects))
+	}
+	if report.DurationSeconds > 0 {
+		report.NSXRequestsPerSecond = float64(requests) / report.DurationSeconds
+	}
+	for objType, count := range g.countObjects() {
+		report.NSXObjects[objType] = count - objectsBefore[objType]
+	}
+	return report, nil
+}
+
+// publish writes the report to the ConfigMap, and to the file if ReportPath is set.
+func (g *LoadGenerator) publish(ctx context.Context, report *LoadGenReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if g.ReportPath != "" {
+		if err := os.WriteFile(g.ReportPath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write load generation report to %s: %w", g.ReportPath, err)
+		}
+	}
+	cm := &v1.ConfigMap{}
+	key := types.NamespacedName{Namespace: g.Namespace, Name: LoadGenReportConfigMapName}
+	if err := g.Client.Get(ctx, key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: g.Namespace, Name: LoadGenReportConfigMapName},
+			Data:       map[string]string{LoadGenReportKey: string(data)},
+		}
+		return g.Client.Create(ctx, cm)
+	}
+	cm.Data = map[string]string{LoadGenReportKey: string(data)}
+	return g.Client.Update(ctx, cm)
+}
+
+// cleanup deletes the generated Namespaces, the CRs are deleted with them.
+func (g *LoadGenerator) cleanup(ctx context.Context) error {
+	nsList := &v1.NamespaceList{}
+	if err := g.Client.List(ctx, nsList, client.MatchingLabels{LabelLoadGen: "true"}); err != nil {
+		return err
+	}
+	for i := range nsList.Items {
+		if err := g.Client.Delete(ctx, &nsList.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	log.Info("deleted generated Namespaces", "count", len(nsList.Items))
+	return nil
+}
+
+// Start implements manager.Runnable, it runs the load generation once, nsx-operator keeps running afterwards.
+func (g *LoadGenerator) Start(ctx context.Context) error {
+	report, err := g.Run(ctx)
+	if err != nil {
+		log.Error(err, "failed to generate load")
+	} else {
+		log.Info("load generation finished", "durationSeconds", report.DurationSeconds, "timedOut", report.TimedOut,
+			"kinds", report.Kinds, "nsxRequests", report.NSXRequests, "heapAllocDeltaBytes", report.HeapAllocDeltaBytes)
+		if err := g.publish(ctx, report); err != nil {
+			log.Error(err, "failed to publish load generation report")
+		}
+	}
+	if !g.Keep {
+		if err := g.cleanup(ctx); err != nil {
+			log.Error(err, "failed to delete generated Namespaces")
+		}
+	}
+	return nil
+}
+
+// NeedLeaderElection returns true, the CRs are only generated once.
+func (g *LoadGenerator) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestLoadGenerator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	// the CRs left by a former load generation are reused
+	readySP := buildLoadGenSecurityPolicy("nsx-loadgen-0", 0)
+	readySP.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+	failedSP := buildLoadGenSecurityPolicy("nsx-loadgen-1", 1)
+	failedSP.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionFalse}}
+	readySubnet := buildLoadGenSubnet("nsx-loadgen-0", 0)
+	readySubnet.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-operator"}},
+			readySP, failedSP, readySubnet).
+		WithStatusSubresource(&v1alpha1.SecurityPolicy{}, &v1alpha1.Subnet{}).Build()
+	generator := &LoadGenerator{
+		Client:           k8sClient,
+		Namespace:        "nsx-operator",
+		Namespaces:       2,
+		SecurityPolicies: 3,
+		Subnets:          1,
+		Timeout:          20 * time.Millisecond,
+		PollInterval:     time.Millisecond,
+		Counters: []servicecommon.ObjectCounter{
+			fakeObjectCounter{servicecommon.ObjectTypeRule: {"nsx-loadgen-0": 3}},
+		},
+	}
+
+	ctx := context.TODO()
+	assert.NoError(t, generator.Start(ctx))
+
+	cm := &v1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "nsx-operator", Name: LoadGenReportConfigMapName}, cm))
+	report := &LoadGenReport{}
+	assert.NoError(t, json.Unmarshal([]byte(cm.Data[LoadGenReportKey]), report))
+	assert.True(t, report.TimedOut)
+	spReport := report.Kinds[loadGenKindSecurityPolicy]
+	assert.Equal(t, []int{3, 1, 1, 1}, []int{spReport.Created, spReport.Ready, spReport.Failed, spReport.Pending})
+	assert.LessOrEqual(t, spReport.P50LatencySeconds, spReport.MaxLatencySeconds)
+	assert.Equal(t, 1, report.Kinds[loadGenKindSubnet].Ready)
+	assert.Equal(t, 0, report.NSXObjects[servicecommon.ObjectTypeRule])
+
+	// the generated Namespaces are deleted
+	nsList := &v1.NamespaceList{}
+	assert.NoError(t, k8sClient.List(ctx, nsList, client.MatchingLabels{LabelLoadGen: "true"}))
+	assert.Empty(t, nsList.Items)
+
+	// the generated Namespaces are kept
+	generator.Keep = true
+	assert.NoError(t, generator.Start(ctx))
+	assert.NoError(t, k8sClient.List(ctx, nsList, client.MatchingLabels{LabelLoadGen: "true"}))
+	assert.Len(t, nsList.Items, 2)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}
+	assert.Equal(t, 0.0, percentile(nil, 0.5))
+	assert.Equal(t, 2.0, percentile(sorted, 0.5))
+	assert.Equal(t, 4.0, percentile(sorted, 0.99))
+	assert.Equal(t, 4.0, percentile(sorted, 1))
+}
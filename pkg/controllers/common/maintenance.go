@@ -0,0 +1,149 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const (
+	MaintenanceCheckInterval = 30 * time.Second
+	// ConditionNSXMaintenance is the condition of the NSXOperatorConfig CR reporting whether the write
+	// operations to NSX are paused for the NSX maintenance mode.
+	ConditionNSXMaintenance v1alpha1.ConditionType = "NSXMaintenance"
+	ReasonNSXMaintenance                           = "NSXMaintenance"
+	ReasonNSXAvailable                             = "NSXAvailable"
+)
+
+var (
+	// ResultRequeueAfterMaintenance postpones the reconcile while NSX is in maintenance mode, the changes
+	// are resynced once NSX leaves it anyway.
+	ResultRequeueAfterMaintenance = ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Minute}
+
+	nsxInMaintenance atomic.Bool
+)
+
+// InNSXMaintenance returns whether NSX is in maintenance mode, the reconciles and the garbage collections
+// don't write to NSX meanwhile and are postponed.
+func InNSXMaintenance() bool {
+	return nsxInMaintenance.Load()
+}
+
+// MaintenanceMonitor polls the maintenance mode of NSX, e.g. during an NSX upgrade. While NSX is in maintenance
+// mode, the write operations are paused instead of failing with errors and events, it's reported by the
+// NSXMaintenance condition of the NSXOperatorConfig CR and the nsx_maintenance metric. The changes of the CRs
+// stay in the reconcile queues, and the CRs are resynced by OnResume when NSX leaves the maintenance mode. It
+// is added to the manager to only run on the leader.
+type MaintenanceMonitor struct {
+	Client    client.Client
+	NSXConfig *config.NSXOperatorConfig
+	// Check returns whether NSX is in maintenance mode.
+	Check    func() (bool, error)
+	Interval time.Duration
+	// OnResume are called when NSX leaves the maintenance mode, to resync the CRs.
+	OnResume []func(ctx context.Context) error
+}
+
+func (m *MaintenanceMonitor) Start(ctx context.Context) error {
+	interval := m.Interval
+	if interval == 0 {
+		interval = MaintenanceCheckInterval
+	}
+	for {
+		m.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// NeedLeaderElection returns true, only the leader writes to NSX.
+func (m *MaintenanceMonitor) NeedLeaderElection() bool {
+	return true
+}
+
+func (m *MaintenanceMonitor) poll(ctx context.Context) {
+	inMaintenance, err := m.Check()
+	if err != nil {
+		// NSX may be unreachable during the maintenance, the state is kept until it's known
+		log.V(1).Info("failed to check NSX maintenance mode", "error", err.Error())
+		return
+	}
+	if nsxInMaintenance.Swap(inMaintenance) == inMaintenance {
+		return
+	}
+	metrics.GaugeSet(m.NSXConfig, metrics.NSXMaintenance, boolToFloat(inMaintenance))
+	m.updateCondition(ctx, inMaintenance)
+	if inMaintenance {
+		log.Info("NSX entered maintenance mode, pausing the write operations to NSX")
+		return
+	}
+	log.Info("NSX left maintenance mode, resuming the write operations to NSX")
+	for _, resume := range m.OnResume {
+		if err := resume(ctx); err != nil {
+			log.Error(err, "failed to resync after NSX maintenance")
+		}
+	}
+}
+
+// updateCondition reports the maintenance mode on the NSXOperatorConfig CR if it exists.
+func (m *MaintenanceMonitor) updateCondition(ctx context.Context, inMaintenance bool) {
+	condition := v1alpha1.Condition{
+		Type:               ConditionNSXMaintenance,
+		Status:             v1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNSXAvailable,
+		Message:            "write operations to NSX are enabled",
+	}
+	if inMaintenance {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = ReasonNSXMaintenance
+		condition.Message = "NSX is in maintenance mode, write operations to NSX are paused"
+	}
+	updateOperatorConfigCondition(ctx, m.Client, condition)
+}
+
+// updateOperatorConfigCondition sets the condition of its type on the NSXOperatorConfig CR if it exists, the
+// conditions of the other types are kept.
+func updateOperatorConfigCondition(ctx context.Context, c client.Client, condition v1alpha1.Condition) {
+	obj := &v1alpha1.NSXOperatorConfig{}
+	if err := c.Get(ctx, types.NamespacedName{Name: v1alpha1.NSXOperatorConfigName}, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to get NSXOperatorConfig", "nsxoperatorconfig", v1alpha1.NSXOperatorConfigName)
+		}
+		return
+	}
+	conditions := []v1alpha1.Condition{condition}
+	for _, existing := range obj.Status.Conditions {
+		if existing.Type != condition.Type {
+			conditions = append(conditions, existing)
+		}
+	}
+	obj.Status.Conditions = conditions
+	if err := c.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "failed to update NSXOperatorConfig status", "nsxoperatorconfig", obj.Name)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
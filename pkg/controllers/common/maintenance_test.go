@@ -0,0 +1,88 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestMaintenanceMonitor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	operatorConfig := &v1alpha1.NSXOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.NSXOperatorConfigName},
+		Status: v1alpha1.NSXOperatorConfigStatus{
+			Conditions: []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operatorConfig).
+		WithStatusSubresource(operatorConfig).Build()
+	ctx := context.TODO()
+	defer nsxInMaintenance.Store(false)
+
+	inMaintenance, checkErr := false, error(nil)
+	resumed := 0
+	m := &MaintenanceMonitor{
+		Client:    k8sClient,
+		NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		Check:     func() (bool, error) { return inMaintenance, checkErr },
+		OnResume: []func(ctx context.Context) error{func(ctx context.Context) error {
+			resumed++
+			return nil
+		}},
+	}
+	getCondition := func() *v1alpha1.Condition {
+		obj := &v1alpha1.NSXOperatorConfig{}
+		assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: v1alpha1.NSXOperatorConfigName}, obj))
+		// the other conditions are kept
+		assert.Equal(t, v1alpha1.Ready, obj.Status.Conditions[len(obj.Status.Conditions)-1].Type)
+		for i := range obj.Status.Conditions {
+			if obj.Status.Conditions[i].Type == ConditionNSXMaintenance {
+				return &obj.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// nothing changes while NSX is available
+	m.poll(ctx)
+	assert.False(t, InNSXMaintenance())
+	assert.Nil(t, getCondition())
+
+	// NSX enters maintenance mode
+	inMaintenance = true
+	m.poll(ctx)
+	assert.True(t, InNSXMaintenance())
+	condition := getCondition()
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonNSXMaintenance, condition.Reason)
+
+	// the state is kept if NSX is unreachable
+	checkErr = errors.New("connection refused")
+	m.poll(ctx)
+	assert.True(t, InNSXMaintenance())
+	assert.Equal(t, 0, resumed)
+
+	// NSX leaves maintenance mode, the CRs are resynced once
+	inMaintenance, checkErr = false, nil
+	m.poll(ctx)
+	m.poll(ctx)
+	assert.False(t, InNSXMaintenance())
+	assert.Equal(t, 1, resumed)
+	condition = getCondition()
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonNSXAvailable, condition.Reason)
+}
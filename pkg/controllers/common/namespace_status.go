@@ -0,0 +1,246 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	NamespaceStatusInterval = 5 * time.Minute
+	// maxResourceSyncErrors is the max count of the failed resources of a kind listed in the status.
+	maxResourceSyncErrors = 10
+)
+
+// namespaceStatusKinds are the kinds of the namespaced CRs realized on NSX, summarized in the
+// NamespaceNetworkStatus. The kinds whose CRD isn't installed, e.g. the VPC ones without VPC, are skipped.
+var namespaceStatusKinds = []struct {
+	kind    string
+	newList func() client.ObjectList
+}{
+	{"SecurityPolicy", func() client.ObjectList { return &v1alpha1.SecurityPolicyList{} }},
+	{"GatewayPolicy", func() client.ObjectList { return &v1alpha1.GatewayPolicyList{} }},
+	{"IDSPolicy", func() client.ObjectList { return &v1alpha1.IDSPolicyList{} }},
+	{"ServiceExposure", func() client.ObjectList { return &v1alpha1.ServiceExposureList{} }},
+	{"SubnetPolicy", func() client.ObjectList { return &v1alpha1.SubnetPolicyList{} }},
+	{"Subnet", func() client.ObjectList { return &v1alpha1.SubnetList{} }},
+	{"SubnetSet", func() client.ObjectList { return &v1alpha1.SubnetSetList{} }},
+	{"SubnetPort", func() client.ObjectList { return &v1alpha1.SubnetPortList{} }},
+	{"IPPool", func() client.ObjectList { return &v1alpha1.IPPoolList{} }},
+}
+
+// NamespaceStatusReporter periodically generates the NamespaceNetworkStatus in each Namespace, which
+// summarizes the realization of the CRs in the Namespace, the errors of the failed ones and the NSX objects
+// created for it, so that the Namespace owners can diagnose their resources without access to the logs of
+// nsx-operator. The status is deleted when the Namespace has nothing realized on NSX any more. It is added
+// to the manager to only run on the leader.
+type NamespaceStatusReporter struct {
+	Client   client.Client
+	Reader   client.Reader
+	Counters []servicecommon.ObjectCounter
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// Generate builds the status of each Namespace with CRs or NSX objects, keyed by the Namespace. The
+// Namespaces being deleted are skipped.
+func (r *NamespaceStatusReporter) Generate(ctx context.Context) (map[string]*v1alpha1.NamespaceNetworkStatusStatus, error) {
+	nsList := &v1.NamespaceList{}
+	if err := r.Reader.List(ctx, nsList); err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	generatedAt := metav1.NewTime(now())
+	statuses := map[string]*v1alpha1.NamespaceNetworkStatusStatus{}
+	active := map[string]bool{}
+	for _, ns := range nsList.Items {
+		active[ns.Name] = ns.DeletionTimestamp.IsZero()
+	}
+	statusOf := func(ns string) *v1alpha1.NamespaceNetworkStatusStatus {
+		if !active[ns] {
+			return nil
+		}
+		if statuses[ns] == nil {
+			statuses[ns] = &v1alpha1.NamespaceNetworkStatusStatus{GeneratedAt: generatedAt}
+		}
+		return statuses[ns]
+	}
+
+	for _, k := range namespaceStatusKinds {
+		list := k.newList()
+		if err := r.Reader.List(ctx, list); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		resources := map[string]*v1alpha1.ResourceSyncStatus{}
+		for _, obj := range objs {
+			cr, ok := obj.(client.Object)
+			if !ok || statusOf(cr.GetNamespace()) == nil {
+				continue
+			}
+			resource := resources[cr.GetNamespace()]
+			if resource == nil {
+				resource = &v1alpha1.ResourceSyncStatus{Kind: k.kind}
+				resources[cr.GetNamespace()] = resource
+			}
+			resource.Total++
+			condition := objectReadyCondition(cr)
+			if condition != nil && !condition.LastTransitionTime.IsZero() &&
+				(resource.LastSyncTime == nil || resource.LastSyncTime.Before(&condition.LastTransitionTime)) {
+				resource.LastSyncTime = condition.LastTransitionTime.DeepCopy()
+			}
+			if condition != nil && condition.Status == v1.ConditionTrue {
+				continue
+			}
+			resource.NotReady++
+			syncError := v1alpha1.ResourceSyncError{Name: cr.GetName()}
+			if condition != nil {
+				syncError.Reason = condition.Reason
+				syncError.Message = condition.Message
+				syncError.LastTransitionTime = condition.LastTransitionTime
+			}
+			resource.Errors = append(resource.Errors, syncError)
+		}
+		for ns, resource := range resources {
+			sort.Slice(resource.Errors, func(i, j int) bool {
+				return resource.Errors[i].Name < resource.Errors[j].Name
+			})
+			if len(resource.Errors) > maxResourceSyncErrors {
+				resource.Errors = resource.Errors[:maxResourceSyncErrors]
+			}
+			status := statusOf(ns)
+			status.NotReady += resource.NotReady
+			status.Resources = append(status.Resources, *resource)
+		}
+	}
+
+	for objType, objCounts := range (&ObjectCountReporter{Counters: r.Counters}).Count() {
+		for ns, count := range objCounts {
+			if count == 0 {
+				continue
+			}
+			status := statusOf(ns)
+			if status == nil {
+				continue
+			}
+			if status.NSXObjects == nil {
+				status.NSXObjects = map[string]int{}
+			}
+			status.NSXObjects[objType] = count
+		}
+	}
+	return statuses, nil
+}
+
+// objectReadyCondition returns the Ready condition of the CR, or nil if it's not reconciled yet.
+func objectReadyCondition(obj client.Object) *v1alpha1.Condition {
+	var conditions []v1alpha1.Condition
+	switch o := obj.(type) {
+	case *v1alpha1.SecurityPolicy:
+		conditions = o.Status.Conditions
+	case *v1alpha1.GatewayPolicy:
+		conditions = o.Status.Conditions
+	case *v1alpha1.IDSPolicy:
+		conditions = o.Status.Conditions
+	case *v1alpha1.ServiceExposure:
+		conditions = o.Status.Conditions
+	case *v1alpha1.SubnetPolicy:
+		conditions = o.Status.Conditions
+	case *v1alpha1.Subnet:
+		conditions = o.Status.Conditions
+	case *v1alpha1.SubnetSet:
+		conditions = o.Status.Conditions
+	case *v1alpha1.SubnetPort:
+		conditions = o.Status.Conditions
+	case *v1alpha1.IPPool:
+		conditions = o.Status.Conditions
+	}
+	return readyCondition(conditions)
+}
+
+// Report generates the statuses and writes them to the NamespaceNetworkStatus of each Namespace, which is
+// created if it doesn't exist, and deletes the ones of the Namespaces with nothing realized on NSX.
+func (r *NamespaceStatusReporter) Report(ctx context.Context) error {
+	statuses, err := r.Generate(ctx)
+	if err != nil {
+		return err
+	}
+	existing := &v1alpha1.NamespaceNetworkStatusList{}
+	if err := r.Reader.List(ctx, existing); err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		obj := &existing.Items[i]
+		if obj.Name != v1alpha1.NamespaceNetworkStatusName || statuses[obj.Namespace] != nil {
+			continue
+		}
+		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	var lastErr error
+	for ns, status := range statuses {
+		if err := r.updateStatus(ctx, ns, status); err != nil {
+			log.Error(err, "failed to update NamespaceNetworkStatus", "namespace", ns)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (r *NamespaceStatusReporter) updateStatus(ctx context.Context, ns string, status *v1alpha1.NamespaceNetworkStatusStatus) error {
+	key := types.NamespacedName{Namespace: ns, Name: v1alpha1.NamespaceNetworkStatusName}
+	obj := &v1alpha1.NamespaceNetworkStatus{}
+	if err := r.Reader.Get(ctx, key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		obj = &v1alpha1.NamespaceNetworkStatus{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: v1alpha1.NamespaceNetworkStatusName}}
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return err
+		}
+	}
+	obj.Status = *status
+	return r.Client.Status().Update(ctx, obj)
+}
+
+// Start implements manager.Runnable, it reports periodically until ctx is done.
+func (r *NamespaceStatusReporter) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval == 0 {
+		interval = NamespaceStatusInterval
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		if err := r.Report(ctx); err != nil {
+			log.Error(err, "failed to generate namespace network statuses")
+		}
+	}
+}
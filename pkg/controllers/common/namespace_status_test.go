@@ -0,0 +1,110 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestNamespaceStatusReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	readyAt := metav1.NewTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	failedAt := metav1.NewTime(time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC))
+	readySP := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "ready"},
+		Status: v1alpha1.SecurityPolicyStatus{
+			Conditions: []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue, LastTransitionTime: readyAt}},
+		},
+	}
+	failedSP := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "failed"},
+		Status: v1alpha1.SecurityPolicyStatus{
+			Conditions: []v1alpha1.Condition{{
+				Type: v1alpha1.Ready, Status: v1.ConditionFalse, LastTransitionTime: failedAt,
+				Reason: "SecurityPolicyFailed", Message: "invalid selector",
+			}},
+		},
+	}
+	pendingGP := &v1alpha1.GatewayPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "pending"}}
+	stale := &v1alpha1.NamespaceNetworkStatus{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-3", Name: v1alpha1.NamespaceNetworkStatusName},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-2"}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-3"}},
+			readySP, failedSP, pendingGP, stale).
+		WithStatusSubresource(&v1alpha1.NamespaceNetworkStatus{}).Build()
+	generatedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	reporter := &NamespaceStatusReporter{
+		Client: k8sClient,
+		Reader: k8sClient,
+		Counters: []servicecommon.ObjectCounter{
+			fakeObjectCounter{servicecommon.ObjectTypeRule: {"ns-1": 3, "ns-2": 2, "": 1}},
+		},
+		now: func() time.Time { return generatedAt },
+	}
+
+	ctx := context.TODO()
+	assert.NoError(t, reporter.Report(ctx))
+	status := &v1alpha1.NamespaceNetworkStatus{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: v1alpha1.NamespaceNetworkStatusName}, status))
+	assert.True(t, generatedAt.Equal(status.Status.GeneratedAt.Time))
+	assert.Equal(t, 2, status.Status.NotReady)
+	assert.Equal(t, map[string]int{servicecommon.ObjectTypeRule: 3}, status.Status.NSXObjects)
+	assert.Len(t, status.Status.Resources, 2)
+	sp, gp := status.Status.Resources[0], status.Status.Resources[1]
+	assert.Equal(t, "SecurityPolicy", sp.Kind)
+	assert.Equal(t, 2, sp.Total)
+	assert.Equal(t, 1, sp.NotReady)
+	assert.True(t, failedAt.Equal(sp.LastSyncTime))
+	assert.Len(t, sp.Errors, 1)
+	assert.Equal(t, "failed", sp.Errors[0].Name)
+	assert.Equal(t, "SecurityPolicyFailed", sp.Errors[0].Reason)
+	assert.Equal(t, "invalid selector", sp.Errors[0].Message)
+	// the CR not reconciled yet is not ready
+	assert.Equal(t, "GatewayPolicy", gp.Kind)
+	assert.Equal(t, 1, gp.NotReady)
+	assert.Nil(t, gp.LastSyncTime)
+	assert.Equal(t, []v1alpha1.ResourceSyncError{{Name: "pending"}}, gp.Errors)
+
+	// the Namespace with NSX objects only
+	status = &v1alpha1.NamespaceNetworkStatus{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns-2", Name: v1alpha1.NamespaceNetworkStatusName}, status))
+	assert.Empty(t, status.Status.Resources)
+	assert.Equal(t, map[string]int{servicecommon.ObjectTypeRule: 2}, status.Status.NSXObjects)
+
+	// the status of the Namespace with nothing realized is deleted
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns-3", Name: v1alpha1.NamespaceNetworkStatusName}, status)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the existing status is updated
+	assert.NoError(t, k8sClient.Delete(ctx, failedSP))
+	assert.NoError(t, k8sClient.Delete(ctx, pendingGP))
+	assert.NoError(t, reporter.Report(ctx))
+	status = &v1alpha1.NamespaceNetworkStatus{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: v1alpha1.NamespaceNetworkStatusName}, status))
+	assert.Equal(t, 0, status.Status.NotReady)
+	assert.Len(t, status.Status.Resources, 1)
+	assert.Equal(t, 1, status.Status.Resources[0].Total)
+	assert.Empty(t, status.Status.Resources[0].Errors)
+	assert.True(t, readyAt.Equal(status.Status.Resources[0].LastSyncTime))
+}
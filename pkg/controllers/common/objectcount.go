@@ -0,0 +1,157 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	ObjectCountReportInterval = time.Minute
+	// ObjectCountConfigMapName is the ConfigMap in the namespace of nsx-operator which summarizes
+	// the NSX objects created by nsx-operator.
+	ObjectCountConfigMapName = "nsx-operator-object-counts"
+	ObjectCountTotalKey      = "total"
+	ObjectCountNamespacesKey = "namespaces"
+)
+
+// ObjectCountReporter exports the count of NSX objects created by nsx-operator per namespace and in
+// total, as metrics and in the summary ConfigMap, so that the consumption can be tracked against the
+// NSX config maximums. It is added to the manager to only run on the leader.
+type ObjectCountReporter struct {
+	Client    client.Client
+	Reader    client.Reader
+	NSXConfig *config.NSXOperatorConfig
+	// Namespace is the namespace of the summary ConfigMap.
+	Namespace string
+	// ConfigMapName is the name of the summary ConfigMap, ObjectCountConfigMapName by default. Each shard of
+	// the controllers summarizes the objects of its own stores in its ConfigMap.
+	ConfigMapName string
+	Counters      []servicecommon.ObjectCounter
+	Interval      time.Duration
+
+	// reported records the namespaces reported per object type, to delete the metrics of the
+	// namespaces which have no object any more.
+	reported map[string]sets.Set[string]
+}
+
+// Count sums the ObjectCounts of all counters, keyed by object type.
+func (r *ObjectCountReporter) Count() map[string]servicecommon.ObjectCounts {
+	counts := map[string]servicecommon.ObjectCounts{}
+	for _, counter := range r.Counters {
+		for objType, objCounts := range counter.CountObjects() {
+			if _, ok := counts[objType]; !ok {
+				counts[objType] = servicecommon.ObjectCounts{}
+			}
+			for ns, count := range objCounts {
+				counts[objType][ns] += count
+			}
+		}
+	}
+	return counts
+}
+
+// Report updates the metrics and the summary ConfigMap.
+func (r *ObjectCountReporter) Report(ctx context.Context) error {
+	counts := r.Count()
+	r.reportMetrics(counts)
+	return r.updateConfigMap(ctx, counts)
+}
+
+func (r *ObjectCountReporter) reportMetrics(counts map[string]servicecommon.ObjectCounts) {
+	if r.reported == nil {
+		r.reported = map[string]sets.Set[string]{}
+	}
+	for objType, objCounts := range counts {
+		current := sets.New[string]()
+		for ns, count := range objCounts {
+			current.Insert(ns)
+			metrics.GaugeSet(r.NSXConfig, metrics.NSXObjectCount, float64(count), objType, ns)
+		}
+		for ns := range r.reported[objType].Difference(current) {
+			metrics.GaugeDelete(r.NSXConfig, metrics.NSXObjectCount, objType, ns)
+		}
+		r.reported[objType] = current
+		metrics.GaugeSet(r.NSXConfig, metrics.NSXObjectCountTotal, float64(objCounts.Total()), objType)
+	}
+}
+
+func (r *ObjectCountReporter) updateConfigMap(ctx context.Context, counts map[string]servicecommon.ObjectCounts) error {
+	totals := map[string]int{}
+	namespaces := map[string]map[string]int{}
+	for objType, objCounts := range counts {
+		totals[objType] = objCounts.Total()
+		for ns, count := range objCounts {
+			if ns == "" {
+				continue
+			}
+			if _, ok := namespaces[ns]; !ok {
+				namespaces[ns] = map[string]int{}
+			}
+			namespaces[ns][objType] = count
+		}
+	}
+	totalData, err := json.Marshal(totals)
+	if err != nil {
+		return err
+	}
+	namespacesData, err := json.Marshal(namespaces)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{
+		ObjectCountTotalKey:      string(totalData),
+		ObjectCountNamespacesKey: string(namespacesData),
+	}
+
+	cm := &v1.ConfigMap{}
+	name := r.ConfigMapName
+	if name == "" {
+		name = ObjectCountConfigMapName
+	}
+	key := types.NamespacedName{Namespace: r.Namespace, Name: name}
+	if err := r.Reader.Get(ctx, key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: name},
+			Data:       data,
+		}
+		return r.Client.Create(ctx, cm)
+	}
+	cm.Data = data
+	return r.Client.Update(ctx, cm)
+}
+
+// Start implements manager.Runnable, it reports periodically until ctx is done.
+func (r *ObjectCountReporter) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval == 0 {
+		interval = ObjectCountReportInterval
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		if err := r.Report(ctx); err != nil {
+			log.Error(err, "failed to report NSX object counts")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeObjectCounter map[string]servicecommon.ObjectCounts
+
+func (c fakeObjectCounter) CountObjects() map[string]servicecommon.ObjectCounts {
+	return c
+}
+
+func TestObjectCountReporter(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().Build()
+	reporter := &ObjectCountReporter{
+		Client:    k8sClient,
+		Reader:    k8sClient,
+		NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		Namespace: "vmware-system-nsx",
+		Counters: []servicecommon.ObjectCounter{
+			fakeObjectCounter{servicecommon.ObjectTypeGroup: {"ns-1": 2, "": 1}},
+			fakeObjectCounter{servicecommon.ObjectTypeGroup: {"ns-1": 1}, servicecommon.ObjectTypeRule: {"ns-2": 4}},
+		},
+	}
+
+	counts := reporter.Count()
+	assert.Equal(t, servicecommon.ObjectCounts{"ns-1": 3, "": 1}, counts[servicecommon.ObjectTypeGroup])
+	assert.Equal(t, 4, counts[servicecommon.ObjectTypeGroup].Total())
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "vmware-system-nsx", Name: ObjectCountConfigMapName}
+	assert.NoError(t, reporter.Report(ctx))
+	cm := &v1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(ctx, key, cm))
+	assert.JSONEq(t, `{"Group":4,"Rule":4}`, cm.Data[ObjectCountTotalKey])
+	assert.JSONEq(t, `{"ns-1":{"Group":3},"ns-2":{"Rule":4}}`, cm.Data[ObjectCountNamespacesKey])
+
+	reporter.Counters = reporter.Counters[:1]
+	assert.NoError(t, reporter.Report(ctx))
+	assert.NoError(t, k8sClient.Get(ctx, key, cm))
+	assert.JSONEq(t, `{"Group":3}`, cm.Data[ObjectCountTotalKey])
+	assert.JSONEq(t, `{"ns-1":{"Group":2}}`, cm.Data[ObjectCountNamespacesKey])
+}
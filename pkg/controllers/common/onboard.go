@@ -0,0 +1,31 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import "context"
+
+// The stages reported when onboarding a namespace.
+const (
+	// OnboardStageProvisioned is reported when the VPC CR and the default SubnetSets of the namespace are created.
+	OnboardStageProvisioned = "Provisioned"
+	// OnboardStageSharedVPC is reported when the namespace shares the VPC of another namespace, nothing is created.
+	OnboardStageSharedVPC = "SharedVPC"
+	// OnboardStageReady is reported when the VPC of the namespace, including its SNAT, is realized on NSX.
+	OnboardStageReady = "Ready"
+	// OnboardStageFailed is reported with the error when the namespace fails to be onboarded.
+	OnboardStageFailed = "Failed"
+)
+
+// OnboardEvent reports the progress of onboarding a namespace.
+type OnboardEvent struct {
+	Namespace string `json:"namespace"`
+	Stage     string `json:"stage"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NamespaceOnboarder provisions the network plumbing of a batch of namespaces, the progress of every
+// namespace is reported by the events.
+type NamespaceOnboarder interface {
+	OnboardNamespaces(ctx context.Context, namespaces []string, report func(OnboardEvent))
+}
@@ -0,0 +1,293 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// PolicyReconciler is the reconcile, finalizer and garbage collection flow shared by the controllers of the
+// policy CRs realized on NSX as a whole by a service, e.g. the GatewayPolicies and the IDSPolicies. The CR
+// is realized again on every change, its NSX resources are deleted before its finalizer is removed, and the
+// ones whose CR is gone are collected periodically. The restriction errors of the service are not retried
+// until the CR is changed.
+type PolicyReconciler[T client.Object] struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *WarmupGate
+	// DeletionGuard pauses the garbage collection deleting too many policies until it's confirmed.
+	DeletionGuard *DeletionGuard
+
+	NSXConfig *config.NSXOperatorConfig
+	// Kind is the kind of the CR, e.g. GatewayPolicy, ResType is its metric resource type, e.g. gatewaypolicy.
+	Kind      string
+	ResType   string
+	Finalizer string
+	// Subject is the subject of the messages of the Ready condition, e.g. NSX GatewayPolicy.
+	Subject string
+
+	NewObject  func() T
+	NewList    func() client.ObjectList
+	Conditions func(obj T) *[]v1alpha1.Condition
+
+	// CreateOrUpdate, Delete and ListIDs realize the CR, delete the NSX resources of a CR UID and list the
+	// CR UIDs of the NSX resources.
+	CreateOrUpdate func(obj T) error
+	Delete         func(uid types.UID) error
+	ListIDs        func() sets.Set[string]
+}
+
+func (r *PolicyReconciler[T]) deleteFail(ctx context.Context, obj T, err error) {
+	r.setReadyStatusFalse(ctx, obj, err)
+	r.Recorder.Event(obj, v1.EventTypeWarning, ReasonFailDelete, fmt.Sprintf("%v", err))
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteFailTotal, r.ResType)
+}
+
+func (r *PolicyReconciler[T]) updateFail(ctx context.Context, obj T, err error) {
+	r.setReadyStatusFalse(ctx, obj, err)
+	r.Recorder.Event(obj, v1.EventTypeWarning, ReasonFailUpdate, fmt.Sprintf("%v", err))
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateFailTotal, r.ResType)
+}
+
+func (r *PolicyReconciler[T]) updateSuccess(ctx context.Context, obj T) {
+	r.setReadyStatusTrue(ctx, obj)
+	r.Recorder.Event(obj, v1.EventTypeNormal, ReasonSuccessfulUpdate, fmt.Sprintf("%s CR has been successfully updated", r.Kind))
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateSuccessTotal, r.ResType)
+}
+
+func (r *PolicyReconciler[T]) deleteSuccess(obj T) {
+	r.Recorder.Event(obj, v1.EventTypeNormal, ReasonSuccessfulDelete, fmt.Sprintf("%s CR has been successfully deleted", r.Kind))
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteSuccessTotal, r.ResType)
+}
+
+func (r *PolicyReconciler[T]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", r.ResType, req.NamespacedName)
+		return ResultRequeueAfter10sec, nil
+	}
+	if InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", r.ResType, req.NamespacedName)
+		return ResultRequeueAfterMaintenance, nil
+	}
+	obj := r.NewObject()
+	log.Info(fmt.Sprintf("reconciling %s CR", r.ResType), r.ResType, req.NamespacedName)
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerSyncTotal, r.ResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, fmt.Sprintf("unable to fetch %s CR", r.ResType), "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.GetDeletionTimestamp().IsZero() {
+		metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateTotal, r.ResType)
+		if !controllerutil.ContainsFinalizer(obj, r.Finalizer) {
+			controllerutil.AddFinalizer(obj, r.Finalizer)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", r.ResType, req.NamespacedName)
+				r.updateFail(ctx, obj, err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info(fmt.Sprintf("added finalizer on %s CR", r.ResType), r.ResType, req.NamespacedName)
+		}
+
+		if err := r.CreateOrUpdate(obj); err != nil {
+			r.updateFail(ctx, obj, err)
+			// the policies NSX can't realize are not retried until they are changed
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				return ResultNormal, nil
+			}
+			return ResultRequeue, err
+		}
+		r.updateSuccess(ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, r.Finalizer) {
+			metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteTotal, r.ResType)
+			if err := r.Delete(obj.GetUID()); err != nil {
+				log.Error(err, "delete failed, would retry exponentially", r.ResType, req.NamespacedName)
+				r.deleteFail(ctx, obj, err)
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, r.Finalizer)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				r.deleteFail(ctx, obj, err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", r.ResType, req.NamespacedName)
+			r.deleteSuccess(obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", r.ResType, req.NamespacedName)
+		}
+	}
+
+	return ResultNormal, nil
+}
+
+func (r *PolicyReconciler[T]) setReadyStatusTrue(ctx context.Context, obj T) {
+	r.updateStatusConditions(ctx, obj, v1alpha1.Condition{
+		Type:               v1alpha1.Ready,
+		Status:             v1.ConditionTrue,
+		Message:            fmt.Sprintf("%s has been successfully created/updated", r.Subject),
+		Reason:             "NSX API returned 200 response code for PATCH",
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (r *PolicyReconciler[T]) setReadyStatusFalse(ctx context.Context, obj T, err error) {
+	r.updateStatusConditions(ctx, obj, v1alpha1.Condition{
+		Type:               v1alpha1.Ready,
+		Status:             v1.ConditionFalse,
+		Message:            fmt.Sprintf("%s could not be created/updated/deleted", r.Subject),
+		Reason:             fmt.Sprintf("error occurred while processing the %s CR. Error: %v", r.Kind, err),
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (r *PolicyReconciler[T]) updateStatusConditions(ctx context.Context, obj T, newCondition v1alpha1.Condition) {
+	if r.mergeStatusCondition(obj, &newCondition) {
+		r.Client.Status().Update(ctx, obj)
+		log.V(1).Info(fmt.Sprintf("updated %s", r.Kind), "Name", obj.GetName(), "New Condition", newCondition)
+	}
+}
+
+func (r *PolicyReconciler[T]) mergeStatusCondition(obj T, newCondition *v1alpha1.Condition) bool {
+	conditions := r.Conditions(obj)
+	var matchedCondition *v1alpha1.Condition
+	for i := range *conditions {
+		if (*conditions)[i].Type == newCondition.Type {
+			matchedCondition = &(*conditions)[i]
+			break
+		}
+	}
+
+	if reflect.DeepEqual(matchedCondition, newCondition) {
+		log.V(2).Info("conditions already match", "New Condition", newCondition, "Existing Condition", matchedCondition)
+		return false
+	}
+
+	if matchedCondition != nil {
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+	} else {
+		*conditions = append(*conditions, *newCondition)
+	}
+	return true
+}
+
+func (r *PolicyReconciler[T]) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(r.NewObject()).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *PolicyReconciler[T]) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	r.Warmup = NewWarmupGate(r.ResType, r.NSXConfig, mgr.GetCache(), r.NewObject())
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector collect the NSX resources of the policies which have been removed from crd.
+// cancel is used to break the loop during UT
+func (r *PolicyReconciler[T]) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started", "type", r.ResType)
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if InNSXMaintenance() {
+			continue
+		}
+		if err := r.CollectGarbage(ctx); err != nil {
+			log.Error(err, fmt.Sprintf("failed to collect garbage of %s", r.Kind))
+		}
+	}
+}
+
+// CollectGarbage deletes the NSX resources of the policies whose CR has been removed. The deletion is paused
+// by the DeletionGuard if too many policies are collected.
+func (r *PolicyReconciler[T]) CollectGarbage(ctx context.Context) error {
+	nsxPolicySet := r.ListIDs()
+	if len(nsxPolicySet) == 0 {
+		return nil
+	}
+
+	crdPolicyList := r.NewList()
+	if err := r.Client.List(ctx, crdPolicyList); err != nil {
+		return err
+	}
+
+	crdPolicySet := sets.New[string]()
+	if err := meta.EachListItem(crdPolicyList, func(obj runtime.Object) error {
+		crdPolicySet.Insert(string(obj.(client.Object).GetUID()))
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	stale := nsxPolicySet.Difference(crdPolicySet)
+	if err := r.DeletionGuard.Allow(ctx, len(stale), len(nsxPolicySet)); err != nil {
+		return err
+	}
+	for uid := range stale {
+		log.V(1).Info(fmt.Sprintf("GC collected %s CR", r.Kind), "UID", uid)
+		metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteTotal, r.ResType)
+		if err := r.Delete(types.UID(uid)); err != nil {
+			metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteFailTotal, r.ResType)
+		} else {
+			metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteSuccessTotal, r.ResType)
+		}
+	}
+	return nil
+}
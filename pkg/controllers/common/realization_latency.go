@@ -0,0 +1,115 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// RealizationLatency measures the time from a spec change of a CR to the realization of the change
+// confirmed by NSX, and reports it by the realization_latency_seconds histogram of the resource type.
+type RealizationLatency struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+
+	mu      sync.Mutex
+	changes map[types.UID]specChange
+	now     func() time.Time
+}
+
+type specChange struct {
+	generation int64
+	time       time.Time
+}
+
+// NewRealizationLatency creates the latency tracking of the resource type.
+func NewRealizationLatency(resType string, cf *config.NSXOperatorConfig) *RealizationLatency {
+	return &RealizationLatency{
+		resType:   resType,
+		nsxConfig: cf,
+		changes:   make(map[types.UID]specChange),
+		now:       time.Now,
+	}
+}
+
+// Predicate returns predicate funcs which never filter events, they only record the spec changes
+// of the watched resource.
+func (l *RealizationLatency) Predicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			l.Changed(e.Object)
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+				l.Changed(e.ObjectNew)
+			}
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			l.Forget(e.Object.GetUID())
+			return true
+		},
+	}
+}
+
+// Changed records the time of the spec change of the object. A new object is measured from its creation,
+// the objects created before nsx-operator started are listed by the informers but not changed.
+// The changes are not tracked with a nil RealizationLatency.
+func (l *RealizationLatency) Changed(obj client.Object) {
+	if l == nil {
+		return
+	}
+	changed := l.now()
+	if obj.GetGeneration() <= 1 {
+		created := obj.GetCreationTimestamp().Time
+		if created.Before(processStart) {
+			return
+		}
+		changed = created
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the first time wins so the latency covers the successive changes realized together
+	if change, ok := l.changes[obj.GetUID()]; ok {
+		changed = change.time
+	}
+	l.changes[obj.GetUID()] = specChange{generation: obj.GetGeneration(), time: changed}
+}
+
+// Realized observes the latency of the changes of the object up to the generation realized, each change
+// is observed once.
+func (l *RealizationLatency) Realized(uid types.UID, generation int64) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	change, ok := l.changes[uid]
+	if !ok || change.generation > generation {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.changes, uid)
+	l.mu.Unlock()
+	metrics.HistogramObserve(l.nsxConfig, metrics.RealizationLatencySeconds, l.now().Sub(change.time).Seconds(), l.resType)
+}
+
+// Forget stops tracking the object, e.g. when it's deleted.
+func (l *RealizationLatency) Forget(uid types.UID) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.changes, uid)
+}
@@ -0,0 +1,54 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestRealizationLatency(t *testing.T) {
+	l := NewRealizationLatency(MetricResTypeSecurityPolicy, nil)
+	now := processStart.Add(time.Hour)
+	l.now = func() time.Time { return now }
+	p := l.Predicate()
+
+	// the objects created before the start are not changed
+	old := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{UID: "uid1", Generation: 1, CreationTimestamp: metav1.NewTime(processStart.Add(-time.Hour))}}
+	assert.True(t, p.Create(event.CreateEvent{Object: old}))
+	assert.Empty(t, l.changes)
+
+	// a new object is measured from its creation
+	created := metav1.NewTime(now.Add(-time.Minute).Truncate(time.Second))
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{UID: "uid2", Generation: 1, CreationTimestamp: created}}
+	assert.True(t, p.Create(event.CreateEvent{Object: sp}))
+	assert.Equal(t, specChange{generation: 1, time: created.Time}, l.changes["uid2"])
+
+	// the successive changes are measured from the first one
+	newSP := sp.DeepCopy()
+	newSP.Generation = 2
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: newSP}))
+	assert.Equal(t, specChange{generation: 2, time: created.Time}, l.changes["uid2"])
+	l.Realized("uid2", 1)
+	assert.Len(t, l.changes, 1)
+	l.Realized("uid2", 2)
+	assert.Empty(t, l.changes)
+
+	// a spec change is measured from the update
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: newSP}))
+	assert.Equal(t, specChange{generation: 2, time: now}, l.changes["uid2"])
+	assert.True(t, p.Delete(event.DeleteEvent{Object: newSP}))
+	assert.Empty(t, l.changes)
+
+	var nilLatency *RealizationLatency
+	nilLatency.Changed(sp)
+	nilLatency.Realized("uid2", 1)
+	nilLatency.Forget("uid2")
+}
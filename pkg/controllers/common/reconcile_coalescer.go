@@ -0,0 +1,118 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// ReconcileCoalescer coalesces the rapid successive updates of a K8s resource, e.g. controllers
+// fighting over annotations, into a single reconcile once the resource has been quiet for the
+// window configured by reconcile_coalesce_window, so the redundant build, compare and patch
+// cycles are skipped. The creation and the deletion of a resource are not deferred.
+type ReconcileCoalescer struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+
+	mu         sync.Mutex
+	lastUpdate map[types.NamespacedName]time.Time
+	now        func() time.Time
+}
+
+// NewReconcileCoalescer creates a coalescer for the resource type.
+func NewReconcileCoalescer(resType string, cf *config.NSXOperatorConfig) *ReconcileCoalescer {
+	return &ReconcileCoalescer{
+		resType:    resType,
+		nsxConfig:  cf,
+		lastUpdate: make(map[types.NamespacedName]time.Time),
+		now:        time.Now,
+	}
+}
+
+// Predicate returns predicate funcs which never filter events, they only record the time of the
+// last update of the spec, labels or annotations of the watched resource. The status updates, e.g.
+// written by the controller itself, are not recorded.
+func (c *ReconcileCoalescer) Predicate() predicate.Funcs {
+	if c == nil {
+		return predicate.Funcs{}
+	}
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			key := types.NamespacedName{Namespace: e.ObjectNew.GetNamespace(), Name: e.ObjectNew.GetName()}
+			if !e.ObjectNew.GetDeletionTimestamp().IsZero() || c.window() == 0 {
+				c.Forget(key)
+				return true
+			}
+			if !specOrMetadataChanged(e) {
+				return true
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.lastUpdate[key] = c.now()
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			c.Forget(types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()})
+			return true
+		},
+	}
+}
+
+// Defer returns how long the reconcile of the key should be deferred until the resource has been
+// quiet for the window, 0 if it can be reconciled now. A deferred reconcile is counted as coalesced.
+// Predicate, Defer and Forget are no-op on a nil coalescer.
+func (c *ReconcileCoalescer) Defer(key types.NamespacedName) time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, ok := c.lastUpdate[key]
+	if !ok {
+		return 0
+	}
+	quiet := c.now().Sub(last)
+	if window := c.window(); quiet < window {
+		metrics.CounterInc(c.nsxConfig, metrics.ControllerReconcileCoalescedTotal, c.resType)
+		return window - quiet
+	}
+	delete(c.lastUpdate, key)
+	return 0
+}
+
+// Forget removes the last update of the key.
+func (c *ReconcileCoalescer) Forget(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.lastUpdate, key)
+}
+
+// specOrMetadataChanged returns true if the update changes the generation, labels or annotations.
+func specOrMetadataChanged(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil {
+		return true
+	}
+	return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+		!reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+		!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+}
+
+func (c *ReconcileCoalescer) window() time.Duration {
+	if c.nsxConfig != nil && c.nsxConfig.K8sConfig != nil && c.nsxConfig.ReconcileCoalesceWindow > 0 {
+		return time.Duration(c.nsxConfig.ReconcileCoalesceWindow) * time.Millisecond
+	}
+	return 0
+}
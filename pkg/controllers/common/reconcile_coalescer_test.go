@@ -0,0 +1,77 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestReconcileCoalescer(t *testing.T) {
+	now := time.Now()
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{ReconcileCoalesceWindow: 500}}
+	c := NewReconcileCoalescer(MetricResTypeSecurityPolicy, cf)
+	c.now = func() time.Time { return now }
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1}}
+	updated := sp.DeepCopy()
+	updated.Generation = 2
+	update := event.UpdateEvent{ObjectOld: sp, ObjectNew: updated}
+
+	// a resource not updated is reconciled now
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+
+	// the status updates are not recorded
+	statusUpdated := sp.DeepCopy()
+	statusUpdated.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready}}
+	assert.True(t, c.Predicate().Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: statusUpdated}))
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+
+	// the annotation updates are recorded
+	annotated := sp.DeepCopy()
+	annotated.Annotations = map[string]string{"nsx.vmware.com/dry_run": "true"}
+	assert.True(t, c.Predicate().Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: annotated}))
+	assert.Equal(t, 500*time.Millisecond, c.Defer(key))
+	c.Forget(key)
+
+	// successive updates defer the reconcile until the resource is quiet for the window
+	assert.True(t, c.Predicate().Update(update))
+	now = now.Add(200 * time.Millisecond)
+	assert.Equal(t, 300*time.Millisecond, c.Defer(key))
+	assert.True(t, c.Predicate().Update(update))
+	now = now.Add(100 * time.Millisecond)
+	assert.Equal(t, 400*time.Millisecond, c.Defer(key))
+	now = now.Add(400 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+	assert.Empty(t, c.lastUpdate)
+
+	// the deletion is not deferred
+	assert.True(t, c.Predicate().Update(update))
+	deleting := sp.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	assert.True(t, c.Predicate().Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: deleting}))
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+
+	// the coalescing is disabled
+	cf.ReconcileCoalesceWindow = 0
+	assert.True(t, c.Predicate().Update(update))
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+}
+
+func TestReconcileCoalescer_Nil(t *testing.T) {
+	var c *ReconcileCoalescer
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	assert.Equal(t, time.Duration(0), c.Defer(key))
+	c.Forget(key)
+	sp := &v1alpha1.SecurityPolicy{}
+	assert.True(t, c.Predicate().Update(event.UpdateEvent{ObjectOld: sp, ObjectNew: sp}))
+}
@@ -0,0 +1,161 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+// ReconcileTier is the order the resources of a new namespace are realized in, the resources of a tier depend on
+// the ones of the lower tiers, e.g. the SubnetPorts are created in the Subnets.
+type ReconcileTier int
+
+const (
+	// TierNetwork is the tier of the VPC, the Subnets and the SubnetSets.
+	TierNetwork ReconcileTier = iota
+	// TierGroups is the tier of the SubnetPorts and the Pods, the members of the NSX groups of the policies.
+	TierGroups
+	// TierPolicy is the tier of the SecurityPolicies and the NetworkPolicies.
+	TierPolicy
+	// TierLoadBalancer is the tier of the ServiceExposures.
+	TierLoadBalancer
+)
+
+// tierRecheckInterval is the interval the lower tiers are checked again while the reconcile is deferred.
+const tierRecheckInterval = 5 * time.Second
+
+// TierPendingFunc returns whether any resource of a tier in the namespace is not realized yet.
+type TierPendingFunc func(ctx context.Context, namespace string) (bool, error)
+
+type tierCheck struct {
+	tier    ReconcileTier
+	resType string
+	pending TierPendingFunc
+}
+
+var (
+	tierChecksLock = &sync.Mutex{}
+	tierChecks     []tierCheck
+)
+
+// RegisterTierCheck registers the check of the resources of the type in a tier, the reconciles of the higher tiers
+// in the new namespaces wait until it reports none is pending.
+func RegisterTierCheck(tier ReconcileTier, resType string, pending TierPendingFunc) {
+	tierChecksLock.Lock()
+	defer tierChecksLock.Unlock()
+	tierChecks = append(tierChecks, tierCheck{tier: tier, resType: resType, pending: pending})
+}
+
+// ReadyConditionPending returns a TierPendingFunc reporting the CRs of the namespace, listed in a new list from
+// newList, which are not being deleted and have no Ready condition of status True.
+func ReadyConditionPending(c client.Client, newList func() client.ObjectList, conditionsOf func(obj client.Object) []v1alpha1.Condition) TierPendingFunc {
+	return func(ctx context.Context, namespace string) (bool, error) {
+		list := newList()
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return false, err
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+		for _, o := range objs {
+			obj, ok := o.(client.Object)
+			if !ok || !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			ready := false
+			for _, condition := range conditionsOf(obj) {
+				if condition.Type == v1alpha1.Ready && condition.Status == v1.ConditionTrue {
+					ready = true
+					break
+				}
+			}
+			if !ready {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// TierGate defers the reconciles of the resources of a tier in the namespaces created within the window configured
+// by tiered_reconcile_window, until the resources of the lower tiers in the namespace are realized. When many CRs
+// are created along with a namespace, e.g. by a GitOps sync, they're realized in order instead of each controller
+// racing and retrying on the missing dependencies. The namespaces older than the window are never gated, so a lower
+// tier failing to realize holds up the higher tiers for the window at most.
+type TierGate struct {
+	tier      ReconcileTier
+	resType   string
+	client    client.Client
+	nsxConfig *config.NSXOperatorConfig
+	now       func() time.Time
+}
+
+// NewTierGate creates a gate for the resource type in the tier.
+func NewTierGate(tier ReconcileTier, resType string, c client.Client, cf *config.NSXOperatorConfig) *TierGate {
+	return &TierGate{
+		tier:      tier,
+		resType:   resType,
+		client:    c,
+		nsxConfig: cf,
+		now:       time.Now,
+	}
+}
+
+// Defer returns how long the reconcile of a resource of the namespace should be deferred until the resources of
+// the lower tiers are realized, 0 if it can be reconciled now. The checks failing don't defer the reconcile.
+// Defer is no-op on a nil gate.
+func (g *TierGate) Defer(ctx context.Context, namespace string) time.Duration {
+	if g == nil {
+		return 0
+	}
+	window := g.window()
+	if window == 0 {
+		return 0
+	}
+	ns := &v1.Namespace{}
+	if err := g.client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return 0
+	}
+	if g.now().Sub(ns.CreationTimestamp.Time) >= window {
+		return 0
+	}
+	tierChecksLock.Lock()
+	checks := make([]tierCheck, len(tierChecks))
+	copy(checks, tierChecks)
+	tierChecksLock.Unlock()
+	for _, check := range checks {
+		if check.tier >= g.tier {
+			continue
+		}
+		pending, err := check.pending(ctx, namespace)
+		if err != nil {
+			log.Error(err, "failed to check lower tier, not deferring the reconcile", "type", g.resType, "lowerType", check.resType, "namespace", namespace)
+			continue
+		}
+		if pending {
+			log.V(1).Info("waiting for lower tier of new namespace", "type", g.resType, "lowerType", check.resType, "namespace", namespace)
+			return tierRecheckInterval
+		}
+	}
+	return 0
+}
+
+// window returns the tiered_reconcile_window, 0 if the tiered reconcile is disabled.
+func (g *TierGate) window() time.Duration {
+	if g.nsxConfig != nil && g.nsxConfig.K8sConfig != nil && g.nsxConfig.TieredReconcileWindow > 0 {
+		return time.Duration(g.nsxConfig.TieredReconcileWindow) * time.Second
+	}
+	return 0
+}
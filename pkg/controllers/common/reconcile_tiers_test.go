@@ -0,0 +1,63 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestTierGate(t *testing.T) {
+	defer func(checks []tierCheck) { tierChecks = checks }(tierChecks)
+	tierChecks = nil
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	now := time.Now()
+	subnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "new", Name: "default"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "new", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "old", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}},
+		subnetSet,
+		&v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "old", Name: "default"}},
+	).Build()
+	RegisterTierCheck(TierNetwork, MetricResTypeSubnetSet, ReadyConditionPending(k8sClient,
+		func() client.ObjectList { return &v1alpha1.SubnetSetList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.SubnetSet).Status.Conditions }))
+
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{}}
+	policyGate := NewTierGate(TierPolicy, MetricResTypeSecurityPolicy, k8sClient, cf)
+	policyGate.now = func() time.Time { return now }
+	networkGate := NewTierGate(TierNetwork, MetricResTypeSubnet, k8sClient, cf)
+	networkGate.now = policyGate.now
+	ctx := context.Background()
+
+	// the tiers are disabled
+	assert.Zero(t, policyGate.Defer(ctx, "new"))
+
+	cf.TieredReconcileWindow = 600
+	assert.Equal(t, tierRecheckInterval, policyGate.Defer(ctx, "new"))
+	// the namespaces older than the window and the resources of the same tier are not gated
+	assert.Zero(t, policyGate.Defer(ctx, "old"))
+	assert.Zero(t, networkGate.Defer(ctx, "new"))
+	var nilGate *TierGate
+	assert.Zero(t, nilGate.Defer(ctx, "new"))
+
+	subnetSet.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+	assert.NoError(t, k8sClient.Update(ctx, subnetSet))
+	assert.Zero(t, policyGate.Defer(ctx, "new"))
+}
@@ -0,0 +1,269 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const (
+	// DefaultReconcileStallTimeout is used when reconcile_stall_timeout is not set in config.
+	DefaultReconcileStallTimeout = 15 * time.Minute
+	ReconcileReportInterval      = 30 * time.Second
+)
+
+var (
+	trackersLock = &sync.Mutex{}
+	trackers     []*ReconcileTracker
+)
+
+// ReconcileTracker records the K8s resources whose spec has changed but not yet been
+// synchronized to NSX, and the reconciles in flight, so that queue depth, staleness
+// and a stalled queue can be observed.
+type ReconcileTracker struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+
+	mu           sync.Mutex
+	pending      map[types.NamespacedName]time.Time
+	inFlight     map[types.NamespacedName]time.Time
+	lastProgress time.Time
+	now          func() time.Time
+}
+
+// NewReconcileTracker creates a tracker for the resource type and registers it so that
+// it is covered by CheckReconcileStall.
+func NewReconcileTracker(resType string, cf *config.NSXOperatorConfig) *ReconcileTracker {
+	t := &ReconcileTracker{
+		resType:   resType,
+		nsxConfig: cf,
+		pending:   make(map[types.NamespacedName]time.Time),
+		inFlight:  make(map[types.NamespacedName]time.Time),
+		now:       time.Now,
+	}
+	t.lastProgress = t.now()
+	trackersLock.Lock()
+	trackers = append(trackers, t)
+	trackersLock.Unlock()
+	return t
+}
+
+// Predicate returns predicate funcs which never filter events, they only record spec changes
+// of the watched resource as pending.
+func (t *ReconcileTracker) Predicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			t.Enqueued(e.Object)
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!e.ObjectNew.GetDeletionTimestamp().IsZero() {
+				t.Enqueued(e.ObjectNew)
+			}
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			t.Forget(types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()})
+			return true
+		},
+	}
+}
+
+// Enqueued records the object as pending, the first record wins so that the staleness
+// reflects the oldest unsynchronized change.
+func (t *ReconcileTracker) Enqueued(obj client.Object) {
+	if t == nil {
+		return
+	}
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[key]; !ok {
+		t.pending[key] = t.now()
+	}
+}
+
+// Started marks the beginning of a reconcile for the key.
+// Enqueued, Started, Done, Forget and RunReporter are no-op on a nil tracker.
+func (t *ReconcileTracker) Started(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight[key] = t.now()
+}
+
+// Done marks the end of a reconcile for the key, synced is true if the spec of the
+// resource has been successfully synchronized to NSX.
+func (t *ReconcileTracker) Done(key types.NamespacedName, synced bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inFlight, key)
+	t.lastProgress = t.now()
+	if synced {
+		t.forget(key)
+	}
+}
+
+// Forget removes the key from pending, it is used when the resource has been removed from K8s.
+func (t *ReconcileTracker) Forget(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forget(key)
+}
+
+func (t *ReconcileTracker) forget(key types.NamespacedName) {
+	if _, ok := t.pending[key]; !ok {
+		return
+	}
+	delete(t.pending, key)
+	metrics.GaugeDelete(t.nsxConfig, metrics.ReconcileStaleness, t.resType, key.Namespace, key.Name)
+}
+
+// Report updates the queue depth, the oldest item age and the staleness of each pending resource.
+func (t *ReconcileTracker) Report() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	oldest := 0.0
+	for key, since := range t.pending {
+		age := now.Sub(since).Seconds()
+		if age > oldest {
+			oldest = age
+		}
+		metrics.GaugeSet(t.nsxConfig, metrics.ReconcileStaleness, age, t.resType, key.Namespace, key.Name)
+	}
+	metrics.GaugeSet(t.nsxConfig, metrics.ReconcileQueueDepth, float64(len(t.pending)), t.resType)
+	metrics.GaugeSet(t.nsxConfig, metrics.ReconcileOldestItemAge, oldest, t.resType)
+	stalled := 0.0
+	if t.stalled(now) != nil {
+		stalled = 1
+	}
+	metrics.GaugeSet(t.nsxConfig, metrics.ReconcileStalled, stalled, t.resType)
+}
+
+// QueueState is the state of the reconcile queue of a resource type, it's collected in the diagnostics.
+type QueueState struct {
+	ResType              string   `json:"resType"`
+	Pending              int      `json:"pending"`
+	InFlight             []string `json:"inFlight,omitempty"`
+	OldestPendingSeconds float64  `json:"oldestPendingSeconds"`
+	SinceProgressSeconds float64  `json:"sinceProgressSeconds"`
+	Stalled              string   `json:"stalled,omitempty"`
+}
+
+// State returns the state of the reconcile queue.
+func (t *ReconcileTracker) State() QueueState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	state := QueueState{ResType: t.resType, Pending: len(t.pending), SinceProgressSeconds: now.Sub(t.lastProgress).Seconds()}
+	for _, since := range t.pending {
+		state.OldestPendingSeconds = math.Max(state.OldestPendingSeconds, now.Sub(since).Seconds())
+	}
+	for key := range t.inFlight {
+		state.InFlight = append(state.InFlight, key.String())
+	}
+	sort.Strings(state.InFlight)
+	if err := t.stalled(now); err != nil {
+		state.Stalled = err.Error()
+	}
+	return state
+}
+
+// ReconcileQueueStates returns the states of the reconcile queues of all the trackers.
+func ReconcileQueueStates() []QueueState {
+	trackersLock.Lock()
+	defer trackersLock.Unlock()
+	states := make([]QueueState, 0, len(trackers))
+	for _, t := range trackers {
+		states = append(states, t.State())
+	}
+	return states
+}
+
+// RunReporter reports metrics periodically.
+// cancel is used to break the loop during UT
+func (t *ReconcileTracker) RunReporter(cancel chan bool, interval time.Duration) {
+	if t == nil {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		t.Report()
+	}
+}
+
+// Stalled returns an error if a reconcile has been running longer than the stall timeout, or
+// a change has been pending longer than the stall timeout while no reconcile has finished since.
+func (t *ReconcileTracker) Stalled() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stalled(t.now())
+}
+
+// stallTimeout is read from config every time since it can be changed at runtime by NSXOperatorConfig.
+func (t *ReconcileTracker) stallTimeout() time.Duration {
+	if t.nsxConfig == nil {
+		return DefaultReconcileStallTimeout
+	}
+	if timeout := t.nsxConfig.GetRuntimeConfig().ReconcileStallTimeout; timeout > 0 {
+		return time.Duration(timeout) * time.Second
+	}
+	return DefaultReconcileStallTimeout
+}
+
+func (t *ReconcileTracker) stalled(now time.Time) error {
+	stallTimeout := t.stallTimeout()
+	for key, start := range t.inFlight {
+		if now.Sub(start) > stallTimeout {
+			return fmt.Errorf("%s reconcile of %s has been running for %s", t.resType, key, now.Sub(start).Round(time.Second))
+		}
+	}
+	for key, since := range t.pending {
+		if now.Sub(since) > stallTimeout && t.lastProgress.Before(since) {
+			return fmt.Errorf("%s %s has been pending for %s without any reconcile progress", t.resType, key, now.Sub(since).Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// CheckReconcileStall is a healthz checker which fails if the reconcile queue of any resource
+// type is stalled, so that the liveness probe can restart the operator.
+func CheckReconcileStall(_ *http.Request) error {
+	trackersLock.Lock()
+	defer trackersLock.Unlock()
+	for _, t := range trackers {
+		if err := t.Stalled(); err != nil {
+			log.Error(err, "reconcile queue is stalled")
+			return err
+		}
+	}
+	return nil
+}
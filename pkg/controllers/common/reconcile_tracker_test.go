@@ -0,0 +1,71 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func newTestTracker(now *time.Time) *ReconcileTracker {
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{ReconcileStallTimeout: 60}}
+	t := NewReconcileTracker(MetricResTypeSecurityPolicy, cf)
+	t.now = func() time.Time { return *now }
+	t.lastProgress = *now
+	return t
+}
+
+func TestReconcileTracker_Stalled(t *testing.T) {
+	now := time.Now()
+	tracker := newTestTracker(&now)
+	assert.Equal(t, 60*time.Second, tracker.stallTimeout())
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+
+	now = now.Add(time.Second)
+	tracker.Enqueued(sp)
+	assert.Equal(t, 1, len(tracker.pending))
+	assert.Nil(t, tracker.Stalled())
+
+	// pending without any reconcile progress
+	now = now.Add(2 * time.Minute)
+	assert.NotNil(t, tracker.Stalled())
+	assert.NotNil(t, CheckReconcileStall(nil))
+
+	// reconcile finished without sync, pending is kept but no longer stalled
+	tracker.Started(key)
+	tracker.Done(key, false)
+	assert.Equal(t, 1, len(tracker.pending))
+	assert.Nil(t, tracker.Stalled())
+
+	// reconcile hangs
+	tracker.Started(key)
+	now = now.Add(2 * time.Minute)
+	assert.NotNil(t, tracker.Stalled())
+
+	tracker.Done(key, true)
+	assert.Equal(t, 0, len(tracker.pending))
+	assert.Equal(t, 0, len(tracker.inFlight))
+	assert.Nil(t, tracker.Stalled())
+	assert.Nil(t, CheckReconcileStall(nil))
+}
+
+func TestReconcileTracker_Nil(t *testing.T) {
+	var tracker *ReconcileTracker
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	tracker.Enqueued(&v1alpha1.SecurityPolicy{})
+	tracker.Started(key)
+	tracker.Done(key, true)
+	tracker.Forget(key)
+	tracker.RunReporter(nil, time.Second)
+}
+
+func TestNewReconcileTracker_DefaultTimeout(t *testing.T) {
+	tracker := NewReconcileTracker(MetricResTypeNetworkPolicy, nil)
+	assert.Equal(t, DefaultReconcileStallTimeout, tracker.stallTimeout())
+}
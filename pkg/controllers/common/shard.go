@@ -0,0 +1,100 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const ShardCheckInterval = 30 * time.Second
+
+// ShardCoordinator watches the leader election leases of all the shards when the controllers are split across
+// the replicas by the shard config. The resources of a shard without a leader are not reconciled at all, e.g.
+// the SecurityPolicies of the new namespaces keep waiting for the VPCs if the network shard is down, so it's
+// reported by the shard_owned metric and logged. It is added to the manager to only run on the leader of its
+// shard, so each shard watches the others.
+type ShardCoordinator struct {
+	Reader    client.Reader
+	NSXConfig *config.NSXOperatorConfig
+	// Namespace is the namespace of the leases, i.e. the nsx-operator namespace.
+	Namespace string
+	Interval  time.Duration
+
+	now   func() time.Time
+	owned map[string]bool
+}
+
+func (c *ShardCoordinator) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval == 0 {
+		interval = ShardCheckInterval
+	}
+	for {
+		c.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// NeedLeaderElection returns true, the coordinator only runs on the leader of the shard.
+func (c *ShardCoordinator) NeedLeaderElection() bool {
+	return true
+}
+
+// Check returns whether each shard has a leader holding an unexpired lease, and reports the shards losing or
+// regaining their leader.
+func (c *ShardCoordinator) Check(ctx context.Context) map[string]bool {
+	if c.owned == nil {
+		c.owned = map[string]bool{}
+	}
+	owned := map[string]bool{}
+	for _, shard := range config.Shards {
+		lease := &coordinationv1.Lease{}
+		key := types.NamespacedName{Namespace: c.Namespace, Name: config.ShardLeaderElectionID(shard)}
+		if err := c.Reader.Get(ctx, key, lease); err != nil {
+			if !apierrors.IsNotFound(err) {
+				// the state is kept until the lease is read
+				log.Error(err, "failed to get shard lease", "shard", shard, "lease", key)
+				owned[shard] = c.owned[shard]
+				continue
+			}
+		}
+		owned[shard] = c.leaseHeld(lease)
+		metrics.GaugeSet(c.NSXConfig, metrics.ShardOwned, boolToFloat(owned[shard]), shard)
+		if previous, checked := c.owned[shard]; checked && previous == owned[shard] {
+			continue
+		}
+		if owned[shard] {
+			log.Info("shard has a leader", "shard", shard, "holder", *lease.Spec.HolderIdentity)
+		} else {
+			log.Error(nil, "shard has no leader, its resources are not reconciled", "shard", shard)
+		}
+	}
+	c.owned = owned
+	return owned
+}
+
+func (c *ShardCoordinator) leaseHeld(lease *coordinationv1.Lease) bool {
+	spec := lease.Spec
+	if spec.HolderIdentity == nil || *spec.HolderIdentity == "" || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	return now().Before(spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second))
+}
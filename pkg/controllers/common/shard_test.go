@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestShardCoordinator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	coordinationv1.AddToScheme(scheme)
+	now := time.Now()
+	holder, duration := "nsx-operator-policy-0", int32(15)
+	policyLease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "vmware-system-nsx", Name: "nsx-operator-policy"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &metav1.MicroTime{Time: now.Add(-5 * time.Second)},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policyLease).Build()
+	c := &ShardCoordinator{
+		Reader:    k8sClient,
+		NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		Namespace: "vmware-system-nsx",
+		now:       func() time.Time { return now },
+	}
+	assert.True(t, c.NeedLeaderElection())
+	// the network shard has no lease
+	assert.Equal(t, map[string]bool{config.ShardPolicy: true, config.ShardNetwork: false}, c.Check(context.TODO()))
+
+	// the lease of the policy shard expires
+	now = now.Add(time.Minute)
+	assert.Equal(t, map[string]bool{config.ShardPolicy: false, config.ShardNetwork: false}, c.Check(context.TODO()))
+}
@@ -0,0 +1,160 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+const (
+	// OrphansConfigMapName is the ConfigMap in the namespace of nsx-operator recording the CRs whose finalizer
+	// is forcibly removed while their NSX resources are left.
+	OrphansConfigMapName = "nsx-operator-orphans"
+
+	StuckDeletionActionAlerted = "alerted"
+	StuckDeletionActionForced  = "forced"
+)
+
+// Orphan is the record of a CR whose finalizer is forcibly removed, its NSX resources are left to the garbage
+// collection or to be cleaned up manually.
+type Orphan struct {
+	ResType           string      `json:"resType"`
+	Namespace         string      `json:"namespace"`
+	Name              string      `json:"name"`
+	UID               types.UID   `json:"uid"`
+	DeletionTimestamp metav1.Time `json:"deletionTimestamp"`
+	ForcedAt          metav1.Time `json:"forcedAt"`
+	Error             string      `json:"error"`
+}
+
+// StuckDeletionPolicy escalates the CRs stuck deleting for longer than stuck_deletion_timeout because their NSX
+// resources fail to be deleted, e.g. NSX rejects the deletion, which keeps the namespace in Terminating forever.
+// A stuck CR is alerted by a DeletionStuck event and the deletion_stuck_total metric. If force_finalizer_removal
+// is set, its finalizer is removed too, and the CR is recorded in the nsx-operator-orphans ConfigMap.
+type StuckDeletionPolicy struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+	client    client.Client
+	recorder  record.EventRecorder
+	namespace string
+
+	// alerted are the UIDs of the CRs alerted, a CR is alerted once.
+	alerted sync.Map
+	now     func() time.Time
+}
+
+// NewStuckDeletionPolicy creates a policy for the resource type, the orphans are recorded in the namespace.
+func NewStuckDeletionPolicy(resType string, cf *config.NSXOperatorConfig, c client.Client, recorder record.EventRecorder, namespace string) *StuckDeletionPolicy {
+	return &StuckDeletionPolicy{
+		resType:   resType,
+		nsxConfig: cf,
+		client:    c,
+		recorder:  recorder,
+		namespace: namespace,
+		now:       time.Now,
+	}
+}
+
+// Escalate is called when deleting the NSX resources of the CR fails with deleteErr. It returns true if the
+// finalizer of the CR is to be removed regardless. Escalate is no-op on a nil policy.
+func (p *StuckDeletionPolicy) Escalate(ctx context.Context, obj client.Object, deleteErr error) bool {
+	if p == nil || obj.GetDeletionTimestamp() == nil {
+		return false
+	}
+	timeout := p.timeout()
+	if timeout <= 0 {
+		return false
+	}
+	stuck := p.now().Sub(obj.GetDeletionTimestamp().Time)
+	if stuck < timeout {
+		return false
+	}
+	if _, alerted := p.alerted.LoadOrStore(obj.GetUID(), true); !alerted {
+		log.Error(deleteErr, "deletion stuck", "type", p.resType, "namespace", obj.GetNamespace(), "name", obj.GetName(), "duration", stuck.Round(time.Second))
+		p.recorder.Eventf(obj, v1.EventTypeWarning, ReasonDeletionStuck, "Deleting the NSX resources has been failing for %s: %s",
+			stuck.Round(time.Second), ErrorMessage(deleteErr))
+		metrics.CounterIncWithLabels(p.nsxConfig, metrics.DeletionStuckTotal, p.resType, StuckDeletionActionAlerted)
+	}
+	if !p.nsxConfig.ForceFinalizerRemoval {
+		return false
+	}
+	if err := p.recordOrphan(ctx, obj, deleteErr); err != nil {
+		log.Error(err, "failed to record orphan, the finalizer is kept", "type", p.resType, "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return false
+	}
+	log.Info("forcibly removing finalizer of stuck deletion", "type", p.resType, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	p.recorder.Eventf(obj, v1.EventTypeWarning, ReasonFinalizerForced, "The finalizer is removed, the NSX resources left are recorded in ConfigMap %s/%s",
+		p.namespace, OrphansConfigMapName)
+	metrics.CounterIncWithLabels(p.nsxConfig, metrics.DeletionStuckTotal, p.resType, StuckDeletionActionForced)
+	return true
+}
+
+// Forget drops the state of the CR once its finalizer is removed.
+func (p *StuckDeletionPolicy) Forget(uid types.UID) {
+	if p == nil {
+		return
+	}
+	p.alerted.Delete(uid)
+}
+
+// recordOrphan adds the CR to the orphans ConfigMap, the entry is keyed by the resource type and the UID.
+func (p *StuckDeletionPolicy) recordOrphan(ctx context.Context, obj client.Object, deleteErr error) error {
+	orphan := Orphan{
+		ResType:           p.resType,
+		Namespace:         obj.GetNamespace(),
+		Name:              obj.GetName(),
+		UID:               obj.GetUID(),
+		DeletionTimestamp: *obj.GetDeletionTimestamp(),
+		ForcedAt:          metav1.NewTime(p.now()),
+	}
+	if deleteErr != nil {
+		orphan.Error = deleteErr.Error()
+	}
+	data, err := json.Marshal(orphan)
+	if err != nil {
+		return err
+	}
+	entry := p.resType + "." + string(obj.GetUID())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &v1.ConfigMap{}
+		key := types.NamespacedName{Namespace: p.namespace, Name: OrphansConfigMapName}
+		if err := p.client.Get(ctx, key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: p.namespace, Name: OrphansConfigMapName},
+				Data:       map[string]string{entry: string(data)},
+			}
+			return p.client.Create(ctx, cm)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[entry] = string(data)
+		return p.client.Update(ctx, cm)
+	})
+}
+
+// timeout returns the stuck_deletion_timeout, 0 if the stuck deletions are not escalated.
+func (p *StuckDeletionPolicy) timeout() time.Duration {
+	if p.nsxConfig == nil || p.nsxConfig.K8sConfig == nil {
+		return 0
+	}
+	return time.Duration(p.nsxConfig.StuckDeletionTimeout) * time.Second
+}
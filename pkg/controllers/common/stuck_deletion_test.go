@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestStuckDeletionPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	recorder := record.NewFakeRecorder(10)
+	cf := &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{}}
+	p := NewStuckDeletionPolicy(MetricResTypeSecurityPolicy, cf, k8sClient, recorder, "nsx-system")
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	ctx := context.TODO()
+	deleteErr := errors.New("NSX rejected the deletion")
+	obj := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         "ns1",
+		Name:              "sp1",
+		UID:               "uid1",
+		DeletionTimestamp: &metav1.Time{Time: now.Add(-time.Hour)},
+	}}
+
+	// the policy is disabled
+	assert.False(t, p.Escalate(ctx, obj, deleteErr))
+	assert.Empty(t, recorder.Events)
+
+	// the deletion is not stuck for long enough
+	cf.StuckDeletionTimeout = 7200
+	assert.False(t, p.Escalate(ctx, obj, deleteErr))
+	assert.Empty(t, recorder.Events)
+
+	// the stuck deletion is alerted once
+	cf.StuckDeletionTimeout = 1800
+	assert.False(t, p.Escalate(ctx, obj, deleteErr))
+	assert.Contains(t, <-recorder.Events, ReasonDeletionStuck)
+	assert.False(t, p.Escalate(ctx, obj, deleteErr))
+	assert.Empty(t, recorder.Events)
+
+	// the finalizer is removed and the orphan is recorded
+	cf.ForceFinalizerRemoval = true
+	assert.True(t, p.Escalate(ctx, obj, deleteErr))
+	assert.Contains(t, <-recorder.Events, ReasonFinalizerForced)
+	cm := &v1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "nsx-system", Name: OrphansConfigMapName}, cm))
+	orphan := Orphan{}
+	assert.NoError(t, json.Unmarshal([]byte(cm.Data["securitypolicy.uid1"]), &orphan))
+	assert.Equal(t, "ns1", orphan.Namespace)
+	assert.Equal(t, "sp1", orphan.Name)
+	assert.Equal(t, "NSX rejected the deletion", orphan.Error)
+
+	// the orphans are accumulated
+	p.Forget(obj.UID)
+	obj.Name, obj.UID = "sp2", "uid2"
+	assert.True(t, p.Escalate(ctx, obj, deleteErr))
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "nsx-system", Name: OrphansConfigMapName}, cm))
+	assert.Len(t, cm.Data, 2)
+
+	var nilPolicy *StuckDeletionPolicy
+	assert.False(t, nilPolicy.Escalate(ctx, obj, deleteErr))
+}
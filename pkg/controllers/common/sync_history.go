@@ -0,0 +1,196 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+const (
+	// DefaultSyncHistorySize is the count of the last sync attempts kept for each resource.
+	DefaultSyncHistorySize = 20
+	// flappingTransitions is the count of the changes between success and failure a resource is flapping from.
+	flappingTransitions = 4
+
+	SyncOutcomeSuccess = "success"
+	SyncOutcomeFailure = "failure"
+)
+
+// SyncAttempt is an attempt to sync a resource to NSX.
+type SyncAttempt struct {
+	Time            time.Time `json:"time"`
+	Outcome         string    `json:"outcome"`
+	Error           string    `json:"error,omitempty"`
+	DurationSeconds float64   `json:"durationSeconds"`
+}
+
+// syncRing keeps the last attempts of a resource, next is the index the next attempt is written to.
+type syncRing struct {
+	attempts []SyncAttempt
+	next     int
+	started  time.Time
+}
+
+// SyncHistory keeps the last sync attempts of each resource in memory, so that the flapping resources can be
+// seen without correlating the logs. Started marks the beginning of an attempt, and Succeeded or Failed
+// records its outcome. All the methods are no-op on a nil history.
+type SyncHistory struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[types.NamespacedName]*syncRing
+	now     func() time.Time
+}
+
+// NewSyncHistory creates a history keeping the last size attempts of each resource.
+func NewSyncHistory(size int) *SyncHistory {
+	if size <= 0 {
+		size = DefaultSyncHistorySize
+	}
+	return &SyncHistory{
+		size:    size,
+		entries: make(map[types.NamespacedName]*syncRing),
+		now:     time.Now,
+	}
+}
+
+// Started marks the beginning of a sync attempt for the key.
+func (h *SyncHistory) Started(key types.NamespacedName) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ring, ok := h.entries[key]
+	if !ok {
+		ring = &syncRing{}
+		h.entries[key] = ring
+	}
+	ring.started = h.now()
+}
+
+// Succeeded records the attempt for the key as successful.
+func (h *SyncHistory) Succeeded(key types.NamespacedName) {
+	h.record(key, nil)
+}
+
+// Failed records the attempt for the key as failed with the error.
+func (h *SyncHistory) Failed(key types.NamespacedName, err error) {
+	h.record(key, err)
+}
+
+func (h *SyncHistory) record(key types.NamespacedName, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ring, ok := h.entries[key]
+	if !ok {
+		ring = &syncRing{}
+		h.entries[key] = ring
+	}
+	now := h.now()
+	attempt := SyncAttempt{Time: now, Outcome: SyncOutcomeSuccess}
+	if !ring.started.IsZero() {
+		attempt.DurationSeconds = now.Sub(ring.started).Seconds()
+		ring.started = time.Time{}
+	}
+	if err != nil {
+		attempt.Outcome = SyncOutcomeFailure
+		attempt.Error = ErrorMessage(err)
+	}
+	if len(ring.attempts) < h.size {
+		ring.attempts = append(ring.attempts, attempt)
+	} else {
+		ring.attempts[ring.next] = attempt
+	}
+	ring.next = (ring.next + 1) % h.size
+}
+
+// Forget removes the history of the key, it is used when the resource has been removed from K8s.
+func (h *SyncHistory) Forget(key types.NamespacedName) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.entries, key)
+}
+
+// Attempts returns the last attempts of the key, the oldest first.
+func (h *SyncHistory) Attempts(key types.NamespacedName) []SyncAttempt {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ring, ok := h.entries[key]
+	if !ok {
+		return nil
+	}
+	return ring.list()
+}
+
+// All returns the last attempts of all the resources keyed by Namespace/Name.
+func (h *SyncHistory) All() map[string][]SyncAttempt {
+	all := map[string][]SyncAttempt{}
+	if h == nil {
+		return all
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, ring := range h.entries {
+		if attempts := ring.list(); len(attempts) > 0 {
+			all[key.String()] = attempts
+		}
+	}
+	return all
+}
+
+// list returns the attempts from the oldest one, which is the next one to be overwritten once the ring is full.
+func (r *syncRing) list() []SyncAttempt {
+	attempts := make([]SyncAttempt, 0, len(r.attempts))
+	attempts = append(attempts, r.attempts[r.next:]...)
+	return append(attempts, r.attempts[:r.next]...)
+}
+
+// Summary summarizes the last attempts of the key for the status of the resource, it returns nil if there
+// is no attempt.
+func (h *SyncHistory) Summary(key types.NamespacedName) *v1alpha1.SyncSummary {
+	attempts := h.Attempts(key)
+	if len(attempts) == 0 {
+		return nil
+	}
+	summary := &v1alpha1.SyncSummary{Attempts: len(attempts)}
+	for i, attempt := range attempts {
+		if i > 0 && attempt.Outcome != attempts[i-1].Outcome {
+			summary.Transitions++
+		}
+		if attempt.Outcome == SyncOutcomeFailure {
+			summary.Failures++
+			// the status keeps the time in seconds
+			failureTime := metav1.NewTime(attempt.Time.Truncate(time.Second))
+			summary.LastFailureTime = &failureTime
+			summary.LastError = attempt.Error
+		}
+	}
+	summary.Flapping = summary.Transitions >= flappingTransitions
+	return summary
+}
+
+// SyncSummaryEqual returns whether the summaries are the same, the times are compared as instants.
+func SyncSummaryEqual(a, b *v1alpha1.SyncSummary) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Attempts == b.Attempts && a.Failures == b.Failures && a.Transitions == b.Transitions &&
+		a.Flapping == b.Flapping && a.LastError == b.LastError && a.LastFailureTime.Equal(b.LastFailureTime)
+}
@@ -0,0 +1,83 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncHistory(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	history := NewSyncHistory(3)
+	history.now = func() time.Time { return now }
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	assert.Nil(t, history.Summary(key))
+
+	sync := func(err error) {
+		history.Started(key)
+		now = now.Add(2 * time.Second)
+		if err != nil {
+			history.Failed(key, err)
+		} else {
+			history.Succeeded(key)
+		}
+	}
+	sync(nil)
+	sync(errors.New("failed 1"))
+	sync(nil)
+	sync(errors.New("failed 2"))
+
+	// the oldest attempt is overwritten
+	attempts := history.Attempts(key)
+	assert.Equal(t, 3, len(attempts))
+	assert.Equal(t, []string{SyncOutcomeFailure, SyncOutcomeSuccess, SyncOutcomeFailure},
+		[]string{attempts[0].Outcome, attempts[1].Outcome, attempts[2].Outcome})
+	assert.Equal(t, "failed 2", attempts[2].Error)
+	assert.Equal(t, 2.0, attempts[2].DurationSeconds)
+	assert.Equal(t, attempts, history.All()[key.String()])
+
+	summary := history.Summary(key)
+	assert.Equal(t, 3, summary.Attempts)
+	assert.Equal(t, 2, summary.Failures)
+	assert.Equal(t, 2, summary.Transitions)
+	assert.False(t, summary.Flapping)
+	assert.Equal(t, "failed 2", summary.LastError)
+	assert.True(t, summary.LastFailureTime.Time.Equal(now))
+	assert.True(t, SyncSummaryEqual(summary, history.Summary(key)))
+
+	sync(nil)
+	assert.False(t, SyncSummaryEqual(summary, history.Summary(key)))
+
+	history.Forget(key)
+	assert.Nil(t, history.Attempts(key))
+	assert.Empty(t, history.All())
+
+	// no-op on a nil history
+	var nilHistory *SyncHistory
+	nilHistory.Started(key)
+	nilHistory.Failed(key, errors.New("failed"))
+	assert.Nil(t, nilHistory.Summary(key))
+}
+
+func TestSyncHistory_Flapping(t *testing.T) {
+	history := NewSyncHistory(0)
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	for i := 0; i < 5; i++ {
+		if i%2 == 0 {
+			history.Succeeded(key)
+		} else {
+			history.Failed(key, errors.New("failed"))
+		}
+	}
+	summary := history.Summary(key)
+	assert.Equal(t, 4, summary.Transitions)
+	assert.True(t, summary.Flapping)
+	// the duration is unknown without the start
+	assert.Equal(t, 0.0, history.Attempts(key)[0].DurationSeconds)
+}
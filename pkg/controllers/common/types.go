@@ -7,19 +7,26 @@ import (
 )
 
 const (
-	MetricResTypeSecurityPolicy    = "securitypolicy"
-	MetricResTypeNetworkPolicy     = "networkpolicy"
-	MetricResTypeIPPool            = "ippool"
-	MetricResTypeNSXServiceAccount = "nsxserviceaccount"
-	MetricResTypeSubnetPort        = "subnetport"
-	MetricResTypeStaticRoute       = "staticroute"
-	MetricResTypeSubnet            = "subnet"
-	MetricResTypeSubnetSet         = "subnetset"
-	MetricResTypeVPC               = "vpc"
-	MetricResTypeNamespace         = "namespace"
-	MetricResTypePod               = "pod"
-	MetricResTypeNode              = "node"
-	MaxConcurrentReconciles        = 8
+	MetricResTypeSecurityPolicy     = "securitypolicy"
+	MetricResTypeNetworkPolicy      = "networkpolicy"
+	MetricResTypeIPPool             = "ippool"
+	MetricResTypeNSXServiceAccount  = "nsxserviceaccount"
+	MetricResTypeSubnetPort         = "subnetport"
+	MetricResTypeStaticRoute        = "staticroute"
+	MetricResTypeSubnetPolicy       = "subnetpolicy"
+	MetricResTypeServiceExposure    = "serviceexposure"
+	MetricResTypeSecurityExclusion  = "securityexclusion"
+	MetricResTypeGatewayPolicy      = "gatewaypolicy"
+	MetricResTypeIDSPolicy          = "idspolicy"
+	MetricResTypeAdminNetworkPolicy = "adminnetworkpolicy"
+	MetricResTypeDefaultDeny        = "defaultdeny"
+	MetricResTypeSubnet             = "subnet"
+	MetricResTypeSubnetSet          = "subnetset"
+	MetricResTypeVPC                = "vpc"
+	MetricResTypeNamespace          = "namespace"
+	MetricResTypePod                = "pod"
+	MetricResTypeNode               = "node"
+	MaxConcurrentReconciles         = 8
 
 	LabelK8sMasterRole  = "node-role.kubernetes.io/master"
 	LabelK8sControlRole = "node-role.kubernetes.io/control-plane"
@@ -35,8 +42,23 @@ var (
 )
 
 const (
-	ReasonSuccessfulDelete = "SuccessfulDelete"
-	ReasonSuccessfulUpdate = "SuccessfulUpdate"
-	ReasonFailDelete       = "FailDelete"
-	ReasonFailUpdate       = "FailUpdate"
+	ReasonSuccessfulDelete      = "SuccessfulDelete"
+	ReasonSuccessfulUpdate      = "SuccessfulUpdate"
+	ReasonFailDelete            = "FailDelete"
+	ReasonFailUpdate            = "FailUpdate"
+	ReasonApproachingLimit      = "ApproachingLimit"
+	ReasonFlowsBlocked          = "FlowsBlocked"
+	ReasonOwnershipConflict     = "OwnershipConflict"
+	ReasonMassDeletionPaused    = "MassDeletionPaused"
+	ReasonMassDeletionConfirmed = "MassDeletionConfirmed"
+	ReasonNSXResourcesChanged   = "NSXResourcesChanged"
+	ReasonPolicyProtected       = "PolicyProtected"
+	ReasonSupersededDeleted     = "SupersededDeleted"
+	ReasonDraftPublished        = "DraftPublished"
+	ReasonDraftPublishFailed    = "DraftPublishFailed"
+	ReasonSyncFlapping          = "SyncFlapping"
+	ReasonNSXDriftDetected      = "NSXDriftDetected"
+	ReasonPriorityConflict      = "PriorityConflict"
+	ReasonDeletionStuck         = "DeletionStuck"
+	ReasonFinalizerForced       = "FinalizerForced"
 )
@@ -13,8 +13,11 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
 	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
 )
 
@@ -147,3 +150,37 @@ func GetVirtualMachineNameForSubnetPort(subnetPort *v1alpha1.SubnetPort) (string
 func NumReconcile() int {
 	return MaxConcurrentReconciles
 }
+
+// ErrorMessage returns the message of the error surfaced in CR conditions and events,
+// the structured detail is used for the error returned by NSX API instead of its raw body.
+func ErrorMessage(err error) string {
+	if apiErr := nsxutil.ParseAPIError(err); apiErr != nil {
+		return apiErr.Error()
+	}
+	return fmt.Sprintf("%v", err)
+}
+
+// RecordNSXAPIError increases the NSX API error metric labeled with the error code and module
+// if the error is returned by NSX API, the error is kept in the recent errors of the diagnostics.
+func RecordNSXAPIError(cf *config.NSXOperatorConfig, resType string, err error) {
+	if apiErr := nsxutil.ParseAPIError(err); apiErr != nil {
+		metrics.CounterIncWithLabels(cf, metrics.NSXAPIErrorTotal, resType, apiErr.ErrorCodeLabel(), apiErr.ModuleName)
+		recordRecentNSXAPIError(resType, apiErr)
+	}
+}
+
+// GetNSXSite returns the NSX site the object targets, the annotation on the object takes precedence over
+// the one on its Namespace. Empty string means the default NSX endpoint.
+func GetNSXSite(client k8sclient.Client, obj metav1.Object) (string, error) {
+	if site, ok := obj.GetAnnotations()[servicecommon.AnnotationNSXSite]; ok {
+		return site, nil
+	}
+	if obj.GetNamespace() == "" {
+		return "", nil
+	}
+	ns := &v1.Namespace{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		return "", err
+	}
+	return ns.Annotations[servicecommon.AnnotationNSXSite], nil
+}
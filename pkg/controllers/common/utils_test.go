@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 )
@@ -57,3 +59,24 @@ func TestGetVirtualMachineNameForSubnetPort(t *testing.T) {
 		}
 	}
 }
+
+func TestGetNSXSite(t *testing.T) {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ns1",
+		Annotations: map[string]string{"nsx.vmware.com/nsx_site": "site-a"},
+	}}
+	client := fake.NewClientBuilder().WithObjects(ns).Build()
+
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1"}}
+	site, err := GetNSXSite(client, sp)
+	assert.Nil(t, err)
+	assert.Equal(t, "site-a", site)
+
+	sp.Annotations = map[string]string{"nsx.vmware.com/nsx_site": "site-b"}
+	site, err = GetNSXSite(client, sp)
+	assert.Nil(t, err)
+	assert.Equal(t, "site-b", site)
+
+	_, err = GetNSXSite(client, &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "sp1"}})
+	assert.NotNil(t, err)
+}
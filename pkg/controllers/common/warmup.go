@@ -0,0 +1,119 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+var (
+	warmupGatesLock = &sync.Mutex{}
+	warmupGates     []*WarmupGate
+	// processStart is the start of the warm-up, which covers the initialization of the NSX stores.
+	processStart = time.Now()
+)
+
+// WarmupGate is the readiness gate of a controller. The NSX stores of the controller are initialized
+// before the gate is created, and the gate is opened once the informers of the resources the controller
+// reads are synced. The reconciles and the garbage collection of the controller wait for the gate, so
+// they never see a partial state, e.g. delete the NSX resources of the CRs not yet in cache.
+type WarmupGate struct {
+	resType   string
+	nsxConfig *config.NSXOperatorConfig
+	informers cache.Informers
+	objects   []client.Object
+
+	ready chan struct{}
+	now   func() time.Time
+}
+
+// NewWarmupGate creates a gate for the resource type which waits for the informers of the objects, and
+// registers it so that it is covered by CheckWarmup. The gate is opened when it's started by the manager.
+func NewWarmupGate(resType string, cf *config.NSXOperatorConfig, informers cache.Informers, objects ...client.Object) *WarmupGate {
+	g := &WarmupGate{
+		resType:   resType,
+		nsxConfig: cf,
+		informers: informers,
+		objects:   objects,
+		ready:     make(chan struct{}),
+		now:       time.Now,
+	}
+	warmupGatesLock.Lock()
+	warmupGates = append(warmupGates, g)
+	warmupGatesLock.Unlock()
+	return g
+}
+
+// Start opens the gate once the informers of the objects and all the other informers started are synced,
+// the warm-up time is reported by the controller_warmup_seconds metric.
+func (g *WarmupGate) Start(ctx context.Context) error {
+	for _, obj := range g.objects {
+		// the informer is created if it's not watched yet, GetInformer blocks until it's synced
+		if _, err := g.informers.GetInformer(ctx, obj); err != nil {
+			return fmt.Errorf("failed to warm up %s controller: %w", g.resType, err)
+		}
+	}
+	if !g.informers.WaitForCacheSync(ctx) {
+		return fmt.Errorf("failed to warm up %s controller: caches are not synced", g.resType)
+	}
+	warmup := g.now().Sub(processStart)
+	metrics.GaugeSet(g.nsxConfig, metrics.ControllerWarmupSeconds, warmup.Seconds(), g.resType)
+	log.Info("controller warmed up", "type", g.resType, "duration", warmup.Round(time.Millisecond))
+	close(g.ready)
+	return nil
+}
+
+// NeedLeaderElection returns false, the caches are synced on every replica.
+func (g *WarmupGate) NeedLeaderElection() bool {
+	return false
+}
+
+// Ready returns true if the gate is opened.
+// Ready and Wait are not gated on a nil gate.
+func (g *WarmupGate) Ready() bool {
+	if g == nil {
+		return true
+	}
+	select {
+	case <-g.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the gate is opened, it returns false if it's cancelled before.
+func (g *WarmupGate) Wait(cancel chan bool) bool {
+	if g == nil {
+		return true
+	}
+	select {
+	case <-g.ready:
+		return true
+	case <-cancel:
+		return false
+	}
+}
+
+// CheckWarmup is a readyz checker which fails until all the controllers are warmed up.
+func CheckWarmup(_ *http.Request) error {
+	warmupGatesLock.Lock()
+	defer warmupGatesLock.Unlock()
+	for _, g := range warmupGates {
+		if !g.Ready() {
+			return fmt.Errorf("%s controller is warming up", g.resType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+type fakeInformers struct {
+	cache.Informers
+	informed []client.Object
+	err      error
+	synced   bool
+}
+
+func (f *fakeInformers) GetInformer(_ context.Context, obj client.Object, _ ...cache.InformerGetOption) (cache.Informer, error) {
+	f.informed = append(f.informed, obj)
+	return nil, f.err
+}
+
+func (f *fakeInformers) WaitForCacheSync(_ context.Context) bool {
+	return f.synced
+}
+
+func TestWarmupGate(t *testing.T) {
+	informers := &fakeInformers{err: errors.New("timeout")}
+	g := NewWarmupGate(MetricResTypeSecurityPolicy, nil, informers, &v1alpha1.SecurityPolicy{}, &v1.Namespace{})
+	defer func() {
+		warmupGatesLock.Lock()
+		warmupGates = warmupGates[:len(warmupGates)-1]
+		warmupGatesLock.Unlock()
+	}()
+	assert.False(t, g.NeedLeaderElection())
+	ctx := context.TODO()
+
+	// the gate is closed until the informers are synced
+	assert.EqualError(t, g.Start(ctx), "failed to warm up securitypolicy controller: timeout")
+	informers.err = nil
+	assert.EqualError(t, g.Start(ctx), "failed to warm up securitypolicy controller: caches are not synced")
+	assert.False(t, g.Ready())
+	assert.EqualError(t, CheckWarmup(nil), "securitypolicy controller is warming up")
+	cancel := make(chan bool, 1)
+	cancel <- true
+	assert.False(t, g.Wait(cancel))
+
+	informers.synced = true
+	assert.NoError(t, g.Start(ctx))
+	assert.Equal(t, []client.Object{&v1alpha1.SecurityPolicy{}, &v1.Namespace{}}, informers.informed[len(informers.informed)-2:])
+	assert.True(t, g.Ready())
+	assert.True(t, g.Wait(make(chan bool)))
+	assert.NoError(t, CheckWarmup(nil))
+
+	var nilGate *WarmupGate
+	assert.True(t, nilGate.Ready())
+	assert.True(t, nilGate.Wait(make(chan bool)))
+}
@@ -0,0 +1,37 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"sync"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+var (
+	webhookServer     webhook.Server
+	webhookServerLock = &sync.Mutex{}
+)
+
+// GetWebhookServer returns the webhook server shared by the controllers, it is created and
+// added to the manager on first call.
+func GetWebhookServer(mgr ctrl.Manager) (webhook.Server, error) {
+	webhookServerLock.Lock()
+	defer webhookServerLock.Unlock()
+	if webhookServer != nil {
+		return webhookServer, nil
+	}
+	hookServer := webhook.NewServer(webhook.Options{
+		Port:    config.WebhookServerPort,
+		CertDir: config.WebhookCertDir,
+	})
+	if err := mgr.Add(hookServer); err != nil {
+		return nil, err
+	}
+	webhookServer = hookServer
+	return webhookServer, nil
+}
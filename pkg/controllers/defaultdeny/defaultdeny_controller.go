@@ -0,0 +1,195 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package defaultdeny
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeDefaultDeny
+)
+
+// DefaultDenyReconciler realizes the default deny of the Namespaces annotated with nsx.vmware.com/default_deny,
+// the value is ingress, egress or all. The default deny is removed once the annotation is removed, an invalid
+// value is reported as a Warning event on the Namespace and keeps the default deny realized before.
+type DefaultDenyReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *securitypolicy.SecurityPolicyService
+	Recorder record.EventRecorder
+}
+
+func (r *DefaultDenyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "namespace", req.Name)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	ns := &v1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, ns); err != nil {
+		// the default deny of a Namespace which is gone is collected by the GC
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+	log.Info("reconciling namespace default deny", "namespace", ns.Name)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	value, annotated := ns.Annotations[servicecommon.AnnotationDefaultDeny]
+	if !annotated || !ns.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteNamespaceDefaultDeny(ns.UID); err != nil {
+			log.Error(err, "delete failed, would retry exponentially", "namespace", ns.Name)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			return ResultRequeue, err
+		}
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		return ResultNormal, nil
+	}
+
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+	ingress, egress, err := securitypolicy.ParseDefaultDeny(value)
+	if err != nil {
+		log.Error(err, "invalid namespace default deny", "namespace", ns.Name)
+		r.Recorder.Event(ns, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		// the invalid value is not retried until the annotation is changed
+		return ResultNormal, nil
+	}
+	if err := r.Service.CreateOrUpdateNamespaceDefaultDeny(ns, ingress, egress); err != nil {
+		log.Error(err, "failed to realize namespace default deny", "namespace", ns.Name)
+		r.Recorder.Event(ns, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		return ResultRequeue, err
+	}
+	r.Recorder.Event(ns, v1.EventTypeNormal, common.ReasonSuccessfulUpdate,
+		fmt.Sprintf("Default deny of %s traffic has been successfully realized", value))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+	return ResultNormal, nil
+}
+
+// predicateFuncsNamespace filters the events of the Namespaces changing their default deny, the Namespaces
+// without the annotation are reconciled on restart in case the annotation was removed meanwhile.
+var predicateFuncsNamespace = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetAnnotations()[servicecommon.AnnotationDefaultDeny] != e.ObjectNew.GetAnnotations()[servicecommon.AnnotationDefaultDeny] ||
+			!e.ObjectNew.GetDeletionTimestamp().IsZero()
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		// the default deny of a deleted Namespace is collected by the GC
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *DefaultDenyReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("namespace-default-deny").
+		For(&v1.Namespace{}, builder.WithPredicates(predicateFuncsNamespace)).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *DefaultDenyReconciler) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector collects the default deny of the Namespaces which have been removed or are not annotated
+// any more.
+// cancel is used to break the loop during UT
+func (r *DefaultDenyReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		r.collectGarbage(ctx)
+	}
+}
+
+func (r *DefaultDenyReconciler) collectGarbage(ctx context.Context) {
+	nsxPolicySet := r.Service.ListNamespaceDefaultDenyID()
+	if len(nsxPolicySet) == 0 {
+		return
+	}
+
+	nsList := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList); err != nil {
+		log.Error(err, "failed to list namespaces")
+		return
+	}
+	nsSet := sets.New[string]()
+	for _, ns := range nsList.Items {
+		if _, annotated := ns.Annotations[servicecommon.AnnotationDefaultDeny]; annotated {
+			nsSet.Insert(string(ns.UID))
+		}
+	}
+
+	for elem := range nsxPolicySet {
+		uid := securitypolicy.NamespaceDefaultDenyUIDOf(elem)
+		if nsSet.Has(uid) {
+			continue
+		}
+		log.V(1).Info("GC collected namespace default deny", "UID", uid)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteNamespaceDefaultDeny(types.UID(uid)); err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+		} else {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		}
+	}
+}
+
+func StartDefaultDenyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+	defaultDenyReconcile := DefaultDenyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("defaultdeny-controller"),
+	}
+	defaultDenyReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
+	if err := defaultDenyReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "DefaultDeny")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,100 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package defaultdeny
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+type realized struct {
+	ingress, egress bool
+}
+
+func TestDefaultDenyReconciler_Reconcile(t *testing.T) {
+	service := &securitypolicy.SecurityPolicyService{
+		Service: servicecommon.Service{NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}}},
+	}
+	defaultDenies := map[types.UID]realized{}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(service), "CreateOrUpdateNamespaceDefaultDeny",
+		func(_ *securitypolicy.SecurityPolicyService, ns *v1.Namespace, ingress, egress bool) error {
+			defaultDenies[ns.UID] = realized{ingress, egress}
+			return nil
+		})
+	patches.ApplyMethod(reflect.TypeOf(service), "DeleteNamespaceDefaultDeny",
+		func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+			delete(defaultDenies, uid)
+			return nil
+		})
+	patches.ApplyMethod(reflect.TypeOf(service), "ListNamespaceDefaultDenyID",
+		func(_ *securitypolicy.SecurityPolicyService) sets.Set[string] {
+			ids := sets.New[string]()
+			for uid := range defaultDenies {
+				ids.Insert(service.BuildNamespaceDefaultDenyID(uid))
+			}
+			return ids
+		})
+	defer patches.Reset()
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "ns1", UID: "uid1", Annotations: map[string]string{servicecommon.AnnotationDefaultDeny: "ingress"},
+	}}
+	k8sClient := fake.NewClientBuilder().WithObjects(ns).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &DefaultDenyReconciler{Client: k8sClient, Service: service, Recorder: recorder}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "ns1"}}
+
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, realized{ingress: true}, defaultDenies["uid1"])
+	assert.Contains(t, <-recorder.Events, "SuccessfulUpdate")
+
+	// the default deny realized before is kept if the annotation is invalid
+	ns.Annotations[servicecommon.AnnotationDefaultDeny] = "both"
+	assert.NoError(t, k8sClient.Update(ctx, ns))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, realized{ingress: true}, defaultDenies["uid1"])
+	assert.Contains(t, <-recorder.Events, "invalid nsx.vmware.com/default_deny")
+
+	// the default deny is removed with the annotation
+	ns.Annotations = nil
+	assert.NoError(t, k8sClient.Update(ctx, ns))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Empty(t, defaultDenies)
+
+	// the default deny of the Namespaces which are gone or not annotated is collected
+	defaultDenies["uid1"] = realized{egress: true}
+	defaultDenies["uid2"] = realized{egress: true}
+	r.collectGarbage(ctx)
+	assert.Empty(t, defaultDenies)
+}
+
+func TestPredicateFuncsNamespace(t *testing.T) {
+	oldNs := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+	newNs := oldNs.DeepCopy()
+	newNs.Labels = map[string]string{"team": "web"}
+	assert.False(t, predicateFuncsNamespace.Update(event.UpdateEvent{ObjectOld: oldNs, ObjectNew: newNs}))
+	newNs.Annotations = map[string]string{servicecommon.AnnotationDefaultDeny: "all"}
+	assert.True(t, predicateFuncsNamespace.Update(event.UpdateEvent{ObjectOld: oldNs, ObjectNew: newNs}))
+	assert.False(t, predicateFuncsNamespace.Delete(event.DeleteEvent{Object: newNs}))
+}
@@ -0,0 +1,73 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package gatewaypolicy
+
+import (
+	"os"
+
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeGatewayPolicy
+)
+
+// GatewayPolicyReconciler reconciles a GatewayPolicy object, the reconcile, finalizer and garbage collection flow is the one
+// shared by the policy CRs.
+type GatewayPolicyReconciler struct {
+	common.PolicyReconciler[*v1alpha1.GatewayPolicy]
+	Scheme  *apimachineryruntime.Scheme
+	Service *securitypolicy.SecurityPolicyService
+}
+
+func NewGatewayPolicyReconciler(c client.Client, scheme *apimachineryruntime.Scheme, service *securitypolicy.SecurityPolicyService,
+	recorder record.EventRecorder, deletionGuard *common.DeletionGuard) *GatewayPolicyReconciler {
+	return &GatewayPolicyReconciler{
+		PolicyReconciler: common.PolicyReconciler[*v1alpha1.GatewayPolicy]{
+			Client:        c,
+			Recorder:      recorder,
+			DeletionGuard: deletionGuard,
+			NSXConfig:     service.NSXConfig,
+			Kind:          "GatewayPolicy",
+			ResType:       MetricResType,
+			Finalizer:     commonservice.GatewayPolicyFinalizerName,
+			Subject:       "NSX GatewayPolicy",
+			NewObject: func() *v1alpha1.GatewayPolicy {
+				return &v1alpha1.GatewayPolicy{}
+			},
+			NewList: func() client.ObjectList {
+				return &v1alpha1.GatewayPolicyList{}
+			},
+			Conditions: func(obj *v1alpha1.GatewayPolicy) *[]v1alpha1.Condition {
+				return &obj.Status.Conditions
+			},
+			CreateOrUpdate: service.CreateOrUpdateGatewayPolicy,
+			Delete:         service.DeleteGatewayPolicy,
+			ListIDs:        service.ListGatewayPolicyID,
+		},
+		Scheme:  scheme,
+		Service: service,
+	}
+}
+
+func StartGatewayPolicyController(mgr ctrl.Manager, gatewayPolicyService *securitypolicy.SecurityPolicyService, deletionGuard *common.DeletionGuard) {
+	gatewayPolicyReconcile := NewGatewayPolicyReconciler(mgr.GetClient(), mgr.GetScheme(), gatewayPolicyService,
+		mgr.GetEventRecorderFor("gatewaypolicy-controller"), deletionGuard)
+	if err := gatewayPolicyReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "GatewayPolicy")
+		os.Exit(1)
+	}
+}
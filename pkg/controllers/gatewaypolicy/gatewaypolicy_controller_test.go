@@ -0,0 +1,172 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package gatewaypolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newFakeGatewayPolicyReconciler(objs ...client.Object) *GatewayPolicyReconciler {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	service := &securitypolicy.SecurityPolicyService{
+		Service: commonservice.Service{
+			NSXConfig: &config.NSXOperatorConfig{
+				NsxConfig: &config.NsxConfig{},
+				K8sConfig: &config.K8sConfig{},
+			},
+		},
+	}
+	return NewGatewayPolicyReconciler(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.GatewayPolicy{}).Build(), scheme, service, record.NewFakeRecorder(100), nil)
+}
+
+func TestGatewayPolicyReconciler_Reconcile(t *testing.T) {
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "gp1"}}
+	gp := &v1alpha1.GatewayPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "gp1", UID: "uid1"}}
+	r := newFakeGatewayPolicyReconciler(gp)
+
+	// the reconciles wait for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultRequeueAfter10sec, result)
+	r.Warmup = nil
+
+	// not found
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "gp2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// the finalizer is added and the GatewayPolicy is realized
+	var s *securitypolicy.SecurityPolicyService
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "CreateOrUpdateGatewayPolicy",
+		func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.GatewayPolicy) error {
+			return nil
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	obj := &v1alpha1.GatewayPolicy{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, []string{commonservice.GatewayPolicyFinalizerName}, obj.Finalizers)
+	assert.Equal(t, v1.ConditionTrue, obj.Status.Conditions[0].Status)
+	patches.Reset()
+
+	// the restriction errors are not retried
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "CreateOrUpdateGatewayPolicy",
+		func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.GatewayPolicy) error {
+			return nsxutil.RestrictionError{Desc: "not supported"}
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	patches.Reset()
+
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "CreateOrUpdateGatewayPolicy",
+		func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.GatewayPolicy) error {
+			return errors.New("create failed")
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, v1.ConditionFalse, obj.Status.Conditions[0].Status)
+	patches.Reset()
+
+	// the finalizer is kept until the NSX resources are deleted
+	assert.NoError(t, r.Client.Delete(ctx, obj))
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteGatewayPolicy",
+		func(_ *securitypolicy.SecurityPolicyService, _ types.UID) error {
+			return errors.New("delete failed")
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	patches.Reset()
+
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteGatewayPolicy",
+		func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+			assert.Equal(t, types.UID("uid1"), uid)
+			return nil
+		})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.True(t, apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, obj)))
+}
+
+func TestGatewayPolicyReconciler_GarbageCollector(t *testing.T) {
+	gp := &v1alpha1.GatewayPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "gp1", UID: "uid1"}}
+	r := newFakeGatewayPolicyReconciler(gp, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system"}})
+	r.DeletionGuard = common.NewDeletionGuard(MetricResType, r.Service.NSXConfig, r.Client, r.Recorder, "nsx-system")
+
+	var s *securitypolicy.SecurityPolicyService
+	var deleted []string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "ListGatewayPolicyID", func(_ *securitypolicy.SecurityPolicyService) sets.Set[string] {
+		return sets.New[string]("uid1", "uid2", "uid3")
+	})
+	patches.ApplyMethod(reflect.TypeOf(s), "DeleteGatewayPolicy", func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+		deleted = append(deleted, string(uid))
+		return nil
+	})
+	defer patches.Reset()
+
+	// the garbage collection waits for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, time.Millisecond)
+	assert.Empty(t, deleted)
+	r.Warmup = nil
+
+	// the mass deletion is paused until it's confirmed
+	r.Service.NSXConfig.MassDeletionMaxCount = 1
+	r.Service.NSXConfig.MassDeletionMaxPercent = 50
+	assert.Error(t, r.CollectGarbage(context.TODO()))
+	assert.Empty(t, deleted)
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system", Annotations: map[string]string{common.AnnotationConfirmMassDeletion: "2"}}}
+	assert.NoError(t, r.Client.Update(context.TODO(), ns))
+	assert.NoError(t, r.CollectGarbage(context.TODO()))
+	assert.ElementsMatch(t, []string{"uid2", "uid3"}, deleted)
+}
+
+func TestGatewayPolicyReconciler_Start(t *testing.T) {
+	r := newFakeGatewayPolicyReconciler()
+	var mgr ctrl.Manager
+	assert.Error(t, r.Start(mgr))
+}
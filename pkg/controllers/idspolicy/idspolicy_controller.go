@@ -0,0 +1,73 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package idspolicy
+
+import (
+	"os"
+
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeIDSPolicy
+)
+
+// IDSPolicyReconciler reconciles a IDSPolicy object, the reconcile, finalizer and garbage collection flow is the one
+// shared by the policy CRs.
+type IDSPolicyReconciler struct {
+	common.PolicyReconciler[*v1alpha1.IDSPolicy]
+	Scheme  *apimachineryruntime.Scheme
+	Service *securitypolicy.SecurityPolicyService
+}
+
+func NewIDSPolicyReconciler(c client.Client, scheme *apimachineryruntime.Scheme, service *securitypolicy.SecurityPolicyService,
+	recorder record.EventRecorder, deletionGuard *common.DeletionGuard) *IDSPolicyReconciler {
+	return &IDSPolicyReconciler{
+		PolicyReconciler: common.PolicyReconciler[*v1alpha1.IDSPolicy]{
+			Client:        c,
+			Recorder:      recorder,
+			DeletionGuard: deletionGuard,
+			NSXConfig:     service.NSXConfig,
+			Kind:          "IDSPolicy",
+			ResType:       MetricResType,
+			Finalizer:     commonservice.IDSPolicyFinalizerName,
+			Subject:       "NSX IDS/IPS policy",
+			NewObject: func() *v1alpha1.IDSPolicy {
+				return &v1alpha1.IDSPolicy{}
+			},
+			NewList: func() client.ObjectList {
+				return &v1alpha1.IDSPolicyList{}
+			},
+			Conditions: func(obj *v1alpha1.IDSPolicy) *[]v1alpha1.Condition {
+				return &obj.Status.Conditions
+			},
+			CreateOrUpdate: service.CreateOrUpdateIDSPolicy,
+			Delete:         service.DeleteIDSPolicy,
+			ListIDs:        service.ListIDSPolicyID,
+		},
+		Scheme:  scheme,
+		Service: service,
+	}
+}
+
+func StartIDSPolicyController(mgr ctrl.Manager, idsPolicyService *securitypolicy.SecurityPolicyService, deletionGuard *common.DeletionGuard) {
+	idsPolicyReconcile := NewIDSPolicyReconciler(mgr.GetClient(), mgr.GetScheme(), idsPolicyService,
+		mgr.GetEventRecorderFor("idspolicy-controller"), deletionGuard)
+	if err := idsPolicyReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "IDSPolicy")
+		os.Exit(1)
+	}
+}
@@ -174,6 +174,67 @@ func (r *NamespaceReconciler) insertNamespaceNetworkconfigBinding(ns string, ann
 	return nil
 }
 
+// errInvalidNamespace is returned by provisionNamespace if the annotations of the namespace are invalid, retrying
+// doesn't help until the namespace is updated.
+var errInvalidNamespace = errors.New("invalid namespace")
+
+// provisionNamespace creates the VPC CR and the default SubnetSets of the namespace. It returns a nil VPC without
+// error if the namespace shares the VPC of another namespace, the VPC is created for that namespace.
+func (r *NamespaceReconciler) provisionNamespace(obj *v1.Namespace) (*v1alpha1.VPC, error) {
+	ns := obj.GetName()
+	ctx := context.Background()
+	annotations := obj.GetAnnotations()
+	err := r.insertNamespaceNetworkconfigBinding(ns, annotations)
+	if err != nil {
+		log.Error(err, "failed to build namespace and network config bindings", "Namepspace", ns)
+		return nil, err
+	}
+	// read anno "nsx.vmware.com/vpc_name", if ns contains this annotation, it means it will share
+	// infra VPC, if the ns in the annotation is the same as ns event, create infra VPC, if not,
+	// skip the event.
+	ncName, ncExist := annotations[types.AnnotationVPCNetworkConfig]
+	vpcName, nameExist := annotations[types.AnnotationVPCName]
+	var createVpcName *string
+	if nameExist {
+		log.Info("read ns annotation vpcName", "VPCNAME", vpcName)
+		res := strings.Split(vpcName, "/")
+		// The format should be namespace/vpc_name
+		if len(res) != 2 {
+			message := fmt.Sprintf("incorrect vpcName annotation %s for namespace %s", vpcName, ns)
+			r.namespaceError(&ctx, obj, message, nil)
+			// If illegal format, skip handling this event?
+			return nil, fmt.Errorf("%w: %s", errInvalidNamespace, message)
+		}
+		log.Info("start to handle vpcName anno", "VPCNS", res[1], "NS", ns)
+
+		if ns != res[0] {
+			log.Info("name space is using shared vpc, with vpc name anno", "VPCNAME", vpcName, "Namespace", ns)
+			return nil, nil
+		}
+		createVpcName = &res[1]
+		log.Info("creating vpc using customer defined vpc name", "VPCName", res[1])
+	}
+
+	// If ns do not have network config name tag, then use default vpc network config name
+	if !ncExist {
+		log.Info("network config name not found on ns, using default network config", "Namespace", ns)
+		ncName, err = r.getDefaultNetworkConfigName()
+		if err != nil {
+			log.Error(err, "failed to get default network config name", "Namespace", ns)
+			return nil, err
+		}
+	}
+
+	vpcCR, err := r.createVPCCR(&ctx, obj, ns, ncName, createVpcName)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.createDefaultSubnetSet(ns); err != nil {
+		return nil, err
+	}
+	return vpcCR, nil
+}
+
 /*
 	VPC creation strategy:
 
@@ -204,53 +265,10 @@ func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateTotal, common.MetricResTypeNamespace)
 		log.Info("start processing namespace create/update event", "namespace", ns)
 
-		ctx := context.Background()
-		annotations := obj.GetAnnotations()
-		err := r.insertNamespaceNetworkconfigBinding(ns, annotations)
-		if err != nil {
-			log.Error(err, "failed to build namespace and network config bindings", "Namepspace", ns)
-			return common.ResultRequeueAfter10sec, nil
-		}
-		// read anno "nsx.vmware.com/vpc_name", if ns contains this annotation, it means it will share
-		// infra VPC, if the ns in the annotation is the same as ns event, create infra VPC, if not,
-		// skip the event.
-		ncName, ncExist := annotations[types.AnnotationVPCNetworkConfig]
-		vpcName, nameExist := annotations[types.AnnotationVPCName]
-		var createVpcName *string
-		if nameExist {
-			log.Info("read ns annotation vpcName", "VPCNAME", vpcName)
-			res := strings.Split(vpcName, "/")
-			// The format should be namespace/vpc_name
-			if len(res) != 2 {
-				message := fmt.Sprintf("incorrect vpcName annotation %s for namespace %s", vpcName, ns)
-				r.namespaceError(&ctx, obj, message, nil)
-				// If illegal format, skip handling this event?
+		if _, err := r.provisionNamespace(obj); err != nil {
+			if errors.Is(err, errInvalidNamespace) {
 				return common.ResultNormal, nil
 			}
-			log.Info("start to handle vpcName anno", "VPCNS", res[1], "NS", ns)
-
-			if ns != res[0] {
-				log.Info("name space is using shared vpc, with vpc name anno", "VPCNAME", vpcName, "Namespace", ns)
-				return common.ResultNormal, nil
-			}
-			createVpcName = &res[1]
-			log.Info("creating vpc using customer defined vpc name", "VPCName", res[1])
-		}
-
-		// If ns do not have network config name tag, then use default vpc network config name
-		if !ncExist {
-			log.Info("network config name not found on ns, using default network config", "Namespace", ns)
-			ncName, err = r.getDefaultNetworkConfigName()
-			if err != nil {
-				log.Error(err, "failed to get default network config name", "Namespace", ns)
-				return common.ResultRequeueAfter10sec, nil
-			}
-		}
-
-		if _, err := r.createVPCCR(&ctx, obj, ns, ncName, createVpcName); err != nil {
-			return common.ResultRequeueAfter10sec, nil
-		}
-		if err := r.createDefaultSubnetSet(ns); err != nil {
 			return common.ResultRequeueAfter10sec, nil
 		}
 		return common.ResultNormal, nil
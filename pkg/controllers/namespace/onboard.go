@@ -0,0 +1,88 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+)
+
+// onboardConcurrency limits the namespaces onboarded at the same time, not to flood NSX with VPC creations.
+const onboardConcurrency = 4
+
+var (
+	onboardPollInterval = 2 * time.Second
+	// onboardReadyTimeout is how long the realization of the VPC of a namespace is waited for.
+	onboardReadyTimeout = 5 * time.Minute
+)
+
+// OnboardNamespaces provisions the VPCs and the default SubnetSets of the namespaces in one batch, like they're
+// created by the reconciles of the namespaces, and waits for the VPCs to be realized. The progress of every
+// namespace is reported as it goes, the namespaces which fail don't stop the others.
+func (r *NamespaceReconciler) OnboardNamespaces(ctx context.Context, namespaces []string, report func(common.OnboardEvent)) {
+	var lock sync.Mutex
+	reportLocked := func(event common.OnboardEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+		report(event)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, onboardConcurrency)
+	for _, ns := range namespaces {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ns string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := r.onboardNamespace(ctx, ns, reportLocked); err != nil {
+				log.Error(err, "failed to onboard namespace", "Namespace", ns)
+				reportLocked(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageFailed, Error: err.Error()})
+			}
+		}(ns)
+	}
+	wg.Wait()
+}
+
+func (r *NamespaceReconciler) onboardNamespace(ctx context.Context, ns string, report func(common.OnboardEvent)) error {
+	obj := &v1.Namespace{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ns}, obj); err != nil {
+		return err
+	}
+	if !obj.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("namespace %s is being deleted", ns)
+	}
+	vpcCR, err := r.provisionNamespace(obj)
+	if err != nil {
+		return err
+	}
+	if vpcCR == nil {
+		report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageSharedVPC})
+		return nil
+	}
+	report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageProvisioned})
+
+	key := client.ObjectKeyFromObject(vpcCR)
+	if err := wait.PollUntilContextTimeout(ctx, onboardPollInterval, onboardReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		vpc := &v1alpha1.VPC{}
+		if err := r.Client.Get(ctx, key, vpc); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return vpc.Status.NSXResourcePath != "", nil
+	}); err != nil {
+		return fmt.Errorf("VPC %s is not realized: %w", key, err)
+	}
+	report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageReady})
+	return nil
+}
@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package namespace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+)
+
+func TestOnboardNamespaces(t *testing.T) {
+	onboardPollInterval, onboardReadyTimeout = 10*time.Millisecond, 100*time.Millisecond
+	defer func() { onboardPollInterval, onboardReadyTimeout = 2*time.Second, 5*time.Minute }()
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	vpcs := map[string]*v1alpha1.VPC{
+		"ns-ready":   {ObjectMeta: metav1.ObjectMeta{Namespace: "ns-ready", Name: "vpc-ready"}, Status: v1alpha1.VPCStatus{NSXResourcePath: "/orgs/default/projects/p1/vpcs/vpc-ready"}},
+		"ns-pending": {ObjectMeta: metav1.ObjectMeta{Namespace: "ns-pending", Name: "vpc-pending"}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-ready"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-pending"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-shared"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-invalid"}},
+		vpcs["ns-ready"], vpcs["ns-pending"],
+	).Build()
+	r := createNameSpaceReconciler()
+	r.Client = k8sClient
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(r), "provisionNamespace", func(_ *NamespaceReconciler, obj *v1.Namespace) (*v1alpha1.VPC, error) {
+		if obj.Name == "ns-invalid" {
+			return nil, errors.New("missing network config")
+		}
+		return vpcs[obj.Name], nil
+	})
+	defer patches.Reset()
+
+	var events []common.OnboardEvent
+	r.OnboardNamespaces(context.TODO(), []string{"ns-ready", "ns-pending", "ns-shared", "ns-invalid", "ns-missing"}, func(event common.OnboardEvent) {
+		events = append(events, event)
+	})
+	stages := map[string][]string{}
+	for _, event := range events {
+		stages[event.Namespace] = append(stages[event.Namespace], event.Stage)
+	}
+	assert.Equal(t, map[string][]string{
+		"ns-ready":   {common.OnboardStageProvisioned, common.OnboardStageReady},
+		"ns-pending": {common.OnboardStageProvisioned, common.OnboardStageFailed},
+		"ns-shared":  {common.OnboardStageSharedVPC},
+		"ns-invalid": {common.OnboardStageFailed},
+		"ns-missing": {common.OnboardStageFailed},
+	}, stages)
+	var errs []string
+	for _, event := range events {
+		if event.Error != "" {
+			errs = append(errs, event.Error)
+		}
+	}
+	sort.Strings(errs)
+	assert.Len(t, errs, 3)
+	assert.Contains(t, errs[0], "VPC ns-pending/vpc-pending is not realized")
+	assert.Equal(t, "missing network config", errs[1])
+	assert.Contains(t, errs[2], "not found")
+}
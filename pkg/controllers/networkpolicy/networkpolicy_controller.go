@@ -6,21 +6,24 @@ package networkpolicy
 import (
 	"context"
 	"errors"
-	"fmt"
 	"os"
 	"runtime"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
@@ -41,20 +44,30 @@ var (
 
 // NetworkPolicyReconciler reconciles a NetworkPolicy object
 type NetworkPolicyReconciler struct {
-	Client   client.Client
-	Scheme   *apimachineryruntime.Scheme
-	Service  *securitypolicy.SecurityPolicyService
-	Recorder record.EventRecorder
+	Client    client.Client
+	Scheme    *apimachineryruntime.Scheme
+	Service   *securitypolicy.SecurityPolicyService
+	Recorder  record.EventRecorder
+	Tracker   *common.ReconcileTracker
+	Coalescer *common.ReconcileCoalescer
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *common.WarmupGate
+	// Tiers defers the reconciles in the new namespaces until the resources of the lower tiers are realized.
+	Tiers *common.TierGate
+	// StuckDeletion escalates the NetworkPolicies stuck deleting due to NSX errors.
+	StuckDeletion *common.StuckDeletionPolicy
 }
 
 func updateFail(r *NetworkPolicyReconciler, c *context.Context, o *networkingv1.NetworkPolicy, e *error) {
-	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, common.ErrorMessage(*e))
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+	common.RecordNSXAPIError(r.Service.NSXConfig, MetricResType, *e)
 }
 
 func deleteFail(r *NetworkPolicyReconciler, c *context.Context, o *networkingv1.NetworkPolicy, e *error) {
-	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, common.ErrorMessage(*e))
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+	common.RecordNSXAPIError(r.Service.NSXConfig, MetricResType, *e)
 }
 
 func updateSuccess(r *NetworkPolicyReconciler, c *context.Context, o *networkingv1.NetworkPolicy) {
@@ -68,16 +81,36 @@ func deleteSuccess(r *NetworkPolicyReconciler, _ *context.Context, o *networking
 }
 
 func (r *NetworkPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", "networkpolicy", req.NamespacedName)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "networkpolicy", req.NamespacedName)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	if wait := r.Coalescer.Defer(req.NamespacedName); wait > 0 {
+		log.V(1).Info("coalescing successive updates", "networkpolicy", req.NamespacedName, "after", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
 	networkPolicy := &networkingv1.NetworkPolicy{}
 	log.Info("reconciling networkpolicy", "networkpolicy", req.NamespacedName)
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+	synced := false
+	r.Tracker.Started(req.NamespacedName)
+	defer func() { r.Tracker.Done(req.NamespacedName, synced) }()
 
 	if err := r.Client.Get(ctx, req.NamespacedName, networkPolicy); err != nil {
 		log.Error(err, "unable to fetch network policy", "req", req.NamespacedName)
+		synced = apierrors.IsNotFound(err)
 		return ResultNormal, client.IgnoreNotFound(err)
 	}
 
 	if networkPolicy.ObjectMeta.DeletionTimestamp.IsZero() {
+		if wait := r.Tiers.Defer(ctx, req.Namespace); wait > 0 {
+			log.V(1).Info("waiting for the lower tiers of the new namespace", "networkpolicy", req.NamespacedName, "after", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
 		if !controllerutil.ContainsFinalizer(networkPolicy, servicecommon.NetworkPolicyFinalizerName) {
 			controllerutil.AddFinalizer(networkPolicy, servicecommon.NetworkPolicyFinalizerName)
@@ -100,13 +133,18 @@ func (r *NetworkPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			return ResultRequeue, err
 		}
 		updateSuccess(r, &ctx, networkPolicy)
+		synced = true
 	} else {
 		if controllerutil.ContainsFinalizer(networkPolicy, servicecommon.NetworkPolicyFinalizerName) {
 			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
 			if err := r.Service.DeleteSecurityPolicy(networkPolicy, false, servicecommon.ResourceTypeNetworkPolicy); err != nil {
-				log.Error(err, "deletion failed, would retry exponentially", "networkpolicy", req.NamespacedName)
-				deleteFail(r, &ctx, networkPolicy, &err)
-				return ResultRequeue, err
+				if !r.StuckDeletion.Escalate(ctx, networkPolicy, err) {
+					log.Error(err, "deletion failed, would retry exponentially", "networkpolicy", req.NamespacedName)
+					deleteFail(r, &ctx, networkPolicy, &err)
+					return ResultRequeue, err
+				}
+				// the NSX resources left are retried by the garbage collection once the CR is gone
+				log.Info("deletion stuck, removing finalizer", "networkpolicy", req.NamespacedName, "reason", err.Error())
 			}
 			controllerutil.RemoveFinalizer(networkPolicy, servicecommon.NetworkPolicyFinalizerName)
 			if err := r.Client.Update(ctx, networkPolicy); err != nil {
@@ -115,7 +153,9 @@ func (r *NetworkPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				return ResultRequeue, err
 			}
 			log.V(1).Info("removed finalizer", "networkpolicy", req.NamespacedName)
+			r.StuckDeletion.Forget(networkPolicy.UID)
 			deleteSuccess(r, &ctx, networkPolicy)
+			synced = true
 		} else {
 			// only print a message because it's not a normal case
 			log.Info("finalizers cannot be recognized", "networkpolicy", req.NamespacedName)
@@ -127,22 +167,53 @@ func (r *NetworkPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 func (r *NetworkPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&networkingv1.NetworkPolicy{}).
+		For(&networkingv1.NetworkPolicy{}, builder.WithPredicates(r.Tracker.Predicate(), r.Coalescer.Predicate())).
 		WithOptions(
 			controller.Options{
 				MaxConcurrentReconciles: runtime.NumCPU(),
 			}).
+		Watches(
+			&v1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.lbServiceMapFunc),
+			builder.WithPredicates(common.PredicateFuncsLBService),
+		).
 		Complete(r)
 }
 
+// lbServiceMapFunc reconciles the NetworkPolicies with ingress rules in the namespace of the LoadBalancer
+// Service, their rules allowing the health checks of the load balancer may be changed.
+func (r *NetworkPolicyReconciler) lbServiceMapFunc(ctx context.Context, obj client.Object) []reconcile.Request {
+	policyList := &networkingv1.NetworkPolicyList{}
+	if err := r.Client.List(ctx, policyList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list NetworkPolicy", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policyList.Items {
+		if len(policy.Spec.Ingress) == 0 {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name},
+		})
+	}
+	return requests
+}
+
 // Start setup manager and launch GC
 func (r *NetworkPolicyReconciler) Start(mgr ctrl.Manager) error {
 	err := r.setupWithManager(mgr)
 	if err != nil {
 		return err
 	}
+	r.Warmup = common.NewWarmupGate(MetricResType, r.Service.NSXConfig, mgr.GetCache(), &networkingv1.NetworkPolicy{}, &v1.Namespace{}, &v1.Pod{})
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+	r.Tiers = common.NewTierGate(common.TierPolicy, MetricResType, mgr.GetClient(), r.Service.NSXConfig)
 
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	go r.Tracker.RunReporter(make(chan bool), common.ReconcileReportInterval)
 	return nil
 }
 
@@ -151,12 +222,18 @@ func (r *NetworkPolicyReconciler) Start(mgr ctrl.Manager) error {
 func (r *NetworkPolicyReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
 	ctx := context.Background()
 	log.Info("garbage collector started")
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
 	for {
 		select {
 		case <-cancel:
 			return
 		case <-time.After(timeout):
 		}
+		if common.InNSXMaintenance() {
+			continue
+		}
 		nsxPolicySet := r.Service.ListNetworkPolicyID()
 		if len(nsxPolicySet) == 0 {
 			continue
@@ -190,13 +267,16 @@ func (r *NetworkPolicyReconciler) GarbageCollector(cancel chan bool, timeout tim
 	}
 }
 
-func StartNetworkPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+func StartNetworkPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider, stuckDeletion *common.StuckDeletionPolicy) {
 	networkPolicyReconcile := NetworkPolicyReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("networkpolicy-controller"),
 	}
 	networkPolicyReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
+	networkPolicyReconcile.Tracker = common.NewReconcileTracker(MetricResType, commonService.NSXConfig)
+	networkPolicyReconcile.Coalescer = common.NewReconcileCoalescer(MetricResType, commonService.NSXConfig)
+	networkPolicyReconcile.StuckDeletion = stuckDeletion
 	if err := networkPolicyReconcile.Start(mgr); err != nil {
 		log.Error(err, "failed to create controller", "controller", "NetworkPolicy")
 		os.Exit(1)
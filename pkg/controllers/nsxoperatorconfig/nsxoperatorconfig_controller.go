@@ -0,0 +1,173 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsxoperatorconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+const (
+	ReasonApplied  = "Applied"
+	ReasonRejected = "Rejected"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+)
+
+// NSXOperatorConfigReconciler applies the settings of NSXOperatorConfig CR to the running nsx-operator.
+type NSXOperatorConfigReconciler struct {
+	Client    client.Client
+	Scheme    *apimachineryruntime.Scheme
+	NSXConfig *config.NSXOperatorConfig
+	Recorder  record.EventRecorder
+	// baseline is the RuntimeConfig loaded from the config file, it is restored when the CR is deleted
+	// and used for the fields not set in the CR.
+	baseline config.RuntimeConfig
+}
+
+func (r *NSXOperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.NSXOperatorConfig{}
+	log.Info("reconciling NSXOperatorConfig CR", "nsxoperatorconfig", req.Name)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch NSXOperatorConfig CR", "req", req.NamespacedName)
+			return ResultRequeue, err
+		}
+		if req.Name == v1alpha1.NSXOperatorConfigName {
+			return r.restoreBaseline()
+		}
+		return ResultNormal, nil
+	}
+
+	if obj.Name != v1alpha1.NSXOperatorConfigName {
+		err := fmt.Errorf("only NSXOperatorConfig named %s is honored", v1alpha1.NSXOperatorConfigName)
+		r.updateStatus(ctx, obj, err)
+		return ResultNormal, nil
+	}
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.restoreBaseline()
+	}
+
+	// The forbidden rules are read by the SecurityPolicy webhook from the CR, they are only validated here.
+	err := securitypolicy.ValidateForbiddenRules(obj.Spec.ForbiddenRules)
+	if err == nil {
+		err = r.NSXConfig.ApplyRuntimeConfig(r.runtimeConfig(&obj.Spec))
+	}
+	if err != nil {
+		log.Error(err, "failed to apply NSXOperatorConfig", "nsxoperatorconfig", req.Name)
+	}
+	r.updateStatus(ctx, obj, err)
+	// the rejected config is not retried until the CR is changed
+	return ResultNormal, nil
+}
+
+// runtimeConfig overlays the fields set in the spec on the baseline.
+func (r *NSXOperatorConfigReconciler) runtimeConfig(spec *v1alpha1.NSXOperatorConfigSpec) config.RuntimeConfig {
+	rc := r.baseline
+	if spec.ReconcileStallTimeout != 0 {
+		rc.ReconcileStallTimeout = spec.ReconcileStallTimeout
+	}
+	if spec.LicenseValidationInterval != 0 {
+		rc.LicenseValidationInterval = spec.LicenseValidationInterval
+	}
+	return rc
+}
+
+func (r *NSXOperatorConfigReconciler) restoreBaseline() (ctrl.Result, error) {
+	log.Info("NSXOperatorConfig CR is deleted, restoring config from file", "config", r.baseline)
+	if err := r.NSXConfig.ApplyRuntimeConfig(r.baseline); err != nil {
+		log.Error(err, "failed to restore config from file")
+		return ResultRequeue, err
+	}
+	return ResultNormal, nil
+}
+
+func (r *NSXOperatorConfigReconciler) updateStatus(ctx context.Context, obj *v1alpha1.NSXOperatorConfig, applyErr error) {
+	condition := v1alpha1.Condition{
+		Type:    v1alpha1.Ready,
+		Status:  v1.ConditionTrue,
+		Reason:  ReasonApplied,
+		Message: "NSXOperatorConfig has been successfully applied",
+	}
+	eventType := v1.EventTypeNormal
+	if applyErr != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonRejected
+		condition.Message = applyErr.Error()
+		eventType = v1.EventTypeWarning
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, eventType, condition.Reason, condition.Message)
+	}
+
+	conditions := make([]v1alpha1.Condition, 0, len(obj.Status.Conditions)+1)
+	found := false
+	for _, existing := range obj.Status.Conditions {
+		if existing.Type == condition.Type {
+			found = true
+			condition.LastTransitionTime = existing.LastTransitionTime
+			if existing.Status != condition.Status {
+				condition.LastTransitionTime = metav1.Now()
+			}
+			existing = condition
+		}
+		conditions = append(conditions, existing)
+	}
+	if !found {
+		condition.LastTransitionTime = metav1.Now()
+		conditions = append(conditions, condition)
+	}
+	obj.Status.Conditions = conditions
+	obj.Status.ObservedGeneration = obj.Generation
+	if err := r.Client.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "failed to update NSXOperatorConfig status", "nsxoperatorconfig", obj.Name)
+	}
+}
+
+func (r *NSXOperatorConfigReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.NSXOperatorConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// Start captures the config from file as baseline and setups the controller.
+func (r *NSXOperatorConfigReconciler) Start(mgr ctrl.Manager) error {
+	r.baseline = r.NSXConfig.GetRuntimeConfig()
+	return r.setupWithManager(mgr)
+}
+
+func StartNSXOperatorConfigController(mgr ctrl.Manager, cf *config.NSXOperatorConfig) {
+	reconciler := &NSXOperatorConfigReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		NSXConfig: cf,
+		Recorder:  mgr.GetEventRecorderFor("nsxoperatorconfig-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "NSXOperatorConfig")
+		os.Exit(1)
+	}
+}
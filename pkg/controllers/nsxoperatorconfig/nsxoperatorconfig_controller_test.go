@@ -0,0 +1,111 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsxoperatorconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func newFakeReconciler(objs ...*v1alpha1.NSXOperatorConfig) *NSXOperatorConfigReconciler {
+	scheme := clientgoscheme.Scheme
+	v1alpha1.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.NSXOperatorConfig{})
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	cf := &config.NSXOperatorConfig{
+		NsxConfig: &config.NsxConfig{},
+		K8sConfig: &config.K8sConfig{ReconcileStallTimeout: 300},
+	}
+	r := &NSXOperatorConfigReconciler{
+		Client:    builder.Build(),
+		Scheme:    scheme,
+		NSXConfig: cf,
+	}
+	r.baseline = cf.GetRuntimeConfig()
+	return r
+}
+
+func reconcile(t *testing.T, r *NSXOperatorConfigReconciler, name string) *v1alpha1.NSXOperatorConfig {
+	ctx := context.TODO()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	assert.Nil(t, err)
+	obj := &v1alpha1.NSXOperatorConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+func TestNSXOperatorConfigReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.NSXOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.NSXOperatorConfigName, Generation: 2},
+		Spec:       v1alpha1.NSXOperatorConfigSpec{LicenseValidationInterval: 120},
+	}
+	other := &v1alpha1.NSXOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       v1alpha1.NSXOperatorConfigSpec{LicenseValidationInterval: 600},
+	}
+	r := newFakeReconciler(obj, other)
+
+	// applied, unset field falls back to the config file
+	result := reconcile(t, r, v1alpha1.NSXOperatorConfigName)
+	assert.Equal(t, config.RuntimeConfig{ReconcileStallTimeout: 300, LicenseValidationInterval: 120}, r.NSXConfig.GetRuntimeConfig())
+	assert.Equal(t, v1.ConditionTrue, result.Status.Conditions[0].Status)
+	assert.Equal(t, ReasonApplied, result.Status.Conditions[0].Reason)
+	assert.Equal(t, int64(2), result.Status.ObservedGeneration)
+
+	// rejected by validation
+	result.Spec.ReconcileStallTimeout = 10
+	assert.Nil(t, r.Client.Update(context.TODO(), result))
+	result = reconcile(t, r, v1alpha1.NSXOperatorConfigName)
+	assert.Equal(t, 300, r.NSXConfig.ReconcileStallTimeout)
+	assert.Equal(t, 1, len(result.Status.Conditions))
+	assert.Equal(t, v1.ConditionFalse, result.Status.Conditions[0].Status)
+	assert.Equal(t, ReasonRejected, result.Status.Conditions[0].Reason)
+
+	// CR with other name is rejected
+	result = reconcile(t, r, "other")
+	assert.Equal(t, 120, r.NSXConfig.LicenseValidationInterval)
+	assert.Equal(t, ReasonRejected, result.Status.Conditions[0].Reason)
+
+	// deleted, restore config from file
+	assert.Nil(t, r.Client.Delete(context.TODO(), obj))
+	assert.Nil(t, reconcile(t, r, v1alpha1.NSXOperatorConfigName))
+	assert.Equal(t, r.baseline, r.NSXConfig.GetRuntimeConfig())
+}
+
+func TestNSXOperatorConfigReconciler_ForbiddenRules(t *testing.T) {
+	obj := &v1alpha1.NSXOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.NSXOperatorConfigName},
+		Spec: v1alpha1.NSXOperatorConfigSpec{
+			LicenseValidationInterval: 120,
+			ForbiddenRules:            []v1alpha1.ForbiddenRule{{Name: "ssh", CIDR: "0.0.0.0/0", Port: 22}},
+		},
+	}
+	r := newFakeReconciler(obj)
+	result := reconcile(t, r, v1alpha1.NSXOperatorConfigName)
+	assert.Equal(t, ReasonApplied, result.Status.Conditions[0].Reason)
+
+	// the invalid forbidden rule rejects the whole config
+	result.Spec.ForbiddenRules[0].CIDR = "0.0.0.0"
+	result.Spec.LicenseValidationInterval = 600
+	assert.Nil(t, r.Client.Update(context.TODO(), result))
+	result = reconcile(t, r, v1alpha1.NSXOperatorConfigName)
+	assert.Equal(t, ReasonRejected, result.Status.Conditions[0].Reason)
+	assert.Contains(t, result.Status.Conditions[0].Message, "forbidden rule ssh")
+	assert.Equal(t, 120, r.NSXConfig.LicenseValidationInterval)
+}
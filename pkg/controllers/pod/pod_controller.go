@@ -46,6 +46,8 @@ type PodReconciler struct {
 	VPCService        servicecommon.VPCServiceProvider
 	NodeServiceReader servicecommon.NodeServiceReader
 	Recorder          record.EventRecorder
+	// Tiers defers the reconciles in the new namespaces until the resources of the lower tiers are realized.
+	Tiers *common.TierGate
 }
 
 func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -68,6 +70,10 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	if !podIsDeleted(pod) {
+		if wait := r.Tiers.Defer(ctx, req.Namespace); wait > 0 {
+			log.V(1).Info("waiting for the lower tiers of the new namespace", "pod", req.NamespacedName, "after", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		metrics.CounterInc(r.SubnetPortService.NSXConfig, metrics.ControllerUpdateTotal, MetricResTypePod)
 		if !controllerutil.ContainsFinalizer(pod, servicecommon.PodFinalizerName) {
 			controllerutil.AddFinalizer(pod, servicecommon.PodFinalizerName)
@@ -187,6 +193,7 @@ func (r *PodReconciler) Start(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	r.Tiers = common.NewTierGate(common.TierGroups, MetricResTypePod, mgr.GetClient(), r.SubnetPortService.NSXConfig)
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
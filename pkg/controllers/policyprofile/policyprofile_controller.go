@@ -0,0 +1,295 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package policyprofile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	ReasonMaterialized        = "Materialized"
+	ReasonMaterializeFailed   = "MaterializeFailed"
+	ReasonInvalidPolicyBundle = "InvalidPolicyBundle"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+)
+
+// PolicyProfileReconciler materializes the SecurityPolicies of the PolicyProfile in each Namespace labeled with
+// nsx.vmware.com/policy-profile set to the name of the profile, they are realized on NSX by the SecurityPolicy
+// controller. The SecurityPolicies are owned by the PolicyProfile, so they are garbage collected with it, and
+// the changes made on them are reverted.
+type PolicyProfileReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Recorder record.EventRecorder
+}
+
+func (r *PolicyProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	profile := &v1alpha1.PolicyProfile{}
+	log.Info("reconciling PolicyProfile", "policyprofile", req.NamespacedName)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, profile); err != nil {
+		// the materialized SecurityPolicies are garbage collected by the owner reference
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+	if !profile.DeletionTimestamp.IsZero() {
+		return ResultNormal, nil
+	}
+
+	if err := validatePolicyBundle(profile); err != nil {
+		log.Error(err, "invalid policy bundle of PolicyProfile", "policyprofile", req.NamespacedName)
+		r.Recorder.Event(profile, v1.EventTypeWarning, ReasonInvalidPolicyBundle, err.Error())
+		r.updateStatus(ctx, profile, nil, err)
+		// the invalid bundle is not retried until the profile is changed
+		return ResultNormal, nil
+	}
+
+	nsList := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList, client.MatchingLabels{servicecommon.LabelPolicyProfile: profile.Name}); err != nil {
+		return ResultRequeue, err
+	}
+	namespaces := make([]string, 0, len(nsList.Items))
+	var desired []*v1alpha1.SecurityPolicy
+	for _, ns := range nsList.Items {
+		if !ns.DeletionTimestamp.IsZero() {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+		for i := range profile.Spec.SecurityPolicies {
+			desired = append(desired, buildSecurityPolicy(profile, ns.Name, &profile.Spec.SecurityPolicies[i]))
+		}
+	}
+	sort.Strings(namespaces)
+
+	if err := r.apply(ctx, profile, desired); err != nil {
+		log.Error(err, "failed to apply SecurityPolicies of PolicyProfile", "policyprofile", req.NamespacedName)
+		r.Recorder.Event(profile, v1.EventTypeWarning, ReasonMaterializeFailed, err.Error())
+		r.updateStatus(ctx, profile, namespaces, err)
+		return ResultRequeue, err
+	}
+	r.updateStatus(ctx, profile, namespaces, nil)
+	r.Recorder.Event(profile, v1.EventTypeNormal, ReasonMaterialized,
+		fmt.Sprintf("materialized %d SecurityPolicies in %d Namespaces", len(desired), len(namespaces)))
+	return ResultNormal, nil
+}
+
+// validatePolicyBundle makes sure the names of the SecurityPolicies of the bundle are unique, otherwise they
+// would overwrite each other in the Namespaces.
+func validatePolicyBundle(profile *v1alpha1.PolicyProfile) error {
+	names := make(map[string]bool, len(profile.Spec.SecurityPolicies))
+	for _, sp := range profile.Spec.SecurityPolicies {
+		if sp.Name == "" {
+			return fmt.Errorf("name of SecurityPolicy is empty")
+		}
+		if names[sp.Name] {
+			return fmt.Errorf("duplicated SecurityPolicy name %s", sp.Name)
+		}
+		names[sp.Name] = true
+	}
+	return nil
+}
+
+func buildSecurityPolicy(profile *v1alpha1.PolicyProfile, namespace string, template *v1alpha1.PolicyProfileSecurityPolicy) *v1alpha1.SecurityPolicy {
+	return &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      profile.Name + "-" + template.Name,
+			Labels:    map[string]string{servicecommon.LabelPolicyProfileUID: string(profile.UID)},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+}
+
+// apply creates or updates the desired SecurityPolicies and deletes the ones materialized before but no more
+// desired, e.g. the Namespace is not labeled with the profile anymore, or the SecurityPolicy is removed from
+// the bundle.
+func (r *PolicyProfileReconciler) apply(ctx context.Context, profile *v1alpha1.PolicyProfile, desired []*v1alpha1.SecurityPolicy) error {
+	existingList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, existingList, client.MatchingLabels{servicecommon.LabelPolicyProfileUID: string(profile.UID)}); err != nil {
+		return err
+	}
+	existing := make(map[types.NamespacedName]*v1alpha1.SecurityPolicy, len(existingList.Items))
+	for i := range existingList.Items {
+		sp := &existingList.Items[i]
+		existing[types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}] = sp
+	}
+
+	var conflicts []string
+	for _, sp := range desired {
+		key := types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}
+		current, found := existing[key]
+		delete(existing, key)
+		if !found {
+			if err := controllerutil.SetControllerReference(profile, sp, r.Scheme); err != nil {
+				return err
+			}
+			if err := r.Client.Create(ctx, sp); err != nil {
+				// the SecurityPolicy created by the Namespace owner is not overwritten
+				if apierrors.IsAlreadyExists(err) {
+					conflicts = append(conflicts, key.String())
+					continue
+				}
+				return err
+			}
+			log.Info("created SecurityPolicy of PolicyProfile", "policyprofile", profile.Name, "securitypolicy", key)
+			continue
+		}
+		if reflect.DeepEqual(current.Spec, sp.Spec) {
+			continue
+		}
+		current.Spec = sp.Spec
+		if err := r.Client.Update(ctx, current); err != nil {
+			return err
+		}
+		log.Info("updated SecurityPolicy of PolicyProfile", "policyprofile", profile.Name, "securitypolicy", key)
+	}
+
+	for key, stale := range existing {
+		if err := r.Client.Delete(ctx, stale); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("deleted stale SecurityPolicy of PolicyProfile", "policyprofile", profile.Name, "securitypolicy", key)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("SecurityPolicies not created by the PolicyProfile already exist: %s", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+func (r *PolicyProfileReconciler) updateStatus(ctx context.Context, profile *v1alpha1.PolicyProfile, namespaces []string, err error) {
+	condition := v1alpha1.Condition{
+		Type:    v1alpha1.Ready,
+		Status:  v1.ConditionTrue,
+		Message: "SecurityPolicies of the PolicyProfile have been materialized in the labeled Namespaces",
+		Reason:  ReasonMaterialized,
+	}
+	if err != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Message = fmt.Sprintf("error occurred while materializing the PolicyProfile. Error: %v", err)
+		condition.Reason = ReasonMaterializeFailed
+	}
+	// the transition time is kept unless the condition is changed
+	condition.LastTransitionTime = metav1.Now()
+	if existing := getExistingConditionOfType(v1alpha1.Ready, profile.Status.Conditions); existing != nil &&
+		existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	conditions := []v1alpha1.Condition{condition}
+	if reflect.DeepEqual(profile.Status.Namespaces, namespaces) && reflect.DeepEqual(profile.Status.Conditions, conditions) {
+		return
+	}
+	profile.Status.Namespaces = namespaces
+	profile.Status.Conditions = conditions
+	if err := r.Client.Status().Update(ctx, profile); err != nil {
+		log.Error(err, "failed to update PolicyProfile status", "policyprofile", profile.Name)
+		return
+	}
+	log.V(1).Info("updated PolicyProfile", "Name", profile.Name, "Namespaces", namespaces, "New Conditions", conditions)
+}
+
+func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingConditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range existingConditions {
+		if existingConditions[i].Type == conditionType {
+			return &existingConditions[i]
+		}
+	}
+	return nil
+}
+
+// namespaceMapFunc requeues the PolicyProfiles the Namespace is labeled with before and after the change, all the
+// PolicyProfiles are requeued since the label before the change is unknown to the handler.
+func (r *PolicyProfileReconciler) namespaceMapFunc(ctx context.Context, _ client.Object) []reconcile.Request {
+	profileList := &v1alpha1.PolicyProfileList{}
+	if err := r.Client.List(ctx, profileList); err != nil {
+		log.Error(err, "failed to list PolicyProfiles in Namespace handler")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(profileList.Items))
+	for _, profile := range profileList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: profile.Name}})
+	}
+	return requests
+}
+
+var PredicateFuncsNs = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		_, ok := e.Object.GetLabels()[servicecommon.LabelPolicyProfile]
+		return ok
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetLabels()[servicecommon.LabelPolicyProfile] != e.ObjectNew.GetLabels()[servicecommon.LabelPolicyProfile]
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		// the materialized SecurityPolicies are deleted with the Namespace, the status is refreshed to drop it
+		_, ok := e.Object.GetLabels()[servicecommon.LabelPolicyProfile]
+		return ok
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *PolicyProfileReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.PolicyProfile{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// revert the changes on the materialized SecurityPolicies
+		Owns(&v1alpha1.SecurityPolicy{}).
+		Watches(&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceMapFunc),
+			builder.WithPredicates(PredicateFuncsNs)).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager
+func (r *PolicyProfileReconciler) Start(mgr ctrl.Manager) error {
+	return r.setupWithManager(mgr)
+}
+
+// StartPolicyProfileController materializes the SecurityPolicies of the PolicyProfiles in the Namespaces which
+// opt into them by the nsx.vmware.com/policy-profile label.
+func StartPolicyProfileController(mgr ctrl.Manager) {
+	reconciler := &PolicyProfileReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("policyprofile-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "PolicyProfile")
+		os.Exit(1)
+	}
+}
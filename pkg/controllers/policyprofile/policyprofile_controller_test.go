@@ -0,0 +1,106 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package policyprofile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func TestPolicyProfileReconciler(t *testing.T) {
+	scheme := newScheme()
+	action, direction := v1alpha1.RuleActionDrop, v1alpha1.RuleDirectionIn
+	profile := &v1alpha1.PolicyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", UID: "restricted-uid"},
+		Spec: v1alpha1.PolicyProfileSpec{SecurityPolicies: []v1alpha1.PolicyProfileSecurityPolicy{
+			{Name: "deny-ingress", Spec: v1alpha1.SecurityPolicySpec{
+				Priority: 10,
+				Rules:    []v1alpha1.SecurityPolicyRule{{Action: &action, Direction: &direction}},
+			}},
+		}},
+	}
+	nsProd := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{servicecommon.LabelPolicyProfile: "restricted"}}}
+	nsDev := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile, nsProd, nsDev).
+		WithStatusSubresource(&v1alpha1.PolicyProfile{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &PolicyProfileReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "restricted"}}
+
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	sp := &v1alpha1.SecurityPolicy{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "prod", Name: "restricted-deny-ingress"}, sp))
+	assert.Equal(t, 10, sp.Spec.Priority)
+	assert.Equal(t, "restricted-uid", sp.Labels[servicecommon.LabelPolicyProfileUID])
+	assert.Equal(t, "restricted", sp.OwnerReferences[0].Name)
+	assert.Contains(t, <-recorder.Events, ReasonMaterialized)
+	assert.NoError(t, k8sClient.Get(ctx, req.NamespacedName, profile))
+	assert.Equal(t, []string{"prod"}, profile.Status.Namespaces)
+	assert.Equal(t, v1.ConditionTrue, profile.Status.Conditions[0].Status)
+
+	// the change on the materialized SecurityPolicy is reverted
+	sp.Spec.Priority = 1
+	assert.NoError(t, k8sClient.Update(ctx, sp))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "prod", Name: "restricted-deny-ingress"}, sp))
+	assert.Equal(t, 10, sp.Spec.Priority)
+
+	// the Namespaces are relabeled, the SecurityPolicy in the Namespace opted out is deleted
+	assert.Len(t, r.namespaceMapFunc(ctx, nsDev), 1)
+	delete(nsProd.Labels, servicecommon.LabelPolicyProfile)
+	nsDev.Labels = map[string]string{servicecommon.LabelPolicyProfile: "restricted"}
+	assert.NoError(t, k8sClient.Update(ctx, nsProd))
+	assert.NoError(t, k8sClient.Update(ctx, nsDev))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	spList := &v1alpha1.SecurityPolicyList{}
+	assert.NoError(t, k8sClient.List(ctx, spList, client.MatchingLabels{servicecommon.LabelPolicyProfileUID: "restricted-uid"}))
+	assert.Len(t, spList.Items, 1)
+	assert.Equal(t, "dev", spList.Items[0].Namespace)
+
+	// the SecurityPolicy created by the Namespace owner is not overwritten
+	nsProd.Labels = map[string]string{servicecommon.LabelPolicyProfile: "restricted"}
+	assert.NoError(t, k8sClient.Update(ctx, nsProd))
+	assert.NoError(t, k8sClient.Create(ctx, &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "restricted-deny-ingress"}}))
+	_, err = r.Reconcile(ctx, req)
+	assert.ErrorContains(t, err, "prod/restricted-deny-ingress")
+	assert.NoError(t, k8sClient.Get(ctx, req.NamespacedName, profile))
+	assert.Equal(t, []string{"dev", "prod"}, profile.Status.Namespaces)
+	assert.Equal(t, v1.ConditionFalse, profile.Status.Conditions[0].Status)
+}
+
+func TestValidatePolicyBundle(t *testing.T) {
+	profile := &v1alpha1.PolicyProfile{Spec: v1alpha1.PolicyProfileSpec{SecurityPolicies: []v1alpha1.PolicyProfileSecurityPolicy{
+		{Name: "a"}, {Name: "b"},
+	}}}
+	assert.NoError(t, validatePolicyBundle(profile))
+	profile.Spec.SecurityPolicies = append(profile.Spec.SecurityPolicies, v1alpha1.PolicyProfileSecurityPolicy{Name: "a"})
+	assert.EqualError(t, validatePolicyBundle(profile), "duplicated SecurityPolicy name a")
+	profile.Spec.SecurityPolicies = []v1alpha1.PolicyProfileSecurityPolicy{{}}
+	assert.EqualError(t, validatePolicyBundle(profile), "name of SecurityPolicy is empty")
+}
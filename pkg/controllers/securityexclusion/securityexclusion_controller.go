@@ -0,0 +1,307 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securityexclusion"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeSecurityExclusion
+)
+
+// SecurityExclusionReconciler reconciles a SecurityExclusion object
+type SecurityExclusionReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *securityexclusion.SecurityExclusionService
+	Recorder record.EventRecorder
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *common.WarmupGate
+	// DeletionGuard pauses the garbage collection deleting too many SecurityExclusions until it's confirmed.
+	DeletionGuard *common.DeletionGuard
+}
+
+func deleteFail(r *SecurityExclusionReconciler, c *context.Context, o *v1alpha1.SecurityExclusion, e *error) {
+	r.setSecurityExclusionReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateFail(r *SecurityExclusionReconciler, c *context.Context, o *v1alpha1.SecurityExclusion, e *error) {
+	r.setSecurityExclusionReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func updateSuccess(r *SecurityExclusionReconciler, c *context.Context, o *v1alpha1.SecurityExclusion) {
+	r.setSecurityExclusionReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "SecurityExclusion CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *SecurityExclusionReconciler, _ *context.Context, o *v1alpha1.SecurityExclusion) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "SecurityExclusion CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *SecurityExclusionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", "securityexclusion", req.Name)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "securityexclusion", req.Name)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	obj := &v1alpha1.SecurityExclusion{}
+	log.Info("reconciling securityexclusion CR", "securityexclusion", req.Name)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch securityexclusion CR", "req", req.Name)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, commonservice.SecurityExclusionFinalizerName) {
+			controllerutil.AddFinalizer(obj, commonservice.SecurityExclusionFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "securityexclusion", req.Name)
+				updateFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on securityexclusion CR", "securityexclusion", req.Name)
+		}
+
+		if err := r.Service.CreateOrUpdateSecurityExclusion(obj); err != nil {
+			updateFail(r, &ctx, obj, &err)
+			// the selectors NSX can't express are not retried until the SecurityExclusion is changed
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				return ResultNormal, nil
+			}
+			return ResultRequeue, err
+		}
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, commonservice.SecurityExclusionFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteSecurityExclusion(obj.UID); err != nil {
+				log.Error(err, "delete failed, would retry exponentially", "securityexclusion", req.Name)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, commonservice.SecurityExclusionFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "securityexclusion", req.Name)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "securityexclusion", req.Name)
+		}
+	}
+
+	return ResultNormal, nil
+}
+
+func (r *SecurityExclusionReconciler) setSecurityExclusionReadyStatusTrue(ctx *context.Context, securityExclusion *v1alpha1.SecurityExclusion, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX group of the Pods has been successfully added to the DFW exclusion list",
+			Reason:             "NSX API returned 200 response code for PUT",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateSecurityExclusionStatusConditions(ctx, securityExclusion, newConditions)
+}
+
+func (r *SecurityExclusionReconciler) setSecurityExclusionReadyStatusFalse(ctx *context.Context, securityExclusion *v1alpha1.SecurityExclusion, transitionTime metav1.Time, err *error) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX group of the Pods could not be added to/removed from the DFW exclusion list",
+			Reason:             fmt.Sprintf("error occurred while processing the SecurityExclusion CR. Error: %v", *err),
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateSecurityExclusionStatusConditions(ctx, securityExclusion, newConditions)
+}
+
+func (r *SecurityExclusionReconciler) updateSecurityExclusionStatusConditions(ctx *context.Context, securityExclusion *v1alpha1.SecurityExclusion, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if r.mergeSecurityExclusionStatusCondition(securityExclusion, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		r.Client.Status().Update(*ctx, securityExclusion)
+		log.V(1).Info("updated SecurityExclusion", "Name", securityExclusion.Name, "New Conditions", newConditions)
+	}
+}
+
+func (r *SecurityExclusionReconciler) mergeSecurityExclusionStatusCondition(securityExclusion *v1alpha1.SecurityExclusion, newCondition *v1alpha1.Condition) bool {
+	matchedCondition := getExistingConditionOfType(newCondition.Type, securityExclusion.Status.Conditions)
+
+	if reflect.DeepEqual(matchedCondition, newCondition) {
+		log.V(2).Info("conditions already match", "New Condition", newCondition, "Existing Condition", matchedCondition)
+		return false
+	}
+
+	if matchedCondition != nil {
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+	} else {
+		securityExclusion.Status.Conditions = append(securityExclusion.Status.Conditions, *newCondition)
+	}
+	return true
+}
+
+func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingConditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range existingConditions {
+		if existingConditions[i].Type == conditionType {
+			return &existingConditions[i]
+		}
+	}
+	return nil
+}
+
+func (r *SecurityExclusionReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SecurityExclusion{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *SecurityExclusionReconciler) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	r.Warmup = common.NewWarmupGate(MetricResType, r.Service.NSXConfig, mgr.GetCache(), &v1alpha1.SecurityExclusion{})
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), commonservice.GCInterval)
+	return nil
+}
+
+// GarbageCollector collect the NSX resources of the SecurityExclusions which have been removed from crd.
+// cancel is used to break the loop during UT
+func (r *SecurityExclusionReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started")
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		if err := r.collectGarbage(ctx); err != nil {
+			log.Error(err, "failed to collect garbage of SecurityExclusion")
+		}
+	}
+}
+
+// collectGarbage deletes the NSX resources of the SecurityExclusions whose CR has been removed. The deletion is
+// paused by the DeletionGuard if too many SecurityExclusions are collected.
+func (r *SecurityExclusionReconciler) collectGarbage(ctx context.Context) error {
+	nsxSecurityExclusionSet := r.Service.ListSecurityExclusionID()
+	if len(nsxSecurityExclusionSet) == 0 {
+		return nil
+	}
+
+	crdSecurityExclusionList := &v1alpha1.SecurityExclusionList{}
+	if err := r.Client.List(ctx, crdSecurityExclusionList); err != nil {
+		return err
+	}
+
+	crdSecurityExclusionSet := sets.New[string]()
+	for _, securityExclusion := range crdSecurityExclusionList.Items {
+		crdSecurityExclusionSet.Insert(string(securityExclusion.UID))
+	}
+
+	stale := nsxSecurityExclusionSet.Difference(crdSecurityExclusionSet)
+	if err := r.DeletionGuard.Allow(ctx, len(stale), len(nsxSecurityExclusionSet)); err != nil {
+		return err
+	}
+	for uid := range stale {
+		log.V(1).Info("GC collected SecurityExclusion CR", "UID", uid)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteSecurityExclusion(types.UID(uid)); err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+		} else {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		}
+	}
+	return nil
+}
+
+func StartSecurityExclusionController(mgr ctrl.Manager, securityExclusionService *securityexclusion.SecurityExclusionService, deletionGuard *common.DeletionGuard) {
+	securityExclusionReconcile := SecurityExclusionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("securityexclusion-controller"),
+	}
+	securityExclusionReconcile.Service = securityExclusionService
+	securityExclusionReconcile.DeletionGuard = deletionGuard
+	if err := securityExclusionReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "SecurityExclusion")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,313 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The paths of the admin API, every request is authorized by RBAC on the non-resource URL of its path.
+const (
+	AdminPathRealized = "/admin/v1/securitypolicies/realized"
+	AdminPathResync   = "/admin/v1/securitypolicies/resync"
+	AdminPathGC       = "/admin/v1/securitypolicies/gc"
+	AdminPathPlan     = "/admin/v1/securitypolicies/plan"
+	AdminPathHistory  = "/admin/v1/securitypolicies/history"
+	// AdminPathOrdering returns the NSX rules applying to a namespace in the order the DFW evaluates them.
+	AdminPathOrdering = "/admin/v1/securitypolicies/ordering"
+	// AdminPathLogLabels maps the log label of a DFW syslog event to the CR rule it's correlated with.
+	AdminPathLogLabels = "/admin/v1/securitypolicies/loglabels"
+	// AdminPathDiagnostics isn't scoped to the SecurityPolicies, the diagnostics cover all the controllers.
+	AdminPathDiagnostics = "/admin/v1/diagnostics"
+	// AdminPathOnboard provisions the network plumbing of a batch of namespaces, with VPC only.
+	AdminPathOnboard = "/admin/v1/namespaces/onboard"
+)
+
+// maxOnboardNamespaces limits the namespaces onboarded by one request.
+const maxOnboardNamespaces = 200
+
+// OnboardRequest is the body of the namespace onboarding request.
+type OnboardRequest struct {
+	Namespaces []string `json:"namespaces"`
+}
+
+// OnboardSummary is the last line of the namespace onboarding response.
+type OnboardSummary struct {
+	Onboarded int `json:"onboarded"`
+	Failed    int `json:"failed"`
+}
+
+// AdminServer serves the admin API of the SecurityPolicy controller over HTTPS, which is consumed by the
+// CLI and the support tooling to query the stores, resync the SecurityPolicies, trigger the garbage
+// collection, plan the realization of a SecurityPolicy, list its last sync attempts, preview the ordering of the
+// rules of a namespace, collect the diagnostics bundle and onboard a batch of namespaces. It only runs on the leader.
+type AdminServer struct {
+	Addr       string
+	CertDir    string
+	Client     client.Client
+	Reconciler *SecurityPolicyReconciler
+	// Counters count the NSX objects in the stores for the diagnostics.
+	Counters []servicecommon.ObjectCounter
+	// Onboarder onboards the namespaces, it's nil without VPC.
+	Onboarder common.NamespaceOnboarder
+}
+
+func (s *AdminServer) Start(ctx context.Context) error {
+	server := &http.Server{Addr: s.Addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "failed to shut down admin server")
+		}
+	}()
+	log.Info("admin server started", "address", s.Addr)
+	err := server.ListenAndServeTLS(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Handler returns the handler of the admin API.
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(AdminPathRealized, s.authorized(http.MethodGet, s.handleRealized))
+	mux.HandleFunc(AdminPathResync, s.authorized(http.MethodPost, s.handleResync))
+	mux.HandleFunc(AdminPathGC, s.authorized(http.MethodPost, s.handleGC))
+	mux.HandleFunc(AdminPathPlan, s.authorized(http.MethodGet, s.handlePlan))
+	mux.HandleFunc(AdminPathHistory, s.authorized(http.MethodGet, s.handleHistory))
+	mux.HandleFunc(AdminPathOrdering, s.authorized(http.MethodGet, s.handleOrdering))
+	mux.HandleFunc(AdminPathLogLabels, s.authorized(http.MethodGet, s.handleLogLabels))
+	mux.HandleFunc(AdminPathDiagnostics, s.authorized(http.MethodGet, s.handleDiagnostics))
+	mux.HandleFunc(AdminPathOnboard, s.authorized(http.MethodPost, s.handleOnboard))
+	return mux
+}
+
+func (s *AdminServer) authorized(method string, handle func(w http.ResponseWriter, req *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if code, err := common.AuthorizeAdminRequest(req.Context(), s.Client, req); err != nil {
+			log.Info("admin request rejected", "path", req.URL.Path, "reason", err.Error())
+			http.Error(w, err.Error(), code)
+			return
+		}
+		log.Info("admin request", "method", req.Method, "path", req.URL.Path, "query", req.URL.RawQuery)
+		handle(w, req)
+	}
+}
+
+// handleRealized returns the NSX resources realized for the SecurityPolicy of the uid, or for all the
+// SecurityPolicies if the uid is not set.
+func (s *AdminServer) handleRealized(w http.ResponseWriter, req *http.Request) {
+	realized := s.Reconciler.Service.ListRealizedResources()
+	uid := req.URL.Query().Get("uid")
+	if uid == "" {
+		writeJSON(w, realized)
+		return
+	}
+	resources, ok := realized[uid]
+	if !ok {
+		http.Error(w, "no NSX resources realized for uid "+uid, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, resources)
+}
+
+func (s *AdminServer) handleResync(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	count, err := s.Reconciler.Resync(req.Context(), query.Get("namespace"), query.Get("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]int{"resynced": count})
+}
+
+func (s *AdminServer) handleGC(w http.ResponseWriter, req *http.Request) {
+	if err := s.Reconciler.CollectGarbage(req.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "completed"})
+}
+
+func (s *AdminServer) handlePlan(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	obj := &v1alpha1.SecurityPolicy{}
+	if err := s.Client.Get(req.Context(), types.NamespacedName{Namespace: query.Get("namespace"), Name: query.Get("name")}, obj); err != nil {
+		writeError(w, err)
+		return
+	}
+	service, err := s.Reconciler.serviceFor(obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	plan, err := service.PlanSecurityPolicy(realizedObject(service, obj))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, plan)
+}
+
+// handleHistory returns the last sync attempts of the SecurityPolicy of the namespace and name, or of all the
+// SecurityPolicies if the name is not set.
+func (s *AdminServer) handleHistory(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if query.Get("name") == "" {
+		writeJSON(w, s.Reconciler.History.All())
+		return
+	}
+	key := types.NamespacedName{Namespace: query.Get("namespace"), Name: query.Get("name")}
+	attempts := s.Reconciler.History.Attempts(key)
+	if len(attempts) == 0 {
+		http.Error(w, "no sync attempts of "+key.String(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, attempts)
+}
+
+// handleOrdering returns the NSX rules applying to the namespace in the order the DFW evaluates them. If preview
+// is set, the SecurityPolicy of the name is placed in the ordering as built from its current spec, before it's
+// realized.
+func (s *AdminServer) handleOrdering(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	namespace := query.Get("namespace")
+	if namespace == "" {
+		http.Error(w, "namespace is required", http.StatusBadRequest)
+		return
+	}
+	// the namespace decides the NSX site of the ordering
+	obj := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}}
+	name := query.Get("preview")
+	if name != "" {
+		if err := s.Client.Get(req.Context(), types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	service, err := s.Reconciler.serviceFor(obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var preview *v1alpha1.SecurityPolicy
+	if name != "" {
+		preview = realizedObject(service, obj)
+	}
+	ordering, err := service.BuildRuleOrdering(namespace, preview)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, ordering)
+}
+
+// handleLogLabels returns the CR rules the log label of a DFW syslog event is correlated with.
+func (s *AdminServer) handleLogLabels(w http.ResponseWriter, req *http.Request) {
+	label := req.URL.Query().Get("label")
+	if label == "" {
+		http.Error(w, "label is required", http.StatusBadRequest)
+		return
+	}
+	owners := s.Reconciler.Service.LookupLogLabel(label)
+	if len(owners) == 0 {
+		http.Error(w, "no rules correlated with log label "+label, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, owners)
+}
+
+// handleDiagnostics returns the diagnostics bundle as a gzipped tar archive, so the support cases don't
+// require exec access into the pod.
+func (s *AdminServer) handleDiagnostics(w http.ResponseWriter, _ *http.Request) {
+	buf := &bytes.Buffer{}
+	if err := common.WriteDiagnostics(buf, s.Reconciler.Service.NSXConfig, s.Counters); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=nsx-operator-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z")))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Error(err, "failed to write admin response")
+	}
+}
+
+// handleOnboard onboards the namespaces of the request and streams the progress as JSON lines, one OnboardEvent
+// per line as the namespaces progress, and the OnboardSummary in the end.
+func (s *AdminServer) handleOnboard(w http.ResponseWriter, req *http.Request) {
+	if s.Onboarder == nil {
+		http.Error(w, "namespace onboarding requires VPC", http.StatusNotImplemented)
+		return
+	}
+	onboardReq := &OnboardRequest{}
+	if err := json.NewDecoder(req.Body).Decode(onboardReq); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	namespaces := sets.List(sets.New[string](onboardReq.Namespaces...))
+	if len(namespaces) == 0 || len(namespaces) > maxOnboardNamespaces {
+		http.Error(w, fmt.Sprintf("1 to %d namespaces are required", maxOnboardNamespaces), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	failed := sets.New[string]()
+	writeLine := func(v interface{}) {
+		if err := encoder.Encode(v); err != nil {
+			log.Error(err, "failed to write admin response")
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	s.Onboarder.OnboardNamespaces(req.Context(), namespaces, func(event common.OnboardEvent) {
+		if event.Stage == common.OnboardStageFailed {
+			failed.Insert(event.Namespace)
+		}
+		writeLine(event)
+	})
+	writeLine(OnboardSummary{Onboarded: len(namespaces) - failed.Len(), Failed: failed.Len()})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error(err, "failed to write admin response")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if apierrors.IsNotFound(err) {
+		code = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,119 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+)
+
+func TestAdminServer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1"}},
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp2"}},
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "sp3"}},
+	).Build()
+	r := &SecurityPolicyReconciler{Client: k8sClient, History: common.NewSyncHistory(0), resync: make(chan event.GenericEvent, resyncQueueSize)}
+	server := &AdminServer{Client: k8sClient, Reconciler: r}
+
+	authorized := false
+	patches := gomonkey.ApplyFunc(common.AuthorizeAdminRequest, func(_ context.Context, _ client.Client, _ *http.Request) (int, error) {
+		if !authorized {
+			return http.StatusForbidden, errors.New("forbidden")
+		}
+		return http.StatusOK, nil
+	})
+	defer patches.Reset()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, AdminPathResync).Code)
+	assert.Len(t, r.resync, 0)
+
+	authorized = true
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, AdminPathResync).Code)
+
+	w := serve(http.MethodPost, AdminPathResync+"?namespace=ns1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"resynced":2}`, w.Body.String())
+
+	w = serve(http.MethodPost, AdminPathResync+"?namespace=ns2&name=sp3")
+	assert.JSONEq(t, `{"resynced":1}`, w.Body.String())
+	assert.Len(t, r.resync, 3)
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, AdminPathResync+"?namespace=ns2&name=sp4").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, AdminPathPlan+"?namespace=ns2&name=sp4").Code)
+
+	r.History.Started(types.NamespacedName{Namespace: "ns1", Name: "sp1"})
+	r.History.Failed(types.NamespacedName{Namespace: "ns1", Name: "sp1"}, errors.New("failed"))
+	w = serve(http.MethodGet, AdminPathHistory+"?namespace=ns1&name=sp1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"outcome":"failure","error":"failed"`)
+	w = serve(http.MethodGet, AdminPathHistory)
+	assert.Contains(t, w.Body.String(), `"ns1/sp1":[`)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, AdminPathHistory+"?namespace=ns1&name=sp2").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, AdminPathLogLabels).Code)
+}
+
+type fakeOnboarder struct{}
+
+func (fakeOnboarder) OnboardNamespaces(_ context.Context, namespaces []string, report func(common.OnboardEvent)) {
+	for _, ns := range namespaces {
+		if ns == "ns2" {
+			report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageFailed, Error: "failed"})
+			continue
+		}
+		report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageProvisioned})
+		report(common.OnboardEvent{Namespace: ns, Stage: common.OnboardStageReady})
+	}
+}
+
+func TestAdminServer_Onboard(t *testing.T) {
+	patches := gomonkey.ApplyFunc(common.AuthorizeAdminRequest, func(_ context.Context, _ client.Client, _ *http.Request) (int, error) {
+		return http.StatusOK, nil
+	})
+	defer patches.Reset()
+	server := &AdminServer{}
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, AdminPathOnboard, strings.NewReader(body)))
+		return w
+	}
+	assert.Equal(t, http.StatusNotImplemented, serve(`{"namespaces":["ns1"]}`).Code)
+
+	server.Onboarder = fakeOnboarder{}
+	assert.Equal(t, http.StatusBadRequest, serve(`{"namespaces":[]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(`namespaces`).Code)
+
+	// the duplicated namespaces are onboarded once
+	w := serve(`{"namespaces":["ns1","ns2","ns1"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"namespace":"ns1","stage":"Provisioned"}
+{"namespace":"ns1","stage":"Ready"}
+{"namespace":"ns2","stage":"Failed","error":"failed"}
+{"onboarded":1,"failed":1}
+`, w.Body.String())
+}
@@ -0,0 +1,219 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+const (
+	// BackupReasonNamespaceDeletion is the backup of the SecurityPolicies in a terminating namespace,
+	// which are deleted in cascade.
+	BackupReasonNamespaceDeletion = "namespace-deletion"
+	// BackupReasonGC is the backup of the NSX SecurityPolicies collected by the garbage collection.
+	BackupReasonGC = "gc"
+	// BackupReasonSuperseded is the backup of the NSX SecurityPolicies realized for the previous CRs of the
+	// same namespace and name, which are deleted once the new CR is realized.
+	BackupReasonSuperseded = "superseded"
+	// maxBackupArchives is the count of the latest archives kept in the backup Secret.
+	maxBackupArchives = 10
+)
+
+// BackupEntry is a SecurityPolicy CR with the NSX resources realized for it, the CR is not set for the
+// NSX resources whose CR has been removed.
+type BackupEntry struct {
+	Namespace string                            `json:"namespace,omitempty"`
+	Name      string                            `json:"name,omitempty"`
+	UID       string                            `json:"uid"`
+	Labels    map[string]string                 `json:"labels,omitempty"`
+	Spec      *v1alpha1.SecurityPolicySpec      `json:"spec,omitempty"`
+	Resources *securitypolicy.RealizedResources `json:"resources,omitempty"`
+}
+
+// BackupArchive is the content of a gzipped backup archive.
+type BackupArchive struct {
+	Reason  string        `json:"reason"`
+	Time    metav1.Time   `json:"time"`
+	Entries []BackupEntry `json:"entries"`
+}
+
+// Backuper writes a compressed archive of the SecurityPolicy CRs and the NSX resources realized for them
+// before they are deleted in bulk, so an accidental deletion can be replayed by recreating the CRs. The
+// archives are kept in the Secret, and written to the directory, e.g. a mounted PVC, if they are set.
+// Backup is no-op on a nil Backuper.
+type Backuper struct {
+	Client client.Client
+	// Reader reads the backup Secret from the apiserver, the Secrets are not cached.
+	Reader client.Reader
+	// Namespace is the namespace of the backup Secret.
+	Namespace  string
+	SecretName string
+	Dir        string
+
+	mu sync.Mutex
+	// namespaces are the UIDs of the terminating namespaces backed up.
+	namespaces sets.Set[types.UID]
+	now        func() time.Time
+}
+
+// NewBackuper returns a Backuper, or nil if neither the Secret nor the directory is set.
+func NewBackuper(c client.Client, reader client.Reader, namespace, secretName, dir string) *Backuper {
+	if secretName == "" && dir == "" {
+		return nil
+	}
+	return &Backuper{
+		Client:     c,
+		Reader:     reader,
+		Namespace:  namespace,
+		SecretName: secretName,
+		Dir:        dir,
+		namespaces: sets.New[types.UID](),
+		now:        time.Now,
+	}
+}
+
+// BackupNamespace backs up the SecurityPolicies in the terminating namespace with the NSX resources
+// realized for them by the service once, before they are deleted in cascade.
+func (b *Backuper) BackupNamespace(ctx context.Context, service RealizedResourceLister, namespace string) error {
+	if b == nil {
+		return nil
+	}
+	ns := &v1.Namespace{}
+	if err := b.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ns.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.namespaces.Has(ns.UID) {
+		return nil
+	}
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := b.Client.List(ctx, policyList, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	if err := b.backup(ctx, service, BackupReasonNamespaceDeletion+"-"+namespace, policyList.Items, nil); err != nil {
+		return err
+	}
+	b.namespaces.Insert(ns.UID)
+	return nil
+}
+
+// Backup backs up the SecurityPolicy CRs, and the UIDs without CR, with the NSX resources realized for them
+// by the service. The archive is named with the time and the reason, as "<unix seconds>-<reason>.json.gz".
+func (b *Backuper) Backup(ctx context.Context, service RealizedResourceLister, reason string, policies []v1alpha1.SecurityPolicy, uids []string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.backup(ctx, service, reason, policies, uids)
+}
+
+func (b *Backuper) backup(ctx context.Context, service RealizedResourceLister, reason string, policies []v1alpha1.SecurityPolicy, uids []string) error {
+	realized := service.ListRealizedResources()
+	archive := BackupArchive{Reason: reason, Time: metav1.NewTime(b.now())}
+	for i := range policies {
+		policy := &policies[i]
+		uid := string(realizedObjectUID(policy, realized))
+		archive.Entries = append(archive.Entries, BackupEntry{
+			Namespace: policy.Namespace,
+			Name:      policy.Name,
+			UID:       uid,
+			Labels:    policy.Labels,
+			Spec:      policy.Spec.DeepCopy(),
+			Resources: realized[uid],
+		})
+	}
+	for _, uid := range uids {
+		archive.Entries = append(archive.Entries, BackupEntry{UID: uid, Resources: realized[uid]})
+	}
+	if len(archive.Entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(archive); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%010d-%s.json.gz", archive.Time.Unix(), reason)
+	if b.Dir != "" {
+		if err := os.WriteFile(filepath.Join(b.Dir, key), buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+	if b.SecretName != "" {
+		if err := b.writeSecret(ctx, key, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	log.Info("backed up SecurityPolicies before deletion", "reason", reason, "entries", len(archive.Entries), "archive", key)
+	return nil
+}
+
+// writeSecret adds the archive to the backup Secret, the oldest archives are removed to keep the latest
+// maxBackupArchives ones.
+func (b *Backuper) writeSecret(ctx context.Context, key string, data []byte) error {
+	secret := &v1.Secret{}
+	secretKey := types.NamespacedName{Namespace: b.Namespace, Name: b.SecretName}
+	if err := b.Reader.Get(ctx, secretKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: b.Namespace, Name: b.SecretName},
+			Data:       map[string][]byte{key: data},
+		}
+		return b.Client.Create(ctx, secret)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[key] = data
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	// the archives are ordered by the time prefixing their keys
+	sort.Strings(keys)
+	for len(keys) > maxBackupArchives {
+		delete(secret.Data, keys[0])
+		keys = keys[1:]
+	}
+	return b.Client.Update(ctx, secret)
+}
+
+// realizedObjectUID returns the UID the NSX resources of the CR are tagged with.
+func realizedObjectUID(policy *v1alpha1.SecurityPolicy, realized map[string]*securitypolicy.RealizedResources) types.UID {
+	if uid, ok := policy.Annotations[servicecommon.AnnotationRealizedUID]; ok && realized[uid] != nil {
+		return types.UID(uid)
+	}
+	return policy.UID
+}
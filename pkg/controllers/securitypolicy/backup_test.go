@@ -0,0 +1,98 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func readBackupArchive(t *testing.T, data []byte) *BackupArchive {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	archive := &BackupArchive{}
+	assert.NoError(t, json.NewDecoder(reader).Decode(archive))
+	return archive
+}
+
+func TestBackuper(t *testing.T) {
+	assert.Nil(t, NewBackuper(nil, nil, "nsx-system", "", ""))
+	var nilBackuper *Backuper
+	assert.NoError(t, nilBackuper.BackupNamespace(context.TODO(), nil, "ns1"))
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	deletionTime := metav1.Now()
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", UID: "ns-uid1", DeletionTimestamp: &deletionTime, Finalizers: []string{"test"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", UID: "ns-uid2"}},
+		&v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"},
+			Spec:       v1alpha1.SecurityPolicySpec{Priority: 3},
+		},
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "sp2", UID: "uid2"}},
+	).Build()
+	lister := fakeRealizedResourceLister{
+		"uid1": {SecurityPolicies: []string{"sp_uid1"}},
+		"uid3": {SecurityPolicies: []string{"sp_uid3"}, Rules: []string{"sp_uid3_0"}},
+	}
+	dir := t.TempDir()
+	b := NewBackuper(k8sClient, k8sClient, "nsx-system", "nsx-operator-backup", dir)
+	now := time.Unix(1700000000, 0)
+	b.now = func() time.Time { return now }
+	ctx := context.TODO()
+	secretKey := types.NamespacedName{Namespace: "nsx-system", Name: "nsx-operator-backup"}
+
+	// the namespace not terminating is not backed up
+	assert.NoError(t, b.BackupNamespace(ctx, lister, "ns2"))
+	assert.NoError(t, b.BackupNamespace(ctx, lister, "ns1"))
+	secret := &v1.Secret{}
+	assert.NoError(t, k8sClient.Get(ctx, secretKey, secret))
+	assert.Len(t, secret.Data, 1)
+	archive := readBackupArchive(t, secret.Data["1700000000-namespace-deletion-ns1.json.gz"])
+	assert.Equal(t, "namespace-deletion-ns1", archive.Reason)
+	assert.Equal(t, []BackupEntry{{
+		Namespace: "ns1", Name: "sp1", UID: "uid1",
+		Spec:      &v1alpha1.SecurityPolicySpec{Priority: 3},
+		Resources: &securitypolicy.RealizedResources{SecurityPolicies: []string{"sp_uid1"}},
+	}}, archive.Entries)
+	data, err := os.ReadFile(filepath.Join(dir, "1700000000-namespace-deletion-ns1.json.gz"))
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data["1700000000-namespace-deletion-ns1.json.gz"], data)
+
+	// the terminating namespace is backed up once
+	now = now.Add(time.Second)
+	assert.NoError(t, b.BackupNamespace(ctx, lister, "ns1"))
+	assert.NoError(t, k8sClient.Get(ctx, secretKey, secret))
+	assert.Len(t, secret.Data, 1)
+
+	// only the latest archives are kept in the Secret
+	for i := 0; i < maxBackupArchives; i++ {
+		now = now.Add(time.Second)
+		assert.NoError(t, b.Backup(ctx, lister, BackupReasonGC, nil, []string{"uid3"}))
+	}
+	assert.NoError(t, k8sClient.Get(ctx, secretKey, secret))
+	assert.Len(t, secret.Data, maxBackupArchives)
+	assert.NotContains(t, secret.Data, "1700000000-namespace-deletion-ns1.json.gz")
+	archive = readBackupArchive(t, secret.Data["1700000011-gc.json.gz"])
+	assert.Equal(t, []BackupEntry{{UID: "uid3", Resources: lister["uid3"]}}, archive.Entries)
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// When the cluster IPs or the ports of the cluster DNS service are changed, the security
+// policies with allowDNS are reconciled to update their DNS rules.
+
+type EnqueueRequestForDNSService struct {
+	Client client.Client
+}
+
+func (e *EnqueueRequestForDNSService) Create(_ context.Context, _ event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q)
+}
+
+func (e *EnqueueRequestForDNSService) Update(_ context.Context, _ event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q)
+}
+
+func (e *EnqueueRequestForDNSService) Delete(_ context.Context, _ event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q)
+}
+
+func (e *EnqueueRequestForDNSService) Generic(_ context.Context, _ event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q)
+}
+
+func (e *EnqueueRequestForDNSService) enqueue(q workqueue.RateLimitingInterface) {
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := e.Client.List(context.Background(), spList); err != nil {
+		log.Error(err, "failed to list all the security policy")
+		return
+	}
+	for _, securityPolicy := range spList.Items {
+		if !securityPolicy.Spec.AllowDNS {
+			continue
+		}
+		log.Info("reconcile security policy because of cluster DNS service change",
+			"namespace", securityPolicy.Namespace, "name", securityPolicy.Name)
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      securityPolicy.Name,
+				Namespace: securityPolicy.Namespace,
+			},
+		})
+	}
+}
+
+// predicateFuncsDNSService filters the events of the cluster DNS service which change the DNS rules.
+func predicateFuncsDNSService(dnsService types.NamespacedName) predicate.Funcs {
+	isDNSService := func(obj client.Object) bool {
+		return obj.GetNamespace() == dnsService.Namespace && obj.GetName() == dnsService.Name
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isDNSService(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isDNSService(e.ObjectNew) {
+				return false
+			}
+			oldObj := e.ObjectOld.(*v1.Service)
+			newObj := e.ObjectNew.(*v1.Service)
+			return !reflect.DeepEqual(oldObj.Spec.ClusterIPs, newObj.Spec.ClusterIPs) ||
+				!reflect.DeepEqual(oldObj.Spec.Ports, newObj.Spec.Ports)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isDNSService(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
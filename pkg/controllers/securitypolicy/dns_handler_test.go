@@ -0,0 +1,52 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestEnqueueRequestForDNSService(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1"}, Spec: v1alpha1.SecurityPolicySpec{AllowDNS: true}},
+		&v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp2"}},
+	).Build()
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	e := &EnqueueRequestForDNSService{Client: c}
+	e.Update(context.TODO(), event.UpdateEvent{}, q)
+	assert.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	assert.Equal(t, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "sp1"}}, item)
+
+	dnsService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-dns"},
+		Spec:       v1.ServiceSpec{ClusterIPs: []string{"10.96.0.10"}},
+	}
+	p := predicateFuncsDNSService(types.NamespacedName{Namespace: "kube-system", Name: "kube-dns"})
+	assert.True(t, p.Create(event.CreateEvent{Object: dnsService}))
+	assert.False(t, p.Create(event.CreateEvent{Object: &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "kube-dns"}}}))
+
+	newService := dnsService.DeepCopy()
+	newService.Labels = map[string]string{"k": "v"}
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: dnsService, ObjectNew: newService}))
+	newService.Spec.ClusterIPs = []string{"10.96.0.11"}
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: dnsService, ObjectNew: newService}))
+}
@@ -0,0 +1,108 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+)
+
+const (
+	// AnnotationPublishDFWDraft is annotated on the nsx-operator namespace to approve the publication of the
+	// DFW draft, it's removed once the draft is published.
+	AnnotationPublishDFWDraft = "nsx.vmware.com/publish_dfw_draft"
+	// DraftApprovalCheckInterval is the interval the approval of the DFW draft is checked at.
+	DraftApprovalCheckInterval = 30 * time.Second
+)
+
+// DraftPublisher publishes the NSX DFW draft the changes of the SecurityPolicies are staged in when dfw_draft
+// is enabled, every Interval or once the nsx-operator namespace is annotated with
+// nsx.vmware.com/publish_dfw_draft. The publication is reported by an event on the namespace. It is added to
+// the manager to only run on the leader.
+type DraftPublisher struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+	// Namespace is the nsx-operator namespace the approval is annotated on.
+	Namespace string
+	// Publish publishes the draft and returns the count of the changes published.
+	Publish func() (int, error)
+	// Interval is the cadence of the publication, 0 publishes the draft only when it's approved.
+	Interval      time.Duration
+	CheckInterval time.Duration
+
+	lastPublished time.Time
+	now           func() time.Time
+}
+
+func (p *DraftPublisher) Start(ctx context.Context) error {
+	interval := p.CheckInterval
+	if interval == 0 {
+		interval = DraftApprovalCheckInterval
+	}
+	p.lastPublished = p.clock()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		p.poll(ctx)
+	}
+}
+
+// NeedLeaderElection returns true, only the leader writes to NSX.
+func (p *DraftPublisher) NeedLeaderElection() bool {
+	return true
+}
+
+// poll publishes the draft if it's approved or the cadence is due. A failed publication is retried by the
+// next poll, the approval is kept until the draft is published.
+func (p *DraftPublisher) poll(ctx context.Context) {
+	ns := &v1.Namespace{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: p.Namespace}, ns); err != nil {
+		log.Error(err, "failed to get namespace for DFW draft approval", "namespace", p.Namespace)
+		return
+	}
+	_, approved := ns.Annotations[AnnotationPublishDFWDraft]
+	now := p.clock()
+	due := p.Interval > 0 && now.Sub(p.lastPublished) >= p.Interval
+	if !approved && !due {
+		return
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing DFW draft publication")
+		return
+	}
+	published, err := p.Publish()
+	if err != nil {
+		log.Error(err, "failed to publish DFW draft")
+		p.Recorder.Event(ns, v1.EventTypeWarning, common.ReasonDraftPublishFailed, err.Error())
+		return
+	}
+	p.lastPublished = now
+	if approved {
+		patch := client.MergeFrom(ns.DeepCopy())
+		delete(ns.Annotations, AnnotationPublishDFWDraft)
+		if err := p.Client.Patch(ctx, ns, patch); err != nil {
+			log.Error(err, "failed to remove DFW draft approval", "namespace", p.Namespace)
+		}
+	}
+	if published > 0 {
+		p.Recorder.Eventf(ns, v1.EventTypeNormal, common.ReasonDraftPublished, "Published %d changes staged in DFW draft", published)
+	}
+}
+
+func (p *DraftPublisher) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
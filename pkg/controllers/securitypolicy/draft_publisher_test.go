@@ -0,0 +1,91 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDraftPublisher_Poll(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-operator"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build()
+	recorder := record.NewFakeRecorder(10)
+	now := time.Now()
+	calls, staged := 0, 3
+	var publishErr error
+	publisher := &DraftPublisher{
+		Client:    k8sClient,
+		Recorder:  recorder,
+		Namespace: "nsx-operator",
+		Publish: func() (int, error) {
+			calls++
+			if publishErr != nil {
+				return 0, publishErr
+			}
+			published := staged
+			staged = 0
+			return published, nil
+		},
+		Interval:      time.Hour,
+		lastPublished: now,
+		now:           func() time.Time { return now },
+	}
+	ctx := context.TODO()
+	approve := func() {
+		obj := &v1.Namespace{}
+		assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "nsx-operator"}, obj))
+		obj.Annotations = map[string]string{AnnotationPublishDFWDraft: "true"}
+		assert.NoError(t, k8sClient.Update(ctx, obj))
+	}
+	approved := func() bool {
+		obj := &v1.Namespace{}
+		assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "nsx-operator"}, obj))
+		_, ok := obj.Annotations[AnnotationPublishDFWDraft]
+		return ok
+	}
+
+	// the draft is not published before the cadence is due
+	publisher.poll(ctx)
+	assert.Equal(t, 0, calls)
+
+	// the draft is published when it's approved, the approval is consumed
+	approve()
+	publisher.poll(ctx)
+	assert.Equal(t, 1, calls)
+	assert.False(t, approved())
+	assert.Contains(t, <-recorder.Events, "Published 3 changes")
+
+	// the approval is kept if the publication fails
+	approve()
+	publishErr = errors.New("ERROR")
+	publisher.poll(ctx)
+	assert.True(t, approved())
+	assert.Contains(t, <-recorder.Events, "DraftPublishFailed")
+
+	// the draft is published when the cadence is due, nothing is reported without a change
+	publishErr = nil
+	k8sClient.Get(ctx, types.NamespacedName{Name: "nsx-operator"}, ns)
+	ns.Annotations = nil
+	assert.NoError(t, k8sClient.Update(ctx, ns))
+	now = now.Add(time.Hour)
+	publisher.poll(ctx)
+	assert.Equal(t, 3, calls)
+	assert.Empty(t, recorder.Events)
+	publisher.poll(ctx)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,142 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// DriftDetector checks periodically whether the NSX resources of the SecurityPolicies have been edited in NSX
+// Manager, the drift is reported in the InSync condition and a Warning event of the CR. If the remediation is
+// enabled, the drifted resources are patched again by resyncing the CR.
+// cancel is used to break the loop during UT
+func (r *SecurityPolicyReconciler) DriftDetector(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("drift detector started", "interval", interval)
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		r.checkDrift(ctx)
+	}
+}
+
+func (r *SecurityPolicyReconciler) driftCheckInterval() time.Duration {
+	if r.Service.NSXConfig == nil || r.Service.NSXConfig.K8sConfig == nil {
+		return 0
+	}
+	return time.Duration(r.Service.NSXConfig.DriftCheckInterval) * time.Second
+}
+
+func (r *SecurityPolicyReconciler) driftRemediation() bool {
+	return r.Service.NSXConfig != nil && r.Service.NSXConfig.K8sConfig != nil && r.Service.NSXConfig.DriftRemediation
+}
+
+// checkDrift compares the NSX resources of all the SecurityPolicies in the stores with the ones in NSX.
+func (r *SecurityPolicyReconciler) checkDrift(ctx context.Context) {
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, policyList); err != nil {
+		log.Error(err, "failed to list SecurityPolicies for drift detection")
+		return
+	}
+	for i := range policyList.Items {
+		obj := &policyList.Items[i]
+		if !obj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		service, err := r.serviceFor(obj)
+		if err != nil {
+			continue
+		}
+		drift, err := service.DetectDrift(realizedObject(service, obj).UID)
+		if err != nil {
+			log.Error(err, "failed to detect drift of NSX resources", "securitypolicy", types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name})
+			continue
+		}
+		if drift == nil {
+			// not realized yet, or staged in the DFW draft
+			continue
+		}
+		r.reportDrift(ctx, service, obj, drift)
+	}
+}
+
+// reportDrift reports the drift of the CR, and forgets the drifted resources in the stores and resyncs the CR
+// if the remediation is enabled. The InSync condition is only set True once the CR has been reported drifted,
+// so the status of the CRs never edited in NSX is not updated by the checks.
+func (r *SecurityPolicyReconciler) reportDrift(ctx context.Context, service *securitypolicy.SecurityPolicyService, obj *v1alpha1.SecurityPolicy, drift *securitypolicy.Drift) {
+	key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+	if drift.Empty() {
+		if existing := getExistingConditionOfType(v1alpha1.InSync, obj.Status.Conditions); existing != nil && existing.Status != v1.ConditionTrue {
+			r.updateInSyncCondition(ctx, key, obj.UID, inSyncCondition(nil))
+		}
+		return
+	}
+	log.Info("NSX resources of SecurityPolicy drifted", "securitypolicy", key, "drift", drift.String())
+	r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonNSXDriftDetected, drift.String())
+	r.updateInSyncCondition(ctx, key, obj.UID, inSyncCondition(drift))
+	if !r.driftRemediation() || !drift.Remediable() {
+		return
+	}
+	if err := service.RemediateDrift(drift); err != nil {
+		log.Error(err, "failed to remediate drift of NSX resources", "securitypolicy", key)
+		return
+	}
+	select {
+	case r.resync <- event.GenericEvent{Object: obj}:
+	case <-ctx.Done():
+	}
+}
+
+// updateInSyncCondition updates the InSync condition of the CR, unless the CR has been recreated since.
+func (r *SecurityPolicyReconciler) updateInSyncCondition(ctx context.Context, key types.NamespacedName, uid types.UID, condition v1alpha1.Condition) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := &v1alpha1.SecurityPolicy{}
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if obj.UID != uid || !mergeCondition(obj, condition) {
+			return nil
+		}
+		return r.Client.Status().Update(ctx, obj)
+	})
+	if err != nil {
+		log.Error(err, "failed to update the InSync condition", "securitypolicy", key)
+	}
+}
+
+// inSyncCondition returns the InSync condition with the drift of the NSX resources.
+func inSyncCondition(drift *securitypolicy.Drift) v1alpha1.Condition {
+	condition := v1alpha1.Condition{Type: v1alpha1.InSync, LastTransitionTime: metav1.Now()}
+	if drift.Empty() {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = v1alpha1.ReasonInSync
+		condition.Message = "NSX Security Policy, rules and groups are unchanged in NSX"
+		return condition
+	}
+	condition.Status = v1.ConditionFalse
+	condition.Reason = v1alpha1.ReasonDrifted
+	condition.Message = "NSX resources changed in NSX, " + drift.String()
+	return condition
+}
@@ -0,0 +1,35 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func TestInSyncCondition(t *testing.T) {
+	obj := &v1alpha1.SecurityPolicy{}
+	obj.Status.Conditions = []v1alpha1.Condition{realizedCondition(nil, false)}
+
+	drifted := inSyncCondition(&securitypolicy.Drift{Modified: []string{"Rule/rule-1"}})
+	assert.Equal(t, v1.ConditionFalse, drifted.Status)
+	assert.Equal(t, v1alpha1.ReasonDrifted, drifted.Reason)
+	assert.Equal(t, "NSX resources changed in NSX, modified: [Rule/rule-1]", drifted.Message)
+	assert.True(t, mergeCondition(obj, drifted))
+	assert.False(t, mergeCondition(obj, drifted))
+	// the Realized condition is kept
+	assert.Equal(t, v1.ConditionTrue, getExistingConditionOfType(v1alpha1.Realized, obj.Status.Conditions).Status)
+
+	inSync := inSyncCondition(&securitypolicy.Drift{})
+	assert.Equal(t, v1.ConditionTrue, inSync.Status)
+	assert.Equal(t, v1alpha1.ReasonInSync, inSync.Reason)
+	assert.True(t, mergeCondition(obj, inSync))
+	assert.Len(t, obj.Status.Conditions, 2)
+	assert.Equal(t, v1alpha1.ReasonInSync, getExistingConditionOfType(v1alpha1.InSync, obj.Status.Conditions).Reason)
+}
@@ -0,0 +1,36 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// isDryRun returns whether the SecurityPolicy is previewed instead of being realized on NSX.
+func isDryRun(obj *v1alpha1.SecurityPolicy) bool {
+	return obj.Annotations[servicecommon.AnnotationDryRun] == "true"
+}
+
+// dryRun previews the changes of the NSX resources of the SecurityPolicy in the CR status instead of
+// realizing it on NSX, the NSX resources realized from a previous spec are kept.
+func (r *SecurityPolicyReconciler) dryRun(ctx context.Context, service *securitypolicy.SecurityPolicyService, obj *v1alpha1.SecurityPolicy) error {
+	status := service.DryRunSecurityPolicy(realizedObject(service, obj))
+	if reflect.DeepEqual(obj.Status.DryRun, status) {
+		log.V(1).Info("SecurityPolicy dry run is unchanged", "securitypolicy", obj.Name, "namespace", obj.Namespace)
+		return nil
+	}
+	obj.Status.DryRun = status
+	if err := r.Client.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "failed to update dry run", "securitypolicy", obj.Name, "namespace", obj.Namespace)
+		return err
+	}
+	log.Info("updated SecurityPolicy dry run", "securitypolicy", obj.Name, "namespace", obj.Namespace,
+		"summary", status.Summary, "error", status.Error)
+	return nil
+}
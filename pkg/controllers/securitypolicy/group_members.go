@@ -0,0 +1,99 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+)
+
+// GroupMembersCollector reads periodically the effective members of the NSX groups of the SecurityPolicies from NSX,
+// they are summarized in the CR status.
+// cancel is used to break the loop during UT
+func (r *SecurityPolicyReconciler) GroupMembersCollector(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("group members collector started", "interval", interval)
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		r.collectGroupMembers(ctx)
+	}
+}
+
+func (r *SecurityPolicyReconciler) groupMembersInterval() time.Duration {
+	if r.Service.NSXConfig == nil || r.Service.NSXConfig.K8sConfig == nil {
+		return 0
+	}
+	return time.Duration(r.Service.NSXConfig.GroupMembersInterval) * time.Second
+}
+
+// collectGroupMembers reads the group members of all the realized SecurityPolicies and reports them in the status.
+func (r *SecurityPolicyReconciler) collectGroupMembers(ctx context.Context) {
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, policyList); err != nil {
+		log.Error(err, "failed to list SecurityPolicies for group members")
+		return
+	}
+	for i := range policyList.Items {
+		obj := &policyList.Items[i]
+		if !obj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+		service, err := r.serviceFor(obj)
+		if err != nil {
+			continue
+		}
+		members, err := service.CollectGroupMembers(realizedObject(service, obj))
+		if err != nil {
+			log.Error(err, "failed to collect group members", "securitypolicy", key)
+			continue
+		}
+		if members == nil {
+			// not realized yet
+			continue
+		}
+		r.updateGroupMembers(ctx, key, obj.UID, members)
+	}
+}
+
+// updateGroupMembers reports the group members in the CR status, unless the CR has been recreated since. The status
+// is only updated when the members change, the collected time alone doesn't trigger the update.
+func (r *SecurityPolicyReconciler) updateGroupMembers(ctx context.Context, key types.NamespacedName, uid types.UID, members []v1alpha1.GroupMembers) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := &v1alpha1.SecurityPolicy{}
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if obj.UID != uid {
+			return nil
+		}
+		if existing := obj.Status.GroupMembers; existing != nil && reflect.DeepEqual(existing.Groups, members) {
+			return nil
+		}
+		obj.Status.GroupMembers = &v1alpha1.GroupMembersSummary{CollectedTime: metav1.Now(), Groups: members}
+		return r.Client.Status().Update(ctx, obj)
+	})
+	if err != nil {
+		log.Error(err, "failed to update group members", "securitypolicy", key)
+	}
+}
@@ -0,0 +1,48 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestSecurityPolicyReconciler_updateGroupMembers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sp).WithStatusSubresource(sp).Build()
+	r := &SecurityPolicyReconciler{Client: k8sClient}
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	getGroupMembers := func() *v1alpha1.GroupMembersSummary {
+		obj := &v1alpha1.SecurityPolicy{}
+		assert.NoError(t, k8sClient.Get(ctx, key, obj))
+		return obj.Status.GroupMembers
+	}
+
+	members := []v1alpha1.GroupMembers{
+		{Group: "sp_uid1_scope", Roles: []string{"appliedTo"}, IPCount: 1, IPs: []string{"10.0.0.1"}, PortCount: 1, Ports: []string{"pod-a"}},
+		{Group: "sp_uid1_0_src", Roles: []string{"rules[0].source"}},
+	}
+	r.updateGroupMembers(ctx, key, "uid1", members)
+	assert.Equal(t, members, getGroupMembers().Groups)
+
+	// the unchanged members are not updated
+	collected := getGroupMembers().CollectedTime
+	r.updateGroupMembers(ctx, key, "uid1", members)
+	assert.Equal(t, collected, getGroupMembers().CollectedTime)
+
+	// the recreated CR is not updated
+	r.updateGroupMembers(ctx, key, "uid0", nil)
+	assert.Equal(t, members, getGroupMembers().Groups)
+}
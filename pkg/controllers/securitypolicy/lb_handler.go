@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// When a LoadBalancer Service is changed, the security policies in its namespace are reconciled to update
+// the rules allowing the health checks of the load balancer to the backends.
+
+type EnqueueRequestForLBService struct {
+	Client client.Client
+}
+
+func (e *EnqueueRequestForLBService) Create(_ context.Context, createEvent event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(createEvent.Object, q)
+}
+
+func (e *EnqueueRequestForLBService) Update(_ context.Context, updateEvent event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(updateEvent.ObjectNew, q)
+}
+
+func (e *EnqueueRequestForLBService) Delete(_ context.Context, deleteEvent event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(deleteEvent.Object, q)
+}
+
+func (e *EnqueueRequestForLBService) Generic(_ context.Context, _ event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("service generic event, do nothing")
+}
+
+func (e *EnqueueRequestForLBService) enqueue(svc client.Object, q workqueue.RateLimitingInterface) {
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := e.Client.List(context.Background(), spList, client.InNamespace(svc.GetNamespace())); err != nil {
+		log.Error(err, "failed to list security policy", "namespace", svc.GetNamespace())
+		return
+	}
+	for _, securityPolicy := range spList.Items {
+		log.Info("reconcile security policy because of load balancer service change",
+			"namespace", securityPolicy.Namespace, "name", securityPolicy.Name, "service", svc.GetName())
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      securityPolicy.Name,
+				Namespace: securityPolicy.Namespace,
+			},
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
 )
 
@@ -26,6 +27,8 @@ import (
 
 type EnqueueRequestForPod struct {
 	Client client.Client
+	// Dampener delays the reconciles to batch the Pod events, the reconciles are not delayed if it's nil.
+	Dampener *common.GroupUpdateDampener
 }
 
 func (e *EnqueueRequestForPod) Create(_ context.Context, createEvent event.CreateEvent, q workqueue.RateLimitingInterface) {
@@ -70,7 +73,7 @@ func (e *EnqueueRequestForPod) Raw(evt interface{}, q workqueue.RateLimitingInte
 		return
 	}
 	pods = append(pods, *pod)
-	err := reconcileSecurityPolicy(e.Client, pods, q)
+	err := reconcileSecurityPolicy(e.Client, pods, e.Dampener.Queue(q))
 	if err != nil {
 		log.Error(err, "failed to reconcile security policy")
 	}
@@ -102,8 +105,9 @@ var PredicateFuncsPod = predicate.Funcs{
 		oldObj := e.ObjectOld.(*v1.Pod)
 		newObj := e.ObjectNew.(*v1.Pod)
 		log.V(1).Info("receive pod update event", "namespace", oldObj.Namespace, "name", oldObj.Name)
-		if reflect.DeepEqual(oldObj.ObjectMeta.Labels, newObj.ObjectMeta.Labels) {
-			log.V(1).Info("label of pod is not changed, ignore it", "name", oldObj.Name)
+		if reflect.DeepEqual(oldObj.ObjectMeta.Labels, newObj.ObjectMeta.Labels) &&
+			reflect.DeepEqual(util.GetPodSecondaryIPs(oldObj), util.GetPodSecondaryIPs(newObj)) {
+			log.V(1).Info("label and secondary addresses of pod are not changed, ignore it", "name", oldObj.Name)
 			return false
 		}
 		if util.CheckPodHasNamedPort(*newObj, "update") {
@@ -0,0 +1,83 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// checkPriorityConflict detects the other SecurityPolicies realized on the same NSX declaring the priority of the
+// CR, NSX orders the policies with the same sequence number non-deterministically. The collision is reported in a
+// Warning event and the PriorityConflict condition of the CR. If the priorities are spread, the rank of the CR
+// among them is set for its sequence number, and the others are resynced when the rank changes since theirs may
+// have shifted.
+func (r *SecurityPolicyReconciler) checkPriorityConflict(ctx context.Context, service *securitypolicy.SecurityPolicyService, obj, realized *v1alpha1.SecurityPolicy) error {
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, policyList); err != nil {
+		return err
+	}
+	var policies []v1alpha1.SecurityPolicy
+	for i := range policyList.Items {
+		if policyService, err := r.serviceFor(&policyList.Items[i]); err == nil && policyService == service {
+			policies = append(policies, policyList.Items[i])
+		}
+	}
+	conflicts, rank := securitypolicy.PriorityConflicts(obj, policies)
+	if service.PrioritySpreading() && service.SetPriorityRank(realized.UID, rank) {
+		for i := range policies {
+			if policies[i].UID == obj.UID || policies[i].Spec.Priority != obj.Spec.Priority {
+				continue
+			}
+			select {
+			case r.resync <- event.GenericEvent{Object: &policies[i]}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonPriorityConflict, priorityConflictCondition(obj, conflicts).Message)
+	} else if getExistingConditionOfType(v1alpha1.PriorityConflict, obj.Status.Conditions) == nil {
+		// the condition is only added once the CR has collided
+		return nil
+	}
+	if mergeCondition(obj, priorityConflictCondition(obj, conflicts)) {
+		if err := r.Client.Status().Update(ctx, obj); err != nil {
+			log.Error(err, "failed to update the PriorityConflict condition", "securitypolicy", types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name})
+		}
+	}
+	return nil
+}
+
+// priorityConflictCondition returns the PriorityConflict condition with the SecurityPolicies declaring the same
+// priority as the CR.
+func priorityConflictCondition(obj *v1alpha1.SecurityPolicy, conflicts []types.NamespacedName) v1alpha1.Condition {
+	condition := v1alpha1.Condition{Type: v1alpha1.PriorityConflict, LastTransitionTime: metav1.Now()}
+	if len(conflicts) == 0 {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = v1alpha1.ReasonPriorityUnique
+		condition.Message = fmt.Sprintf("no other SecurityPolicy declares priority %d", obj.Spec.Priority)
+		return condition
+	}
+	names := make([]string, len(conflicts))
+	for i := range conflicts {
+		names[i] = conflicts[i].String()
+	}
+	condition.Status = v1.ConditionTrue
+	condition.Reason = v1alpha1.ReasonPriorityShared
+	condition.Message = fmt.Sprintf("priority %d is also declared by SecurityPolicies %s, the order of the policies in NSX is not deterministic",
+		obj.Spec.Priority, strings.Join(names, ", "))
+	return condition
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// PolicyProtector reads the SecurityPolicies protected by the cluster-scoped ProtectedPolicies. The NSX
+// resources realized for a protected SecurityPolicy are neither updated by the CR changes, nor deleted with
+// the CR, its Namespace or by the garbage collection, until the protection is lifted by the cluster admins.
+// Nothing is protected by a nil PolicyProtector.
+type PolicyProtector struct {
+	Client client.Client
+}
+
+// Protected returns the SecurityPolicies protected by the ProtectedPolicies without override, it's empty
+// if the ProtectedPolicy CRD isn't installed.
+func (p *PolicyProtector) Protected(ctx context.Context) (sets.Set[types.NamespacedName], error) {
+	protected := sets.New[types.NamespacedName]()
+	if p == nil {
+		return protected, nil
+	}
+	policyList := &v1alpha1.ProtectedPolicyList{}
+	if err := p.Client.List(ctx, policyList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return protected, nil
+		}
+		return nil, err
+	}
+	for _, policy := range policyList.Items {
+		if policy.Spec.Override {
+			continue
+		}
+		for _, ref := range policy.Spec.SecurityPolicies {
+			protected.Insert(types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+		}
+	}
+	return protected, nil
+}
+
+// IsProtected checks whether the SecurityPolicy is protected.
+func (p *PolicyProtector) IsProtected(ctx context.Context, obj *v1alpha1.SecurityPolicy) (bool, error) {
+	protected, err := p.Protected(ctx)
+	if err != nil {
+		return false, err
+	}
+	return protected.Has(types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}), nil
+}
+
+// skipProtected returns the stale UIDs except the ones realized for the protected SecurityPolicies, which are
+// kept by the garbage collection though their CRs have been removed.
+func skipProtected(service *securitypolicy.SecurityPolicyService, staleUIDs []string, protected sets.Set[types.NamespacedName]) []string {
+	if len(protected) == 0 {
+		return staleUIDs
+	}
+	var unprotected []string
+	for _, uid := range staleUIDs {
+		if name, ok := service.RealizedName(types.UID(uid)); ok && protected.Has(name) {
+			log.V(1).Info("GC skipped protected SecurityPolicy", "UID", uid, "securitypolicy", name)
+			continue
+		}
+		unprotected = append(unprotected, uid)
+	}
+	return unprotected
+}
+
+// protectedPolicyMapFunc enqueues the SecurityPolicies referred to by the ProtectedPolicy, so the changes
+// and the deletion of the SecurityPolicies skipped are realized once the protection is lifted.
+func protectedPolicyMapFunc(_ context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*v1alpha1.ProtectedPolicy)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, ref := range policy.Spec.SecurityPolicies {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}})
+	}
+	return requests
+}
@@ -0,0 +1,63 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestPolicyProtector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	system := &v1alpha1.ProtectedPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "system"},
+		Spec: v1alpha1.ProtectedPolicySpec{SecurityPolicies: []v1alpha1.ProtectedPolicyReference{
+			{Namespace: "ns1", Name: "allow-dns"},
+		}},
+	}
+	overridden := &v1alpha1.ProtectedPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "overridden"},
+		Spec: v1alpha1.ProtectedPolicySpec{
+			SecurityPolicies: []v1alpha1.ProtectedPolicyReference{{Namespace: "ns1", Name: "isolation"}},
+			Override:         true,
+		},
+	}
+	protector := &PolicyProtector{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(system, overridden).Build()}
+	ctx := context.TODO()
+
+	protected, err := protector.Protected(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sets.New(types.NamespacedName{Namespace: "ns1", Name: "allow-dns"}), protected)
+
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "allow-dns"}}
+	isProtected, err := protector.IsProtected(ctx, sp)
+	assert.NoError(t, err)
+	assert.True(t, isProtected)
+
+	// the protection is lifted by the override
+	sp.Name = "isolation"
+	isProtected, err = protector.IsProtected(ctx, sp)
+	assert.NoError(t, err)
+	assert.False(t, isProtected)
+
+	// nothing is protected by a nil protector
+	var nilProtector *PolicyProtector
+	isProtected, err = nilProtector.IsProtected(ctx, sp)
+	assert.NoError(t, err)
+	assert.False(t, isProtected)
+
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "isolation"}}},
+		protectedPolicyMapFunc(ctx, overridden))
+}
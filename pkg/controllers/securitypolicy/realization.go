@@ -0,0 +1,163 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/realizestate"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// RealizationReporter reports the realization of the NSX SecurityPolicy, rules and groups created for a
+// SecurityPolicy CR in its Realized condition. The intents are polled by the realization watcher, so the
+// reconcile doesn't wait for them. Nothing is reported by a nil RealizationReporter.
+type RealizationReporter struct {
+	Client client.Client
+}
+
+// Report sets the Realized condition of the CR InProgress and watches the realization of its intents. The
+// realization is only watched again if the NSX resources were changed by the sync, or if they are not
+// realized yet. The realization is not watched while the changes are staged in the DFW draft, NSX doesn't
+// realize them until the draft is published.
+func (r *RealizationReporter) Report(ctx context.Context, service *securitypolicy.SecurityPolicyService, realized, obj *v1alpha1.SecurityPolicy, changed bool) {
+	if r == nil || service.DraftEnabled() {
+		return
+	}
+	if condition := getExistingConditionOfType(v1alpha1.Realized, obj.Status.Conditions); !changed && condition != nil && condition.Status == v1.ConditionTrue {
+		return
+	}
+	intents, err := service.RealizationIntents(realized)
+	if err != nil {
+		log.Error(err, "failed to get the realization intents", "securitypolicy", types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name})
+		return
+	}
+	pool := realizestate.GetWatcherPool(service.Service)
+	r.watchIntents(ctx, obj, intents, func(intentPath string, callback realizestate.RealizeCallback) {
+		pool.Watch(intentPath, "", callback)
+	})
+}
+
+// watchIntents reports the CR InProgress, then the result of the realization once all the intents complete.
+func (r *RealizationReporter) watchIntents(ctx context.Context, obj *v1alpha1.SecurityPolicy, intents []string,
+	watch func(intentPath string, callback realizestate.RealizeCallback),
+) {
+	if len(intents) == 0 {
+		return
+	}
+	key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+	uid, generation := obj.UID, obj.Generation
+	r.updateRealizedCondition(ctx, key, uid, generation, realizedCondition(nil, true))
+	tracker := newRealizationTracker(intents)
+	for _, intentPath := range intents {
+		watch(intentPath, func(intentPath, _ string, err error) {
+			if done, err := tracker.complete(intentPath, err); done {
+				// the callbacks are called by the watcher workers out of the reconcile
+				r.updateRealizedCondition(context.Background(), key, uid, generation, realizedCondition(err, false))
+			}
+		})
+	}
+}
+
+// updateRealizedCondition updates the Realized condition of the CR, unless the CR has been recreated or its
+// spec has changed since, whose realization is reported by the following sync.
+func (r *RealizationReporter) updateRealizedCondition(ctx context.Context, key types.NamespacedName, uid types.UID, generation int64, condition v1alpha1.Condition) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := &v1alpha1.SecurityPolicy{}
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if obj.UID != uid || obj.Generation != generation {
+			return nil
+		}
+		if !mergeCondition(obj, condition) {
+			return nil
+		}
+		return r.Client.Status().Update(ctx, obj)
+	})
+	if err != nil {
+		log.Error(err, "failed to update the Realized condition", "securitypolicy", key)
+		return
+	}
+	log.V(1).Info("updated the Realized condition", "securitypolicy", key, "reason", condition.Reason)
+}
+
+// realizedCondition returns the Realized condition with the result of the realization.
+func realizedCondition(err error, inProgress bool) v1alpha1.Condition {
+	condition := v1alpha1.Condition{Type: v1alpha1.Realized, LastTransitionTime: metav1.Now()}
+	switch {
+	case inProgress:
+		condition.Status = v1.ConditionUnknown
+		condition.Reason = v1alpha1.ReasonInProgress
+		condition.Message = "NSX Security Policy is being realized"
+	case err != nil:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = v1alpha1.ReasonRealizationError
+		condition.Message = err.Error()
+	default:
+		condition.Status = v1.ConditionTrue
+		condition.Reason = v1alpha1.ReasonRealized
+		condition.Message = "NSX Security Policy, rules and groups have been realized"
+	}
+	return condition
+}
+
+// mergeCondition merges the condition into the CR status, the transition time is kept if the status is
+// unchanged. It returns false if the condition is unchanged.
+func mergeCondition(obj *v1alpha1.SecurityPolicy, condition v1alpha1.Condition) bool {
+	existing := getExistingConditionOfType(condition.Type, obj.Status.Conditions)
+	if existing == nil {
+		obj.Status.Conditions = append(obj.Status.Conditions, condition)
+		return true
+	}
+	if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+		return false
+	}
+	if existing.Status != condition.Status {
+		existing.LastTransitionTime = condition.LastTransitionTime
+	}
+	existing.Status = condition.Status
+	existing.Reason = condition.Reason
+	existing.Message = condition.Message
+	return true
+}
+
+// realizationTracker tracks the intents of a CR being realized, the first error is reported once all of them
+// complete.
+type realizationTracker struct {
+	mu      sync.Mutex
+	pending sets.Set[string]
+	err     error
+	done    bool
+}
+
+func newRealizationTracker(intents []string) *realizationTracker {
+	return &realizationTracker{pending: sets.New(intents...)}
+}
+
+// complete records the realization of the intent, it returns true with the first error once, when the last
+// intent completes.
+func (t *realizationTracker) complete(intentPath string, err error) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil && t.err == nil {
+		t.err = fmt.Errorf("%s: %w", intentPath, err)
+	}
+	t.pending.Delete(intentPath)
+	if t.done || t.pending.Len() > 0 {
+		return false, nil
+	}
+	t.done = true
+	return true, t.err
+}
@@ -0,0 +1,87 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/realizestate"
+)
+
+func TestRealizationReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1", Generation: 2}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sp).WithStatusSubresource(sp).Build()
+	reporter := &RealizationReporter{Client: k8sClient}
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	realizedReason := func() (v1.ConditionStatus, string) {
+		obj := &v1alpha1.SecurityPolicy{}
+		assert.NoError(t, k8sClient.Get(ctx, key, obj))
+		condition := getExistingConditionOfType(v1alpha1.Realized, obj.Status.Conditions)
+		if !assert.NotNil(t, condition) {
+			return "", ""
+		}
+		return condition.Status, condition.Reason
+	}
+
+	callbacks := map[string]realizestate.RealizeCallback{}
+	watch := func(intentPath string, callback realizestate.RealizeCallback) {
+		callbacks[intentPath] = callback
+	}
+	intents := []string{"/infra/domains/default/groups/g1", "/infra/domains/default/security-policies/sp1"}
+	reporter.watchIntents(ctx, sp, intents, watch)
+	status, reason := realizedReason()
+	assert.Equal(t, v1.ConditionUnknown, status)
+	assert.Equal(t, v1alpha1.ReasonInProgress, reason)
+
+	// the result is reported once all the intents complete
+	callbacks[intents[0]](intents[0], "", errors.New("ERROR"))
+	_, reason = realizedReason()
+	assert.Equal(t, v1alpha1.ReasonInProgress, reason)
+	callbacks[intents[1]](intents[1], "", nil)
+	status, reason = realizedReason()
+	assert.Equal(t, v1.ConditionFalse, status)
+	assert.Equal(t, v1alpha1.ReasonRealizationError, reason)
+
+	// the realization of a former generation is not reported
+	reporter.watchIntents(ctx, sp, intents, watch)
+	obj := &v1alpha1.SecurityPolicy{}
+	assert.NoError(t, k8sClient.Get(ctx, key, obj))
+	obj.Generation = 3
+	assert.NoError(t, k8sClient.Update(ctx, obj))
+	for _, intentPath := range intents {
+		callbacks[intentPath](intentPath, "", nil)
+	}
+	_, reason = realizedReason()
+	assert.Equal(t, v1alpha1.ReasonInProgress, reason)
+
+	// nothing is reported by a nil reporter
+	var nilReporter *RealizationReporter
+	nilReporter.Report(ctx, nil, sp, sp, true)
+}
+
+func TestRealizationTracker(t *testing.T) {
+	tracker := newRealizationTracker([]string{"a", "b"})
+	done, err := tracker.complete("a", errors.New("ERROR"))
+	assert.False(t, done)
+	assert.NoError(t, err)
+	done, err = tracker.complete("b", nil)
+	assert.True(t, done)
+	assert.EqualError(t, err, "a: ERROR")
+	// the merged callbacks of the same intent are reported once
+	done, _ = tracker.complete("b", nil)
+	assert.False(t, done)
+}
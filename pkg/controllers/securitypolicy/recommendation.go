@@ -0,0 +1,146 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+const (
+	// RecommendationConfigMapName is the ConfigMap in the namespace of nsx-operator which records the
+	// recommendations ingested, so that a rejected recommendation is not materialized again.
+	RecommendationConfigMapName = "nsx-operator-recommendations"
+	RecommendationIngestedKey   = "ingested"
+	// RecommendationSimulateHours is the hours of observed flows the pending SecurityPolicy is simulated against.
+	RecommendationSimulateHours = "24"
+)
+
+// RecommendationIngester materializes the NSX Intelligence recommendations scoped to the Pods of the cluster
+// as SecurityPolicy CRs pending for approval. The pending SecurityPolicy is annotated with
+// nsx.vmware.com/simulate_hours, so it is only simulated against the observed flows, the admin approves it
+// by removing the annotation or rejects it by deleting the CR. It is added to the manager to only run on
+// the leader.
+type RecommendationIngester struct {
+	Client client.Client
+	Reader client.Reader
+	Source securitypolicy.RecommendationSource
+	// Namespace is the namespace of the ConfigMap recording the ingested recommendations.
+	Namespace string
+	Interval  time.Duration
+}
+
+// Ingest creates the SecurityPolicy CRs for the recommendations not ingested yet.
+func (i *RecommendationIngester) Ingest(ctx context.Context) error {
+	recommendations, err := i.Source.ListRecommendations()
+	if err != nil {
+		return err
+	}
+	cm, ingested, err := i.getIngested(ctx)
+	if err != nil {
+		return err
+	}
+	resolve, err := newEndpointResolver(ctx, i.Client)
+	if err != nil {
+		return err
+	}
+	count := ingested.Len()
+	for idx := range recommendations {
+		recommendation := &recommendations[idx]
+		if ingested.Has(recommendation.ID) {
+			continue
+		}
+		securityPolicy, err := securitypolicy.BuildRecommendedSecurityPolicy(recommendation, resolve)
+		if err != nil {
+			// the recommendation may be scoped to the cluster after the Pods are created, retry it next time
+			log.V(1).Info("skip recommendation", "recommendation", recommendation.ID, "reason", err.Error())
+			continue
+		}
+		if isSysNs, err := util.IsSystemNamespace(i.Client, securityPolicy.Namespace, nil); err != nil {
+			return err
+		} else if isSysNs {
+			log.V(1).Info("skip recommendation in system Namespace", "recommendation", recommendation.ID, "namespace", securityPolicy.Namespace)
+			continue
+		}
+		securityPolicy.Annotations[servicecommon.AnnotationSimulateHours] = RecommendationSimulateHours
+		if err := i.Client.Create(ctx, securityPolicy); err != nil && !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "failed to create SecurityPolicy for recommendation", "recommendation", recommendation.ID)
+			continue
+		}
+		log.Info("created SecurityPolicy pending for approval", "recommendation", recommendation.ID,
+			"securitypolicy", securityPolicy.Name, "namespace", securityPolicy.Namespace)
+		ingested.Insert(recommendation.ID)
+	}
+	if ingested.Len() == count && cm != nil {
+		return nil
+	}
+	return i.updateIngested(ctx, cm, ingested)
+}
+
+func (i *RecommendationIngester) getIngested(ctx context.Context) (*v1.ConfigMap, sets.Set[string], error) {
+	ingested := sets.New[string]()
+	cm := &v1.ConfigMap{}
+	key := types.NamespacedName{Namespace: i.Namespace, Name: RecommendationConfigMapName}
+	if err := i.Reader.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ingested, nil
+		}
+		return nil, nil, err
+	}
+	var ids []string
+	if data, ok := cm.Data[RecommendationIngestedKey]; ok {
+		if err := json.Unmarshal([]byte(data), &ids); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cm, ingested.Insert(ids...), nil
+}
+
+func (i *RecommendationIngester) updateIngested(ctx context.Context, cm *v1.ConfigMap, ingested sets.Set[string]) error {
+	ids := ingested.UnsortedList()
+	sort.Strings(ids)
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	if cm == nil {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: i.Namespace, Name: RecommendationConfigMapName},
+			Data:       map[string]string{RecommendationIngestedKey: string(data)},
+		}
+		return i.Client.Create(ctx, cm)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[RecommendationIngestedKey] = string(data)
+	return i.Client.Update(ctx, cm)
+}
+
+// Start implements manager.Runnable, it ingests the recommendations periodically until ctx is done.
+func (i *RecommendationIngester) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(i.Interval):
+		}
+		if err := i.Ingest(ctx); err != nil {
+			log.Error(err, "failed to ingest NSX Intelligence recommendations")
+		}
+	}
+}
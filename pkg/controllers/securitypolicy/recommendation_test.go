@@ -0,0 +1,85 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+type fakeRecommendationSource struct {
+	recommendations []securitypolicy.Recommendation
+}
+
+func (s *fakeRecommendationSource) ListRecommendations() ([]securitypolicy.Recommendation, error) {
+	return s.recommendations, nil
+}
+
+func TestRecommendationIngester_Ingest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	objs := []runtime.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+		// util.IsSystemNamespace gets the Namespace with its name as the namespace, the fake client doesn't drop it
+		// for the cluster scoped Namespace as the API server does.
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-system", Annotations: map[string]string{"vmware-system-shared-t1": "true"}}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{PodIPs: []v1.PodIP{{IP: "10.0.0.1"}}},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dns", Labels: map[string]string{"app": "dns"}},
+			Status:     v1.PodStatus{PodIPs: []v1.PodIP{{IP: "10.0.0.2"}}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	newRecommendation := func(id, appliedTo string) securitypolicy.Recommendation {
+		return securitypolicy.Recommendation{ID: id, Rules: []securitypolicy.RecommendedRule{{
+			Action:    v1alpha1.RuleActionAllow,
+			Direction: v1alpha1.RuleDirectionIn,
+			AppliedTo: []string{appliedTo},
+			Peers:     []string{"192.168.0.1"},
+		}}}
+	}
+	source := &fakeRecommendationSource{recommendations: []securitypolicy.Recommendation{
+		newRecommendation("rec-web", "10.0.0.1"),
+		newRecommendation("rec-dns", "10.0.0.2"),
+		newRecommendation("rec-unknown", "10.0.1.1"),
+	}}
+	ingester := &RecommendationIngester{Client: k8sClient, Reader: k8sClient, Source: source, Namespace: "vmware-system-nsx"}
+	ctx := context.TODO()
+
+	assert.NoError(t, ingester.Ingest(ctx))
+	spList := &v1alpha1.SecurityPolicyList{}
+	assert.NoError(t, k8sClient.List(ctx, spList))
+	assert.Len(t, spList.Items, 1)
+	sp := spList.Items[0]
+	assert.Equal(t, "ns1", sp.Namespace)
+	assert.Equal(t, securitypolicy.RecommendedSecurityPolicyName("rec-web"), sp.Name)
+	assert.Equal(t, RecommendationSimulateHours, sp.Annotations[servicecommon.AnnotationSimulateHours])
+	assert.Equal(t, "rec-web", sp.Annotations[servicecommon.AnnotationRecommendationID])
+
+	cm := &v1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "vmware-system-nsx", Name: RecommendationConfigMapName}, cm))
+	assert.Equal(t, `["rec-web"]`, cm.Data[RecommendationIngestedKey])
+
+	// the rejected recommendation is not materialized again
+	assert.NoError(t, k8sClient.Delete(ctx, &sp))
+	assert.NoError(t, ingester.Ingest(ctx))
+	assert.NoError(t, k8sClient.List(ctx, spList))
+	assert.Empty(t, spList.Items)
+}
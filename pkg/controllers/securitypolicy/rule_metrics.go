@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// RuleMetricsReporter exports the count of realized NSX rules per namespace by rule action and
+// direction periodically, e.g. to show the namespaces covered by drop rules.
+// cancel is used to break the loop during UT
+func (r *SecurityPolicyReconciler) RuleMetricsReporter(cancel chan bool, interval time.Duration) {
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		r.reportRuleMetrics()
+	}
+}
+
+// reportRuleMetrics sums the rules realized on all the NSX sites, the metrics of the keys which have
+// no rule any more are deleted.
+func (r *SecurityPolicyReconciler) reportRuleMetrics() {
+	counts := r.Service.CountRulesByAction()
+	for _, service := range r.SiteServices {
+		for key, count := range service.CountRulesByAction() {
+			counts[key] += count
+		}
+	}
+	current := sets.New[securitypolicy.RuleCountKey]()
+	for key, count := range counts {
+		current.Insert(key)
+		metrics.GaugeSet(r.Service.NSXConfig, metrics.SecurityPolicyRuleCount, float64(count), key.Namespace, key.Action, key.Direction)
+	}
+	for key := range r.reportedRuleCounts.Difference(current) {
+		metrics.GaugeDelete(r.Service.NSXConfig, metrics.SecurityPolicyRuleCount, key.Namespace, key.Action, key.Direction)
+	}
+	r.reportedRuleCounts = current
+}
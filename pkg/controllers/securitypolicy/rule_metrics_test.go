@@ -0,0 +1,48 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func TestSecurityPolicyReconciler_reportRuleMetrics(t *testing.T) {
+	service := &securitypolicy.SecurityPolicyService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"}},
+		},
+	}
+	siteService := &securitypolicy.SecurityPolicyService{}
+	r := &SecurityPolicyReconciler{Service: service, SiteServices: map[string]*securitypolicy.SecurityPolicyService{"site-b": siteService}}
+	drop := securitypolicy.RuleCountKey{Namespace: "ns1", Action: "drop", Direction: "in"}
+	allow := securitypolicy.RuleCountKey{Namespace: "ns1", Action: "allow", Direction: "in"}
+	counts := map[*securitypolicy.SecurityPolicyService]map[securitypolicy.RuleCountKey]int{
+		service:     {drop: 1, allow: 2},
+		siteService: {drop: 1},
+	}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(service), "CountRulesByAction", func(s *securitypolicy.SecurityPolicyService) map[securitypolicy.RuleCountKey]int {
+		result := map[securitypolicy.RuleCountKey]int{}
+		for key, count := range counts[s] {
+			result[key] = count
+		}
+		return result
+	})
+	defer patches.Reset()
+
+	r.reportRuleMetrics()
+	assert.Equal(t, sets.New(drop, allow), r.reportedRuleCounts)
+
+	counts[service] = map[securitypolicy.RuleCountKey]int{}
+	r.reportRuleMetrics()
+	assert.Equal(t, sets.New(drop), r.reportedRuleCounts)
+}
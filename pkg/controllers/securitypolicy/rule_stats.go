@@ -0,0 +1,140 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// ruleStatsKey is the labels of the rule statistics metrics of a rule.
+type ruleStatsKey struct {
+	namespace string
+	name      string
+	rule      string
+}
+
+// RuleStatsCollector collects periodically the hit statistics of the rules of the SecurityPolicies from NSX, they
+// are reported by the metrics, and in the CR status if rule_stats_status is enabled.
+// cancel is used to break the loop during UT
+func (r *SecurityPolicyReconciler) RuleStatsCollector(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("rule statistics collector started", "interval", interval)
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		r.collectRuleStats(ctx)
+	}
+}
+
+func (r *SecurityPolicyReconciler) ruleStatsInterval() time.Duration {
+	if r.Service.NSXConfig == nil || r.Service.NSXConfig.K8sConfig == nil {
+		return 0
+	}
+	return time.Duration(r.Service.NSXConfig.RuleStatsInterval) * time.Second
+}
+
+func (r *SecurityPolicyReconciler) ruleStatsStatus() bool {
+	return r.Service.NSXConfig != nil && r.Service.NSXConfig.K8sConfig != nil && r.Service.NSXConfig.RuleStatsStatus
+}
+
+// collectRuleStats reads the rule statistics of all the SecurityPolicies, the metrics of the rules which are gone
+// are deleted.
+func (r *SecurityPolicyReconciler) collectRuleStats(ctx context.Context) {
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, policyList); err != nil {
+		log.Error(err, "failed to list SecurityPolicies for rule statistics")
+		return
+	}
+	current := sets.New[ruleStatsKey]()
+	for i := range policyList.Items {
+		obj := &policyList.Items[i]
+		if !obj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+		service, err := r.serviceFor(obj)
+		if err != nil {
+			continue
+		}
+		stats, err := service.CollectRuleStats(realizedObject(service, obj))
+		if err != nil {
+			log.Error(err, "failed to collect rule statistics", "securitypolicy", key)
+			// the metrics are kept until the statistics are read
+			for reported := range r.reportedRuleStats {
+				if reported.namespace == obj.Namespace && reported.name == obj.Name {
+					current.Insert(reported)
+				}
+			}
+			continue
+		}
+		if stats == nil {
+			// not realized yet
+			continue
+		}
+		for _, stat := range stats {
+			statsKey := ruleStatsKey{namespace: obj.Namespace, name: obj.Name, rule: strconv.Itoa(stat.Index)}
+			current.Insert(statsKey)
+			metrics.GaugeSet(r.Service.NSXConfig, metrics.SecurityPolicyRuleHitCount, float64(stat.HitCount), statsKey.namespace, statsKey.name, statsKey.rule)
+			metrics.GaugeSet(r.Service.NSXConfig, metrics.SecurityPolicyRuleByteCount, float64(stat.ByteCount), statsKey.namespace, statsKey.name, statsKey.rule)
+		}
+		if r.ruleStatsStatus() {
+			r.updateRuleStats(ctx, key, obj.UID, stats)
+		}
+	}
+	for key := range r.reportedRuleStats.Difference(current) {
+		metrics.GaugeDelete(r.Service.NSXConfig, metrics.SecurityPolicyRuleHitCount, key.namespace, key.name, key.rule)
+		metrics.GaugeDelete(r.Service.NSXConfig, metrics.SecurityPolicyRuleByteCount, key.namespace, key.name, key.rule)
+	}
+	r.reportedRuleStats = current
+}
+
+// updateRuleStats reports the rule statistics in the CR status, unless the CR has been recreated since. The status
+// is only updated when the statistics change, the collected time alone doesn't trigger the update.
+func (r *SecurityPolicyReconciler) updateRuleStats(ctx context.Context, key types.NamespacedName, uid types.UID, stats []v1alpha1.RuleStats) {
+	unused := 0
+	for _, stat := range stats {
+		if stat.HitCount == 0 {
+			unused++
+		}
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := &v1alpha1.SecurityPolicy{}
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if obj.UID != uid {
+			return nil
+		}
+		if existing := obj.Status.RuleStats; existing != nil && existing.UnusedRules == unused && reflect.DeepEqual(existing.Rules, stats) {
+			return nil
+		}
+		obj.Status.RuleStats = &v1alpha1.RuleStatsSummary{CollectedTime: metav1.Now(), UnusedRules: unused, Rules: stats}
+		return r.Client.Status().Update(ctx, obj)
+	})
+	if err != nil {
+		log.Error(err, "failed to update rule statistics", "securitypolicy", key)
+	}
+}
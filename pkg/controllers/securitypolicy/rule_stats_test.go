@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestSecurityPolicyReconciler_updateRuleStats(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sp).WithStatusSubresource(sp).Build()
+	r := &SecurityPolicyReconciler{Client: k8sClient}
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp1"}
+	getRuleStats := func() *v1alpha1.RuleStatsSummary {
+		obj := &v1alpha1.SecurityPolicy{}
+		assert.NoError(t, k8sClient.Get(ctx, key, obj))
+		return obj.Status.RuleStats
+	}
+
+	stats := []v1alpha1.RuleStats{{Name: "allow-web", Index: 0, HitCount: 6, ByteCount: 600}, {Name: "drop-all", Index: 1}}
+	r.updateRuleStats(ctx, key, "uid1", stats)
+	summary := getRuleStats()
+	assert.Equal(t, 1, summary.UnusedRules)
+	assert.Equal(t, stats, summary.Rules)
+
+	// the unchanged statistics are not updated
+	collected := summary.CollectedTime
+	r.updateRuleStats(ctx, key, "uid1", stats)
+	assert.Equal(t, collected, getRuleStats().CollectedTime)
+
+	// the recreated CR is not updated
+	r.updateRuleStats(ctx, key, "uid0", nil)
+	assert.Equal(t, stats, getRuleStats().Rules)
+}
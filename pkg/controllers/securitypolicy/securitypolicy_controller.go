@@ -9,9 +9,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -24,7 +26,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 
@@ -34,6 +41,7 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	_ "github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
 	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/realizestate"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
@@ -47,18 +55,81 @@ var (
 	MetricResType           = common.MetricResTypeSecurityPolicy
 )
 
+// resyncQueueSize is the count of the SecurityPolicies resynced on demand buffered before they are enqueued.
+const resyncQueueSize = 100
+
 // SecurityPolicyReconciler SecurityPolicyReconcile reconciles a SecurityPolicy object
 type SecurityPolicyReconciler struct {
-	Client   client.Client
-	Scheme   *apimachineryruntime.Scheme
-	Service  *securitypolicy.SecurityPolicyService
-	Recorder record.EventRecorder
+	Client  client.Client
+	Scheme  *apimachineryruntime.Scheme
+	Service *securitypolicy.SecurityPolicyService
+	// SiteServices are the services of the additional NSX sites, the SecurityPolicy is realized
+	// on the site selected by the nsx.vmware.com/nsx_site annotation of the CR or its Namespace.
+	SiteServices map[string]*securitypolicy.SecurityPolicyService
+	Recorder     record.EventRecorder
+	Tracker      *common.ReconcileTracker
+	// History keeps the last sync attempts of each SecurityPolicy, they're summarized in the status.
+	History   *common.SyncHistory
+	Coalescer *common.ReconcileCoalescer
+	// Dampener batches the Pod events updating the IP address groups of the SecurityPolicies.
+	Dampener *common.GroupUpdateDampener
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *common.WarmupGate
+	// Tiers defers the reconciles in the new namespaces until the resources of the lower tiers are realized.
+	Tiers *common.TierGate
+	// Latency measures the time from the spec changes to their realization confirmed by NSX.
+	Latency *common.RealizationLatency
+	// Backuper backs up the SecurityPolicies before they are deleted in bulk, it's nil if the backup is disabled.
+	Backuper *Backuper
+	// DeletionGuard pauses the garbage collection deleting too many SecurityPolicies until it's confirmed.
+	DeletionGuard *common.DeletionGuard
+	// StuckDeletion escalates the SecurityPolicies stuck deleting due to NSX errors.
+	StuckDeletion *common.StuckDeletionPolicy
+	// Protector keeps the NSX resources of the SecurityPolicies protected by the ProtectedPolicies.
+	Protector *PolicyProtector
+	// Realization reports the realization of the NSX resources in the Realized condition of the CRs.
+	Realization *RealizationReporter
+	// reportedRuleCounts are the keys of the rule metrics last reported.
+	reportedRuleCounts sets.Set[securitypolicy.RuleCountKey]
+	// reportedRuleStats are the keys of the rule statistics metrics last reported.
+	reportedRuleStats sets.Set[ruleStatsKey]
+	// resync enqueues the SecurityPolicies resynced on demand.
+	resync chan event.GenericEvent
+	// gcLock serializes the periodic and the on-demand garbage collections.
+	gcLock sync.Mutex
+	// lastOrphanScan is the last time NSX is scanned for the objects missing in the stores.
+	lastOrphanScan time.Time
+}
+
+// defaultOrphanScanInterval is the interval of the scans of NSX for the orphan objects, the stores are
+// complete after they're initialized, they only miss the objects whose creation is not recorded.
+const defaultOrphanScanInterval = 30 * time.Minute
+
+// serviceFor returns the service of the NSX site which the SecurityPolicy targets.
+func (r *SecurityPolicyReconciler) serviceFor(obj *v1alpha1.SecurityPolicy) (*securitypolicy.SecurityPolicyService, error) {
+	if len(r.SiteServices) == 0 {
+		return r.Service, nil
+	}
+	site, err := common.GetNSXSite(r.Client, obj)
+	if err != nil {
+		return nil, err
+	}
+	if site == "" {
+		return r.Service, nil
+	}
+	service, ok := r.SiteServices[site]
+	if !ok {
+		return nil, fmt.Errorf("NSX site %s is not configured", site)
+	}
+	return service, nil
 }
 
 func updateFail(r *SecurityPolicyReconciler, c *context.Context, o *v1alpha1.SecurityPolicy, e *error) {
+	r.History.Failed(types.NamespacedName{Namespace: o.Namespace, Name: o.Name}, *e)
 	r.setSecurityPolicyReadyStatusFalse(c, o, metav1.Now(), e)
-	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, common.ErrorMessage(*e))
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+	common.RecordNSXAPIError(r.Service.NSXConfig, MetricResType, *e)
 }
 
 func k8sClient(mgr ctrl.Manager) client.Client {
@@ -70,35 +141,66 @@ func k8sClient(mgr ctrl.Manager) client.Client {
 }
 
 func deleteFail(r *SecurityPolicyReconciler, c *context.Context, o *v1alpha1.SecurityPolicy, e *error) {
+	r.History.Failed(types.NamespacedName{Namespace: o.Namespace, Name: o.Name}, *e)
 	r.setSecurityPolicyReadyStatusFalse(c, o, metav1.Now(), e)
-	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, common.ErrorMessage(*e))
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+	common.RecordNSXAPIError(r.Service.NSXConfig, MetricResType, *e)
 }
 
 func updateSuccess(r *SecurityPolicyReconciler, c *context.Context, o *v1alpha1.SecurityPolicy) {
+	r.History.Succeeded(types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
 	r.setSecurityPolicyReadyStatusTrue(c, o, metav1.Now())
 	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "SecurityPolicy CR has been successfully updated")
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
 }
 
 func deleteSuccess(r *SecurityPolicyReconciler, _ *context.Context, o *v1alpha1.SecurityPolicy) {
+	r.History.Forget(types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
 	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "SecurityPolicy CR has been successfully deleted")
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
 }
 
 func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", "securitypolicy", req.NamespacedName)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "securitypolicy", req.NamespacedName)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	if wait := r.Coalescer.Defer(req.NamespacedName); wait > 0 {
+		log.V(1).Info("coalescing successive updates", "securitypolicy", req.NamespacedName, "after", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
 	obj := &v1alpha1.SecurityPolicy{}
 	log.Info("reconciling securitypolicy CR", "securitypolicy", req.NamespacedName)
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+	synced := false
+	r.Tracker.Started(req.NamespacedName)
+	r.History.Started(req.NamespacedName)
+	defer func() { r.Tracker.Done(req.NamespacedName, synced) }()
 
 	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
 		log.Error(err, "unable to fetch security policy CR", "req", req.NamespacedName)
+		synced = apierrors.IsNotFound(err)
+		if synced {
+			r.Dampener.Forget(req.NamespacedName)
+		}
 		return ResultNormal, client.IgnoreNotFound(err)
 	}
 
+	service, err := r.serviceFor(obj)
+	if err != nil {
+		log.Error(err, "failed to get NSX site", "securitypolicy", req.NamespacedName)
+		updateFail(r, &ctx, obj, &err)
+		return ResultRequeueAfter5mins, nil
+	}
+
 	// Since SecurityPolicy service can only be activated from NSX 3.2.0 onwards,
 	// So need to check NSX version before starting SecurityPolicy reconcile
-	if !r.Service.NSXClient.NSXCheckVersion(nsx.SecurityPolicy) {
+	if !service.NSXClient.NSXCheckVersion(nsx.SecurityPolicy) {
 		err := errors.New("NSX version check failed, SecurityPolicy feature is not supported")
 		updateFail(r, &ctx, obj, &err)
 		// if NSX version check fails, it will be put back to reconcile queue and be reconciled after 5 minutes
@@ -106,7 +208,13 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		if wait := r.Tiers.Defer(ctx, req.Namespace); wait > 0 {
+			log.V(1).Info("waiting for the lower tiers of the new namespace", "securitypolicy", req.NamespacedName, "after", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		// the NSX resources of the previous CRs of the same namespace and name are looked up on the first sync
+		created := false
 		if !controllerutil.ContainsFinalizer(obj, servicecommon.SecurityPolicyFinalizerName) {
 			controllerutil.AddFinalizer(obj, servicecommon.SecurityPolicyFinalizerName)
 			if err := r.Client.Update(ctx, obj); err != nil {
@@ -115,6 +223,7 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				return ResultRequeue, err
 			}
 			log.V(1).Info("added finalizer on securitypolicy CR", "securitypolicy", req.NamespacedName)
+			created = true
 		}
 
 		if isCRInSysNs, err := util.IsSystemNamespace(r.Client, req.Namespace, nil); err != nil {
@@ -129,7 +238,48 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ResultNormal, nil
 		}
 
-		if err := r.Service.CreateOrUpdateSecurityPolicy(obj); err != nil {
+		if _, ok := obj.Annotations[servicecommon.AnnotationSimulateHours]; ok {
+			if err := r.simulate(ctx, service, obj, service.FlowSource()); err != nil {
+				log.Error(err, "simulation failed, would retry exponentially", "securitypolicy", req.NamespacedName)
+				return ResultRequeue, err
+			}
+			synced = true
+			return ResultNormal, nil
+		}
+		if isDryRun(obj) {
+			if err := r.dryRun(ctx, service, obj); err != nil {
+				log.Error(err, "dry run failed, would retry exponentially", "securitypolicy", req.NamespacedName)
+				return ResultRequeue, err
+			}
+			synced = true
+			return ResultNormal, nil
+		}
+
+		realized := realizedObject(service, obj)
+		protected, err := r.Protector.IsProtected(ctx, obj)
+		if err != nil {
+			log.Error(err, "failed to check protection, would retry exponentially", "securitypolicy", req.NamespacedName)
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		} else if protected && service.IsRealizedFor(realized.UID, obj.Namespace, obj.Name) {
+			// the protected NSX resources are kept as realized until the protection is lifted
+			log.Info("skip realizing the changes of protected securitypolicy CR", "securitypolicy", req.NamespacedName)
+			r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonPolicyProtected, "the changes are not realized until the ProtectedPolicy is lifted")
+			synced = true
+			return ResultNormal, nil
+		}
+		if err := r.checkPriorityConflict(ctx, service, obj, realized); err != nil {
+			log.Error(err, "failed to check priority conflicts, would retry exponentially", "securitypolicy", req.NamespacedName)
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		if err := service.CreateOrUpdateSecurityPolicy(realized); err != nil {
+			if errors.As(err, &nsxutil.OwnershipConflictError{}) {
+				// the NSX resources may be released by the other owner once its lease expires
+				r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonOwnershipConflict, err.Error())
+				updateFail(r, &ctx, obj, &err)
+				return ResultRequeueAfter5mins, nil
+			}
 			if errors.As(err, &nsxutil.RestrictionError{}) {
 				log.Error(err, err.Error(), "securitypolicy", req.NamespacedName)
 				updateFail(r, &ctx, obj, &err)
@@ -159,15 +309,53 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			updateFail(r, &ctx, obj, &err)
 			return ResultRequeue, err
 		}
+		if created && !protected {
+			r.deleteSupersededPolicies(ctx, service, realized, obj)
+		}
+		r.updateRuleBudgets(&ctx, obj, service.GetRuleBudgets(realized.UID))
+		r.updateLintWarnings(&ctx, obj, securitypolicy.LintSecurityPolicy(obj))
+		diff := service.TakeSyncDiff(realized.UID)
+		if diff != nil {
+			// the event keeps a change history of the CR for auditing
+			r.Recorder.Event(obj, v1.EventTypeNormal, common.ReasonNSXResourcesChanged, fmt.Sprintf("generation %d: %s", obj.Generation, diff))
+		}
+		r.watchRealization(service, realized, obj)
+		// the dry run is obsolete once the SecurityPolicy is realized, it's cleared along with the conditions
+		obj.Status.DryRun = nil
 		updateSuccess(r, &ctx, obj)
+		r.Realization.Report(ctx, service, realized, obj, diff != nil)
+		synced = true
 	} else {
 		if controllerutil.ContainsFinalizer(obj, servicecommon.SecurityPolicyFinalizerName) {
 			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
-			if err := r.Service.DeleteSecurityPolicy(obj, false, servicecommon.ResourceTypeSecurityPolicy); err != nil {
-				log.Error(err, "deletion failed, would retry exponentially", "securitypolicy", req.NamespacedName)
+			if err := r.Backuper.BackupNamespace(ctx, service, obj.Namespace); err != nil {
+				log.Error(err, "failed to back up SecurityPolicies of the terminating namespace, would retry exponentially", "securitypolicy", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			protected, err := r.Protector.IsProtected(ctx, obj)
+			if err != nil {
+				log.Error(err, "failed to check protection, would retry exponentially", "securitypolicy", req.NamespacedName)
 				deleteFail(r, &ctx, obj, &err)
 				return ResultRequeue, err
 			}
+			if protected {
+				// the protected NSX resources are left to the garbage collection once the protection is lifted
+				log.Info("skip deleting the NSX resources of protected securitypolicy CR", "securitypolicy", req.NamespacedName)
+				r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonPolicyProtected, "the NSX resources are kept until the ProtectedPolicy is lifted")
+			} else if err := service.DeleteSecurityPolicy(realizedObject(service, obj), false, servicecommon.ResourceTypeSecurityPolicy); errors.As(err, &nsxutil.OwnershipConflictError{}) {
+				// the NSX resources owned by another controller are left to it, only the CR is released
+				log.Info("skip deleting the NSX resources owned by another controller", "securitypolicy", req.NamespacedName, "reason", err.Error())
+				r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonOwnershipConflict, err.Error())
+			} else if err != nil {
+				if !r.StuckDeletion.Escalate(ctx, obj, err) {
+					log.Error(err, "deletion failed, would retry exponentially", "securitypolicy", req.NamespacedName)
+					deleteFail(r, &ctx, obj, &err)
+					return ResultRequeue, err
+				}
+				// the NSX resources left are retried by the garbage collection once the CR is gone
+				log.Info("deletion stuck, removing finalizer", "securitypolicy", req.NamespacedName, "reason", err.Error())
+			}
 			controllerutil.RemoveFinalizer(obj, servicecommon.SecurityPolicyFinalizerName)
 			if err := r.Client.Update(ctx, obj); err != nil {
 				log.Error(err, "deletion failed, would retry exponentially", "securitypolicy", req.NamespacedName)
@@ -175,7 +363,10 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				return ResultRequeue, err
 			}
 			log.V(1).Info("removed finalizer", "securitypolicy", req.NamespacedName)
+			r.Latency.Forget(obj.UID)
+			r.StuckDeletion.Forget(obj.UID)
 			deleteSuccess(r, &ctx, obj)
+			synced = true
 		} else {
 			// only print a message because it's not a normal case
 			log.Info("finalizers cannot be recognized", "securitypolicy", req.NamespacedName)
@@ -185,6 +376,108 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ResultNormal, nil
 }
 
+// watchRealization observes the realization latency of the CR once the realization of the NSX SecurityPolicy
+// is confirmed by NSX, the realization is only checked in VPC.
+func (r *SecurityPolicyReconciler) watchRealization(service *securitypolicy.SecurityPolicyService, realized, obj *v1alpha1.SecurityPolicy) {
+	if r.Latency == nil {
+		return
+	}
+	path, ok := service.RealizationPath(realized)
+	if !ok {
+		return
+	}
+	uid, generation := obj.UID, obj.Generation
+	realizestate.GetWatcherPool(service.Service).Watch(path, securitypolicy.RealizedEntityType, func(_, _ string, err error) {
+		// the failures are logged by the watcher, the change is observed once realized by a later reconcile
+		if err == nil {
+			r.Latency.Realized(uid, generation)
+		}
+	})
+}
+
+// realizedObject returns the CR to realize on NSX. If the CR is annotated with the UID of a previous CR by
+// the restore, it carries that UID, so the NSX resources realized for the previous CR are adopted instead
+// of recreated. The annotation is ignored if the NSX resources were not realized for the same namespace
+// and name.
+func realizedObject(service *securitypolicy.SecurityPolicyService, obj *v1alpha1.SecurityPolicy) *v1alpha1.SecurityPolicy {
+	uid, ok := obj.Annotations[servicecommon.AnnotationRealizedUID]
+	if !ok || types.UID(uid) == obj.UID {
+		return obj
+	}
+	if !service.IsRealizedFor(types.UID(uid), obj.Namespace, obj.Name) {
+		log.Info("NSX resources of the realized UID not found for the CR, ignore the annotation", "securitypolicy",
+			types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, "realizedUID", uid)
+		return obj
+	}
+	realized := obj.DeepCopy()
+	realized.UID = types.UID(uid)
+	return realized
+}
+
+// deleteSupersededPolicies deletes the NSX resources realized for the previous CRs of the same namespace and
+// name, which were deleted and recreated with a new UID while the deletion was missed. They are deleted once
+// the new CR is realized, so the enforcement isn't interrupted, instead of being left to the garbage collection.
+func (r *SecurityPolicyReconciler) deleteSupersededPolicies(ctx context.Context, service *securitypolicy.SecurityPolicyService, realized, obj *v1alpha1.SecurityPolicy) {
+	uids := service.SupersededUIDs(realized)
+	if len(uids) == 0 {
+		return
+	}
+	staleUIDs := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		staleUIDs = append(staleUIDs, string(uid))
+	}
+	key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+	if err := r.Backuper.Backup(ctx, service, BackupReasonSuperseded, nil, staleUIDs); err != nil {
+		log.Error(err, "failed to back up superseded SecurityPolicies, left to GC", "securitypolicy", key)
+		return
+	}
+	for _, uid := range uids {
+		if err := service.DeleteSecurityPolicy(uid, false, servicecommon.ResourceTypeSecurityPolicy); err != nil {
+			log.Error(err, "failed to delete superseded SecurityPolicy, left to GC", "securitypolicy", key, "UID", uid)
+			continue
+		}
+		log.Info("deleted superseded SecurityPolicy", "securitypolicy", key, "UID", uid)
+		r.Recorder.Event(obj, v1.EventTypeNormal, common.ReasonSupersededDeleted,
+			fmt.Sprintf("deleted the NSX resources realized for the previous CR %s", uid))
+	}
+}
+
+// updateRuleBudgets reports the NSX objects generated for each rule in the CR status, and warns
+// about the rules approaching NSX scale limits.
+func (r *SecurityPolicyReconciler) updateRuleBudgets(ctx *context.Context, secPolicy *v1alpha1.SecurityPolicy, budgets []v1alpha1.RuleBudget) {
+	for _, budget := range budgets {
+		if budget.Warning != "" {
+			r.Recorder.Event(secPolicy, v1.EventTypeWarning, common.ReasonApproachingLimit,
+				fmt.Sprintf("rule %d %s is approaching NSX scale limits: %s", budget.Index, budget.Name, budget.Warning))
+		}
+	}
+	if reflect.DeepEqual(secPolicy.Status.RuleBudgets, budgets) {
+		return
+	}
+	secPolicy.Status.RuleBudgets = budgets
+	if err := r.Client.Status().Update(*ctx, secPolicy); err != nil {
+		log.Error(err, "failed to update rule budgets", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace)
+		return
+	}
+	log.V(1).Info("updated SecurityPolicy rule budgets", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace,
+		"RuleBudgets", budgets)
+}
+
+// updateLintWarnings reports the lint findings of the SecurityPolicy in the CR status, they're returned on
+// admission too but the status keeps them visible after the SecurityPolicy is applied.
+func (r *SecurityPolicyReconciler) updateLintWarnings(ctx *context.Context, secPolicy *v1alpha1.SecurityPolicy, warnings []string) {
+	if reflect.DeepEqual(secPolicy.Status.LintWarnings, warnings) {
+		return
+	}
+	secPolicy.Status.LintWarnings = warnings
+	if err := r.Client.Status().Update(*ctx, secPolicy); err != nil {
+		log.Error(err, "failed to update lint warnings", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace)
+		return
+	}
+	log.V(1).Info("updated SecurityPolicy lint warnings", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace,
+		"LintWarnings", warnings)
+}
+
 func (r *SecurityPolicyReconciler) setSecurityPolicyReadyStatusTrue(ctx *context.Context, secPolicy *v1alpha1.SecurityPolicy, transitionTime metav1.Time) {
 	newConditions := []v1alpha1.Condition{
 		{
@@ -205,8 +498,8 @@ func (r *SecurityPolicyReconciler) setSecurityPolicyReadyStatusFalse(ctx *contex
 			Status:  v1.ConditionFalse,
 			Message: "NSX Security Policy could not be created/updated",
 			Reason: fmt.Sprintf(
-				"error occurred while processing the SecurityPolicy CR. Error: %v",
-				*err,
+				"error occurred while processing the SecurityPolicy CR. Error: %s",
+				common.ErrorMessage(*err),
 			),
 			LastTransitionTime: transitionTime,
 		},
@@ -221,6 +514,16 @@ func (r *SecurityPolicyReconciler) updateSecurityPolicyStatusConditions(ctx *con
 			conditionsUpdated = true
 		}
 	}
+	// the summary of the sync history is updated along with the conditions
+	summary := r.History.Summary(types.NamespacedName{Namespace: secPolicy.Namespace, Name: secPolicy.Name})
+	if summary != nil && !common.SyncSummaryEqual(secPolicy.Status.SyncSummary, summary) {
+		if summary.Flapping && (secPolicy.Status.SyncSummary == nil || !secPolicy.Status.SyncSummary.Flapping) {
+			r.Recorder.Event(secPolicy, v1.EventTypeWarning, common.ReasonSyncFlapping,
+				fmt.Sprintf("%d of the last %d syncs failed, last error: %s", summary.Failures, summary.Attempts, summary.LastError))
+		}
+		secPolicy.Status.SyncSummary = summary
+		conditionsUpdated = true
+	}
 	if conditionsUpdated {
 		r.Client.Status().Update(*ctx, secPolicy)
 		log.V(1).Info("updated SecurityPolicy", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace,
@@ -256,8 +559,14 @@ func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingCo
 }
 
 func (r *SecurityPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.SecurityPolicy{}).
+	r.resync = make(chan event.GenericEvent, resyncQueueSize)
+	b := ctrl.NewControllerManagedBy(mgr).
+		// The status updates of the reconciler don't trigger another reconcile, only the changes of the spec,
+		// labels and annotations do.
+		For(&v1alpha1.SecurityPolicy{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+			r.Tracker.Predicate(), r.Coalescer.Predicate(), r.Latency.Predicate(),
+		)).
 		WithOptions(
 			controller.Options{
 				MaxConcurrentReconciles: common.NumReconcile(),
@@ -269,10 +578,39 @@ func (r *SecurityPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
 		).
 		Watches(
 			&v1.Pod{},
-			&EnqueueRequestForPod{Client: k8sClient(mgr)},
+			&EnqueueRequestForPod{Client: k8sClient(mgr), Dampener: r.Dampener},
 			builder.WithPredicates(PredicateFuncsPod),
 		).
-		Complete(r)
+		Watches(
+			&v1.Pod{},
+			&EnqueueRequestForWorkloadPod{Client: k8sClient(mgr), Dampener: r.Dampener},
+			builder.WithPredicates(PredicateFuncsWorkloadPod),
+		).
+		Watches(
+			&v1.Service{},
+			&EnqueueRequestForDNSService{Client: k8sClient(mgr)},
+			builder.WithPredicates(predicateFuncsDNSService(r.Service.DNSService())),
+		).
+		Watches(
+			&v1.Service{},
+			&EnqueueRequestForLBService{Client: k8sClient(mgr)},
+			builder.WithPredicates(common.PredicateFuncsLBService),
+		).
+		Watches(
+			&v1.Service{},
+			&EnqueueRequestForExternalService{Client: k8sClient(mgr)},
+			builder.WithPredicates(PredicateFuncsExternalService),
+		).
+		Watches(
+			&v1.Endpoints{},
+			&EnqueueRequestForExternalService{Client: k8sClient(mgr)},
+			builder.WithPredicates(PredicateFuncsExternalService),
+		).
+		WatchesRawSource(&source.Channel{Source: r.resync}, &handler.EnqueueRequestForObject{})
+	if r.Protector != nil {
+		b = b.Watches(&v1alpha1.ProtectedPolicy{}, handler.EnqueueRequestsFromMapFunc(protectedPolicyMapFunc))
+	}
+	return b.Complete(r)
 }
 
 // Start setup manager and launch GC
@@ -281,8 +619,27 @@ func (r *SecurityPolicyReconciler) Start(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	r.Warmup = common.NewWarmupGate(MetricResType, r.Service.NSXConfig, mgr.GetCache(), &v1alpha1.SecurityPolicy{}, &v1.Namespace{}, &v1.Pod{})
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+	r.Tiers = common.NewTierGate(common.TierPolicy, MetricResType, mgr.GetClient(), r.Service.NSXConfig)
+	common.RegisterTierCheck(common.TierPolicy, MetricResType, common.ReadyConditionPending(mgr.GetClient(),
+		func() client.ObjectList { return &v1alpha1.SecurityPolicyList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.SecurityPolicy).Status.Conditions }))
 
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	go r.Tracker.RunReporter(make(chan bool), common.ReconcileReportInterval)
+	go r.RuleMetricsReporter(make(chan bool), common.ObjectCountReportInterval)
+	if interval := r.driftCheckInterval(); interval > 0 {
+		go r.DriftDetector(make(chan bool), interval)
+	}
+	if interval := r.ruleStatsInterval(); interval > 0 {
+		go r.RuleStatsCollector(make(chan bool), interval)
+	}
+	if interval := r.groupMembersInterval(); interval > 0 {
+		go r.GroupMembersCollector(make(chan bool), interval)
+	}
 	return nil
 }
 
@@ -291,40 +648,167 @@ func (r *SecurityPolicyReconciler) Start(mgr ctrl.Manager) error {
 func (r *SecurityPolicyReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
 	ctx := context.Background()
 	log.Info("garbage collector started")
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	r.lastOrphanScan = time.Now()
 	for {
 		select {
 		case <-cancel:
 			return
 		case <-time.After(timeout):
 		}
-		nsxPolicySet := r.Service.ListSecurityPolicyID()
-		if len(nsxPolicySet) == 0 {
+		if common.InNSXMaintenance() {
 			continue
 		}
-		policyList := &v1alpha1.SecurityPolicyList{}
-		err := r.Client.List(ctx, policyList)
+		r.scanOrphans()
+		if err := r.CollectGarbage(ctx); err != nil {
+			log.Error(err, "failed to collect garbage of SecurityPolicy")
+		}
+	}
+}
+
+// CollectGarbage deletes the SecurityPolicies on all NSX sites whose CR has been removed, it's run
+// periodically by GarbageCollector, and on demand by the admin API. The deletion is paused by the
+// DeletionGuard if too many SecurityPolicies are collected.
+func (r *SecurityPolicyReconciler) CollectGarbage(ctx context.Context) error {
+	r.gcLock.Lock()
+	defer r.gcLock.Unlock()
+	nsxPolicySets := map[string]sets.Set[string]{"": r.Service.ListSecurityPolicyID()}
+	count := len(nsxPolicySets[""])
+	for site, service := range r.SiteServices {
+		nsxPolicySets[site] = service.ListSecurityPolicyID()
+		count += len(nsxPolicySets[site])
+	}
+	if count == 0 {
+		return nil
+	}
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, policyList); err != nil {
+		return err
+	}
+	protected, err := r.Protector.Protected(ctx)
+	if err != nil {
+		return err
+	}
+	staleUIDs := map[string][]string{"": skipProtected(r.Service, r.staleSecurityPolicies("", r.Service, nsxPolicySets[""], policyList), protected)}
+	stale := len(staleUIDs[""])
+	for site, service := range r.SiteServices {
+		staleUIDs[site] = skipProtected(service, r.staleSecurityPolicies(site, service, nsxPolicySets[site], policyList), protected)
+		stale += len(staleUIDs[site])
+	}
+	if err := r.DeletionGuard.Allow(ctx, stale, count); err != nil {
+		return err
+	}
+	r.collectGarbage(ctx, "", r.Service, staleUIDs[""])
+	for site, service := range r.SiteServices {
+		r.collectGarbage(ctx, site, service, staleUIDs[site])
+	}
+	return nil
+}
+
+func (r *SecurityPolicyReconciler) orphanScanInterval() time.Duration {
+	if r.Service.NSXConfig != nil && r.Service.NSXConfig.K8sConfig != nil && r.Service.NSXConfig.OrphanScanInterval != 0 {
+		return time.Duration(r.Service.NSXConfig.OrphanScanInterval) * time.Second
+	}
+	return defaultOrphanScanInterval
+}
+
+// scanOrphans scans NSX for the objects of the SecurityPolicies missing in the stores on all NSX sites when
+// it's due, e.g. the ones left on NSX while nsx-operator was down or exited before recording them, the
+// following garbage collection deletes the ones whose CR is gone.
+func (r *SecurityPolicyReconciler) scanOrphans() {
+	interval := r.orphanScanInterval()
+	if interval < 0 || time.Since(r.lastOrphanScan) < interval {
+		return
+	}
+	r.lastOrphanScan = time.Now()
+	services := map[string]*securitypolicy.SecurityPolicyService{"": r.Service}
+	for site, service := range r.SiteServices {
+		services[site] = service
+	}
+	for site, service := range services {
+		found, err := service.ScanOrphans()
 		if err != nil {
-			log.Error(err, "failed to list SecurityPolicy CR")
+			log.Error(err, "failed to scan NSX for orphan objects", "site", site)
 			continue
 		}
+		if found > 0 {
+			log.Info("found NSX objects missing in store, collecting the ones whose CR is gone", "count", found, "site", site)
+		}
+	}
+}
 
-		CRPolicySet := sets.NewString()
-		for _, policy := range policyList.Items {
-			CRPolicySet.Insert(string(policy.UID))
+// Resync enqueues the SecurityPolicies to reconcile them again, all the SecurityPolicies in the
+// namespace if name is empty, and all the SecurityPolicies if namespace is empty too. It returns the
+// count of the SecurityPolicies enqueued.
+func (r *SecurityPolicyReconciler) Resync(ctx context.Context, namespace, name string) (int, error) {
+	if r.resync == nil {
+		return 0, errors.New("SecurityPolicy controller is not started")
+	}
+	var policies []v1alpha1.SecurityPolicy
+	if name != "" {
+		policy := &v1alpha1.SecurityPolicy{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, policy); err != nil {
+			return 0, err
 		}
+		policies = append(policies, *policy)
+	} else {
+		policyList := &v1alpha1.SecurityPolicyList{}
+		if err := r.Client.List(ctx, policyList, client.InNamespace(namespace)); err != nil {
+			return 0, err
+		}
+		policies = policyList.Items
+	}
+	for i := range policies {
+		select {
+		case r.resync <- event.GenericEvent{Object: &policies[i]}:
+		case <-ctx.Done():
+			return i, ctx.Err()
+		}
+	}
+	return len(policies), nil
+}
+
+// staleSecurityPolicies returns the UIDs of the SecurityPolicies on the NSX site whose CR is removed or
+// moved to another site.
+func (r *SecurityPolicyReconciler) staleSecurityPolicies(site string, service *securitypolicy.SecurityPolicyService, nsxPolicySet sets.Set[string], policyList *v1alpha1.SecurityPolicyList) []string {
+	if len(nsxPolicySet) == 0 {
+		return nil
+	}
 
-		for elem := range nsxPolicySet {
-			if CRPolicySet.Has(elem) {
+	CRPolicySet := sets.New[string]()
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		if len(r.SiteServices) > 0 {
+			policySite, err := common.GetNSXSite(r.Client, policy)
+			// keep the SecurityPolicy if the site is unknown
+			if err == nil && policySite != site {
 				continue
 			}
-			log.V(1).Info("GC collected SecurityPolicy CR", "UID", elem)
-			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
-			err = r.Service.DeleteSecurityPolicy(types.UID(elem), false, servicecommon.ResourceTypeSecurityPolicy)
-			if err != nil {
-				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
-			} else {
-				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
-			}
+		}
+		CRPolicySet.Insert(string(realizedObject(service, policy).UID))
+	}
+	return sets.List(nsxPolicySet.Difference(CRPolicySet))
+}
+
+// collectGarbage deletes the stale SecurityPolicies on the NSX site.
+func (r *SecurityPolicyReconciler) collectGarbage(ctx context.Context, site string, service *securitypolicy.SecurityPolicyService, staleUIDs []string) {
+	if len(staleUIDs) == 0 {
+		return
+	}
+	if err := r.Backuper.Backup(ctx, service, BackupReasonGC, nil, staleUIDs); err != nil {
+		log.Error(err, "failed to back up SecurityPolicies collected by GC, skip deleting them", "site", site)
+		return
+	}
+	for _, elem := range staleUIDs {
+		log.V(1).Info("GC collected SecurityPolicy CR", "UID", elem, "site", site)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		err := service.DeleteSecurityPolicy(types.UID(elem), false, servicecommon.ResourceTypeSecurityPolicy)
+		if err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+		} else {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
 		}
 	}
 }
@@ -369,15 +853,53 @@ func reconcileSecurityPolicy(client client.Client, pods []v1.Pod, q workqueue.Ra
 	return nil
 }
 
-func StartSecurityPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+func StartSecurityPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider, siteClients *nsx.SiteClients,
+	backuper *Backuper, deletionGuard *common.DeletionGuard, stuckDeletion *common.StuckDeletionPolicy, enableWebhook bool) *SecurityPolicyReconciler {
 	securityPolicyReconcile := SecurityPolicyReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("securitypolicy-controller"),
 	}
 	securityPolicyReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
+	securityPolicyReconcile.SiteServices = make(map[string]*securitypolicy.SecurityPolicyService)
+	for _, site := range siteClients.Sites() {
+		siteClient, _ := siteClients.Get(site)
+		siteService := servicecommon.Service{
+			Client:    commonService.Client,
+			NSXClient: siteClient,
+			NSXConfig: siteClient.NsxConfig,
+		}
+		service, err := securitypolicy.InitializeSecurityPolicy(siteService, vpcService)
+		if err != nil {
+			log.Error(err, "failed to initialize SecurityPolicy service", "site", site)
+			os.Exit(1)
+		}
+		securityPolicyReconcile.SiteServices[site] = service
+	}
+	securityPolicyReconcile.Tracker = common.NewReconcileTracker(MetricResType, commonService.NSXConfig)
+	securityPolicyReconcile.History = common.NewSyncHistory(common.DefaultSyncHistorySize)
+	securityPolicyReconcile.Coalescer = common.NewReconcileCoalescer(MetricResType, commonService.NSXConfig)
+	securityPolicyReconcile.Dampener = common.NewGroupUpdateDampener(MetricResType, commonService.NSXConfig)
+	securityPolicyReconcile.Latency = common.NewRealizationLatency(MetricResType, commonService.NSXConfig)
+	securityPolicyReconcile.Backuper = backuper
+	securityPolicyReconcile.DeletionGuard = deletionGuard
+	securityPolicyReconcile.StuckDeletion = stuckDeletion
+	securityPolicyReconcile.Protector = &PolicyProtector{Client: mgr.GetClient()}
+	securityPolicyReconcile.Realization = &RealizationReporter{Client: mgr.GetClient()}
 	if err := securityPolicyReconcile.Start(mgr); err != nil {
 		log.Error(err, "failed to create controller", "controller", "SecurityPolicy")
 		os.Exit(1)
 	}
+	if enableWebhook {
+		hookServer, err := common.GetWebhookServer(mgr)
+		if err != nil {
+			log.Error(err, "failed to create webhook server", "controller", "SecurityPolicy")
+			os.Exit(1)
+		}
+		hookServer.Register(SecurityPolicyWebhookPath,
+			&webhook.Admission{
+				Handler: &SecurityPolicyValidator{Client: mgr.GetClient(), Service: securityPolicyReconcile.Service},
+			})
+	}
+	return &securityPolicyReconcile
 }
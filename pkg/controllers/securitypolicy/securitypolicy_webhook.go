@@ -0,0 +1,153 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+var securitypolicylog = logf.Log.WithName("securitypolicy-webhook")
+
+const SecurityPolicyWebhookPath = "/validate-nsx-vmware-com-v1alpha1-securitypolicy"
+
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+//+kubebuilder:webhook:path=/validate-nsx-vmware-com-v1alpha1-securitypolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=nsx.vmware.com,resources=securitypolicies,verbs=create;update,versions=v1alpha1,name=default.securitypolicy.validating.nsx.vmware.com,admissionReviewVersions=v1
+
+// SecurityPolicyValidator rejects the SecurityPolicy which can't be realized on NSX, so that the error
+// is returned to the user on admission instead of surfacing during reconcile.
+type SecurityPolicyValidator struct {
+	Client client.Client
+	// Service precomputes the NSX objects the SecurityPolicy expands to, the NSX scale limits are not
+	// checked if it's nil.
+	Service *securitypolicy.SecurityPolicyService
+	decoder *admission.Decoder
+}
+
+// Handle handles admission requests.
+func (v *SecurityPolicyValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	securityPolicy := &v1alpha1.SecurityPolicy{}
+	if err := v.decoder.Decode(req, securityPolicy); err != nil {
+		securitypolicylog.Error(err, "error while decoding SecurityPolicy", "SecurityPolicy", req.Namespace+"/"+req.Name)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// the SecurityPolicy being deleted is not realized again
+	if !securityPolicy.ObjectMeta.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if err := securitypolicy.ValidateRules(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateSelectors(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateAllowDNS(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateRedirect(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateIdentityGroups(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateFQDN(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateAppIDs(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := securitypolicy.ValidateServices(securityPolicy); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if securitypolicy.UsesAppliedToAll(securityPolicy) {
+		allowed, err := v.isAllowedAppliedToAll(ctx, req)
+		if err != nil {
+			securitypolicylog.Error(err, "failed to review access to appliedTo all", "SecurityPolicy", req.Namespace+"/"+req.Name)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return admission.Denied(fmt.Sprintf("user %s is not allowed to %s securitypolicies in namespace %s",
+				req.UserInfo.Username, securitypolicy.AppliedToAllVerb, req.Namespace))
+		}
+	}
+	if v.Service != nil {
+		if err := v.Service.ValidateScaleLimits(securityPolicy); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+	forbiddenRules, err := v.getForbiddenRules(ctx)
+	if err != nil {
+		securitypolicylog.Error(err, "failed to get forbidden rules", "SecurityPolicy", req.Namespace+"/"+req.Name)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if err := securitypolicy.CheckForbiddenRules(securityPolicy, forbiddenRules); err != nil {
+		return admission.Denied(err.Error())
+	}
+	// the lint findings are returned as warnings to the user, they don't block the SecurityPolicy
+	return admission.Allowed("").WithWarnings(securitypolicy.LintSecurityPolicy(securityPolicy)...)
+}
+
+// isAllowedAppliedToAll reviews whether the requesting user is allowed by RBAC to apply the SecurityPolicy
+// to all the workloads of the cluster.
+func (v *SecurityPolicyValidator) isAllowedAppliedToAll(ctx context.Context, req admission.Request) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(req.UserInfo.Extra))
+	for k, value := range req.UserInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      securitypolicy.AppliedToAllVerb,
+				Group:     v1alpha1.GroupVersion.Group,
+				Resource:  "securitypolicies",
+				Name:      req.Name,
+			},
+		},
+	}
+	if err := v.Client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// getForbiddenRules returns the forbidden rules configured in the NSXOperatorConfig CR, the rules
+// are not enforced if the CR doesn't exist.
+func (v *SecurityPolicyValidator) getForbiddenRules(ctx context.Context) ([]v1alpha1.ForbiddenRule, error) {
+	operatorConfig := &v1alpha1.NSXOperatorConfig{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: v1alpha1.NSXOperatorConfigName}, operatorConfig); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return operatorConfig.Spec.ForbiddenRules, nil
+}
+
+// InjectDecoder injects the decoder into a validator.
+// A decoder will be automatically injected by controller-manager.
+func (v *SecurityPolicyValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
@@ -0,0 +1,108 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// simulate reports the observed flows which the SecurityPolicy would block in the CR status instead
+// of realizing it on NSX, the NSX objects realized from a previous spec are kept. The simulation is
+// skipped if the generation and the hours have been simulated.
+func (r *SecurityPolicyReconciler) simulate(ctx context.Context, service *securitypolicy.SecurityPolicyService, obj *v1alpha1.SecurityPolicy, source securitypolicy.FlowSource) error {
+	value := obj.Annotations[servicecommon.AnnotationSimulateHours]
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours <= 0 || hours > securitypolicy.MaxSimulationHours {
+		return r.updateSimulation(ctx, obj, &v1alpha1.SimulationStatus{
+			ObservedGeneration: obj.Generation,
+			Error: fmt.Sprintf("invalid annotation %s: %s, it should be the hours between 1 and %d",
+				servicecommon.AnnotationSimulateHours, value, securitypolicy.MaxSimulationHours),
+		})
+	}
+	if simulation := obj.Status.Simulation; simulation != nil && simulation.Error == "" &&
+		simulation.ObservedGeneration == obj.Generation && simulation.Hours == hours {
+		log.V(1).Info("SecurityPolicy has been simulated", "securitypolicy", obj.Name, "namespace", obj.Namespace)
+		return nil
+	}
+
+	resolve, err := newEndpointResolver(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	status := service.Simulate(obj, hours, source, resolve)
+	if status.BlockedFlowCount > 0 {
+		r.Recorder.Event(obj, v1.EventTypeWarning, common.ReasonFlowsBlocked,
+			fmt.Sprintf("%d of %d flows observed in the last %d hours would be blocked", status.BlockedFlowCount, status.Flows, hours))
+	}
+	return r.updateSimulation(ctx, obj, status)
+}
+
+func (r *SecurityPolicyReconciler) updateSimulation(ctx context.Context, obj *v1alpha1.SecurityPolicy, status *v1alpha1.SimulationStatus) error {
+	obj.Status.Simulation = status
+	if err := r.Client.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "failed to update simulation", "securitypolicy", obj.Name, "namespace", obj.Namespace)
+		return err
+	}
+	log.Info("updated SecurityPolicy simulation", "securitypolicy", obj.Name, "namespace", obj.Namespace,
+		"flows", status.Flows, "blockedFlows", status.BlockedFlowCount, "error", status.Error)
+	return nil
+}
+
+// newEndpointResolver resolves the IPs of the flows or the recommendations to the Pods with their
+// labels and the labels of their Namespaces.
+func newEndpointResolver(ctx context.Context, c client.Client) (securitypolicy.EndpointResolver, error) {
+	nsList := &v1.NamespaceList{}
+	if err := c.List(ctx, nsList); err != nil {
+		return nil, err
+	}
+	nsLabels := make(map[string]map[string]string, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		nsLabels[ns.Name] = ns.Labels
+	}
+	podList := &v1.PodList{}
+	if err := c.List(ctx, podList); err != nil {
+		return nil, err
+	}
+	endpoints := make(map[string]*securitypolicy.SimulationEndpoint)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		endpoint := &securitypolicy.SimulationEndpoint{
+			Namespace:       pod.Namespace,
+			Name:            pod.Name,
+			Labels:          pod.Labels,
+			NamespaceLabels: nsLabels[pod.Namespace],
+			NamedPorts:      map[string]int{},
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name != "" {
+					endpoint.NamedPorts[port.Name] = int(port.ContainerPort)
+				}
+			}
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			endpoints[podIP.IP] = endpoint
+		}
+		for _, ip := range util.GetPodSecondaryIPs(pod) {
+			endpoints[ip] = endpoint
+		}
+	}
+	return func(ip string) *securitypolicy.SimulationEndpoint {
+		return endpoints[ip]
+	}, nil
+}
@@ -0,0 +1,89 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+type fakeFlowSource struct {
+	flows []securitypolicy.FlowRecord
+	calls int
+}
+
+func (s *fakeFlowSource) ListFlows(_, _ time.Time) ([]securitypolicy.FlowRecord, error) {
+	s.calls++
+	return s.flows, nil
+}
+
+func TestSecurityPolicyReconciler_simulate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	drop, in := v1alpha1.RuleActionDrop, v1alpha1.RuleDirectionIn
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: "sp", Generation: 1,
+			Annotations: map[string]string{servicecommon.AnnotationSimulateHours: "12"},
+		},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}}},
+			Rules:     []v1alpha1.SecurityPolicyRule{{Action: &drop, Direction: &in}},
+		},
+	}
+	pods := []runtime.Object{
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db", Labels: map[string]string{"app": "db"}},
+			Status:     v1.PodStatus{PodIPs: []v1.PodIP{{IP: "10.0.0.2"}}},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{PodIPs: []v1.PodIP{{IP: "10.0.0.1"}}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.SecurityPolicy{}).
+		WithObjects(sp).WithRuntimeObjects(pods...).Build()
+	r := &SecurityPolicyReconciler{Client: k8sClient, Scheme: scheme, Recorder: fakeRecorder{}}
+	source := &fakeFlowSource{flows: []securitypolicy.FlowRecord{
+		{SourceIP: "10.0.0.1", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 5432},
+		{SourceIP: "10.0.0.2", DestinationIP: "10.0.0.1", Protocol: v1.ProtocolTCP, DestinationPort: 80},
+	}}
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "ns1", Name: "sp"}
+
+	obj := &v1alpha1.SecurityPolicy{}
+	assert.NoError(t, k8sClient.Get(ctx, key, obj))
+	assert.NoError(t, r.simulate(ctx, &securitypolicy.SecurityPolicyService{}, obj, source))
+	assert.NoError(t, k8sClient.Get(ctx, key, obj))
+	simulation := obj.Status.Simulation
+	assert.Equal(t, 12, simulation.Hours)
+	assert.Equal(t, 2, simulation.Flows)
+	assert.Equal(t, 1, simulation.BlockedFlowCount)
+	assert.Equal(t, []v1alpha1.SimulatedFlow{{Rule: "0", Source: "ns1/web", Destination: "ns1/db", Protocol: v1.ProtocolTCP, Port: 5432}},
+		simulation.BlockedFlows)
+
+	// the simulated generation is skipped
+	assert.NoError(t, r.simulate(ctx, &securitypolicy.SecurityPolicyService{}, obj, source))
+	assert.Equal(t, 1, source.calls)
+
+	obj.Annotations[servicecommon.AnnotationSimulateHours] = "invalid"
+	assert.NoError(t, r.simulate(ctx, &securitypolicy.SecurityPolicyService{}, obj, source))
+	assert.NoError(t, k8sClient.Get(ctx, key, obj))
+	assert.Contains(t, obj.Status.Simulation.Error, "invalid annotation")
+	assert.Equal(t, 1, source.calls)
+}
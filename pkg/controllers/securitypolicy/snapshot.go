@@ -0,0 +1,193 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+const (
+	// SnapshotSecretName is the Secret in the namespace of nsx-operator which keeps the snapshot of the
+	// NSX resources realized for the SecurityPolicy CRs, it is expected to be backed up with the CRs.
+	SnapshotSecretName = "nsx-operator-snapshot"
+	SnapshotKey        = "securitypolicies"
+)
+
+// RealizedResourceLister lists the NSX resources realized for the SecurityPolicy CRs, keyed by CR UID.
+type RealizedResourceLister interface {
+	ListRealizedResources() map[string]*securitypolicy.RealizedResources
+}
+
+// SnapshotEntry maps a SecurityPolicy CR to the NSX resources realized for it.
+type SnapshotEntry struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// UID is the UID the NSX resources are tagged with, it differs from the UID of the CR if the CR
+	// has adopted the NSX resources realized for a previous CR.
+	UID string `json:"uid"`
+	// SpecHash is the hash of the CR spec when the snapshot is taken.
+	SpecHash  string                            `json:"specHash"`
+	Resources *securitypolicy.RealizedResources `json:"resources"`
+}
+
+// Snapshotter keeps the mapping from the SecurityPolicy CRs to the NSX resources realized for them in the
+// snapshot Secret, and restores it on a rebuilt cluster by annotating the recreated CRs with the UID of the
+// previous CRs, so the NSX resources are adopted instead of recreated. Snapshot is added to the manager to
+// only run on the leader, Restore is called before the manager is started.
+type Snapshotter struct {
+	Client  client.Client
+	Reader  client.Reader
+	Service RealizedResourceLister
+	// Namespace is the namespace of the snapshot Secret.
+	Namespace string
+	Interval  time.Duration
+}
+
+// Snapshot saves the NSX resources realized for the SecurityPolicy CRs in the snapshot Secret.
+func (s *Snapshotter) Snapshot(ctx context.Context) error {
+	policyList := &v1alpha1.SecurityPolicyList{}
+	if err := s.Reader.List(ctx, policyList); err != nil {
+		return err
+	}
+	realized := s.Service.ListRealizedResources()
+	entries := []SnapshotEntry{}
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		uid := string(policy.UID)
+		if realizedUID, ok := policy.Annotations[servicecommon.AnnotationRealizedUID]; ok && realized[realizedUID] != nil {
+			uid = realizedUID
+		}
+		resources, ok := realized[uid]
+		if !ok {
+			continue
+		}
+		specHash, err := hashSpec(&policy.Spec)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, SnapshotEntry{
+			Namespace: policy.Namespace,
+			Name:      policy.Name,
+			UID:       uid,
+			SpecHash:  specHash,
+			Resources: resources,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Namespace: s.Namespace, Name: SnapshotSecretName}
+	if err := s.Reader.Get(ctx, key, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.Namespace, Name: SnapshotSecretName},
+			Data:       map[string][]byte{SnapshotKey: data},
+		}
+		return s.Client.Create(ctx, secret)
+	}
+	if string(secret.Data[SnapshotKey]) == string(data) {
+		return nil
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[SnapshotKey] = data
+	return s.Client.Update(ctx, secret)
+}
+
+// Restore annotates each SecurityPolicy CR recreated with the same namespace and name as in the snapshot
+// with the UID of the previous CR, it returns the number of CRs annotated. Nothing is restored if the
+// snapshot Secret doesn't exist.
+func (s *Snapshotter) Restore(ctx context.Context) (int, error) {
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Namespace: s.Namespace, Name: SnapshotSecretName}
+	if err := s.Reader.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("no snapshot found, skip restore", "secret", key)
+			return 0, nil
+		}
+		return 0, err
+	}
+	var entries []SnapshotEntry
+	if err := json.Unmarshal(secret.Data[SnapshotKey], &entries); err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, entry := range entries {
+		policy := &v1alpha1.SecurityPolicy{}
+		policyKey := types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}
+		if err := s.Reader.Get(ctx, policyKey, policy); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.V(1).Info("SecurityPolicy in snapshot not found, skip restore", "securitypolicy", policyKey)
+				continue
+			}
+			return restored, err
+		}
+		if string(policy.UID) == entry.UID || policy.Annotations[servicecommon.AnnotationRealizedUID] == entry.UID {
+			continue
+		}
+		if specHash, err := hashSpec(&policy.Spec); err == nil && specHash != entry.SpecHash {
+			log.Info("SecurityPolicy spec changed since snapshot, the NSX resources will be updated on adoption",
+				"securitypolicy", policyKey)
+		}
+		if policy.Annotations == nil {
+			policy.Annotations = map[string]string{}
+		}
+		policy.Annotations[servicecommon.AnnotationRealizedUID] = entry.UID
+		if err := s.Client.Update(ctx, policy); err != nil {
+			return restored, err
+		}
+		log.Info("restored realized UID of SecurityPolicy", "securitypolicy", policyKey, "realizedUID", entry.UID)
+		restored++
+	}
+	return restored, nil
+}
+
+// Start implements manager.Runnable, it takes the snapshot periodically until ctx is done.
+func (s *Snapshotter) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.Interval):
+		}
+		if err := s.Snapshot(ctx); err != nil {
+			log.Error(err, "failed to take snapshot of realized NSX resources")
+		}
+	}
+}
+
+func hashSpec(spec *v1alpha1.SecurityPolicySpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return util.Sha1(string(data)), nil
+}
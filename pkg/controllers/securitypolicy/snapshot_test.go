@@ -0,0 +1,84 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+type fakeRealizedResourceLister map[string]*securitypolicy.RealizedResources
+
+func (l fakeRealizedResourceLister) ListRealizedResources() map[string]*securitypolicy.RealizedResources {
+	return l
+}
+
+func TestSnapshotter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	newPolicy := func(name, uid string) *v1alpha1.SecurityPolicy {
+		return &v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, UID: types.UID(uid)},
+			Spec:       v1alpha1.SecurityPolicySpec{Priority: 1},
+		}
+	}
+	resources := &securitypolicy.RealizedResources{SecurityPolicies: []string{"sp1-id"}, Rules: []string{"sp1-id-rule"}}
+	oldClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPolicy("sp1", "old-uid"), newPolicy("sp2", "sp2-uid")).Build()
+	snapshotter := &Snapshotter{
+		Client:    oldClient,
+		Reader:    oldClient,
+		Service:   fakeRealizedResourceLister{"old-uid": resources},
+		Namespace: "vmware-system-nsx",
+	}
+	ctx := context.TODO()
+
+	// sp2 is not realized, it's not in the snapshot
+	assert.NoError(t, snapshotter.Snapshot(ctx))
+	assert.NoError(t, snapshotter.Snapshot(ctx))
+	secret := &v1.Secret{}
+	assert.NoError(t, oldClient.Get(ctx, types.NamespacedName{Namespace: "vmware-system-nsx", Name: SnapshotSecretName}, secret))
+	specHash, _ := hashSpec(&v1alpha1.SecurityPolicySpec{Priority: 1})
+	assert.JSONEq(t, `[{"namespace":"ns1","name":"sp1","uid":"old-uid","specHash":"`+specHash+
+		`","resources":{"securityPolicies":["sp1-id"],"rules":["sp1-id-rule"]}}]`, string(secret.Data[SnapshotKey]))
+
+	// the Secret and the CRs are restored on a rebuilt cluster
+	secret.ResourceVersion = ""
+	newClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, newPolicy("sp1", "new-uid")).Build()
+	snapshotter.Client, snapshotter.Reader = newClient, newClient
+	restored, err := snapshotter.Restore(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restored)
+	policy := &v1alpha1.SecurityPolicy{}
+	assert.NoError(t, newClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "sp1"}, policy))
+	assert.Equal(t, "old-uid", policy.Annotations[servicecommon.AnnotationRealizedUID])
+
+	// the adopted NSX resources are kept in the snapshot with the previous UID
+	restored, err = snapshotter.Restore(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, restored)
+	assert.NoError(t, snapshotter.Snapshot(ctx))
+	assert.NoError(t, newClient.Get(ctx, types.NamespacedName{Namespace: "vmware-system-nsx", Name: SnapshotSecretName}, secret))
+	assert.Contains(t, string(secret.Data[SnapshotKey]), `"uid":"old-uid"`)
+}
+
+func TestSnapshotterRestoreWithoutSnapshot(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().Build()
+	snapshotter := &Snapshotter{Client: k8sClient, Reader: k8sClient, Namespace: "vmware-system-nsx"}
+	restored, err := snapshotter.Restore(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, restored)
+}
@@ -0,0 +1,172 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// When the IPs of a Pod are changed, the security policies in its namespace whose peers refer to Services or
+// StatefulSets are reconciled, the Pod may be one of the workloads.
+
+type EnqueueRequestForWorkloadPod struct {
+	Client client.Client
+	// Dampener delays the reconciles to batch the Pod events, the reconciles are not delayed if it's nil.
+	Dampener *common.GroupUpdateDampener
+}
+
+func (e *EnqueueRequestForWorkloadPod) Create(_ context.Context, createEvent event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(createEvent.Object, q)
+}
+
+func (e *EnqueueRequestForWorkloadPod) Update(_ context.Context, updateEvent event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(updateEvent.ObjectNew, q)
+}
+
+func (e *EnqueueRequestForWorkloadPod) Delete(_ context.Context, deleteEvent event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(deleteEvent.Object, q)
+}
+
+func (e *EnqueueRequestForWorkloadPod) Generic(_ context.Context, _ event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("pod generic event, do nothing")
+}
+
+func (e *EnqueueRequestForWorkloadPod) enqueue(pod client.Object, q workqueue.RateLimitingInterface) {
+	q = e.Dampener.Queue(q)
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := e.Client.List(context.Background(), spList, client.InNamespace(pod.GetNamespace())); err != nil {
+		log.Error(err, "failed to list security policy", "namespace", pod.GetNamespace())
+		return
+	}
+	for i := range spList.Items {
+		securityPolicy := &spList.Items[i]
+		if !securitypolicy.UsesWorkloadPeers(securityPolicy) {
+			continue
+		}
+		log.V(1).Info("reconcile security policy because of workload pod change",
+			"namespace", securityPolicy.Namespace, "name", securityPolicy.Name, "pod", pod.GetName())
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      securityPolicy.Name,
+				Namespace: securityPolicy.Namespace,
+			},
+		})
+	}
+}
+
+// PredicateFuncsWorkloadPod filters the Pod events changing the IPs or the labels of the Pods. The IPs of
+// a restarting Pod are removed first, the policies keep its last known IPs until the new ones are assigned.
+var PredicateFuncsWorkloadPod = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj, okOld := e.ObjectOld.(*v1.Pod)
+		newObj, okNew := e.ObjectNew.(*v1.Pod)
+		if !okOld || !okNew {
+			return false
+		}
+		return !reflect.DeepEqual(oldObj.Status.PodIPs, newObj.Status.PodIPs) ||
+			!reflect.DeepEqual(oldObj.Labels, newObj.Labels) ||
+			oldObj.Status.Phase != newObj.Status.Phase
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// When a Service without selector, or its manual Endpoints, is changed, the security policies in its namespace
+// whose peers refer to the Service are reconciled, its domain name or its IPs may be changed.
+
+type EnqueueRequestForExternalService struct {
+	Client client.Client
+}
+
+func (e *EnqueueRequestForExternalService) Create(_ context.Context, createEvent event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(createEvent.Object, q)
+}
+
+func (e *EnqueueRequestForExternalService) Update(_ context.Context, updateEvent event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(updateEvent.ObjectNew, q)
+}
+
+func (e *EnqueueRequestForExternalService) Delete(_ context.Context, deleteEvent event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(deleteEvent.Object, q)
+}
+
+func (e *EnqueueRequestForExternalService) Generic(_ context.Context, _ event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("external service generic event, do nothing")
+}
+
+func (e *EnqueueRequestForExternalService) enqueue(obj client.Object, q workqueue.RateLimitingInterface) {
+	if _, ok := obj.(*v1.Endpoints); ok {
+		// the Endpoints of a Service with selector follow its Pods, which are watched by EnqueueRequestForWorkloadPod
+		svc := &v1.Service{}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if err := e.Client.Get(context.Background(), key, svc); err == nil && len(svc.Spec.Selector) > 0 {
+			return
+		}
+	}
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := e.Client.List(context.Background(), spList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list security policy", "namespace", obj.GetNamespace())
+		return
+	}
+	for i := range spList.Items {
+		securityPolicy := &spList.Items[i]
+		if !securitypolicy.UsesService(securityPolicy, obj.GetName()) {
+			continue
+		}
+		log.V(1).Info("reconcile security policy because of external service change",
+			"namespace", securityPolicy.Namespace, "name", securityPolicy.Name, "service", obj.GetName())
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      securityPolicy.Name,
+				Namespace: securityPolicy.Namespace,
+			},
+		})
+	}
+}
+
+// PredicateFuncsExternalService filters the events of the Services changing their selectors or external names,
+// and the events of the Endpoints changing their addresses.
+var PredicateFuncsExternalService = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		switch oldObj := e.ObjectOld.(type) {
+		case *v1.Service:
+			newObj, ok := e.ObjectNew.(*v1.Service)
+			return ok && (!reflect.DeepEqual(oldObj.Spec.Selector, newObj.Spec.Selector) ||
+				oldObj.Spec.Type != newObj.Spec.Type || oldObj.Spec.ExternalName != newObj.Spec.ExternalName)
+		case *v1.Endpoints:
+			newObj, ok := e.ObjectNew.(*v1.Endpoints)
+			return ok && !reflect.DeepEqual(oldObj.Subsets, newObj.Subsets)
+		}
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
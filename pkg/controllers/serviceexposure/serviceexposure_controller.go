@@ -0,0 +1,401 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package serviceexposure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeServiceExposure
+)
+
+// ServiceExposureReconciler reconciles a ServiceExposure object
+type ServiceExposureReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *securitypolicy.SecurityPolicyService
+	Recorder record.EventRecorder
+	// Tiers defers the reconciles in the new namespaces until the resources of the lower tiers are realized.
+	Tiers *common.TierGate
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *common.WarmupGate
+	// DeletionGuard pauses the garbage collection deleting too many ServiceExposures until it's confirmed.
+	DeletionGuard *common.DeletionGuard
+}
+
+func deleteFail(r *ServiceExposureReconciler, c *context.Context, o *v1alpha1.ServiceExposure, e *error) {
+	r.setServiceExposureReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateFail(r *ServiceExposureReconciler, c *context.Context, o *v1alpha1.ServiceExposure, e *error) {
+	r.setServiceExposureReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func updateSuccess(r *ServiceExposureReconciler, c *context.Context, o *v1alpha1.ServiceExposure) {
+	r.setServiceExposureReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "ServiceExposure CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *ServiceExposureReconciler, _ *context.Context, o *v1alpha1.ServiceExposure) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "ServiceExposure CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *ServiceExposureReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", "serviceexposure", req.NamespacedName)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "serviceexposure", req.NamespacedName)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	obj := &v1alpha1.ServiceExposure{}
+	log.Info("reconciling serviceexposure CR", "serviceexposure", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch serviceexposure CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		if wait := r.Tiers.Defer(ctx, req.Namespace); wait > 0 {
+			log.V(1).Info("waiting for the lower tiers of the new namespace", "serviceexposure", req.NamespacedName, "after", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, commonservice.ServiceExposureFinalizerName) {
+			controllerutil.AddFinalizer(obj, commonservice.ServiceExposureFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "serviceexposure", req.NamespacedName)
+				updateFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on serviceexposure CR", "serviceexposure", req.NamespacedName)
+		}
+
+		svc := &v1.Service{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.Service}, svc); err != nil {
+			err = fmt.Errorf("failed to get Service %s: %w", obj.Spec.Service, err)
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		consumerNamespaces, err := r.listConsumerNamespaces(ctx, obj)
+		if err != nil {
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		if err := r.Service.CreateOrUpdateServiceExposure(obj, svc, consumerNamespaces); err != nil {
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		if !reflect.DeepEqual(obj.Status.ConsumerNamespaces, consumerNamespaces) {
+			obj.Status.ConsumerNamespaces = consumerNamespaces
+			if err := r.Client.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update consumer namespaces", "serviceexposure", req.NamespacedName)
+			}
+		}
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, commonservice.ServiceExposureFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteServiceExposure(obj.UID); err != nil {
+				log.Error(err, "delete failed, would retry exponentially", "serviceexposure", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, commonservice.ServiceExposureFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "serviceexposure", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "serviceexposure", req.NamespacedName)
+		}
+	}
+
+	return ResultNormal, nil
+}
+
+// listConsumerNamespaces returns the sorted names of the Namespaces selected by any consumer, the Namespaces
+// being deleted are skipped.
+func (r *ServiceExposureReconciler) listConsumerNamespaces(ctx context.Context, obj *v1alpha1.ServiceExposure) ([]string, error) {
+	namespaces := sets.New[string]()
+	for _, consumer := range obj.Spec.Consumers {
+		selector, err := metav1.LabelSelectorAsSelector(consumer.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		nsList := &v1.NamespaceList{}
+		if err := r.Client.List(ctx, nsList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list Namespaces: %w", err)
+		}
+		for _, ns := range nsList.Items {
+			if ns.DeletionTimestamp.IsZero() {
+				namespaces.Insert(ns.Name)
+			}
+		}
+	}
+	return sets.List(namespaces), nil
+}
+
+func (r *ServiceExposureReconciler) setServiceExposureReadyStatusTrue(ctx *context.Context, serviceExposure *v1alpha1.ServiceExposure, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX Security Policies of the provider and consumers have been successfully created/updated",
+			Reason:             "NSX API returned 200 response code for PATCH",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateServiceExposureStatusConditions(ctx, serviceExposure, newConditions)
+}
+
+func (r *ServiceExposureReconciler) setServiceExposureReadyStatusFalse(ctx *context.Context, serviceExposure *v1alpha1.ServiceExposure, transitionTime metav1.Time, err *error) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX Security Policies of the provider and consumers could not be created/updated/deleted",
+			Reason:             fmt.Sprintf("error occurred while processing the ServiceExposure CR. Error: %v", *err),
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateServiceExposureStatusConditions(ctx, serviceExposure, newConditions)
+}
+
+func (r *ServiceExposureReconciler) updateServiceExposureStatusConditions(ctx *context.Context, serviceExposure *v1alpha1.ServiceExposure, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if r.mergeServiceExposureStatusCondition(serviceExposure, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		r.Client.Status().Update(*ctx, serviceExposure)
+		log.V(1).Info("updated ServiceExposure", "Name", serviceExposure.Name, "Namespace", serviceExposure.Namespace, "New Conditions", newConditions)
+	}
+}
+
+func (r *ServiceExposureReconciler) mergeServiceExposureStatusCondition(serviceExposure *v1alpha1.ServiceExposure, newCondition *v1alpha1.Condition) bool {
+	matchedCondition := getExistingConditionOfType(newCondition.Type, serviceExposure.Status.Conditions)
+
+	if reflect.DeepEqual(matchedCondition, newCondition) {
+		log.V(2).Info("conditions already match", "New Condition", newCondition, "Existing Condition", matchedCondition)
+		return false
+	}
+
+	if matchedCondition != nil {
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+	} else {
+		serviceExposure.Status.Conditions = append(serviceExposure.Status.Conditions, *newCondition)
+	}
+	return true
+}
+
+func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingConditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range existingConditions {
+		if existingConditions[i].Type == conditionType {
+			return &existingConditions[i]
+		}
+	}
+	return nil
+}
+
+// serviceMapFunc enqueues the ServiceExposures publishing the Service, so the rules follow the selector and
+// the ports of the Service.
+func (r *ServiceExposureReconciler) serviceMapFunc(ctx context.Context, obj client.Object) []reconcile.Request {
+	serviceExposureList := &v1alpha1.ServiceExposureList{}
+	var requests []reconcile.Request
+	if err := r.Client.List(ctx, serviceExposureList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list serviceexposure in Service handler")
+		return requests
+	}
+	for _, serviceExposure := range serviceExposureList.Items {
+		if serviceExposure.Spec.Service == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: serviceExposure.Name, Namespace: serviceExposure.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// namespaceMapFunc enqueues all the ServiceExposures when a Namespace is created, deleted or relabeled, the
+// Namespace may become or stop being a consumer.
+func (r *ServiceExposureReconciler) namespaceMapFunc(ctx context.Context, _ client.Object) []reconcile.Request {
+	serviceExposureList := &v1alpha1.ServiceExposureList{}
+	var requests []reconcile.Request
+	if err := r.Client.List(ctx, serviceExposureList); err != nil {
+		log.Error(err, "failed to list serviceexposure in Namespace handler")
+		return requests
+	}
+	for _, serviceExposure := range serviceExposureList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: serviceExposure.Name, Namespace: serviceExposure.Namespace},
+		})
+	}
+	return requests
+}
+
+var predicateFuncsNamespace = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *ServiceExposureReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ServiceExposure{}, builder.WithPredicates(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		})).
+		Watches(&v1.Service{}, handler.EnqueueRequestsFromMapFunc(r.serviceMapFunc)).
+		Watches(&v1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.namespaceMapFunc), builder.WithPredicates(predicateFuncsNamespace)).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *ServiceExposureReconciler) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	r.Tiers = common.NewTierGate(common.TierLoadBalancer, MetricResType, mgr.GetClient(), r.Service.NSXConfig)
+	r.Warmup = common.NewWarmupGate(MetricResType, r.Service.NSXConfig, mgr.GetCache(), &v1alpha1.ServiceExposure{}, &v1.Service{}, &v1.Namespace{})
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), commonservice.GCInterval)
+	return nil
+}
+
+// GarbageCollector collect the NSX resources of the ServiceExposures which have been removed from crd.
+// cancel is used to break the loop during UT
+func (r *ServiceExposureReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started")
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		if err := r.collectGarbage(ctx); err != nil {
+			log.Error(err, "failed to collect garbage of ServiceExposure")
+		}
+	}
+}
+
+// collectGarbage deletes the NSX resources of the ServiceExposures whose CR has been removed. The deletion is
+// paused by the DeletionGuard if too many ServiceExposures are collected.
+func (r *ServiceExposureReconciler) collectGarbage(ctx context.Context) error {
+	nsxPolicySet := r.Service.ListServiceExposureID()
+	if len(nsxPolicySet) == 0 {
+		return nil
+	}
+
+	crdServiceExposureList := &v1alpha1.ServiceExposureList{}
+	if err := r.Client.List(ctx, crdServiceExposureList); err != nil {
+		return err
+	}
+
+	crdServiceExposureSet := sets.New[string]()
+	for _, serviceExposure := range crdServiceExposureList.Items {
+		crdServiceExposureSet.Insert(string(serviceExposure.UID))
+	}
+
+	nsxServiceExposureSet := sets.New[string]()
+	for elem := range nsxPolicySet {
+		nsxServiceExposureSet.Insert(securitypolicy.ServiceExposureUIDOf(elem))
+	}
+	gcSet := nsxServiceExposureSet.Difference(crdServiceExposureSet)
+	if err := r.DeletionGuard.Allow(ctx, len(gcSet), len(nsxServiceExposureSet)); err != nil {
+		return err
+	}
+	for uid := range gcSet {
+		log.V(1).Info("GC collected ServiceExposure CR", "UID", uid)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteServiceExposure(types.UID(uid)); err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+		} else {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		}
+	}
+	return nil
+}
+
+func StartServiceExposureController(mgr ctrl.Manager, commonService commonservice.Service, vpcService commonservice.VPCServiceProvider,
+	deletionGuard *common.DeletionGuard) {
+	serviceExposureReconcile := ServiceExposureReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("serviceexposure-controller"),
+	}
+	serviceExposureReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
+	serviceExposureReconcile.DeletionGuard = deletionGuard
+	if err := serviceExposureReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "ServiceExposure")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,189 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package serviceexposure
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func newFakeServiceExposureReconciler(objs ...client.Object) *ServiceExposureReconciler {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	return &ServiceExposureReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.ServiceExposure{}).Build(),
+		Scheme: scheme,
+		Service: &securitypolicy.SecurityPolicyService{
+			Service: commonservice.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{},
+					K8sConfig: &config.K8sConfig{},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func TestServiceExposureReconciler_Reconcile(t *testing.T) {
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "exposure1"}}
+	exposure := &v1alpha1.ServiceExposure{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "exposure1", UID: "uid1"},
+		Spec: v1alpha1.ServiceExposureSpec{
+			Service: "svc1",
+			Consumers: []v1alpha1.ServiceExposureConsumer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"consumer": "true"}}},
+			},
+		},
+	}
+	r := newFakeServiceExposureReconciler(exposure,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Labels: map[string]string{"consumer": "true"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns3"}},
+	)
+
+	// the reconciles wait for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultRequeueAfter10sec, result)
+	r.Warmup = nil
+
+	// not found
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "exposure2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// the ServiceExposure is retried until the Service is created
+	var s *securitypolicy.SecurityPolicyService
+	var consumers []string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "CreateOrUpdateServiceExposure",
+		func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.ServiceExposure, _ *v1.Service, consumerNamespaces []string) error {
+			consumers = consumerNamespaces
+			return nil
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	obj := &v1alpha1.ServiceExposure{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, []string{commonservice.ServiceExposureFinalizerName}, obj.Finalizers)
+	assert.Equal(t, v1.ConditionFalse, obj.Status.Conditions[0].Status)
+
+	assert.NoError(t, r.Client.Create(ctx, &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1"}}))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.Equal(t, []string{"ns2"}, consumers)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, []string{"ns2"}, obj.Status.ConsumerNamespaces)
+	assert.Equal(t, v1.ConditionTrue, obj.Status.Conditions[0].Status)
+	patches.Reset()
+
+	// the finalizer is kept until the NSX resources are deleted
+	assert.NoError(t, r.Client.Delete(ctx, obj))
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteServiceExposure",
+		func(_ *securitypolicy.SecurityPolicyService, _ types.UID) error {
+			return errors.New("delete failed")
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	patches.Reset()
+
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteServiceExposure",
+		func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+			assert.Equal(t, types.UID("uid1"), uid)
+			return nil
+		})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.True(t, apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, obj)))
+}
+
+func TestServiceExposureReconciler_mapFuncs(t *testing.T) {
+	r := newFakeServiceExposureReconciler(
+		&v1alpha1.ServiceExposure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "exposure1"},
+			Spec:       v1alpha1.ServiceExposureSpec{Service: "svc1"},
+		},
+		&v1alpha1.ServiceExposure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "exposure2"},
+			Spec:       v1alpha1.ServiceExposureSpec{Service: "svc1"},
+		},
+	)
+	requests := r.serviceMapFunc(context.TODO(), &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1"}})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "exposure1"}}}, requests)
+	requests = r.namespaceMapFunc(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns3"}})
+	assert.Len(t, requests, 2)
+}
+
+func TestServiceExposureReconciler_GarbageCollector(t *testing.T) {
+	exposure := &v1alpha1.ServiceExposure{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "exposure1", UID: "uid1"}}
+	r := newFakeServiceExposureReconciler(exposure, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system"}})
+	r.DeletionGuard = common.NewDeletionGuard(MetricResType, r.Service.NSXConfig, r.Client, r.Recorder, "nsx-system")
+
+	var s *securitypolicy.SecurityPolicyService
+	var deleted []string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "ListServiceExposureID", func(_ *securitypolicy.SecurityPolicyService) sets.Set[string] {
+		// the provider and the consumer policies of a ServiceExposure are counted once
+		return sets.New[string]("uid1_provider", "uid2_provider", "uid2_ns2", "uid3_provider")
+	})
+	patches.ApplyMethod(reflect.TypeOf(s), "DeleteServiceExposure", func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+		deleted = append(deleted, string(uid))
+		return nil
+	})
+	defer patches.Reset()
+
+	// the garbage collection waits for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, time.Millisecond)
+	assert.Empty(t, deleted)
+	r.Warmup = nil
+
+	// the mass deletion is paused until it's confirmed
+	r.Service.NSXConfig.MassDeletionMaxCount = 1
+	r.Service.NSXConfig.MassDeletionMaxPercent = 50
+	assert.Error(t, r.collectGarbage(context.TODO()))
+	assert.Empty(t, deleted)
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system", Annotations: map[string]string{common.AnnotationConfirmMassDeletion: "2"}}}
+	assert.NoError(t, r.Client.Update(context.TODO(), ns))
+	assert.NoError(t, r.collectGarbage(context.TODO()))
+	assert.ElementsMatch(t, []string{"uid2", "uid3"}, deleted)
+}
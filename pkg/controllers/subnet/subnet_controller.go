@@ -48,6 +48,8 @@ type SubnetReconciler struct {
 	SubnetPortService servicecommon.SubnetPortServiceProvider
 	VPCService        servicecommon.VPCServiceProvider
 	Recorder          record.EventRecorder
+	// Latency measures the time from the spec changes to their realization confirmed by NSX.
+	Latency *common.RealizationLatency
 }
 
 func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -104,6 +106,8 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			updateFail(r, &ctx, obj, "")
 			return ResultRequeue, err
 		}
+		// the realization of the NSX Subnet is checked before CreateOrUpdateSubnet returns
+		r.Latency.Realized(obj.UID, obj.Generation)
 		if err := r.updateSubnetStatus(obj); err != nil {
 			log.Error(err, "update subnet status failed, would retry exponentially", "subnet", req.NamespacedName)
 			updateFail(r, &ctx, obj, "")
@@ -125,6 +129,7 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				return ResultRequeue, err
 			}
 			log.V(1).Info("removed finalizer", "subnet", req.NamespacedName)
+			r.Latency.Forget(obj.UID)
 			deleteSuccess(r, &ctx, obj)
 		} else {
 			log.Info("finalizers cannot be recognized", "subnet", req.NamespacedName)
@@ -262,7 +267,7 @@ func deleteSuccess(r *SubnetReconciler, _ *context.Context, o *v1alpha1.Subnet)
 
 func (r *SubnetReconciler) setupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Subnet{}).
+		For(&v1alpha1.Subnet{}, builder.WithPredicates(r.Latency.Predicate())).
 		WithEventFilter(predicate.Funcs{
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				// Suppress Delete events to avoid filtering them out in the Reconcile function
@@ -289,6 +294,7 @@ func StartSubnetController(mgr ctrl.Manager, subnetService *subnet.SubnetService
 		SubnetPortService: subnetPortService,
 		VPCService:        vpcService,
 		Recorder:          mgr.GetEventRecorderFor("subnet-controller"),
+		Latency:           common.NewRealizationLatency(MetricResTypeSubnet, subnetService.NSXConfig),
 	}
 	if err := subnetReconciler.Start(mgr); err != nil {
 		log.Error(err, "failed to create controller", "controller", "Subnet")
@@ -303,6 +309,9 @@ func (r *SubnetReconciler) Start(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	common.RegisterTierCheck(common.TierNetwork, MetricResTypeSubnet, common.ReadyConditionPending(mgr.GetClient(),
+		func() client.ObjectList { return &v1alpha1.SubnetList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.Subnet).Status.Conditions }))
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
@@ -0,0 +1,366 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetpolicy"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeSubnetPolicy
+)
+
+// SubnetPolicyReconciler reconciles a SubnetPolicy object
+type SubnetPolicyReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *subnetpolicy.SubnetPolicyService
+	Recorder record.EventRecorder
+	// Warmup gates the reconciles and the garbage collection until the caches are synced.
+	Warmup *common.WarmupGate
+	// DeletionGuard pauses the garbage collection deleting too many SubnetPolicys until it's confirmed.
+	DeletionGuard *common.DeletionGuard
+}
+
+func deleteFail(r *SubnetPolicyReconciler, c *context.Context, o *v1alpha1.SubnetPolicy, e *error) {
+	r.setSubnetPolicyReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateFail(r *SubnetPolicyReconciler, c *context.Context, o *v1alpha1.SubnetPolicy, e *error) {
+	r.setSubnetPolicyReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func updateSuccess(r *SubnetPolicyReconciler, c *context.Context, o *v1alpha1.SubnetPolicy) {
+	r.setSubnetPolicyReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "SubnetPolicy CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *SubnetPolicyReconciler, _ *context.Context, o *v1alpha1.SubnetPolicy) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "SubnetPolicy CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *SubnetPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Warmup.Ready() {
+		log.V(1).Info("controller is warming up", "subnetpolicy", req.NamespacedName)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if common.InNSXMaintenance() {
+		log.V(1).Info("NSX is in maintenance mode, postponing the reconcile", "subnetpolicy", req.NamespacedName)
+		return common.ResultRequeueAfterMaintenance, nil
+	}
+	obj := &v1alpha1.SubnetPolicy{}
+	log.Info("reconciling subnetpolicy CR", "subnetpolicy", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch subnetpolicy CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, commonservice.SubnetPolicyFinalizerName) {
+			controllerutil.AddFinalizer(obj, commonservice.SubnetPolicyFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "subnetpolicy", req.NamespacedName)
+				updateFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on subnetpolicy CR", "subnetpolicy", req.NamespacedName)
+		}
+
+		subnetPaths, err := r.listSubnetPaths(ctx, obj)
+		if err != nil {
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		if err := r.Service.CreateOrUpdateSubnetPolicy(obj, subnetPaths); err != nil {
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, commonservice.SubnetPolicyFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteSubnetPolicy(obj.UID); err != nil {
+				log.Error(err, "delete failed, would retry exponentially", "subnetpolicy", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, commonservice.SubnetPolicyFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "subnetpolicy", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "subnetpolicy", req.NamespacedName)
+		}
+	}
+
+	return ResultNormal, nil
+}
+
+// listSubnetPaths returns the NSX paths of the Subnets and the Subnets of the SubnetSets the SubnetPolicy is
+// applied to, the SubnetPolicy is retried until all of them are realized.
+func (r *SubnetPolicyReconciler) listSubnetPaths(ctx context.Context, obj *v1alpha1.SubnetPolicy) ([]string, error) {
+	var subnetPaths []string
+	for _, name := range obj.Spec.Subnets {
+		subnet := &v1alpha1.Subnet{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: name}, subnet); err != nil {
+			return nil, fmt.Errorf("failed to get Subnet %s: %w", name, err)
+		}
+		if subnet.Status.NSXResourcePath == "" {
+			return nil, fmt.Errorf("subnet %s is not realized yet", name)
+		}
+		subnetPaths = append(subnetPaths, subnet.Status.NSXResourcePath)
+	}
+	for _, name := range obj.Spec.SubnetSets {
+		subnetSet := &v1alpha1.SubnetSet{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: name}, subnetSet); err != nil {
+			return nil, fmt.Errorf("failed to get SubnetSet %s: %w", name, err)
+		}
+		if len(subnetSet.Status.Subnets) == 0 {
+			return nil, fmt.Errorf("subnetset %s has no subnet realized yet", name)
+		}
+		for _, subnetInfo := range subnetSet.Status.Subnets {
+			subnetPaths = append(subnetPaths, subnetInfo.NSXResourcePath)
+		}
+	}
+	return subnetPaths, nil
+}
+
+func (r *SubnetPolicyReconciler) setSubnetPolicyReadyStatusTrue(ctx *context.Context, subnetPolicy *v1alpha1.SubnetPolicy, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX Security Policy of the Subnets has been successfully created/updated",
+			Reason:             "NSX API returned 200 response code for PATCH",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateSubnetPolicyStatusConditions(ctx, subnetPolicy, newConditions)
+}
+
+func (r *SubnetPolicyReconciler) setSubnetPolicyReadyStatusFalse(ctx *context.Context, subnetPolicy *v1alpha1.SubnetPolicy, transitionTime metav1.Time, err *error) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX Security Policy of the Subnets could not be created/updated/deleted",
+			Reason:             fmt.Sprintf("error occurred while processing the SubnetPolicy CR. Error: %v", *err),
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateSubnetPolicyStatusConditions(ctx, subnetPolicy, newConditions)
+}
+
+func (r *SubnetPolicyReconciler) updateSubnetPolicyStatusConditions(ctx *context.Context, subnetPolicy *v1alpha1.SubnetPolicy, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if r.mergeSubnetPolicyStatusCondition(subnetPolicy, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		r.Client.Status().Update(*ctx, subnetPolicy)
+		log.V(1).Info("updated SubnetPolicy", "Name", subnetPolicy.Name, "Namespace", subnetPolicy.Namespace, "New Conditions", newConditions)
+	}
+}
+
+func (r *SubnetPolicyReconciler) mergeSubnetPolicyStatusCondition(subnetPolicy *v1alpha1.SubnetPolicy, newCondition *v1alpha1.Condition) bool {
+	matchedCondition := getExistingConditionOfType(newCondition.Type, subnetPolicy.Status.Conditions)
+
+	if reflect.DeepEqual(matchedCondition, newCondition) {
+		log.V(2).Info("conditions already match", "New Condition", newCondition, "Existing Condition", matchedCondition)
+		return false
+	}
+
+	if matchedCondition != nil {
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+	} else {
+		subnetPolicy.Status.Conditions = append(subnetPolicy.Status.Conditions, *newCondition)
+	}
+	return true
+}
+
+func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingConditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range existingConditions {
+		if existingConditions[i].Type == conditionType {
+			return &existingConditions[i]
+		}
+	}
+	return nil
+}
+
+// subnetMapFunc enqueues the SubnetPolicies referring to the Subnet or SubnetSet, so they're realized again
+// when the Subnets are realized or the SubnetSet is scaled out.
+func (r *SubnetPolicyReconciler) subnetMapFunc(ctx context.Context, obj client.Object) []reconcile.Request {
+	subnetPolicyList := &v1alpha1.SubnetPolicyList{}
+	var requests []reconcile.Request
+	if err := r.Client.List(ctx, subnetPolicyList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list subnetpolicy in Subnet handler")
+		return requests
+	}
+	_, isSubnetSet := obj.(*v1alpha1.SubnetSet)
+	for _, subnetPolicy := range subnetPolicyList.Items {
+		names := subnetPolicy.Spec.Subnets
+		if isSubnetSet {
+			names = subnetPolicy.Spec.SubnetSets
+		}
+		for _, name := range names {
+			if name == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: subnetPolicy.Name, Namespace: subnetPolicy.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+func (r *SubnetPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SubnetPolicy{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(r.subnetMapFunc)).
+		Watches(&v1alpha1.SubnetSet{}, handler.EnqueueRequestsFromMapFunc(r.subnetMapFunc)).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *SubnetPolicyReconciler) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	r.Warmup = common.NewWarmupGate(MetricResType, r.Service.NSXConfig, mgr.GetCache(), &v1alpha1.SubnetPolicy{}, &v1alpha1.Subnet{}, &v1alpha1.SubnetSet{})
+	if err := mgr.Add(r.Warmup); err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), commonservice.GCInterval)
+	return nil
+}
+
+// GarbageCollector collect the NSX resources of the SubnetPolicies which have been removed from crd.
+// cancel is used to break the loop during UT
+func (r *SubnetPolicyReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("garbage collector started")
+	if !r.Warmup.Wait(cancel) {
+		return
+	}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		if common.InNSXMaintenance() {
+			continue
+		}
+		if err := r.collectGarbage(ctx); err != nil {
+			log.Error(err, "failed to collect garbage of SubnetPolicy")
+		}
+	}
+}
+
+// collectGarbage deletes the NSX resources of the SubnetPolicys whose CR has been removed. The deletion is
+// paused by the DeletionGuard if too many SubnetPolicys are collected.
+func (r *SubnetPolicyReconciler) collectGarbage(ctx context.Context) error {
+	nsxSubnetPolicySet := r.Service.ListSubnetPolicyID()
+	if len(nsxSubnetPolicySet) == 0 {
+		return nil
+	}
+
+	crdSubnetPolicyList := &v1alpha1.SubnetPolicyList{}
+	if err := r.Client.List(ctx, crdSubnetPolicyList); err != nil {
+		return err
+	}
+
+	crdSubnetPolicySet := sets.New[string]()
+	for _, subnetPolicy := range crdSubnetPolicyList.Items {
+		crdSubnetPolicySet.Insert(string(subnetPolicy.UID))
+	}
+
+	stale := nsxSubnetPolicySet.Difference(crdSubnetPolicySet)
+	if err := r.DeletionGuard.Allow(ctx, len(stale), len(nsxSubnetPolicySet)); err != nil {
+		return err
+	}
+	for uid := range stale {
+		log.V(1).Info("GC collected SubnetPolicy CR", "UID", uid)
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteSubnetPolicy(types.UID(uid)); err != nil {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+		} else {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+		}
+	}
+	return nil
+}
+
+func StartSubnetPolicyController(mgr ctrl.Manager, subnetPolicyService *subnetpolicy.SubnetPolicyService, deletionGuard *common.DeletionGuard) {
+	subnetPolicyReconcile := SubnetPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("subnetpolicy-controller"),
+	}
+	subnetPolicyReconcile.Service = subnetPolicyService
+	subnetPolicyReconcile.DeletionGuard = deletionGuard
+	if err := subnetPolicyReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "SubnetPolicy")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,185 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetpolicy"
+)
+
+func newFakeSubnetPolicyReconciler(objs ...client.Object) *SubnetPolicyReconciler {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	return &SubnetPolicyReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.SubnetPolicy{}).Build(),
+		Scheme: scheme,
+		Service: &subnetpolicy.SubnetPolicyService{
+			Service: commonservice.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{},
+					K8sConfig: &config.K8sConfig{},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func TestSubnetPolicyReconciler_Reconcile(t *testing.T) {
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "policy1"}}
+	policy := &v1alpha1.SubnetPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "policy1", UID: "uid1"},
+		Spec:       v1alpha1.SubnetPolicySpec{Subnets: []string{"subnet1"}, SubnetSets: []string{"subnetset1"}},
+	}
+	subnet := &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"}}
+	subnetSet := &v1alpha1.SubnetSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnetset1"},
+		Status:     v1alpha1.SubnetSetStatus{Subnets: []v1alpha1.SubnetInfo{{NSXResourcePath: "/subnets/subnetset1-0"}}},
+	}
+	r := newFakeSubnetPolicyReconciler(policy, subnet, subnetSet)
+
+	// the reconciles wait for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultRequeueAfter10sec, result)
+	r.Warmup = nil
+
+	// not found
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "policy2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// the SubnetPolicy is retried until the Subnets are realized
+	var s *subnetpolicy.SubnetPolicyService
+	var realizedPaths []string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "CreateOrUpdateSubnetPolicy",
+		func(_ *subnetpolicy.SubnetPolicyService, _ *v1alpha1.SubnetPolicy, subnetPaths []string) error {
+			realizedPaths = subnetPaths
+			return nil
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.EqualError(t, err, "subnet subnet1 is not realized yet")
+	assert.Equal(t, ResultRequeue, result)
+	obj := &v1alpha1.SubnetPolicy{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, []string{commonservice.SubnetPolicyFinalizerName}, obj.Finalizers)
+	assert.Equal(t, v1.ConditionFalse, obj.Status.Conditions[0].Status)
+
+	subnet.Status.NSXResourcePath = "/subnets/subnet1"
+	assert.NoError(t, r.Client.Update(ctx, subnet))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.Equal(t, []string{"/subnets/subnet1", "/subnets/subnetset1-0"}, realizedPaths)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	assert.Equal(t, v1.ConditionTrue, obj.Status.Conditions[0].Status)
+	patches.Reset()
+
+	// the finalizer is kept until the NSX resources are deleted
+	assert.NoError(t, r.Client.Delete(ctx, obj))
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteSubnetPolicy",
+		func(_ *subnetpolicy.SubnetPolicyService, _ types.UID) error {
+			return errors.New("delete failed")
+		})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, obj))
+	patches.Reset()
+
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(s), "DeleteSubnetPolicy",
+		func(_ *subnetpolicy.SubnetPolicyService, uid types.UID) error {
+			assert.Equal(t, types.UID("uid1"), uid)
+			return nil
+		})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.True(t, apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, obj)))
+}
+
+func TestSubnetPolicyReconciler_subnetMapFunc(t *testing.T) {
+	r := newFakeSubnetPolicyReconciler(
+		&v1alpha1.SubnetPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "policy1"},
+			Spec:       v1alpha1.SubnetPolicySpec{Subnets: []string{"subnet1"}},
+		},
+		&v1alpha1.SubnetPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "policy2"},
+			Spec:       v1alpha1.SubnetPolicySpec{SubnetSets: []string{"subnet1"}},
+		},
+	)
+	requests := r.subnetMapFunc(context.TODO(), &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"}})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "policy1"}}}, requests)
+	requests = r.subnetMapFunc(context.TODO(), &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"}})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "policy2"}}}, requests)
+}
+
+func TestSubnetPolicyReconciler_GarbageCollector(t *testing.T) {
+	policy := &v1alpha1.SubnetPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "policy1", UID: "uid1"}}
+	r := newFakeSubnetPolicyReconciler(policy, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system"}})
+	r.DeletionGuard = common.NewDeletionGuard(MetricResType, r.Service.NSXConfig, r.Client, r.Recorder, "nsx-system")
+
+	var s *subnetpolicy.SubnetPolicyService
+	var deleted []string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "ListSubnetPolicyID", func(_ *subnetpolicy.SubnetPolicyService) sets.Set[string] {
+		return sets.New[string]("uid1", "uid2", "uid3")
+	})
+	patches.ApplyMethod(reflect.TypeOf(s), "DeleteSubnetPolicy", func(_ *subnetpolicy.SubnetPolicyService, uid types.UID) error {
+		deleted = append(deleted, string(uid))
+		return nil
+	})
+	defer patches.Reset()
+
+	// the garbage collection waits for the warm-up
+	r.Warmup = common.NewWarmupGate(MetricResType, nil, nil)
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, time.Millisecond)
+	assert.Empty(t, deleted)
+	r.Warmup = nil
+
+	// the mass deletion is paused until it's confirmed
+	r.Service.NSXConfig.MassDeletionMaxCount = 1
+	r.Service.NSXConfig.MassDeletionMaxPercent = 50
+	assert.Error(t, r.collectGarbage(context.TODO()))
+	assert.Empty(t, deleted)
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsx-system", Annotations: map[string]string{common.AnnotationConfirmMassDeletion: "2"}}}
+	assert.NoError(t, r.Client.Update(context.TODO(), ns))
+	assert.NoError(t, r.collectGarbage(context.TODO()))
+	assert.ElementsMatch(t, []string{"uid2", "uid3"}, deleted)
+}
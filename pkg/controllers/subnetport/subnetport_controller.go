@@ -54,6 +54,10 @@ type SubnetPortReconciler struct {
 	SubnetService     servicecommon.SubnetServiceProvider
 	VPCService        servicecommon.VPCServiceProvider
 	Recorder          record.EventRecorder
+	// Latency measures the time from the spec changes to their realization confirmed by NSX.
+	Latency *common.RealizationLatency
+	// Tiers defers the reconciles in the new namespaces until the resources of the lower tiers are realized.
+	Tiers *common.TierGate
 }
 
 // +kubebuilder:rbac:groups=nsx.vmware.com,resources=subnetports,verbs=get;list;watch;create;update;patch;delete
@@ -77,6 +81,10 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	if subnetPort.ObjectMeta.DeletionTimestamp.IsZero() {
+		if wait := r.Tiers.Defer(ctx, req.Namespace); wait > 0 {
+			log.V(1).Info("waiting for the lower tiers of the new namespace", "subnetport", req.NamespacedName, "after", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		metrics.CounterInc(r.SubnetPortService.NSXConfig, metrics.ControllerUpdateTotal, MetricResTypeSubnetPort)
 		if !controllerutil.ContainsFinalizer(subnetPort, servicecommon.SubnetPortFinalizerName) {
 			controllerutil.AddFinalizer(subnetPort, servicecommon.SubnetPortFinalizerName)
@@ -105,6 +113,8 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			updateFail(r, &ctx, subnetPort, &err)
 			return common.ResultRequeue, err
 		}
+		// the realization of the NSX subnet port is checked before CreateOrUpdateSubnetPort returns
+		r.Latency.Realized(subnetPort.UID, subnetPort.Generation)
 		ipAddress := v1alpha1.SubnetPortIPAddress{
 			IP: *nsxSubnetPortState.RealizedBindings[0].Binding.IpAddress,
 		}
@@ -137,6 +147,7 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 				return common.ResultRequeue, err
 			}
 			log.Info("removed finalizer", "subnetport", req.NamespacedName)
+			r.Latency.Forget(subnetPort.UID)
 			deleteSuccess(r, &ctx, subnetPort)
 		} else {
 			log.Info("finalizers cannot be recognized", "subnetport", req.NamespacedName)
@@ -148,7 +159,7 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 // SetupWithManager sets up the controller with the Manager.
 func (r *SubnetPortReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.SubnetPort{}).
+		For(&v1alpha1.SubnetPort{}, builder.WithPredicates(r.Latency.Predicate())).
 		WithEventFilter(
 			predicate.Funcs{
 				DeleteFunc: func(e event.DeleteEvent) bool {
@@ -207,6 +218,7 @@ func StartSubnetPortController(mgr ctrl.Manager, subnetPortService *subnetport.S
 		SubnetPortService: subnetPortService,
 		VPCService:        vpcService,
 		Recorder:          mgr.GetEventRecorderFor("subnetport-controller"),
+		Latency:           common.NewRealizationLatency(MetricResTypeSubnetPort, subnetPortService.NSXConfig),
 	}
 	if err := subnetPortReconciler.Start(mgr); err != nil {
 		log.Error(err, "failed to create controller", "controller", "SubnetPort")
@@ -220,6 +232,10 @@ func (r *SubnetPortReconciler) Start(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	r.Tiers = common.NewTierGate(common.TierGroups, MetricResTypeSubnetPort, mgr.GetClient(), r.SubnetPortService.NSXConfig)
+	common.RegisterTierCheck(common.TierGroups, MetricResTypeSubnetPort, common.ReadyConditionPending(mgr.GetClient(),
+		func() client.ObjectList { return &v1alpha1.SubnetPortList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.SubnetPort).Status.Conditions }))
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
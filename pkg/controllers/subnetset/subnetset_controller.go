@@ -22,7 +22,6 @@ import (
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 
-	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
@@ -333,12 +332,12 @@ func (r *SubnetSetReconciler) Start(mgr ctrl.Manager, enableWebhook bool) error
 	if err != nil {
 		return err
 	}
+	common.RegisterTierCheck(common.TierNetwork, MetricResTypeSubnetSet, common.ReadyConditionPending(mgr.GetClient(),
+		func() client.ObjectList { return &v1alpha1.SubnetSetList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.SubnetSet).Status.Conditions }))
 	if enableWebhook {
-		hookServer := webhook.NewServer(webhook.Options{
-			Port:    config.WebhookServerPort,
-			CertDir: config.WebhookCertDir,
-		})
-		if err := mgr.Add(hookServer); err != nil {
+		hookServer, err := common.GetWebhookServer(mgr)
+		if err != nil {
 			return err
 		}
 		hookServer.Register("/validate-nsx-vmware-com-v1alpha1-subnetset",
@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpc
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// VPC controller should watch event of namespace, when there are some updates of namespace labels,
+// controller should reconcile the VPC CRs in the namespace to sync the labels to the NSX VPC tags.
+
+type EnqueueRequestForNamespace struct {
+	Client client.Client
+}
+
+func (e *EnqueueRequestForNamespace) Create(_ context.Context, _ event.CreateEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("namespace create event, do nothing")
+}
+
+func (e *EnqueueRequestForNamespace) Delete(_ context.Context, _ event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("namespace delete event, do nothing")
+}
+
+func (e *EnqueueRequestForNamespace) Generic(_ context.Context, _ event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	log.V(1).Info("namespace generic event, do nothing")
+}
+
+func (e *EnqueueRequestForNamespace) Update(_ context.Context, updateEvent event.UpdateEvent, l workqueue.RateLimitingInterface) {
+	obj := updateEvent.ObjectNew.(*v1.Namespace)
+	if err := reconcileVPC(e.Client, obj.Name, l); err != nil {
+		log.Error(err, "failed to reconcile VPC", "Namespace", obj.Name)
+	}
+}
+
+var PredicateFuncsNs = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj := e.ObjectOld.(*v1.Namespace)
+		newObj := e.ObjectNew.(*v1.Namespace)
+		if reflect.DeepEqual(oldObj.ObjectMeta.Labels, newObj.ObjectMeta.Labels) {
+			return false
+		}
+		log.V(1).Info("labels of namespace changed, reconcile its VPC", "Namespace", newObj.Name)
+		return true
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+}
+
+func reconcileVPC(c client.Client, namespace string, q workqueue.RateLimitingInterface) error {
+	vpcList := &v1alpha1.VPCList{}
+	if err := c.List(context.Background(), vpcList, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for _, vpc := range vpcList.Items {
+		log.Info("reconcile VPC CR due to namespace labels update", "VPC", vpc.Name, "Namespace", vpc.Namespace)
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      vpc.Name,
+				Namespace: vpc.Namespace,
+			},
+		})
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
@@ -153,6 +154,12 @@ func (r *VPCReconciler) setupWithManager(mgr ctrl.Manager) error {
 				vpcService: r.Service,
 			},
 			builder.WithPredicates(VPCNetworkConfigurationPredicate)).
+		Watches(
+			// For the updated labels of namespace, sync the labels to the tags of NSX VPC.
+			&v1.Namespace{},
+			&EnqueueRequestForNamespace{Client: mgr.GetClient()},
+			builder.WithPredicates(PredicateFuncsNs),
+		).
 		Complete(r)
 }
 
@@ -162,6 +169,9 @@ func (r *VPCReconciler) Start(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	common.RegisterTierCheck(common.TierNetwork, MetricResType, common.ReadyConditionPending(mgr.GetClient(),
+		func() client.ObjectList { return &v1alpha1.VPCList{} },
+		func(obj client.Object) []v1alpha1.Condition { return obj.(*v1alpha1.VPC).Status.Conditions }))
 
 	go r.GarbageCollector(make(chan bool), commonservice.GCInterval)
 	return nil
@@ -164,6 +164,7 @@ func buildNetworkConfigInfo(vpcConfigCR v1alpha1.VPCNetworkConfiguration) (*type
 		DefaultIPv4SubnetSize:   vpcConfigCR.Spec.DefaultIPv4SubnetSize,
 		DefaultSubnetAccessMode: vpcConfigCR.Spec.DefaultSubnetAccessMode,
 		ShortID:                 vpcConfigCR.Spec.ShortID,
+		NSXDomain:               vpcConfigCR.Spec.NSXDomain,
 	}
 	return ninfo, nil
 }
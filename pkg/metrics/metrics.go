@@ -11,17 +11,41 @@ import (
 )
 
 const (
-	MetricNamespace                 = "nsx"
-	MetricSubsystem                 = "operator"
-	HealthKey                       = "health_status"
-	ControllerSyncTotalKey          = "controller_sync_total"
-	ControllerUpdateTotalKey        = "controller_update_total"
-	ControllerUpdateSuccessTotalKey = "controller_update_success_total"
-	ControllerUpdateFailTotalKey    = "controller_update_fail_total"
-	ControllerDeleteTotalKey        = "controller_delete_total"
-	ControllerDeleteSuccessTotalKey = "controller_delete_success_total"
-	ControllerDeleteFailTotalKey    = "controller_delete_fail_total"
-	ScrapeTimeout                   = 30
+	MetricNamespace                       = "nsx"
+	MetricSubsystem                       = "operator"
+	HealthKey                             = "health_status"
+	ControllerSyncTotalKey                = "controller_sync_total"
+	ControllerUpdateTotalKey              = "controller_update_total"
+	ControllerUpdateSuccessTotalKey       = "controller_update_success_total"
+	ControllerUpdateFailTotalKey          = "controller_update_fail_total"
+	ControllerDeleteTotalKey              = "controller_delete_total"
+	ControllerDeleteSuccessTotalKey       = "controller_delete_success_total"
+	ControllerDeleteFailTotalKey          = "controller_delete_fail_total"
+	ControllerReconcileCoalescedTotalKey  = "controller_reconcile_coalesced_total"
+	ControllerGroupUpdateDampenedTotalKey = "controller_group_update_dampened_total"
+	ReconcileQueueDepthKey                = "reconcile_queue_depth"
+	ReconcileOldestItemAgeKey             = "reconcile_oldest_item_age_seconds"
+	ReconcileStalenessKey                 = "reconcile_staleness_seconds"
+	ReconcileStalledKey                   = "reconcile_stalled"
+	NSXAPIErrorTotalKey                   = "nsx_api_error_total"
+	NSXObjectCountKey                     = "nsx_object_count"
+	NSXObjectCountTotalKey                = "nsx_object_count_total"
+	SecurityPolicyRuleCountKey            = "securitypolicy_rule_count"
+	SecurityPolicyRuleHitCountKey         = "securitypolicy_rule_hit_count"
+	SecurityPolicyRuleByteCountKey        = "securitypolicy_rule_byte_count"
+	MassDeletionPausedKey                 = "mass_deletion_paused"
+	ControllerWarmupSecondsKey            = "controller_warmup_seconds"
+	RealizationLatencySecondsKey          = "realization_latency_seconds"
+	LeaderElectionTransitionsTotalKey     = "leader_election_transitions_total"
+	LeaderStatusKey                       = "leader_status"
+	StoreRebuildDurationSecondsKey        = "store_rebuild_duration_seconds"
+	StoreInitFailureTotalKey              = "store_init_failure_total"
+	NSXMaintenanceKey                     = "nsx_maintenance"
+	ShardOwnedKey                         = "shard_owned"
+	DeletionStuckTotalKey                 = "deletion_stuck_total"
+	NSXCapacityUsagePercentKey            = "nsx_capacity_usage_percent"
+	NSXCapacityPressureKey                = "nsx_capacity_pressure"
+	ScrapeTimeout                         = 30
 )
 
 var log = logger.Log
@@ -98,6 +122,224 @@ var (
 		},
 		[]string{"res_type"},
 	)
+	ControllerReconcileCoalescedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ControllerReconcileCoalescedTotalKey,
+			Help:      "Total number of reconciles deferred by NSX Operator to coalesce successive K8s update events",
+		},
+		[]string{"res_type"},
+	)
+	ControllerGroupUpdateDampenedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ControllerGroupUpdateDampenedTotalKey,
+			Help:      "Total number of Pod events batched by NSX Operator into a pending update of the IP address groups",
+		},
+		[]string{"res_type"},
+	)
+	ReconcileQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ReconcileQueueDepthKey,
+			Help:      "Number of K8s resources which have pending changes not yet synchronized by NSX Operator",
+		},
+		[]string{"res_type"},
+	)
+	ReconcileOldestItemAge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ReconcileOldestItemAgeKey,
+			Help:      "Age in seconds of the oldest pending change not yet synchronized by NSX Operator",
+		},
+		[]string{"res_type"},
+	)
+	ReconcileStaleness = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ReconcileStalenessKey,
+			Help:      "Seconds since the spec of a K8s resource changed without a successful sync by NSX Operator",
+		},
+		[]string{"res_type", "namespace", "name"},
+	)
+	ReconcileStalled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ReconcileStalledKey,
+			Help:      "1 if the reconcile queue of the resource type is considered stalled, otherwise 0",
+		},
+		[]string{"res_type"},
+	)
+	NSXAPIErrorTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXAPIErrorTotalKey,
+			Help:      "Total number of errors returned by NSX API when reconciling the K8s resources",
+		},
+		[]string{"res_type", "error_code", "module"},
+	)
+	NSXObjectCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXObjectCountKey,
+			Help:      "Number of NSX objects created by NSX Operator in a K8s namespace",
+		},
+		[]string{"object_type", "namespace"},
+	)
+	NSXObjectCountTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXObjectCountTotalKey,
+			Help:      "Total number of NSX objects created by NSX Operator",
+		},
+		[]string{"object_type"},
+	)
+	SecurityPolicyRuleCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SecurityPolicyRuleCountKey,
+			Help:      "Number of NSX rules realized by NSX Operator in a K8s namespace by rule action and direction",
+		},
+		[]string{"namespace", "action", "direction"},
+	)
+	SecurityPolicyRuleHitCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SecurityPolicyRuleHitCountKey,
+			Help:      "Hits of a rule of a SecurityPolicy counted by NSX, reset when the NSX rules are recreated",
+		},
+		[]string{"namespace", "securitypolicy", "rule"},
+	)
+	SecurityPolicyRuleByteCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SecurityPolicyRuleByteCountKey,
+			Help:      "Bytes processed by a rule of a SecurityPolicy counted by NSX, reset when the NSX rules are recreated",
+		},
+		[]string{"namespace", "securitypolicy", "rule"},
+	)
+	MassDeletionPaused = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      MassDeletionPausedKey,
+			Help:      "Number of NSX objects whose bulk deletion by NSX Operator is paused until confirmed, 0 if not paused",
+		},
+		[]string{"res_type"},
+	)
+	ControllerWarmupSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ControllerWarmupSecondsKey,
+			Help:      "Seconds from the start of NSX Operator until the NSX stores and the K8s caches of a controller are ready",
+		},
+		[]string{"res_type"},
+	)
+	RealizationLatencySeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      RealizationLatencySecondsKey,
+			Help:      "Seconds from a spec change of a K8s resource until its realization is confirmed by NSX",
+			Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300, 600},
+		},
+		[]string{"res_type"},
+	)
+	LeaderElectionTransitionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      LeaderElectionTransitionsTotalKey,
+			Help:      "Total number of times NSX Operator acquired or released the leadership, by 'event'",
+		},
+		[]string{"event"},
+	)
+	LeaderStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      LeaderStatusKey,
+			Help:      "1 if NSX Operator is the leader, otherwise 0",
+		},
+		[]string{},
+	)
+	StoreRebuildDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      StoreRebuildDurationSecondsKey,
+			Help:      "Seconds taken by NSX Operator to query the NSX resources of a type and rebuild its store",
+			Buckets:   []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"res_type"},
+	)
+	StoreInitFailureTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      StoreInitFailureTotalKey,
+			Help:      "Total number of failed NSX queries when NSX Operator rebuilds the store of a resource type",
+		},
+		[]string{"res_type"},
+	)
+	NSXMaintenance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXMaintenanceKey,
+			Help:      "1 if NSX is in maintenance mode and NSX Operator pauses the write operations to NSX, otherwise 0",
+		},
+		[]string{},
+	)
+	ShardOwned = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      ShardOwnedKey,
+			Help:      "1 if a replica of NSX Operator holds the leadership of the 'shard', otherwise 0",
+		},
+		[]string{"shard"},
+	)
+	DeletionStuckTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      DeletionStuckTotalKey,
+			Help:      "Total number of K8s resources stuck deleting due to NSX errors, by the 'action' escalated, i.e. alerted or forced",
+		},
+		[]string{"res_type", "action"},
+	)
+	NSXCapacityUsagePercent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXCapacityUsagePercentKey,
+			Help:      "Usage percentage of the max supported count of an NSX object type reported by the NSX capacity dashboard",
+		},
+		[]string{"usage_type"},
+	)
+	NSXCapacityPressure = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      NSXCapacityPressureKey,
+			Help:      "1 if the usage of an NSX object type exceeds the NSX warning threshold or the safety margin, 2 if NSX Operator refuses to create more of it, otherwise 0",
+		},
+		[]string{"usage_type"},
+	)
 )
 
 var registerMetrics sync.Once
@@ -121,10 +363,37 @@ func InitializePrometheusMetrics() {
 		ControllerDeleteTotal,
 		ControllerDeleteSuccessTotal,
 		ControllerDeleteFailTotal,
+		ControllerReconcileCoalescedTotal,
+		ControllerGroupUpdateDampenedTotal,
+		ReconcileQueueDepth,
+		ReconcileOldestItemAge,
+		ReconcileStaleness,
+		ReconcileStalled,
+		NSXAPIErrorTotal,
+		NSXObjectCount,
+		NSXObjectCountTotal,
+		SecurityPolicyRuleCount,
+		SecurityPolicyRuleHitCount,
+		SecurityPolicyRuleByteCount,
+		MassDeletionPaused,
+		ControllerWarmupSeconds,
+		RealizationLatencySeconds,
+		LeaderElectionTransitionsTotal,
+		LeaderStatus,
+		StoreRebuildDurationSeconds,
+		StoreInitFailureTotal,
+		NSXMaintenance,
+		ShardOwned,
+		DeletionStuckTotal,
+		NSXCapacityUsagePercent,
+		NSXCapacityPressure,
 	)
 }
 
 func AreMetricsExposed(cf *config.NSXOperatorConfig) bool {
+	if cf == nil || cf.NsxConfig == nil {
+		return false
+	}
 	if cf.EnforcementPoint == "vmc-enforcementpoint" {
 		return true
 	}
@@ -136,3 +405,27 @@ func CounterInc(cf *config.NSXOperatorConfig, counter *prometheus.CounterVec, re
 		counter.WithLabelValues(res_type).Inc()
 	}
 }
+
+func CounterIncWithLabels(cf *config.NSXOperatorConfig, counter *prometheus.CounterVec, labels ...string) {
+	if AreMetricsExposed(cf) {
+		counter.WithLabelValues(labels...).Inc()
+	}
+}
+
+func GaugeSet(cf *config.NSXOperatorConfig, gauge *prometheus.GaugeVec, value float64, labels ...string) {
+	if AreMetricsExposed(cf) {
+		gauge.WithLabelValues(labels...).Set(value)
+	}
+}
+
+func GaugeDelete(cf *config.NSXOperatorConfig, gauge *prometheus.GaugeVec, labels ...string) {
+	if AreMetricsExposed(cf) {
+		gauge.DeleteLabelValues(labels...)
+	}
+}
+
+func HistogramObserve(cf *config.NSXOperatorConfig, histogram *prometheus.HistogramVec, value float64, labels ...string) {
+	if AreMetricsExposed(cf) {
+		histogram.WithLabelValues(labels...).Observe(value)
+	}
+}
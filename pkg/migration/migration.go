@@ -0,0 +1,226 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+var (
+	log        = logger.Log
+	lock       = &sync.Mutex{}
+	migrations []Migration
+)
+
+// Migration migrates the NSX resources created by an older nsx-operator to the conventions
+// of a newer version, e.g. tag scopes, ID formats or policy category placement.
+// Migrate must be idempotent, since it is retried on the next startup if it fails, and it is
+// responsible for updating the version tag of the resources it has migrated, see UpdateVersionTag.
+type Migration interface {
+	// Version is the nsx-operator version which the migration upgrades the NSX resources to, e.g. "1.1.0"
+	Version() string
+	// Description is a short sentence logged when the migration runs
+	Description() string
+	// Migrate is called with the version of the NSX resources detected on startup
+	Migrate(ctx context.Context, service common.Service, from string) error
+}
+
+// Register adds a migration, it is expected to be called in init() of the package implementing it.
+func Register(m Migration) {
+	lock.Lock()
+	defer lock.Unlock()
+	migrations = append(migrations, m)
+}
+
+// Run detects the version of the NSX resources created for the cluster and runs, in version order,
+// the registered migrations which are newer than the detected version but not newer than the
+// running nsx-operator. The NSX resources without version tag are not taken into account, so
+// nothing is migrated on a fresh installation. The NSX resources are not searched if no
+// registered migration applies to the running nsx-operator.
+func Run(ctx context.Context, service common.Service) error {
+	current := strings.Join(common.TagValueVersion, ".")
+	candidates, err := pendingMigrations("0", current)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		log.V(1).Info("no migration registered, skip migration", "operatorVersion", current)
+		return nil
+	}
+	from, err := DetectVersion(service)
+	if err != nil {
+		return fmt.Errorf("failed to detect version of NSX resources: %w", err)
+	}
+	if from == "" {
+		log.Info("no versioned NSX resources found, skip migration")
+		return nil
+	}
+	pending, err := pendingMigrations(from, current)
+	if err != nil {
+		return err
+	}
+	log.Info("detected version of NSX resources", "version", from, "operatorVersion", current, "migrations", len(pending))
+	for _, m := range pending {
+		log.Info("running migration", "version", m.Version(), "description", m.Description())
+		if err := m.Migrate(ctx, service, from); err != nil {
+			return fmt.Errorf("migration to %s failed: %w", m.Version(), err)
+		}
+		log.Info("migration finished", "version", m.Version())
+	}
+	return nil
+}
+
+func pendingMigrations(from, to string) ([]Migration, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	var pending []Migration
+	for _, m := range migrations {
+		fromCmp, err := CompareVersion(m.Version(), from)
+		if err != nil {
+			return nil, err
+		}
+		toCmp, err := CompareVersion(m.Version(), to)
+		if err != nil {
+			return nil, err
+		}
+		if fromCmp > 0 && toCmp <= 0 {
+			pending = append(pending, m)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		c, _ := CompareVersion(pending[i].Version(), pending[j].Version())
+		return c < 0
+	})
+	return pending, nil
+}
+
+// DetectVersion returns the lowest version tagged on the NSX resources created for the cluster,
+// it returns empty string if there is no versioned NSX resource.
+func DetectVersion(service common.Service) (string, error) {
+	queryParam := fmt.Sprintf("tags.scope:%s AND tags.tag:%s AND tags.scope:%s",
+		strings.Replace(common.TagScopeCluster, "/", "\\/", -1),
+		strings.Replace(service.NSXConfig.Cluster, ":", "\\:", -1),
+		strings.Replace(common.TagScopeVersion, "/", "\\/", -1))
+	store := &versionStore{versions: sets.New[string]()}
+	if _, err := service.SearchResource("", queryParam, store, nil); err != nil {
+		return "", err
+	}
+	lowest := ""
+	for v := range store.versions {
+		if lowest == "" {
+			lowest = v
+			continue
+		}
+		c, err := CompareVersion(v, lowest)
+		if err != nil {
+			return "", err
+		}
+		if c < 0 {
+			lowest = v
+		}
+	}
+	return lowest, nil
+}
+
+// CompareVersion compares two dotted versions, it returns -1, 0 or 1 if a is lower than,
+// equal to or higher than b.
+func CompareVersion(a, b string) (int, error) {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := 0, 0
+		var err error
+		if i < len(as) {
+			if x, err = strconv.Atoi(as[i]); err != nil {
+				return 0, fmt.Errorf("invalid version %q", a)
+			}
+		}
+		if i < len(bs) {
+			if y, err = strconv.Atoi(bs[i]); err != nil {
+				return 0, fmt.Errorf("invalid version %q", b)
+			}
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// UpdateVersionTag sets the version tag to the version of the running nsx-operator,
+// the tag is appended if it doesn't exist.
+func UpdateVersionTag(tags []model.Tag) []model.Tag {
+	version := strings.Join(common.TagValueVersion, ".")
+	for i := range tags {
+		if tags[i].Scope != nil && *tags[i].Scope == common.TagScopeVersion {
+			tags[i].Tag = common.String(version)
+			return tags
+		}
+	}
+	return append(tags, model.Tag{Scope: common.String(common.TagScopeVersion), Tag: common.String(version)})
+}
+
+// versionStore only collects the version tags of the searched resources,
+// the resource types are heterogeneous so the tags are read from the raw StructValue.
+type versionStore struct {
+	versions sets.Set[string]
+}
+
+func (s *versionStore) TransResourceToStore(entity *data.StructValue) error {
+	tagsValue, err := entity.Field("tags")
+	if err != nil {
+		return nil
+	}
+	tagList, ok := tagsValue.(*data.ListValue)
+	if !ok {
+		return nil
+	}
+	for _, v := range tagList.List() {
+		tag, ok := v.(*data.StructValue)
+		if !ok {
+			continue
+		}
+		if fieldString(tag, "scope") == common.TagScopeVersion {
+			s.versions.Insert(fieldString(tag, "tag"))
+		}
+	}
+	return nil
+}
+
+func fieldString(entity *data.StructValue, field string) string {
+	value, err := entity.Field(field)
+	if err != nil {
+		return ""
+	}
+	if str, ok := value.(*data.StringValue); ok {
+		return str.Value()
+	}
+	return ""
+}
+
+func (s *versionStore) ListIndexFuncValues(_ string) sets.Set[string] {
+	return s.versions
+}
+
+func (s *versionStore) Apply(_ interface{}) error {
+	return nil
+}
+
+func (s *versionStore) IsPolicyAPI() bool {
+	return true
+}
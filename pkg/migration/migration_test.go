@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeMigration struct {
+	version string
+}
+
+func (m *fakeMigration) Version() string     { return m.version }
+func (m *fakeMigration) Description() string { return "fake migration " + m.version }
+func (m *fakeMigration) Migrate(_ context.Context, _ common.Service, _ string) error {
+	return nil
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{"1.0.0", "1.0.0", 0, false},
+		{"1.0", "1.0.0", 0, false},
+		{"1.0.1", "1.0.0", 1, false},
+		{"1.2.0", "1.10.0", -1, false},
+		{"2", "1.9.9", 1, false},
+		{"1.x", "1.0", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := CompareVersion(tt.a, tt.b)
+		if tt.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations = nil
+	defer func() { migrations = nil }()
+	Register(&fakeMigration{version: "1.2.0"})
+	Register(&fakeMigration{version: "0.9.0"})
+	Register(&fakeMigration{version: "1.1.0"})
+	Register(&fakeMigration{version: "1.0.0"})
+
+	pending, err := pendingMigrations("0.9.0", "1.1.0")
+	assert.NoError(t, err)
+	var versions []string
+	for _, m := range pending {
+		versions = append(versions, m.Version())
+	}
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, versions)
+
+	pending, err = pendingMigrations("1.2.0", "1.2.0")
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRun(t *testing.T) {
+	migrations = nil
+	defer func() { migrations = nil }()
+	detected := 0
+	patches := gomonkey.ApplyFunc(DetectVersion, func(_ common.Service) (string, error) {
+		detected++
+		return "0.9.0", nil
+	})
+	defer patches.Reset()
+
+	// No migration registered, the NSX resources are not searched
+	assert.NoError(t, Run(context.TODO(), common.Service{}))
+	assert.Equal(t, 0, detected)
+
+	// The migration is newer than the running nsx-operator
+	Register(&fakeMigration{version: "2.0.0"})
+	assert.NoError(t, Run(context.TODO(), common.Service{}))
+	assert.Equal(t, 0, detected)
+
+	Register(&fakeMigration{version: "1.0.0"})
+	assert.NoError(t, Run(context.TODO(), common.Service{}))
+	assert.Equal(t, 1, detected)
+}
+
+func TestUpdateVersionTag(t *testing.T) {
+	version := "1.0.0"
+	tags := UpdateVersionTag([]model.Tag{{Scope: common.String(common.TagScopeCluster), Tag: common.String("k8scl-one")}})
+	assert.Equal(t, 2, len(tags))
+	assert.Equal(t, version, *tags[1].Tag)
+
+	old := "0.1.0"
+	tags = UpdateVersionTag([]model.Tag{{Scope: common.String(common.TagScopeVersion), Tag: &old}})
+	assert.Equal(t, 1, len(tags))
+	assert.Equal(t, version, *tags[0].Tag)
+}
+
+func TestVersionStore(t *testing.T) {
+	newTag := func(scope, tag string) *data.StructValue {
+		return data.NewStructValue("", map[string]data.DataValue{
+			"scope": data.NewStringValue(scope),
+			"tag":   data.NewStringValue(tag),
+		})
+	}
+	tags := data.NewListValue()
+	tags.Add(newTag(common.TagScopeCluster, "k8scl-one"))
+	tags.Add(newTag(common.TagScopeVersion, "0.9.0"))
+	entity := data.NewStructValue("", map[string]data.DataValue{"tags": tags})
+
+	store := &versionStore{versions: sets.New[string]()}
+	assert.NoError(t, store.TransResourceToStore(entity))
+	assert.NoError(t, store.TransResourceToStore(data.NewStructValue("", map[string]data.DataValue{})))
+	assert.Equal(t, sets.New[string]("0.9.0"), store.ListIndexFuncValues(""))
+}
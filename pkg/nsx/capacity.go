@@ -0,0 +1,16 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+// CapacityUsage returns the usage of the NSX object types and their max supported counts from the capacity
+// dashboard of NSX, which is refreshed by NSX periodically.
+func (client *Client) CapacityUsage() ([]model.CapacityDashboardUsage, error) {
+	response, err := client.CapacityUsageClient.Get(nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return response.CapacityUsage, nil
+}
@@ -5,8 +5,10 @@ package nsx
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	vspherelog "github.com/vmware/vsphere-automation-sdk-go/runtime/log"
@@ -15,13 +17,21 @@ import (
 	mpsearch "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-mp/nsx/search"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-mp/nsx/trust_management"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-mp/nsx/trust_management/principal_identities"
+	policyinfra "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/capacity/dashboard"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains"
+	group_members "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains/groups/members"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains/security_policies"
+	infra_realized_state "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/realized_state"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/settings/firewall/security"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/settings/firewall/security/intrusion_services"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/sites"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/sites/enforcement_points"
 	projects "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects"
 	infra "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/realized_state"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	vpc_group_members "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/groups/members"
 	nat "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/nat"
 	vpc_sp "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/security_policies"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/subnets"
@@ -31,9 +41,13 @@ import (
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/schema"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
+// defaultSessionRenewInterval renews the NSX auth session before NSX expires it after 30 minutes by default.
+const defaultSessionRenewInterval = 25 * time.Minute
+
 const (
 	VPC = iota
 	SecurityPolicy
@@ -57,7 +71,9 @@ type Client struct {
 	SecurityClient domains.SecurityPoliciesClient
 	RuleClient     security_policies.RulesClient
 	InfraClient    nsx_policy.InfraClient
+	DomainClient   policyinfra.DomainsClient
 
+	EnforcementPointClient     sites.EnforcementPointsClient
 	ClusterControlPlanesClient enforcement_points.ClusterControlPlanesClient
 	HostTransPortNodesClient   enforcement_points.HostTransportNodesClient
 	SubnetStatusClient         subnets.StatusClient
@@ -85,8 +101,37 @@ type Client struct {
 	SubnetsClient       vpcs.SubnetsClient
 	RealizedStateClient realized_state.RealizedEntitiesClient
 
+	// InfraRealizedStateClient queries the realized state of the intents under /infra, without VPC
+	InfraRealizedStateClient infra_realized_state.RealizedEntitiesClient
+
+	// DraftsClient stages the changes of the SecurityPolicies in a DFW draft and publishes it, without VPC
+	DraftsClient policyinfra.DraftsClient
+
+	// SecurityStatisticsClient and VPCSecurityStatisticsClient read the hit statistics of the rules of a SecurityPolicy
+	SecurityStatisticsClient    security_policies.StatisticsClient
+	VPCSecurityStatisticsClient vpc_sp.StatisticsClient
+
+	// ExcludeListClient manages the members of the DFW exclusion list, without VPC
+	ExcludeListClient security.ExcludeListClient
+
+	// IDSProfileClient manages the IDS profiles of the IDSPolicies, without VPC
+	IDSProfileClient intrusion_services.ProfilesClient
+
+	// CapacityUsageClient reads the usage of the NSX object types from the capacity dashboard
+	CapacityUsageClient dashboard.UsageClient
+
+	// GroupIPMembersClient, GroupPortMembersClient and their VPC counterparts read the effective members of the groups
+	GroupIPMembersClient      group_members.IpAddressesClient
+	GroupPortMembersClient    group_members.SegmentPortsClient
+	VPCGroupIPMembersClient   vpc_group_members.IpAddressesClient
+	VPCGroupPortMembersClient vpc_group_members.SubnetPortsClient
+
 	NSXChecker    NSXHealthChecker
 	NSXVerChecker NSXVersionChecker
+
+	// Schema is the OpenAPI schema of NSX Manager the payloads are validated against, it's nil unless loaded by
+	// LoadSchema.
+	Schema *schema.Schema
 }
 
 var (
@@ -121,6 +166,10 @@ func restConnector(c *Cluster) *client.RestConnector {
 	return connector
 }
 
+func restConnectorFor(c *Cluster, subsystem string) *client.RestConnector {
+	return c.NewRestConnectorFor(subsystem)
+}
+
 func GetClient(cf *config.NSXOperatorConfig) *Client {
 	// Set log level for vsphere-automation-sdk-go
 	logger := logrus.New()
@@ -132,15 +181,31 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 	c := NewConfig(strings.Join(cf.NsxApiManagers, ","), cf.NsxApiUser, cf.NsxApiPassword, cf.CaFile, 10, 3, defaultHttpTimeout, 20, true, true, true,
 		ratelimiter.AIMD, cf.GetTokenProvider(), nil, cf.Thumbprint)
 	c.EnvoyHost = cf.EnvoyHost
+	c.SessionRenewInterval = defaultSessionRenewInterval
+	if cf.SessionRenewInterval > 0 {
+		c.SessionRenewInterval = time.Duration(cf.SessionRenewInterval) * time.Second
+	} else if cf.SessionRenewInterval < 0 {
+		c.SessionRenewInterval = 0
+	}
 	c.EnvoyPort = cf.EnvoyPort
+	if shares, err := cf.GetAPIRateShares(); err != nil {
+		log.Error(err, "invalid API rate shares, the NSX API throughput is not partitioned")
+	} else {
+		c.APIRateShares = shares
+	}
 	cluster, _ := NewCluster(c)
 
 	queryClient := search.NewQueryClient(restConnector(cluster))
-	groupClient := domains.NewGroupsClient(restConnector(cluster))
-	securityClient := domains.NewSecurityPoliciesClient(restConnector(cluster))
-	ruleClient := security_policies.NewRulesClient(restConnector(cluster))
-	infraClient := nsx_policy.NewInfraClient(restConnector(cluster))
+	groupClient := domains.NewGroupsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	securityClient := domains.NewSecurityPoliciesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	ruleClient := security_policies.NewRulesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	// InfraClient is mostly used to realize the SecurityPolicies, OrgRootClient is shared by the SecurityPolicies
+	// and the Subnets in VPC mode, so it's accounted to the subsystem other.
+	infraClient := nsx_policy.NewInfraClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+
+	domainClient := policyinfra.NewDomainsClient(restConnector(cluster))
 
+	enforcementPointClient := sites.NewEnforcementPointsClient(restConnector(cluster))
 	clusterControlPlanesClient := enforcement_points.NewClusterControlPlanesClient(restConnector(cluster))
 	hostTransportNodesClient := enforcement_points.NewHostTransportNodesClient(restConnector(cluster))
 	realizedEntitiesClient := realized_state.NewRealizedEntitiesClient(restConnector(cluster))
@@ -151,21 +216,32 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 
 	orgRootClient := nsx_policy.NewOrgRootClient(restConnector(cluster))
 	projectInfraClient := projects.NewInfraClient(restConnector(cluster))
-	vpcClient := projects.NewVpcsClient(restConnector(cluster))
-	ipBlockClient := infra.NewIpBlocksClient(restConnector(cluster))
-	staticRouteClient := vpcs.NewStaticRoutesClient(restConnector(cluster))
-	natRulesClient := nat.NewNatRulesClient(restConnector(cluster))
-	vpcGroupClient := vpcs.NewGroupsClient(restConnector(cluster))
-	portClient := subnets.NewPortsClient(restConnector(cluster))
-	portStateClient := ports.NewStateClient(restConnector(cluster))
-	ipPoolClient := subnets.NewIpPoolsClient(restConnector(cluster))
-	ipAllocationClient := ip_pools.NewIpAllocationsClient(restConnector(cluster))
-	subnetsClient := vpcs.NewSubnetsClient(restConnector(cluster))
-	subnetStatusClient := subnets.NewStatusClient(restConnector(cluster))
+	vpcClient := projects.NewVpcsClient(restConnectorFor(cluster, ratelimiter.SubsystemVPC))
+	ipBlockClient := infra.NewIpBlocksClient(restConnectorFor(cluster, ratelimiter.SubsystemVPC))
+	staticRouteClient := vpcs.NewStaticRoutesClient(restConnectorFor(cluster, ratelimiter.SubsystemVPC))
+	natRulesClient := nat.NewNatRulesClient(restConnectorFor(cluster, ratelimiter.SubsystemVPC))
+	vpcGroupClient := vpcs.NewGroupsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	portClient := subnets.NewPortsClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
+	portStateClient := ports.NewStateClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
+	ipPoolClient := subnets.NewIpPoolsClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
+	ipAllocationClient := ip_pools.NewIpAllocationsClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
+	subnetsClient := vpcs.NewSubnetsClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
+	subnetStatusClient := subnets.NewStatusClient(restConnectorFor(cluster, ratelimiter.SubsystemSubnet))
 	realizedStateClient := realized_state.NewRealizedEntitiesClient(restConnector(cluster))
-
-	vpcSecurityClient := vpcs.NewSecurityPoliciesClient(restConnector(cluster))
-	vpcRuleClient := vpc_sp.NewRulesClient(restConnector(cluster))
+	infraRealizedStateClient := infra_realized_state.NewRealizedEntitiesClient(restConnector(cluster))
+	draftsClient := policyinfra.NewDraftsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+
+	vpcSecurityClient := vpcs.NewSecurityPoliciesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	vpcRuleClient := vpc_sp.NewRulesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	securityStatisticsClient := security_policies.NewStatisticsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	vpcSecurityStatisticsClient := vpc_sp.NewStatisticsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	excludeListClient := security.NewExcludeListClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	capacityUsageClient := dashboard.NewUsageClient(restConnector(cluster))
+	idsProfileClient := intrusion_services.NewProfilesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	groupIPMembersClient := group_members.NewIpAddressesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	groupPortMembersClient := group_members.NewSegmentPortsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	vpcGroupIPMembersClient := vpc_group_members.NewIpAddressesClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
+	vpcGroupPortMembersClient := vpc_group_members.NewSubnetPortsClient(restConnectorFor(cluster, ratelimiter.SubsystemSecurity))
 
 	nsxChecker := &NSXHealthChecker{
 		cluster: cluster,
@@ -183,7 +259,9 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 		SecurityClient:             securityClient,
 		RuleClient:                 ruleClient,
 		InfraClient:                infraClient,
+		DomainClient:               domainClient,
 		Cluster:                    cluster,
+		EnforcementPointClient:     enforcementPointClient,
 		ClusterControlPlanesClient: clusterControlPlanesClient,
 		HostTransPortNodesClient:   hostTransportNodesClient,
 		RealizedEntitiesClient:     realizedEntitiesClient,
@@ -211,6 +289,23 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 		IPAllocationClient:  ipAllocationClient,
 		SubnetsClient:       subnetsClient,
 		RealizedStateClient: realizedStateClient,
+
+		InfraRealizedStateClient: infraRealizedStateClient,
+
+		DraftsClient: draftsClient,
+
+		SecurityStatisticsClient:    securityStatisticsClient,
+		VPCSecurityStatisticsClient: vpcSecurityStatisticsClient,
+
+		ExcludeListClient: excludeListClient,
+		IDSProfileClient:  idsProfileClient,
+
+		CapacityUsageClient: capacityUsageClient,
+
+		GroupIPMembersClient:      groupIPMembersClient,
+		GroupPortMembersClient:    groupPortMembersClient,
+		VPCGroupIPMembersClient:   vpcGroupIPMembersClient,
+		VPCGroupPortMembersClient: vpcGroupPortMembersClient,
 	}
 	// NSX version check will be restarted during SecurityPolicy reconcile
 	// So, it's unnecessary to exit even if failed in the first time
@@ -267,6 +362,38 @@ func (client *Client) FeatureEnabled(feature int) bool {
 	return client.NSXVerChecker.featureSupported[feature] == true
 }
 
+// ValidateDomainAndEnforcementPoint checks the configured enforcement point and, without VPC, the domain
+// exist on NSX, so that a typo fails nsx-operator at startup instead of every realization.
+func (client *Client) ValidateDomainAndEnforcementPoint() error {
+	enforcementPoint := client.NsxConfig.GetEnforcementPoint()
+	if _, err := client.EnforcementPointClient.Get(config.DefaultSite, enforcementPoint); err != nil {
+		return fmt.Errorf("failed to get enforcement point %s of site %s: %w", enforcementPoint, config.DefaultSite, err)
+	}
+	if client.NsxConfig.EnableVPCNetwork {
+		return nil
+	}
+	domain := client.NsxConfig.GetDomain()
+	if _, err := client.DomainClient.Get(domain); err != nil {
+		return fmt.Errorf("failed to get domain %s: %w", domain, err)
+	}
+	return nil
+}
+
+// LoadSchema downloads the OpenAPI spec of the Policy API from NSX Manager, the payloads are validated against
+// it before they are sent.
+func (client *Client) LoadSchema() error {
+	doc, err := client.Cluster.HttpGet(PolicyOpenAPISpecAPI)
+	if err != nil {
+		return fmt.Errorf("failed to download NSX OpenAPI spec: %w", err)
+	}
+	s, err := schema.Load(doc)
+	if err != nil {
+		return err
+	}
+	client.Schema = s
+	return nil
+}
+
 // ValidateLicense validates NSX license. init is used to indicate whether nsx-operator is init or not
 // if not init, nsx-operator will check if license has been updated.
 // once license updated, operator will restart
@@ -4,10 +4,13 @@
 package nsx
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -40,6 +43,7 @@ const (
 	EnvoyUrlWithCert       = "http://%s:%d/external-cert/http1/%s"
 	EnvoyUrlWithThumbprint = "http://%s:%d/external-tp/http1/%s/%s"
 	LicenseAPI             = "api/v1/licenses/licensed-features"
+	PolicyOpenAPISpecAPI   = "policy/api/v1/spec/openapi/nsx_policy_api.json"
 )
 
 const (
@@ -85,6 +89,9 @@ func NewCluster(config *Config) (*Cluster, error) {
 	cluster.endpoints = eps
 	cluster.transport.endpoints = eps
 	cluster.transport.config = cluster.config
+	if len(config.APIRateShares) > 0 {
+		cluster.transport.partitioner = ratelimiter.NewPartitioner(config.APIRateShares)
+	}
 	cluster.loadCAforEnvoy()
 	for _, ep := range cluster.endpoints {
 		envoyUrl := cluster.CreateServerUrl(ep.Host(), ep.Scheme())
@@ -169,6 +176,15 @@ func (cluster *Cluster) NewRestConnector() (*policyclient.RestConnector, *Header
 	return connector, header
 }
 
+// NewRestConnectorFor creates a RestConnector used for the SDK clients of the subsystem, the API calls
+// sent by the clients are accounted to the subsystem when the API throughput is partitioned.
+func (cluster *Cluster) NewRestConnectorFor(subsystem string) *policyclient.RestConnector {
+	nsxtUrl := cluster.CreateServerUrl(cluster.endpoints[0].Host(), cluster.endpoints[0].Scheme())
+	client := *cluster.client
+	client.Transport = &subsystemTransport{base: cluster.client.Transport, subsystem: subsystem}
+	return policyclient.NewRestConnector(nsxtUrl, client)
+}
+
 func (cluster *Cluster) UsingEnvoy() bool {
 	return cluster.config.EnvoyPort != 0
 }
@@ -362,7 +378,7 @@ func (cluster *Cluster) GetVersion() (*NsxVersion, error) {
 
 // HttpGet sends a http GET request to the cluster, exported for use
 func (cluster *Cluster) HttpGet(url string) (map[string]interface{}, error) {
-	resp, err := cluster.httpAction(url, "GET")
+	resp, err := cluster.httpAction(url, "GET", nil)
 	if err != nil {
 		log.Error(err, "failed to do http GET operation")
 		return nil, err
@@ -372,15 +388,18 @@ func (cluster *Cluster) HttpGet(url string) (map[string]interface{}, error) {
 	return respJson, err
 }
 
-func (cluster *Cluster) httpAction(url, method string) (*http.Response, error) {
+func (cluster *Cluster) httpAction(url, method string, body io.Reader) (*http.Response, error) {
 	ep := cluster.endpoints[0]
 	serverUrl := cluster.CreateServerUrl(cluster.endpoints[0].Host(), cluster.endpoints[0].Scheme())
 	url = fmt.Sprintf("%s/%s", serverUrl, url)
-	req, err := http.NewRequest(method, url, nil)
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		log.Error(err, "failed to create http request")
 		return nil, err
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	log.V(1).Info(method+" url", "url", req.URL)
 	resp, err := ep.client.Do(req)
 	if err != nil {
@@ -391,7 +410,7 @@ func (cluster *Cluster) httpAction(url, method string) (*http.Response, error) {
 
 // HttpDelete sends a http DELETE request to the cluster, exported for use
 func (cluster *Cluster) HttpDelete(url string) error {
-	_, err := cluster.httpAction(url, "DELETE")
+	_, err := cluster.httpAction(url, "DELETE", nil)
 	if err != nil {
 		log.Error(err, "failed to do http DELETE operation")
 		return err
@@ -399,6 +418,21 @@ func (cluster *Cluster) HttpDelete(url string) error {
 	return nil
 }
 
+// HttpPatch sends a http PATCH request with the JSON body to the cluster, exported for use
+func (cluster *Cluster) HttpPatch(url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := cluster.httpAction(url, "PATCH", bytes.NewReader(data))
+	if err != nil {
+		log.Error(err, "failed to do http PATCH operation")
+		return err
+	}
+	err, _ = util.HandleHTTPResponse(resp, nil, true)
+	return err
+}
+
 func (nsxVersion *NsxVersion) Validate() error {
 	re, _ := regexp.Compile(`^([\d]+).([\d]+).([\d]+)`)
 	result := re.Find([]byte(nsxVersion.NodeVersion))
@@ -467,7 +501,7 @@ func (nsxVersion *NsxVersion) featureSupported(feature int) bool {
 }
 
 func (cluster *Cluster) FetchLicense() error {
-	resp, err := cluster.httpAction(LicenseAPI, "GET")
+	resp, err := cluster.httpAction(LicenseAPI, "GET", nil)
 	if err != nil {
 		log.Error(err, "failed to get nsx license")
 		return err
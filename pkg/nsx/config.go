@@ -5,6 +5,7 @@ package nsx
 
 import (
 	"strings"
+	"time"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/auth"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
@@ -50,13 +51,20 @@ type Config struct {
 	// sent, and will be decreased by half after 429/503 error for each period. The rate has hard max limit of
 	// min(100/s, param api_rate_limit_per_endpoint).
 	APIRateMode ratelimiter.Type
+	// Shares of the NSX API throughput of the subsystems, keyed by subsystem, e.g. security and subnet. If set,
+	// the rate limiter tokens are partitioned between the subsystems waiting for them in proportion to their shares.
+	APIRateShares map[string]int
 	// None, or instance of implemented AbstractJWTProvider which will return the JSON Web Token used in the requests
 	// in NSX for authorization.
 	TokenProvider auth.TokenProvider
 	// None, or ClientCertProvider object. If specified, client cert will be used instead of basic authentication.
 	ClientCertProvider auth.ClientCertProvider
-	EnvoyHost          string
-	EnvoyPort          int
+	// The auth session is created again after it's been used for SessionRenewInterval, before NSX expires it.
+	// If the session creation fails, the requests fall back to the basic auth until the session is created
+	// again. 0 disables the proactive renewal, the session is still created again when NSX expires it.
+	SessionRenewInterval time.Duration
+	EnvoyHost            string
+	EnvoyPort            int
 }
 
 // NewConfig creates a nsx configuration. It provides default values for those items not in function parameters.
@@ -51,6 +51,11 @@ type Endpoint struct {
 	caFile        string
 	Thumbprint    string
 	envoyUrl      string
+	// sessionLock serializes the creations of the auth session, sessionAttempted is the time of the last
+	// creation and sessionCreated of the last successful one.
+	sessionLock      sync.Mutex
+	sessionAttempted time.Time
+	sessionCreated   time.Time
 	sync.RWMutex
 	provider
 }
@@ -280,6 +285,19 @@ func (ep *Endpoint) createAuthSession(certProvider auth.ClientCertProvider, toke
 		return nil
 	}
 
+	ep.Lock()
+	ep.sessionAttempted = time.Now()
+	ep.Unlock()
+	if err := ep.doCreateAuthSession(username, password, jar); err != nil {
+		// the requests fall back to the basic auth until a session is created
+		ep.setXSRFToken("")
+		log.Info("falling back to basic auth", "endpoint", ep.Host())
+		return err
+	}
+	return nil
+}
+
+func (ep *Endpoint) doCreateAuthSession(username string, password string, jar *Jar) error {
 	u := &url.URL{Host: ep.Host(), Scheme: ep.Scheme()}
 	postValues := url.Values{}
 	postValues.Add("j_username", username)
@@ -329,12 +347,38 @@ func (ep *Endpoint) createAuthSession(certProvider auth.ClientCertProvider, toke
 	ep.Lock()
 	ep.noBalancerClient.Jar = jar
 	ep.client.Jar = jar
+	ep.sessionCreated = time.Now()
 	ep.Unlock()
 	ep.setStatus(UP)
 	log.Info("session creation succeeded", "endpoint", u.Host)
 	return nil
 }
 
+// renewAuthSession creates the auth session again unless it has been created after since, e.g. by the other
+// requests failing on the same expired session, so only one of them creates it.
+func (ep *Endpoint) renewAuthSession(certProvider auth.ClientCertProvider, tokenProvider auth.TokenProvider, username string, password string, jar *Jar, since time.Time) error {
+	ep.sessionLock.Lock()
+	defer ep.sessionLock.Unlock()
+	ep.RLock()
+	attempted := ep.sessionAttempted
+	ep.RUnlock()
+	if attempted.After(since) {
+		return nil
+	}
+	return ep.createAuthSession(certProvider, tokenProvider, username, password, jar)
+}
+
+// sessionRenewalDue returns whether the auth session has been created or attempted longer than interval ago,
+// it's renewed before NSX expires it, or created again after falling back to the basic auth.
+func (ep *Endpoint) sessionRenewalDue(interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+	ep.RLock()
+	defer ep.RUnlock()
+	return time.Since(ep.sessionAttempted) >= interval
+}
+
 func (ep *Endpoint) UpdateHttpRequestAuth(request *http.Request) error {
 	// retry if GetToken failed, wait for 120s to avoid user lock
 	// try 10 times
@@ -362,7 +406,7 @@ func (ep *Endpoint) UpdateHttpRequestAuth(request *http.Request) error {
 			if request.Header.Get("Authorization") != "" {
 				request.Header.Del("Authorization")
 			}
-			request.Header.Add("X-Xsrf-Token", ep.XSRFToken())
+			request.Header.Set("X-Xsrf-Token", xsrfToken)
 			url := &url.URL{Host: ep.Host()}
 			ep.Lock()
 			cookies := ep.client.Jar.Cookies(url)
@@ -375,6 +419,9 @@ func (ep *Endpoint) UpdateHttpRequestAuth(request *http.Request) error {
 			}
 		} else {
 			log.V(2).Info("update user/password")
+			// the request may be retried after the session is dropped
+			request.Header.Del("X-Xsrf-Token")
+			request.Header.Del("Cookie")
 			request.SetBasicAuth(ep.user, ep.password)
 		}
 	}
@@ -218,3 +218,56 @@ func TestKeepAlive(t *testing.T) {
 	ep.KeepAlive()
 	assert.Equal(ep.Status(), DOWN)
 }
+
+func TestRenewAuthSession(t *testing.T) {
+	created := 0
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		created++
+		w.Header().Set("X-Xsrf-Token", fmt.Sprintf("token%d", created))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	cluster := &Cluster{config: &Config{}}
+	tr := cluster.createTransport(10)
+	client := cluster.createHTTPClient(tr, 30)
+	noBClient := cluster.createNoBalancerClient(90, 90)
+	ep, err := NewEndpoint(ts.URL, client, noBClient, ratelimiter.NewFixRateLimiter(10), nil)
+	assert.NoError(t, err)
+	ep.setUserPassword("admin", "password")
+	jar := NewJar()
+
+	before := time.Now()
+	assert.NoError(t, ep.createAuthSession(nil, nil, "admin", "password", jar))
+	assert.Equal(t, "token1", ep.XSRFToken())
+	assert.False(t, ep.sessionRenewalDue(time.Hour))
+	assert.False(t, ep.sessionRenewalDue(0))
+	assert.True(t, ep.sessionRenewalDue(time.Nanosecond))
+
+	// the session renewed after the request started is reused
+	assert.NoError(t, ep.renewAuthSession(nil, nil, "admin", "password", jar, before))
+	assert.Equal(t, 1, created)
+	assert.NoError(t, ep.renewAuthSession(nil, nil, "admin", "password", jar, time.Now()))
+	assert.Equal(t, 2, created)
+	assert.Equal(t, "token2", ep.XSRFToken())
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	assert.NoError(t, ep.UpdateHttpRequestAuth(req))
+	assert.Equal(t, "token2", req.Header.Get("X-Xsrf-Token"))
+	_, _, ok := req.BasicAuth()
+	assert.False(t, ok)
+
+	// the request falls back to the basic auth if the session creation fails
+	fail = true
+	assert.Error(t, ep.renewAuthSession(nil, nil, "admin", "password", jar, time.Now()))
+	assert.Empty(t, ep.XSRFToken())
+	assert.NoError(t, ep.UpdateHttpRequestAuth(req))
+	assert.Empty(t, req.Header.Get("X-Xsrf-Token"))
+	user, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "password", password)
+}
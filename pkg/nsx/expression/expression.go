@@ -0,0 +1,237 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package expression builds the expressions of the NSX groups from typed nodes, instead of assembling the
+// fields of the data.StructValue in each service, and validates them against the NSX nesting limits before
+// they are sent to NSX.
+package expression
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+)
+
+// The resource types of the expression nodes.
+const (
+	TypeCondition           = "Condition"
+	TypeConjunctionOperator = "ConjunctionOperator"
+	TypeNestedExpression    = "NestedExpression"
+	TypeIPAddressExpression = "IPAddressExpression"
+	TypePathExpression      = "PathExpression"
+)
+
+// The conjunctions joining the expressions.
+const (
+	And = "AND"
+	Or  = "OR"
+)
+
+// The NSX limits of the nesting.
+const (
+	// MaxNestingDepth is the depth of the NestedExpressions, a NestedExpression can't contain another one.
+	MaxNestingDepth = 1
+	// MaxNestedConditions is the max count of the conditions in a NestedExpression.
+	MaxNestedConditions = 15
+)
+
+const resourceType = "resource_type"
+
+// Condition is a condition matching the members of a group by their tags or other keys.
+type Condition struct {
+	// MemberType is the type of the members matched, e.g. Pod, VirtualMachine, Segment or SegmentPort.
+	MemberType string
+	// Key is the key matched, e.g. Tag.
+	Key string
+	// Value is the value matched, a tag is formatted as scope|tag.
+	Value string
+	// Operator is the operator of the value, it's ignored if the ScopeOperator is NOTEQUALS.
+	Operator string
+	// ScopeOperator is the operator of the tag scope.
+	ScopeOperator string
+}
+
+// TagCondition returns the condition matching the tag of the members with the operators.
+func TagCondition(memberType, value, operator, scopeOperator string) Condition {
+	return Condition{MemberType: memberType, Key: "Tag", Value: value, Operator: operator, ScopeOperator: scopeOperator}
+}
+
+// Build returns the NSX Condition.
+func (c Condition) Build() *data.StructValue {
+	fields := map[string]data.DataValue{
+		resourceType:     data.NewStringValue(TypeCondition),
+		"member_type":    data.NewStringValue(c.MemberType),
+		"value":          data.NewStringValue(c.Value),
+		"key":            data.NewStringValue(c.Key),
+		"scope_operator": data.NewStringValue(c.ScopeOperator),
+	}
+	// when the scope operator is "NOTEQUALS", the tag operator and value field will not be used
+	if c.ScopeOperator != "NOTEQUALS" {
+		fields["operator"] = data.NewStringValue(c.Operator)
+	}
+	return data.NewStructValue("", fields)
+}
+
+// Conjunction returns the ConjunctionOperator joining two expressions.
+func Conjunction(op string) *data.StructValue {
+	return data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			"conjunction_operator": data.NewStringValue(op),
+			resourceType:           data.NewStringValue(TypeConjunctionOperator),
+		},
+	)
+}
+
+// IPAddresses returns the IPAddressExpression of the IP addresses or CIDRs.
+func IPAddresses(addresses []string) *data.StructValue {
+	return data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			resourceType:   data.NewStringValue(TypeIPAddressExpression),
+			"ip_addresses": stringList(addresses),
+		},
+	)
+}
+
+// Paths returns the PathExpression of the NSX paths.
+func Paths(paths []string) *data.StructValue {
+	return data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			resourceType: data.NewStringValue(TypePathExpression),
+			"paths":      stringList(paths),
+		},
+	)
+}
+
+// Nested appends a NestedExpression to the expressions, and returns the list of its expressions to which
+// the conditions are added.
+func Nested(expressions *[]*data.StructValue) *data.ListValue {
+	nested := data.NewListValue()
+	*expressions = append(*expressions, data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			"expressions": nested,
+			resourceType:  data.NewStringValue(TypeNestedExpression),
+		},
+	))
+	return nested
+}
+
+// AppendConjunction appends the conjunction to the expressions if they are not empty, so the next expression
+// is joined with them.
+func AppendConjunction(expressions *[]*data.StructValue, op string) {
+	if len(*expressions) > 0 {
+		*expressions = append(*expressions, Conjunction(op))
+	}
+}
+
+// AddConjunction adds the conjunction to the expressions of a NestedExpression if they are not empty.
+func AddConjunction(expressions *data.ListValue, op string) {
+	if !expressions.IsEmpty() {
+		expressions.Add(Conjunction(op))
+	}
+}
+
+func stringList(values []string) *data.ListValue {
+	list := data.NewListValue()
+	for _, value := range values {
+		list.Add(data.NewStringValue(value))
+	}
+	return list
+}
+
+// Validate checks the expressions of a group: they are joined by the conjunctions one by one, and the
+// NestedExpressions contain no more than MaxNestedConditions conditions joined by AND, no NestedExpression.
+func Validate(expressions []*data.StructValue) error {
+	return validate(expressions, 0)
+}
+
+func validate(expressions []*data.StructValue, depth int) error {
+	conditions := 0
+	for i, expr := range expressions {
+		if expr == nil {
+			return fmt.Errorf("expression %d is nil", i)
+		}
+		exprType := stringField(expr, resourceType)
+		if i%2 == 1 {
+			if exprType != TypeConjunctionOperator {
+				return fmt.Errorf("expression %d of type %s is not joined by a conjunction", i, exprType)
+			}
+			op := stringField(expr, "conjunction_operator")
+			if op != And && (op != Or || depth > 0) {
+				return fmt.Errorf("invalid conjunction %q at %d", op, i)
+			}
+			continue
+		}
+		switch exprType {
+		case TypeCondition:
+			conditions++
+			if stringField(expr, "member_type") == "" || stringField(expr, "key") == "" {
+				return fmt.Errorf("condition %d has no member type or key", i)
+			}
+		case TypeNestedExpression:
+			if depth+1 > MaxNestingDepth {
+				return fmt.Errorf("nested expression %d exceeds NSX nesting depth of %d", i, MaxNestingDepth)
+			}
+			nested, err := nestedExpressions(expr)
+			if err != nil {
+				return fmt.Errorf("nested expression %d: %w", i, err)
+			}
+			if len(nested) == 0 {
+				return fmt.Errorf("nested expression %d is empty", i)
+			}
+			if err := validate(nested, depth+1); err != nil {
+				return fmt.Errorf("nested expression %d: %w", i, err)
+			}
+		case TypeConjunctionOperator:
+			return fmt.Errorf("conjunction at %d joins no expression", i)
+		case TypeIPAddressExpression, TypePathExpression:
+			if depth > 0 {
+				return fmt.Errorf("%s at %d is not allowed in a nested expression", exprType, i)
+			}
+		default:
+			return fmt.Errorf("unknown expression type %q at %d", exprType, i)
+		}
+	}
+	if len(expressions) > 0 && len(expressions)%2 == 0 {
+		return errors.New("expressions end with a conjunction")
+	}
+	if depth > 0 && conditions > MaxNestedConditions {
+		return fmt.Errorf("%d conditions exceed NSX limit of %d", conditions, MaxNestedConditions)
+	}
+	return nil
+}
+
+func nestedExpressions(expr *data.StructValue) ([]*data.StructValue, error) {
+	value, err := expr.Field("expressions")
+	if err != nil {
+		return nil, err
+	}
+	list, ok := value.(*data.ListValue)
+	if !ok {
+		return nil, errors.New("expressions is not a list")
+	}
+	var nested []*data.StructValue
+	for _, item := range list.List() {
+		e, ok := item.(*data.StructValue)
+		if !ok {
+			return nil, errors.New("expression is not a struct")
+		}
+		nested = append(nested, e)
+	}
+	return nested, nil
+}
+
+func stringField(expr *data.StructValue, name string) string {
+	value, err := expr.Field(name)
+	if err != nil {
+		return ""
+	}
+	if str, ok := value.(*data.StringValue); ok {
+		return str.Value()
+	}
+	return ""
+}
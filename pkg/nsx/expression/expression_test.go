@@ -0,0 +1,103 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+)
+
+func TestConditionBuild(t *testing.T) {
+	condition := TagCondition("Pod", "app|web", "EQUALS", "EQUALS").Build()
+	assert.Equal(t, TypeCondition, stringField(condition, resourceType))
+	assert.Equal(t, "Tag", stringField(condition, "key"))
+	assert.Equal(t, "EQUALS", stringField(condition, "operator"))
+
+	// the operator is not used with the NOTEQUALS scope operator
+	condition = TagCondition("Pod", "app|", "", "NOTEQUALS").Build()
+	_, err := condition.Field("operator")
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	condition := TagCondition("Pod", "app|web", "EQUALS", "EQUALS")
+	valid := func() []*data.StructValue {
+		var expressions []*data.StructValue
+		nested := Nested(&expressions)
+		nested.Add(condition.Build())
+		AddConjunction(nested, And)
+		nested.Add(condition.Build())
+		AppendConjunction(&expressions, Or)
+		expressions = append(expressions, IPAddresses([]string{"10.0.0.0/24"}))
+		AppendConjunction(&expressions, Or)
+		expressions = append(expressions, Paths([]string{"/infra/domains/default/groups/g1"}))
+		return expressions
+	}
+	assert.NoError(t, Validate(valid()))
+	assert.NoError(t, Validate(nil))
+
+	tests := []struct {
+		name   string
+		modify func([]*data.StructValue) []*data.StructValue
+	}{
+		{
+			name: "ending-with-conjunction",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				return append(expressions, Conjunction(Or))
+			},
+		},
+		{
+			name: "missing-conjunction",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				return append(expressions, condition.Build())
+			},
+		},
+		{
+			name: "nested-in-nested",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				var inner []*data.StructValue
+				Nested(&inner).Add(condition.Build())
+				nested, _ := expressions[0].Field("expressions")
+				AddConjunction(nested.(*data.ListValue), And)
+				nested.(*data.ListValue).Add(inner[0])
+				return expressions
+			},
+		},
+		{
+			name: "or-in-nested",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				nested, _ := expressions[0].Field("expressions")
+				nested.(*data.ListValue).Add(Conjunction(Or))
+				nested.(*data.ListValue).Add(condition.Build())
+				return expressions
+			},
+		},
+		{
+			name: "too-many-nested-conditions",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				nested, _ := expressions[0].Field("expressions")
+				for i := 0; i < MaxNestedConditions; i++ {
+					AddConjunction(nested.(*data.ListValue), And)
+					nested.(*data.ListValue).Add(condition.Build())
+				}
+				return expressions
+			},
+		},
+		{
+			name: "empty-nested",
+			modify: func(expressions []*data.StructValue) []*data.StructValue {
+				AppendConjunction(&expressions, Or)
+				Nested(&expressions)
+				return expressions
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, Validate(tt.modify(valid())))
+		})
+	}
+}
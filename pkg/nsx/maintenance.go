@@ -0,0 +1,33 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import "strings"
+
+const nodeStatusAPI = "api/v1/node/status"
+
+// InMaintenanceMode returns whether the NSX manager is in maintenance mode, e.g. while it's upgraded, by the
+// maintenance_mode of the node status.
+func (cluster *Cluster) InMaintenanceMode() (bool, error) {
+	status, err := cluster.HttpGet(nodeStatusAPI)
+	if err != nil {
+		return false, err
+	}
+	return parseMaintenanceMode(status), nil
+}
+
+// parseMaintenanceMode reads maintenance_mode of the node status, which is a boolean or a string like "ON"
+// or "ENABLED" depending on the NSX version.
+func parseMaintenanceMode(status map[string]interface{}) bool {
+	switch mode := status["maintenance_mode"].(type) {
+	case bool:
+		return mode
+	case string:
+		switch strings.ToUpper(mode) {
+		case "ON", "ENABLED", "TRUE":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintenanceMode(t *testing.T) {
+	assert.True(t, parseMaintenanceMode(map[string]interface{}{"maintenance_mode": true}))
+	assert.True(t, parseMaintenanceMode(map[string]interface{}{"maintenance_mode": "ON"}))
+	assert.True(t, parseMaintenanceMode(map[string]interface{}{"maintenance_mode": "enabled"}))
+	assert.False(t, parseMaintenanceMode(map[string]interface{}{"maintenance_mode": "OFF"}))
+	assert.False(t, parseMaintenanceMode(map[string]interface{}{"maintenance_mode": false}))
+	assert.False(t, parseMaintenanceMode(map[string]interface{}{"node_version": "4.1.2"}))
+}
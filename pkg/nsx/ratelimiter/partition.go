@@ -0,0 +1,124 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ratelimiter
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// The subsystems the NSX API calls are accounted to.
+const (
+	SubsystemSecurity = "security"
+	SubsystemSubnet   = "subnet"
+	SubsystemVPC      = "vpc"
+	SubsystemGC       = "gc"
+	SubsystemOther    = "other"
+)
+
+// Subsystems are the subsystems whose share of the NSX API throughput can be configured.
+var Subsystems = []string{SubsystemSecurity, SubsystemSubnet, SubsystemVPC, SubsystemGC, SubsystemOther}
+
+type subsystemKey struct{}
+
+// WithSubsystem returns a copy of ctx carrying the subsystem the NSX API call is accounted to.
+func WithSubsystem(ctx context.Context, subsystem string) context.Context {
+	return context.WithValue(ctx, subsystemKey{}, subsystem)
+}
+
+// SubsystemFrom returns the subsystem carried by ctx, SubsystemOther if it's not set.
+func SubsystemFrom(ctx context.Context) string {
+	if subsystem, ok := ctx.Value(subsystemKey{}).(string); ok {
+		return subsystem
+	}
+	return SubsystemOther
+}
+
+// Partitioner partitions the tokens of a RateLimiter between the subsystems in proportion to their
+// shares. The turns to wait for a token are granted one at a time by stride scheduling, so when
+// several subsystems are waiting, each one gains the tokens in proportion to its share, and the
+// tokens not claimed by an idle subsystem are gained by the others.
+type Partitioner struct {
+	sync.Mutex
+	shares map[string]int
+	// pass is the virtual time of each subsystem, it advances by 1/share on each turn granted.
+	pass    map[string]float64
+	current float64
+	waiters map[string][]chan struct{}
+	busy    bool
+}
+
+// NewPartitioner creates a Partitioner with the shares of the subsystems, the subsystem without
+// share is accounted to SubsystemOther, which has the share 1 if it's not set.
+func NewPartitioner(shares map[string]int) *Partitioner {
+	p := &Partitioner{shares: map[string]int{SubsystemOther: 1}, pass: map[string]float64{}, waiters: map[string][]chan struct{}{}}
+	for subsystem, share := range shares {
+		if share > 0 {
+			p.shares[subsystem] = share
+		}
+	}
+	return p
+}
+
+// Acquire blocks the caller until the turn of the subsystem to wait for a token, the caller must
+// call Release once the token is gained.
+func (p *Partitioner) Acquire(subsystem string) {
+	if _, ok := p.shares[subsystem]; !ok {
+		subsystem = SubsystemOther
+	}
+	p.Lock()
+	if !p.busy {
+		p.busy = true
+		p.advance(subsystem)
+		p.Unlock()
+		return
+	}
+	waiter := make(chan struct{})
+	p.waiters[subsystem] = append(p.waiters[subsystem], waiter)
+	p.Unlock()
+	<-waiter
+}
+
+// Release grants the next turn to the waiting subsystem with the smallest virtual time.
+func (p *Partitioner) Release() {
+	p.Lock()
+	defer p.Unlock()
+	next := ""
+	for _, subsystem := range p.waitingSubsystems() {
+		if next == "" || p.passOf(subsystem) < p.passOf(next) {
+			next = subsystem
+		}
+	}
+	if next == "" {
+		p.busy = false
+		return
+	}
+	waiter := p.waiters[next][0]
+	p.waiters[next] = p.waiters[next][1:]
+	p.advance(next)
+	close(waiter)
+}
+
+// passOf returns the virtual time of the subsystem, a subsystem which has been idle resumes from the
+// current virtual time, so it doesn't gain the turns it hasn't claimed.
+func (p *Partitioner) passOf(subsystem string) float64 {
+	return max(p.pass[subsystem], p.current)
+}
+
+func (p *Partitioner) advance(subsystem string) {
+	p.current = p.passOf(subsystem)
+	p.pass[subsystem] = p.current + 1/float64(p.shares[subsystem])
+}
+
+func (p *Partitioner) waitingSubsystems() []string {
+	var subsystems []string
+	for subsystem, waiters := range p.waiters {
+		if len(waiters) > 0 {
+			subsystems = append(subsystems, subsystem)
+		}
+	}
+	sort.Strings(subsystems)
+	return subsystems
+}
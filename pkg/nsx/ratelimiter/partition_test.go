@@ -0,0 +1,74 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubsystemFrom(t *testing.T) {
+	assert.Equal(t, SubsystemOther, SubsystemFrom(context.TODO()))
+	assert.Equal(t, SubsystemSecurity, SubsystemFrom(WithSubsystem(context.TODO(), SubsystemSecurity)))
+}
+
+func TestPartitioner(t *testing.T) {
+	p := NewPartitioner(map[string]int{SubsystemSecurity: 3, SubsystemSubnet: 1})
+	// hold the turn until all the waiters are queued
+	p.Acquire(SubsystemOther)
+
+	var lock sync.Mutex
+	var granted []string
+	var wg sync.WaitGroup
+	queue := func(subsystem string, count int) {
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.Acquire(subsystem)
+				lock.Lock()
+				granted = append(granted, subsystem)
+				lock.Unlock()
+				p.Release()
+			}()
+		}
+	}
+	queue(SubsystemSecurity, 12)
+	queue(SubsystemSubnet, 12)
+	assert.Eventually(t, func() bool {
+		p.Lock()
+		defer p.Unlock()
+		return len(p.waiters[SubsystemSecurity]) == 12 && len(p.waiters[SubsystemSubnet]) == 12
+	}, time.Second, time.Millisecond)
+	p.Release()
+	wg.Wait()
+
+	// the security subsystem gains 3 turns for each turn of the subnet subsystem while both are waiting
+	security := 0
+	for _, subsystem := range granted[:16] {
+		if subsystem == SubsystemSecurity {
+			security++
+		}
+	}
+	assert.Equal(t, 12, security)
+	assert.Len(t, granted, 24)
+	p.Lock()
+	assert.False(t, p.busy)
+	p.Unlock()
+}
+
+func TestPartitionerIdleSubsystem(t *testing.T) {
+	p := NewPartitioner(map[string]int{SubsystemSecurity: 1, SubsystemSubnet: 1})
+	// the turns taken by the subnet subsystem while the security subsystem is idle are not paid back
+	for i := 0; i < 10; i++ {
+		p.Acquire(SubsystemSubnet)
+		p.Release()
+	}
+	assert.Equal(t, p.passOf(SubsystemSecurity), p.current)
+	assert.Equal(t, p.passOf(SubsystemSecurity)+1, p.passOf(SubsystemSubnet))
+}
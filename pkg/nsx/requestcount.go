@@ -0,0 +1,31 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import (
+	"sync"
+)
+
+// requestCounts counts the requests sent to NSX by HTTP method, the retries included.
+var requestCounts = struct {
+	sync.Mutex
+	counts map[string]int64
+}{counts: map[string]int64{}}
+
+func countRequest(method string) {
+	requestCounts.Lock()
+	defer requestCounts.Unlock()
+	requestCounts.counts[method]++
+}
+
+// RequestCounts returns the count of the requests sent to NSX by HTTP method since nsx-operator started.
+func RequestCounts() map[string]int64 {
+	requestCounts.Lock()
+	defer requestCounts.Unlock()
+	counts := make(map[string]int64, len(requestCounts.counts))
+	for method, count := range requestCounts.counts {
+		counts[method] = count
+	}
+	return counts
+}
@@ -0,0 +1,234 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package schema validates the payloads sent to NSX against the OpenAPI spec downloaded from NSX Manager, so a
+// field or a value the NSX version doesn't support fails before the call with the offending field, instead of a
+// generic error from NSX, or the field being silently dropped.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resourceTypeField is the field discriminating the subtypes of the polymorphic NSX types, e.g. the children of
+// the hierarchical API or the expressions of the groups, its value is the name of the subtype definition.
+const resourceTypeField = "resource_type"
+
+// Schema is the object definitions of an OpenAPI spec.
+type Schema struct {
+	objects map[string]*object
+}
+
+// object is the properties of an object definition merged with the ones of its allOf definitions.
+type object struct {
+	properties map[string]map[string]interface{}
+	// open is true if the object accepts any field, i.e. it defines no properties or additional ones.
+	open bool
+}
+
+// Load parses the definitions of the OpenAPI spec, both the Swagger 2.0 definitions and the OpenAPI 3.0
+// components are supported.
+func Load(doc map[string]interface{}) (*Schema, error) {
+	definitions, ok := doc["definitions"].(map[string]interface{})
+	if !ok {
+		components, _ := doc["components"].(map[string]interface{})
+		definitions, ok = components["schemas"].(map[string]interface{})
+	}
+	if !ok || len(definitions) == 0 {
+		return nil, errors.New("no definitions in the OpenAPI spec")
+	}
+	s := &Schema{objects: map[string]*object{}}
+	for name := range definitions {
+		s.resolve(definitions, name, map[string]bool{})
+	}
+	return s, nil
+}
+
+// resolve merges the properties of the definition with the ones of its allOf definitions.
+func (s *Schema) resolve(definitions map[string]interface{}, name string, resolving map[string]bool) *object {
+	if obj, ok := s.objects[name]; ok {
+		return obj
+	}
+	definition, ok := definitions[name].(map[string]interface{})
+	if !ok || resolving[name] {
+		// an unknown or a recursive definition accepts anything
+		return &object{open: true}
+	}
+	resolving[name] = true
+	obj := s.inlineObject(definitions, definition, resolving)
+	s.objects[name] = obj
+	return obj
+}
+
+func (s *Schema) inlineObject(definitions map[string]interface{}, definition map[string]interface{}, resolving map[string]bool) *object {
+	obj := &object{properties: map[string]map[string]interface{}{}}
+	if additional, ok := definition["additionalProperties"]; ok && additional != false {
+		obj.open = true
+	}
+	if properties, ok := definition["properties"].(map[string]interface{}); ok {
+		for property, propertySchema := range properties {
+			if propertyMap, ok := propertySchema.(map[string]interface{}); ok {
+				obj.properties[property] = propertyMap
+			}
+		}
+	}
+	allOf, _ := definition["allOf"].([]interface{})
+	for _, item := range allOf {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var parent *object
+		if ref, ok := itemMap["$ref"].(string); ok {
+			parent = s.resolve(definitions, refName(ref), resolving)
+		} else {
+			parent = s.inlineObject(definitions, itemMap, resolving)
+		}
+		obj.open = obj.open || parent.open
+		for property, propertySchema := range parent.properties {
+			if _, ok := obj.properties[property]; !ok {
+				obj.properties[property] = propertySchema
+			}
+		}
+	}
+	if len(obj.properties) == 0 {
+		obj.open = true
+	}
+	return obj
+}
+
+// Has returns whether the spec defines the object.
+func (s *Schema) Has(name string) bool {
+	_, ok := s.objects[name]
+	return ok
+}
+
+// Validate checks the payload decoded from JSON against the object definition. It returns an error with the
+// path of the first field which is not defined by the spec, or whose value doesn't match the type or the enum
+// of the field. The fields of the definitions missing in the spec are not checked.
+func (s *Schema) Validate(name string, payload interface{}) error {
+	return s.validateObject(name, payload, "")
+}
+
+func (s *Schema) validateObject(name string, value interface{}, path string) error {
+	obj, ok := s.objects[name]
+	if !ok {
+		return nil
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("field %s is %T, %s object expected", displayPath(path), value, name)
+	}
+	// the subtype is validated instead of the base type, the subtypes missing in the spec are not checked
+	if resourceType, ok := fields[resourceTypeField].(string); ok && resourceType != name {
+		subtype, ok := s.objects[resourceType]
+		if !ok {
+			return nil
+		}
+		name, obj = resourceType, subtype
+	}
+	return s.validateFields(name, obj, fields, path)
+}
+
+func (s *Schema) validateFields(name string, obj *object, fields map[string]interface{}, path string) error {
+	if obj.open {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fieldPath := joinPath(path, key)
+		propertySchema, ok := obj.properties[key]
+		if !ok {
+			return fmt.Errorf("field %s is not defined for %s by NSX", fieldPath, name)
+		}
+		if err := s.validateValue(propertySchema, fields[key], fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateValue(propertySchema map[string]interface{}, value interface{}, path string) error {
+	if value == nil {
+		return nil
+	}
+	if ref, ok := propertySchema["$ref"].(string); ok {
+		return s.validateObject(refName(ref), value, path)
+	}
+	if enum, ok := propertySchema["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allowed := make([]string, 0, len(enum))
+			for _, v := range enum {
+				allowed = append(allowed, fmt.Sprintf("%v", v))
+			}
+			return fmt.Errorf("field %s has value %v not supported by NSX, it must be one of [%s]", path, value, strings.Join(allowed, ", "))
+		}
+	}
+	switch propertySchema["type"] {
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("field %s is %T, array expected", path, value)
+		}
+		itemSchema, _ := propertySchema["items"].(map[string]interface{})
+		for i, item := range items {
+			if err := s.validateValue(itemSchema, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("field %s is %T, string expected", path, value)
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("field %s is %T, number expected", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("field %s is %T, boolean expected", path, value)
+		}
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("field %s is %T, object expected", path, value)
+		}
+		if _, ok := propertySchema["properties"]; ok {
+			return s.validateFields(path, s.inlineObject(nil, propertySchema, map[string]bool{}), fields, path)
+		}
+	}
+	return nil
+}
+
+// refName returns the definition name of the reference, e.g. Rule of #/definitions/Rule.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
@@ -0,0 +1,85 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const spec = `{
+  "swagger": "2.0",
+  "definitions": {
+    "PolicyConfigResource": {
+      "properties": {
+        "id": {"type": "string"},
+        "resource_type": {"type": "string"},
+        "marked_for_delete": {"type": "boolean"},
+        "children": {"type": "array", "items": {"$ref": "#/definitions/ChildPolicyConfigResource"}}
+      },
+      "type": "object"
+    },
+    "ChildPolicyConfigResource": {
+      "properties": {
+        "resource_type": {"type": "string"},
+        "marked_for_delete": {"type": "boolean"}
+      },
+      "type": "object"
+    },
+    "Infra": {"allOf": [{"$ref": "#/definitions/PolicyConfigResource"}]},
+    "ChildRule": {
+      "allOf": [
+        {"$ref": "#/definitions/ChildPolicyConfigResource"},
+        {"properties": {"Rule": {"$ref": "#/definitions/Rule"}}, "type": "object"}
+      ]
+    },
+    "Rule": {
+      "allOf": [
+        {"$ref": "#/definitions/PolicyConfigResource"},
+        {
+          "properties": {
+            "action": {"type": "string", "enum": ["ALLOW", "DROP", "REJECT"]},
+            "sequence_number": {"type": "integer"},
+            "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}}
+          },
+          "type": "object"
+        }
+      ]
+    },
+    "Tag": {"properties": {"scope": {"type": "string"}, "tag": {"type": "string"}}, "type": "object"},
+    "Opaque": {"type": "object"}
+  }
+}`
+
+func TestSchema(t *testing.T) {
+	doc := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(spec), &doc))
+	s, err := Load(doc)
+	assert.NoError(t, err)
+	assert.True(t, s.Has("Rule"))
+	assert.False(t, s.Has("Group"))
+
+	validate := func(payload string) error {
+		var value interface{}
+		assert.NoError(t, json.Unmarshal([]byte(payload), &value))
+		return s.Validate("Infra", value)
+	}
+	assert.NoError(t, validate(`{"resource_type": "Infra", "children": [{"resource_type": "ChildRule", "Rule": {
+		"id": "r1", "resource_type": "Rule", "action": "DROP", "sequence_number": 1, "tags": [{"scope": "s", "tag": "t"}]}}]}`))
+	// the unknown subtypes are not checked
+	assert.NoError(t, validate(`{"children": [{"resource_type": "ChildGroup", "Group": {"id": "g1"}}]}`))
+
+	assert.EqualError(t, validate(`{"children": [{"resource_type": "ChildRule", "Rule": {"action": "JUMP_TO_APPLICATION"}}]}`),
+		"field children[0].Rule.action has value JUMP_TO_APPLICATION not supported by NSX, it must be one of [ALLOW, DROP, REJECT]")
+	assert.EqualError(t, validate(`{"children": [{"resource_type": "ChildRule", "Rule": {"tags": [{"scope": "s", "value": "v"}]}}]}`),
+		"field children[0].Rule.tags[0].value is not defined for Tag by NSX")
+	assert.EqualError(t, validate(`{"children": [{"resource_type": "ChildRule", "Rule": {"sequence_number": "1"}}]}`),
+		"field children[0].Rule.sequence_number is string, number expected")
+	assert.EqualError(t, validate(`[]`), "field <root> is []interface {}, Infra object expected")
+
+	_, err = Load(map[string]interface{}{"swagger": "2.0"})
+	assert.EqualError(t, err, "no definitions in the OpenAPI spec")
+}
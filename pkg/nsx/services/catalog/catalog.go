@@ -0,0 +1,153 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package catalog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	ResourceTypeService                = "Service"
+	ResourceTypeContextProfile         = "PolicyContextProfile"
+	ResourceTypeSegmentSecurityProfile = "SegmentSecurityProfile"
+	ResourceTypeSpoofGuardProfile      = "SpoofGuardProfile"
+	ResourceTypeIPDiscoveryProfile     = "IPDiscoveryProfile"
+	ResourceTypeMacDiscoveryProfile    = "MacDiscoveryProfile"
+	ResourceTypeQoSProfile             = "QoSProfile"
+
+	RefreshInterval = 30 * time.Minute
+)
+
+var (
+	log = logger.Log
+	// CatalogResourceTypes are the system-owned catalogs cached by CatalogService
+	CatalogResourceTypes = []string{
+		ResourceTypeService,
+		ResourceTypeContextProfile,
+		ResourceTypeSegmentSecurityProfile,
+		ResourceTypeSpoofGuardProfile,
+		ResourceTypeIPDiscoveryProfile,
+		ResourceTypeMacDiscoveryProfile,
+		ResourceTypeQoSProfile,
+	}
+	SegmentProfileResourceTypes = []string{
+		ResourceTypeSegmentSecurityProfile,
+		ResourceTypeSpoofGuardProfile,
+		ResourceTypeIPDiscoveryProfile,
+		ResourceTypeMacDiscoveryProfile,
+		ResourceTypeQoSProfile,
+	}
+
+	catalogService *CatalogService
+	lock           = &sync.Mutex{}
+)
+
+// CatalogService caches the NSX system-owned catalogs, e.g. predefined Services, context profiles
+// and segment profiles, which rarely change, so references to them can be validated locally instead
+// of issuing a GET per reconcile. The cache is rebuilt periodically.
+type CatalogService struct {
+	common.Service
+	storeLock sync.RWMutex
+	store     *CatalogStore
+}
+
+// GetCatalogService get singleton CatalogService instance, the catalogs are loaded on the first call.
+func GetCatalogService(service common.Service) (*CatalogService, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if catalogService == nil {
+		s := &CatalogService{Service: service, store: newCatalogStore()}
+		if err := s.Refresh(); err != nil {
+			return nil, err
+		}
+		catalogService = s
+	}
+	return catalogService, nil
+}
+
+// LoadedCatalogService returns the singleton CatalogService if the catalogs are loaded, nil otherwise. Unlike
+// GetCatalogService, it never loads the catalogs, so the callers can fall back to querying NSX.
+func LoadedCatalogService() *CatalogService {
+	lock.Lock()
+	defer lock.Unlock()
+	return catalogService
+}
+
+// Refresh reloads all the catalogs into a new store, the existing store is kept if it fails.
+func (s *CatalogService) Refresh() error {
+	store := newCatalogStore()
+	for _, resourceType := range CatalogResourceTypes {
+		queryParam := fmt.Sprintf("%s:%s AND _system_owned:true AND marked_for_delete:false", common.ResourceType, resourceType)
+		count, err := s.SearchResource(resourceType, queryParam, store, nil)
+		if err != nil {
+			log.Error(err, "failed to load catalog", "resourceType", resourceType)
+			return err
+		}
+		log.V(1).Info("loaded catalog", "resourceType", resourceType, "count", count)
+	}
+	s.storeLock.Lock()
+	s.store = store
+	s.storeLock.Unlock()
+	return nil
+}
+
+// PeriodicRefresh refreshes the catalogs periodically.
+// cancel is used to break the loop during UT
+func (s *CatalogService) PeriodicRefresh(cancel chan bool, interval time.Duration) {
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		if err := s.Refresh(); err != nil {
+			log.Error(err, "failed to refresh catalogs, keep the cached ones")
+		}
+	}
+}
+
+func (s *CatalogService) getStore() *CatalogStore {
+	s.storeLock.RLock()
+	defer s.storeLock.RUnlock()
+	return s.store
+}
+
+// GetByPath returns the catalog entry with the path, nil if not found.
+func (s *CatalogService) GetByPath(path string) *Entry {
+	return s.getStore().GetByPath(path)
+}
+
+// GetService returns the predefined Service with the display name or ID, nil if not found.
+func (s *CatalogService) GetService(name string) *Entry {
+	return s.getStore().GetByName(ResourceTypeService, name)
+}
+
+// GetContextProfile returns the predefined context profile with the display name or ID, nil if not found.
+func (s *CatalogService) GetContextProfile(name string) *Entry {
+	return s.getStore().GetByName(ResourceTypeContextProfile, name)
+}
+
+// GetSegmentProfile returns the predefined segment profile of any type with the display name or ID, nil if not found.
+func (s *CatalogService) GetSegmentProfile(name string) *Entry {
+	store := s.getStore()
+	for _, resourceType := range SegmentProfileResourceTypes {
+		if entry := store.GetByName(resourceType, name); entry != nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+// ValidateServicePath returns an error if the path doesn't refer to a predefined Service.
+func (s *CatalogService) ValidateServicePath(path string) error {
+	if entry := s.GetByPath(path); entry == nil || entry.ResourceType != ResourceTypeService {
+		return fmt.Errorf("NSX Service %s is not found", path)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+)
+
+func newEntity(id, path, name, resourceType string) *data.StructValue {
+	return data.NewStructValue("", map[string]data.DataValue{
+		"id":            data.NewStringValue(id),
+		"path":          data.NewStringValue(path),
+		"display_name":  data.NewStringValue(name),
+		"resource_type": data.NewStringValue(resourceType),
+	})
+}
+
+func TestCatalogService(t *testing.T) {
+	store := newCatalogStore()
+	assert.NoError(t, store.TransResourceToStore(newEntity("HTTP", "/infra/services/HTTP", "HTTP", ResourceTypeService)))
+	assert.NoError(t, store.TransResourceToStore(newEntity("HTTP", "/infra/context-profiles/HTTP", "HTTP", ResourceTypeContextProfile)))
+	assert.NoError(t, store.TransResourceToStore(newEntity("default-spoofguard-profile", "/infra/spoofguard-profiles/default-spoofguard-profile",
+		"default-spoofguard-profile", ResourceTypeSpoofGuardProfile)))
+	// entity without path is ignored
+	assert.NoError(t, store.TransResourceToStore(newEntity("x", "", "x", ResourceTypeService)))
+	assert.Equal(t, 3, len(store.List()))
+
+	s := &CatalogService{store: store}
+	assert.Equal(t, "/infra/services/HTTP", s.GetService("HTTP").Path)
+	assert.Equal(t, "/infra/context-profiles/HTTP", s.GetContextProfile("HTTP").Path)
+	assert.Nil(t, s.GetContextProfile("SSH"))
+	assert.Equal(t, ResourceTypeSpoofGuardProfile, s.GetSegmentProfile("default-spoofguard-profile").ResourceType)
+	assert.Nil(t, s.GetSegmentProfile("HTTP"))
+
+	assert.NoError(t, s.ValidateServicePath("/infra/services/HTTP"))
+	assert.Error(t, s.ValidateServicePath("/infra/context-profiles/HTTP"))
+	assert.Error(t, s.ValidateServicePath("/infra/services/SSH"))
+}
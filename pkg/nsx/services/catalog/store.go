@@ -0,0 +1,123 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package catalog
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	indexKeyName         = "name"
+	indexKeyResourceType = "resourceType"
+)
+
+// Entry is a system-owned NSX object of the catalog, only the fields used to validate
+// references are kept since the catalog covers heterogeneous resource types.
+type Entry struct {
+	ID           string
+	Path         string
+	DisplayName  string
+	ResourceType string
+}
+
+// keyFunc uses the path as key since IDs are only unique per resource type.
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *Entry:
+		return v.Path, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+func indexByName(obj interface{}) ([]string, error) {
+	switch v := obj.(type) {
+	case *Entry:
+		return []string{v.DisplayName, v.ID}, nil
+	default:
+		return nil, errors.New("indexByName doesn't support unknown type")
+	}
+}
+
+func indexByResourceType(obj interface{}) ([]string, error) {
+	switch v := obj.(type) {
+	case *Entry:
+		return []string{v.ResourceType}, nil
+	default:
+		return nil, errors.New("indexByResourceType doesn't support unknown type")
+	}
+}
+
+// CatalogStore is the store of catalog entries, it implements common.Store.
+type CatalogStore struct {
+	cache.Indexer
+}
+
+func newCatalogStore() *CatalogStore {
+	return &CatalogStore{Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
+		indexKeyName:         indexByName,
+		indexKeyResourceType: indexByResourceType,
+	})}
+}
+
+func (s *CatalogStore) TransResourceToStore(entity *data.StructValue) error {
+	entry := &Entry{
+		ID:           fieldString(entity, "id"),
+		Path:         fieldString(entity, "path"),
+		DisplayName:  fieldString(entity, "display_name"),
+		ResourceType: fieldString(entity, "resource_type"),
+	}
+	if entry.Path == "" {
+		return nil
+	}
+	return s.Add(entry)
+}
+
+func (s *CatalogStore) ListIndexFuncValues(key string) sets.Set[string] {
+	return sets.New[string](s.Indexer.ListIndexFuncValues(key)...)
+}
+
+func (s *CatalogStore) Apply(_ interface{}) error {
+	return nil
+}
+
+func (s *CatalogStore) IsPolicyAPI() bool {
+	return true
+}
+
+func (s *CatalogStore) GetByPath(path string) *Entry {
+	obj, exists, err := s.GetByKey(path)
+	if err != nil || !exists {
+		return nil
+	}
+	return obj.(*Entry)
+}
+
+func (s *CatalogStore) GetByName(resourceType, name string) *Entry {
+	objs, err := s.ByIndex(indexKeyName, name)
+	if err != nil {
+		return nil
+	}
+	for _, obj := range objs {
+		if entry := obj.(*Entry); entry.ResourceType == resourceType {
+			return entry
+		}
+	}
+	return nil
+}
+
+func fieldString(entity *data.StructValue, field string) string {
+	value, err := entity.Field(field)
+	if err != nil {
+		return ""
+	}
+	if str, ok := value.(*data.StringValue); ok {
+		return str.Value()
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+// The usage types of the NSX capacity dashboard consumed by the SecurityPolicies.
+const (
+	CapacityUsageGroups                = model.PolicyCapacityDashboardUsage_USAGE_TYPE_GROUPS
+	CapacityUsageFirewallRules         = model.PolicyCapacityDashboardUsage_USAGE_TYPE_FIREWALL_RULES
+	CapacityUsageSecurityPolicies      = model.PolicyCapacityDashboardUsage_USAGE_TYPE_SECURITY_POLICY
+	CapacityUsageSecurityPolicyRules   = model.PolicyCapacityDashboardUsage_USAGE_TYPE_SECURITY_POLICY_RULES
+	defaultCapacityWarningPercent      = 70.0
+	defaultCapacitySafetyMarginPercent = 10
+)
+
+// CapacityUsage is the usage of an NSX object type reported by the NSX capacity dashboard.
+type CapacityUsage struct {
+	UsageType string `json:"usageType"`
+	Current   int64  `json:"current"`
+	Max       int64  `json:"max"`
+	// WarningPercent is the usage percentage from which NSX reports the usage type as approaching its max.
+	WarningPercent float64 `json:"warningPercent"`
+	// MarginExceeded is whether the usage exceeds the max less the safety margin, more objects of the usage type
+	// are refused then.
+	MarginExceeded bool `json:"marginExceeded,omitempty"`
+}
+
+// Percent returns the usage percentage of the max supported count.
+func (u CapacityUsage) Percent() float64 {
+	if u.Max <= 0 {
+		return 0
+	}
+	return float64(u.Current) * 100 / float64(u.Max)
+}
+
+// CapacityTracker keeps the NSX capacity usage polled from the capacity dashboard, and refuses the writes
+// which would consume the safety margin kept below the max supported counts, before NSX rejects them. The
+// objects created since the last poll are reserved so that the concurrent reconciles don't overshoot the
+// margin together. Nothing is refused before the usage is known, e.g. if the polling is disabled.
+type CapacityTracker struct {
+	lock   sync.Mutex
+	usages map[string]CapacityUsage
+	// reserved is the count of the objects of a usage type created since the last poll.
+	reserved map[string]int64
+	// safetyMargin is the percentage of the max supported count of a usage type kept free.
+	safetyMargin int
+}
+
+// Capacity is the CapacityTracker shared by the services.
+var Capacity = &CapacityTracker{}
+
+// Update replaces the usage with the one polled from the capacity dashboard, the objects reserved before
+// are counted by NSX then. A safetyMargin out of (0, 100) means the default of 10 percent.
+func (t *CapacityTracker) Update(usages []model.CapacityDashboardUsage, safetyMargin int) {
+	if safetyMargin <= 0 || safetyMargin >= 100 {
+		safetyMargin = defaultCapacitySafetyMarginPercent
+	}
+	polled := make(map[string]CapacityUsage, len(usages))
+	for _, usage := range usages {
+		if usage.UsageType == nil || usage.CurrentUsageCount == nil || usage.MaxSupportedCount == nil {
+			continue
+		}
+		u := CapacityUsage{
+			UsageType:      *usage.UsageType,
+			Current:        *usage.CurrentUsageCount,
+			Max:            *usage.MaxSupportedCount,
+			WarningPercent: defaultCapacityWarningPercent,
+		}
+		if usage.MinThresholdPercentage != nil && *usage.MinThresholdPercentage > 0 {
+			u.WarningPercent = *usage.MinThresholdPercentage
+		}
+		polled[u.UsageType] = u
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.usages = polled
+	t.reserved = map[string]int64{}
+	t.safetyMargin = safetyMargin
+}
+
+// Reserve reserves the count of the objects of the usage types about to be created, a negative count
+// releases them. It returns an error without reserving any of them if the usage of a type would exceed
+// its max supported count less the safety margin, and logs a warning if it would exceed the warning
+// threshold of NSX.
+func (t *CapacityTracker) Reserve(counts map[string]int64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	usageTypes := make([]string, 0, len(counts))
+	for usageType := range counts {
+		usageTypes = append(usageTypes, usageType)
+	}
+	sort.Strings(usageTypes)
+	for _, usageType := range usageTypes {
+		count := counts[usageType]
+		usage, ok := t.usages[usageType]
+		if count <= 0 || !ok || usage.Max <= 0 {
+			continue
+		}
+		projected := usage.Current + t.reserved[usageType] + count
+		limit := usage.Max * int64(100-t.safetyMargin) / 100
+		if projected > limit {
+			return fmt.Errorf("creating %d more of %s would exceed the NSX capacity less the safety margin of %d%%: %d of %d used, %d allowed",
+				count, usageType, t.safetyMargin, usage.Current+t.reserved[usageType], usage.Max, limit)
+		}
+		if float64(projected)*100 >= float64(usage.Max)*usage.WarningPercent {
+			log.Info("NSX capacity is approaching its max", "usageType", usageType, "used", projected, "max", usage.Max)
+		}
+	}
+	for _, usageType := range usageTypes {
+		if _, ok := t.usages[usageType]; ok {
+			t.reserved[usageType] += counts[usageType]
+		}
+	}
+	return nil
+}
+
+// Usages returns the usage of all the usage types with the objects reserved since the last poll, sorted by
+// usage type.
+func (t *CapacityTracker) Usages() []CapacityUsage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	usages := make([]CapacityUsage, 0, len(t.usages))
+	for _, usage := range t.usages {
+		usage.Current += t.reserved[usage.UsageType]
+		usage.MarginExceeded = usage.Max > 0 && usage.Percent() >= float64(100-t.safetyMargin)
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].UsageType < usages[j].UsageType })
+	return usages
+}
+
+// Pressure returns the usage types whose usage exceeds the warning threshold of NSX or the safety margin,
+// sorted by usage type.
+func (t *CapacityTracker) Pressure() []CapacityUsage {
+	var pressure []CapacityUsage
+	for _, usage := range t.Usages() {
+		if usage.MarginExceeded || (usage.Max > 0 && usage.Percent() >= usage.WarningPercent) {
+			pressure = append(pressure, usage)
+		}
+	}
+	return pressure
+}
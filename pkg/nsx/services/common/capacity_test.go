@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+func capacityUsage(usageType string, current, max int64) model.CapacityDashboardUsage {
+	return model.CapacityDashboardUsage{
+		UsageType:              pointy.String(usageType),
+		CurrentUsageCount:      pointy.Int64(current),
+		MaxSupportedCount:      pointy.Int64(max),
+		MinThresholdPercentage: pointy.Float64(70),
+	}
+}
+
+func TestCapacityTracker(t *testing.T) {
+	tracker := &CapacityTracker{}
+	// nothing is refused before the usage is known
+	assert.NoError(t, tracker.Reserve(map[string]int64{CapacityUsageGroups: 1000}))
+	assert.Empty(t, tracker.Usages())
+
+	tracker.Update([]model.CapacityDashboardUsage{
+		capacityUsage(CapacityUsageGroups, 800, 1000),
+		capacityUsage(CapacityUsageFirewallRules, 100, 1000),
+		{UsageType: pointy.String("NUMBER_OF_LB_VIRTUAL_SERVERS")},
+	}, 0)
+	assert.Len(t, tracker.Usages(), 2)
+	assert.Equal(t, []CapacityUsage{
+		{UsageType: CapacityUsageGroups, Current: 800, Max: 1000, WarningPercent: 70},
+	}, tracker.Pressure())
+
+	// the default safety margin keeps 10 percent free, the objects reserved since the poll are counted
+	assert.NoError(t, tracker.Reserve(map[string]int64{CapacityUsageGroups: 60, CapacityUsageFirewallRules: 10}))
+	err := tracker.Reserve(map[string]int64{CapacityUsageGroups: 41, CapacityUsageFirewallRules: 10})
+	assert.EqualError(t, err, "creating 41 more of NUMBER_OF_GROUPS would exceed the NSX capacity less the safety margin of 10%: 860 of 1000 used, 900 allowed")
+	assert.NoError(t, tracker.Reserve(map[string]int64{CapacityUsageGroups: 40, CapacityUsageFirewallRules: -20}))
+	usages := tracker.Usages()
+	assert.Equal(t, int64(90), usages[0].Current)
+	assert.Equal(t, int64(900), usages[1].Current)
+	assert.True(t, usages[1].MarginExceeded)
+	// the deletions are never refused
+	assert.NoError(t, tracker.Reserve(map[string]int64{CapacityUsageGroups: -10}))
+
+	// the reservations are reset by the next poll
+	tracker.Update([]model.CapacityDashboardUsage{capacityUsage(CapacityUsageGroups, 500, 1000)}, 50)
+	pressure := tracker.Pressure()
+	assert.Len(t, pressure, 1)
+	assert.True(t, pressure[0].MarginExceeded)
+	assert.Error(t, tracker.Reserve(map[string]int64{CapacityUsageGroups: 1}))
+}
@@ -0,0 +1,59 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+// The types of NSX objects counted by ObjectCounter.
+const (
+	ObjectTypeSecurityPolicy = "SecurityPolicy"
+	ObjectTypeRule           = "Rule"
+	ObjectTypeGroup          = "Group"
+	ObjectTypeShare          = "Share"
+	ObjectTypeSubnetPort     = "SubnetPort"
+)
+
+// ObjectCounts is the count of NSX objects keyed by namespace, the objects not bound to
+// a namespace are counted with the empty namespace.
+type ObjectCounts map[string]int
+
+// Total returns the count of the objects in all namespaces.
+func (c ObjectCounts) Total() int {
+	total := 0
+	for _, count := range c {
+		total += count
+	}
+	return total
+}
+
+// ObjectCounter is implemented by the services to report the NSX objects created by nsx-operator.
+type ObjectCounter interface {
+	// CountObjects returns the ObjectCounts of the NSX objects in the store, keyed by object type.
+	CountObjects() map[string]ObjectCounts
+}
+
+// NamespaceOfTags returns the namespace tagged on the NSX object.
+func NamespaceOfTags(tags []model.Tag) string {
+	for _, tag := range tags {
+		if tag.Scope == nil || tag.Tag == nil {
+			continue
+		}
+		if *tag.Scope == TagScopeNamespace || *tag.Scope == TagScopeVMNamespace {
+			return *tag.Tag
+		}
+	}
+	return ""
+}
+
+// CountByNamespace counts the objects in the store by the namespace tagged on them,
+// tagsOf returns the tags of an object in the store.
+func (resourceStore *ResourceStore) CountByNamespace(tagsOf func(obj interface{}) []model.Tag) ObjectCounts {
+	counts := ObjectCounts{}
+	for _, obj := range resourceStore.List() {
+		counts[NamespaceOfTags(tagsOf(obj))]++
+	}
+	return counts
+}
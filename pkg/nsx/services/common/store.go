@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	vapierrors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/bindings"
@@ -14,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
@@ -188,15 +190,31 @@ func (service *Service) SearchResource(resourceTypeValue string, queryParam stri
 // PopulateResourcetoStore is the method used by populating resources created not by nsx-operator
 func (service *Service) PopulateResourcetoStore(wg *sync.WaitGroup, fatalErrors chan error, resourceTypeValue string, queryParam string, store Store, filter Filter) {
 	defer wg.Done()
+	start := time.Now()
 	count, err := service.SearchResource(resourceTypeValue, queryParam, store, filter)
 	if err != nil {
+		metrics.CounterInc(service.NSXConfig, metrics.StoreInitFailureTotal, resourceTypeValue)
 		fatalErrors <- err
 	}
-	log.Info("initialized store", "resourceType", resourceTypeValue, "count", count)
+	duration := time.Since(start)
+	metrics.HistogramObserve(service.NSXConfig, metrics.StoreRebuildDurationSeconds, duration.Seconds(), resourceTypeValue)
+	log.Info("initialized store", "resourceType", resourceTypeValue, "count", count, "duration", duration)
 }
 
 // InitializeCommonStore is the common method used by InitializeResourceStore and InitializeVPCResourceStore
 func (service *Service) InitializeCommonStore(wg *sync.WaitGroup, fatalErrors chan error, org string, project string, resourceTypeValue string, tags []model.Tag, store Store) {
+	queryParam := service.buildTaggedResourceQuery(org, project, resourceTypeValue, tags)
+	service.PopulateResourcetoStore(wg, fatalErrors, resourceTypeValue, queryParam, store, nil)
+}
+
+// SearchTaggedResource searches the resources of the cluster with all the tags from nsx-t side and
+// adds them to the store, it is used to find the resources missing in the store.
+func (service *Service) SearchTaggedResource(resourceTypeValue string, tags []model.Tag, store Store) (uint64, error) {
+	queryParam := service.buildTaggedResourceQuery("", "", resourceTypeValue, tags)
+	return service.SearchResource(resourceTypeValue, queryParam, store, nil)
+}
+
+func (service *Service) buildTaggedResourceQuery(org string, project string, resourceTypeValue string, tags []model.Tag) string {
 	tagScopeClusterKey := strings.Replace(TagScopeCluster, "/", "\\/", -1)
 	tagScopeClusterValue := strings.Replace(service.NSXClient.NsxConfig.Cluster, ":", "\\:", -1)
 	tagParam := fmt.Sprintf("tags.scope:%s AND tags.tag:%s", tagScopeClusterKey, tagScopeClusterValue)
@@ -221,5 +239,5 @@ func (service *Service) InitializeCommonStore(wg *sync.WaitGroup, fatalErrors ch
 		queryParam += " AND " + pathUnescape + path
 	}
 	queryParam += " AND marked_for_delete:false"
-	service.PopulateResourcetoStore(wg, fatalErrors, resourceTypeValue, queryParam, store, nil)
+	return queryParam
 }
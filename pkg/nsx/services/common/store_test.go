@@ -187,3 +187,18 @@ func Test_InitializeResourceStore(t *testing.T) {
 	assert.Empty(t, fatalErrors)
 	assert.Equal(t, []string{"11111"}, ruleStore.ListKeys())
 }
+
+func TestCountByNamespace(t *testing.T) {
+	store := ResourceStore{Indexer: cache.NewIndexer(func(obj interface{}) (string, error) {
+		return *obj.(*model.Group).Id, nil
+	}, cache.Indexers{})}
+	newGroup := func(id, scope, ns string) *model.Group {
+		return &model.Group{Id: &id, Tags: []model.Tag{{Scope: &scope, Tag: &ns}}}
+	}
+	store.Add(newGroup("g1", TagScopeNamespace, "ns-1"))
+	store.Add(newGroup("g2", TagScopeVMNamespace, "ns-1"))
+	store.Add(newGroup("g3", TagScopeCluster, "cluster"))
+
+	counts := store.CountByNamespace(func(obj interface{}) []model.Tag { return obj.(*model.Group).Tags })
+	assert.Equal(t, ObjectCounts{"ns-1": 2, "": 1}, counts)
+}
@@ -23,12 +23,17 @@ const (
 	MaxSubnetNameLength                int    = 80
 	PriorityNetworkPolicyAllowRule     int    = 2010
 	PriorityNetworkPolicyIsolationRule int    = 2090
+	PriorityNamespaceDefaultDeny       int    = 2095
+	PriorityBaselineAdminNetworkPolicy int    = 2100
 	TagScopeNCPCluster                 string = "ncp/cluster"
 	TagScopeNCPProjectUID              string = "ncp/project_uid"
 	TagScopeNCPVIFProjectUID           string = "ncp/vif_project_uid"
 	TagScopeNCPPod                     string = "ncp/pod"
 	TagScopeNCPVNETInterface           string = "ncp/vnet_interface"
+	TagScopeNCPCreatedFor              string = "ncp/created_for"
+	TagScopePrefix                     string = "nsx-op/"
 	TagScopeVersion                    string = "nsx-op/version"
+	TagScopeOwner                      string = "nsx-op/owner"
 	TagScopeCluster                    string = "nsx-op/cluster"
 	TagScopeNamespace                  string = "nsx-op/namespace"
 	TagScopeNamespaceUID               string = "nsx-op/namespace_uid"
@@ -38,8 +43,22 @@ const (
 	TagScopeSecurityPolicyUID          string = "nsx-op/security_policy_uid"
 	TagScopeNetworkPolicyName          string = "nsx-op/network_policy_name"
 	TagScopeNetworkPolicyUID           string = "nsx-op/network_policy_uid"
+	TagScopeServiceExposureName        string = "nsx-op/service_exposure_name"
+	TagScopeServiceExposureUID         string = "nsx-op/service_exposure_uid"
+	TagScopeAdminNetworkPolicyName     string = "nsx-op/admin_network_policy_name"
+	TagScopeAdminNetworkPolicyUID      string = "nsx-op/admin_network_policy_uid"
+	TagScopeDefaultDenyNamespace       string = "nsx-op/default_deny_namespace"
+	TagScopeDefaultDenyNamespaceUID    string = "nsx-op/default_deny_namespace_uid"
 	TagScopeStaticRouteCRName          string = "nsx-op/static_route_name"
 	TagScopeStaticRouteCRUID           string = "nsx-op/static_route_uid"
+	TagScopeSubnetPolicyCRName         string = "nsx-op/subnet_policy_name"
+	TagScopeSubnetPolicyCRUID          string = "nsx-op/subnet_policy_uid"
+	TagScopeSecurityExclusionCRName    string = "nsx-op/security_exclusion_name"
+	TagScopeSecurityExclusionCRUID     string = "nsx-op/security_exclusion_uid"
+	TagScopeGatewayPolicyCRName        string = "nsx-op/gateway_policy_name"
+	TagScopeGatewayPolicyCRUID         string = "nsx-op/gateway_policy_uid"
+	TagScopeIDSPolicyCRName            string = "nsx-op/ids_policy_name"
+	TagScopeIDSPolicyCRUID             string = "nsx-op/ids_policy_uid"
 	TagScopeRuleID                     string = "nsx-op/rule_id"
 	TagScopeGoupID                     string = "nsx-op/group_id"
 	TagScopeGroupType                  string = "nsx-op/group_type"
@@ -61,6 +80,9 @@ const (
 	LabelDefaultSubnetSet              string = "nsxoperator.vmware.com/default-subnetset-for"
 	LabelDefaultVMSubnetSet            string = "VirtualMachine"
 	LabelDefaultPodSubnetSet           string = "Pod"
+	LabelAntreaPolicyUID               string = "nsxoperator.vmware.com/antrea-policy-uid"
+	LabelPolicyProfile                 string = "nsx.vmware.com/policy-profile"
+	LabelPolicyProfileUID              string = "nsxoperator.vmware.com/policy-profile-uid"
 	DefaultPodSubnetSet                string = "pod-default"
 	DefaultVMSubnetSet                 string = "vm-default"
 	TagScopeSubnetCRUID                string = "nsx-op/subnet_uid"
@@ -71,12 +93,23 @@ const (
 	TagValueGroupSource                string = "source"
 	TagValueGroupDestination           string = "destination"
 	TagValueGroupAvi                   string = "avi"
+	TagValueGroupNetwork               string = "network"
+	TagValueGroupCluster               string = "cluster"
+	TagValueGroupShared                string = "shared"
 	AnnotationVPCNetworkConfig         string = "nsx.vmware.com/vpc_network_config"
 	AnnotationVPCName                  string = "nsx.vmware.com/vpc_name"
 	AnnotationDefaultNetworkConfig     string = "nsx.vmware.com/default"
 	AnnotationAttachmentRef            string = "nsx.vmware.com/attachment_ref"
 	AnnotationPodMAC                   string = "nsx.vmware.com/mac"
 	AnnotationPodAttachment            string = "nsx.vmware.com/attachment"
+	AnnotationNSXSite                  string = "nsx.vmware.com/nsx_site"
+	AnnotationSimulateHours            string = "nsx.vmware.com/simulate_hours"
+	AnnotationRecommendationID         string = "nsx.vmware.com/recommendation_id"
+	AnnotationAntreaPolicy             string = "nsx.vmware.com/antrea_policy"
+	AnnotationRealizedUID              string = "nsx.vmware.com/realized_uid"
+	AnnotationDefaultDeny              string = "nsx.vmware.com/default_deny"
+	AnnotationDryRun                   string = "nsx.vmware.com/dry_run"
+	AnnotationPodNetworkStatus         string = "k8s.v1.cni.cncf.io/network-status"
 	TagScopePodName                    string = "nsx-op/pod_name"
 	TagScopePodUID                     string = "nsx-op/pod_uid"
 	ValueMajorVersion                  string = "1"
@@ -92,34 +125,53 @@ const (
 	IPPoolTypePublic    = "Public"
 	IPPoolTypePrivate   = "Private"
 
-	SecurityPolicyFinalizerName    = "securitypolicy.nsx.vmware.com/finalizer"
-	NetworkPolicyFinalizerName     = "networkpolicy.nsx.vmware.com/finalizer"
-	StaticRouteFinalizerName       = "staticroute.nsx.vmware.com/finalizer"
-	NSXServiceAccountFinalizerName = "nsxserviceaccount.nsx.vmware.com/finalizer"
-	SubnetFinalizerName            = "subnet.nsx.vmware.com/finalizer"
-	SubnetSetFinalizerName         = "subnetset.nsx.vmware.com/finalizer"
-	SubnetPortFinalizerName        = "subnetport.nsx.vmware.com/finalizer"
-	VPCFinalizerName               = "vpc.nsx.vmware.com/finalizer"
-	PodFinalizerName               = "pod.nsx.vmware.com/finalizer"
+	SecurityPolicyFinalizerName     = "securitypolicy.nsx.vmware.com/finalizer"
+	NetworkPolicyFinalizerName      = "networkpolicy.nsx.vmware.com/finalizer"
+	StaticRouteFinalizerName        = "staticroute.nsx.vmware.com/finalizer"
+	SubnetPolicyFinalizerName       = "subnetpolicy.nsx.vmware.com/finalizer"
+	ServiceExposureFinalizerName    = "serviceexposure.nsx.vmware.com/finalizer"
+	SecurityExclusionFinalizerName  = "securityexclusion.nsx.vmware.com/finalizer"
+	GatewayPolicyFinalizerName      = "gatewaypolicy.nsx.vmware.com/finalizer"
+	IDSPolicyFinalizerName          = "idspolicy.nsx.vmware.com/finalizer"
+	AdminNetworkPolicyFinalizerName = "adminnetworkpolicy.nsx.vmware.com/finalizer"
+	NSXServiceAccountFinalizerName  = "nsxserviceaccount.nsx.vmware.com/finalizer"
+	SubnetFinalizerName             = "subnet.nsx.vmware.com/finalizer"
+	SubnetSetFinalizerName          = "subnetset.nsx.vmware.com/finalizer"
+	SubnetPortFinalizerName         = "subnetport.nsx.vmware.com/finalizer"
+	VPCFinalizerName                = "vpc.nsx.vmware.com/finalizer"
+	PodFinalizerName                = "pod.nsx.vmware.com/finalizer"
 
 	IndexKeySubnetID            = "IndexKeySubnetID"
 	IndexKeyPathPath            = "Path"
 	IndexKeyNodeName            = "IndexKeyNodeName"
 	GCValidationInterval uint16 = 720
 
-	RuleSuffixIngressAllow  = "ingress-allow"
-	RuleSuffixEgressAllow   = "egress-allow"
-	RuleSuffixIngressDrop   = "ingress-isolation"
-	RuleSuffixEgressDrop    = "egress-isolation"
-	RuleSuffixIngressReject = "ingress-reject"
-	RuleSuffixEgressReject  = "egress-reject"
-	SecurityPolicyPrefix    = "sp"
-	NetworkPolicyPrefix     = "np"
-	TargetGroupSuffix       = "scope"
-	SrcGroupSuffix          = "src"
-	DstGroupSuffix          = "dst"
-	IpSetGroupSuffix        = "ipset"
-	SharePrefix             = "share"
+	RuleSuffixIngressAllow           = "ingress-allow"
+	RuleSuffixEgressAllow            = "egress-allow"
+	RuleSuffixIngressDrop            = "ingress-isolation"
+	RuleSuffixEgressDrop             = "egress-isolation"
+	RuleSuffixIngressReject          = "ingress-reject"
+	RuleSuffixEgressReject           = "egress-reject"
+	RuleSuffixIngressRedirect        = "ingress-redirect"
+	RuleSuffixEgressRedirect         = "egress-redirect"
+	RuleSuffixIngressPass            = "ingress-pass"
+	RuleSuffixEgressPass             = "egress-pass"
+	SecurityPolicyPrefix             = "sp"
+	NetworkPolicyPrefix              = "np"
+	ServiceExposurePrefix            = "se"
+	AdminNetworkPolicyPrefix         = "anp"
+	BaselineAdminNetworkPolicyPrefix = "banp"
+	NamespaceDefaultDenyPrefix       = "ndd"
+	TargetGroupSuffix                = "scope"
+	SrcGroupSuffix                   = "src"
+	DstGroupSuffix                   = "dst"
+	IpSetGroupSuffix                 = "ipset"
+	SharedGroupSuffix                = "shared"
+	SharePrefix                      = "share"
+	SubnetPolicyPrefix               = "subnetpolicy"
+	SecurityExclusionPrefix          = "exclusion"
+	GatewayPolicyPrefix              = "gwp"
+	IDSPolicyPrefix                  = "ids"
 )
 
 var (
@@ -129,28 +181,46 @@ var (
 )
 
 var (
-	ResourceType                       = "resource_type"
-	ResourceTypeInfra                  = "Infra"
-	ResourceTypeDomain                 = "Domain"
-	ResourceTypeSecurityPolicy         = "SecurityPolicy"
-	ResourceTypeNetworkPolicy          = "NetworkPolicy"
-	ResourceTypeGroup                  = "Group"
-	ResourceTypeRule                   = "Rule"
-	ResourceTypeIPBlock                = "IpAddressBlock"
-	ResourceTypeOrgRoot                = "OrgRoot"
-	ResourceTypeOrg                    = "Org"
-	ResourceTypeProject                = "Project"
-	ResourceTypeVpc                    = "Vpc"
-	ResourceTypeSubnetPort             = "VpcSubnetPort"
-	ResourceTypeVirtualMachine         = "VirtualMachine"
-	ResourceTypeShare                  = "Share"
-	ResourceTypeSharedResource         = "SharedResource"
-	ResourceTypeChildSharedResource    = "ChildSharedResource"
-	ResourceTypeChildShare             = "ChildShare"
-	ResourceTypeChildRule              = "ChildRule"
-	ResourceTypeChildGroup             = "ChildGroup"
-	ResourceTypeChildSecurityPolicy    = "ChildSecurityPolicy"
-	ResourceTypeChildResourceReference = "ChildResourceReference"
+	ResourceType                           = "resource_type"
+	ResourceTypeInfra                      = "Infra"
+	ResourceTypeDomain                     = "Domain"
+	ResourceTypeSecurityPolicy             = "SecurityPolicy"
+	ResourceTypeNetworkPolicy              = "NetworkPolicy"
+	ResourceTypeServiceExposure            = "ServiceExposure"
+	ResourceTypeAdminNetworkPolicy         = "AdminNetworkPolicy"
+	ResourceTypeBaselineAdminNetworkPolicy = "BaselineAdminNetworkPolicy"
+	ResourceTypeNamespaceDefaultDeny       = "NamespaceDefaultDeny"
+	ResourceTypeGatewayPolicy              = "GatewayPolicy"
+	ResourceTypeIDSPolicy                  = "IDSPolicy"
+	ResourceTypeIdsSecurityPolicy          = "IdsSecurityPolicy"
+	ResourceTypeIdsRule                    = "IdsRule"
+	ResourceTypeIdsProfile                 = "IdsProfile"
+	ResourceTypeGroup                      = "Group"
+	ResourceTypeRule                       = "Rule"
+	ResourceTypeIPBlock                    = "IpAddressBlock"
+	ResourceTypeOrgRoot                    = "OrgRoot"
+	ResourceTypeOrg                        = "Org"
+	ResourceTypeProject                    = "Project"
+	ResourceTypeVpc                        = "Vpc"
+	ResourceTypeSubnetPort                 = "VpcSubnetPort"
+	ResourceTypeVirtualMachine             = "VirtualMachine"
+	ResourceTypeShare                      = "Share"
+	ResourceTypeSharedResource             = "SharedResource"
+	ResourceTypeChildSharedResource        = "ChildSharedResource"
+	ResourceTypeChildShare                 = "ChildShare"
+	ResourceTypeChildRule                  = "ChildRule"
+	ResourceTypeChildGroup                 = "ChildGroup"
+	ResourceTypeChildSecurityPolicy        = "ChildSecurityPolicy"
+	ResourceTypeChildGatewayPolicy         = "ChildGatewayPolicy"
+	ResourceTypeChildIdsSecurityPolicy     = "ChildIdsSecurityPolicy"
+	ResourceTypeChildIdsRule               = "ChildIdsRule"
+	ResourceTypeChildResourceReference     = "ChildResourceReference"
+	ResourceTypeRedirectionPolicy          = "RedirectionPolicy"
+	ResourceTypeRedirectionRule            = "RedirectionRule"
+	ResourceTypeChildRedirectionPolicy     = "ChildRedirectionPolicy"
+	ResourceTypeChildRedirectionRule       = "ChildRedirectionRule"
+	ResourceTypeContextProfile             = "PolicyContextProfile"
+	ResourceTypeChildContextProfile        = "ChildPolicyContextProfile"
 
 	// ResourceTypeClusterControlPlane is used by NSXServiceAccountController
 	ResourceTypeClusterControlPlane = "clustercontrolplane"
@@ -205,4 +275,5 @@ type VPCNetworkConfigInfo struct {
 	DefaultIPv4SubnetSize   int
 	DefaultSubnetAccessMode string
 	ShortID                 string
+	NSXDomain               string
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 )
@@ -81,7 +82,7 @@ func (service *NodeService) SyncNodeStore(nodeName string, deleted bool) error {
 		// }
 		// node.NodeStore.Apply(updatedNode)
 	}
-	nodeResults, err := service.NSXClient.HostTransPortNodesClient.List("default", "default", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	nodeResults, err := service.NSXClient.HostTransPortNodesClient.List(config.DefaultSite, service.NSXConfig.GetEnforcementPoint(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to list HostTransportNodes: %s", err)
 	}
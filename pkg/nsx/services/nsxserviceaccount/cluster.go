@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
@@ -30,8 +31,7 @@ import (
 )
 
 const (
-	siteId             = "default"
-	enforcementpointId = "default"
+	siteId             = config.DefaultSite
 	PortRestAPI        = "rest-api"
 	PortNSXRPCFwdProxy = "nsx-rpc-fwd-proxy"
 	// #nosec G101: false positive triggered by variable name which includes "secret"
@@ -190,7 +190,7 @@ func (s *NSXServiceAccountService) RestoreRealizedNSXServiceAccount(ctx context.
 	} else if hasPI || hasCCP || (piObj != nil) || (ccpObj != nil) {
 		return fmt.Errorf("PI/CCP doesn't match")
 	}
-	_, err := s.NSXClient.ClusterControlPlanesClient.Get(siteId, enforcementpointId, normalizedClusterName)
+	_, err := s.NSXClient.ClusterControlPlanesClient.Get(siteId, s.NSXConfig.GetEnforcementPoint(), normalizedClusterName)
 	if err == nil {
 		return fmt.Errorf("CCP store is not synchronized")
 	}
@@ -252,7 +252,7 @@ func (s *NSXServiceAccountService) createPIAndCCP(normalizedClusterName string,
 	hasCCP := len(s.ClusterControlPlaneStore.GetByIndex(common.TagScopeNSXServiceAccountCRUID, string(obj.UID))) > 0
 	clusterId := ""
 	if ccpObj := s.ClusterControlPlaneStore.GetByKey(normalizedClusterName); !hasCCP && ccpObj == nil {
-		ccp, err := s.NSXClient.ClusterControlPlanesClient.Update(siteId, enforcementpointId, normalizedClusterName, model.ClusterControlPlane{
+		ccp, err := s.NSXClient.ClusterControlPlanesClient.Update(siteId, s.NSXConfig.GetEnforcementPoint(), normalizedClusterName, model.ClusterControlPlane{
 			Revision:     &revision1,
 			ResourceType: &antreaClusterResourceType,
 			Certificate:  &cert,
@@ -331,7 +331,7 @@ func (s *NSXServiceAccountService) DeleteNSXServiceAccount(ctx context.Context,
 	// delete ClusterControlPlane
 	if isDeleteCCP {
 		cascade := true
-		if err := s.NSXClient.ClusterControlPlanesClient.Delete(siteId, enforcementpointId, normalizedClusterName, &cascade); err != nil {
+		if err := s.NSXClient.ClusterControlPlanesClient.Delete(siteId, s.NSXConfig.GetEnforcementPoint(), normalizedClusterName, &cascade); err != nil {
 			log.Error(err, "failed to delete", "ClusterControlPlane", normalizedClusterName)
 			return err
 		}
@@ -432,7 +432,7 @@ func (s *NSXServiceAccountService) updatePIAndCCPCert(normalizedClusterName, uid
 	// update ClusterControlPlane cert
 	ccp := ccpObj.(model.ClusterControlPlane)
 	ccp.Certificate = &cert
-	if ccp, err := s.NSXClient.ClusterControlPlanesClient.Update(siteId, enforcementpointId, normalizedClusterName, ccp); err != nil {
+	if ccp, err := s.NSXClient.ClusterControlPlanesClient.Update(siteId, s.NSXConfig.GetEnforcementPoint(), normalizedClusterName, ccp); err != nil {
 		return err
 	} else {
 		s.ClusterControlPlaneStore.Add(ccp)
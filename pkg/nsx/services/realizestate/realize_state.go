@@ -6,6 +6,7 @@ package realizestate
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -34,27 +35,66 @@ func IsRealizeStateError(err error) bool {
 // CheckRealizeState allows the caller to check realize status of entityType with retries.
 // backoff defines the maximum retries and the wait interval between two retries.
 func (service *RealizeStateService) CheckRealizeState(backoff wait.Backoff, intentPath, entityType string) error {
-	vpcInfo, err := common.ParseVPCResourcePath(intentPath)
-	if err != nil {
+	if _, err := common.ParseVPCResourcePath(intentPath); err != nil {
 		return err
 	}
 	return retry.OnError(backoff, func(err error) bool {
 		// Won't retry when realized state is `ERROR`.
 		return !IsRealizeStateError(err)
 	}, func() error {
-		results, err := service.NSXClient.RealizedEntitiesClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, intentPath, nil)
-		if err != nil {
-			return err
+		return service.checkRealizeStateOnce(intentPath, entityType)
+	})
+}
+
+// checkRealizeStateOnce returns nil if entityType of intentPath is realized, otherwise an error
+// with the realized state or the failure of the query. All the entities realized for intentPath
+// are checked if entityType is empty.
+func (service *RealizeStateService) checkRealizeStateOnce(intentPath, entityType string) error {
+	results, err := service.listRealizedEntities(intentPath)
+	if err != nil {
+		return err
+	}
+	if entityType == "" {
+		return checkAllRealized(intentPath, results.Results)
+	}
+	for _, result := range results.Results {
+		if *result.EntityType != entityType {
+			continue
 		}
-		for _, result := range results.Results {
-			if *result.EntityType != entityType {
-				continue
-			}
-			if *result.State == model.GenericPolicyRealizedResource_STATE_REALIZED {
-				return nil
-			}
+		if *result.State == model.GenericPolicyRealizedResource_STATE_REALIZED {
+			return nil
+		}
+		return errors.New(*result.State)
+	}
+	return fmt.Errorf("%s not realized", entityType)
+}
+
+// listRealizedEntities lists the entities realized for the intent, which is under a VPC or under /infra.
+func (service *RealizeStateService) listRealizedEntities(intentPath string) (model.GenericPolicyRealizedResourceListResult, error) {
+	if strings.HasPrefix(intentPath, "/infra/") {
+		return service.NSXClient.InfraRealizedStateClient.List(intentPath, nil)
+	}
+	vpcInfo, err := common.ParseVPCResourcePath(intentPath)
+	if err != nil {
+		return model.GenericPolicyRealizedResourceListResult{}, err
+	}
+	return service.NSXClient.RealizedEntitiesClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, intentPath, nil)
+}
+
+// checkAllRealized returns the ERROR state if any entity is realized with error, otherwise an error with
+// the state of the first entity not realized yet.
+func checkAllRealized(intentPath string, results []model.GenericPolicyRealizedResource) error {
+	if len(results) == 0 {
+		return fmt.Errorf("%s not realized", intentPath)
+	}
+	var pending error
+	for _, result := range results {
+		switch {
+		case *result.State == model.GenericPolicyRealizedResource_STATE_ERROR:
 			return errors.New(*result.State)
+		case *result.State != model.GenericPolicyRealizedResource_STATE_REALIZED && pending == nil:
+			pending = errors.New(*result.State)
 		}
-		return fmt.Errorf("%s not realized", entityType)
-	})
+	}
+	return pending
 }
@@ -0,0 +1,142 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package realizestate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	DefaultWatcherWorkers    = 4
+	DefaultWatcherMaxRetries = 12
+	watcherBaseDelay         = 1 * time.Second
+	watcherMaxDelay          = 2 * time.Minute
+)
+
+var (
+	log         = logger.Log
+	watcherPool *WatcherPool
+	watcherLock = &sync.Mutex{}
+)
+
+// RealizeCallback is called once when the realization of the watched intent completes, err is nil
+// if it is realized, otherwise the realized state error or the timeout error.
+// It is usually used to update the conditions of the CR the intent is created for.
+type RealizeCallback func(intentPath, entityType string, err error)
+
+type watchKey struct {
+	intentPath string
+	entityType string
+}
+
+// WatcherPool tracks the pending realization of recently patched NSX intents for all the services,
+// so that reconcile doesn't wait for the realization. The intents are checked by a bounded number of
+// workers with exponential backoff between two checks of the same intent.
+type WatcherPool struct {
+	queue      workqueue.RateLimitingInterface
+	workers    int
+	maxRetries int
+	check      func(intentPath, entityType string) error
+
+	mu        sync.Mutex
+	callbacks map[watchKey][]RealizeCallback
+}
+
+// NewWatcherPool creates a pool checking the realization with the service.
+func NewWatcherPool(service *RealizeStateService, workers, maxRetries int) *WatcherPool {
+	return &WatcherPool{
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(watcherBaseDelay, watcherMaxDelay), "realization-watcher"),
+		workers:    workers,
+		maxRetries: maxRetries,
+		check:      service.checkRealizeStateOnce,
+		callbacks:  make(map[watchKey][]RealizeCallback),
+	}
+}
+
+// GetWatcherPool get singleton WatcherPool instance which is shared by all the services, the workers
+// are started on the first call.
+func GetWatcherPool(service common.Service) *WatcherPool {
+	watcherLock.Lock()
+	defer watcherLock.Unlock()
+	if watcherPool == nil {
+		watcherPool = NewWatcherPool(InitializeRealizeState(service), DefaultWatcherWorkers, DefaultWatcherMaxRetries)
+		watcherPool.Start()
+	}
+	return watcherPool
+}
+
+// Watch adds the intent to the pool, the callbacks of an intent already being watched are merged,
+// and all of them are called when the realization completes.
+func (p *WatcherPool) Watch(intentPath, entityType string, callback RealizeCallback) {
+	key := watchKey{intentPath: intentPath, entityType: entityType}
+	p.mu.Lock()
+	p.callbacks[key] = append(p.callbacks[key], callback)
+	p.mu.Unlock()
+	p.queue.Add(key)
+}
+
+// Pending returns the number of intents whose realization is being watched.
+func (p *WatcherPool) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.callbacks)
+}
+
+// Start launches the workers, they exit when Stop is called.
+func (p *WatcherPool) Start() {
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			for p.processNextItem() {
+			}
+		}()
+	}
+}
+
+func (p *WatcherPool) Stop() {
+	p.queue.ShutDown()
+}
+
+func (p *WatcherPool) processNextItem() bool {
+	item, shutdown := p.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer p.queue.Done(item)
+	key := item.(watchKey)
+
+	err := p.check(key.intentPath, key.entityType)
+	switch {
+	case err == nil:
+		log.V(1).Info("intent realized", "intentPath", key.intentPath, "entityType", key.entityType)
+	case IsRealizeStateError(err):
+		log.Error(err, "intent realized with error", "intentPath", key.intentPath, "entityType", key.entityType)
+	case p.queue.NumRequeues(item) < p.maxRetries:
+		p.queue.AddRateLimited(item)
+		return true
+	default:
+		err = fmt.Errorf("%s of %s is not realized after %d retries: %w", key.entityType, key.intentPath, p.maxRetries, err)
+		log.Error(err, "stop watching realization")
+	}
+	p.queue.Forget(item)
+	p.complete(key, err)
+	return true
+}
+
+func (p *WatcherPool) complete(key watchKey, err error) {
+	p.mu.Lock()
+	callbacks := p.callbacks[key]
+	delete(p.callbacks, key)
+	p.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(key.intentPath, key.entityType, err)
+	}
+}
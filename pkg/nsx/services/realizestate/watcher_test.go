@@ -0,0 +1,69 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package realizestate
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newTestWatcherPool(check func(intentPath, entityType string) error) *WatcherPool {
+	return &WatcherPool{
+		// no delay between retries in UT
+		queue:      workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(0, 0)),
+		workers:    2,
+		maxRetries: 3,
+		check:      check,
+		callbacks:  make(map[watchKey][]RealizeCallback),
+	}
+}
+
+func TestWatcherPool(t *testing.T) {
+	lock := sync.Mutex{}
+	checks := map[string]int{}
+	pool := newTestWatcherPool(func(intentPath, _ string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		checks[intentPath]++
+		switch intentPath {
+		case "realized":
+			if checks[intentPath] < 2 {
+				return errors.New(model.GenericPolicyRealizedResource_STATE_UNREALIZED)
+			}
+			return nil
+		case "error":
+			return errors.New(model.GenericPolicyRealizedResource_STATE_ERROR)
+		default:
+			return errors.New(model.GenericPolicyRealizedResource_STATE_UNREALIZED)
+		}
+	})
+
+	wg := sync.WaitGroup{}
+	results := map[string]error{}
+	callback := func(intentPath, _ string, err error) {
+		lock.Lock()
+		results[intentPath] = err
+		lock.Unlock()
+		wg.Done()
+	}
+	wg.Add(3)
+	pool.Watch("realized", "RealizedLogicalPort", callback)
+	pool.Watch("error", "RealizedLogicalPort", callback)
+	pool.Watch("timeout", "RealizedLogicalPort", callback)
+	pool.Start()
+	defer pool.Stop()
+	wg.Wait()
+
+	assert.Nil(t, results["realized"])
+	assert.True(t, IsRealizeStateError(results["error"]))
+	assert.Equal(t, 1, checks["error"])
+	assert.Error(t, results["timeout"])
+	assert.Equal(t, 4, checks["timeout"])
+	assert.Equal(t, 0, pool.Pending())
+}
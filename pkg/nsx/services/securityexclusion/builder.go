@@ -0,0 +1,111 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// The Namespaces are matched by the labels NCP tags their Segments with, and the Pods by the labels NCP tags
+// their SegmentPorts with.
+const (
+	memberTypeNamespace = "Segment"
+	memberTypePod       = "SegmentPort"
+)
+
+func buildGroupID(uid types.UID) string {
+	return util.GenerateID(string(uid), common.SecurityExclusionPrefix, "", "")
+}
+
+func isEmptySelector(selector *metav1.LabelSelector) bool {
+	return selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0)
+}
+
+// buildSecurityExclusionGroup builds the group of the Pods the SecurityExclusion excludes from DFW. The group
+// has one criterion matching the Segments of the cluster and the selected Namespaces, and the SegmentPorts of
+// the selected Pods on them, so the Pods created later are excluded without the group being updated.
+func (service *SecurityExclusionService) buildSecurityExclusionGroup(obj *v1alpha1.SecurityExclusion) (*model.Group, error) {
+	// an empty SecurityExclusion would exclude all the Pods of the cluster
+	if isEmptySelector(obj.Spec.NamespaceSelector) && isEmptySelector(obj.Spec.PodSelector) {
+		return nil, nsxutil.RestrictionError{Desc: "neither namespaceSelector nor podSelector selects by labels"}
+	}
+	var expressions []*data.StructValue
+	conditions := expression.Nested(&expressions)
+	conditions.Add(expression.TagCondition(memberTypeNamespace,
+		fmt.Sprintf("%s|%s", common.TagScopeNCPCluster, service.NSXConfig.Cluster), "EQUALS", "EQUALS").Build())
+	if err := addSelectorConditions(obj.Spec.NamespaceSelector, memberTypeNamespace, conditions); err != nil {
+		return nil, err
+	}
+	if err := addSelectorConditions(obj.Spec.PodSelector, memberTypePod, conditions); err != nil {
+		return nil, err
+	}
+	if err := expression.Validate(expressions); err != nil {
+		return nil, nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid selectors: %v", err)}
+	}
+
+	groupID := buildGroupID(obj.UID)
+	return &model.Group{
+		Id:          String(groupID),
+		DisplayName: String(util.GenerateTruncName(common.MaxNameLength, obj.Name, common.SecurityExclusionPrefix, "", "", "")),
+		Path:        String(fmt.Sprintf("/infra/domains/%s/groups/%s", service.NSXConfig.GetDomain(), groupID)),
+		Expression:  expressions,
+		Tags:        util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+	}, nil
+}
+
+// addSelectorConditions adds the conditions of the label selector joined by AND to the criterion. An "In"
+// requirement can't be expressed in one criterion unless it has one value, and "NotIn" is not supported by NSX
+// for the Segments.
+func addSelectorConditions(selector *metav1.LabelSelector, memberType string, conditions *data.ListValue) error {
+	if selector == nil {
+		return nil
+	}
+	matchLabels := *util.NormalizeLabels(&selector.MatchLabels)
+	keys := make([]string, 0, len(matchLabels))
+	for key := range matchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expression.AddConjunction(conditions, expression.And)
+		conditions.Add(expression.TagCondition(memberType, fmt.Sprintf("%s|%s", key, matchLabels[key]), "EQUALS", "EQUALS").Build())
+	}
+	for _, expr := range selector.MatchExpressions {
+		var condition expression.Condition
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn:
+			if len(expr.Values) != 1 {
+				return nsxutil.RestrictionError{Desc: fmt.Sprintf("operator 'In' of key %s with %d values is not supported", expr.Key, len(expr.Values))}
+			}
+			condition = expression.TagCondition(memberType, fmt.Sprintf("%s|%s", expr.Key, expr.Values[0]), "EQUALS", "EQUALS")
+		case metav1.LabelSelectorOpNotIn:
+			if memberType == memberTypeNamespace {
+				return nsxutil.RestrictionError{Desc: "operator 'NotIn' for namespaceSelector is not supported in NSX-T since its member type is Segment"}
+			}
+			condition = expression.TagCondition(memberType, fmt.Sprintf("%s|%s", expr.Key, strings.Join(expr.Values, ",")), "NOTIN", "EQUALS")
+		case metav1.LabelSelectorOpExists:
+			condition = expression.TagCondition(memberType, fmt.Sprintf("%s|", expr.Key), "EQUALS", "EQUALS")
+		case metav1.LabelSelectorOpDoesNotExist:
+			condition = expression.TagCondition(memberType, fmt.Sprintf("%s|", expr.Key), "", "NOTEQUALS")
+		default:
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid operator %s in matchExpressions", expr.Operator)}
+		}
+		expression.AddConjunction(conditions, expression.And)
+		conditions.Add(condition.Build())
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func fakeService() *SecurityExclusionService {
+	service := &SecurityExclusionService{}
+	service.NSXConfig = &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"}}
+	return service
+}
+
+// conditionsOf returns the conditions of the criterion of the group as member_type:value.
+func conditionsOf(t *testing.T, expressions []*data.StructValue) []string {
+	assert.Len(t, expressions, 1)
+	nested, _ := expressions[0].Field("expressions")
+	var conditions []string
+	for _, item := range nested.(*data.ListValue).List() {
+		expr := item.(*data.StructValue)
+		if resourceType, _ := expr.String("resource_type"); resourceType != "Condition" {
+			continue
+		}
+		memberType, _ := expr.String("member_type")
+		value, _ := expr.String("value")
+		conditions = append(conditions, fmt.Sprintf("%s:%s", memberType, value))
+	}
+	return conditions
+}
+
+func TestBuildSecurityExclusionGroup(t *testing.T) {
+	service := fakeService()
+	obj := &v1alpha1.SecurityExclusion{
+		ObjectMeta: metav1.ObjectMeta{Name: "cni-agents", UID: "uid1"},
+		Spec: v1alpha1.SecurityExclusionSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"}},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels:      map[string]string{"app": "cni"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpExists}},
+			},
+		},
+	}
+	group, err := service.buildSecurityExclusionGroup(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "exclusion_uid1", *group.Id)
+	assert.Equal(t, "/infra/domains/k8scl-one/groups/exclusion_uid1", *group.Path)
+	assert.Equal(t, []string{
+		"Segment:ncp/cluster|k8scl-one",
+		"Segment:kubernetes.io/metadata.name|kube-system",
+		"SegmentPort:app|cni",
+		"SegmentPort:tier|",
+	}, conditionsOf(t, group.Expression))
+
+	// all the Pods of the selected Namespaces are excluded
+	obj.Spec.PodSelector = nil
+	group, err = service.buildSecurityExclusionGroup(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Segment:ncp/cluster|k8scl-one", "Segment:kubernetes.io/metadata.name|kube-system"}, conditionsOf(t, group.Expression))
+
+	obj.Spec.NamespaceSelector = &metav1.LabelSelector{}
+	_, err = service.buildSecurityExclusionGroup(obj)
+	assert.EqualError(t, err, "neither namespaceSelector nor podSelector selects by labels")
+
+	obj.Spec.NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}}}
+	_, err = service.buildSecurityExclusionGroup(obj)
+	assert.EqualError(t, err, "operator 'NotIn' for namespaceSelector is not supported in NSX-T since its member type is Segment")
+
+	obj.Spec.NamespaceSelector = nil
+	obj.Spec.PodSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}}}}
+	_, err = service.buildSecurityExclusionGroup(obj)
+	assert.EqualError(t, err, "operator 'In' of key app with 2 values is not supported")
+
+	obj.Spec.PodSelector.MatchLabels = map[string]string{}
+	for i := 0; i < 15; i++ {
+		obj.Spec.PodSelector.MatchLabels[fmt.Sprintf("label%d", i)] = "v"
+	}
+	obj.Spec.PodSelector.MatchExpressions = nil
+	_, err = service.buildSecurityExclusionGroup(obj)
+	assert.EqualError(t, err, "invalid selectors: nested expression 0: 16 conditions exceed NSX limit of 15")
+}
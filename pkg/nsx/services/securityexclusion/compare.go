@@ -0,0 +1,26 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+type Group model.Group
+
+func (group *Group) Key() string {
+	return *group.Id
+}
+
+func (group *Group) Value() data.DataValue {
+	g := &model.Group{
+		Id:          group.Id,
+		DisplayName: group.DisplayName,
+		Tags:        group.Tags,
+		Expression:  group.Expression,
+	}
+	dataValue, _ := g.GetDataValue__()
+	return dataValue
+}
@@ -0,0 +1,159 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// SecurityExclusionService realizes SecurityExclusion as a group of the selected Pods, which is a member of the
+// DFW exclusion list of NSX.
+type SecurityExclusionService struct {
+	common.Service
+	GroupStore *GroupStore
+	// excludeListLock serializes the updates of the exclusion list shared by all the SecurityExclusions
+	excludeListLock sync.Mutex
+}
+
+var (
+	log    = logger.Log
+	String = common.String
+)
+
+// InitializeSecurityExclusion sync NSX resources
+func InitializeSecurityExclusion(commonService common.Service) (*SecurityExclusionService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(1)
+	securityExclusionService := &SecurityExclusionService{Service: commonService}
+	securityExclusionService.GroupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeSecurityExclusionCRUID: indexFunc}),
+		BindingType: model.GroupBindingType(),
+	}}
+
+	tags := []model.Tag{{Scope: String(common.TagScopeSecurityExclusionCRUID)}}
+	go securityExclusionService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeGroup, tags, securityExclusionService.GroupStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return securityExclusionService, err
+	}
+
+	return securityExclusionService, nil
+}
+
+// CreateOrUpdateSecurityExclusion realizes the group of the Pods the SecurityExclusion selects, and makes sure
+// the group is a member of the exclusion list.
+func (service *SecurityExclusionService) CreateOrUpdateSecurityExclusion(obj *v1alpha1.SecurityExclusion) error {
+	if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+		log.Info("no DFW license, skip creating SecurityExclusion.")
+		return nsxutil.RestrictionError{Desc: "no DFW license"}
+	}
+	group, err := service.buildSecurityExclusionGroup(obj)
+	if err != nil {
+		return err
+	}
+	existingGroup := service.GroupStore.GetByKey(*group.Id)
+	if existingGroup == nil || common.CompareResource((*Group)(existingGroup), (*Group)(group)) {
+		if err := service.NSXClient.GroupClient.Patch(service.NSXConfig.GetDomain(), *group.Id, *group); err != nil {
+			return err
+		}
+		if err := service.GroupStore.Add(group); err != nil {
+			return err
+		}
+		log.Info("successfully patched NSX group for SecurityExclusion", "group", *group.Id)
+	}
+	return service.updateExcludeList(*group.Path, true)
+}
+
+// DeleteSecurityExclusion removes the group of the SecurityExclusion of the UID from the exclusion list, then
+// deletes it, the group can't be deleted while it's a member of the exclusion list.
+func (service *SecurityExclusionService) DeleteSecurityExclusion(uid types.UID) error {
+	group := service.GroupStore.GetByKey(buildGroupID(uid))
+	if group == nil {
+		return nil
+	}
+	if err := service.updateExcludeList(*group.Path, false); err != nil {
+		return err
+	}
+	if err := service.NSXClient.GroupClient.Delete(service.NSXConfig.GetDomain(), *group.Id, nil, nil); err != nil {
+		return err
+	}
+	if err := service.GroupStore.Delete(group); err != nil {
+		return err
+	}
+	log.Info("successfully deleted NSX group for SecurityExclusion", "group", *group.Id)
+	return nil
+}
+
+// updateExcludeList adds the group to or removes it from the members of the exclusion list. The list is shared
+// with the members added by the admins on NSX, so it's read and updated as a whole with its revision, the update
+// fails instead of overwriting the members changed in between, and the SecurityExclusion is retried.
+func (service *SecurityExclusionService) updateExcludeList(groupPath string, member bool) error {
+	service.excludeListLock.Lock()
+	defer service.excludeListLock.Unlock()
+
+	excludeList, err := service.NSXClient.ExcludeListClient.Get()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(excludeList.Members, groupPath) == member {
+		return nil
+	}
+	if member {
+		excludeList.Members = append(excludeList.Members, groupPath)
+	} else {
+		excludeList.Members = slices.DeleteFunc(excludeList.Members, func(path string) bool { return path == groupPath })
+	}
+	if _, err := service.NSXClient.ExcludeListClient.Update(excludeList); err != nil {
+		return err
+	}
+	log.Info("successfully updated NSX exclusion list", "group", groupPath, "member", member)
+	return nil
+}
+
+// ListSecurityExclusionID returns the UIDs of the SecurityExclusions which have NSX groups realized.
+func (service *SecurityExclusionService) ListSecurityExclusionID() sets.Set[string] {
+	return service.GroupStore.ListIndexFuncValues(common.TagScopeSecurityExclusionCRUID)
+}
+
+func (service *SecurityExclusionService) Cleanup(ctx context.Context) error {
+	uids := service.ListSecurityExclusionID()
+	log.Info("cleaning up securityexclusion", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteSecurityExclusion(types.UID(uid)); err != nil {
+				log.Error(err, "remove securityexclusion failed", "uid", uid)
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securityexclusion
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// GroupStore is a store for the groups of the Pods SecurityExclusion excludes from DFW
+type GroupStore struct {
+	common.ResourceStore
+}
+
+// keyFunc is used to get the key of a resource, usually, which is the ID of the resource
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *model.Group:
+		return *v.Id, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+// indexFunc is used to get index of a resource, usually, which is the UID of the CR controller reconciles,
+// index is used to filter out resources which are related to the CR
+func indexFunc(obj interface{}) ([]string, error) {
+	switch v := obj.(type) {
+	case *model.Group:
+		return filterTag(v.Tags), nil
+	default:
+		return nil, errors.New("indexFunc doesn't support unknown type")
+	}
+}
+
+var filterTag = func(v []model.Tag) []string {
+	res := make([]string, 0, 5)
+	for _, tag := range v {
+		if *tag.Scope == common.TagScopeSecurityExclusionCRUID {
+			res = append(res, *tag.Tag)
+		}
+	}
+	return res
+}
+
+func (groupStore *GroupStore) Apply(i interface{}) error {
+	// not used by securityexclusion since securityexclusion doesn't use hierarchy API
+	return nil
+}
+
+func (groupStore *GroupStore) GetByKey(key string) *model.Group {
+	obj := groupStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		return obj.(*model.Group)
+	}
+	return nil
+}
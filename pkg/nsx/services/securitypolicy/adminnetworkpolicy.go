@@ -0,0 +1,109 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// An AdminNetworkPolicy is realized as an internal SecurityPolicy in each Namespace of its subject, the
+// internal UIDs start with the UID of the AdminNetworkPolicy followed by "_". The admin policies are in a DFW
+// category enforced before the Application category of the namespace policies, so they can't be overridden
+// by the SecurityPolicies and the NetworkPolicies. The BaselineAdminNetworkPolicy is realized the same way,
+// but after the namespace policies.
+
+const (
+	policyCategoryInfrastructure = "Infrastructure"
+	policyCategoryEnvironment    = "Environment"
+)
+
+// policyCategory returns the DFW category of the NSX SecurityPolicy created for the owner of the type
+// createdFor, the NSX SecurityPolicy is in the default Application category if it's empty.
+func (service *SecurityPolicyService) policyCategory(createdFor string) string {
+	if createdFor != common.ResourceTypeAdminNetworkPolicy {
+		return ""
+	}
+	cf := service.NSXConfig
+	if cf != nil && cf.K8sConfig != nil && strings.EqualFold(cf.AdminNetworkPolicyCategory, policyCategoryInfrastructure) {
+		return policyCategoryInfrastructure
+	}
+	return policyCategoryEnvironment
+}
+
+func (service *SecurityPolicyService) BuildAdminNetworkPolicyID(uid types.UID, namespace string) string {
+	return fmt.Sprintf("%s_%s", uid, namespace)
+}
+
+// AdminNetworkPolicyUIDOf returns the UID of the AdminNetworkPolicy the internal SecurityPolicy is converted from.
+func AdminNetworkPolicyUIDOf(internalUID string) string {
+	return strings.SplitN(internalUID, "_", 2)[0]
+}
+
+// CreateOrUpdateAdminNetworkPolicy realizes the internal SecurityPolicies converted from the (Baseline)
+// AdminNetworkPolicy of the type createdFor, and deletes the ones of the Namespaces which are not in its
+// subject any more.
+func (service *SecurityPolicyService) CreateOrUpdateAdminNetworkPolicy(uid types.UID, createdFor string, internalSecurityPolicies []*v1alpha1.SecurityPolicy) error {
+	if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+		log.Info("no DFW license, skip creating AdminNetworkPolicy.")
+		return nsxutil.RestrictionError{Desc: "no DFW license"}
+	}
+	if isVpcEnabled(service) {
+		return nsxutil.RestrictionError{Desc: "the AdminNetworkPolicies are not supported in VPC mode"}
+	}
+	desired := sets.New[string]()
+	for _, internalSecurityPolicy := range internalSecurityPolicies {
+		if err := service.createOrUpdateSecurityPolicy(internalSecurityPolicy, createdFor); err != nil {
+			return err
+		}
+		desired.Insert(string(internalSecurityPolicy.UID))
+	}
+	for internalUID := range service.listAdminNetworkPolicyIDs(uid) {
+		if desired.Has(internalUID) {
+			continue
+		}
+		log.Info("deleting the rules of the stale subject Namespace", "adminNetworkPolicy", uid, "UID", internalUID)
+		if err := service.deleteSecurityPolicy(types.UID(internalUID), false, createdFor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAdminNetworkPolicy deletes the rules realized for the (Baseline)AdminNetworkPolicy in all the Namespaces.
+func (service *SecurityPolicyService) DeleteAdminNetworkPolicy(uid types.UID) error {
+	for internalUID := range service.listAdminNetworkPolicyIDs(uid) {
+		if err := service.deleteSecurityPolicy(types.UID(internalUID), false, common.ResourceTypeAdminNetworkPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListAdminNetworkPolicyID returns the UIDs of the internal SecurityPolicies converted from the
+// AdminNetworkPolicies and the BaselineAdminNetworkPolicies.
+func (service *SecurityPolicyService) ListAdminNetworkPolicyID() sets.Set[string] {
+	groupSet := service.groupStore.ListIndexFuncValues(common.TagScopeAdminNetworkPolicyUID)
+	shareSet := service.shareStore.ListIndexFuncValues(common.TagScopeAdminNetworkPolicyUID)
+	policySet := service.securityPolicyStore.ListIndexFuncValues(common.TagScopeAdminNetworkPolicyUID)
+
+	return groupSet.Union(policySet).Union(shareSet)
+}
+
+func (service *SecurityPolicyService) listAdminNetworkPolicyIDs(uid types.UID) sets.Set[string] {
+	ids := sets.New[string]()
+	for internalUID := range service.ListAdminNetworkPolicyID() {
+		if AdminNetworkPolicyUIDOf(internalUID) == string(uid) {
+			ids.Insert(internalUID)
+		}
+	}
+	return ids
+}
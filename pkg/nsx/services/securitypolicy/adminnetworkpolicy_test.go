@@ -0,0 +1,66 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_AdminNetworkPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	pass := v1alpha1.RuleActionPass
+	sp := spWithPodSelector.DeepCopy()
+	sp.UID = types.UID(service.BuildAdminNetworkPolicyID("anp-uid", sp.Namespace))
+	sp.Spec.Rules = sp.Spec.Rules[:1]
+	sp.Spec.Rules[0].Action = &pass
+	assert.Equal(t, "anp-uid", AdminNetworkPolicyUIDOf(string(sp.UID)))
+
+	// the admin policy is in the Environment category, the Pass rules jump to the Application category
+	nsxSecurityPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeAdminNetworkPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "Environment", *nsxSecurityPolicy.Category)
+	assert.True(t, strings.HasPrefix(*nsxSecurityPolicy.Id, common.AdminNetworkPolicyPrefix))
+	for _, rule := range nsxSecurityPolicy.Rules {
+		assert.Equal(t, model.Rule_ACTION_JUMP_TO_APPLICATION, *rule.Action)
+	}
+	for _, tag := range nsxSecurityPolicy.Tags {
+		if *tag.Scope == common.TagScopeAdminNetworkPolicyUID {
+			assert.Equal(t, string(sp.UID), *tag.Tag)
+		}
+	}
+
+	// the Pass action is not supported by the Infrastructure category and the SecurityPolicies
+	service.NSXConfig.K8sConfig = &config.K8sConfig{AdminNetworkPolicyCategory: "Infrastructure"}
+	defer func() { service.NSXConfig.K8sConfig = nil }()
+	_, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeAdminNetworkPolicy)
+	assert.EqualError(t, err, "the Pass action is only supported in the Environment category")
+	_, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "the Pass action is only supported in the Environment category")
+
+	// the baseline policy is in the default category
+	allow := v1alpha1.RuleActionAllow
+	sp.Spec.Rules[0].Action = &allow
+	nsxSecurityPolicy, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeBaselineAdminNetworkPolicy)
+	assert.NoError(t, err)
+	assert.Nil(t, nsxSecurityPolicy.Category)
+	assert.True(t, strings.HasPrefix(*nsxSecurityPolicy.Id, common.BaselineAdminNetworkPolicyPrefix))
+}
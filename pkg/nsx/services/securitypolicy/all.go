@@ -0,0 +1,93 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// AppliedToAllVerb is the RBAC verb on securitypolicies required to set the appliedTo selecting all
+// the workloads of the cluster.
+const AppliedToAllVerb = "applyto-all"
+
+// UsesAppliedToAll returns whether the SecurityPolicy or any of its rules is applied to all the workloads
+// of the cluster.
+func UsesAppliedToAll(obj *v1alpha1.SecurityPolicy) bool {
+	for _, target := range obj.Spec.AppliedTo {
+		if target.All {
+			return true
+		}
+	}
+	for _, rule := range obj.Spec.Rules {
+		for _, target := range rule.AppliedTo {
+			if target.All {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (service *SecurityPolicyService) buildClusterGroupID() string {
+	return util.GenerateID(getCluster(service), common.SecurityPolicyPrefix, "", "all")
+}
+
+func (service *SecurityPolicyService) buildClusterGroupPath() string {
+	return fmt.Sprintf("/infra/domains/%s/groups/%s", getDomain(service), service.buildClusterGroupID())
+}
+
+// buildClusterGroup builds the NSX group of all the workloads of the cluster, which is referred to by the
+// targets selecting all.
+func (service *SecurityPolicyService) buildClusterGroup() *model.Group {
+	return &model.Group{
+		Id:          String(service.buildClusterGroupID()),
+		DisplayName: String(util.GenerateTruncName(common.MaxNameLength, getCluster(service), "", "all", "", "")),
+		Expression: []*data.StructValue{
+			expression.TagCondition("Segment", fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)), "EQUALS", "EQUALS").Build(),
+		},
+		Tags: []model.Tag{
+			{Scope: String(common.TagScopeCluster), Tag: String(getCluster(service))},
+			{Scope: String(common.TagScopeVersion), Tag: String(strings.Join(common.TagValueVersion, "."))},
+			{Scope: String(common.TagScopeGroupType), Tag: String(common.TagValueGroupCluster)},
+		},
+	}
+}
+
+// SyncClusterGroup creates or updates the NSX group of all the workloads of the cluster. Like the groups of
+// the cluster networks, it's only maintained without VPC.
+func (service *SecurityPolicyService) SyncClusterGroup() error {
+	if isVpcEnabled(service) {
+		return nil
+	}
+	group := service.buildClusterGroup()
+	if err := service.NSXClient.GroupClient.Patch(getDomain(service), *group.Id, *group); err != nil {
+		return err
+	}
+	log.Info("synced NSX group of all the cluster workloads", "group", *group.Id)
+	return nil
+}
+
+// updateAllTargetExpressions refers the target group to the group of all the workloads of the cluster.
+// With VPC, the target group selects the workloads of the cluster by itself instead.
+func (service *SecurityPolicyService) updateAllTargetExpressions(target *v1alpha1.SecurityPolicyTarget, group *model.Group) (bool, error) {
+	if target.PodSelector != nil || target.VMSelector != nil {
+		return false, errors.New("all is not allowed to set with PodSelector or VMSelector")
+	}
+	if isVpcEnabled(service) {
+		return false, nil
+	}
+	expression.AppendConjunction(&group.Expression, expression.Or)
+	group.Expression = append(group.Expression, expression.Paths([]string{service.buildClusterGroupPath()}))
+	return true, nil
+}
@@ -0,0 +1,74 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_AppliedToAll(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	groupClient := &fakeGroupsClient{patched: map[string]model.Group{}}
+	service.NSXClient.GroupClient = groupClient
+
+	// the group of all the cluster workloads is synced
+	assert.NoError(t, service.SyncClusterGroup())
+	group, ok := groupClient.patched["k8scl-one:test/sp_k8scl-one:test_all"]
+	assert.True(t, ok)
+	assert.Len(t, group.Expression, 1)
+
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{All: true}},
+		},
+	}
+	assert.True(t, UsesAppliedToAll(sp))
+	policyGroup, path, err := service.buildPolicyGroup(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "ANY", path)
+	assert.Len(t, policyGroup.Expression, 1)
+	paths, _ := policyGroup.Expression[0].Field("paths")
+	assert.Equal(t, []data.DataValue{data.NewStringValue("/infra/domains/k8scl-one:test/groups/sp_k8scl-one:test_all")}, paths.(*data.ListValue).List())
+
+	// all can't be set with the selectors
+	sp.Spec.AppliedTo[0].PodSelector = &metav1.LabelSelector{}
+	_, _, err = service.buildPolicyGroup(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "all is not allowed to set with PodSelector or VMSelector")
+
+	// with VPC, the group is not synced and the target group selects the cluster workloads by itself
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+	groupClient.patched = map[string]model.Group{}
+	assert.NoError(t, service.SyncClusterGroup())
+	assert.Empty(t, groupClient.patched)
+	target := &v1alpha1.SecurityPolicyTarget{All: true}
+	group = model.Group{}
+	_, _, err = service.updateTargetExpressions(sp, target, &group, -1)
+	assert.NoError(t, err)
+	assert.Len(t, group.Expression, 1)
+	resourceType, _ := group.Expression[0].Field("resource_type")
+	assert.Equal(t, data.NewStringValue("NestedExpression"), resourceType)
+
+	sp.Spec.AppliedTo = nil
+	assert.False(t, UsesAppliedToAll(sp))
+}
@@ -0,0 +1,44 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// The App IDs of a rule are matched by the APP_ID attribute of the context profile of the rule, NSX identifies
+// the application of the traffic by deep packet inspection, e.g. SSL, DNS, HTTP, regardless of its ports.
+
+// appIDPattern matches the IDs of the NSX system App IDs, e.g. SSL, HTTP2, MS_SQL.
+var appIDPattern = regexp.MustCompile(`^[A-Z0-9_]+$`)
+
+// ruleAppIDs returns the sorted unique App IDs of the rule.
+func ruleAppIDs(rule *v1alpha1.SecurityPolicyRule) []string {
+	if len(rule.AppIDs) == 0 {
+		return nil
+	}
+	return sets.List(sets.New(rule.AppIDs...))
+}
+
+// ValidateAppIDs rejects the malformed App IDs, and the App IDs in the Redirect rules, which have no context
+// profile.
+func ValidateAppIDs(obj *v1alpha1.SecurityPolicy) error {
+	for i := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[i]
+		for j, appID := range rule.AppIDs {
+			if !appIDPattern.MatchString(appID) {
+				return fmt.Errorf("spec.rules[%d].appIds[%d] has invalid App ID %q", i, j, appID)
+			}
+		}
+		if len(rule.AppIDs) > 0 && isRedirectRule(rule) {
+			return fmt.Errorf("spec.rules[%d].appIds can't be used with the Redirect action", i)
+		}
+	}
+	return nil
+}
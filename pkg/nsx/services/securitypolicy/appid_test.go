@@ -0,0 +1,67 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_AppIDs(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules = sp.Spec.Rules[:1]
+	sp.Spec.Rules[0].AppIDs = []string{"SSL", "HTTP", "SSL"}
+	appIDPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+
+	// the App IDs are in the context profile, the sources of the rule are kept
+	profiles := service.buildContextProfiles(sp, common.ResourceTypeSecurityPolicy)
+	assert.Len(t, profiles, 1)
+	assert.Equal(t, []model.PolicyAttributes{{
+		Key:      String(model.PolicyAttributes_KEY_APP_ID),
+		Datatype: String(model.PolicyAttributes_DATATYPE_STRING),
+		Value:    []string{"HTTP", "SSL"},
+	}}, profiles[0].Attributes)
+	for _, rule := range appIDPolicy.Rules {
+		assert.Equal(t, []string{"/infra/context-profiles/" + *profiles[0].Id}, rule.Profiles)
+		assert.NotEqual(t, []string{"ANY"}, rule.SourceGroups)
+	}
+
+	// both the App IDs and the FQDNs are in the context profile
+	sp = spWithFQDNs("www.example.com")
+	sp.Spec.Rules[0].AppIDs = []string{"SSL"}
+	profiles = service.buildContextProfiles(sp, common.ResourceTypeSecurityPolicy)
+	assert.Len(t, profiles[0].Attributes, 2)
+	assert.Equal(t, model.PolicyAttributes_KEY_DOMAIN_NAME, *profiles[0].Attributes[1].Key)
+}
+
+func TestValidateAppIDs(t *testing.T) {
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules[0].AppIDs = []string{"SSL", "MS_SQL"}
+	assert.NoError(t, ValidateAppIDs(sp))
+
+	sp.Spec.Rules[0].AppIDs = []string{"ssl"}
+	assert.EqualError(t, ValidateAppIDs(sp), `spec.rules[0].appIds[0] has invalid App ID "ssl"`)
+
+	redirect := v1alpha1.RuleActionRedirect
+	sp.Spec.Rules[0].AppIDs = []string{"SSL"}
+	sp.Spec.Rules[0].Action = &redirect
+	assert.EqualError(t, ValidateAppIDs(sp), "spec.rules[0].appIds can't be used with the Redirect action")
+}
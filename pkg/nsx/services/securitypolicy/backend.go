@@ -0,0 +1,267 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+const (
+	BackendInfra         = "infra"
+	BackendVPC           = "vpc"
+	BackendGlobalManager = "global-manager"
+)
+
+// EnforcementBackend realizes the NSX SecurityPolicy built from a SecurityPolicy or NetworkPolicy
+// on the NSX target where it is enforced, e.g. NSX /infra, NSX Project/VPC or Global Manager for
+// federation. The rule and group building pipeline is shared by all the backends, only the
+// realization target differs.
+type EnforcementBackend interface {
+	// Name returns the name the backend is registered with
+	Name() string
+	// UsesProjectShares is true if groups referred out of the policy scope are created at project
+	// level and shared with the target, they are kept in projectGroupStore and shareStore.
+	UsesProjectShares() bool
+	// Realize creates, updates or deletes, per MarkedForDelete, the SecurityPolicy with its rules
+	// and groups, as well as the project groups and shares, in one hierarchical call. It returns
+	// an error if NSX doesn't store the created or updated objects even though the call succeeds,
+	// the stores are updated only after Realize returns nil.
+	Realize(namespace string, sp *model.SecurityPolicy, groups []model.Group, projectGroups []model.Group, projectShares []model.Share) error
+	// PatchGroup creates or updates a single group
+	PatchGroup(namespace string, group *model.Group) error
+}
+
+// BackendFactory creates the backend for the service.
+type BackendFactory func(service *SecurityPolicyService) EnforcementBackend
+
+// BackendSelector returns the name of the backend for the namespace, empty name means the default one.
+type BackendSelector func(namespace string) string
+
+var (
+	backendLock      = &sync.RWMutex{}
+	backendFactories = map[string]BackendFactory{
+		BackendInfra: func(service *SecurityPolicyService) EnforcementBackend { return &infraBackend{service: service} },
+		BackendVPC:   func(service *SecurityPolicyService) EnforcementBackend { return &vpcBackend{service: service} },
+		BackendGlobalManager: func(service *SecurityPolicyService) EnforcementBackend {
+			return &globalManagerBackend{service: service}
+		},
+	}
+)
+
+// RegisterEnforcementBackend registers a backend factory with the name, an existing one is replaced.
+func RegisterEnforcementBackend(name string, factory BackendFactory) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+	backendFactories[name] = factory
+}
+
+// SetBackendSelector sets the selector which chooses the backend per namespace.
+func (service *SecurityPolicyService) SetBackendSelector(selector BackendSelector) {
+	service.backendSelector = selector
+}
+
+// namespaceBackendSelector returns the selector choosing the backends of the namespaces configured by
+// enforcement_backends, it returns error if a backend is not registered.
+func namespaceBackendSelector(backends map[string]string) (BackendSelector, error) {
+	backendLock.RLock()
+	defer backendLock.RUnlock()
+	for namespace, name := range backends {
+		if _, ok := backendFactories[name]; !ok {
+			return nil, fmt.Errorf("unknown enforcement backend %s for namespace %s", name, namespace)
+		}
+	}
+	return func(namespace string) string {
+		return backends[namespace]
+	}, nil
+}
+
+// getBackend returns the backend of the namespace, it is NSX Project/VPC in VPC mode or for VPC cleanup,
+// otherwise NSX /infra, unless the backend selector chooses another one.
+func (service *SecurityPolicyService) getBackend(namespace string, isVpcCleanup bool) (EnforcementBackend, error) {
+	name := BackendInfra
+	if isVpcEnabled(service) || isVpcCleanup {
+		name = BackendVPC
+	}
+	if service.backendSelector != nil {
+		if selected := service.backendSelector(namespace); selected != "" {
+			name = selected
+		}
+	}
+	backendLock.RLock()
+	factory, ok := backendFactories[name]
+	backendLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown enforcement backend %s for namespace %s", name, namespace)
+	}
+	return factory(service), nil
+}
+
+type infraBackend struct {
+	service *SecurityPolicyService
+}
+
+func (b *infraBackend) Name() string {
+	return BackendInfra
+}
+
+func (b *infraBackend) UsesProjectShares() bool {
+	return false
+}
+
+func (b *infraBackend) Realize(_ string, sp *model.SecurityPolicy, groups []model.Group, _ []model.Group, _ []model.Share) error {
+	rules := sp.Rules
+	domainChildren, err := b.service.wrapDomainChildren(sp, groups)
+	if err != nil {
+		log.Error(err, "failed to wrap SecurityPolicy")
+		return err
+	}
+	if window := b.service.patchBatchWindow(); window > 0 && !b.service.DraftEnabled() {
+		err = b.service.batcher.Patch(window, domainChildren, batchKeys(sp, groups), b.patchDomainChildren)
+	} else {
+		err = b.patchDomainChildren(domainChildren)
+	}
+	if err != nil {
+		log.Error(err, "failed to patch SecurityPolicy")
+		return err
+	}
+	if b.service.DraftEnabled() {
+		// the staged changes are not verified, NSX doesn't store them until the draft is published
+		return nil
+	}
+	domain := getDomain(b.service)
+	return verifyRealizedIntent(sp, rules, groups, func(cursor *string) (model.RuleListResult, error) {
+		return b.service.NSXClient.RuleClient.List(domain, *sp.Id, cursor, nil, nil, nil, nil, nil)
+	}, func(id string) error {
+		_, err := b.service.NSXClient.GroupClient.Get(domain, id)
+		return err
+	})
+}
+
+// patchDomainChildren patches the children of the domain in one hierarchical call, or stages them in the DFW
+// draft if it's enabled.
+func (b *infraBackend) patchDomainChildren(children []*data.StructValue) error {
+	infra, err := b.service.wrapDomainInfra(children)
+	if err != nil {
+		return err
+	}
+	if err := b.service.validateInfra(infra); err != nil {
+		return err
+	}
+	if b.service.DraftEnabled() {
+		return b.service.stageInDraft(infra)
+	}
+	return b.service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam)
+}
+
+func (b *infraBackend) PatchGroup(_ string, group *model.Group) error {
+	if b.service.DraftEnabled() {
+		groupsChildren, err := b.service.wrapGroups([]model.Group{*group})
+		if err != nil {
+			return err
+		}
+		infraChildren, err := b.service.wrapDomainResource(groupsChildren, getDomain(b.service))
+		if err != nil {
+			return err
+		}
+		infra, err := b.service.wrapInfra(infraChildren)
+		if err != nil {
+			return err
+		}
+		return b.service.stageInDraft(infra)
+	}
+	return b.service.NSXClient.GroupClient.Patch(getDomain(b.service), *group.Id, *group)
+}
+
+type vpcBackend struct {
+	service *SecurityPolicyService
+}
+
+func (b *vpcBackend) Name() string {
+	return BackendVPC
+}
+
+func (b *vpcBackend) UsesProjectShares() bool {
+	return true
+}
+
+func (b *vpcBackend) Realize(namespace string, sp *model.SecurityPolicy, groups []model.Group, projectGroups []model.Group, projectShares []model.Share) error {
+	vpcInfo, err := b.service.getVpcInfo(namespace)
+	if err != nil {
+		return err
+	}
+
+	// 1.Wrap project groups and shares into project child infra.
+	var projectInfra []*data.StructValue
+	if len(projectShares) != 0 || len(projectGroups) != 0 {
+		projectInfra, err = b.service.wrapHierarchyProjectResources(namespace, projectShares, projectGroups)
+		if err != nil {
+			log.Error(err, "failed to wrap project groups and shares")
+			return err
+		}
+	}
+
+	// 2.Wrap SecurityPolicy, groups, rules under VPC level together with project groups and shares into one hierarchy resource tree.
+	rules := sp.Rules
+	orgRoot, err := b.service.WrapHierarchyVpcSecurityPolicy(sp, groups, projectInfra, vpcInfo)
+	if err != nil {
+		log.Error(err, "failed to wrap SecurityPolicy in VPC")
+		return err
+	}
+
+	// 3.Patch SecurityPolicy together with groups, rules under VPC level and project groups, shares.
+	if err := b.service.validateOrgRoot(orgRoot); err != nil {
+		log.Error(err, "invalid SecurityPolicy payload in VPC")
+		return err
+	}
+	err = b.service.NSXClient.OrgRootClient.Patch(*orgRoot, &EnforceRevisionCheckParam)
+	if err != nil {
+		log.Error(err, "failed to patch SecurityPolicy in VPC")
+		return err
+	}
+
+	// 4.Verify the SecurityPolicy, rules and groups under VPC level are stored by NSX.
+	return verifyRealizedIntent(sp, rules, groups, func(cursor *string) (model.RuleListResult, error) {
+		return b.service.NSXClient.VPCRuleClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *sp.Id, cursor, nil, nil, nil, nil, nil)
+	}, func(id string) error {
+		_, err := b.service.NSXClient.VpcGroupClient.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, id)
+		return err
+	})
+}
+
+func (b *vpcBackend) PatchGroup(namespace string, group *model.Group) error {
+	vpcInfo, err := b.service.getVpcInfo(namespace)
+	if err != nil {
+		return err
+	}
+	return b.service.NSXClient.VpcGroupClient.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *group.Id, *group)
+}
+
+// globalManagerBackend realizes the SecurityPolicy on NSX Global Manager for federation. The operator has
+// no Global Manager client yet, so the namespaces selecting it are refused with a RestrictionError, which
+// is not retried.
+type globalManagerBackend struct {
+	service *SecurityPolicyService
+}
+
+func (b *globalManagerBackend) Name() string {
+	return BackendGlobalManager
+}
+
+func (b *globalManagerBackend) UsesProjectShares() bool {
+	return false
+}
+
+func (b *globalManagerBackend) Realize(namespace string, _ *model.SecurityPolicy, _ []model.Group, _ []model.Group, _ []model.Share) error {
+	return nsxutil.RestrictionError{Desc: fmt.Sprintf("the %s enforcement backend of namespace %s is not supported yet", BackendGlobalManager, namespace)}
+}
+
+func (b *globalManagerBackend) PatchGroup(namespace string, _ *model.Group) error {
+	return nsxutil.RestrictionError{Desc: fmt.Sprintf("the %s enforcement backend of namespace %s is not supported yet", BackendGlobalManager, namespace)}
+}
@@ -0,0 +1,111 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+type fakeBackend struct {
+	infraBackend
+}
+
+func (b *fakeBackend) Name() string {
+	return "fake"
+}
+
+func (b *fakeBackend) Realize(_ string, _ *model.SecurityPolicy, _ []model.Group, _ []model.Group, _ []model.Share) error {
+	return nil
+}
+
+func TestGetBackend(t *testing.T) {
+	service := &SecurityPolicyService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{
+				CoeConfig: &config.CoeConfig{
+					Cluster:          "k8scl-one:test",
+					EnableVPCNetwork: false,
+				},
+			},
+		},
+	}
+
+	backend, err := service.getBackend("ns1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, BackendInfra, backend.Name())
+	assert.False(t, backend.UsesProjectShares())
+
+	backend, err = service.getBackend("ns1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, BackendVPC, backend.Name())
+	assert.True(t, backend.UsesProjectShares())
+
+	service.NSXConfig.EnableVPCNetwork = true
+	backend, err = service.getBackend("ns1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, BackendVPC, backend.Name())
+
+	registerTestBackend(t, "fake", func(s *SecurityPolicyService) EnforcementBackend {
+		return &fakeBackend{infraBackend{service: s}}
+	})
+	service.SetBackendSelector(func(namespace string) string {
+		switch namespace {
+		case "ns-fake":
+			return "fake"
+		case "ns-gm":
+			return BackendGlobalManager
+		case "ns-unknown":
+			return "unknown"
+		}
+		return ""
+	})
+	backend, err = service.getBackend("ns-fake", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "fake", backend.Name())
+	backend, err = service.getBackend("ns1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, BackendVPC, backend.Name())
+	backend, err = service.getBackend("ns-gm", false)
+	assert.NoError(t, err)
+	assert.Equal(t, BackendGlobalManager, backend.Name())
+	assert.True(t, errors.As(backend.Realize("ns-gm", &model.SecurityPolicy{}, nil, nil, nil), &nsxutil.RestrictionError{}))
+	_, err = service.getBackend("ns-unknown", false)
+	assert.Error(t, err)
+}
+
+// registerTestBackend registers the backend factory for the test, the previous registration is restored once the
+// test ends.
+func registerTestBackend(t *testing.T, name string, factory BackendFactory) {
+	backendLock.RLock()
+	previous, registered := backendFactories[name]
+	backendLock.RUnlock()
+	RegisterEnforcementBackend(name, factory)
+	t.Cleanup(func() {
+		backendLock.Lock()
+		defer backendLock.Unlock()
+		if registered {
+			backendFactories[name] = previous
+		} else {
+			delete(backendFactories, name)
+		}
+	})
+}
+
+func TestNamespaceBackendSelector(t *testing.T) {
+	selector, err := namespaceBackendSelector(map[string]string{"ns-gm": BackendGlobalManager})
+	assert.NoError(t, err)
+	assert.Equal(t, BackendGlobalManager, selector("ns-gm"))
+	assert.Equal(t, "", selector("ns1"))
+
+	_, err = namespaceBackendSelector(map[string]string{"ns1": "unknown"})
+	assert.EqualError(t, err, "unknown enforcement backend unknown for namespace ns1")
+}
@@ -0,0 +1,115 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The SecurityPolicies realized at the same time, e.g. when a namespace is applied in bulk, are reconciled
+// concurrently and each of them is patched in its own hierarchical call. Without VPC they are all children of
+// the same domain, so the ones realized within patch_batch_window are gathered and patched in one call instead.
+
+// maxPatchBatchSize is the max count of the SecurityPolicies patched in one call, NSX limits the payload size.
+const maxPatchBatchSize = 50
+
+// patchBatcher gathers the domain children of the SecurityPolicies patched within the window into batches.
+type patchBatcher struct {
+	mu      sync.Mutex
+	pending *patchBatch
+}
+
+type patchBatch struct {
+	patch    func(children []*data.StructValue) error
+	requests []*patchRequest
+	// keys are the NSX objects in the batch, an object can't be patched twice in one call.
+	keys sets.Set[string]
+}
+
+type patchRequest struct {
+	children []*data.StructValue
+	err      error
+	done     chan struct{}
+}
+
+// Patch adds the children with the keys of their NSX objects to the pending batch, and returns once the batch is
+// patched by the patch func. The batch is patched when the window since its first request elapses, or when it's
+// full or a request patches the same NSX objects again. If the batch fails, its requests are patched one by one,
+// so that each of them gets its own error.
+func (b *patchBatcher) Patch(window time.Duration, children []*data.StructValue, keys []string, patch func(children []*data.StructValue) error) error {
+	request := &patchRequest{children: children, done: make(chan struct{})}
+	b.mu.Lock()
+	if b.pending != nil && (b.pending.keys.HasAny(keys...) || len(b.pending.requests) >= maxPatchBatchSize) {
+		b.sendLocked()
+	}
+	if b.pending == nil {
+		batch := &patchBatch{patch: patch, keys: sets.New[string]()}
+		b.pending = batch
+		time.AfterFunc(window, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.pending == batch {
+				b.sendLocked()
+			}
+		})
+	}
+	b.pending.requests = append(b.pending.requests, request)
+	b.pending.keys.Insert(keys...)
+	b.mu.Unlock()
+	<-request.done
+	return request.err
+}
+
+// sendLocked detaches the pending batch and patches it, b.mu must be held.
+func (b *patchBatcher) sendLocked() {
+	batch := b.pending
+	b.pending = nil
+	go batch.send()
+}
+
+func (batch *patchBatch) send() {
+	var children []*data.StructValue
+	for _, request := range batch.requests {
+		children = append(children, request.children...)
+	}
+	err := batch.patch(children)
+	if err == nil || len(batch.requests) == 1 {
+		log.V(1).Info("patched SecurityPolicies in batch", "count", len(batch.requests), "error", err)
+		for _, request := range batch.requests {
+			request.err = err
+			close(request.done)
+		}
+		return
+	}
+	log.Error(err, "failed to patch SecurityPolicies in batch, patching them separately", "count", len(batch.requests))
+	for _, request := range batch.requests {
+		request.err = batch.patch(request.children)
+		close(request.done)
+	}
+}
+
+// patchBatchWindow returns the window the SecurityPolicies are gathered in, 0 if the batching is disabled.
+func (service *SecurityPolicyService) patchBatchWindow() time.Duration {
+	if service.NSXConfig == nil || service.NSXConfig.NsxConfig == nil {
+		return 0
+	}
+	return time.Duration(service.NSXConfig.PatchBatchWindow) * time.Millisecond
+}
+
+// batchKeys returns the keys of the NSX SecurityPolicy and groups patched for a SecurityPolicy.
+func batchKeys(sp *model.SecurityPolicy, groups []model.Group) []string {
+	keys := make([]string, 0, len(groups)+1)
+	keys = append(keys, common.ResourceTypeSecurityPolicy+"/"+*sp.Id)
+	for _, group := range groups {
+		keys = append(keys, common.ResourceTypeGroup+"/"+*group.Id)
+	}
+	return keys
+}
@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+func TestPatchBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]string
+	failing := "sp-bad"
+	patch := func(children []*data.StructValue) error {
+		mu.Lock()
+		defer mu.Unlock()
+		var ids []string
+		for _, child := range children {
+			ids = append(ids, structString(child, "id"))
+		}
+		calls = append(calls, ids)
+		for _, id := range ids {
+			if id == failing {
+				return errors.New("rejected")
+			}
+		}
+		return nil
+	}
+	child := func(id string) []*data.StructValue {
+		return []*data.StructValue{data.NewStructValue("", map[string]data.DataValue{"id": data.NewStringValue(id)})}
+	}
+	batcher := &patchBatcher{}
+	patchAll := func(ids ...string) map[string]error {
+		errs := map[string]error{}
+		var wg sync.WaitGroup
+		for _, id := range ids {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				err := batcher.Patch(100*time.Millisecond, child(id), []string{"SecurityPolicy/" + id}, patch)
+				mu.Lock()
+				errs[id] = err
+				mu.Unlock()
+			}(id)
+		}
+		wg.Wait()
+		return errs
+	}
+
+	// the SecurityPolicies patched at the same time are patched in one call
+	errs := patchAll("sp-1", "sp-2", "sp-3")
+	assert.Equal(t, map[string]error{"sp-1": nil, "sp-2": nil, "sp-3": nil}, errs)
+	assert.Len(t, calls, 1)
+	assert.ElementsMatch(t, []string{"sp-1", "sp-2", "sp-3"}, calls[0])
+
+	// the failed batch is patched separately
+	calls = nil
+	errs = patchAll("sp-1", failing)
+	assert.NoError(t, errs["sp-1"])
+	assert.EqualError(t, errs[failing], "rejected")
+	assert.Len(t, calls, 3)
+
+	// the same SecurityPolicy is not patched twice in one call
+	calls = nil
+	errs = patchAll("sp-1", "sp-1")
+	assert.Equal(t, map[string]error{"sp-1": nil}, errs)
+	assert.Equal(t, [][]string{{"sp-1"}, {"sp-1"}}, calls)
+}
+
+func TestBatchKeys(t *testing.T) {
+	sp := &model.SecurityPolicy{Id: String("sp")}
+	assert.Equal(t, []string{"SecurityPolicy/sp", "Group/g1"}, batchKeys(sp, []model.Group{{Id: String("g1")}}))
+}
@@ -0,0 +1,122 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	// MaxRuleServiceEntries is the max count of service entries in one NSX rule.
+	MaxRuleServiceEntries int = 128
+	// budgetWarningRatio is the ratio of an NSX limit from which a rule is reported as approaching the limit.
+	budgetWarningRatio float64 = 0.8
+)
+
+func expressionType(expr *data.StructValue) string {
+	value, err := expr.Field(common.ResourceType)
+	if err != nil {
+		return ""
+	}
+	if str, ok := value.(*data.StringValue); ok {
+		return str.Value()
+	}
+	return ""
+}
+
+// countGroupCriteria returns the count of criteria in the group and the count of expressions in them,
+// the criteria are joined by the ConjunctionOperator.
+func countGroupCriteria(group *model.Group) (int, int) {
+	criteria, expressions := 0, 0
+	for _, expr := range group.Expression {
+		if expr == nil {
+			continue
+		}
+		switch expressionType(expr) {
+		case expression.TypeConjunctionOperator:
+			continue
+		case expression.TypeNestedExpression:
+			criteria++
+			nested, err := expr.Field("expressions")
+			if err != nil {
+				continue
+			}
+			list, ok := nested.(*data.ListValue)
+			if !ok {
+				continue
+			}
+			for _, value := range list.List() {
+				if e, ok := value.(*data.StructValue); ok && expressionType(e) == expression.TypeConjunctionOperator {
+					continue
+				}
+				expressions++
+			}
+		default:
+			criteria++
+			expressions++
+		}
+	}
+	return criteria, expressions
+}
+
+func approaching(count, limit int) bool {
+	return float64(count) >= float64(limit)*budgetWarningRatio
+}
+
+// buildRuleBudget counts the NSX objects expanded from the rule, and describes the NSX scale
+// limits approached by them.
+func buildRuleBudget(rule *v1alpha1.SecurityPolicyRule, ruleIdx int, nsxRules []*model.Rule, nsxGroups []*model.Group) v1alpha1.RuleBudget {
+	budget := v1alpha1.RuleBudget{Name: rule.Name, Index: ruleIdx}
+	var warnings []string
+
+	ruleIDs := sets.New[string]()
+	for _, nsxRule := range nsxRules {
+		if nsxRule == nil || ruleIDs.Has(*nsxRule.Id) {
+			continue
+		}
+		ruleIDs.Insert(*nsxRule.Id)
+		budget.ServiceEntries += len(nsxRule.ServiceEntries)
+		if approaching(len(nsxRule.ServiceEntries), MaxRuleServiceEntries) {
+			warnings = append(warnings, fmt.Sprintf("rule %s has %d service entries, limit %d", *nsxRule.Id, len(nsxRule.ServiceEntries), MaxRuleServiceEntries))
+		}
+	}
+	budget.Rules = ruleIDs.Len()
+
+	groupIDs := sets.New[string]()
+	for _, nsxGroup := range nsxGroups {
+		if nsxGroup == nil || groupIDs.Has(*nsxGroup.Id) {
+			continue
+		}
+		groupIDs.Insert(*nsxGroup.Id)
+		criteria, expressions := countGroupCriteria(nsxGroup)
+		budget.Criteria += criteria
+		if approaching(criteria, MaxCriteria) {
+			warnings = append(warnings, fmt.Sprintf("group %s has %d criteria, limit %d", *nsxGroup.Id, criteria, MaxCriteria))
+		}
+		if approaching(expressions, MaxTotalCriteriaExpressions) {
+			warnings = append(warnings, fmt.Sprintf("group %s has %d expressions, limit %d", *nsxGroup.Id, expressions, MaxTotalCriteriaExpressions))
+		}
+	}
+	budget.Groups = groupIDs.Len()
+	budget.Warning = strings.Join(warnings, "; ")
+	return budget
+}
+
+// GetRuleBudgets returns the RuleBudgets of the SecurityPolicy CR built last time.
+func (service *SecurityPolicyService) GetRuleBudgets(uid types.UID) []v1alpha1.RuleBudget {
+	if budgets, ok := service.ruleBudgets.Load(uid); ok {
+		return budgets.([]v1alpha1.RuleBudget)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func budgetExpression(resourceType string) *data.StructValue {
+	return data.NewStructValue("", map[string]data.DataValue{
+		"resource_type": data.NewStringValue(resourceType),
+	})
+}
+
+func budgetNestedExpression(count int) *data.StructValue {
+	expressions := data.NewListValue()
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			expressions.Add(budgetExpression("ConjunctionOperator"))
+		}
+		expressions.Add(budgetExpression("Condition"))
+	}
+	return data.NewStructValue("", map[string]data.DataValue{
+		"resource_type": data.NewStringValue("NestedExpression"),
+		"expressions":   expressions,
+	})
+}
+
+func TestCountGroupCriteria(t *testing.T) {
+	group := &model.Group{
+		Id: String("group"),
+		Expression: []*data.StructValue{
+			budgetNestedExpression(3),
+			budgetExpression("ConjunctionOperator"),
+			budgetExpression("Condition"),
+		},
+	}
+	criteria, expressions := countGroupCriteria(group)
+	assert.Equal(t, 2, criteria)
+	assert.Equal(t, 4, expressions)
+}
+
+func TestBuildRuleBudget(t *testing.T) {
+	rule := &v1alpha1.SecurityPolicyRule{Name: "rule-0"}
+	serviceEntries := make([]*data.StructValue, MaxRuleServiceEntries-1)
+	nsxRules := []*model.Rule{
+		{Id: String("rule-0-a"), ServiceEntries: []*data.StructValue{budgetExpression("L4PortSetServiceEntry")}},
+		{Id: String("rule-0-b"), ServiceEntries: serviceEntries},
+		{Id: String("rule-0-a"), ServiceEntries: []*data.StructValue{budgetExpression("L4PortSetServiceEntry")}},
+		nil,
+	}
+	nsxGroups := []*model.Group{
+		{Id: String("src"), Expression: []*data.StructValue{budgetNestedExpression(2)}},
+		{Id: String("dst"), Expression: []*data.StructValue{budgetExpression("Condition")}},
+		{Id: String("src"), Expression: []*data.StructValue{budgetNestedExpression(2)}},
+	}
+
+	budget := buildRuleBudget(rule, 0, nsxRules, nsxGroups)
+	assert.Equal(t, "rule-0", budget.Name)
+	assert.Equal(t, 2, budget.Rules)
+	assert.Equal(t, 2, budget.Groups)
+	assert.Equal(t, 2, budget.Criteria)
+	assert.Equal(t, MaxRuleServiceEntries, budget.ServiceEntries)
+	assert.Contains(t, budget.Warning, "rule rule-0-b has 127 service entries")
+	assert.NotContains(t, budget.Warning, "group")
+
+	budget = buildRuleBudget(rule, 1, nsxRules[:1], nsxGroups[1:2])
+	assert.Equal(t, 1, budget.Index)
+	assert.Empty(t, budget.Warning)
+}
+
+func TestGetRuleBudgets(t *testing.T) {
+	service := &SecurityPolicyService{}
+	uid := types.UID("uid")
+	assert.Nil(t, service.GetRuleBudgets(uid))
+
+	budgets := []v1alpha1.RuleBudget{{Name: "rule-0", Rules: 1}}
+	service.ruleBudgets.Store(uid, budgets)
+	assert.Equal(t, budgets, service.GetRuleBudgets(uid))
+}
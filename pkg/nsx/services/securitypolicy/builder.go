@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
@@ -31,6 +32,8 @@ const (
 	MaxMatchExpressionInValues  int = 5
 	ClusterTagCount             int = 1
 	NameSpaceTagCount           int = 1
+	// MaxLabelTags is the max count of the labels copied to tags, NSX allows 30 tags on an object.
+	MaxLabelTags int = 10
 )
 
 var (
@@ -38,20 +41,58 @@ var (
 	Int64  = common.Int64
 )
 
-func (service *SecurityPolicyService) buildecurityPolicyName(obj *v1alpha1.SecurityPolicy, createdFor string) string {
-	prefix := common.SecurityPolicyPrefix
-	if createdFor != common.ResourceTypeSecurityPolicy {
-		prefix = common.NetworkPolicyPrefix
+// policyPrefix returns the prefix of the IDs and names of the NSX resources created for the owner of the type
+// createdFor, the internal SecurityPolicies converted from the other resources are told apart by it.
+func policyPrefix(createdFor string) string {
+	switch createdFor {
+	case common.ResourceTypeNetworkPolicy:
+		return common.NetworkPolicyPrefix
+	case common.ResourceTypeServiceExposure:
+		return common.ServiceExposurePrefix
+	case common.ResourceTypeAdminNetworkPolicy:
+		return common.AdminNetworkPolicyPrefix
+	case common.ResourceTypeBaselineAdminNetworkPolicy:
+		return common.BaselineAdminNetworkPolicyPrefix
+	case common.ResourceTypeNamespaceDefaultDeny:
+		return common.NamespaceDefaultDenyPrefix
+	case common.ResourceTypeGatewayPolicy:
+		return common.GatewayPolicyPrefix
+	case common.ResourceTypeIDSPolicy:
+		return common.IDSPolicyPrefix
+	default:
+		return common.SecurityPolicyPrefix
+	}
+}
+
+// ownerTagScopes returns the scopes of the tags of the owner name and UID of the NSX resources created for the
+// owner of the type createdFor, the stores index the NSX resources by the owner UID scope.
+func ownerTagScopes(createdFor string) (string, string) {
+	switch createdFor {
+	case common.ResourceTypeNetworkPolicy:
+		return common.TagScopeNetworkPolicyName, common.TagScopeNetworkPolicyUID
+	case common.ResourceTypeServiceExposure:
+		return common.TagScopeServiceExposureName, common.TagScopeServiceExposureUID
+	case common.ResourceTypeAdminNetworkPolicy, common.ResourceTypeBaselineAdminNetworkPolicy:
+		return common.TagScopeAdminNetworkPolicyName, common.TagScopeAdminNetworkPolicyUID
+	case common.ResourceTypeNamespaceDefaultDeny:
+		return common.TagScopeDefaultDenyNamespace, common.TagScopeDefaultDenyNamespaceUID
+	case common.ResourceTypeGatewayPolicy:
+		return common.TagScopeGatewayPolicyCRName, common.TagScopeGatewayPolicyCRUID
+	case common.ResourceTypeIDSPolicy:
+		return common.TagScopeIDSPolicyCRName, common.TagScopeIDSPolicyCRUID
+	default:
+		return common.TagValueScopeSecurityPolicyName, common.TagValueScopeSecurityPolicyUID
 	}
+}
+
+func (service *SecurityPolicyService) buildecurityPolicyName(obj *v1alpha1.SecurityPolicy, createdFor string) string {
+	prefix := policyPrefix(createdFor)
 	nsxSecurityPolicyName := util.GenerateTruncName(common.MaxNameLength, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name), prefix, "", "", "")
 	return nsxSecurityPolicyName
 }
 
 func (service *SecurityPolicyService) buildecurityPolicyID(obj *v1alpha1.SecurityPolicy, createdFor string) string {
-	prefix := common.SecurityPolicyPrefix
-	if createdFor != common.ResourceTypeSecurityPolicy {
-		prefix = common.NetworkPolicyPrefix
-	}
+	prefix := policyPrefix(createdFor)
 	nsxSecurityPolicyID := util.GenerateID(string(obj.UID), prefix, "", "")
 	return nsxSecurityPolicyID
 }
@@ -64,12 +105,35 @@ func (service *SecurityPolicyService) buildSecurityPolicy(obj *v1alpha1.Security
 	var projectShares []ProjectShare
 
 	log.V(1).Info("building the model SecurityPolicy from CR SecurityPolicy", "object", *obj)
+	memo := newSelectorMemo()
+	service.selectorMemos.Store(obj.UID, memo)
+	defer service.selectorMemos.CompareAndDelete(obj.UID, memo)
+	// the DNS rule is built after the rules of the spec, so their IDs are not changed by allowDNS
+	userRules := len(obj.Spec.Rules)
+	obj, err := service.withDNSRule(obj, createdFor)
+	if err != nil {
+		log.Error(err, "failed to build DNS rule")
+		return nil, nil, nil, err
+	}
+	obj, err = service.withHealthProbeRule(obj, createdFor)
+	if err != nil {
+		log.Error(err, "failed to build health probe rule")
+		return nil, nil, nil, err
+	}
+	obj, err = service.withExternalNameServices(obj, createdFor)
+	if err != nil {
+		log.Error(err, "failed to resolve ExternalName Services")
+		return nil, nil, nil, err
+	}
 	nsxSecurityPolicy := &model.SecurityPolicy{}
 
 	nsxSecurityPolicy.Id = String(service.buildecurityPolicyID(obj, createdFor))
 	nsxSecurityPolicy.DisplayName = String(service.buildecurityPolicyName(obj, createdFor))
 	// TODO: confirm the sequence number: offset
-	nsxSecurityPolicy.SequenceNumber = Int64(int64(obj.Spec.Priority))
+	nsxSecurityPolicy.SequenceNumber = Int64(service.policySequenceNumber(obj, createdFor))
+	if category := service.policyCategory(createdFor); category != "" {
+		nsxSecurityPolicy.Category = String(category)
+	}
 
 	policyGroup, policyGroupPath, err := service.buildPolicyGroup(obj, createdFor)
 	if err != nil {
@@ -82,14 +146,19 @@ func (service *SecurityPolicyService) buildSecurityPolicy(obj *v1alpha1.Security
 		nsxGroups = append(nsxGroups, *policyGroup)
 	}
 	currentSet := sets.Set[string]{}
+	ruleBudgets := make([]v1alpha1.RuleBudget, 0, len(obj.Spec.Rules))
+	// A rule containing named port may expand to multiple rules if the name maps to multiple port numbers.
+	results := service.buildRulesInParallel(obj, createdFor)
 	for ruleIdx, r := range obj.Spec.Rules {
 		rule := r
-		// A rule containing named port may expand to multiple rules if the name maps to multiple port numbers.
-		expandRules, buildGroups, buildProjectShares, err := service.buildRuleAndGroups(obj, &rule, ruleIdx, createdFor)
+		expandRules, buildGroups, buildProjectShares, err := results[ruleIdx].rules, results[ruleIdx].groups, results[ruleIdx].projectShares, results[ruleIdx].err
 		if err != nil {
 			log.Error(err, "failed to build rule and groups", "rule", rule, "ruleIndex", ruleIdx)
 			return nil, nil, nil, err
 		}
+		if ruleIdx < userRules {
+			ruleBudgets = append(ruleBudgets, buildRuleBudget(&rule, ruleIdx, expandRules, buildGroups))
+		}
 
 		for _, nsxRule := range expandRules {
 			if nsxRule != nil {
@@ -123,14 +192,92 @@ func (service *SecurityPolicyService) buildSecurityPolicy(obj *v1alpha1.Security
 		}
 
 	}
+	nsxGroups = reusePeerGroups(nsxRules, nsxGroups)
+	if injected := len(obj.Spec.Rules) - userRules; injected > 0 {
+		// the injected rules, e.g. the DNS rule, are enforced before the rules of the spec, e.g. a rule dropping
+		// all the egress traffic
+		for i := range nsxRules {
+			if *nsxRules[i].SequenceNumber >= int64(userRules) {
+				nsxRules[i].SequenceNumber = Int64(*nsxRules[i].SequenceNumber - int64(userRules))
+			} else {
+				nsxRules[i].SequenceNumber = Int64(*nsxRules[i].SequenceNumber + int64(injected))
+			}
+		}
+	}
 	nsxSecurityPolicy.Rules = nsxRules
 	nsxSecurityPolicy.Tags = service.buildBasicTags(obj, createdFor)
+	if createdFor == common.ResourceTypeSecurityPolicy {
+		service.ruleBudgets.Store(obj.UID, ruleBudgets)
+	}
 	// nsxRules info are included in nsxSecurityPolicy obj
 	log.Info("built nsxSecurityPolicy", "nsxSecurityPolicy", nsxSecurityPolicy, "nsxGroups", nsxGroups, "nsxProjectGroups", nsxProjectGroups, "nsxProjectShares", nsxProjectShares)
 
 	return nsxSecurityPolicy, &nsxGroups, &projectShares, nil
 }
 
+// reusePeerGroups keeps one group of the rule peers with the same selectors in the policy, e.g. the sources
+// of a rule which are the destinations of another, and the rules refer to the first group instead of their
+// own. The kept group is still tagged with the rule and the peer type it was built for. The groups shared
+// from the project by the VPC project shares are not reused.
+func reusePeerGroups(rules []model.Rule, groups []model.Group) []model.Group {
+	reused := map[string]string{}
+	firstBySelectors := map[string]string{}
+	kept := make([]model.Group, 0, len(groups))
+	for _, group := range groups {
+		hash := peerSelectorHash(&group)
+		if hash == "" {
+			kept = append(kept, group)
+			continue
+		}
+		if id, ok := firstBySelectors[hash]; ok {
+			reused[*group.Id] = id
+			continue
+		}
+		firstBySelectors[hash] = *group.Id
+		kept = append(kept, group)
+	}
+	if len(reused) == 0 {
+		return groups
+	}
+	log.V(1).Info("reused rule peer groups with the same selectors", "groups", reused)
+	for i := range rules {
+		reusePeerGroupPaths(rules[i].SourceGroups, reused)
+		reusePeerGroupPaths(rules[i].DestinationGroups, reused)
+	}
+	return kept
+}
+
+// peerSelectorHash returns the hash of the selectors of a rule peer group, or empty for the other groups.
+func peerSelectorHash(group *model.Group) string {
+	var groupType, hash string
+	for _, tag := range group.Tags {
+		if tag.Scope == nil || tag.Tag == nil {
+			continue
+		}
+		switch *tag.Scope {
+		case common.TagScopeGroupType:
+			groupType = *tag.Tag
+		case common.TagScopeSelectorHash:
+			hash = *tag.Tag
+		}
+	}
+	if groupType != common.TagValueGroupSource && groupType != common.TagValueGroupDestination {
+		return ""
+	}
+	return hash
+}
+
+// reusePeerGroupPaths replaces the paths of the groups reused by another group with the path of that group,
+// the groups of a policy are in the same domain or VPC.
+func reusePeerGroupPaths(paths []string, reused map[string]string) {
+	for i, path := range paths {
+		idx := strings.LastIndex(path, "/")
+		if id, ok := reused[path[idx+1:]]; ok {
+			paths[i] = path[:idx+1] + id
+		}
+	}
+}
+
 func (service *SecurityPolicyService) buildPolicyGroup(obj *v1alpha1.SecurityPolicy, createdFor string) (*model.Group, string, error) {
 	policyAppliedGroup := model.Group{}
 	policyAppliedGroup.Id = String(service.buildAppliedGroupID(obj, -1, createdFor))
@@ -185,6 +332,9 @@ func (service *SecurityPolicyService) buildPolicyGroup(obj *v1alpha1.SecurityPol
 	if err != nil {
 		return nil, "", err
 	}
+	if err := validateGroupExpression(&policyAppliedGroup); err != nil {
+		return nil, "", err
+	}
 
 	log.V(1).Info("built policy target group", "policyAppliedGroup", policyAppliedGroup)
 	return &policyAppliedGroup, policyAppliedGroupPath, nil
@@ -240,12 +390,7 @@ func (service *SecurityPolicyService) buildTargetTags(obj *v1alpha1.SecurityPoli
 }
 
 func (service *SecurityPolicyService) buildBasicTags(obj *v1alpha1.SecurityPolicy, createdFor string) []model.Tag {
-	scopeOwnerName := common.TagValueScopeSecurityPolicyName
-	scopeOwnerUID := common.TagValueScopeSecurityPolicyUID
-	if createdFor == common.ResourceTypeNetworkPolicy {
-		scopeOwnerName = common.TagScopeNetworkPolicyName
-		scopeOwnerUID = common.TagScopeNetworkPolicyUID
-	}
+	scopeOwnerName, scopeOwnerUID := ownerTagScopes(createdFor)
 
 	tags := util.BuildBasicTags(getCluster(service), obj, service.getNamespaceUID(obj.ObjectMeta.Namespace))
 	tags = append(tags, []model.Tag{
@@ -258,61 +403,39 @@ func (service *SecurityPolicyService) buildBasicTags(obj *v1alpha1.SecurityPolic
 			Tag:   String(string(obj.UID)),
 		},
 	}...)
+	tags = append(tags, service.buildLabelTags(obj)...)
 	return tags
 }
 
-func (service *SecurityPolicyService) buildConjOperator(op string) *data.StructValue {
-	operator := data.NewStructValue(
-		"",
-		map[string]data.DataValue{
-			"conjunction_operator": data.NewStringValue(op),
-			"resource_type":        data.NewStringValue("ConjunctionOperator"),
-		},
-	)
-	return operator
-}
-
-func (service *SecurityPolicyService) buildGroupExpression(policyExpression *[]*data.StructValue) *data.ListValue {
-	expressions := data.NewListValue()
-	expressionFrame := data.NewStructValue(
-		"",
-		map[string]data.DataValue{
-			"expressions":   expressions,
-			"resource_type": data.NewStringValue("NestedExpression"),
-		},
-	)
-	*policyExpression = append(*policyExpression, expressionFrame)
-	return expressions
-}
-
-func (service *SecurityPolicyService) buildExpression(resource_type, member_type, value, key, operator, scope_op string) *data.StructValue {
-	var expression *data.StructValue
-	if scope_op == "NOTEQUALS" {
-		// when scope_op is "NOTEQUALS", the tag operator and value field will not be used
-		expression = data.NewStructValue(
-			"",
-			map[string]data.DataValue{
-				"resource_type":  data.NewStringValue(resource_type),
-				"member_type":    data.NewStringValue(member_type),
-				"value":          data.NewStringValue(value),
-				"key":            data.NewStringValue(key),
-				"scope_operator": data.NewStringValue(scope_op),
-			},
-		)
-	} else {
-		expression = data.NewStructValue(
-			"",
-			map[string]data.DataValue{
-				"resource_type":  data.NewStringValue(resource_type),
-				"member_type":    data.NewStringValue(member_type),
-				"value":          data.NewStringValue(value),
-				"key":            data.NewStringValue(key),
-				"operator":       data.NewStringValue(operator),
-				"scope_operator": data.NewStringValue(scope_op),
-			},
-		)
+// buildLabelTags copies the labels of the SecurityPolicy with a key in the prefix allow-list of the config to
+// tags, the label key is the tag scope. The labels in the scopes of the operator or beyond the NSX tag limits are
+// skipped.
+func (service *SecurityPolicyService) buildLabelTags(obj *v1alpha1.SecurityPolicy) []model.Tag {
+	if service.NSXConfig == nil || service.NSXConfig.K8sConfig == nil || len(service.NSXConfig.LabelTagPrefixes) == 0 {
+		return nil
+	}
+	var keys []string
+	for key := range obj.Labels {
+		if strings.HasPrefix(key, common.TagScopePrefix) || len(key) > common.MaxTagScopeLength {
+			continue
+		}
+		for _, prefix := range service.NSXConfig.LabelTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > MaxLabelTags {
+		log.Info("too many labels to copy to tags, skipping the rest", "securityPolicy", obj.Name, "namespace", obj.Namespace, "skipped", keys[MaxLabelTags:])
+		keys = keys[:MaxLabelTags]
 	}
-	return expression
+	tags := make([]model.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, model.Tag{Scope: String(key), Tag: String(obj.Labels[key])})
+	}
+	return tags
 }
 
 func (service *SecurityPolicyService) buildExpressionsMatchExpression(matchExpressions []v1.LabelSelectorRequirement, memberType string, expressions *data.ListValue) error {
@@ -325,33 +448,22 @@ func (service *SecurityPolicyService) buildExpressionsMatchExpression(matchExpre
 			continue
 
 		case v1.LabelSelectorOpNotIn:
-			service.addOperatorIfNeeded(expressions, "AND")
+			expression.AddConjunction(expressions, expression.And)
 			joinValues := strings.Join(expr.Values[:], ",")
 
-			expression := service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|%s", expr.Key, joinValues),
-				"Tag", "NOTIN", "EQUALS",
-			)
-			expressions.Add(expression)
+			condition := expression.TagCondition(memberType,
+				fmt.Sprintf("%s|%s", expr.Key, joinValues), "NOTIN", "EQUALS").Build()
+			expressions.Add(condition)
 
 		case v1.LabelSelectorOpExists:
-			service.addOperatorIfNeeded(expressions, "AND")
-			expression := service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|", expr.Key),
-				"Tag", "EQUALS", "EQUALS",
-			)
-			expressions.Add(expression)
+			expression.AddConjunction(expressions, expression.And)
+			condition := expression.TagCondition(memberType, fmt.Sprintf("%s|", expr.Key), "EQUALS", "EQUALS").Build()
+			expressions.Add(condition)
 
 		case v1.LabelSelectorOpDoesNotExist:
-			service.addOperatorIfNeeded(expressions, "AND")
-			expression := service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|", expr.Key),
-				"Tag", "", "NOTEQUALS",
-			)
-			expressions.Add(expression)
+			expression.AddConjunction(expressions, expression.And)
+			condition := expression.TagCondition(memberType, fmt.Sprintf("%s|", expr.Key), "", "NOTEQUALS").Build()
+			expressions.Add(condition)
 
 		default:
 			errorMsg = fmt.Sprintf("invalid operator %s in matchExpressions", expr.Operator)
@@ -366,10 +478,7 @@ func (service *SecurityPolicyService) buildExpressionsMatchExpression(matchExpre
 
 // build appliedTo group ID for both policy and rule levels.
 func (service *SecurityPolicyService) buildAppliedGroupID(obj *v1alpha1.SecurityPolicy, ruleIdx int, createdFor string) string {
-	prefix := common.SecurityPolicyPrefix
-	if createdFor == common.ResourceTypeNetworkPolicy {
-		prefix = common.NetworkPolicyPrefix
-	}
+	prefix := policyPrefix(createdFor)
 
 	ruleIdxStr := ""
 	if ruleIdx != -1 {
@@ -428,6 +537,11 @@ func (service *SecurityPolicyService) buildRuleAndGroups(obj *v1alpha1.SecurityP
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	// The App IDs and the FQDN destinations are matched by the context profile of the rule.
+	rule, profilePath, err := service.buildRuleContextProfile(obj, rule, ruleIdx, createdFor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	// Since a named port may map to multiple port numbers, then it would return multiple rules.
 	// We use the destination port number of service entry to group the rules.
@@ -478,10 +592,30 @@ func (service *SecurityPolicyService) buildRuleAndGroups(obj *v1alpha1.SecurityP
 		}
 		ruleGroups = append(ruleGroups, nsxRuleAppliedGroup)
 		nsxRule.Scope = []string{nsxRuleAppliedGroupPath}
+		if profilePath != "" {
+			nsxRule.Profiles = []string{profilePath}
+		}
+	}
+	for _, group := range ruleGroups {
+		if err := validateGroupExpression(group); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 	return nsxRules, ruleGroups, projectShares, nil
 }
 
+// validateGroupExpression checks the expressions built for the group before they are sent to NSX, a
+// malformed expression is a bug of the builder rather than of the CR.
+func validateGroupExpression(group *model.Group) error {
+	if group == nil {
+		return nil
+	}
+	if err := expression.Validate(group.Expression); err != nil {
+		return fmt.Errorf("malformed expressions of group %s: %w", *group.Id, err)
+	}
+	return nil
+}
+
 func (service *SecurityPolicyService) buildRuleServiceEntries(port v1alpha1.SecurityPolicyPort, portAddress nsxutil.PortAddress) *data.StructValue {
 	var portRange string
 	sourcePorts := data.NewListValue()
@@ -496,12 +630,18 @@ func (service *SecurityPolicyService) buildRuleServiceEntries(port v1alpha1.Secu
 	}
 	destinationPorts.Add(data.NewStringValue(portRange))
 
-	serviceEntry := data.NewStructValue(
+	serviceEntry := newL4PortSetServiceEntry(sourcePorts, destinationPorts, port.Protocol)
+	log.V(1).Info("built rule service entry", "destinationPorts", portRange, "protocol", port.Protocol)
+	return serviceEntry
+}
+
+func newL4PortSetServiceEntry(sourcePorts, destinationPorts *data.ListValue, protocol corev1.Protocol) *data.StructValue {
+	return data.NewStructValue(
 		"",
 		map[string]data.DataValue{
 			"source_ports":      sourcePorts,
 			"destination_ports": destinationPorts,
-			"l4_protocol":       data.NewStringValue(string(port.Protocol)),
+			"l4_protocol":       data.NewStringValue(string(protocol)),
 			"resource_type":     data.NewStringValue("L4PortSetServiceEntry"),
 			// Adding the following default values to make it easy when compare the
 			// existing object from store and the new built object
@@ -509,14 +649,16 @@ func (service *SecurityPolicyService) buildRuleServiceEntries(port v1alpha1.Secu
 			"overridden":        data.NewBooleanValue(false),
 		},
 	)
-	log.V(1).Info("built rule service entry", "destinationPorts", portRange, "protocol", port.Protocol)
-	return serviceEntry
 }
 
 func (service *SecurityPolicyService) buildRuleAppliedToGroup(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, nsxRuleSrcGroupPath string, nsxRuleDstGroupPath string, createdFor string) (*model.Group, string, error) {
 	var nsxRuleAppliedGroup *model.Group
 	var nsxRuleAppliedGroupPath string
 	var err error
+	if createdFor == common.ResourceTypeGatewayPolicy {
+		// the gateway rules are applied to the gateways instead of the groups of workloads
+		return nil, "ANY", nil
+	}
 	if len(rule.AppliedTo) > 0 {
 		nsxRuleAppliedGroup, nsxRuleAppliedGroupPath, err = service.buildRuleAppliedGroupByRule(
 			obj, rule, ruleIdx, createdFor)
@@ -580,10 +722,7 @@ func (service *SecurityPolicyService) buildRuleOutGroup(obj *v1alpha1.SecurityPo
 }
 
 func (service *SecurityPolicyService) buildRuleID(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, createdFor string) string {
-	prefix := common.SecurityPolicyPrefix
-	if createdFor == common.ResourceTypeNetworkPolicy {
-		prefix = common.NetworkPolicyPrefix
-	}
+	prefix := policyPrefix(createdFor)
 	serializedBytes, _ := json.Marshal(rule)
 	return util.GenerateID(fmt.Sprintf("%s", obj.UID), prefix, fmt.Sprintf("%s", util.Sha1(string(serializedBytes))), fmt.Sprintf("%d", ruleIdx))
 }
@@ -609,6 +748,10 @@ func (service *SecurityPolicyService) buildRuleDisplayName(obj *v1alpha1.Securit
 			suffix = common.RuleSuffixIngressDrop
 		case util.ToUpper(v1alpha1.RuleActionReject):
 			suffix = common.RuleSuffixIngressReject
+		case util.ToUpper(v1alpha1.RuleActionRedirect):
+			suffix = common.RuleSuffixIngressRedirect
+		case util.ToUpper(v1alpha1.RuleActionPass):
+			suffix = common.RuleSuffixIngressPass
 		}
 	} else {
 		switch ruleAction {
@@ -618,6 +761,10 @@ func (service *SecurityPolicyService) buildRuleDisplayName(obj *v1alpha1.Securit
 			suffix = common.RuleSuffixEgressDrop
 		case util.ToUpper(v1alpha1.RuleActionReject):
 			suffix = common.RuleSuffixEgressReject
+		case util.ToUpper(v1alpha1.RuleActionRedirect):
+			suffix = common.RuleSuffixEgressRedirect
+		case util.ToUpper(v1alpha1.RuleActionPass):
+			suffix = common.RuleSuffixEgressPass
 		}
 	}
 	ruleName = service.buildRulePortsString(&rule.Ports, suffix)
@@ -625,7 +772,7 @@ func (service *SecurityPolicyService) buildRuleDisplayName(obj *v1alpha1.Securit
 	if len(rule.Name) > 0 {
 		// For the internal security policy rule converted from network policy, skipping to add suffix for the rule name
 		// if it has its own name generated, usually, it's for the internal isolation security policy rule created for network policy.
-		if createdFor == common.ResourceTypeNetworkPolicy {
+		if createdFor != common.ResourceTypeSecurityPolicy {
 			ruleName = rule.Name
 		} else {
 			// If user defines the rule name, the generated NSX security policy rule will also be added with the same suffix: "-direction-action" as building rulePortsString
@@ -757,7 +904,7 @@ func (service *SecurityPolicyService) buildRulePeerGroupPath(obj *v1alpha1.Secur
 		vpcId := (*vpcInfo).VPCID
 
 		if groupShared {
-			return fmt.Sprintf("/orgs/%s/projects/%s/infra/domains/%s/groups/%s", orgId, projectId, getVpcProjectDomain(), groupID), nil
+			return fmt.Sprintf("/orgs/%s/projects/%s/infra/domains/%s/groups/%s", orgId, projectId, getVpcProjectDomain(service, obj.ObjectMeta.Namespace), groupID), nil
 		}
 		return fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/groups/%s", orgId, projectId, vpcId, groupID), nil
 	}
@@ -880,6 +1027,13 @@ func (service *SecurityPolicyService) buildRuleBasicInfo(obj *v1alpha1.SecurityP
 	if err != nil {
 		return nil, err
 	}
+	if ruleAction == util.ToUpper(v1alpha1.RuleActionPass) {
+		// the Pass rules skip the rest of the admin policies to the namespace ones in the Application category
+		if service.policyCategory(createdFor) != policyCategoryEnvironment {
+			return nil, fmt.Errorf("the Pass action is only supported in the %s category", policyCategoryEnvironment)
+		}
+		ruleAction = model.Rule_ACTION_JUMP_TO_APPLICATION
+	}
 	displayName, err := service.buildRuleDisplayName(obj, rule, portIdx, portNumber, hasNamedport, createdFor)
 	if err != nil {
 		log.Error(err, "failed to build rule's display name", "object.UID", obj.UID, "rule", rule, "createdFor", createdFor)
@@ -894,6 +1048,21 @@ func (service *SecurityPolicyService) buildRuleBasicInfo(obj *v1alpha1.SecurityP
 		Services:       []string{"ANY"},
 		Tags:           service.buildBasicTags(obj, createdFor),
 	}
+	if len(rule.Services) > 0 {
+		// NSX matches the traffic of any of the services and the service entries built from the ports
+		nsxRule.Services, err = service.buildRuleServices(rule)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the log label correlates the DFW syslog events with the CR rule
+	nsxRule.Tag = String(buildLogLabel(rule.RuleTag, buildCorrelationID(obj.UID, ruleIdx)))
+	// Logged is always set, so the logging turned off is updated to NSX as well.
+	logged := obj.Spec.Logged
+	if rule.Logged != nil {
+		logged = *rule.Logged
+	}
+	nsxRule.Logged = common.Bool(logged)
 	log.V(1).Info("built rule basic info", "nsxRule", nsxRule)
 	return &nsxRule, nil
 }
@@ -969,9 +1138,15 @@ func (service *SecurityPolicyService) updateTargetExpressions(obj *v1alpha1.Secu
 		return 0, 0, err
 	}
 
+	if target.All {
+		if referred, err := service.updateAllTargetExpressions(target, group); err != nil || referred {
+			return 0, 0, err
+		}
+	}
+
 	log.V(2).Info("update target expressions", "ruleIndex", ruleIdx)
-	service.appendOperatorIfNeeded(&group.Expression, "OR")
-	expressions := service.buildGroupExpression(&group.Expression)
+	expression.AppendConjunction(&group.Expression, expression.Or)
+	expressions := expression.Nested(&group.Expression)
 
 	// In non-VPC network, setting cluster memberType to Segment for PodSelector and VMSelector ensures the criteria is mixed,
 	// Because the following conditions must have condition whose memberType is SegmentPort.
@@ -979,20 +1154,14 @@ func (service *SecurityPolicyService) updateTargetExpressions(obj *v1alpha1.Secu
 	// Also, following conditions must have condition whose memberType is VpcSubnetPort.
 	// Segment and SegmentPort are not supported in VPC level group.
 	// Target group must be put under VPC level group path.
-	clusterExpression := service.buildExpression(
-		"Condition", clusterMemberType,
-		fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)),
-		"Tag", "EQUALS", "EQUALS",
-	)
+	clusterExpression := expression.TagCondition(clusterMemberType,
+		fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)), "EQUALS", "EQUALS").Build()
 	expressions.Add(clusterExpression)
 
 	if target.PodSelector != nil {
-		service.addOperatorIfNeeded(expressions, "AND")
-		nsExpression := service.buildExpression(
-			"Condition", memberType,
-			fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, false), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))),
-			"Tag", "EQUALS", "EQUALS",
-		)
+		expression.AddConjunction(expressions, expression.And)
+		nsExpression := expression.TagCondition(memberType,
+			fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, false), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))), "EQUALS", "EQUALS").Build()
 		expressions.Add(nsExpression)
 
 		tagValueExpression = nsExpression
@@ -1000,12 +1169,9 @@ func (service *SecurityPolicyService) updateTargetExpressions(obj *v1alpha1.Secu
 		matchExpressions = &target.PodSelector.MatchExpressions
 	}
 	if target.VMSelector != nil {
-		service.addOperatorIfNeeded(expressions, "AND")
-		nsExpression := service.buildExpression(
-			"Condition", memberType,
-			fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, true), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))),
-			"Tag", "EQUALS", "EQUALS",
-		)
+		expression.AddConjunction(expressions, expression.And)
+		nsExpression := expression.TagCondition(memberType,
+			fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, true), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))), "EQUALS", "EQUALS").Build()
 		expressions.Add(nsExpression)
 
 		tagValueExpression = nsExpression
@@ -1019,8 +1185,11 @@ func (service *SecurityPolicyService) updateTargetExpressions(obj *v1alpha1.Secu
 		matchLabelsCount += ClusterTagCount + NameSpaceTagCount
 
 		if matchExpressions != nil {
-			mergedMatchExpressions = service.mergeSelectorMatchExpression(*matchExpressions)
+			mergedMatchExpressions = service.selectorMemoFor(obj).mergedMatchExpressions(*matchExpressions, mergeMatchExpressions)
 			matchExpressionsCount = len(*mergedMatchExpressions)
+			if err = validateSelectorConflicts(*mergedMatchExpressions, matchLabels); err != nil {
+				return 0, 0, err
+			}
 			opInValueCount, err = service.validateSelectorOpIn(*mergedMatchExpressions, matchLabels)
 			if err != nil {
 				return 0, 0, err
@@ -1056,29 +1225,18 @@ func (service *SecurityPolicyService) updateTargetExpressions(obj *v1alpha1.Secu
 	return totalCriteriaCount, totalExprCount, nil
 }
 
-func (service *SecurityPolicyService) appendOperatorIfNeeded(policyExpression *[]*data.StructValue, op string) {
-	if len(*policyExpression) > 0 {
-		operator := service.buildConjOperator(op)
-		*policyExpression = append(*policyExpression, operator)
-	}
-}
-
-func (service *SecurityPolicyService) addOperatorIfNeeded(expressions *data.ListValue, op string) {
-	if !expressions.IsEmpty() {
-		operator := service.buildConjOperator(op)
-		expressions.Add(operator)
-	}
-}
-
 func (service *SecurityPolicyService) updateExpressionsMatchLabels(matchLabels map[string]string, memberType string, expressions *data.ListValue) {
-	for k, v := range *util.NormalizeLabels(&matchLabels) {
-		service.addOperatorIfNeeded(expressions, "AND")
-		expression := service.buildExpression(
-			"Condition", memberType,
-			fmt.Sprintf("%s|%s", k, v),
-			"Tag", "EQUALS", "EQUALS",
-		)
-		expressions.Add(expression)
+	labels := *util.NormalizeLabels(&matchLabels)
+	// the labels are added in order, so that the same selectors build the same criteria
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		expression.AddConjunction(expressions, expression.And)
+		condition := expression.TagCondition(memberType, fmt.Sprintf("%s|%s", k, labels[k]), "EQUALS", "EQUALS").Build()
+		expressions.Add(condition)
 	}
 }
 
@@ -1090,6 +1248,10 @@ func (service *SecurityPolicyService) updateExpressionsMatchLabels(matchLabels m
 //   - {key: k1, operator: NotIn, values: [a2, a3, a4]}
 //     => {key: k1, operator: NotIn, values: [a1, a2, a3, a4]}
 func (service *SecurityPolicyService) mergeSelectorMatchExpression(matchExpressions []v1.LabelSelectorRequirement) *[]v1.LabelSelectorRequirement {
+	return mergeMatchExpressions(matchExpressions)
+}
+
+func mergeMatchExpressions(matchExpressions []v1.LabelSelectorRequirement) *[]v1.LabelSelectorRequirement {
 	mergedMatchExpressions := make([]v1.LabelSelectorRequirement, 0)
 	var mergedSelector v1.LabelSelectorRequirement
 	labelSelectorMap := map[v1.LabelSelectorOperator]map[string][]string{}
@@ -1108,6 +1270,8 @@ func (service *SecurityPolicyService) mergeSelectorMatchExpression(matchExpressi
 			labelSelectorMap[d.Operator][d.Key] = util.RemoveDuplicateStr(
 				labelSelectorMap[d.Operator][d.Key],
 			)
+			// the values are deduplicated in a set, sort them so that the same selectors build the same criteria
+			sort.Strings(labelSelectorMap[d.Operator][d.Key])
 		}
 	}
 
@@ -1119,7 +1283,14 @@ func (service *SecurityPolicyService) mergeSelectorMatchExpression(matchExpressi
 			mergedMatchExpressions = append(mergedMatchExpressions, mergedSelector)
 		}
 	}
+	sort.Slice(mergedMatchExpressions, func(i, j int) bool {
+		if mergedMatchExpressions[i].Key != mergedMatchExpressions[j].Key {
+			return mergedMatchExpressions[i].Key < mergedMatchExpressions[j].Key
+		}
+		return mergedMatchExpressions[i].Operator < mergedMatchExpressions[j].Operator
+	})
 
+	mergedMatchExpressions = normalizeNegatedExpressions(mergedMatchExpressions)
 	return &mergedMatchExpressions
 }
 
@@ -1260,28 +1431,25 @@ func (service *SecurityPolicyService) updateExpressionsMatchExpression(matchExpr
 		expr := matchExpressions[opInIdx]
 		for i := 0; i < len(expr.Values); i++ {
 			if i != 0 {
-				service.appendOperatorIfNeeded(policyExpression, "OR")
-				expressions = service.buildGroupExpression(policyExpression)
+				expression.AppendConjunction(policyExpression, expression.Or)
+				expressions = expression.Nested(policyExpression)
 
 				if clusterExpression != nil {
 					expressions.Add(clusterExpression)
 				}
 				if tagValueExpression != nil {
 					if clusterExpression != nil {
-						service.addOperatorIfNeeded(expressions, "AND")
+						expression.AddConjunction(expressions, expression.And)
 					}
 					expressions.Add(tagValueExpression)
 				}
 				service.updateExpressionsMatchLabels(matchLabels, memberType, expressions)
 			}
 
-			service.addOperatorIfNeeded(expressions, "AND")
-			expression := service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|%s", expr.Key, expr.Values[i]),
-				"Tag", "EQUALS", "EQUALS",
-			)
-			expressions.Add(expression)
+			expression.AddConjunction(expressions, expression.And)
+			condition := expression.TagCondition(memberType,
+				fmt.Sprintf("%s|%s", expr.Key, expr.Values[i]), "EQUALS", "EQUALS").Build()
+			expressions.Add(condition)
 			err = service.buildExpressionsMatchExpression(matchExpressions, memberType, expressions)
 			if err != nil {
 				break
@@ -1335,15 +1503,15 @@ func (service *SecurityPolicyService) updateMixedExpressionsMatchExpression(nsMa
 		expr := opInMatchExpressions[opInIdx]
 		for i := 0; i < len(expr.Values); i++ {
 			if i != 0 {
-				service.appendOperatorIfNeeded(policyExpression, "OR")
-				expressions = service.buildGroupExpression(policyExpression)
+				expression.AppendConjunction(policyExpression, expression.Or)
+				expressions = expression.Nested(policyExpression)
 
 				if clusterExpression != nil {
 					expressions.Add(clusterExpression)
 				}
 				if tagValueExpression != nil {
 					if clusterExpression != nil {
-						service.addOperatorIfNeeded(expressions, "AND")
+						expression.AddConjunction(expressions, expression.And)
 					}
 					expressions.Add(tagValueExpression)
 				}
@@ -1361,13 +1529,10 @@ func (service *SecurityPolicyService) updateMixedExpressionsMatchExpression(nsMa
 				break
 			}
 
-			service.addOperatorIfNeeded(expressions, "AND")
-			expression := service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|%s", expr.Key, expr.Values[i]),
-				"Tag", "EQUALS", "EQUALS",
-			)
-			expressions.Add(expression)
+			expression.AddConjunction(expressions, expression.And)
+			condition := expression.TagCondition(memberType,
+				fmt.Sprintf("%s|%s", expr.Key, expr.Values[i]), "EQUALS", "EQUALS").Build()
+			expressions.Add(condition)
 
 			err = service.buildExpressionsMatchExpression(opInMatchExpressions, memberType, expressions)
 			if err != nil {
@@ -1378,6 +1543,15 @@ func (service *SecurityPolicyService) updateMixedExpressionsMatchExpression(nsMa
 	return err
 }
 
+// ipBlockAddresses returns the CIDR of the IP block, or the IP ranges of the CIDR without the excepts, since
+// NSX IPAddressExpression can't exclude addresses.
+func ipBlockAddresses(block v1alpha1.IPBlock) ([]string, error) {
+	if len(block.Except) == 0 {
+		return []string{block.CIDR}, nil
+	}
+	return util.GetCIDRRangesWithExcept(block.CIDR, block.Except)
+}
+
 func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.SecurityPolicy, peer *v1alpha1.SecurityPolicyPeer, group *model.Group, ruleIdx int, groupShared bool) (int, int, error) {
 	var err error
 	errorMsg := ""
@@ -1390,21 +1564,36 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 	mixedNsSelector := false
 	isVpcEnable := isVpcEnabled(service)
 
-	if len(peer.IPBlocks) > 0 {
-		addresses := data.NewListValue()
-		for _, block := range peer.IPBlocks {
-			addresses.Add(data.NewStringValue(block.CIDR))
+	networkCIDRs, networkPaths, err := service.buildPeerNetworks(peer)
+	if err != nil {
+		return 0, 0, err
+	}
+	identityGroupPaths, err := service.buildPeerIdentityGroups(peer)
+	if err != nil {
+		return 0, 0, err
+	}
+	networkPaths = append(networkPaths, identityGroupPaths...)
+	workloadIPs, err := service.buildPeerWorkloadIPs(obj, peer)
+	if err != nil {
+		return 0, 0, err
+	}
+	var addresses []string
+	for _, block := range peer.IPBlocks {
+		blockAddresses, err := ipBlockAddresses(block)
+		if err != nil {
+			return 0, 0, err
 		}
-		service.appendOperatorIfNeeded(&group.Expression, "OR")
-
-		blockExpression := data.NewStructValue(
-			"",
-			map[string]data.DataValue{
-				"resource_type": data.NewStringValue("IPAddressExpression"),
-				"ip_addresses":  addresses,
-			},
-		)
-		group.Expression = append(group.Expression, blockExpression)
+		addresses = append(addresses, blockAddresses...)
+	}
+	addresses = append(addresses, networkCIDRs...)
+	addresses = append(addresses, workloadIPs...)
+	if len(addresses) > 0 {
+		expression.AppendConjunction(&group.Expression, expression.Or)
+		group.Expression = append(group.Expression, expression.IPAddresses(addresses))
+	}
+	if len(networkPaths) > 0 {
+		expression.AppendConjunction(&group.Expression, expression.Or)
+		group.Expression = append(group.Expression, expression.Paths(networkPaths))
 	}
 
 	log.V(2).Info("update peer expressions", "ruleIndex", ruleIdx)
@@ -1421,8 +1610,8 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 		return 0, 0, err
 	}
 
-	service.appendOperatorIfNeeded(&group.Expression, "OR")
-	expressions := service.buildGroupExpression(&group.Expression)
+	expression.AppendConjunction(&group.Expression, expression.Or)
+	expressions := expression.Nested(&group.Expression)
 
 	// In non-VPC network, setting cluster memberType to Segment for PodSelector and VMSelector ensures the criteria is mixed,
 	// Because the following conditions must have condition whose memberType is SegmentPort
@@ -1447,29 +1636,18 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 		memberType = "VpcSubnetPort"
 	}
 
-	clusterExpression := service.buildExpression(
-		"Condition", clusterMemberType,
-		fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)),
-		"Tag", "EQUALS", "EQUALS",
-	)
+	clusterExpression := expression.TagCondition(clusterMemberType,
+		fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)), "EQUALS", "EQUALS").Build()
 	expressions.Add(clusterExpression)
 
 	if peer.PodSelector != nil {
-		service.addOperatorIfNeeded(expressions, "AND")
-		podExpression := service.buildExpression(
-			"Condition",
-			memberType,
-			fmt.Sprintf("%s|", getScopePodTag(service)),
-			"Tag",
-			"EQUALS",
-			"EQUALS",
-		)
+		expression.AddConjunction(expressions, expression.And)
+		podExpression := expression.TagCondition(memberType,
+			fmt.Sprintf("%s|", getScopePodTag(service)), "EQUALS", "EQUALS").Build()
 
 		if peer.NamespaceSelector == nil {
-			podExpression = service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, false), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))),
-				"Tag", "EQUALS", "EQUALS")
+			podExpression = expression.TagCondition(memberType,
+				fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, false), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))), "EQUALS", "EQUALS").Build()
 			mixedNsSelector = false
 		} else {
 			mixedNsSelector = true
@@ -1484,21 +1662,13 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 		matchLabelsCount += ClusterTagCount + NameSpaceTagCount
 	}
 	if peer.VMSelector != nil {
-		service.addOperatorIfNeeded(expressions, "AND")
-		vmExpression := service.buildExpression(
-			"Condition",
-			memberType,
-			fmt.Sprintf("%s|", getScopeVMInterfaceTag(service)),
-			"Tag",
-			"EQUALS",
-			"EQUALS",
-		)
+		expression.AddConjunction(expressions, expression.And)
+		vmExpression := expression.TagCondition(memberType,
+			fmt.Sprintf("%s|", getScopeVMInterfaceTag(service)), "EQUALS", "EQUALS").Build()
 
 		if peer.NamespaceSelector == nil {
-			vmExpression = service.buildExpression(
-				"Condition", memberType,
-				fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, true), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))),
-				"Tag", "EQUALS", "EQUALS")
+			vmExpression = expression.TagCondition(memberType,
+				fmt.Sprintf("%s|%s", getScopeNamespaceUIDTag(service, true), string(service.getNamespaceUID(obj.ObjectMeta.Namespace))), "EQUALS", "EQUALS").Build()
 			mixedNsSelector = false
 		} else {
 			mixedNsSelector = true
@@ -1520,12 +1690,9 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 				// 2. An expression list size is equal to or greater than 3
 				// 3. In a list, with indices starting from 0, all non-conjunction expressions must be at even indices
 				// Hence, add one more SegmentPort member condition to meet the criteria aforementioned
-				service.addOperatorIfNeeded(expressions, "AND")
-				clusterSegPortExpression := service.buildExpression(
-					"Condition", "SegmentPort",
-					fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)),
-					"Tag", "EQUALS", "EQUALS",
-				)
+				expression.AddConjunction(expressions, expression.And)
+				clusterSegPortExpression := expression.TagCondition("SegmentPort",
+					fmt.Sprintf("%s|%s", getScopeCluserTag(service), getCluster(service)), "EQUALS", "EQUALS").Build()
 				expressions.Add(clusterSegPortExpression)
 				matchLabelsCount = ClusterTagCount + 1
 				matchExpressionsCount = 0
@@ -1548,13 +1715,22 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 			}
 
 			// Validate expressions for POD/VM Selectors
-			mergedMatchExpressions = service.mergeSelectorMatchExpression(*matchExpressions)
+			mergedMatchExpressions = service.selectorMemoFor(obj).mergedMatchExpressions(*matchExpressions, mergeMatchExpressions)
+			if err = validateSelectorConflicts(*mergedMatchExpressions, matchLabels); err != nil {
+				return 0, 0, err
+			}
 			opInValueCount, err = service.validateSelectorOpIn(*mergedMatchExpressions, matchLabels)
+			if err != nil {
+				return 0, 0, err
+			}
 
-			nsMergedMatchExpressions := service.mergeSelectorMatchExpression(*nsMatchExpressions)
-			nsOpInValCount, opErr := service.validateSelectorOpIn(*nsMergedMatchExpressions, nsMatchLabels)
-
-			if err != nil || opErr != nil {
+			nsMergedMatchExpressions := service.selectorMemoFor(obj).mergedMatchExpressions(*nsMatchExpressions, mergeMatchExpressions)
+			if err = validateSelectorConflicts(*nsMergedMatchExpressions, nsMatchLabels); err != nil {
+				return 0, 0, err
+			}
+			var nsOpInValCount int
+			nsOpInValCount, err = service.validateSelectorOpIn(*nsMergedMatchExpressions, nsMatchLabels)
+			if err != nil {
 				return 0, 0, err
 			}
 
@@ -1573,7 +1749,7 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 
 			// NamespaceSelector AND with PodSelector or VMSelector expressions to produce final expressions
 			err = service.updateMixedExpressionsMatchExpression(*nsMergedMatchExpressions, nsMatchLabels,
-				*matchExpressions, matchLabels, &group.Expression, clusterExpression, tagValueExpression, expressions)
+				*mergedMatchExpressions, matchLabels, &group.Expression, clusterExpression, tagValueExpression, expressions)
 			if err != nil {
 				return 0, 0, err
 			}
@@ -1592,8 +1768,11 @@ func (service *SecurityPolicyService) updatePeerExpressions(obj *v1alpha1.Securi
 					}
 				}
 
-				mergedMatchExpressions = service.mergeSelectorMatchExpression(*matchExpressions)
+				mergedMatchExpressions = service.selectorMemoFor(obj).mergedMatchExpressions(*matchExpressions, mergeMatchExpressions)
 				matchExpressionsCount = len(*mergedMatchExpressions)
+				if err = validateSelectorConflicts(*mergedMatchExpressions, matchLabels); err != nil {
+					return 0, 0, err
+				}
 				opInValueCount, err = service.validateSelectorOpIn(
 					*mergedMatchExpressions,
 					matchLabels,
@@ -1653,14 +1832,7 @@ func (service *SecurityPolicyService) buildShareID(nsxProjectName, groupID strin
 }
 
 func (service *SecurityPolicyService) buildShareTags(obj *v1alpha1.SecurityPolicy, projectId string, group *model.Group, createdFor string) []model.Tag {
-	var scopeOwnerName, scopeOwnerUID string
-	if createdFor == common.ResourceTypeSecurityPolicy {
-		scopeOwnerName = common.TagValueScopeSecurityPolicyName
-		scopeOwnerUID = common.TagValueScopeSecurityPolicyUID
-	} else {
-		scopeOwnerName = common.TagScopeNetworkPolicyName
-		scopeOwnerUID = common.TagScopeNetworkPolicyUID
-	}
+	scopeOwnerName, scopeOwnerUID := ownerTagScopes(createdFor)
 	tags := []model.Tag{
 		{
 			Scope: String(common.TagScopeVersion),
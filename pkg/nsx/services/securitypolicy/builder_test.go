@@ -9,11 +9,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 )
 
@@ -45,6 +49,12 @@ func TestBuildSecurityPolicy(t *testing.T) {
 	vmSelectorRule1Name00, _ := service.buildRuleDisplayName(&spWithVMSelector, &spWithVMSelector.Spec.Rules[1], 0, -1, false, common.ResourceTypeSecurityPolicy)
 	vmSelectorRule2Name00, _ := service.buildRuleDisplayName(&spWithVMSelector, &spWithVMSelector.Spec.Rules[2], 0, -1, false, common.ResourceTypeSecurityPolicy)
 
+	podSelectorRule0Label := buildCorrelationID(spWithPodSelector.UID, 0)
+	podSelectorRule1Label := buildCorrelationID(spWithPodSelector.UID, 1)
+	vmSelectorRule0Label := buildCorrelationID(spWithVMSelector.UID, 0)
+	vmSelectorRule1Label := buildCorrelationID(spWithVMSelector.UID, 1)
+	vmSelectorRule2Label := buildCorrelationID(spWithVMSelector.UID, 2)
+
 	tests := []struct {
 		name           string
 		inputPolicy    *v1alpha1.SecurityPolicy
@@ -69,6 +79,8 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"/infra/domains/k8scl-one/groups/sp_uidA_0_src"},
 						Action:            &nsxActionAllow,
+						Logged:            common.Bool(false),
+						Tag:               &podSelectorRule0Label,
 						Tags:              basicTags,
 					},
 					{
@@ -82,6 +94,8 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						SourceGroups:      []string{"/infra/domains/k8scl-one/groups/sp_uidA_1_src"},
 						Action:            &nsxActionAllow,
 						ServiceEntries:    []*data.StructValue{serviceEntry},
+						Logged:            common.Bool(false),
+						Tag:               &podSelectorRule1Label,
 						Tags:              basicTags,
 					},
 				},
@@ -107,6 +121,8 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Logged:            common.Bool(false),
+						Tag:               &vmSelectorRule0Label,
 						Tags:              basicTags,
 					},
 					{
@@ -119,6 +135,8 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Logged:            common.Bool(false),
+						Tag:               &vmSelectorRule1Label,
 						Tags:              basicTags,
 					},
 
@@ -132,6 +150,8 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Logged:            common.Bool(false),
+						Tag:               &vmSelectorRule2Label,
 						Tags:              basicTags,
 					},
 				},
@@ -187,6 +207,28 @@ func TestBuildPolicyGroup(t *testing.T) {
 	}
 }
 
+func TestUpdatePeerExpressionsIPBlockExcept(t *testing.T) {
+	service := fakeService()
+	peer := &v1alpha1.SecurityPolicyPeer{IPBlocks: []v1alpha1.IPBlock{
+		{CIDR: "192.168.0.0/24", Except: []string{"192.168.0.0/28", "192.168.0.128/25"}},
+		{CIDR: "10.0.0.0/8"},
+	}}
+	group := model.Group{}
+	_, _, err := service.updatePeerExpressions(&v1alpha1.SecurityPolicy{}, peer, &group, 0, false)
+	assert.NoError(t, err)
+	assert.Len(t, group.Expression, 1)
+	addresses, _ := group.Expression[0].Field("ip_addresses")
+	assert.Equal(t, []data.DataValue{
+		data.NewStringValue("192.168.0.16-192.168.0.127"),
+		data.NewStringValue("10.0.0.0/8"),
+	}, addresses.(*data.ListValue).List())
+
+	// the except of an IPv6 CIDR is rejected
+	peer.IPBlocks = []v1alpha1.IPBlock{{CIDR: "fd00::/64", Except: []string{"fd00::/80"}}}
+	_, _, err = service.updatePeerExpressions(&v1alpha1.SecurityPolicy{}, peer, &model.Group{}, 0, false)
+	assert.EqualError(t, err, "except is only supported with IPv4 CIDR, got fd00::/64")
+}
+
 func TestBuildTargetTags(t *testing.T) {
 	ruleTagID0 := service.buildRuleID(&spWithPodSelector, &spWithPodSelector.Spec.Rules[0], 0, common.ResourceTypeSecurityPolicy)
 	tests := []struct {
@@ -391,7 +433,7 @@ func TestMergeSelectorMatchExpression(t *testing.T) {
 
 func TestUpdateExpressionsMatchExpression(t *testing.T) {
 	group := model.Group{}
-	expressions := service.buildGroupExpression(&group.Expression)
+	expressions := expression.Nested(&group.Expression)
 	memberType := "SegmentPort"
 	matchLabels := map[string]string{"VM_selector_1": "VM_value_1"}
 
@@ -573,7 +615,7 @@ func TestValidateNsSelectorOpNotIn(t *testing.T) {
 
 func TestUpdateMixedExpressionsMatchExpression(t *testing.T) {
 	group := model.Group{}
-	expressions := service.buildGroupExpression(&group.Expression)
+	expressions := expression.Nested(&group.Expression)
 	nsMatchLabels := map[string]string{"ns_selector_1": "ns_1"}
 	matchLabels := map[string]string{"pod_selector_1": "pod_value_1"}
 
@@ -807,3 +849,101 @@ func TestBuildRuleDisplayName(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildRuleTag(t *testing.T) {
+	sp := securityPolicyWithMultipleNormalPorts.DeepCopy()
+	service := fakeService()
+	service.Client = fake.NewClientBuilder().WithObjects(&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: sp.Namespace}}).Build()
+	nsxRule, err := service.buildRuleBasicInfo(sp, &sp.Spec.Rules[0], 0, 0, 0, -1, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	correlationID := buildCorrelationID(sp.UID, 0)
+	assert.Equal(t, correlationID, *nsxRule.Tag)
+
+	sp.Spec.Rules[0].RuleTag = "payments"
+	nsxRule, err = service.buildRuleBasicInfo(sp, &sp.Spec.Rules[0], 0, 0, 0, -1, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments|"+correlationID, *nsxRule.Tag)
+}
+
+func TestBuildRuleLogged(t *testing.T) {
+	sp := securityPolicyWithMultipleNormalPorts.DeepCopy()
+	service := fakeService()
+	service.Client = fake.NewClientBuilder().WithObjects(&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: sp.Namespace}}).Build()
+	nsxRule, err := service.buildRuleBasicInfo(sp, &sp.Spec.Rules[0], 0, 0, 0, -1, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.False(t, *nsxRule.Logged)
+
+	// the rules are logged by the policy level default
+	sp.Spec.Logged = true
+	nsxRule, err = service.buildRuleBasicInfo(sp, &sp.Spec.Rules[0], 0, 0, 0, -1, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.True(t, *nsxRule.Logged)
+
+	// the rule level Logged takes precedence
+	sp.Spec.Rules[0].Logged = common.Bool(false)
+	nsxRule, err = service.buildRuleBasicInfo(sp, &sp.Spec.Rules[0], 0, 0, 0, -1, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.False(t, *nsxRule.Logged)
+}
+
+func TestBuildSecurityPolicyReusePeerGroups(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	sp := spWithVMSelector.DeepCopy()
+	// the destinations of the first rule are the sources of the new rule
+	sp.Spec.Rules = append(sp.Spec.Rules, v1alpha1.SecurityPolicyRule{
+		Action:    &allowAction,
+		Direction: &directionIn,
+		Name:      "rule-from-VM-selector",
+		Sources:   sp.Spec.Rules[0].Destinations,
+	})
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"/infra/domains/k8scl-one:test/groups/sp_uidA_0_dst"}, nsxSecurityPolicy.Rules[0].DestinationGroups)
+	assert.Equal(t, []string{"/infra/domains/k8scl-one:test/groups/sp_uidA_0_dst"}, nsxSecurityPolicy.Rules[3].SourceGroups)
+	groupIDs := make([]string, 0, len(*nsxGroups))
+	for _, group := range *nsxGroups {
+		groupIDs = append(groupIDs, *group.Id)
+	}
+	assert.Contains(t, groupIDs, "sp_uidA_0_dst")
+	assert.NotContains(t, groupIDs, "sp_uidA_3_src")
+	// the groups of the other peers are kept
+	assert.Contains(t, groupIDs, "sp_uidA_1_dst")
+	assert.Contains(t, groupIDs, "sp_uidA_2_dst")
+}
+
+func TestBuildLabelTags(t *testing.T) {
+	sp := spWithPodSelector.DeepCopy()
+	sp.Labels = map[string]string{
+		"compliance.example.com/pci":   "true",
+		"compliance.example.com/owner": "payments",
+		"app":                          "web",
+		common.TagScopePrefix + "x":    "y",
+	}
+	assert.Empty(t, service.buildLabelTags(sp))
+
+	s := &SecurityPolicyService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{
+				CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"},
+				K8sConfig: &config.K8sConfig{LabelTagPrefixes: []string{"compliance.example.com/", common.TagScopePrefix}},
+			},
+		},
+	}
+	assert.Equal(t, []model.Tag{
+		{Scope: String("compliance.example.com/owner"), Tag: String("payments")},
+		{Scope: String("compliance.example.com/pci"), Tag: String("true")},
+	}, s.buildLabelTags(sp))
+
+	for i := 0; i < MaxLabelTags; i++ {
+		sp.Labels[fmt.Sprintf("compliance.example.com/k%02d", i)] = "v"
+	}
+	assert.Len(t, s.buildLabelTags(sp), MaxLabelTags)
+}
@@ -0,0 +1,127 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"encoding/json"
+	"runtime"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	meta1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// maxBuildWorkers bounds the workers building the rules of a SecurityPolicy in parallel.
+const maxBuildWorkers = 8
+
+// ruleBuildResult is the result of building a rule of the SecurityPolicy with its groups.
+type ruleBuildResult struct {
+	rules         []*model.Rule
+	groups        []*model.Group
+	projectShares []*ProjectShare
+	err           error
+}
+
+// buildRulesInParallel builds the rules of the SecurityPolicy and their groups by a bounded pool of workers,
+// since expanding the peers of large selector sets is CPU heavy. The results are in the order of the rules.
+func (service *SecurityPolicyService) buildRulesInParallel(obj *v1alpha1.SecurityPolicy, createdFor string) []ruleBuildResult {
+	results := make([]ruleBuildResult, len(obj.Spec.Rules))
+	workers := min(maxBuildWorkers, runtime.GOMAXPROCS(0), len(obj.Spec.Rules))
+	ruleIdxs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ruleIdx := range ruleIdxs {
+				rule := obj.Spec.Rules[ruleIdx]
+				result := &results[ruleIdx]
+				result.rules, result.groups, result.projectShares, result.err = service.buildRuleAndGroups(obj, &rule, ruleIdx, createdFor)
+			}
+		}()
+	}
+	for ruleIdx := range obj.Spec.Rules {
+		ruleIdxs <- ruleIdx
+	}
+	close(ruleIdxs)
+	wg.Wait()
+	return results
+}
+
+// selectorMemo memoizes the selector conversions repeated across the rules of a SecurityPolicy build. It's
+// created by buildSecurityPolicy and dropped once the build returns, so the Namespaces resolved by a build are
+// never served to another one. A nil selectorMemo memoizes nothing.
+type selectorMemo struct {
+	mu sync.Mutex
+	// merged are the merged match expressions, keyed by the serialized match expressions.
+	merged map[string][]meta1.LabelSelectorRequirement
+	// namespaces are the Namespaces resolved by the selectors, keyed by the selector string.
+	namespaces map[string]*v1.NamespaceList
+}
+
+func newSelectorMemo() *selectorMemo {
+	return &selectorMemo{
+		merged:     map[string][]meta1.LabelSelectorRequirement{},
+		namespaces: map[string]*v1.NamespaceList{},
+	}
+}
+
+// selectorMemoFor returns the memo of the SecurityPolicy being built, nil if it's not being built.
+func (service *SecurityPolicyService) selectorMemoFor(obj *v1alpha1.SecurityPolicy) *selectorMemo {
+	if memo, ok := service.selectorMemos.Load(obj.UID); ok {
+		return memo.(*selectorMemo)
+	}
+	return nil
+}
+
+// mergedMatchExpressions returns a copy of the merged match expressions memoized, which are merged by merge
+// if they are not memoized yet.
+func (m *selectorMemo) mergedMatchExpressions(matchExpressions []meta1.LabelSelectorRequirement,
+	merge func([]meta1.LabelSelectorRequirement) *[]meta1.LabelSelectorRequirement,
+) *[]meta1.LabelSelectorRequirement {
+	if m == nil {
+		return merge(matchExpressions)
+	}
+	key, err := json.Marshal(matchExpressions)
+	if err != nil {
+		return merge(matchExpressions)
+	}
+	m.mu.Lock()
+	merged, ok := m.merged[string(key)]
+	m.mu.Unlock()
+	if !ok {
+		merged = *merge(matchExpressions)
+		m.mu.Lock()
+		m.merged[string(key)] = merged
+		m.mu.Unlock()
+	}
+	copied := make([]meta1.LabelSelectorRequirement, len(merged))
+	for i := range merged {
+		merged[i].DeepCopyInto(&copied[i])
+	}
+	return &copied
+}
+
+// resolvedNamespaces returns the Namespaces memoized for the selector, which are read only.
+func (m *selectorMemo) resolvedNamespaces(selector string) (*v1.NamespaceList, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nsList, ok := m.namespaces[selector]
+	return nsList, ok
+}
+
+func (m *selectorMemo) storeNamespaces(selector string, nsList *v1.NamespaceList) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.namespaces[selector] = nsList
+}
@@ -0,0 +1,85 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	meta1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_buildRulesInParallel(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	sp := spWithPodSelector.DeepCopy()
+	service.selectorMemos.Store(sp.UID, newSelectorMemo())
+	results := service.buildRulesInParallel(sp, common.ResourceTypeSecurityPolicy)
+	assert.Len(t, results, len(sp.Spec.Rules))
+	// the results are the same as the rules built one by one, in the order of the rules
+	for ruleIdx := range sp.Spec.Rules {
+		rule := sp.Spec.Rules[ruleIdx]
+		nsxRules, nsxGroups, projectShares, err := service.buildRuleAndGroups(sp, &rule, ruleIdx, common.ResourceTypeSecurityPolicy)
+		assert.Equal(t, err, results[ruleIdx].err)
+		assert.Equal(t, nsxRules, results[ruleIdx].rules)
+		assert.Equal(t, nsxGroups, results[ruleIdx].groups)
+		assert.Equal(t, projectShares, results[ruleIdx].projectShares)
+	}
+}
+
+func TestSelectorMemo(t *testing.T) {
+	merges := 0
+	merge := func(matchExpressions []meta1.LabelSelectorRequirement) *[]meta1.LabelSelectorRequirement {
+		merges++
+		return mergeMatchExpressions(matchExpressions)
+	}
+	matchExpressions := []meta1.LabelSelectorRequirement{
+		{Key: "app", Operator: meta1.LabelSelectorOpNotIn, Values: []string{"a"}},
+		{Key: "app", Operator: meta1.LabelSelectorOpNotIn, Values: []string{"b"}},
+	}
+
+	// nothing is memoized out of a build
+	var noMemo *selectorMemo
+	noMemo.mergedMatchExpressions(matchExpressions, merge)
+	noMemo.mergedMatchExpressions(matchExpressions, merge)
+	assert.Equal(t, 2, merges)
+	noMemo.storeNamespaces("env=prod", &v1.NamespaceList{})
+	_, ok := noMemo.resolvedNamespaces("env=prod")
+	assert.False(t, ok)
+
+	memo := newSelectorMemo()
+	merged := memo.mergedMatchExpressions(matchExpressions, merge)
+	(*merged)[0].Values[0] = "modified"
+	memoized := memo.mergedMatchExpressions(matchExpressions, merge)
+	assert.Equal(t, 3, merges)
+	assert.ElementsMatch(t, []string{"a", "b"}, (*memoized)[0].Values)
+}
+
+func TestSecurityPolicyService_buildSecurityPolicyDropsSelectorMemo(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	sp := spWithPodSelector.DeepCopy()
+	_, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	// the Namespaces resolved by the build are not served to the next build
+	assert.Nil(t, service.selectorMemoFor(sp))
+}
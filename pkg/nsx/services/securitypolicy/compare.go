@@ -12,6 +12,14 @@ type (
 	Rule           model.Rule
 	Group          model.Group
 	Share          model.Share
+
+	RedirectionPolicy model.RedirectionPolicy
+	RedirectionRule   model.RedirectionRule
+	ContextProfile    model.PolicyContextProfile
+	GatewayPolicy     model.GatewayPolicy
+	IDSPolicy         model.IdsSecurityPolicy
+	IDSRule           model.IdsRule
+	IDSProfile        model.IdsProfile
 )
 
 type Comparable = common.Comparable
@@ -39,6 +47,7 @@ func (sp *SecurityPolicy) Value() data.DataValue {
 		SequenceNumber: sp.SequenceNumber,
 		Scope:          sp.Scope,
 		Tags:           sp.Tags,
+		Category:       sp.Category,
 	}
 	dataValue, _ := ComparableToSecurityPolicy(s).GetDataValue__()
 	return dataValue
@@ -57,6 +66,9 @@ func (rule *Rule) Value() data.DataValue {
 		ServiceEntries:    rule.ServiceEntries,
 		DestinationGroups: rule.DestinationGroups,
 		SourceGroups:      rule.SourceGroups,
+		Profiles:          ruleProfiles(rule.Profiles),
+		Tag:               rule.Tag,
+		Logged:            rule.Logged,
 	}
 	dataValue, _ := ComparableToRule(r).GetDataValue__()
 	return dataValue
@@ -180,3 +192,233 @@ func ComparableToShares(shares []Comparable) []model.Share {
 func ComparableToShare(share Comparable) *model.Share {
 	return (*model.Share)(share.(*Share))
 }
+
+func (policy *RedirectionPolicy) Key() string {
+	return *policy.Id
+}
+
+func (rule *RedirectionRule) Key() string {
+	return *rule.Id
+}
+
+func (policy *RedirectionPolicy) Value() data.DataValue {
+	p := &model.RedirectionPolicy{
+		Id:             policy.Id,
+		DisplayName:    policy.DisplayName,
+		SequenceNumber: policy.SequenceNumber,
+		Scope:          policy.Scope,
+		RedirectTo:     policy.RedirectTo,
+		Tags:           policy.Tags,
+	}
+	dataValue, _ := p.GetDataValue__()
+	return dataValue
+}
+
+func (rule *RedirectionRule) Value() data.DataValue {
+	r := &model.RedirectionRule{
+		DisplayName:       rule.DisplayName,
+		Id:                rule.Id,
+		Tags:              rule.Tags,
+		Direction:         rule.Direction,
+		Scope:             rule.Scope,
+		SequenceNumber:    rule.SequenceNumber,
+		Action:            rule.Action,
+		Services:          rule.Services,
+		ServiceEntries:    rule.ServiceEntries,
+		DestinationGroups: rule.DestinationGroups,
+		SourceGroups:      rule.SourceGroups,
+	}
+	dataValue, _ := r.GetDataValue__()
+	return dataValue
+}
+
+func RedirectionPoliciesPtrToComparable(policies []*model.RedirectionPolicy) []Comparable {
+	res := make([]Comparable, 0, len(policies))
+	for i := range policies {
+		res = append(res, (*RedirectionPolicy)(policies[i]))
+	}
+	return res
+}
+
+func RedirectionPoliciesToComparable(policies []model.RedirectionPolicy) []Comparable {
+	res := make([]Comparable, 0, len(policies))
+	for i := range policies {
+		res = append(res, (*RedirectionPolicy)(&policies[i]))
+	}
+	return res
+}
+
+func RedirectionRulesPtrToComparable(rules []*model.RedirectionRule) []Comparable {
+	res := make([]Comparable, 0, len(rules))
+	for i := range rules {
+		res = append(res, (*RedirectionRule)(rules[i]))
+	}
+	return res
+}
+
+func RedirectionRulesToComparable(rules []model.RedirectionRule) []Comparable {
+	res := make([]Comparable, 0, len(rules))
+	for i := range rules {
+		res = append(res, (*RedirectionRule)(&rules[i]))
+	}
+	return res
+}
+
+func ComparableToRedirectionPolicies(policies []Comparable) []model.RedirectionPolicy {
+	res := make([]model.RedirectionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		res = append(res, (model.RedirectionPolicy)(*(policy.(*RedirectionPolicy))))
+	}
+	return res
+}
+
+func ComparableToRedirectionRules(rules []Comparable) []model.RedirectionRule {
+	res := make([]model.RedirectionRule, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, (model.RedirectionRule)(*(rule.(*RedirectionRule))))
+	}
+	return res
+}
+
+// ruleProfiles returns the context profiles of the rule, NSX returns "ANY" for the rule without profiles.
+func ruleProfiles(profiles []string) []string {
+	if len(profiles) == 1 && profiles[0] == "ANY" {
+		return nil
+	}
+	return profiles
+}
+
+func (profile *ContextProfile) Key() string {
+	return *profile.Id
+}
+
+func (profile *ContextProfile) Value() data.DataValue {
+	p := &model.PolicyContextProfile{
+		Id:          profile.Id,
+		DisplayName: profile.DisplayName,
+		Attributes:  profile.Attributes,
+		Tags:        profile.Tags,
+	}
+	dataValue, _ := p.GetDataValue__()
+	return dataValue
+}
+
+func ContextProfilesPtrToComparable(profiles []*model.PolicyContextProfile) []Comparable {
+	res := make([]Comparable, 0, len(profiles))
+	for i := range profiles {
+		res = append(res, (*ContextProfile)(profiles[i]))
+	}
+	return res
+}
+
+func ContextProfilesToComparable(profiles []model.PolicyContextProfile) []Comparable {
+	res := make([]Comparable, 0, len(profiles))
+	for i := range profiles {
+		res = append(res, (*ContextProfile)(&profiles[i]))
+	}
+	return res
+}
+
+func ComparableToContextProfiles(profiles []Comparable) []model.PolicyContextProfile {
+	res := make([]model.PolicyContextProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		res = append(res, (model.PolicyContextProfile)(*(profile.(*ContextProfile))))
+	}
+	return res
+}
+
+func (gp *GatewayPolicy) Key() string {
+	return *gp.Id
+}
+
+func (gp *GatewayPolicy) Value() data.DataValue {
+	p := &model.GatewayPolicy{
+		Id:             gp.Id,
+		DisplayName:    gp.DisplayName,
+		SequenceNumber: gp.SequenceNumber,
+		Scope:          gp.Scope,
+		Tags:           gp.Tags,
+		Category:       gp.Category,
+	}
+	dataValue, _ := p.GetDataValue__()
+	return dataValue
+}
+
+func (policy *IDSPolicy) Key() string {
+	return *policy.Id
+}
+
+func (policy *IDSPolicy) Value() data.DataValue {
+	p := &model.IdsSecurityPolicy{
+		Id:             policy.Id,
+		DisplayName:    policy.DisplayName,
+		SequenceNumber: policy.SequenceNumber,
+		Scope:          policy.Scope,
+		Tags:           policy.Tags,
+	}
+	dataValue, _ := p.GetDataValue__()
+	return dataValue
+}
+
+func (rule *IDSRule) Key() string {
+	return *rule.Id
+}
+
+func (rule *IDSRule) Value() data.DataValue {
+	r := &model.IdsRule{
+		Id:                rule.Id,
+		DisplayName:       rule.DisplayName,
+		Tags:              rule.Tags,
+		Direction:         rule.Direction,
+		Scope:             rule.Scope,
+		SequenceNumber:    rule.SequenceNumber,
+		Action:            rule.Action,
+		Services:          rule.Services,
+		ServiceEntries:    rule.ServiceEntries,
+		DestinationGroups: rule.DestinationGroups,
+		SourceGroups:      rule.SourceGroups,
+		IdsProfiles:       rule.IdsProfiles,
+		Tag:               rule.Tag,
+	}
+	dataValue, _ := r.GetDataValue__()
+	return dataValue
+}
+
+func (profile *IDSProfile) Key() string {
+	return *profile.Id
+}
+
+func (profile *IDSProfile) Value() data.DataValue {
+	p := &model.IdsProfile{
+		Id:              profile.Id,
+		DisplayName:     profile.DisplayName,
+		Tags:            profile.Tags,
+		ProfileSeverity: profile.ProfileSeverity,
+	}
+	dataValue, _ := p.GetDataValue__()
+	return dataValue
+}
+
+func IDSRulesPtrToComparable(rules []*model.IdsRule) []Comparable {
+	res := make([]Comparable, 0, len(rules))
+	for i := range rules {
+		res = append(res, (*IDSRule)(rules[i]))
+	}
+	return res
+}
+
+func IDSRulesToComparable(rules []model.IdsRule) []Comparable {
+	res := make([]Comparable, 0, len(rules))
+	for i := range rules {
+		res = append(res, (*IDSRule)(&rules[i]))
+	}
+	return res
+}
+
+func ComparableToIDSRules(rules []Comparable) []model.IdsRule {
+	res := make([]model.IdsRule, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, (model.IdsRule)(*(rule.(*IDSRule))))
+	}
+	return res
+}
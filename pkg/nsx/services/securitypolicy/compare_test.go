@@ -146,6 +146,50 @@ func TestRulesEqual(t *testing.T) {
 			expectedResult1: []model.Rule{},
 			expectedResult2: []model.Rule{},
 		},
+		{
+			name: "rule-with-changed-tag",
+			inputRule1: []model.Rule{
+				{
+					Id:  &ruleID0,
+					Tag: String("app-v1"),
+				},
+			},
+			inputRule2: []model.Rule{
+				{
+					Id:  &ruleID0,
+					Tag: String("app-v2"),
+				},
+			},
+			expectedResult1: []model.Rule{
+				{
+					Id:  &ruleID0,
+					Tag: String("app-v2"),
+				},
+			},
+			expectedResult2: []model.Rule{},
+		},
+		{
+			name: "rule-with-changed-logged",
+			inputRule1: []model.Rule{
+				{
+					Id:     &ruleID0,
+					Logged: common.Bool(false),
+				},
+			},
+			inputRule2: []model.Rule{
+				{
+					Id:     &ruleID0,
+					Logged: common.Bool(true),
+				},
+			},
+			expectedResult1: []model.Rule{
+				{
+					Id:     &ruleID0,
+					Logged: common.Bool(true),
+				},
+			},
+			expectedResult2: []model.Rule{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,150 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// NSX matches the Layer-7 attributes of the traffic, e.g. the App IDs and the domain names, by the context
+// profiles. The App IDs and the FQDN destinations of a rule are built into a context profile attached to the
+// NSX rules expanded from it.
+
+func hasContextProfile(rule *v1alpha1.SecurityPolicyRule) bool {
+	return len(rule.AppIDs) > 0 || len(ruleFQDNs(rule)) > 0
+}
+
+// ruleProfileAttributes returns the attributes of the context profile of the rule.
+func ruleProfileAttributes(rule *v1alpha1.SecurityPolicyRule) []model.PolicyAttributes {
+	var attributes []model.PolicyAttributes
+	if appIDs := ruleAppIDs(rule); len(appIDs) > 0 {
+		attributes = append(attributes, model.PolicyAttributes{
+			Key:      String(model.PolicyAttributes_KEY_APP_ID),
+			Datatype: String(model.PolicyAttributes_DATATYPE_STRING),
+			Value:    appIDs,
+		})
+	}
+	if fqdns := ruleFQDNs(rule); len(fqdns) > 0 {
+		attributes = append(attributes, model.PolicyAttributes{
+			Key:      String(model.PolicyAttributes_KEY_DOMAIN_NAME),
+			Datatype: String(model.PolicyAttributes_DATATYPE_STRING),
+			Value:    fqdns,
+		})
+	}
+	return attributes
+}
+
+// buildRuleContextProfile returns the rule the NSX rules are built from, i.e. a copy of the rule without the
+// FQDN destinations, and the path of the context profile of the rule, which is empty if the rule has neither
+// App ID nor FQDN destination.
+func (service *SecurityPolicyService) buildRuleContextProfile(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, createdFor string) (*v1alpha1.SecurityPolicyRule, string, error) {
+	if !hasContextProfile(rule) {
+		return rule, "", nil
+	}
+	if err := ValidateFQDN(obj); err != nil {
+		return nil, "", err
+	}
+	if err := ValidateAppIDs(obj); err != nil {
+		return nil, "", err
+	}
+	if isVpcEnabled(service) {
+		return nil, "", errors.New("the App IDs and the FQDN destinations are not supported in VPC mode")
+	}
+	return stripFQDNDestinations(rule), service.buildContextProfilePath(service.buildContextProfileID(obj, rule, ruleIdx, createdFor)), nil
+}
+
+// buildContextProfileID builds the ID from the rule without the FQDN destinations like the NSX rules, so the
+// context profile is updated in place when the FQDNs are changed.
+func (service *SecurityPolicyService) buildContextProfileID(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, createdFor string) string {
+	return fmt.Sprintf("%s_profile", service.buildRuleID(obj, stripFQDNDestinations(rule), ruleIdx, createdFor))
+}
+
+func (service *SecurityPolicyService) buildContextProfilePath(id string) string {
+	return fmt.Sprintf("/infra/context-profiles/%s", id)
+}
+
+// buildContextProfiles builds the context profiles of the rules with the App IDs or the FQDN destinations.
+func (service *SecurityPolicyService) buildContextProfiles(obj *v1alpha1.SecurityPolicy, createdFor string) []model.PolicyContextProfile {
+	var profiles []model.PolicyContextProfile
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		if !hasContextProfile(rule) {
+			continue
+		}
+		profiles = append(profiles, model.PolicyContextProfile{
+			Id:          String(service.buildContextProfileID(obj, rule, ruleIdx, createdFor)),
+			DisplayName: String(util.GenerateTruncName(common.MaxNameLength, obj.Name, "", fmt.Sprintf("rule-%d", ruleIdx), "profile", "")),
+			Attributes:  ruleProfileAttributes(rule),
+			Tags:        service.buildBasicTags(obj, createdFor),
+		})
+	}
+	return profiles
+}
+
+// diffContextProfiles compares the expected context profiles of a CR with the ones in store. The changed
+// profiles are patched before the rules referring to them, the stale ones after the rules are updated.
+func (service *SecurityPolicyService) diffContextProfiles(indexScope string, uid types.UID, profiles []model.PolicyContextProfile) (stale []model.PolicyContextProfile, changed []model.PolicyContextProfile) {
+	if service.contextProfileStore == nil {
+		return nil, nil
+	}
+	existingProfiles := service.contextProfileStore.GetByIndex(indexScope, string(uid))
+	changedProfiles, staleProfiles := common.CompareResources(ContextProfilesPtrToComparable(existingProfiles), ContextProfilesToComparable(profiles))
+	stale = ComparableToContextProfiles(staleProfiles)
+	for i := range stale {
+		stale[i].MarkedForDelete = &MarkedForDelete
+	}
+	return stale, ComparableToContextProfiles(changedProfiles)
+}
+
+// patchContextProfiles patches the context profiles by the hierarchical API, then updates the store.
+func (service *SecurityPolicyService) patchContextProfiles(profiles []model.PolicyContextProfile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	var children []*data.StructValue
+	for i := range profiles {
+		profile := profiles[i]
+		profile.ResourceType = &common.ResourceTypeContextProfile // InfraClient need this field to identify the resource type
+		childProfile := model.ChildPolicyContextProfile{
+			Id:                   profile.Id,
+			MarkedForDelete:      profile.MarkedForDelete,
+			ResourceType:         common.ResourceTypeChildContextProfile,
+			PolicyContextProfile: &profile,
+		}
+		dataValue, errs := NewConverter().ConvertToVapi(childProfile, model.ChildPolicyContextProfileBindingType())
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		children = append(children, dataValue.(*data.StructValue))
+	}
+	infra, err := service.wrapInfra(children)
+	if err != nil {
+		return err
+	}
+	if err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam); err != nil {
+		log.Error(err, "failed to patch context profiles")
+		return err
+	}
+	if err = service.contextProfileStore.Apply(&profiles); err != nil {
+		log.Error(err, "failed to apply store", "contextProfiles", profiles)
+		return err
+	}
+	log.Info("successfully patched nsx context profiles", "contextProfiles", profiles)
+	return nil
+}
+
+// deleteContextProfiles deletes the context profiles of a CR.
+func (service *SecurityPolicyService) deleteContextProfiles(indexScope string, uid types.UID) error {
+	stale, _ := service.diffContextProfiles(indexScope, uid, nil)
+	return service.patchContextProfiles(stale)
+}
@@ -0,0 +1,131 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// The default deny of a Namespace annotated with nsx.vmware.com/default_deny is realized as an internal
+// SecurityPolicy applied to all the Pods of the Namespace, dropping the traffic which is not allowed by the
+// SecurityPolicies and the NetworkPolicies. It's after the NetworkPolicies and before the
+// BaselineAdminNetworkPolicies. The internal UID is the UID of the Namespace followed by "_default_deny".
+
+const (
+	DefaultDenyIngress = "ingress"
+	DefaultDenyEgress  = "egress"
+	DefaultDenyAll     = "all"
+)
+
+// ParseDefaultDeny returns the directions denied by the value of the nsx.vmware.com/default_deny annotation.
+func ParseDefaultDeny(value string) (ingress bool, egress bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case DefaultDenyIngress:
+		return true, false, nil
+	case DefaultDenyEgress:
+		return false, true, nil
+	case DefaultDenyAll:
+		return true, true, nil
+	default:
+		return false, false, fmt.Errorf("invalid %s %q, it must be one of %s, %s and %s", common.AnnotationDefaultDeny,
+			value, DefaultDenyIngress, DefaultDenyEgress, DefaultDenyAll)
+	}
+}
+
+func (service *SecurityPolicyService) BuildNamespaceDefaultDenyID(uid types.UID) string {
+	return fmt.Sprintf("%s_default_deny", uid)
+}
+
+// NamespaceDefaultDenyUIDOf returns the UID of the Namespace the internal SecurityPolicy denies the traffic of.
+func NamespaceDefaultDenyUIDOf(internalUID string) string {
+	return strings.SplitN(internalUID, "_", 2)[0]
+}
+
+func (service *SecurityPolicyService) convertNamespaceDefaultDenyToInternalSecurityPolicy(ns *v1.Namespace, ingress, egress bool) (*v1alpha1.SecurityPolicy, error) {
+	actionDrop := v1alpha1.RuleActionDrop
+	directionIn := v1alpha1.RuleDirectionIn
+	directionOut := v1alpha1.RuleDirectionOut
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns.Name,
+			Name:      "default-deny",
+			UID:       types.UID(service.BuildNamespaceDefaultDenyID(ns.UID)),
+		},
+		Spec: v1alpha1.SecurityPolicySpec{
+			Priority:  common.PriorityNamespaceDefaultDeny,
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{}}},
+		},
+	}
+	if ingress {
+		// the health checks of the load balancer are allowed to the Pods behind LoadBalancer Services
+		if isVpcEnabled(service) {
+			rule, err := service.buildHealthProbeRule(sp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build health probe rule: %w", err)
+			}
+			if rule != nil {
+				sp.Spec.Rules = append(sp.Spec.Rules, *rule)
+			}
+		}
+		sp.Spec.Rules = append(sp.Spec.Rules, v1alpha1.SecurityPolicyRule{
+			Action:    &actionDrop,
+			Direction: &directionIn,
+			Name:      "ingress-default-deny",
+		})
+	}
+	if egress {
+		sp.Spec.Rules = append(sp.Spec.Rules, v1alpha1.SecurityPolicyRule{
+			Action:    &actionDrop,
+			Direction: &directionOut,
+			Name:      "egress-default-deny",
+		})
+	}
+	log.V(1).Info("converted namespace default deny to security policy", "namespace", ns.Name, "securityPolicy", sp)
+	return sp, nil
+}
+
+// CreateOrUpdateNamespaceDefaultDeny realizes the default deny of the directions in the Namespace.
+func (service *SecurityPolicyService) CreateOrUpdateNamespaceDefaultDeny(ns *v1.Namespace, ingress, egress bool) error {
+	if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+		log.Info("no DFW license, skip creating namespace default deny.")
+		return nsxutil.RestrictionError{Desc: "no DFW license"}
+	}
+	if !ingress && !egress {
+		return service.DeleteNamespaceDefaultDeny(ns.UID)
+	}
+	internalSecurityPolicy, err := service.convertNamespaceDefaultDenyToInternalSecurityPolicy(ns, ingress, egress)
+	if err != nil {
+		return err
+	}
+	return service.createOrUpdateSecurityPolicy(internalSecurityPolicy, common.ResourceTypeNamespaceDefaultDeny)
+}
+
+// DeleteNamespaceDefaultDeny deletes the default deny of the Namespace, it's a no-op if there is none.
+func (service *SecurityPolicyService) DeleteNamespaceDefaultDeny(uid types.UID) error {
+	internalUID := service.BuildNamespaceDefaultDenyID(uid)
+	if !service.ListNamespaceDefaultDenyID().Has(internalUID) {
+		return nil
+	}
+	return service.deleteSecurityPolicy(types.UID(internalUID), false, common.ResourceTypeNamespaceDefaultDeny)
+}
+
+// ListNamespaceDefaultDenyID returns the UIDs of the internal SecurityPolicies denying the traffic of the
+// Namespaces.
+func (service *SecurityPolicyService) ListNamespaceDefaultDenyID() sets.Set[string] {
+	groupSet := service.groupStore.ListIndexFuncValues(common.TagScopeDefaultDenyNamespaceUID)
+	shareSet := service.shareStore.ListIndexFuncValues(common.TagScopeDefaultDenyNamespaceUID)
+	policySet := service.securityPolicyStore.ListIndexFuncValues(common.TagScopeDefaultDenyNamespaceUID)
+
+	return groupSet.Union(policySet).Union(shareSet)
+}
@@ -0,0 +1,78 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestParseDefaultDeny(t *testing.T) {
+	for value, expected := range map[string][2]bool{
+		"ingress": {true, false},
+		"Egress":  {false, true},
+		" all ":   {true, true},
+	} {
+		ingress, egress, err := ParseDefaultDeny(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, [2]bool{ingress, egress}, value)
+	}
+	_, _, err := ParseDefaultDeny("true")
+	assert.EqualError(t, err, `invalid nsx.vmware.com/default_deny "true", it must be one of ingress, egress and all`)
+}
+
+func TestConvertNamespaceDefaultDenyToInternalSecurityPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", UID: "ns-uid"}}
+	sp, err := service.convertNamespaceDefaultDenyToInternalSecurityPolicy(ns, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", sp.Namespace)
+	assert.Equal(t, "ns-uid_default_deny", string(sp.UID))
+	assert.Equal(t, "ns-uid", NamespaceDefaultDenyUIDOf(string(sp.UID)))
+	assert.Equal(t, common.PriorityNamespaceDefaultDeny, sp.Spec.Priority)
+	assert.Equal(t, &metav1.LabelSelector{}, sp.Spec.AppliedTo[0].PodSelector)
+	assert.Len(t, sp.Spec.Rules, 2)
+	for i, direction := range []v1alpha1.RuleDirection{v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionOut} {
+		assert.Equal(t, v1alpha1.RuleActionDrop, *sp.Spec.Rules[i].Action)
+		assert.Equal(t, direction, *sp.Spec.Rules[i].Direction)
+		assert.Empty(t, sp.Spec.Rules[i].Sources)
+		assert.Empty(t, sp.Spec.Rules[i].Destinations)
+	}
+
+	// the NSX resources are told apart from the ones of the SecurityPolicies
+	nsxSecurityPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeNamespaceDefaultDeny)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(*nsxSecurityPolicy.Id, common.NamespaceDefaultDenyPrefix))
+	assert.Equal(t, int64(common.PriorityNamespaceDefaultDeny), *nsxSecurityPolicy.SequenceNumber)
+	found := false
+	for _, tag := range nsxSecurityPolicy.Tags {
+		if *tag.Scope == common.TagScopeDefaultDenyNamespaceUID {
+			found = true
+			assert.Equal(t, string(sp.UID), *tag.Tag)
+		}
+	}
+	assert.True(t, found)
+
+	sp, err = service.convertNamespaceDefaultDenyToInternalSecurityPolicy(ns, false, true)
+	assert.NoError(t, err)
+	assert.Len(t, sp.Spec.Rules, 1)
+	assert.Equal(t, "egress-default-deny", sp.Spec.Rules[0].Name)
+}
@@ -0,0 +1,55 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// SyncDiff summarizes the NSX resources patched by a successful sync of a SecurityPolicy CR.
+type SyncDiff struct {
+	SecurityPolicyChanged bool
+	AddedRules            int
+	ChangedRules          int
+	RemovedRules          int
+	TouchedGroups         int
+}
+
+func newSyncDiff(isChanged bool, existingRules []*model.Rule, changedRules, staleRules []model.Rule, touchedGroups int) *SyncDiff {
+	existingIDs := sets.New[string]()
+	for _, rule := range existingRules {
+		existingIDs.Insert(*rule.Id)
+	}
+	diff := &SyncDiff{SecurityPolicyChanged: isChanged, RemovedRules: len(staleRules), TouchedGroups: touchedGroups}
+	for _, rule := range changedRules {
+		if existingIDs.Has(*rule.Id) {
+			diff.ChangedRules++
+		} else {
+			diff.AddedRules++
+		}
+	}
+	return diff
+}
+
+func (d *SyncDiff) String() string {
+	policy := "unchanged"
+	if d.SecurityPolicyChanged {
+		policy = "changed"
+	}
+	return fmt.Sprintf("policy %s, rules %d added/%d changed/%d removed, %d groups touched",
+		policy, d.AddedRules, d.ChangedRules, d.RemovedRules, d.TouchedGroups)
+}
+
+// TakeSyncDiff returns the diff of the last successful sync of the SecurityPolicy CR which changed the NSX
+// resources, and forgets it so it's reported once. It returns nil if nothing has changed since.
+func (service *SecurityPolicyService) TakeSyncDiff(uid types.UID) *SyncDiff {
+	if diff, ok := service.syncDiffs.LoadAndDelete(uid); ok {
+		return diff.(*SyncDiff)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncDiff(t *testing.T) {
+	rule := func(id string) model.Rule {
+		return model.Rule{Id: &id}
+	}
+	rule1, rule2 := rule("rule1"), rule("rule2")
+	existingRules := []*model.Rule{&rule1, &rule2}
+	diff := newSyncDiff(false, existingRules, []model.Rule{rule("rule1"), rule("rule3"), rule("rule4")}, []model.Rule{rule("rule2")}, 2)
+	assert.Equal(t, &SyncDiff{AddedRules: 2, ChangedRules: 1, RemovedRules: 1, TouchedGroups: 2}, diff)
+	assert.Equal(t, "policy unchanged, rules 2 added/1 changed/1 removed, 2 groups touched", diff.String())
+	diff.SecurityPolicyChanged = true
+	assert.Equal(t, "policy changed, rules 2 added/1 changed/1 removed, 2 groups touched", diff.String())
+
+	service := &SecurityPolicyService{}
+	uid := types.UID("uid1")
+	assert.Nil(t, service.TakeSyncDiff(uid))
+	service.syncDiffs.Store(uid, diff)
+	assert.Equal(t, diff, service.TakeSyncDiff(uid))
+	// the diff is reported once
+	assert.Nil(t, service.TakeSyncDiff(uid))
+}
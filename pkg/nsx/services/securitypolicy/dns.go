@@ -0,0 +1,99 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// DNSRuleName is the name of the rule injected into the SecurityPolicy with allowDNS.
+const DNSRuleName = "allow-dns"
+
+// ValidateAllowDNS rejects the SecurityPolicy with allowDNS but without the policy level 'Applied To',
+// the DNS rule would allow all the workloads of the cluster otherwise.
+func ValidateAllowDNS(obj *v1alpha1.SecurityPolicy) error {
+	if obj.Spec.AllowDNS && len(obj.Spec.AppliedTo) == 0 {
+		return errors.New("spec.allowDNS requires spec.appliedTo")
+	}
+	return nil
+}
+
+// DNSService returns the namespaced name of the cluster DNS Service, the default one if the config is not loaded.
+func (service *SecurityPolicyService) DNSService() types.NamespacedName {
+	var k8sConfig *config.K8sConfig
+	if service.NSXConfig != nil {
+		k8sConfig = service.NSXConfig.K8sConfig
+	}
+	namespace, name := k8sConfig.GetDNSService()
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+// buildDNSRule builds the egress rule allowing the cluster IPs and the ports of the cluster DNS Service.
+func (service *SecurityPolicyService) buildDNSRule() (*v1alpha1.SecurityPolicyRule, error) {
+	key := service.DNSService()
+	svc := &v1.Service{}
+	if err := service.Client.Get(context.TODO(), key, svc); err != nil {
+		return nil, fmt.Errorf("failed to get cluster DNS service %s: %w", key, err)
+	}
+	clusterIPs := svc.Spec.ClusterIPs
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	peer := v1alpha1.SecurityPolicyPeer{}
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			continue
+		}
+		if ip.To4() != nil {
+			peer.IPBlocks = append(peer.IPBlocks, v1alpha1.IPBlock{CIDR: clusterIP + "/32"})
+		} else {
+			peer.IPBlocks = append(peer.IPBlocks, v1alpha1.IPBlock{CIDR: clusterIP + "/128"})
+		}
+	}
+	if len(peer.IPBlocks) == 0 {
+		return nil, fmt.Errorf("cluster DNS service %s has no cluster IP", key)
+	}
+	var ports []v1alpha1.SecurityPolicyPort
+	for _, port := range svc.Spec.Ports {
+		ports = append(ports, v1alpha1.SecurityPolicyPort{Protocol: port.Protocol, Port: intstr.FromInt(int(port.Port))})
+	}
+	action, direction := v1alpha1.RuleActionAllow, v1alpha1.RuleDirectionOut
+	return &v1alpha1.SecurityPolicyRule{
+		Name:         DNSRuleName,
+		Action:       &action,
+		Direction:    &direction,
+		Destinations: []v1alpha1.SecurityPolicyPeer{peer},
+		Ports:        ports,
+	}, nil
+}
+
+// withDNSRule returns a copy of the SecurityPolicy with the DNS rule appended to the rules if allowDNS is set,
+// so the rule is built as the other rules. The rules are returned as is otherwise.
+func (service *SecurityPolicyService) withDNSRule(obj *v1alpha1.SecurityPolicy, createdFor string) (*v1alpha1.SecurityPolicy, error) {
+	if !obj.Spec.AllowDNS || createdFor != common.ResourceTypeSecurityPolicy {
+		return obj, nil
+	}
+	if err := ValidateAllowDNS(obj); err != nil {
+		return nil, err
+	}
+	rule, err := service.buildDNSRule()
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	obj.Spec.Rules = append(obj.Spec.Rules, *rule)
+	return obj, nil
+}
@@ -0,0 +1,83 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_AllowDNS(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	dnsService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-dns"},
+		Spec: v1.ServiceSpec{
+			ClusterIP:  "10.96.0.10",
+			ClusterIPs: []string{"10.96.0.10", "fd00::a"},
+			Ports: []v1.ServicePort{
+				{Name: "dns", Protocol: v1.ProtocolUDP, Port: 53},
+				{Name: "dns-tcp", Protocol: v1.ProtocolTCP, Port: 53},
+			},
+		},
+	}
+	service := fakeService()
+	service.Client = fake.NewClientBuilder().WithObjects(dnsService).Build()
+
+	policy, _, _, err := service.buildSecurityPolicy(&spWithPodSelector, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.AllowDNS = true
+	dnsPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+
+	// the DNS rule is enforced first and the rules of the spec are kept
+	assert.Equal(t, len(policy.Rules)+1, len(dnsPolicy.Rules))
+	dnsRules := 0
+	for i, rule := range dnsPolicy.Rules {
+		if *rule.SequenceNumber == 0 {
+			dnsRules++
+			assert.Equal(t, "OUT", *rule.Direction)
+			assert.Equal(t, "ALLOW", *rule.Action)
+			assert.Len(t, rule.ServiceEntries, 2)
+			continue
+		}
+		userRule := policy.Rules[i-dnsRules]
+		assert.Equal(t, *userRule.Id, *rule.Id)
+		assert.Equal(t, *userRule.SequenceNumber+1, *rule.SequenceNumber)
+	}
+	assert.Equal(t, 1, dnsRules)
+	budgets, _ := service.ruleBudgets.Load(sp.UID)
+	assert.Len(t, budgets, len(sp.Spec.Rules))
+
+	// the DNS rule follows the cluster IPs of the service
+	rule, err := service.buildDNSRule()
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.IPBlock{{CIDR: "10.96.0.10/32"}, {CIDR: "fd00::a/128"}}, rule.Destinations[0].IPBlocks)
+	assert.Len(t, rule.Ports, 2)
+
+	// the policy level appliedTo is required
+	sp.Spec.AppliedTo = nil
+	_, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "spec.allowDNS requires spec.appliedTo")
+
+	service.Client = fake.NewClientBuilder().Build()
+	_, err = service.buildDNSRule()
+	assert.ErrorContains(t, err, "failed to get cluster DNS service kube-system/kube-dns")
+}
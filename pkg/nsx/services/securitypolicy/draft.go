@@ -0,0 +1,89 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// draftStager serializes the staging of the changes in the DFW draft and its publication, so a change staged
+// while the draft is published is not deleted with it.
+type draftStager struct {
+	mu sync.Mutex
+	// staged is the count of the changes staged since the last publication
+	staged int
+	// recovered is set once the draft left by the former nsx-operator has been looked for
+	recovered bool
+}
+
+// DraftEnabled returns whether the changes of the SecurityPolicies are staged in an NSX DFW draft instead of
+// being patched directly, it's only supported without VPC.
+func (service *SecurityPolicyService) DraftEnabled() bool {
+	return !isVpcEnabled(service) && service.NSXConfig.NsxConfig != nil && service.NSXConfig.DFWDraft
+}
+
+// DraftID returns the ID of the manual DFW draft the changes are staged in, there is one per cluster.
+func (service *SecurityPolicyService) DraftID() string {
+	return "nsx-operator_" + getCluster(service)
+}
+
+// stageInDraft stages the hierarchical changes in the DFW draft, the draft is created against the published
+// configuration by the first change after a publication.
+func (service *SecurityPolicyService) stageInDraft(infra *model.Infra) error {
+	service.draft.mu.Lock()
+	defer service.draft.mu.Unlock()
+	draftID := service.DraftID()
+	if err := service.NSXClient.DraftsClient.Patch(draftID, model.PolicyDraft{Children: infra.Children}); err != nil {
+		return fmt.Errorf("failed to stage changes in DFW draft %s: %w", draftID, err)
+	}
+	service.draft.staged++
+	log.V(1).Info("staged changes in DFW draft", "draft", draftID, "staged", service.draft.staged)
+	return nil
+}
+
+// PendingDraftChanges returns the count of the changes staged in the DFW draft since the last publication.
+func (service *SecurityPolicyService) PendingDraftChanges() int {
+	service.draft.mu.Lock()
+	defer service.draft.mu.Unlock()
+	return service.draft.staged
+}
+
+// PublishDraft publishes the DFW draft onto the NSX configuration and deletes it, so the following changes are
+// staged against the published configuration. It returns the count of the changes published, nothing is
+// published if no change is staged, unless a draft is left by the former nsx-operator.
+func (service *SecurityPolicyService) PublishDraft() (int, error) {
+	service.draft.mu.Lock()
+	defer service.draft.mu.Unlock()
+	draftID := service.DraftID()
+	if !service.draft.recovered {
+		_, err := service.NSXClient.DraftsClient.Get(draftID)
+		if err != nil && !nsxutil.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to get DFW draft %s: %w", draftID, err)
+		}
+		if err == nil && service.draft.staged == 0 {
+			// the count of the changes staged before the restart is unknown
+			service.draft.staged = 1
+		}
+		service.draft.recovered = true
+	}
+	if service.draft.staged == 0 {
+		return 0, nil
+	}
+	if err := service.NSXClient.DraftsClient.Publish(draftID, model.Infra{}); err != nil {
+		return 0, fmt.Errorf("failed to publish DFW draft %s: %w", draftID, err)
+	}
+	published := service.draft.staged
+	service.draft.staged = 0
+	if err := service.NSXClient.DraftsClient.Delete(draftID); err != nil && !nsxutil.IsNotFound(err) {
+		// the published changes are kept in the draft, staging the following ones on it is harmless
+		log.Error(err, "failed to delete published DFW draft", "draft", draftID)
+	}
+	log.Info("published DFW draft", "draft", draftID, "changes", published)
+	return published, nil
+}
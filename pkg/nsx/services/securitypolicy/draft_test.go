@@ -0,0 +1,104 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vapierrors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+type fakeDraftsClient struct {
+	infra.DraftsClient
+	drafts     map[string]model.PolicyDraft
+	published  []string
+	publishErr error
+}
+
+func (c *fakeDraftsClient) Get(draftID string) (model.PolicyDraft, error) {
+	draft, ok := c.drafts[draftID]
+	if !ok {
+		return model.PolicyDraft{}, vapierrors.NotFound{}
+	}
+	return draft, nil
+}
+
+func (c *fakeDraftsClient) Patch(draftID string, draft model.PolicyDraft) error {
+	existing := c.drafts[draftID]
+	existing.Children = append(existing.Children, draft.Children...)
+	c.drafts[draftID] = existing
+	return nil
+}
+
+func (c *fakeDraftsClient) Publish(draftID string, _ model.Infra) error {
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+	if _, ok := c.drafts[draftID]; !ok {
+		return vapierrors.NotFound{}
+	}
+	c.published = append(c.published, draftID)
+	return nil
+}
+
+func (c *fakeDraftsClient) Delete(draftID string) error {
+	delete(c.drafts, draftID)
+	return nil
+}
+
+func TestSecurityPolicyService_Draft(t *testing.T) {
+	service := fakeService()
+	service.NSXConfig.NsxConfig = &config.NsxConfig{}
+	draftsClient := &fakeDraftsClient{drafts: map[string]model.PolicyDraft{}}
+	service.NSXClient.DraftsClient = draftsClient
+	draftID := "nsx-operator_k8scl-one:test"
+	assert.Equal(t, draftID, service.DraftID())
+
+	// the changes are patched directly unless dfw_draft is enabled
+	assert.False(t, service.DraftEnabled())
+	service.NSXConfig.DFWDraft = true
+	assert.True(t, service.DraftEnabled())
+	backend, err := service.getBackend("ns1", false)
+	assert.NoError(t, err)
+
+	// a draft left by the former nsx-operator is published
+	draftsClient.drafts[draftID] = model.PolicyDraft{}
+	published, err := service.PublishDraft()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Empty(t, draftsClient.drafts)
+
+	// nothing is published without a staged change
+	published, err = service.PublishDraft()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Len(t, draftsClient.published, 1)
+
+	// the changes are staged in the draft instead of being verified
+	id := "sp1"
+	assert.NoError(t, backend.Realize("ns1", &model.SecurityPolicy{Id: &id}, nil, nil, nil))
+	groupID := "g1"
+	assert.NoError(t, backend.PatchGroup("ns1", &model.Group{Id: &groupID}))
+	assert.Equal(t, 2, service.PendingDraftChanges())
+	assert.Len(t, draftsClient.drafts[draftID].Children, 2)
+
+	// the changes are kept if the publication fails
+	draftsClient.publishErr = errors.New("ERROR")
+	_, err = service.PublishDraft()
+	assert.ErrorContains(t, err, "failed to publish DFW draft")
+	assert.Equal(t, 2, service.PendingDraftChanges())
+
+	draftsClient.publishErr = nil
+	published, err = service.PublishDraft()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, 0, service.PendingDraftChanges())
+	assert.Empty(t, draftsClient.drafts)
+}
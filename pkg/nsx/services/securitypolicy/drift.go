@@ -0,0 +1,253 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// The stores only reflect the writes of nsx-operator, so the NSX SecurityPolicies, rules and groups edited
+// directly in NSX Manager are not noticed by the reconciles, which compare the built objects with the stores.
+// The drift is detected by reading the objects back from NSX and comparing them with the stores, and it's
+// remediated by forgetting the drifted objects in the stores, so the next reconcile patches them again.
+
+// Drift is the difference between the NSX objects of a SecurityPolicy in the stores and the ones in NSX.
+type Drift struct {
+	// Modified are the paths of the objects changed in NSX.
+	Modified []string
+	// Missing are the paths of the objects deleted in NSX.
+	Missing []string
+	// Unexpected are the paths of the rules added to the NSX SecurityPolicy in NSX, they're not remediated
+	// since nsx-operator doesn't own them.
+	Unexpected []string
+
+	policy *model.SecurityPolicy
+	rules  []*model.Rule
+	groups []*model.Group
+}
+
+// Empty returns whether there is no drift.
+func (d *Drift) Empty() bool {
+	return d == nil || len(d.Modified) == 0 && len(d.Missing) == 0 && len(d.Unexpected) == 0
+}
+
+// Remediable returns whether any object drifted is owned by nsx-operator, and patched by the next reconcile
+// once it's forgotten.
+func (d *Drift) Remediable() bool {
+	return d != nil && (d.policy != nil || len(d.rules) > 0 || len(d.groups) > 0)
+}
+
+func (d *Drift) String() string {
+	var parts []string
+	for _, part := range []struct {
+		name  string
+		paths []string
+	}{{"modified", d.Modified}, {"missing", d.Missing}, {"unexpected", d.Unexpected}} {
+		if len(part.paths) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: [%s]", part.name, strings.Join(part.paths, ", ")))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// driftReader reads the NSX objects of the SecurityPolicies in a namespace.
+type driftReader struct {
+	getPolicy func(id string) (model.SecurityPolicy, error)
+	listRules func(policyID string, cursor *string) (model.RuleListResult, error)
+	getGroup  func(id string) (model.Group, error)
+}
+
+func (service *SecurityPolicyService) driftReaderFor(namespace string) (*driftReader, error) {
+	if isVpcEnabled(service) {
+		vpcInfo, err := service.getVpcInfo(namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &driftReader{
+			getPolicy: func(id string) (model.SecurityPolicy, error) {
+				return service.NSXClient.VPCSecurityClient.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, id)
+			},
+			listRules: func(policyID string, cursor *string) (model.RuleListResult, error) {
+				return service.NSXClient.VPCRuleClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, policyID, cursor, nil, nil, nil, nil, nil)
+			},
+			getGroup: func(id string) (model.Group, error) {
+				return service.NSXClient.VpcGroupClient.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, id)
+			},
+		}, nil
+	}
+	domain := getDomain(service)
+	return &driftReader{
+		getPolicy: func(id string) (model.SecurityPolicy, error) {
+			return service.NSXClient.SecurityClient.Get(domain, id)
+		},
+		listRules: func(policyID string, cursor *string) (model.RuleListResult, error) {
+			return service.NSXClient.RuleClient.List(domain, policyID, cursor, nil, nil, nil, nil, nil)
+		},
+		getGroup: func(id string) (model.Group, error) {
+			return service.NSXClient.GroupClient.Get(domain, id)
+		},
+	}, nil
+}
+
+// DetectDrift compares the NSX SecurityPolicy, rules and groups of the SecurityPolicy CR of the uid in the
+// stores with the ones in NSX. It returns nil if nothing of the uid is in the stores, or the changes are
+// staged in the DFW draft, which NSX doesn't store until the draft is published.
+func (service *SecurityPolicyService) DetectDrift(uid types.UID) (*Drift, error) {
+	if service.DraftEnabled() {
+		return nil, nil
+	}
+	securityPolicyStore, ruleStore, groupStore, _, _ := service.getStores()
+	indexScope := common.TagValueScopeSecurityPolicyUID
+	policies := securityPolicyStore.GetByIndex(indexScope, string(uid))
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	stored := policies[0]
+	reader, err := service.driftReaderFor(namespaceOfTags(stored.Tags))
+	if err != nil {
+		return nil, err
+	}
+	return detectDrift(reader, stored, ruleStore.GetByIndex(indexScope, string(uid)), groupStore.GetByIndex(indexScope, string(uid)))
+}
+
+func detectDrift(reader *driftReader, stored *model.SecurityPolicy, storedRules []*model.Rule, storedGroups []*model.Group) (*Drift, error) {
+	drift := &Drift{}
+	policy, err := reader.getPolicy(*stored.Id)
+	if nsxutil.IsNotFound(err) || err == nil && policy.MarkedForDelete != nil && *policy.MarkedForDelete {
+		// the rules are deleted with the SecurityPolicy
+		drift.Missing = append(drift.Missing, "SecurityPolicy/"+*stored.Id)
+		drift.policy = stored
+		drift.rules = storedRules
+	} else if err != nil {
+		return nil, err
+	} else {
+		if common.CompareResource(SecurityPolicyPtrToComparable(normalizePolicy(stored)), SecurityPolicyPtrToComparable(normalizePolicy(&policy))) {
+			drift.Modified = append(drift.Modified, "SecurityPolicy/"+*stored.Id)
+			drift.policy = stored
+		}
+		if err := detectRuleDrift(reader, drift, *stored.Id, storedRules); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range storedGroups {
+		nsxGroup, err := reader.getGroup(*group.Id)
+		if nsxutil.IsNotFound(err) || err == nil && nsxGroup.MarkedForDelete != nil && *nsxGroup.MarkedForDelete {
+			drift.Missing = append(drift.Missing, "Group/"+*group.Id)
+			drift.groups = append(drift.groups, group)
+		} else if err != nil {
+			return nil, err
+		} else if common.CompareResource((*Group)(group), (*Group)(&nsxGroup)) {
+			drift.Modified = append(drift.Modified, "Group/"+*group.Id)
+			drift.groups = append(drift.groups, group)
+		}
+	}
+	return drift, nil
+}
+
+func detectRuleDrift(reader *driftReader, drift *Drift, policyID string, storedRules []*model.Rule) error {
+	nsxRules := map[string]*model.Rule{}
+	var cursor *string
+	for {
+		result, err := reader.listRules(policyID, cursor)
+		if err != nil {
+			return err
+		}
+		for i := range result.Results {
+			rule := &result.Results[i]
+			if rule.Id != nil && (rule.MarkedForDelete == nil || !*rule.MarkedForDelete) {
+				nsxRules[*rule.Id] = rule
+			}
+		}
+		if result.Cursor == nil || *result.Cursor == "" || len(result.Results) == 0 {
+			break
+		}
+		cursor = result.Cursor
+	}
+	for _, rule := range storedRules {
+		nsxRule, ok := nsxRules[*rule.Id]
+		delete(nsxRules, *rule.Id)
+		if !ok {
+			drift.Missing = append(drift.Missing, "Rule/"+*rule.Id)
+			drift.rules = append(drift.rules, rule)
+		} else if common.CompareResource((*Rule)(normalizeRule(rule)), (*Rule)(normalizeRule(nsxRule))) {
+			drift.Modified = append(drift.Modified, "Rule/"+*rule.Id)
+			drift.rules = append(drift.rules, rule)
+		}
+	}
+	for _, id := range sets.List(sets.KeySet(nsxRules)) {
+		drift.Unexpected = append(drift.Unexpected, "Rule/"+id)
+	}
+	return nil
+}
+
+// anyToNil returns nil for ANY, which NSX returns for the groups, services and scope not set in the patch.
+func anyToNil(values []string) []string {
+	if len(values) == 1 && values[0] == "ANY" {
+		return nil
+	}
+	return values
+}
+
+// normalizePolicy returns a copy of the SecurityPolicy whose fields defaulted by NSX are unset.
+func normalizePolicy(sp *model.SecurityPolicy) *model.SecurityPolicy {
+	normalized := *sp
+	normalized.Scope = anyToNil(normalized.Scope)
+	return &normalized
+}
+
+// normalizeRule returns a copy of the rule whose fields defaulted by NSX are unset.
+func normalizeRule(rule *model.Rule) *model.Rule {
+	normalized := *rule
+	normalized.SourceGroups = anyToNil(normalized.SourceGroups)
+	normalized.DestinationGroups = anyToNil(normalized.DestinationGroups)
+	normalized.Services = anyToNil(normalized.Services)
+	normalized.Scope = anyToNil(normalized.Scope)
+	if normalized.Logged != nil && !*normalized.Logged {
+		normalized.Logged = nil
+	}
+	return &normalized
+}
+
+// RemediateDrift forgets the drifted NSX objects in the stores, so that the next reconcile of the
+// SecurityPolicy patches them again.
+func (service *SecurityPolicyService) RemediateDrift(drift *Drift) error {
+	if !drift.Remediable() {
+		return nil
+	}
+	securityPolicyStore, ruleStore, groupStore, _, _ := service.getStores()
+	if drift.policy != nil {
+		if err := securityPolicyStore.Delete(drift.policy); err != nil {
+			return err
+		}
+	}
+	for _, rule := range drift.rules {
+		if err := ruleStore.Delete(rule); err != nil {
+			return err
+		}
+	}
+	for _, group := range drift.groups {
+		if err := groupStore.Delete(group); err != nil {
+			return err
+		}
+	}
+	log.Info("forgot drifted NSX objects in store", "drift", drift.String())
+	return nil
+}
+
+func namespaceOfTags(tags []model.Tag) string {
+	for _, tag := range tags {
+		if tag.Scope != nil && *tag.Scope == common.TagScopeNamespace && tag.Tag != nil {
+			return *tag.Tag
+		}
+	}
+	return ""
+}
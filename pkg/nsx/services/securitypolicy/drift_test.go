@@ -0,0 +1,100 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newFakeDriftReader(policy *model.SecurityPolicy, rules []model.Rule, groups map[string]model.Group) *driftReader {
+	return &driftReader{
+		getPolicy: func(id string) (model.SecurityPolicy, error) {
+			if policy == nil {
+				return model.SecurityPolicy{}, nsxutil.CreateResourceNotFound("10.0.0.1", "securitypolicy")
+			}
+			return *policy, nil
+		},
+		listRules: func(policyID string, cursor *string) (model.RuleListResult, error) {
+			return model.RuleListResult{Results: rules}, nil
+		},
+		getGroup: func(id string) (model.Group, error) {
+			group, ok := groups[id]
+			if !ok {
+				return model.Group{}, nsxutil.CreateResourceNotFound("10.0.0.1", "group")
+			}
+			return group, nil
+		},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	uid := "sp-uid"
+	tags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid}}
+	policy := &model.SecurityPolicy{Id: String("sp"), DisplayName: String("sp"), Tags: tags}
+	rule1 := &model.Rule{Id: String("rule-1"), DisplayName: String("rule-1"), Action: String("ALLOW"), Tags: tags, SourceGroups: []string{"/infra/domains/default/groups/src"}}
+	rule2 := &model.Rule{Id: String("rule-2"), DisplayName: String("rule-2"), Action: String("DROP"), Tags: tags}
+	group := &model.Group{Id: String("src"), Tags: tags}
+
+	// NSX defaults the unset groups, services and scope to ANY
+	nsxRule2 := *rule2
+	nsxRule2.SourceGroups, nsxRule2.DestinationGroups, nsxRule2.Services, nsxRule2.Scope = []string{"ANY"}, []string{"ANY"}, []string{"ANY"}, []string{"ANY"}
+	logged := false
+	nsxRule2.Logged = &logged
+	nsxPolicy := *policy
+	nsxPolicy.Scope = []string{"ANY"}
+	drift, err := detectDrift(newFakeDriftReader(&nsxPolicy, []model.Rule{*rule1, nsxRule2}, map[string]model.Group{"src": *group}),
+		policy, []*model.Rule{rule1, rule2}, []*model.Group{group})
+	assert.NoError(t, err)
+	assert.True(t, drift.Empty())
+
+	modifiedRule1 := *rule1
+	modifiedRule1.Action = String("DROP")
+	drift, err = detectDrift(newFakeDriftReader(policy, []model.Rule{modifiedRule1, {Id: String("manual")}}, nil),
+		policy, []*model.Rule{rule1, rule2}, []*model.Group{group})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Rule/rule-1"}, drift.Modified)
+	assert.Equal(t, []string{"Rule/rule-2", "Group/src"}, drift.Missing)
+	assert.Equal(t, []string{"Rule/manual"}, drift.Unexpected)
+	assert.True(t, drift.Remediable())
+	assert.Equal(t, "modified: [Rule/rule-1], missing: [Rule/rule-2, Group/src], unexpected: [Rule/manual]", drift.String())
+
+	// the rules are deleted with the SecurityPolicy
+	drift, err = detectDrift(newFakeDriftReader(nil, nil, map[string]model.Group{"src": *group}),
+		policy, []*model.Rule{rule1, rule2}, []*model.Group{group})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SecurityPolicy/sp"}, drift.Missing)
+	assert.Equal(t, []*model.Rule{rule1, rule2}, drift.rules)
+
+	// the rules added in NSX are only reported
+	drift, err = detectDrift(newFakeDriftReader(policy, []model.Rule{*rule1, *rule2, {Id: String("manual")}}, map[string]model.Group{"src": *group}),
+		policy, []*model.Rule{rule1, rule2}, []*model.Group{group})
+	assert.NoError(t, err)
+	assert.False(t, drift.Empty())
+	assert.False(t, drift.Remediable())
+}
+
+func TestRemediateDrift(t *testing.T) {
+	uid := "sp-uid"
+	tags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid}}
+	service := newDeleteTestService(&taggedQueryClient{})
+	policy := &model.SecurityPolicy{Id: String("sp"), Tags: tags}
+	rule1 := &model.Rule{Id: String("rule-1"), Tags: tags}
+	rule2 := &model.Rule{Id: String("rule-2"), Tags: tags}
+	group := &model.Group{Id: String("src"), Tags: tags}
+	service.securityPolicyStore.Add(policy)
+	service.ruleStore.Add(rule1)
+	service.ruleStore.Add(rule2)
+	service.groupStore.Add(group)
+
+	assert.NoError(t, service.RemediateDrift(&Drift{Modified: []string{"Rule/rule-1", "Group/src"}, rules: []*model.Rule{rule1}, groups: []*model.Group{group}}))
+	assert.NotNil(t, service.securityPolicyStore.GetByKey("sp"))
+	assert.Nil(t, service.ruleStore.GetByKey("rule-1"))
+	assert.NotNil(t, service.ruleStore.GetByKey("rule-2"))
+	assert.Nil(t, service.groupStore.GetByKey("src"))
+}
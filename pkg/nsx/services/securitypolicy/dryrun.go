@@ -0,0 +1,102 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"sort"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data/serializers/cleanjson"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	// maxDryRunChanges is the count of the changes listed in the status of a dry run.
+	maxDryRunChanges = 100
+
+	DryRunOperationCreate = "Create"
+	DryRunOperationUpdate = "Update"
+	DryRunOperationDelete = "Delete"
+)
+
+// DryRunSecurityPolicy builds the NSX SecurityPolicy, rules and groups of the SecurityPolicy CR and previews
+// their changes against the ones realized, without patching NSX or updating the stores. The payloads are the
+// fields compared with the realized resources. The project shares in VPC are not previewed.
+func (service *SecurityPolicyService) DryRunSecurityPolicy(obj *v1alpha1.SecurityPolicy) *v1alpha1.DryRunStatus {
+	status := &v1alpha1.DryRunStatus{ObservedGeneration: obj.Generation}
+	createdFor := common.ResourceTypeSecurityPolicy
+	securityPolicyStore, ruleStore, groupStore, _, _ := service.getStores()
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(obj, createdFor)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	_, indexScope := ownerTagScopes(createdFor)
+	existingSecurityPolicy := securityPolicyStore.GetByKey(*nsxSecurityPolicy.Id)
+	if err := service.checkOwnership(existingSecurityPolicy); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if ownerTag := service.buildOwnerTag(existingSecurityPolicy); ownerTag != nil {
+		nsxSecurityPolicy.Tags = append(nsxSecurityPolicy.Tags, *ownerTag)
+	}
+	existingRules := ruleStore.GetByIndex(indexScope, string(obj.UID))
+	existingGroups := groupStore.GetByIndex(indexScope, string(obj.UID))
+
+	var changes []v1alpha1.DryRunChange
+	isChanged := true
+	if existingSecurityPolicy != nil {
+		isChanged = common.CompareResource(SecurityPolicyPtrToComparable(existingSecurityPolicy), SecurityPolicyPtrToComparable(nsxSecurityPolicy))
+	}
+	if isChanged {
+		changes = append(changes, dryRunChange("SecurityPolicy", existingSecurityPolicy != nil, SecurityPolicyPtrToComparable(nsxSecurityPolicy)))
+	}
+	existingIDs := map[string]bool{}
+	for _, rule := range existingRules {
+		existingIDs["Rule/"+*rule.Id] = true
+	}
+	for _, group := range existingGroups {
+		existingIDs["Group/"+*group.Id] = true
+	}
+	changedRules, staleRules := common.CompareResources(RulesPtrToComparable(existingRules), RulesToComparable(nsxSecurityPolicy.Rules))
+	changedGroups, staleGroups := common.CompareResources(GroupsPtrToComparable(existingGroups), GroupsToComparable(*nsxGroups))
+	for _, kind := range []struct {
+		name           string
+		changed, stale []Comparable
+	}{{"Rule", changedRules, staleRules}, {"Group", changedGroups, staleGroups}} {
+		var kindChanges []v1alpha1.DryRunChange
+		for _, changed := range kind.changed {
+			kindChanges = append(kindChanges, dryRunChange(kind.name, existingIDs[kind.name+"/"+changed.Key()], changed))
+		}
+		for _, stale := range kind.stale {
+			kindChanges = append(kindChanges, v1alpha1.DryRunChange{Operation: DryRunOperationDelete, Resource: kind.name + "/" + stale.Key()})
+		}
+		sort.Slice(kindChanges, func(i, j int) bool {
+			return kindChanges[i].Resource < kindChanges[j].Resource
+		})
+		changes = append(changes, kindChanges...)
+	}
+
+	status.Summary = newSyncDiff(isChanged, existingRules, ComparableToRules(changedRules), ComparableToRules(staleRules),
+		len(changedGroups)+len(staleGroups)).String()
+	if len(changes) > maxDryRunChanges {
+		changes = changes[:maxDryRunChanges]
+	}
+	status.Changes = changes
+	return status
+}
+
+func dryRunChange(kind string, exists bool, resource Comparable) v1alpha1.DryRunChange {
+	change := v1alpha1.DryRunChange{Operation: DryRunOperationCreate, Resource: kind + "/" + resource.Key()}
+	if exists {
+		change.Operation = DryRunOperationUpdate
+	}
+	payload, err := cleanjson.NewDataValueToJsonEncoder().Encode(resource.Value())
+	if err != nil {
+		log.Error(err, "failed to encode the payload of dry run", "resource", change.Resource)
+	}
+	change.Payload = payload
+	return change
+}
@@ -0,0 +1,79 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func countDryRunOperations(changes []v1alpha1.DryRunChange) map[string]int {
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.Operation]++
+		if change.Operation == DryRunOperationDelete {
+			continue
+		}
+		if change.Payload == "" {
+			counts["NoPayload"]++
+		}
+	}
+	return counts
+}
+
+func TestDryRunSecurityPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := newDeleteTestService(&taggedQueryClient{})
+	sp := spWithVMSelector.DeepCopy()
+	sp.Generation = 2
+	status := service.DryRunSecurityPolicy(sp)
+	assert.Empty(t, status.Error)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, "policy changed, rules 3 added/0 changed/0 removed, 5 groups touched", status.Summary)
+	assert.Equal(t, "SecurityPolicy/sp_uidA", status.Changes[0].Resource)
+	assert.Equal(t, map[string]int{DryRunOperationCreate: 9}, countDryRunOperations(status.Changes))
+
+	// the stores are not updated by the dry run
+	assert.Nil(t, service.securityPolicyStore.GetByKey("sp_uidA"))
+
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	service.securityPolicyStore.Add(nsxSecurityPolicy)
+	for i := range nsxSecurityPolicy.Rules {
+		service.ruleStore.Add(&nsxSecurityPolicy.Rules[i])
+	}
+	for i := range *nsxGroups {
+		service.groupStore.Add(&(*nsxGroups)[i])
+	}
+
+	status = service.DryRunSecurityPolicy(sp)
+	assert.Equal(t, "policy unchanged, rules 0 added/0 changed/0 removed, 0 groups touched", status.Summary)
+	assert.Empty(t, status.Changes)
+
+	// the rules are logged by the policy, and the last rule is removed with its destination group
+	sp.Spec.Logged = true
+	sp.Spec.Rules = sp.Spec.Rules[:2]
+	status = service.DryRunSecurityPolicy(sp)
+	assert.Equal(t, "policy unchanged, rules 0 added/2 changed/1 removed, 1 groups touched", status.Summary)
+	assert.Equal(t, map[string]int{DryRunOperationUpdate: 2, DryRunOperationDelete: 2}, countDryRunOperations(status.Changes))
+	assert.Equal(t, "Group/sp_uidA_2_dst", status.Changes[3].Resource)
+	for _, change := range status.Changes {
+		if change.Operation == DryRunOperationUpdate {
+			assert.Contains(t, change.Payload, `"logged":true`)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
@@ -42,13 +43,7 @@ func (service *SecurityPolicyService) expandRule(obj *v1alpha1.SecurityPolicy, r
 		if err != nil {
 			return nil, nil, err
 		}
-		var ruleServiceEntries []*data.StructValue
-		for _, port := range rule.Ports {
-			portAddress := nsxutil.PortAddress{Port: port.Port.IntValue()}
-			serviceEntry := service.buildRuleServiceEntries(port, portAddress)
-			ruleServiceEntries = append(ruleServiceEntries, serviceEntry)
-		}
-		nsxRule.ServiceEntries = ruleServiceEntries
+		nsxRule.ServiceEntries = service.buildRuleServiceEntriesOfPorts(rule.Ports)
 
 		nsxRules = append(nsxRules, nsxRule)
 	}
@@ -203,9 +198,11 @@ func (service *SecurityPolicyService) resolvePodPort(pod v1.Pod, spPort *v1alpha
 					errMsg := fmt.Sprintf("pod %s/%s ip not initialized", pod.Namespace, pod.Name)
 					return nil, nsxutil.PodIPNotFound{Desc: errMsg}
 				}
+				// The secondary interfaces of the Pod expose the same container ports
+				ips := append([]string{pod.Status.PodIP}, util.GetPodSecondaryIPs(&pod)...)
 				addr = append(
 					addr,
-					nsxutil.PortAddress{Port: int(port.ContainerPort), IPs: []string{pod.Status.PodIP}},
+					nsxutil.PortAddress{Port: int(port.ContainerPort), IPs: ips},
 				)
 			}
 		}
@@ -234,7 +231,7 @@ func (service *SecurityPolicyService) buildRuleIPSetGroupPath(obj *v1alpha1.Secu
 		vpcId := (*vpcInfo).VPCID
 
 		if groupShared {
-			return fmt.Sprintf("/orgs/%s/projects/%s/infra/domains/%s/groups/%s", orgId, projectId, getVpcProjectDomain(), ipSetGroupID), nil
+			return fmt.Sprintf("/orgs/%s/projects/%s/infra/domains/%s/groups/%s", orgId, projectId, getVpcProjectDomain(service, obj.ObjectMeta.Namespace), ipSetGroupID), nil
 		}
 		return fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/groups/%s", orgId, projectId, vpcId, ipSetGroupID), nil
 	}
@@ -257,19 +254,7 @@ func (service *SecurityPolicyService) buildRuleIPSetGroup(obj *v1alpha1.Security
 	peerTags := service.buildPeerTags(obj, rule, ruleIdx, false, false, createdFor)
 	ipSetGroup.Tags = peerTags
 
-	addresses := data.NewListValue()
-	for _, ip := range ips {
-		addresses.Add(data.NewStringValue(ip))
-	}
-
-	blockExpression := data.NewStructValue(
-		"",
-		map[string]data.DataValue{
-			"resource_type": data.NewStringValue("IPAddressExpression"),
-			"ip_addresses":  addresses,
-		},
-	)
-	ipSetGroup.Expression = append(ipSetGroup.Expression, blockExpression)
+	ipSetGroup.Expression = append(ipSetGroup.Expression, expression.IPAddresses(ips))
 	return &ipSetGroup
 }
 
@@ -331,7 +316,7 @@ func (service *SecurityPolicyService) getPodSelectors(obj *v1alpha1.SecurityPoli
 					labelSelector.LabelSelector = label
 				}
 				if target.NamespaceSelector != nil {
-					ns, err := service.ResolveNamespace(target.NamespaceSelector)
+					ns, err := service.resolveNamespace(target.NamespaceSelector, service.selectorMemoFor(obj))
 					if err != nil {
 						return nil, err
 					}
@@ -380,6 +365,11 @@ func (service *SecurityPolicyService) hasNamedPort(rule *v1alpha1.SecurityPolicy
 
 // ResolveNamespace Get namespace name when the rule has namespace selector.
 func (service *SecurityPolicyService) ResolveNamespace(lbs *meta1.LabelSelector) (*v1.NamespaceList, error) {
+	return service.resolveNamespace(lbs, nil)
+}
+
+// resolveNamespace resolves the Namespaces selected, which are memoized in the memo of the build if any.
+func (service *SecurityPolicyService) resolveNamespace(lbs *meta1.LabelSelector, memo *selectorMemo) (*v1.NamespaceList, error) {
 	ctx := context.Background()
 	nsList := &v1.NamespaceList{}
 	nsOptions := &client.ListOptions{}
@@ -388,9 +378,14 @@ func (service *SecurityPolicyService) ResolveNamespace(lbs *meta1.LabelSelector)
 		return nil, err
 	}
 	nsOptions.LabelSelector = labels.SelectorFromSet(labelMap)
+	// the Namespaces resolved are memoized in the build, they must not be modified
+	if memoized, ok := memo.resolvedNamespaces(nsOptions.LabelSelector.String()); ok {
+		return memoized, nil
+	}
 	err = service.Client.List(ctx, nsList, nsOptions)
 	if err != nil {
 		return nil, err
 	}
+	memo.storeNamespaces(nsOptions.LabelSelector.String(), nsList)
 	return nsList, err
 }
@@ -179,8 +179,8 @@ func TestSecurityPolicyService_getPodSelectors(t *testing.T) {
 	labelSelector, _ := v1.LabelSelectorAsSelector(podSelector)
 	labelSelector2, _ := v1.LabelSelectorAsSelector(podSelector2)
 	var s *SecurityPolicyService
-	patches := gomonkey.ApplyMethod(reflect.TypeOf(s), "ResolveNamespace",
-		func(s *SecurityPolicyService, _ *v1.LabelSelector) (*core_v1.NamespaceList, error) {
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "resolveNamespace",
+		func(s *SecurityPolicyService, _ *v1.LabelSelector, _ *selectorMemo) (*core_v1.NamespaceList, error) {
 			ns := core_v1.NamespaceList{
 				Items: []core_v1.Namespace{
 					{
@@ -0,0 +1,191 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// A ServiceExposure is realized as the paired internal SecurityPolicies, the provider one in the Namespace of
+// the Service allowing the ingress from the consumers to the Pods of the Service, and a consumer one in each
+// consumer Namespace allowing the egress from the consumers to the Pods of the Service. The internal UIDs start
+// with the UID of the ServiceExposure followed by "_".
+
+func (service *SecurityPolicyService) BuildServiceExposureProviderPolicyID(uid string) string {
+	return fmt.Sprintf("%s_provider", uid)
+}
+
+func (service *SecurityPolicyService) BuildServiceExposureConsumerPolicyID(uid string, namespace string) string {
+	return fmt.Sprintf("%s_consumer_%s", uid, namespace)
+}
+
+// ServiceExposureUIDOf returns the UID of the ServiceExposure the internal SecurityPolicy is converted from.
+func ServiceExposureUIDOf(internalUID string) string {
+	return strings.SplitN(internalUID, "_", 2)[0]
+}
+
+// buildServiceExposurePorts returns the target ports of the Service, the port is targeted if the target port
+// is not set.
+func buildServiceExposurePorts(svc *v1.Service) []v1alpha1.SecurityPolicyPort {
+	var ports []v1alpha1.SecurityPolicyPort
+	for _, servicePort := range svc.Spec.Ports {
+		port := servicePort.TargetPort
+		if port.Type == intstr.Int && port.IntVal == 0 {
+			port = intstr.FromInt(int(servicePort.Port))
+		}
+		ports = append(ports, v1alpha1.SecurityPolicyPort{Protocol: servicePort.Protocol, Port: port})
+	}
+	return ports
+}
+
+func (service *SecurityPolicyService) convertServiceExposureToInternalSecurityPolicies(obj *v1alpha1.ServiceExposure, svc *v1.Service, consumerNamespaces []string) ([]*v1alpha1.SecurityPolicy, error) {
+	if len(svc.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s/%s has no selector", svc.Namespace, svc.Name)
+	}
+	ports := buildServiceExposurePorts(svc)
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("service %s/%s has no ports", svc.Namespace, svc.Name)
+	}
+	actionAllow := v1alpha1.RuleActionAllow
+	directionIn := v1alpha1.RuleDirectionIn
+	directionOut := v1alpha1.RuleDirectionOut
+	providerSelector := &metav1.LabelSelector{MatchLabels: svc.Spec.Selector}
+	ruleName := fmt.Sprintf("expose-%s", svc.Name)
+
+	provider := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.Namespace,
+			Name:      fmt.Sprintf("%s-provider", obj.Name),
+			UID:       types.UID(service.BuildServiceExposureProviderPolicyID(string(obj.UID))),
+		},
+		Spec: v1alpha1.SecurityPolicySpec{
+			Priority:  common.PriorityNetworkPolicyAllowRule,
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: providerSelector}},
+		},
+	}
+	providerRule := v1alpha1.SecurityPolicyRule{
+		Name:      ruleName,
+		Action:    &actionAllow,
+		Direction: &directionIn,
+		Ports:     ports,
+	}
+	for _, consumer := range obj.Spec.Consumers {
+		podSelector := consumer.PodSelector
+		if podSelector == nil {
+			podSelector = &metav1.LabelSelector{}
+		}
+		providerRule.Sources = append(providerRule.Sources, v1alpha1.SecurityPolicyPeer{
+			PodSelector:       podSelector,
+			NamespaceSelector: consumer.NamespaceSelector,
+		})
+	}
+	provider.Spec.Rules = []v1alpha1.SecurityPolicyRule{providerRule}
+	securityPolicies := []*v1alpha1.SecurityPolicy{provider}
+
+	for _, namespace := range consumerNamespaces {
+		consumer := &v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s-consumer", obj.Namespace, obj.Name),
+				UID:       types.UID(service.BuildServiceExposureConsumerPolicyID(string(obj.UID), namespace)),
+			},
+			Spec: v1alpha1.SecurityPolicySpec{
+				Priority: common.PriorityNetworkPolicyAllowRule,
+			},
+		}
+		// the consumers selected in the Namespace are allowed to access the Pods of the Service
+		for _, c := range obj.Spec.Consumers {
+			podSelector := c.PodSelector
+			if podSelector == nil {
+				podSelector = &metav1.LabelSelector{}
+			}
+			consumer.Spec.AppliedTo = append(consumer.Spec.AppliedTo, v1alpha1.SecurityPolicyTarget{PodSelector: podSelector})
+		}
+		consumer.Spec.Rules = []v1alpha1.SecurityPolicyRule{{
+			Name:      ruleName,
+			Action:    &actionAllow,
+			Direction: &directionOut,
+			Ports:     ports,
+			Destinations: []v1alpha1.SecurityPolicyPeer{{
+				PodSelector: providerSelector,
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{v1.LabelMetadataName: obj.Namespace},
+				},
+			}},
+		}}
+		securityPolicies = append(securityPolicies, consumer)
+	}
+	log.V(1).Info("converted service exposure to security policies", "serviceExposure", obj.Name, "securityPolicies", securityPolicies)
+	return securityPolicies, nil
+}
+
+// CreateOrUpdateServiceExposure realizes the paired allow rules of the ServiceExposure publishing the Service to
+// the consumer Namespaces, and deletes the rules of the Namespaces which are not consumers any more.
+func (service *SecurityPolicyService) CreateOrUpdateServiceExposure(obj *v1alpha1.ServiceExposure, svc *v1.Service, consumerNamespaces []string) error {
+	if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+		log.Info("no DFW license, skip creating ServiceExposure.")
+		return nsxutil.RestrictionError{Desc: "no DFW license"}
+	}
+	internalSecurityPolicies, err := service.convertServiceExposureToInternalSecurityPolicies(obj, svc, consumerNamespaces)
+	if err != nil {
+		return err
+	}
+	desired := sets.New[string]()
+	for _, internalSecurityPolicy := range internalSecurityPolicies {
+		if err := service.createOrUpdateSecurityPolicy(internalSecurityPolicy, common.ResourceTypeServiceExposure); err != nil {
+			return err
+		}
+		desired.Insert(string(internalSecurityPolicy.UID))
+	}
+	for uid := range service.listServiceExposurePolicyIDs(obj.UID) {
+		if desired.Has(uid) {
+			continue
+		}
+		log.Info("deleting the rules of the stale consumer", "serviceExposure", obj.Name, "UID", uid)
+		if err := service.deleteSecurityPolicy(types.UID(uid), false, common.ResourceTypeServiceExposure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteServiceExposure deletes the rules realized for the ServiceExposure on both sides.
+func (service *SecurityPolicyService) DeleteServiceExposure(uid types.UID) error {
+	for internalUID := range service.listServiceExposurePolicyIDs(uid) {
+		if err := service.deleteSecurityPolicy(types.UID(internalUID), false, common.ResourceTypeServiceExposure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListServiceExposureID returns the UIDs of the internal SecurityPolicies converted from the ServiceExposures.
+func (service *SecurityPolicyService) ListServiceExposureID() sets.Set[string] {
+	groupSet := service.groupStore.ListIndexFuncValues(common.TagScopeServiceExposureUID)
+	shareSet := service.shareStore.ListIndexFuncValues(common.TagScopeServiceExposureUID)
+	policySet := service.securityPolicyStore.ListIndexFuncValues(common.TagScopeServiceExposureUID)
+
+	return groupSet.Union(policySet).Union(shareSet)
+}
+
+func (service *SecurityPolicyService) listServiceExposurePolicyIDs(uid types.UID) sets.Set[string] {
+	ids := sets.New[string]()
+	for internalUID := range service.ListServiceExposureID() {
+		if ServiceExposureUIDOf(internalUID) == string(uid) {
+			ids.Insert(internalUID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,75 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestConvertServiceExposureToInternalSecurityPolicies(t *testing.T) {
+	service := fakeService()
+	consumerSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}}
+	obj := &v1alpha1.ServiceExposure{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "mysql", UID: "uid1"},
+		Spec: v1alpha1.ServiceExposureSpec{
+			Service:   "mysql",
+			Consumers: []v1alpha1.ServiceExposureConsumer{{NamespaceSelector: consumerSelector}},
+		},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "mysql"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "mysql"},
+			Ports: []v1.ServicePort{
+				{Protocol: v1.ProtocolTCP, Port: 3306},
+				{Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("metrics")},
+			},
+		},
+	}
+
+	policies, err := service.convertServiceExposureToInternalSecurityPolicies(obj, svc, []string{"web1", "web2"})
+	assert.NoError(t, err)
+	assert.Len(t, policies, 3)
+	expectedPorts := []v1alpha1.SecurityPolicyPort{
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(3306)},
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromString("metrics")},
+	}
+
+	// the provider allows the ingress from the consumers to the Pods of the Service
+	provider := policies[0]
+	assert.Equal(t, "db", provider.Namespace)
+	assert.Equal(t, "uid1_provider", string(provider.UID))
+	assert.Equal(t, common.PriorityNetworkPolicyAllowRule, provider.Spec.Priority)
+	assert.Equal(t, map[string]string{"app": "mysql"}, provider.Spec.AppliedTo[0].PodSelector.MatchLabels)
+	assert.Equal(t, v1alpha1.RuleDirectionIn, *provider.Spec.Rules[0].Direction)
+	assert.Equal(t, expectedPorts, provider.Spec.Rules[0].Ports)
+	assert.Equal(t, consumerSelector, provider.Spec.Rules[0].Sources[0].NamespaceSelector)
+	assert.Equal(t, &metav1.LabelSelector{}, provider.Spec.Rules[0].Sources[0].PodSelector)
+
+	// each consumer Namespace allows the egress to the Pods of the Service
+	for i, ns := range []string{"web1", "web2"} {
+		consumer := policies[i+1]
+		assert.Equal(t, ns, consumer.Namespace)
+		assert.Equal(t, "uid1_consumer_"+ns, string(consumer.UID))
+		assert.Equal(t, "uid1", ServiceExposureUIDOf(string(consumer.UID)))
+		assert.Equal(t, v1alpha1.RuleDirectionOut, *consumer.Spec.Rules[0].Direction)
+		assert.Equal(t, expectedPorts, consumer.Spec.Rules[0].Ports)
+		destination := consumer.Spec.Rules[0].Destinations[0]
+		assert.Equal(t, map[string]string{"app": "mysql"}, destination.PodSelector.MatchLabels)
+		assert.Equal(t, map[string]string{v1.LabelMetadataName: "db"}, destination.NamespaceSelector.MatchLabels)
+	}
+
+	// a Service without selector can't be published
+	svc.Spec.Selector = nil
+	_, err = service.convertServiceExposureToInternalSecurityPolicies(obj, svc, nil)
+	assert.EqualError(t, err, "service db/mysql has no selector")
+}
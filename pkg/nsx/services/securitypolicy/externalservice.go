@@ -0,0 +1,170 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The Services without selector model the dependencies out of the cluster, they're resolved by the peers
+// referring to them instead of their Pods:
+// - an ExternalName Service with a domain name is matched as an FQDN destination, by the context profile of the rule.
+// - an ExternalName Service with an IP address, or a Service with the manual Endpoints, is matched by the IPs.
+
+// isExternalService returns whether the Service has no selector, its endpoints are out of the cluster.
+func isExternalService(svc *v1.Service) bool {
+	return len(svc.Spec.Selector) == 0
+}
+
+// externalNameFQDN returns the domain name of an ExternalName Service, it returns empty if the Service is not
+// an ExternalName Service or its external name is an IP address.
+func externalNameFQDN(svc *v1.Service) string {
+	if svc.Spec.Type != v1.ServiceTypeExternalName || net.ParseIP(svc.Spec.ExternalName) != nil {
+		return ""
+	}
+	return strings.TrimSuffix(svc.Spec.ExternalName, ".")
+}
+
+// resolveExternalServiceIPs returns the IPs of the Service without selector, from its external name or its
+// Endpoints. It returns false if the Service has a selector, whose IPs are the ones of its Pods.
+func (service *SecurityPolicyService) resolveExternalServiceIPs(namespace, name string) ([]string, bool, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	svc := &v1.Service{}
+	if err := service.Client.Get(context.TODO(), key, svc); err != nil {
+		return nil, false, fmt.Errorf("failed to get %s %s: %w", v1alpha1.WorkloadKindService, key, err)
+	}
+	if !isExternalService(svc) {
+		return nil, false, nil
+	}
+	if svc.Spec.Type == v1.ServiceTypeExternalName {
+		if net.ParseIP(svc.Spec.ExternalName) != nil {
+			return []string{svc.Spec.ExternalName}, true, nil
+		}
+		// the domain name is matched by the FQDN destination
+		return nil, true, nil
+	}
+	endpoints := &v1.Endpoints{}
+	if err := service.Client.Get(context.TODO(), key, endpoints); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, true, nil
+		}
+		return nil, true, fmt.Errorf("failed to get Endpoints %s: %w", key, err)
+	}
+	var ips []string
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			ips = append(ips, address.IP)
+		}
+	}
+	return ips, true, nil
+}
+
+// withExternalNameServices returns a copy of the SecurityPolicy whose destination peers referring to the
+// ExternalName Services with domain names are replaced by the FQDN destinations of the domain names. Like the
+// FQDNs, the ExternalName Services must not be mixed with the other destinations, since the context profile
+// applies to the whole rule. The SecurityPolicy is returned as is if it refers to no ExternalName Service.
+func (service *SecurityPolicyService) withExternalNameServices(obj *v1alpha1.SecurityPolicy, createdFor string) (*v1alpha1.SecurityPolicy, error) {
+	if createdFor != common.ResourceTypeSecurityPolicy || !UsesWorkloadPeers(obj) {
+		return obj, nil
+	}
+	var converted *v1alpha1.SecurityPolicy
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		path := fmt.Sprintf("spec.rules[%d]", ruleIdx)
+		for peerIdx := range rule.Sources {
+			fqdns, err := service.peerExternalNames(obj.Namespace, &rule.Sources[peerIdx])
+			if err != nil {
+				return nil, err
+			}
+			if len(fqdns) > 0 {
+				return nil, fmt.Errorf("%s.sources[%d] refers to ExternalName Service, it's for rule destinations only", path, peerIdx)
+			}
+		}
+		var destinations []v1alpha1.SecurityPolicyPeer
+		externalNames := 0
+		for peerIdx := range rule.Destinations {
+			peer := &rule.Destinations[peerIdx]
+			fqdns, err := service.peerExternalNames(obj.Namespace, peer)
+			if err != nil {
+				return nil, err
+			}
+			if len(fqdns) == 0 {
+				destinations = append(destinations, *peer)
+				continue
+			}
+			others := *peer
+			others.Workloads = nil
+			if len(fqdns) != len(peer.Workloads) || hasOtherPeerFields(&others) {
+				return nil, fmt.Errorf("%s.destinations[%d] can't mix ExternalName Service with the other fields of the peer", path, peerIdx)
+			}
+			for _, fqdn := range fqdns {
+				destinations = append(destinations, v1alpha1.SecurityPolicyPeer{FQDN: fqdn})
+			}
+			externalNames++
+		}
+		if externalNames == 0 {
+			continue
+		}
+		if len(ruleFQDNs(&v1alpha1.SecurityPolicyRule{Destinations: destinations})) != len(destinations) {
+			return nil, fmt.Errorf("%s.destinations can't mix ExternalName Services with the other destinations", path)
+		}
+		if converted == nil {
+			converted = obj.DeepCopy()
+		}
+		converted.Spec.Rules[ruleIdx].Destinations = destinations
+	}
+	if converted == nil {
+		return obj, nil
+	}
+	return converted, nil
+}
+
+// peerExternalNames returns the domain names of the ExternalName Services referred to by the peer.
+func (service *SecurityPolicyService) peerExternalNames(namespace string, peer *v1alpha1.SecurityPolicyPeer) ([]string, error) {
+	var fqdns []string
+	for _, workload := range peer.Workloads {
+		if workload.Kind != v1alpha1.WorkloadKindService {
+			continue
+		}
+		key := types.NamespacedName{Namespace: namespace, Name: workload.Name}
+		svc := &v1.Service{}
+		if err := service.Client.Get(context.TODO(), key, svc); err != nil {
+			if apierrors.IsNotFound(err) {
+				// the missing Service is reported when its IPs are resolved
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %s: %w", workload.Kind, key, err)
+		}
+		if fqdn := externalNameFQDN(svc); fqdn != "" {
+			fqdns = append(fqdns, fqdn)
+		}
+	}
+	return fqdns, nil
+}
+
+// UsesService returns whether any peer of the SecurityPolicy refers to the Service.
+func UsesService(obj *v1alpha1.SecurityPolicy, name string) bool {
+	for _, rule := range obj.Spec.Rules {
+		for _, peers := range [][]v1alpha1.SecurityPolicyPeer{rule.Sources, rule.Destinations} {
+			for _, peer := range peers {
+				for _, workload := range peer.Workloads {
+					if workload.Kind == v1alpha1.WorkloadKindService && workload.Name == name {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
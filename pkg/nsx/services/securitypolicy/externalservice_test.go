@@ -0,0 +1,91 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_ExternalServices(t *testing.T) {
+	newService := func(name string, spec v1.ServiceSpec) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name}, Spec: spec}
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		newService("api", v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "api.example.com."}),
+		newService("dns", v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "192.168.1.53"}),
+		newService("db", v1.ServiceSpec{Ports: []v1.ServicePort{{Port: 5432}}}),
+		&v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db"},
+			Subsets:    []v1.EndpointSubset{{Addresses: []v1.EndpointAddress{{IP: "192.168.1.10"}, {IP: "192.168.1.11"}}}},
+		},
+		newService("cache", v1.ServiceSpec{Ports: []v1.ServicePort{{Port: 6379}}}),
+		newService("web", v1.ServiceSpec{Selector: map[string]string{"app": "web"}}),
+	).Build()
+	service := fakeService()
+	service.Client = k8sClient
+
+	// the Services without selector are resolved by their external names or their manual Endpoints
+	for name, expected := range map[string][]string{
+		"dns":   {"192.168.1.53"},
+		"db":    {"192.168.1.10", "192.168.1.11"},
+		"cache": nil,
+	} {
+		ips, external, err := service.resolveExternalServiceIPs("ns1", name)
+		assert.NoError(t, err)
+		assert.True(t, external, name)
+		assert.Equal(t, expected, ips, name)
+	}
+	ips, external, err := service.resolveExternalServiceIPs("ns1", "api")
+	assert.NoError(t, err)
+	assert.True(t, external)
+	assert.Empty(t, ips)
+	_, external, err = service.resolveExternalServiceIPs("ns1", "web")
+	assert.NoError(t, err)
+	assert.False(t, external)
+
+	peerOf := func(names ...string) v1alpha1.SecurityPolicyPeer {
+		peer := v1alpha1.SecurityPolicyPeer{}
+		for _, name := range names {
+			peer.Workloads = append(peer.Workloads, v1alpha1.WorkloadReference{Kind: v1alpha1.WorkloadKindService, Name: name})
+		}
+		return peer
+	}
+	sp := spWithFQDNs()
+	sp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{peerOf("api")}
+	assert.True(t, UsesService(sp, "api"))
+	assert.False(t, UsesService(sp, "db"))
+
+	// the ExternalName Service is replaced by an FQDN destination, the SecurityPolicy is not changed
+	converted, err := service.withExternalNameServices(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.SecurityPolicyPeer{{FQDN: "api.example.com"}}, converted.Spec.Rules[0].Destinations)
+	assert.Equal(t, []v1alpha1.SecurityPolicyPeer{peerOf("api")}, sp.Spec.Rules[0].Destinations)
+
+	// the Services resolved by IPs are kept as is
+	sp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{peerOf("dns", "db")}
+	converted, err = service.withExternalNameServices(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Same(t, sp, converted)
+
+	sp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{peerOf("api", "db")}
+	_, err = service.withExternalNameServices(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "spec.rules[0].destinations[0] can't mix ExternalName Service with the other fields of the peer")
+
+	sp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{peerOf("api"), peerOf("db")}
+	_, err = service.withExternalNameServices(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "spec.rules[0].destinations can't mix ExternalName Services with the other destinations")
+
+	sp.Spec.Rules[0].Destinations = nil
+	sp.Spec.Rules[0].Sources = []v1alpha1.SecurityPolicyPeer{peerOf("api")}
+	_, err = service.withExternalNameServices(sp, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "spec.rules[0].sources[0] refers to ExternalName Service, it's for rule destinations only")
+}
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 
-	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,6 +41,39 @@ type SecurityPolicyService struct {
 	projectGroupStore   *GroupStore
 	shareStore          *ShareStore
 	vpcService          common.VPCServiceProvider
+	backendSelector     BackendSelector
+	// ruleBudgets caches the last built RuleBudgets of SecurityPolicy CRs, keyed by CR UID
+	ruleBudgets sync.Map
+	// syncDiffs caches the SyncDiff of the last sync of SecurityPolicy CRs not yet reported, keyed by CR UID
+	syncDiffs sync.Map
+	// workloadIPs caches the last known IPs of the Pods of the workloads referred to by the peers, keyed by
+	// namespace/kind/name
+	workloadIPs sync.Map
+	// redirectionPolicyStore and redirectionRuleStore are nil in VPC mode, the Redirect rules are not supported
+	redirectionPolicyStore *RedirectionPolicyStore
+	redirectionRuleStore   *RedirectionRuleStore
+	// contextProfileStore is nil in VPC mode, the FQDN destinations are not supported
+	contextProfileStore *ContextProfileStore
+	// gatewayPolicyStore is nil in VPC mode, the GatewayPolicies are not supported
+	gatewayPolicyStore *GatewayPolicyStore
+	// idsPolicyStore, idsRuleStore and idsProfileStore are nil in VPC mode, the IDSPolicies are not supported
+	idsPolicyStore  *IDSPolicyStore
+	idsRuleStore    *IDSRuleStore
+	idsProfileStore *IDSProfileStore
+	// sharedGroupStore is nil in VPC mode, the rule peer groups are not shared
+	sharedGroupStore *GroupStore
+	sharedGroupRefs  sharedGroupRefs
+	// selectorMemos are the memos of the selector conversions of the SecurityPolicy CRs being built, keyed by CR UID
+	selectorMemos sync.Map
+	// draft serializes the staging in the DFW draft and its publication
+	draft draftStager
+	// priorityRanks caches the ranks of the SecurityPolicy CRs among the CRs with the same priority, keyed by CR UID
+	priorityRanks sync.Map
+	// servicePaths caches the paths of the custom NSX services referred to by display name in the rules, keyed by
+	// display name
+	servicePaths sync.Map
+	// batcher gathers the SecurityPolicies patched at the same time without VPC into one hierarchical call
+	batcher patchBatcher
 }
 
 type ProjectShare struct {
@@ -78,6 +111,19 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 	wg.Add(5)
 
 	securityPolicyService := &SecurityPolicyService{Service: service}
+	if service.NSXConfig != nil && service.NSXConfig.NsxConfig != nil {
+		backends, err := service.NSXConfig.GetEnforcementBackends()
+		if err != nil {
+			return nil, err
+		}
+		if len(backends) > 0 {
+			selector, err := namespaceBackendSelector(backends)
+			if err != nil {
+				return nil, err
+			}
+			securityPolicyService.SetBackendSelector(selector)
+		}
+	}
 
 	if isVpcEnabled(securityPolicyService) {
 		common.TagValueScopeSecurityPolicyName = common.TagScopeSecurityPolicyName
@@ -88,38 +134,57 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 	securityPolicyService.securityPolicyStore = &SecurityPolicyStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(
 			keyFunc, cache.Indexers{
-				indexScope:                      indexBySecurityPolicyUID,
-				common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+				indexScope:                             indexBySecurityPolicyUID,
+				common.TagScopeNetworkPolicyUID:        indexByNetworkPolicyUID,
+				common.TagScopeServiceExposureUID:      indexByServiceExposureUID,
+				common.TagScopeAdminNetworkPolicyUID:   indexByAdminNetworkPolicyUID,
+				common.TagScopeDefaultDenyNamespaceUID: indexByDefaultDenyNamespaceUID,
 			}),
 		BindingType: model.SecurityPolicyBindingType(),
 	}}
 	securityPolicyService.groupStore = &GroupStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
-			common.TagScopeRuleID:           indexGroupFunc,
+			indexScope:                             indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:        indexByNetworkPolicyUID,
+			common.TagScopeServiceExposureUID:      indexByServiceExposureUID,
+			common.TagScopeAdminNetworkPolicyUID:   indexByAdminNetworkPolicyUID,
+			common.TagScopeDefaultDenyNamespaceUID: indexByDefaultDenyNamespaceUID,
+			common.TagScopeGatewayPolicyCRUID:      indexByGatewayPolicyUID,
+			common.TagScopeIDSPolicyCRUID:          indexByIDSPolicyUID,
+			common.TagScopeRuleID:                  indexGroupFunc,
 		}),
 		BindingType: model.GroupBindingType(),
 	}}
 	securityPolicyService.ruleStore = &RuleStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+			indexScope:                             indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:        indexByNetworkPolicyUID,
+			common.TagScopeServiceExposureUID:      indexByServiceExposureUID,
+			common.TagScopeAdminNetworkPolicyUID:   indexByAdminNetworkPolicyUID,
+			common.TagScopeDefaultDenyNamespaceUID: indexByDefaultDenyNamespaceUID,
+			common.TagScopeGatewayPolicyCRUID:      indexByGatewayPolicyUID,
+			sharedGroupIndexKey:                    indexBySharedGroup,
 		}),
 		BindingType: model.RuleBindingType(),
 	}}
 
 	securityPolicyService.projectGroupStore = &GroupStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+			indexScope:                             indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:        indexByNetworkPolicyUID,
+			common.TagScopeServiceExposureUID:      indexByServiceExposureUID,
+			common.TagScopeAdminNetworkPolicyUID:   indexByAdminNetworkPolicyUID,
+			common.TagScopeDefaultDenyNamespaceUID: indexByDefaultDenyNamespaceUID,
 		}),
 		BindingType: model.GroupBindingType(),
 	}}
 	securityPolicyService.shareStore = &ShareStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+			indexScope:                             indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:        indexByNetworkPolicyUID,
+			common.TagScopeServiceExposureUID:      indexByServiceExposureUID,
+			common.TagScopeAdminNetworkPolicyUID:   indexByAdminNetworkPolicyUID,
+			common.TagScopeDefaultDenyNamespaceUID: indexByDefaultDenyNamespaceUID,
 		}),
 		BindingType: model.ShareBindingType(),
 	}}
@@ -147,6 +212,55 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 	go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, ResourceTypeShare, nil, securityPolicyService.shareStore)
 	go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, ResourceTypeSecurityPolicy, nil, securityPolicyService.securityPolicyStore)
 	go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, ResourceTypeRule, nil, securityPolicyService.ruleStore)
+	if !isVpcEnabled(securityPolicyService) {
+		securityPolicyService.redirectionPolicyStore = &RedirectionPolicyStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{indexScope: indexBySecurityPolicyUID}),
+			BindingType: model.RedirectionPolicyBindingType(),
+		}}
+		securityPolicyService.redirectionRuleStore = &RedirectionRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{indexScope: indexBySecurityPolicyUID}),
+			BindingType: model.RedirectionRuleBindingType(),
+		}}
+		securityPolicyService.contextProfileStore = &ContextProfileStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{indexScope: indexBySecurityPolicyUID}),
+			BindingType: model.PolicyContextProfileBindingType(),
+		}}
+		securityPolicyService.gatewayPolicyStore = &GatewayPolicyStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeGatewayPolicyCRUID: indexByGatewayPolicyUID}),
+			BindingType: model.GatewayPolicyBindingType(),
+		}}
+		securityPolicyService.idsPolicyStore = &IDSPolicyStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIDSPolicyCRUID: indexByIDSPolicyUID}),
+			BindingType: model.IdsSecurityPolicyBindingType(),
+		}}
+		securityPolicyService.idsRuleStore = &IDSRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIDSPolicyCRUID: indexByIDSPolicyUID}),
+			BindingType: model.IdsRuleBindingType(),
+		}}
+		securityPolicyService.idsProfileStore = &IDSProfileStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIDSPolicyCRUID: indexByIDSPolicyUID}),
+			BindingType: model.IdsProfileBindingType(),
+		}}
+		securityPolicyService.sharedGroupStore = &GroupStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+			BindingType: model.GroupBindingType(),
+		}}
+		sharedGroupTag := []model.Tag{
+			{
+				Scope: String(common.TagScopeGroupType),
+				Tag:   String(common.TagValueGroupShared),
+			},
+		}
+		wg.Add(8)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeRedirectionPolicy, nil, securityPolicyService.redirectionPolicyStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeRedirectionRule, nil, securityPolicyService.redirectionRuleStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeContextProfile, nil, securityPolicyService.contextProfileStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeGatewayPolicy, nil, securityPolicyService.gatewayPolicyStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIdsSecurityPolicy, nil, securityPolicyService.idsPolicyStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIdsRule, nil, securityPolicyService.idsRuleStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIdsProfile, nil, securityPolicyService.idsProfileStore)
+		go securityPolicyService.InitializeResourceStore(&wg, fatalErrors, ResourceTypeGroup, sharedGroupTag, securityPolicyService.sharedGroupStore)
+	}
 
 	go func() {
 		wg.Wait()
@@ -161,6 +275,14 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 		return securityPolicyService, err
 	}
 
+	if err := securityPolicyService.SyncNetworkGroups(); err != nil {
+		log.Error(err, "failed to sync NSX groups of cluster networks")
+		return securityPolicyService, err
+	}
+	if err := securityPolicyService.SyncClusterGroup(); err != nil {
+		log.Error(err, "failed to sync NSX group of all the cluster workloads")
+		return securityPolicyService, err
+	}
 	return securityPolicyService, nil
 }
 
@@ -257,6 +379,16 @@ func (service *SecurityPolicyService) convertNetworkPolicyToInternalSecurityPoli
 			}
 			spAllow.Spec.Rules = append(spAllow.Spec.Rules, *rule)
 		}
+		// the health checks of the load balancer are allowed to the isolated Pods behind LoadBalancer Services
+		if isVpcEnabled(service) {
+			rule, err := service.buildHealthProbeRule(spAllow)
+			if err != nil {
+				return securityPolicies, fmt.Errorf("failed to build health probe rule: %w", err)
+			}
+			if rule != nil {
+				spAllow.Spec.Rules = append(spAllow.Spec.Rules, *rule)
+			}
+		}
 	}
 	securityPolicies = append(securityPolicies, spAllow, spIsolation)
 
@@ -350,6 +482,47 @@ func (service *SecurityPolicyService) getStores() (*SecurityPolicyStore, *RuleSt
 	return service.securityPolicyStore, service.ruleStore, service.groupStore, service.projectGroupStore, service.shareStore
 }
 
+// CountObjects implements common.ObjectCounter, the project groups are counted as groups.
+func (service *SecurityPolicyService) CountObjects() map[string]common.ObjectCounts {
+	securityPolicyStore, ruleStore, groupStore, projectGroupStore, shareStore := service.getStores()
+	groupCounts := groupStore.CountByNamespace(tagsOf)
+	for ns, count := range projectGroupStore.CountByNamespace(tagsOf) {
+		groupCounts[ns] += count
+	}
+	return map[string]common.ObjectCounts{
+		common.ObjectTypeSecurityPolicy: securityPolicyStore.CountByNamespace(tagsOf),
+		common.ObjectTypeRule:           ruleStore.CountByNamespace(tagsOf),
+		common.ObjectTypeGroup:          groupCounts,
+		common.ObjectTypeShare:          shareStore.CountByNamespace(tagsOf),
+	}
+}
+
+// RuleCountKey is the namespace, action and direction by which the realized NSX rules are counted.
+type RuleCountKey struct {
+	Namespace string
+	Action    string
+	Direction string
+}
+
+// CountRulesByAction counts the realized NSX rules in the store by namespace, action and direction,
+// the action and direction are in lower case, e.g. "drop" and "in_out".
+func (service *SecurityPolicyService) CountRulesByAction() map[RuleCountKey]int {
+	_, ruleStore, _, _, _ := service.getStores()
+	counts := map[RuleCountKey]int{}
+	for _, obj := range ruleStore.List() {
+		rule := obj.(*model.Rule)
+		key := RuleCountKey{Namespace: common.NamespaceOfTags(rule.Tags)}
+		if rule.Action != nil {
+			key.Action = strings.ToLower(*rule.Action)
+		}
+		if rule.Direction != nil {
+			key.Direction = strings.ToLower(*rule.Direction)
+		}
+		counts[key]++
+	}
+	return counts
+}
+
 func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1.SecurityPolicy, createdFor string) error {
 	securityPolicyStore, ruleStore, groupStore, projectGroupStore, shareStore := service.getStores()
 	nsxSecurityPolicy, nsxGroups, projectShares, err := service.buildSecurityPolicy(obj, createdFor)
@@ -357,15 +530,38 @@ func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1
 		log.Error(err, "failed to build SecurityPolicy")
 		return err
 	}
+	// The rule peer groups are replaced with the groups of the same criteria shared by the policies.
+	ownGroups, sharedGroups := service.shareGroups(nsxSecurityPolicy.Rules, *nsxGroups)
+	nsxGroups = &ownGroups
 
 	if len(nsxSecurityPolicy.Scope) == 0 {
-		log.Info("SecurityPolicy has empty policy-level appliedTo")
+		log.Info("SecurityPolicy has empty policy-level appliedTo, set appliedTo all to apply it to all the cluster workloads explicitly")
 	}
-	indexScope := common.TagValueScopeSecurityPolicyUID
-	if createdFor == common.ResourceTypeNetworkPolicy {
-		indexScope = common.TagScopeNetworkPolicyUID
+	_, indexScope := ownerTagScopes(createdFor)
+	redirectionPolicies, err := service.buildRedirectionPolicies(obj, nsxSecurityPolicy, createdFor)
+	if err != nil {
+		log.Error(err, "failed to build RedirectionPolicies")
+		return err
 	}
 	existingSecurityPolicy := securityPolicyStore.GetByKey(*nsxSecurityPolicy.Id)
+	if err := service.checkOwnership(existingSecurityPolicy); err != nil {
+		log.Error(err, "refuse to update the NSX SecurityPolicy owned by another controller", "nsxSecurityPolicy.Id", nsxSecurityPolicy.Id)
+		return err
+	}
+	if ownerTag := service.buildOwnerTag(existingSecurityPolicy); ownerTag != nil {
+		nsxSecurityPolicy.Tags = append(nsxSecurityPolicy.Tags, *ownerTag)
+	}
+	// The stale redirection rules are deleted before the stale groups they refer to.
+	staleRedirectionPolicies, changedRedirectionPolicies := service.diffRedirectionPolicies(indexScope, obj.UID, redirectionPolicies)
+	if err := service.patchRedirectionPolicies(staleRedirectionPolicies); err != nil {
+		return err
+	}
+	// The changed context profiles are patched before the rules refer to them, the stale ones are deleted after
+	// the rules no longer refer to them.
+	staleContextProfiles, changedContextProfiles := service.diffContextProfiles(indexScope, obj.UID, service.buildContextProfiles(obj, createdFor))
+	if err := service.patchContextProfiles(changedContextProfiles); err != nil {
+		return err
+	}
 	existingRules := ruleStore.GetByIndex(indexScope, string(obj.UID))
 	existingGroups := groupStore.GetByIndex(indexScope, string(obj.UID))
 
@@ -381,14 +577,31 @@ func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1
 
 	if !isChanged && len(changedRules) == 0 && len(staleRules) == 0 && len(changedGroups) == 0 && len(staleGroups) == 0 {
 		log.Info("securityPolicy, rules and groups are not changed, skip updating them", "nsxSecurityPolicy.Id", nsxSecurityPolicy.Id)
-		return nil
+		if err := service.patchContextProfiles(staleContextProfiles); err != nil {
+			return err
+		}
+		return service.patchRedirectionPolicies(changedRedirectionPolicies)
+	}
+	if err := reserveCapacity(existingSecurityPolicy == nil, len(nsxSecurityPolicy.Rules), len(existingRules), len(*nsxGroups), len(existingGroups), true); err != nil {
+		log.Error(err, "refuse to patch the SecurityPolicy under NSX capacity pressure", "nsxSecurityPolicy.Id", nsxSecurityPolicy.Id)
+		return err
+	}
+	// The shared groups are created before the rules refer to them, and the ones no longer referred to are
+	// deleted after the rules are updated in store.
+	err = service.acquireSharedGroups(sharedGroups)
+	defer service.releaseSharedGroups(sharedGroups)
+	if err != nil {
+		return err
 	}
 
 	var finalSecurityPolicy *model.SecurityPolicy
 	if isChanged {
 		finalSecurityPolicy = nsxSecurityPolicy
 	} else {
-		finalSecurityPolicy = existingSecurityPolicy
+		// Only the changed and stale rules are patched, the unchanged SecurityPolicy is referred to as their parent.
+		finalSecurityPolicy = securityPolicyReference(existingSecurityPolicy)
+		log.V(1).Info("securityPolicy is not changed, patching the changed rules only", "nsxSecurityPolicy.Id", nsxSecurityPolicy.Id,
+			"changedRules", len(changedRules), "staleRules", len(staleRules))
 	}
 
 	finalRules := make([]model.Rule, 0)
@@ -410,14 +623,13 @@ func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1
 	finalSecurityPolicyCopy := *finalSecurityPolicy
 	finalSecurityPolicyCopy.Rules = finalRules
 
-	if isVpcEnabled(service) {
-		vpcInfo, err := service.getVpcInfo(obj.ObjectMeta.Namespace)
-		if err != nil {
-			return err
-		}
-
-		finalProjectGroups := make([]model.Group, 0)
-		finalProjectShares := make([]model.Share, 0)
+	backend, err := service.getBackend(obj.ObjectMeta.Namespace, false)
+	if err != nil {
+		return err
+	}
+	finalProjectGroups := make([]model.Group, 0)
+	finalProjectShares := make([]model.Share, 0)
+	if backend.UsesProjectShares() {
 		nsxProjectGroups := make([]model.Group, 0)
 		nsxProjectShares := make([]model.Share, 0)
 		for i := len(*projectShares) - 1; i >= 0; i-- {
@@ -449,61 +661,37 @@ func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1
 		}
 		finalProjectShares = append(finalProjectShares, staleProjectShares...)
 		finalProjectShares = append(finalProjectShares, changedProjectShares...)
+	}
 
-		// 1.Wrap project groups and shares into project child infra.
-		var projectInfra []*data.StructValue
-		if len(finalProjectGroups) != 0 || len(finalProjectShares) != 0 {
-			projectInfra, err = service.wrapHierarchyProjectResources(finalProjectShares, finalProjectGroups)
-			if err != nil {
-				log.Error(err, "failed to wrap project groups and shares")
-				return err
-			}
-		}
-
-		// 2.Wrap SecurityPolicy, groups, rules under VPC level together with project groups and shares into one hierarchy resource tree.
-		orgRoot, err := service.WrapHierarchyVpcSecurityPolicy(finalSecurityPolicy, finalGroups, projectInfra, vpcInfo)
-		if err != nil {
-			log.Error(err, "failed to wrap SecurityPolicy in VPC")
-			return err
-		}
-
-		// 3.Create/update SecurityPolicy together with groups, rules under VPC level and project groups, shares.
-		err = service.NSXClient.OrgRootClient.Patch(*orgRoot, &EnforceRevisionCheckParam)
-		if err != nil {
-			log.Error(err, "failed to create or update SecurityPolicy in VPC")
-			return err
-		}
+	// Create/update SecurityPolicy together with groups, rules, as well as project groups and shares if any, on the backend.
+	err = backend.Realize(obj.ObjectMeta.Namespace, finalSecurityPolicy, finalGroups, finalProjectGroups, finalProjectShares)
+	if err != nil {
+		log.Error(err, "failed to create or update SecurityPolicy", "backend", backend.Name())
+		return err
+	}
 
-		if len(finalProjectGroups) != 0 {
-			err = projectGroupStore.Apply(&finalProjectGroups)
-			if err != nil {
-				log.Error(err, "failed to apply store", "nsxProjectGroups", finalProjectGroups)
-				return err
-			}
-		}
+	// The changed redirection rules are patched after the new groups they refer to.
+	if err := service.patchRedirectionPolicies(changedRedirectionPolicies); err != nil {
+		return err
+	}
+	if err := service.patchContextProfiles(staleContextProfiles); err != nil {
+		return err
+	}
 
-		if len(finalProjectShares) != 0 {
-			err = shareStore.Apply(&finalProjectShares)
-			if err != nil {
-				log.Error(err, "failed to apply store", "nsxProjectShares", finalProjectShares)
-				return err
-			}
-		}
-	} else {
-		infraSecurityPolicy, err := service.WrapHierarchySecurityPolicy(finalSecurityPolicy, finalGroups)
+	if len(finalProjectGroups) != 0 {
+		err = projectGroupStore.Apply(&finalProjectGroups)
 		if err != nil {
-			log.Error(err, "failed to wrap SecurityPolicy")
+			log.Error(err, "failed to apply store", "nsxProjectGroups", finalProjectGroups)
 			return err
 		}
-		err = service.NSXClient.InfraClient.Patch(*infraSecurityPolicy, &EnforceRevisionCheckParam)
+	}
+	if len(finalProjectShares) != 0 {
+		err = shareStore.Apply(&finalProjectShares)
 		if err != nil {
-			log.Error(err, "failed to create or update SecurityPolicy")
+			log.Error(err, "failed to apply store", "nsxProjectShares", finalProjectShares)
 			return err
 		}
 	}
-	if err != nil {
-		return err
-	}
 
 	// The steps below know how to deal with NSX resources, if there is MarkedForDelete, then delete it from store,
 	// otherwise add or update it to store.
@@ -528,6 +716,9 @@ func (service *SecurityPolicyService) createOrUpdateSecurityPolicy(obj *v1alpha1
 			return err
 		}
 	}
+	if createdFor == common.ResourceTypeSecurityPolicy {
+		service.syncDiffs.Store(obj.UID, newSyncDiff(isChanged, existingRules, changedRules, staleRules, len(finalGroups)))
+	}
 	log.Info("successfully created or updated nsx SecurityPolicy", "nsxSecurityPolicy", finalSecurityPolicyCopy)
 	return nil
 }
@@ -554,189 +745,311 @@ func (service *SecurityPolicyService) DeleteSecurityPolicy(obj interface{}, isVp
 	return err
 }
 
+type storeSearch struct {
+	resourceType string
+	tags         []model.Tag
+	store        common.Store
+}
+
+// storeSearches returns the searches of the NSX objects tagged with the tag of the owner for each store.
+func (service *SecurityPolicyService) storeSearches(ownerTag model.Tag) []storeSearch {
+	securityPolicyStore, ruleStore, groupStore, projectGroupStore, shareStore := service.getStores()
+	groupTags := []model.Tag{ownerTag}
+	if isVpcEnabled(service) {
+		groupTags = append(groupTags, model.Tag{Scope: String(common.TagScopeProjectGroupShared), Tag: String("false")})
+	}
+	searches := []storeSearch{
+		{ResourceTypeSecurityPolicy, []model.Tag{ownerTag}, securityPolicyStore},
+		{ResourceTypeRule, []model.Tag{ownerTag}, ruleStore},
+		{ResourceTypeGroup, groupTags, groupStore},
+	}
+	if projectGroupStore != nil && shareStore != nil {
+		projectGroupTags := []model.Tag{ownerTag, {Scope: String(common.TagScopeProjectGroupShared), Tag: String("true")}}
+		searches = append(searches,
+			storeSearch{ResourceTypeGroup, projectGroupTags, projectGroupStore},
+			storeSearch{ResourceTypeShare, []model.Tag{ownerTag}, shareStore})
+	}
+	return searches
+}
+
+// searchStoresByUID searches the NSX objects tagged with the UID of the SecurityPolicy or NetworkPolicy
+// and adds them to the stores.
+func (service *SecurityPolicyService) searchStoresByUID(indexScope string, uid types.UID) error {
+	uidTag := model.Tag{Scope: String(indexScope), Tag: String(string(uid))}
+	for _, search := range service.storeSearches(uidTag) {
+		count, err := service.SearchTaggedResource(search.resourceType, search.tags, search.store)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Info("found NSX objects missing in store", "resourceType", search.resourceType, "count", count, "UID", uid)
+		}
+	}
+	return nil
+}
+
+// ScanOrphans searches the NSX SecurityPolicies, rules and groups of the cluster created for the SecurityPolicy
+// CRs, and adds the ones missing in the stores, e.g. created before nsx-operator exited without recording them,
+// so that the GC diffing ListSecurityPolicyID with the CRs deletes the ones whose CR is gone. The objects in the
+// stores are kept as they are, since they may be more recent than the search results. It returns the count of
+// the NSX objects added to the stores.
+func (service *SecurityPolicyService) ScanOrphans() (int, error) {
+	ownerTag := model.Tag{Scope: String(common.TagValueScopeSecurityPolicyUID)}
+	found := 0
+	for _, search := range service.storeSearches(ownerTag) {
+		// the typed GetByKey of the stores shadows the one of the indexer, they're not a cache.Store
+		store, ok := search.store.(interface {
+			Get(obj interface{}) (item interface{}, exists bool, err error)
+			Add(obj interface{}) error
+		})
+		if !ok {
+			return found, fmt.Errorf("unsupported store %T to scan orphans", search.store)
+		}
+		scanned := newScanStore(search.resourceType)
+		if _, err := service.SearchTaggedResource(search.resourceType, search.tags, scanned); err != nil {
+			return found, err
+		}
+		count := 0
+		for _, obj := range scanned.List() {
+			_, exists, err := store.Get(obj)
+			if err != nil {
+				return found, err
+			}
+			if exists {
+				continue
+			}
+			if err := store.Add(obj); err != nil {
+				return found, err
+			}
+			count++
+		}
+		if count > 0 {
+			log.Info("found NSX objects missing in store", "resourceType", search.resourceType, "count", count)
+		}
+		found += count
+	}
+	return found, nil
+}
+
+// newScanStore returns an empty store of the type storing the NSX objects of the resource type.
+func newScanStore(resourceType string) interface {
+	common.Store
+	List() []interface{}
+} {
+	switch resourceType {
+	case ResourceTypeSecurityPolicy:
+		return &SecurityPolicyStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+			BindingType: model.SecurityPolicyBindingType(),
+		}}
+	case ResourceTypeRule:
+		return &RuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+			BindingType: model.RuleBindingType(),
+		}}
+	case ResourceTypeShare:
+		return &ShareStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+			BindingType: model.ShareBindingType(),
+		}}
+	default:
+		return &GroupStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+			BindingType: model.GroupBindingType(),
+		}}
+	}
+}
+
 func (service *SecurityPolicyService) deleteSecurityPolicy(obj interface{}, isVpcCleanup bool, createdFor string) error {
-	var nsxSecurityPolicy *model.SecurityPolicy
+	var spUID types.UID
 	var spNameSpace string
-	var err error
-	g := make([]model.Group, 0)
-	nsxGroups := &g
-	r := make([]model.Rule, 0)
-	nsxRules := &r
-	var projectShares *[]ProjectShare
+	nsxGroups := make([]model.Group, 0)
+	nsxRules := make([]model.Rule, 0)
 	nsxProjectShares := make([]model.Share, 0)
 	nsxProjectGroups := make([]model.Group, 0)
 	securityPolicyStore, ruleStore, groupStore, projectGroupStore, shareStore := service.getStores()
 	switch sp := obj.(type) {
-	// This case is for normal SecurityPolicy deletion process, which means that SecurityPolicy
-	// has corresponding nsx SecurityPolicy object
+	// This case is for normal SecurityPolicy deletion process. The NSX objects are collected from the stores
+	// rather than built from the CR spec, so that the deletion doesn't fail on a spec which can't be built.
 	case *v1alpha1.SecurityPolicy:
-		nsxSecurityPolicy, nsxGroups, projectShares, err = service.buildSecurityPolicy(sp, createdFor)
+		spUID = sp.UID
 		spNameSpace = sp.ObjectMeta.Namespace
-		if err != nil {
-			log.Error(err, "failed to build nsx SecurityPolicy in deleting")
-			return err
-		}
-
-		// Collect project share and project level groups that need to be removed from nsx
-		// project share and project groups only needed in VPC network.
-		for i := len(*projectShares) - 1; i >= 0; i-- {
-			nsxProjectGroups = append(nsxProjectGroups, *(*projectShares)[i].shareGroup)
-			nsxProjectShares = append(nsxProjectShares, *(*projectShares)[i].share)
-		}
-
 	// This case is for SecurityPolicy GC or cleanup process, which means that SecurityPolicy
 	// doesn't exist in K8s any more but still has corresponding nsx SecurityPolicy object.
 	// Hence, we use SecurityPolicy's UID here from store instead of K8s SecurityPolicy object
 	case types.UID:
-		indexScope := common.TagValueScopeSecurityPolicyUID
-		if createdFor == common.ResourceTypeNetworkPolicy {
-			indexScope = common.TagScopeNetworkPolicyUID
-		}
-		existingSecurityPolices := securityPolicyStore.GetByIndex(indexScope, string(sp))
-		if len(existingSecurityPolices) == 0 {
-			log.Info("NSX security policy is not found in store, skip deleting it", "nsxSecurityPolicyUID", sp, "createdFor", createdFor)
-			return nil
-		}
-		nsxSecurityPolicy = existingSecurityPolices[0]
-		// Get namespace of nsx SecurityPolicy from tags since there is no K8s SecurityPolicy object
-		for i := len(nsxSecurityPolicy.Tags) - 1; i >= 0; i-- {
-			if *(nsxSecurityPolicy.Tags[i].Scope) == common.TagScopeNamespace {
-				spNameSpace = *(nsxSecurityPolicy.Tags[i].Tag)
-				log.V(1).Info("get namespace with SecurityPolicy index", "namespace", spNameSpace, "securityPolicyUID", string(sp))
-				break
+		spUID = sp
+	default:
+		return fmt.Errorf("unsupported type %T to delete SecurityPolicy", obj)
+	}
+	service.ruleBudgets.Delete(spUID)
+	service.syncDiffs.Delete(spUID)
+	service.priorityRanks.Delete(spUID)
+
+	_, indexScope := ownerTagScopes(createdFor)
+	existingSecurityPolices := securityPolicyStore.GetByIndex(indexScope, string(spUID))
+	if len(existingSecurityPolices) == 0 {
+		// The SecurityPolicy may be missing in store while its children are left on NSX, e.g. the
+		// process exited between the NSX call and the store update, search them from NSX.
+		log.Info("NSX security policy is not found in store, searching the NSX objects by UID", "nsxSecurityPolicyUID", spUID, "createdFor", createdFor)
+		if err := service.searchStoresByUID(indexScope, spUID); err != nil {
+			log.Error(err, "failed to search NSX objects by UID", "nsxSecurityPolicyUID", spUID)
+			return err
+		}
+		existingSecurityPolices = securityPolicyStore.GetByIndex(indexScope, string(spUID))
+	}
+	// Don't modify the SecurityPolicy in store, the wrapping below modifies the input security policy.
+	var nsxSecurityPolicy model.SecurityPolicy
+	policyExists := len(existingSecurityPolices) > 0
+	if policyExists {
+		if !isVpcCleanup {
+			if err := service.checkOwnership(existingSecurityPolices[0]); err != nil {
+				log.Error(err, "refuse to delete the NSX SecurityPolicy owned by another controller", "nsxSecurityPolicyUID", spUID)
+				return err
 			}
 		}
+		nsxSecurityPolicy = *existingSecurityPolices[0]
+	} else {
+		// Only the orphan groups, project groups and shares are deleted, the reference without
+		// rules is not in the hierarchy.
+		nsxSecurityPolicy = *securityPolicyReference(&model.SecurityPolicy{})
+	}
+
+	// The redirection policies are deleted before the groups they refer to.
+	if err := service.deleteRedirectionPolicies(indexScope, spUID); err != nil {
+		return err
+	}
 
-		existingGroups := groupStore.GetByIndex(indexScope, string(sp))
-		if len(existingGroups) == 0 {
-			log.Info("did not get groups with SecurityPolicy index", "securityPolicyUID", string(sp))
+	existingGroups := groupStore.GetByIndex(indexScope, string(spUID))
+	if len(existingGroups) == 0 {
+		log.Info("did not get groups with SecurityPolicy index", "securityPolicyUID", string(spUID))
+	}
+	for _, group := range existingGroups {
+		nsxGroups = append(nsxGroups, *group)
+	}
+	if spNameSpace == "" {
+		// Get namespace of nsx SecurityPolicy from tags since there is no K8s SecurityPolicy object,
+		// or from the orphan groups if the SecurityPolicy is not found.
+		tags := nsxSecurityPolicy.Tags
+		if !policyExists && len(existingGroups) > 0 {
+			tags = existingGroups[0].Tags
 		}
-		for _, group := range existingGroups {
-			*nsxGroups = append(*nsxGroups, *group)
+		for i := len(tags) - 1; i >= 0; i-- {
+			if *(tags[i].Scope) == common.TagScopeNamespace {
+				spNameSpace = *(tags[i].Tag)
+				log.V(1).Info("get namespace with SecurityPolicy index", "namespace", spNameSpace, "securityPolicyUID", string(spUID))
+				break
+			}
 		}
+	}
+
+	// There is no nsx rules in the security policy retrieved from securityPolicy store,
+	// the rules associated the deleting security policy can only be gotten from rule store.
+	existingRules := ruleStore.GetByIndex(indexScope, string(spUID))
+	if len(existingRules) == 0 {
+		log.Info("did not get rules with SecurityPolicy index", "securityPolicyUID", string(spUID))
+	}
+	for _, rule := range existingRules {
+		nsxRules = append(nsxRules, *rule)
+	}
+	if policyExists {
+		nsxSecurityPolicy.Rules = nsxRules
+	}
 
-		// In GC or Cleanup process, there is no nsx rules in the security policy retrieved from securityPolicy store
-		// the rules associated the deleting security policy can only be gotten from rule store.
-		existingRules := ruleStore.GetByIndex(indexScope, string(sp))
-		if len(existingRules) == 0 {
-			log.Info("did not get rules with SecurityPolicy index", "securityPolicyUID", string(sp))
+	backend, err := service.getBackend(spNameSpace, isVpcCleanup)
+	if err != nil {
+		return err
+	}
+	if backend.UsesProjectShares() {
+		existingNsxProjectGroups := projectGroupStore.GetByIndex(indexScope, string(spUID))
+		if len(existingNsxProjectGroups) == 0 {
+			log.Info("did not get project groups with SecurityPolicy index", "securityPolicyUID", string(spUID))
 		}
-		for _, rule := range existingRules {
-			*nsxRules = append(*nsxRules, *rule)
+		for _, projectGroup := range existingNsxProjectGroups {
+			nsxProjectGroups = append(nsxProjectGroups, *projectGroup)
 		}
-		nsxSecurityPolicy.Rules = *nsxRules
-
-		if isVpcEnabled(service) || isVpcCleanup {
-			existingNsxProjectGroups := projectGroupStore.GetByIndex(indexScope, string(sp))
-			if len(existingNsxProjectGroups) == 0 {
-				log.Info("did not get project groups with SecurityPolicy index", "securityPolicyUID", string(sp))
-			}
-			for _, projectGroup := range existingNsxProjectGroups {
-				nsxProjectGroups = append(nsxProjectGroups, *projectGroup)
-			}
-			existingNsxProjectShares := shareStore.GetByIndex(indexScope, string(sp))
-			if len(existingNsxProjectShares) == 0 {
-				log.Info("did not get project shares with SecurityPolicy index", "securityPolicyUID", string(sp))
-			}
-			for _, nsxShare := range existingNsxProjectShares {
-				nsxProjectShares = append(nsxProjectShares, *nsxShare)
-			}
+		existingNsxProjectShares := shareStore.GetByIndex(indexScope, string(spUID))
+		if len(existingNsxProjectShares) == 0 {
+			log.Info("did not get project shares with SecurityPolicy index", "securityPolicyUID", string(spUID))
 		}
+		for _, nsxShare := range existingNsxProjectShares {
+			nsxProjectShares = append(nsxProjectShares, *nsxShare)
+		}
+	}
+	if !policyExists && len(nsxGroups) == 0 && len(nsxProjectGroups) == 0 && len(nsxProjectShares) == 0 {
+		log.Info("NSX security policy is not found, skip deleting it", "nsxSecurityPolicyUID", spUID, "createdFor", createdFor)
+		return service.deleteContextProfiles(indexScope, spUID)
 	}
 
-	nsxSecurityPolicy.MarkedForDelete = &MarkedForDelete
-	for i := len(*nsxGroups) - 1; i >= 0; i-- { // Don't use range, it would copy the element
-		(*nsxGroups)[i].MarkedForDelete = &MarkedForDelete
+	if policyExists {
+		nsxSecurityPolicy.MarkedForDelete = &MarkedForDelete
+	}
+	for i := len(nsxGroups) - 1; i >= 0; i-- { // Don't use range, it would copy the element
+		nsxGroups[i].MarkedForDelete = &MarkedForDelete
 	}
 	for i := len(nsxSecurityPolicy.Rules) - 1; i >= 0; i-- { // Don't use range, it would copy the element
 		nsxSecurityPolicy.Rules[i].MarkedForDelete = &MarkedForDelete
 	}
 
 	// WrapHighLevelSecurityPolicy will modify the input security policy, so we need to make a copy for the following store update.
-	finalSecurityPolicyCopy := *nsxSecurityPolicy
+	finalSecurityPolicyCopy := nsxSecurityPolicy
 	finalSecurityPolicyCopy.Rules = nsxSecurityPolicy.Rules
 
-	if isVpcEnabled(service) || isVpcCleanup {
-		vpcInfo, err := service.getVpcInfo(spNameSpace)
-		if err != nil {
-			return err
-		}
-
-		for i := len(nsxProjectGroups) - 1; i >= 0; i-- {
-			nsxProjectGroups[i].MarkedForDelete = &MarkedForDelete
-		}
-		for i := len(nsxProjectShares) - 1; i >= 0; i-- {
-			nsxProjectShares[i].MarkedForDelete = &MarkedForDelete
-		}
+	for i := len(nsxProjectGroups) - 1; i >= 0; i-- {
+		nsxProjectGroups[i].MarkedForDelete = &MarkedForDelete
+	}
+	for i := len(nsxProjectShares) - 1; i >= 0; i-- {
+		nsxProjectShares[i].MarkedForDelete = &MarkedForDelete
+	}
 
-		// 1.Wrap project groups and shares into project child infra.
-		var projectInfra []*data.StructValue
-		if len(nsxProjectShares) != 0 || len(nsxProjectGroups) != 0 {
-			projectInfra, err = service.wrapHierarchyProjectResources(nsxProjectShares, nsxProjectGroups)
-			if err != nil {
-				log.Error(err, "failed to wrap project groups and shares")
-				return err
-			}
+	// Delete SecurityPolicy together with groups, rules, as well as project groups and shares if any, on the backend.
+	err = backend.Realize(spNameSpace, &nsxSecurityPolicy, nsxGroups, nsxProjectGroups, nsxProjectShares)
+	if err != nil {
+		// NSX returns not found when a parent of the objects, e.g. the domain or VPC, is already deleted
+		// together with the objects, so the objects are only removed from the stores.
+		if !nsxutil.IsNotFound(err) {
+			log.Error(err, "failed to delete SecurityPolicy", "backend", backend.Name())
+			return err
 		}
+		log.Info("NSX objects of SecurityPolicy are already deleted, removing them from store", "nsxSecurityPolicyUID", spUID, "error", err.Error())
+	}
 
-		// 2.Wrap SecurityPolicy, groups, rules under VPC level together with project groups and shares into one hierarchy resource tree.
-		orgRoot, err := service.WrapHierarchyVpcSecurityPolicy(nsxSecurityPolicy, *nsxGroups, projectInfra, vpcInfo)
+	if len(nsxProjectGroups) != 0 {
+		err = projectGroupStore.Apply(&nsxProjectGroups)
 		if err != nil {
-			log.Error(err, "failed to wrap SecurityPolicy in VPC")
+			log.Error(err, "failed to apply store", "nsxProjectGroups", nsxProjectGroups)
 			return err
 		}
-
-		// 3.Create/update SecurityPolicy together with groups, rules under VPC level and project groups, shares.
-		err = service.NSXClient.OrgRootClient.Patch(*orgRoot, &EnforceRevisionCheckParam)
+	}
+	if len(nsxProjectShares) != 0 {
+		err = shareStore.Apply(&nsxProjectShares)
 		if err != nil {
-			log.Error(err, "failed to delete SecurityPolicy in VPC")
+			log.Error(err, "failed to apply store", "nsxProjectShares", nsxProjectShares)
 			return err
 		}
+	}
 
-		if len(nsxProjectGroups) != 0 {
-			err = projectGroupStore.Apply(&nsxProjectGroups)
-			if err != nil {
-				log.Error(err, "failed to apply store", "nsxProjectGroups", nsxProjectGroups)
-				return err
-			}
-		}
-
-		if len(nsxProjectShares) != 0 {
-			err = shareStore.Apply(&nsxProjectShares)
-			if err != nil {
-				log.Error(err, "failed to apply store", "nsxProjectShares", nsxProjectShares)
-				return err
-			}
-		}
-	} else {
-		infraSecurityPolicy, err := service.WrapHierarchySecurityPolicy(nsxSecurityPolicy, *nsxGroups)
+	if policyExists {
+		err = securityPolicyStore.Apply(&finalSecurityPolicyCopy)
 		if err != nil {
-			log.Error(err, "failed to wrap SecurityPolicy")
+			log.Error(err, "failed to apply store", "securityPolicy", finalSecurityPolicyCopy)
 			return err
 		}
-		err = service.NSXClient.InfraClient.Patch(*infraSecurityPolicy, &EnforceRevisionCheckParam)
+		err = ruleStore.Apply(&finalSecurityPolicyCopy)
 		if err != nil {
-			log.Error(err, "failed to delete SecurityPolicy")
+			log.Error(err, "failed to apply store", "nsxRules", finalSecurityPolicyCopy.Rules)
 			return err
 		}
+		// The shared groups are deleted after the last rules referring to them.
+		service.releaseSharedGroups(nil)
 	}
+	err = groupStore.Apply(&nsxGroups)
 	if err != nil {
+		log.Error(err, "failed to apply store", "nsxGroups", nsxGroups)
 		return err
 	}
-
-	err = securityPolicyStore.Apply(&finalSecurityPolicyCopy)
-	if err != nil {
-		log.Error(err, "failed to apply store", "securityPolicy", finalSecurityPolicyCopy)
-		return err
-	}
-	err = ruleStore.Apply(&finalSecurityPolicyCopy)
-	if err != nil {
-		log.Error(err, "failed to apply store", "nsxRules", finalSecurityPolicyCopy.Rules)
-		return err
-	}
-	err = groupStore.Apply(nsxGroups)
-	if err != nil {
-		log.Error(err, "failed to apply store", "nsxGroups", nsxGroups)
+	// The context profiles are deleted after the rules referring to them.
+	if err := service.deleteContextProfiles(indexScope, spUID); err != nil {
 		return err
 	}
 
@@ -745,25 +1058,15 @@ func (service *SecurityPolicyService) deleteSecurityPolicy(obj interface{}, isVp
 }
 
 func (service *SecurityPolicyService) createOrUpdateGroups(obj *v1alpha1.SecurityPolicy, nsxGroups []*model.Group) error {
-	var vpcInfo *common.VPCResourceInfo
-	var err error
+	backend, err := service.getBackend(obj.ObjectMeta.Namespace, false)
+	if err != nil {
+		return err
+	}
 	finalGroups := make([]model.Group, 0)
 	for _, group := range nsxGroups {
 		group.MarkedForDelete = nil
 		finalGroups = append(finalGroups, *group)
-		if isVpcEnabled(service) {
-			vpcInfo, err = service.getVpcInfo(obj.ObjectMeta.Namespace)
-			if err != nil {
-				return err
-			}
-			orgId := (*vpcInfo).OrgID
-			projectId := (*vpcInfo).ProjectID
-			vpcId := (*vpcInfo).VPCID
-
-			err = service.NSXClient.VpcGroupClient.Patch(orgId, projectId, vpcId, *group.Id, *group)
-		} else {
-			err = service.NSXClient.GroupClient.Patch(getDomain(service), *group.Id, *group)
-		}
+		err = backend.PatchGroup(obj.ObjectMeta.Namespace, group)
 	}
 
 	if err != nil {
@@ -785,8 +1088,11 @@ func (service *SecurityPolicyService) ListSecurityPolicyID() sets.Set[string] {
 	// List SecurityPolicyID to which share resources are associated in share store
 	shareSet := service.shareStore.ListIndexFuncValues(indexScope)
 	policySet := service.securityPolicyStore.ListIndexFuncValues(indexScope)
-
-	return groupSet.Union(policySet).Union(shareSet)
+	idSet := groupSet.Union(policySet).Union(shareSet)
+	if service.contextProfileStore != nil {
+		idSet = idSet.Union(service.contextProfileStore.ListIndexFuncValues(indexScope))
+	}
+	return idSet
 }
 
 func (service *SecurityPolicyService) ListNetworkPolicyID() sets.Set[string] {
@@ -829,6 +1135,84 @@ func (service *SecurityPolicyService) Cleanup(ctx context.Context) error {
 			}
 		}
 	}
+
+	// Delete all the security policies created for service exposure in store
+	uids = service.ListServiceExposureID()
+	log.Info("cleaning up security policies created for service exposure", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteSecurityPolicy(types.UID(uid), true, common.ResourceTypeServiceExposure)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete all the security policies created for admin network policy in store
+	uids = service.ListAdminNetworkPolicyID()
+	log.Info("cleaning up security policies created for admin network policy", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteSecurityPolicy(types.UID(uid), true, common.ResourceTypeAdminNetworkPolicy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete all the default deny security policies of the namespaces in store
+	uids = service.ListNamespaceDefaultDenyID()
+	log.Info("cleaning up default deny security policies of namespaces", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteSecurityPolicy(types.UID(uid), true, common.ResourceTypeNamespaceDefaultDeny)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete all the gateway policies created for GatewayPolicy in store
+	uids = service.ListGatewayPolicyID()
+	log.Info("cleaning up gateway policies", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteGatewayPolicy(types.UID(uid))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete all the IDS/IPS policies and IDS profiles created for IDSPolicy in store
+	uids = service.ListIDSPolicyID()
+	log.Info("cleaning up IDS policies", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteIDSPolicy(types.UID(uid))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete all the shared groups, no rule refers to them any more
+	service.releaseSharedGroups(nil)
 	return nil
 }
 
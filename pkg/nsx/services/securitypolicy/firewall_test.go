@@ -8,16 +8,21 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
 var (
@@ -367,3 +372,158 @@ func TestListSecurityPolicyID(t *testing.T) {
 		})
 	}
 }
+
+// taggedQueryClient returns the groups for the search of groups
+type taggedQueryClient struct {
+	groups []model.Group
+}
+
+func (c *taggedQueryClient) List(query string, _ *string, _ *string, _ *int64, _ *bool, _ *string) (model.SearchResponse, error) {
+	var results []*data.StructValue
+	if strings.HasPrefix(query, "resource_type:Group") {
+		for _, group := range c.groups {
+			dataValue, _ := NewConverter().ConvertToVapi(group, model.GroupBindingType())
+			results = append(results, dataValue.(*data.StructValue))
+		}
+	}
+	resultCount := int64(len(results))
+	return model.SearchResponse{Results: results, ResultCount: &resultCount}, nil
+}
+
+func newDeleteTestService(queryClient *taggedQueryClient) *SecurityPolicyService {
+	cf := &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"}}
+	service := &SecurityPolicyService{
+		Service: common.Service{
+			NSXClient: &nsx.Client{QueryClient: queryClient, NsxConfig: cf},
+			NSXConfig: cf,
+		},
+	}
+	indexers := cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}
+	service.securityPolicyStore = &SecurityPolicyStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.SecurityPolicyBindingType(),
+	}}
+	service.groupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.GroupBindingType(),
+	}}
+	service.ruleStore = &RuleStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.RuleBindingType(),
+	}}
+	service.shareStore = &ShareStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.ShareBindingType(),
+	}}
+	return service
+}
+
+type notFoundBackend struct {
+	fakeBackend
+	realized *model.SecurityPolicy
+}
+
+func (b *notFoundBackend) Realize(_ string, sp *model.SecurityPolicy, _ []model.Group, _ []model.Group, _ []model.Share) error {
+	b.realized = sp
+	return nsxutil.CreateResourceNotFound("10.0.0.1", "patch")
+}
+
+func TestDeleteSecurityPolicyAlreadyDeleted(t *testing.T) {
+	service := newDeleteTestService(&taggedQueryClient{})
+	backend := &notFoundBackend{}
+	registerTestBackend(t, "notfound", func(s *SecurityPolicyService) EnforcementBackend { return backend })
+	service.SetBackendSelector(func(_ string) string { return "notfound" })
+
+	uid, policyID, ruleID, groupID := "sp-uid", "sp-id", "rule-id", "group-id"
+	tags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid}}
+	service.securityPolicyStore.Add(&model.SecurityPolicy{Id: &policyID, Tags: tags})
+	service.ruleStore.Add(&model.Rule{Id: &ruleID, Tags: tags})
+	service.groupStore.Add(&model.Group{Id: &groupID, Tags: tags})
+
+	// the spec can't be built, it is not used to delete the NSX objects
+	obj := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spA", UID: types.UID(uid)},
+		Spec:       v1alpha1.SecurityPolicySpec{Rules: []v1alpha1.SecurityPolicyRule{{Action: &allowAction}}},
+	}
+	err := service.DeleteSecurityPolicy(obj, false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, policyID, *backend.realized.Id)
+	assert.Empty(t, service.securityPolicyStore.ListKeys())
+	assert.Empty(t, service.ruleStore.ListKeys())
+	assert.Empty(t, service.groupStore.ListKeys())
+
+	// nothing left in store
+	backend.realized = nil
+	err = service.DeleteSecurityPolicy(types.UID(uid), false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Nil(t, backend.realized)
+}
+
+func TestDeleteSecurityPolicyOrphanGroups(t *testing.T) {
+	uid, groupID, ns := "sp-uid", "group-id", "ns1"
+	tags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid}, {Scope: &tagScopeNamespace, Tag: &ns}}
+	service := newDeleteTestService(&taggedQueryClient{groups: []model.Group{{Id: &groupID, Tags: tags}}})
+	backend := &fakeGroupsBackend{}
+	registerTestBackend(t, "groups", func(s *SecurityPolicyService) EnforcementBackend { return backend })
+	service.SetBackendSelector(func(_ string) string { return "groups" })
+
+	// the SecurityPolicy is missing in NSX and store, the orphan group is searched from NSX
+	err := service.DeleteSecurityPolicy(types.UID(uid), false, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, ns, backend.namespace)
+	assert.Equal(t, 1, len(backend.groups))
+	assert.Equal(t, groupID, *backend.groups[0].Id)
+	assert.True(t, *backend.groups[0].MarkedForDelete)
+	assert.True(t, isSecurityPolicyReference(backend.policy))
+	assert.Empty(t, service.groupStore.ListKeys())
+}
+
+func TestScanOrphans(t *testing.T) {
+	uid, orphanUID := "sp-uid", "orphan-uid"
+	stored := "stored"
+	tags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid}}
+	orphanTags := []model.Tag{{Scope: &tagScopeSecurityPolicyCRUID, Tag: &orphanUID}}
+	service := newDeleteTestService(&taggedQueryClient{groups: []model.Group{
+		{Id: String("group-1"), Tags: tags},
+		{Id: String("group-2"), Tags: orphanTags},
+	}})
+	service.groupStore.Add(&model.Group{Id: String("group-1"), Tags: tags, Description: &stored})
+
+	found, err := service.ScanOrphans()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, found)
+	// the objects in store are kept, the missing ones are added for the GC to collect
+	assert.Equal(t, stored, *service.groupStore.GetByKey("group-1").(*model.Group).Description)
+	assert.Equal(t, sets.New[string](uid, orphanUID), service.ListSecurityPolicyID())
+
+	found, err = service.ScanOrphans()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, found)
+}
+
+type fakeGroupsBackend struct {
+	fakeBackend
+	namespace string
+	policy    *model.SecurityPolicy
+	groups    []model.Group
+}
+
+func (b *fakeGroupsBackend) Realize(namespace string, sp *model.SecurityPolicy, groups []model.Group, _ []model.Group, _ []model.Share) error {
+	b.namespace, b.policy, b.groups = namespace, sp, groups
+	return nil
+}
+
+func TestCountRulesByAction(t *testing.T) {
+	service := newDeleteTestService(&taggedQueryClient{})
+	ns1, ns2 := "ns1", "ns2"
+	rules := []model.Rule{
+		{Id: String("rule-1"), Action: String("ALLOW"), Direction: String("IN"), Tags: []model.Tag{{Scope: &tagScopeNamespace, Tag: &ns1}}},
+		{Id: String("rule-2"), Action: String("DROP"), Direction: String("IN_OUT"), Tags: []model.Tag{{Scope: &tagScopeNamespace, Tag: &ns1}}},
+		{Id: String("rule-3"), Action: String("DROP"), Direction: String("IN_OUT"), Tags: []model.Tag{{Scope: &tagScopeNamespace, Tag: &ns1}}},
+		{Id: String("rule-4"), Action: String("REJECT"), Direction: String("OUT"), Tags: []model.Tag{{Scope: &tagScopeNamespace, Tag: &ns2}}},
+	}
+	for i := range rules {
+		service.ruleStore.Add(&rules[i])
+	}
+	assert.Equal(t, map[RuleCountKey]int{
+		{Namespace: "ns1", Action: "allow", Direction: "in"}:    1,
+		{Namespace: "ns1", Action: "drop", Direction: "in_out"}: 2,
+		{Namespace: "ns2", Action: "reject", Direction: "out"}:  1,
+	}, service.CountRulesByAction())
+}
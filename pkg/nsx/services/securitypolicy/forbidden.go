@@ -0,0 +1,182 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+const maxPort = 65535
+
+// ValidateForbiddenRules checks the directions, CIDRs and port ranges of the forbidden rules.
+func ValidateForbiddenRules(forbiddenRules []v1alpha1.ForbiddenRule) error {
+	var errs []error
+	for i := range forbiddenRules {
+		forbidden := &forbiddenRules[i]
+		name := forbiddenRuleName(forbidden, i)
+		if forbidden.Direction != "" {
+			if _, err := getRuleDirection(&v1alpha1.SecurityPolicyRule{Direction: &forbidden.Direction}); err != nil {
+				errs = append(errs, fmt.Errorf("forbidden rule %s: %w", name, err))
+			}
+		}
+		if forbidden.CIDR != "" {
+			if _, _, err := net.ParseCIDR(forbidden.CIDR); err != nil {
+				errs = append(errs, fmt.Errorf("forbidden rule %s: %w", name, err))
+			}
+		}
+		if forbidden.Port < 0 || forbidden.Port > maxPort || forbidden.EndPort < 0 || forbidden.EndPort > maxPort {
+			errs = append(errs, fmt.Errorf("forbidden rule %s: port must be between 0 and %d", name, maxPort))
+		} else if forbidden.EndPort != 0 && (forbidden.Port == 0 || forbidden.EndPort < forbidden.Port) {
+			errs = append(errs, fmt.Errorf("forbidden rule %s: endPort must not be less than port", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckForbiddenRules rejects the SecurityPolicy if any of its allow rules allows the traffic described
+// by the forbidden rules. The named ports are resolved only on realization, so they are not checked.
+func CheckForbiddenRules(obj *v1alpha1.SecurityPolicy, forbiddenRules []v1alpha1.ForbiddenRule) error {
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		if rule.Action == nil || *rule.Action != v1alpha1.RuleActionAllow {
+			continue
+		}
+		for i := range forbiddenRules {
+			forbidden := &forbiddenRules[i]
+			allowed, err := allowsForbiddenTraffic(rule, forbidden)
+			if err != nil {
+				return fmt.Errorf("forbidden rule %s: %w", forbiddenRuleName(forbidden, i), err)
+			}
+			if allowed {
+				return fmt.Errorf("rule %d %s allows the traffic forbidden by the cluster admin: forbidden rule %s",
+					ruleIdx, rule.Name, forbiddenRuleName(forbidden, i))
+			}
+		}
+	}
+	return nil
+}
+
+func forbiddenRuleName(forbidden *v1alpha1.ForbiddenRule, idx int) string {
+	if forbidden.Name != "" {
+		return forbidden.Name
+	}
+	return fmt.Sprintf("%d", idx)
+}
+
+func allowsForbiddenTraffic(rule *v1alpha1.SecurityPolicyRule, forbidden *v1alpha1.ForbiddenRule) (bool, error) {
+	ruleDirection, err := getRuleDirection(rule)
+	if err != nil {
+		return false, err
+	}
+	if forbidden.Direction != "" {
+		forbiddenDirection, err := getRuleDirection(&v1alpha1.SecurityPolicyRule{Direction: &forbidden.Direction})
+		if err != nil {
+			return false, err
+		}
+		if forbiddenDirection != ruleDirection {
+			return false, nil
+		}
+	}
+	peers := rule.Sources
+	if ruleDirection == "OUT" {
+		peers = rule.Destinations
+	}
+	covered, err := peersCoverCIDR(peers, forbidden.CIDR)
+	if err != nil || !covered {
+		return false, err
+	}
+	return portsOverlap(rule.Ports, forbidden), nil
+}
+
+// peersCoverCIDR checks if the peers cover all the IPs of the CIDR, the empty peers cover any IP while
+// the Pod and Namespace selectors don't cover a CIDR.
+func peersCoverCIDR(peers []v1alpha1.SecurityPolicyPeer, cidr string) (bool, error) {
+	if len(peers) == 0 || cidr == "" {
+		return true, nil
+	}
+	_, forbiddenNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, err
+	}
+	forbiddenOnes, forbiddenBits := forbiddenNet.Mask.Size()
+	for _, peer := range peers {
+		for _, block := range peer.IPBlocks {
+			_, blockNet, err := net.ParseCIDR(block.CIDR)
+			if err != nil {
+				return false, err
+			}
+			ones, bits := blockNet.Mask.Size()
+			if bits != forbiddenBits || ones > forbiddenOnes || !blockNet.Contains(forbiddenNet.IP) {
+				continue
+			}
+			excepted, err := exceptsOverlap(block.Except, forbiddenNet)
+			if err != nil {
+				return false, err
+			}
+			if !excepted {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// exceptsOverlap checks if any of the excepts of an IP block overlaps the network, the IP block doesn't cover
+// the network then.
+func exceptsOverlap(excepts []string, ipNet *net.IPNet) (bool, error) {
+	for _, except := range excepts {
+		_, exceptNet, err := net.ParseCIDR(except)
+		if err != nil {
+			return false, err
+		}
+		if exceptNet.Contains(ipNet.IP) || ipNet.Contains(exceptNet.IP) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// portsOverlap checks if any of the ports overlaps the protocol and port range of the forbidden rule,
+// the empty ports match any protocol and port.
+func portsOverlap(ports []v1alpha1.SecurityPolicyPort, forbidden *v1alpha1.ForbiddenRule) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		if forbidden.Protocol != "" && forbidden.Protocol != protocol {
+			continue
+		}
+		if port.Port.Type == intstr.String {
+			continue
+		}
+		start, end := portRange(port.Port.IntValue(), port.EndPort)
+		forbiddenStart, forbiddenEnd := portRange(forbidden.Port, forbidden.EndPort)
+		if start <= forbiddenEnd && forbiddenStart <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// portRange returns the range of ports, the zero port means any port.
+func portRange(port, endPort int) (int, int) {
+	if port == 0 {
+		return 0, maxPort
+	}
+	if endPort < port {
+		return port, port
+	}
+	return port, endPort
+}
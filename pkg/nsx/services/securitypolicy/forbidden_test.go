@@ -0,0 +1,122 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestValidateForbiddenRules(t *testing.T) {
+	assert.NoError(t, ValidateForbiddenRules([]v1alpha1.ForbiddenRule{
+		{CIDR: "0.0.0.0/0", Protocol: v1.ProtocolTCP, Port: 22},
+		{Direction: v1alpha1.RuleDirectionEgress, Port: 1000, EndPort: 2000},
+		{},
+	}))
+	err := ValidateForbiddenRules([]v1alpha1.ForbiddenRule{
+		{Name: "cidr", CIDR: "10.0.0.1"},
+		{Name: "direction", Direction: "Both"},
+		{Name: "range", Port: 2000, EndPort: 1000},
+		{Name: "port", Port: 70000},
+	})
+	for _, name := range []string{"cidr", "direction", "range", "port"} {
+		assert.ErrorContains(t, err, "forbidden rule "+name+":")
+	}
+}
+
+func TestCheckForbiddenRules(t *testing.T) {
+	in, out := v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionOut
+	forbiddenRules := []v1alpha1.ForbiddenRule{
+		{Name: "ssh", Direction: v1alpha1.RuleDirectionIngress, CIDR: "0.0.0.0/0", Protocol: v1.ProtocolTCP, Port: 22},
+		{Name: "smb", Direction: v1alpha1.RuleDirectionEgress, Port: 445},
+	}
+	newPolicy := func(rule v1alpha1.SecurityPolicyRule) *v1alpha1.SecurityPolicy {
+		return &v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp"},
+			Spec:       v1alpha1.SecurityPolicySpec{Rules: []v1alpha1.SecurityPolicyRule{rule}},
+		}
+	}
+	tests := []struct {
+		name      string
+		rule      v1alpha1.SecurityPolicyRule
+		forbidden string
+	}{
+		{
+			name: "ingress from any IP on port range",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Sources: []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "0.0.0.0/0"}}}},
+				Ports:   []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(20), EndPort: 30}},
+			},
+			forbidden: "ssh",
+		},
+		{
+			name:      "ingress from any peer on any port",
+			rule:      v1alpha1.SecurityPolicyRule{Action: &allowAction, Direction: &in},
+			forbidden: "ssh",
+		},
+		{
+			name: "ingress from a subnet",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Sources: []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/8"}}}},
+				Ports:   []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(22)}},
+			},
+		},
+		{
+			name: "ingress from any IP except a subnet",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Sources: []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "0.0.0.0/0", Except: []string{"10.0.0.0/8"}}}}},
+				Ports:   []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(22)}},
+			},
+		},
+		{
+			name: "ingress from Pods",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Sources: []v1alpha1.SecurityPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+			},
+		},
+		{
+			name: "ingress over UDP",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Ports: []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolUDP, Port: intstr.FromInt(22)}},
+			},
+		},
+		{
+			name: "named port",
+			rule: v1alpha1.SecurityPolicyRule{
+				Action: &allowAction, Direction: &in,
+				Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromString("ssh")}},
+			},
+		},
+		{
+			name:      "egress to Pods",
+			rule:      v1alpha1.SecurityPolicyRule{Action: &allowAction, Direction: &out, Destinations: []v1alpha1.SecurityPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+			forbidden: "smb",
+		},
+		{
+			name: "drop",
+			rule: v1alpha1.SecurityPolicyRule{Action: &allowDrop, Direction: &in},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckForbiddenRules(newPolicy(tt.rule), forbiddenRules)
+			if tt.forbidden == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, "forbidden rule "+tt.forbidden)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// The FQDN destinations of a rule are matched by the DOMAIN_NAME attribute of the context profile of the rule,
+// the IPs of the domain names are learnt by NSX from the DNS responses. The destination group of the rule is ANY,
+// so the traffic to the domain names is matched by the context profile only.
+
+func hasOtherPeerFields(peer *v1alpha1.SecurityPolicyPeer) bool {
+	return peer.VMSelector != nil || peer.PodSelector != nil || peer.NamespaceSelector != nil || len(peer.IPBlocks) > 0 ||
+		len(peer.Networks) > 0 || len(peer.IdentityGroups) > 0 || len(peer.Workloads) > 0
+}
+
+// ruleFQDNs returns the sorted FQDNs of the rule destinations.
+func ruleFQDNs(rule *v1alpha1.SecurityPolicyRule) []string {
+	var fqdns []string
+	for _, peer := range rule.Destinations {
+		if peer.FQDN != "" {
+			fqdns = append(fqdns, peer.FQDN)
+		}
+	}
+	sort.Strings(fqdns)
+	return fqdns
+}
+
+// ValidateFQDN rejects the FQDNs in the rule sources, and the FQDN destinations mixed with the other
+// destinations, since the context profile applies to the whole rule. The redirection rules have no context
+// profile, so the FQDNs are not allowed in the Redirect rules either.
+func ValidateFQDN(obj *v1alpha1.SecurityPolicy) error {
+	for i, rule := range obj.Spec.Rules {
+		for j, peer := range rule.Sources {
+			if peer.FQDN != "" {
+				return fmt.Errorf("spec.rules[%d].sources[%d].fqdn is not allowed, FQDNs are for rule destinations only", i, j)
+			}
+		}
+		fqdns := 0
+		for j := range rule.Destinations {
+			peer := &rule.Destinations[j]
+			if peer.FQDN == "" {
+				continue
+			}
+			if hasOtherPeerFields(peer) {
+				return fmt.Errorf("spec.rules[%d].destinations[%d].fqdn can't be used with the other fields of the peer", i, j)
+			}
+			fqdns++
+		}
+		if fqdns > 0 && fqdns != len(rule.Destinations) {
+			return fmt.Errorf("spec.rules[%d].destinations can't mix FQDNs with the other destinations", i)
+		}
+		if fqdns > 0 && isRedirectRule(&rule) {
+			return fmt.Errorf("spec.rules[%d].destinations can't have FQDNs with the Redirect action", i)
+		}
+	}
+	return nil
+}
+
+// stripFQDNDestinations returns a copy of the rule without the FQDN destinations, so its destination group is ANY.
+// The FQDN destinations can't be mixed with the other destinations, the rule is returned as is if it has none.
+func stripFQDNDestinations(rule *v1alpha1.SecurityPolicyRule) *v1alpha1.SecurityPolicyRule {
+	if len(ruleFQDNs(rule)) == 0 {
+		return rule
+	}
+	ruleCopy := *rule
+	ruleCopy.Destinations = nil
+	return &ruleCopy
+}
@@ -0,0 +1,85 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func spWithFQDNs(fqdns ...string) *v1alpha1.SecurityPolicy {
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules = sp.Spec.Rules[:1]
+	sp.Spec.Rules[0].Direction = &directionOut
+	sp.Spec.Rules[0].Destinations = nil
+	for _, fqdn := range fqdns {
+		sp.Spec.Rules[0].Destinations = append(sp.Spec.Rules[0].Destinations, v1alpha1.SecurityPolicyPeer{FQDN: fqdn})
+	}
+	return sp
+}
+
+func TestSecurityPolicyService_FQDN(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	patches.ApplyPrivateMethod(reflect.TypeOf(s), "getVpcInfo",
+		func(s *SecurityPolicyService, ns string) (*common.VPCResourceInfo, error) {
+			return &common.VPCResourceInfo{OrgID: "default", ProjectID: "project1", VPCID: "vpc1"}, nil
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	sp := spWithFQDNs("www.example.com", "*.example.org")
+
+	// the rule matches any destination, the domain names are matched by the context profile
+	nsxSecurityPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	profiles := service.buildContextProfiles(sp, common.ResourceTypeSecurityPolicy)
+	assert.Len(t, profiles, 1)
+	assert.Equal(t, []string{"*.example.org", "www.example.com"}, profiles[0].Attributes[0].Value)
+	assert.Equal(t, model.PolicyAttributes_KEY_DOMAIN_NAME, *profiles[0].Attributes[0].Key)
+	for _, rule := range nsxSecurityPolicy.Rules {
+		assert.Equal(t, []string{"ANY"}, rule.DestinationGroups)
+		assert.Equal(t, []string{"/infra/context-profiles/" + *profiles[0].Id}, rule.Profiles)
+		assert.True(t, strings.HasPrefix(*rule.Id, strings.TrimSuffix(*profiles[0].Id, "profile")))
+	}
+
+	// the context profile is kept when the FQDNs are changed
+	changedProfiles := service.buildContextProfiles(spWithFQDNs("www.example.com"), common.ResourceTypeSecurityPolicy)
+	assert.Equal(t, *profiles[0].Id, *changedProfiles[0].Id)
+
+	// the FQDN destinations are not supported with VPC
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+	_, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.ErrorContains(t, err, "the App IDs and the FQDN destinations are not supported in VPC mode")
+}
+
+func TestValidateFQDN(t *testing.T) {
+	sp := spWithFQDNs("www.example.com")
+	assert.NoError(t, ValidateFQDN(sp))
+
+	sp.Spec.Rules[0].Destinations[0].PodSelector = &metav1.LabelSelector{}
+	assert.EqualError(t, ValidateFQDN(sp), "spec.rules[0].destinations[0].fqdn can't be used with the other fields of the peer")
+
+	sp.Spec.Rules[0].Destinations[0].PodSelector = nil
+	sp.Spec.Rules[0].Destinations = append(sp.Spec.Rules[0].Destinations, v1alpha1.SecurityPolicyPeer{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/8"}}})
+	assert.EqualError(t, ValidateFQDN(sp), "spec.rules[0].destinations can't mix FQDNs with the other destinations")
+
+	sp = spWithFQDNs()
+	sp.Spec.Rules[0].Sources = []v1alpha1.SecurityPolicyPeer{{FQDN: "www.example.com"}}
+	assert.EqualError(t, ValidateFQDN(sp), "spec.rules[0].sources[0].fqdn is not allowed, FQDNs are for rule destinations only")
+}
@@ -0,0 +1,259 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// The GatewayPolicies are realized as the NSX gateway policies in the domain of the cluster, which enforce the
+// north-south traffic on the Tier-0 or Tier-1 gateways. The rules and their peer groups are built like the ones
+// of an internal SecurityPolicy, then the rules are scoped to the gateways instead of the groups of workloads.
+// The rules and groups share the stores of the SecurityPolicies, they're indexed by the GatewayPolicy UID.
+
+// gatewayPolicyCategory is the category of the gateway policies created in the user domains.
+const gatewayPolicyCategory = "LocalGatewayRules"
+
+var gatewayPathRegex = regexp.MustCompile(`^/infra/tier-[01]s/[^/]+$`)
+
+// ValidateGatewayPolicy rejects the GatewayPolicy with the paths which are not Tier-0 or Tier-1 gateways, and
+// the rules with the fields which only make sense for the DFW rules.
+func ValidateGatewayPolicy(obj *v1alpha1.GatewayPolicy) error {
+	if len(obj.Spec.Gateways) == 0 {
+		return fmt.Errorf("spec.gateways is required")
+	}
+	for i, gateway := range obj.Spec.Gateways {
+		if !gatewayPathRegex.MatchString(gateway) {
+			return fmt.Errorf("spec.gateways[%d] %s is not the path of a Tier-0 or Tier-1 gateway", i, gateway)
+		}
+	}
+	for i := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[i]
+		if len(rule.AppliedTo) > 0 {
+			return fmt.Errorf("spec.rules[%d].appliedTo is not supported by the gateway rules", i)
+		}
+		if rule.Action != nil {
+			action := util.ToUpper(*rule.Action)
+			if action == util.ToUpper(v1alpha1.RuleActionPass) || action == util.ToUpper(v1alpha1.RuleActionRedirect) {
+				return fmt.Errorf("spec.rules[%d].action %s is not supported by the gateway rules", i, *rule.Action)
+			}
+		}
+		if rule.RedirectTo != "" {
+			return fmt.Errorf("spec.rules[%d].redirectTo is not supported by the gateway rules", i)
+		}
+		if len(rule.AppIDs) > 0 {
+			return fmt.Errorf("spec.rules[%d].appIds is not supported by the gateway rules", i)
+		}
+		for j, port := range rule.Ports {
+			if port.Port.Type != intstr.Int {
+				return fmt.Errorf("spec.rules[%d].ports[%d] named port %s is not supported by the gateway rules", i, j, port.Port.StrVal)
+			}
+		}
+		for _, peers := range [][]v1alpha1.SecurityPolicyPeer{rule.Sources, rule.Destinations} {
+			for _, peer := range peers {
+				if len(peer.Workloads) > 0 {
+					return fmt.Errorf("spec.rules[%d] workloads of the peers are not supported by the gateway rules", i)
+				}
+				if peer.FQDN != "" {
+					return fmt.Errorf("spec.rules[%d] FQDN of the peers is not supported by the gateway rules", i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// buildGatewayPolicy builds the NSX gateway policy with rules and the groups of the rule peers from the
+// GatewayPolicy CR.
+func (service *SecurityPolicyService) buildGatewayPolicy(obj *v1alpha1.GatewayPolicy) (*model.GatewayPolicy, []model.Group, error) {
+	internalSecurityPolicy := &v1alpha1.SecurityPolicy{
+		ObjectMeta: obj.ObjectMeta,
+		Spec: v1alpha1.SecurityPolicySpec{
+			Priority: obj.Spec.Priority,
+			Rules:    obj.Spec.Rules,
+		},
+	}
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(internalSecurityPolicy, common.ResourceTypeGatewayPolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+	scope := append([]string{}, obj.Spec.Gateways...)
+	for i := range nsxSecurityPolicy.Rules {
+		nsxSecurityPolicy.Rules[i].Scope = scope
+	}
+	nsxGatewayPolicy := &model.GatewayPolicy{
+		Id:             nsxSecurityPolicy.Id,
+		DisplayName:    nsxSecurityPolicy.DisplayName,
+		SequenceNumber: nsxSecurityPolicy.SequenceNumber,
+		Category:       String(gatewayPolicyCategory),
+		Scope:          scope,
+		Rules:          nsxSecurityPolicy.Rules,
+		Tags:           nsxSecurityPolicy.Tags,
+	}
+	log.V(1).Info("built nsxGatewayPolicy", "nsxGatewayPolicy", nsxGatewayPolicy, "nsxGroups", nsxGroups)
+	return nsxGatewayPolicy, *nsxGroups, nil
+}
+
+// gatewayPolicyReference returns a GatewayPolicy which only refers to the existing one as the parent of the
+// changed and stale rules in the hierarchical patch.
+func gatewayPolicyReference(id *string) *model.GatewayPolicy {
+	return &model.GatewayPolicy{
+		Id:           id,
+		ResourceType: &common.ResourceTypeChildResourceReference,
+	}
+}
+
+func (service *SecurityPolicyService) CreateOrUpdateGatewayPolicy(obj *v1alpha1.GatewayPolicy) error {
+	if service.gatewayPolicyStore == nil {
+		return nsxutil.RestrictionError{Desc: "the GatewayPolicies are not supported in VPC mode"}
+	}
+	if err := ValidateGatewayPolicy(obj); err != nil {
+		return nsxutil.RestrictionError{Desc: err.Error()}
+	}
+	nsxGatewayPolicy, nsxGroups, err := service.buildGatewayPolicy(obj)
+	if err != nil {
+		log.Error(err, "failed to build GatewayPolicy")
+		return err
+	}
+
+	indexScope := common.TagScopeGatewayPolicyCRUID
+	existingGatewayPolicy := service.gatewayPolicyStore.GetByKey(*nsxGatewayPolicy.Id)
+	existingRules := service.ruleStore.GetByIndex(indexScope, string(obj.UID))
+	existingGroups := service.groupStore.GetByIndex(indexScope, string(obj.UID))
+
+	isChanged := true
+	if existingGatewayPolicy != nil {
+		isChanged = common.CompareResource((*GatewayPolicy)(existingGatewayPolicy), (*GatewayPolicy)(nsxGatewayPolicy))
+	}
+	changed, stale := common.CompareResources(RulesPtrToComparable(existingRules), RulesToComparable(nsxGatewayPolicy.Rules))
+	changedRules, staleRules := ComparableToRules(changed), ComparableToRules(stale)
+	changed, stale = common.CompareResources(GroupsPtrToComparable(existingGroups), GroupsToComparable(nsxGroups))
+	changedGroups, staleGroups := ComparableToGroups(changed), ComparableToGroups(stale)
+
+	if !isChanged && len(changedRules) == 0 && len(staleRules) == 0 && len(changedGroups) == 0 && len(staleGroups) == 0 {
+		log.Info("gatewayPolicy, rules and groups are not changed, skip updating them", "nsxGatewayPolicy.Id", nsxGatewayPolicy.Id)
+		return nil
+	}
+	if err := reserveCapacity(existingGatewayPolicy == nil, len(nsxGatewayPolicy.Rules), len(existingRules), len(nsxGroups), len(existingGroups), false); err != nil {
+		log.Error(err, "refuse to patch the GatewayPolicy under NSX capacity pressure", "nsxGatewayPolicy.Id", nsxGatewayPolicy.Id)
+		return err
+	}
+
+	finalGatewayPolicy := nsxGatewayPolicy
+	if !isChanged {
+		finalGatewayPolicy = gatewayPolicyReference(existingGatewayPolicy.Id)
+	}
+	for i := range staleRules {
+		staleRules[i].MarkedForDelete = &MarkedForDelete
+	}
+	finalGatewayPolicy.Rules = append(staleRules, changedRules...)
+	for i := range staleGroups {
+		staleGroups[i].MarkedForDelete = &MarkedForDelete
+	}
+	finalGroups := append(staleGroups, changedGroups...)
+
+	if err := service.patchGatewayPolicy(finalGatewayPolicy, finalGroups); err != nil {
+		return err
+	}
+	log.Info("successfully created or updated nsx GatewayPolicy", "nsxGatewayPolicy", finalGatewayPolicy)
+	return nil
+}
+
+// DeleteGatewayPolicy deletes the NSX gateway policy with rules and the groups of the GatewayPolicy CR by its UID,
+// the NSX resources are collected from the stores.
+func (service *SecurityPolicyService) DeleteGatewayPolicy(uid types.UID) error {
+	if service.gatewayPolicyStore == nil {
+		return nil
+	}
+	indexScope := common.TagScopeGatewayPolicyCRUID
+	existingGatewayPolicies := service.gatewayPolicyStore.GetByIndex(indexScope, string(uid))
+	existingRules := service.ruleStore.GetByIndex(indexScope, string(uid))
+	existingGroups := service.groupStore.GetByIndex(indexScope, string(uid))
+	if len(existingGatewayPolicies) == 0 && len(existingRules) == 0 && len(existingGroups) == 0 {
+		log.Info("NSX GatewayPolicy is not found in store, skip deleting it", "gatewayPolicyUID", uid)
+		return nil
+	}
+
+	var nsxGatewayPolicy *model.GatewayPolicy
+	if len(existingGatewayPolicies) > 0 {
+		// Don't modify the GatewayPolicy in store.
+		gp := *existingGatewayPolicies[0]
+		gp.MarkedForDelete = &MarkedForDelete
+		nsxGatewayPolicy = &gp
+	} else {
+		// The orphan rules are deleted under the reference of their policy.
+		nsxGatewayPolicy = gatewayPolicyReference(String(util.GenerateID(string(uid), common.GatewayPolicyPrefix, "", "")))
+	}
+	nsxGatewayPolicy.Rules = make([]model.Rule, 0, len(existingRules))
+	for _, rule := range existingRules {
+		r := *rule
+		r.MarkedForDelete = &MarkedForDelete
+		nsxGatewayPolicy.Rules = append(nsxGatewayPolicy.Rules, r)
+	}
+	nsxGroups := make([]model.Group, 0, len(existingGroups))
+	for _, group := range existingGroups {
+		g := *group
+		g.MarkedForDelete = &MarkedForDelete
+		nsxGroups = append(nsxGroups, g)
+	}
+
+	if err := service.patchGatewayPolicy(nsxGatewayPolicy, nsxGroups); err != nil {
+		return err
+	}
+	log.Info("successfully deleted nsx GatewayPolicy", "nsxGatewayPolicy", nsxGatewayPolicy)
+	return nil
+}
+
+// patchGatewayPolicy patches the gateway policy with rules and the groups by the hierarchical API, then updates
+// the stores. The NSX resources already deleted on NSX are only deleted from the stores.
+func (service *SecurityPolicyService) patchGatewayPolicy(gp *model.GatewayPolicy, groups []model.Group) error {
+	infra, err := service.WrapHierarchyGatewayPolicy(gp, groups)
+	if err != nil {
+		log.Error(err, "failed to wrap GatewayPolicy", "nsxGatewayPolicy.Id", gp.Id)
+		return err
+	}
+	err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam)
+	if err != nil && !(gp.MarkedForDelete != nil && *gp.MarkedForDelete && nsxutil.IsNotFound(err)) {
+		log.Error(err, "failed to patch GatewayPolicy", "nsxGatewayPolicy.Id", gp.Id)
+		return err
+	}
+
+	if gp.ResourceType == nil || *gp.ResourceType != common.ResourceTypeChildResourceReference {
+		if err := service.gatewayPolicyStore.Apply(gp); err != nil {
+			log.Error(err, "failed to apply store", "nsxGatewayPolicy", gp)
+			return err
+		}
+	}
+	if err := service.ruleStore.Apply(gp); err != nil {
+		log.Error(err, "failed to apply store", "nsxRules", gp.Rules)
+		return err
+	}
+	if err := service.groupStore.Apply(&groups); err != nil {
+		log.Error(err, "failed to apply store", "nsxGroups", groups)
+		return err
+	}
+	return nil
+}
+
+// ListGatewayPolicyID lists the UIDs of the GatewayPolicy CRs which the NSX resources in the stores are created for.
+func (service *SecurityPolicyService) ListGatewayPolicyID() sets.Set[string] {
+	if service.gatewayPolicyStore == nil {
+		return sets.New[string]()
+	}
+	indexScope := common.TagScopeGatewayPolicyCRUID
+	policySet := service.gatewayPolicyStore.ListIndexFuncValues(indexScope)
+	groupSet := service.groupStore.ListIndexFuncValues(indexScope)
+	return policySet.Union(groupSet)
+}
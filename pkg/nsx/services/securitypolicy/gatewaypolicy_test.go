@@ -0,0 +1,119 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_buildGatewayPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	gateways := []string{"/infra/tier-1s/t1-cluster"}
+	gp := &v1alpha1.GatewayPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "gpA", UID: "uidGP"},
+		Spec: v1alpha1.GatewayPolicySpec{
+			Gateways: gateways,
+			Priority: 5,
+			Rules:    []v1alpha1.SecurityPolicyRule{spWithPodSelector.Spec.Rules[1]},
+		},
+	}
+	assert.NoError(t, ValidateGatewayPolicy(gp))
+
+	// the rules are scoped to the gateways instead of the groups of workloads
+	nsxGatewayPolicy, nsxGroups, err := service.buildGatewayPolicy(gp)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(*nsxGatewayPolicy.Id, common.GatewayPolicyPrefix))
+	assert.Equal(t, gatewayPolicyCategory, *nsxGatewayPolicy.Category)
+	assert.Equal(t, int64(5), *nsxGatewayPolicy.SequenceNumber)
+	assert.Equal(t, gateways, nsxGatewayPolicy.Scope)
+	assert.Len(t, nsxGatewayPolicy.Rules, 1)
+	assert.Equal(t, gateways, nsxGatewayPolicy.Rules[0].Scope)
+	assert.Len(t, nsxGroups, 1)
+	for _, tags := range [][]string{
+		filterTag(nsxGatewayPolicy.Tags, common.TagScopeGatewayPolicyCRUID),
+		filterTag(nsxGatewayPolicy.Rules[0].Tags, common.TagScopeGatewayPolicyCRUID),
+		filterTag(nsxGroups[0].Tags, common.TagScopeGatewayPolicyCRUID),
+	} {
+		assert.Equal(t, []string{"uidGP"}, tags)
+	}
+
+	_, err = service.WrapHierarchyGatewayPolicy(nsxGatewayPolicy, nsxGroups)
+	assert.NoError(t, err)
+	_, err = service.WrapHierarchyGatewayPolicy(gatewayPolicyReference(nsxGatewayPolicy.Id), nsxGroups)
+	assert.NoError(t, err)
+}
+
+func TestValidateGatewayPolicy(t *testing.T) {
+	pass := v1alpha1.RuleActionPass
+	tests := []struct {
+		name    string
+		mutate  func(gp *v1alpha1.GatewayPolicy)
+		wantErr string
+	}{
+		{
+			name:    "no gateways",
+			mutate:  func(gp *v1alpha1.GatewayPolicy) { gp.Spec.Gateways = nil },
+			wantErr: "spec.gateways is required",
+		},
+		{
+			name:    "not a gateway",
+			mutate:  func(gp *v1alpha1.GatewayPolicy) { gp.Spec.Gateways = []string{"/infra/segments/s1"} },
+			wantErr: "spec.gateways[0] /infra/segments/s1 is not the path of a Tier-0 or Tier-1 gateway",
+		},
+		{
+			name: "appliedTo",
+			mutate: func(gp *v1alpha1.GatewayPolicy) {
+				gp.Spec.Rules[0].AppliedTo = spWithPodSelector.Spec.Rules[0].AppliedTo
+			},
+			wantErr: "spec.rules[0].appliedTo is not supported by the gateway rules",
+		},
+		{
+			name:    "Pass action",
+			mutate:  func(gp *v1alpha1.GatewayPolicy) { gp.Spec.Rules[0].Action = &pass },
+			wantErr: "spec.rules[0].action Pass is not supported by the gateway rules",
+		},
+		{
+			name: "named port",
+			mutate: func(gp *v1alpha1.GatewayPolicy) {
+				gp.Spec.Rules[0].Ports = []v1alpha1.SecurityPolicyPort{{Port: intstr.FromString("http")}}
+			},
+			wantErr: "spec.rules[0].ports[0] named port http is not supported by the gateway rules",
+		},
+		{
+			name: "FQDN",
+			mutate: func(gp *v1alpha1.GatewayPolicy) {
+				gp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{{FQDN: "example.com"}}
+			},
+			wantErr: "spec.rules[0] FQDN of the peers is not supported by the gateway rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gp := &v1alpha1.GatewayPolicy{Spec: v1alpha1.GatewayPolicySpec{
+				Gateways: []string{"/infra/tier-0s/t0"},
+				Rules:    []v1alpha1.SecurityPolicyRule{*spWithPodSelector.Spec.Rules[1].DeepCopy()},
+			}}
+			tt.mutate(gp)
+			assert.EqualError(t, ValidateGatewayPolicy(gp), tt.wantErr)
+		})
+	}
+}
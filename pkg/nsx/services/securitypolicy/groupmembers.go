@@ -0,0 +1,159 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The effective members of the NSX groups are evaluated by NSX from the group criteria, e.g. the Pods and the VMs
+// matching the tags, so they're read from NSX to show why a workload is or isn't selected by a SecurityPolicy. Only
+// the first page of the IP addresses and the ports of each group is read, with the total counts, so the summary
+// stays bounded whatever the size of the groups.
+
+// maxGroupMembers is the max count of the IP addresses and the ports of a group read from NSX and reported.
+const maxGroupMembers = 20
+
+// groupRef is an NSX group referred to by a SecurityPolicy and where it's referred to.
+type groupRef struct {
+	path  string
+	roles []string
+}
+
+// CollectGroupMembers reads the effective members of the NSX groups the SecurityPolicy refers to from NSX, i.e. the
+// groups of the policy and the rule scopes and the rule peers. It returns nil if the SecurityPolicy is not realized.
+// The groups whose members can't be read are reported with the error.
+func (service *SecurityPolicyService) CollectGroupMembers(obj *v1alpha1.SecurityPolicy) ([]v1alpha1.GroupMembers, error) {
+	securityPolicyStore, ruleStore, _, _, _ := service.getStores()
+	policies := securityPolicyStore.GetByIndex(common.TagValueScopeSecurityPolicyUID, string(obj.UID))
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	refs := groupRefsOf(string(obj.UID), policies[0], ruleStore.GetByIndex(common.TagValueScopeSecurityPolicyUID, string(obj.UID)))
+	members := make([]v1alpha1.GroupMembers, 0, len(refs))
+	for _, ref := range refs {
+		member := v1alpha1.GroupMembers{Group: ref.path[strings.LastIndex(ref.path, "/")+1:], Roles: ref.roles}
+		if err := service.readGroupMembers(ref.path, &member); err != nil {
+			member.Error = err.Error()
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// groupRefsOf returns the groups the NSX SecurityPolicy and its rules refer to, sorted by the path. The roles of a
+// group refer to the rules of the spec by the index in the NSX rule ID.
+func groupRefsOf(uid string, policy *model.SecurityPolicy, rules []*model.Rule) []groupRef {
+	roles := map[string][]string{}
+	add := func(role string, paths []string) {
+		for _, path := range paths {
+			if path == "ANY" || path == "" {
+				continue
+			}
+			roles[path] = append(roles[path], role)
+		}
+	}
+	add("appliedTo", policy.Scope)
+	sort.Slice(rules, func(i, j int) bool { return *rules[i].Id < *rules[j].Id })
+	for _, rule := range rules {
+		idx, ok := ruleIndexOfID(uid, *rule.Id)
+		if !ok {
+			continue
+		}
+		add(fmt.Sprintf("rules[%d].appliedTo", idx), rule.Scope)
+		add(fmt.Sprintf("rules[%d].source", idx), rule.SourceGroups)
+		add(fmt.Sprintf("rules[%d].destination", idx), rule.DestinationGroups)
+	}
+	refs := make([]groupRef, 0, len(roles))
+	for path, pathRoles := range roles {
+		refs = append(refs, groupRef{path: path, roles: uniqueRoles(pathRoles)})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].path < refs[j].path })
+	return refs
+}
+
+// uniqueRoles removes the same role repeated by the NSX rules expanded from a rule, keeping the order.
+func uniqueRoles(roles []string) []string {
+	seen := map[string]bool{}
+	unique := roles[:0]
+	for _, role := range roles {
+		if !seen[role] {
+			seen[role] = true
+			unique = append(unique, role)
+		}
+	}
+	return unique
+}
+
+// readGroupMembers reads the first effective IP addresses and ports of the group from NSX. The groups are either in
+// the domain of the cluster or in a VPC, the groups shared by the project are not read.
+func (service *SecurityPolicyService) readGroupMembers(path string, member *v1alpha1.GroupMembers) error {
+	pageSize := int64(maxGroupMembers)
+	var ipResult model.PolicyGroupIPMembersListResult
+	var portResult model.PolicyGroupMembersListResult
+	var err error
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch {
+	case len(segments) == 5 && segments[0] == "infra" && segments[1] == "domains" && segments[3] == "groups":
+		domain, group := segments[2], segments[4]
+		ipResult, err = service.NSXClient.GroupIPMembersClient.List(domain, group, nil, nil, nil, nil, &pageSize, nil, nil)
+		if err = common.TransError(err); err != nil {
+			return err
+		}
+		portResult, err = service.NSXClient.GroupPortMembersClient.List(domain, group, nil, nil, nil, nil, &pageSize, nil, nil)
+	case len(segments) == 8 && segments[0] == "orgs" && segments[2] == "projects" && segments[4] == "vpcs" && segments[6] == "groups":
+		org, project, vpc, group := segments[1], segments[3], segments[5], segments[7]
+		ipResult, err = service.NSXClient.VPCGroupIPMembersClient.List(org, project, vpc, group, nil, nil, nil, nil, &pageSize, nil, nil)
+		if err = common.TransError(err); err != nil {
+			return err
+		}
+		portResult, err = service.NSXClient.VPCGroupPortMembersClient.List(org, project, vpc, group, nil, nil, nil, nil, &pageSize, nil, nil)
+	default:
+		return fmt.Errorf("members of group %s are not readable", path)
+	}
+	if err = common.TransError(err); err != nil {
+		return err
+	}
+	summarizeGroupMembers(ipResult, portResult, member)
+	return nil
+}
+
+// summarizeGroupMembers fills the first page of the IP addresses and the ports of the group in the summary, sorted,
+// and the total counts reported by NSX.
+func summarizeGroupMembers(ipResult model.PolicyGroupIPMembersListResult, portResult model.PolicyGroupMembersListResult, member *v1alpha1.GroupMembers) {
+	member.IPCount = int64(len(ipResult.Results))
+	if ipResult.ResultCount != nil {
+		member.IPCount = *ipResult.ResultCount
+	}
+	member.IPs = append([]string(nil), ipResult.Results...)
+	if len(member.IPs) > maxGroupMembers {
+		member.IPs = member.IPs[:maxGroupMembers]
+	}
+	sort.Strings(member.IPs)
+
+	member.PortCount = int64(len(portResult.Results))
+	if portResult.ResultCount != nil {
+		member.PortCount = *portResult.ResultCount
+	}
+	member.Ports = nil
+	for _, port := range portResult.Results {
+		if len(member.Ports) == maxGroupMembers {
+			break
+		}
+		switch {
+		case port.DisplayName != nil:
+			member.Ports = append(member.Ports, *port.DisplayName)
+		case port.Id != nil:
+			member.Ports = append(member.Ports, *port.Id)
+		}
+	}
+	sort.Strings(member.Ports)
+}
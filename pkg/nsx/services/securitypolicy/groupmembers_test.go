@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestGroupRefsOf(t *testing.T) {
+	groupPath := func(id string) string { return "/infra/domains/default/groups/" + id }
+	policy := &model.SecurityPolicy{Scope: []string{groupPath("sp-uid_scope")}}
+	rules := []*model.Rule{
+		// the rule with named ports expanded into two NSX rules
+		{Id: String("sp-uid_0_1a2b3c_8080"), SourceGroups: []string{groupPath("peer")}, DestinationGroups: []string{"ANY"}},
+		{Id: String("sp-uid_0_1a2b3c_80"), SourceGroups: []string{groupPath("peer")}, DestinationGroups: []string{"ANY"}},
+		{Id: String("sp-uid_1_4d5e6f"), Scope: []string{groupPath("sp-uid_scope")}, DestinationGroups: []string{groupPath("peer")}},
+		{Id: String("manual"), SourceGroups: []string{groupPath("other")}},
+	}
+	assert.Equal(t, []groupRef{
+		{path: groupPath("peer"), roles: []string{"rules[0].source", "rules[1].destination"}},
+		{path: groupPath("sp-uid_scope"), roles: []string{"appliedTo", "rules[1].appliedTo"}},
+	}, groupRefsOf("sp-uid", policy, rules))
+}
+
+func TestSummarizeGroupMembers(t *testing.T) {
+	var ips []string
+	for i := 0; i < maxGroupMembers+5; i++ {
+		ips = append(ips, fmt.Sprintf("10.0.0.%d", i))
+	}
+	total := int64(100)
+	ipResult := model.PolicyGroupIPMembersListResult{Results: ips, ResultCount: &total}
+	portResult := model.PolicyGroupMembersListResult{Results: []model.PolicyGroupMemberDetails{
+		{DisplayName: String("pod-b")},
+		{Id: String("port-id")},
+		{DisplayName: String("pod-a"), Id: String("port-a")},
+	}}
+	member := &v1alpha1.GroupMembers{}
+	summarizeGroupMembers(ipResult, portResult, member)
+	assert.Equal(t, int64(100), member.IPCount)
+	assert.Len(t, member.IPs, maxGroupMembers)
+	assert.Equal(t, int64(3), member.PortCount)
+	assert.Equal(t, []string{"pod-a", "pod-b", "port-id"}, member.Ports)
+}
+
+func TestSecurityPolicyService_readGroupMembers(t *testing.T) {
+	service := fakeService()
+	member := &v1alpha1.GroupMembers{}
+	err := service.readGroupMembers("/orgs/default/projects/p1/infra/domains/default/groups/g1", member)
+	assert.EqualError(t, err, "members of group /orgs/default/projects/p1/infra/domains/default/groups/g1 are not readable")
+}
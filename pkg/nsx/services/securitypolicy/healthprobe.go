@@ -0,0 +1,167 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// HealthProbeRuleName is the name of the rule injected into the policies whose targets are the backends of
+// LoadBalancer Services.
+const HealthProbeRuleName = "allow-lb-health-probe"
+
+// isLoadBalancerService returns whether the Service is a LoadBalancer Service with backend Pods.
+func isLoadBalancerService(svc *v1.Service) bool {
+	return svc.Spec.Type == v1.ServiceTypeLoadBalancer && len(svc.Spec.Selector) > 0
+}
+
+// getLBSubnetCIDR returns the CIDR of the load balancer Subnet of the VPC of the namespace, the health checks
+// and the SNAT traffic of the load balancer are from it. It's empty if the load balancer is not enabled.
+func (service *SecurityPolicyService) getLBSubnetCIDR(namespace string) (string, error) {
+	vpcInfo, err := service.getVpcInfo(namespace)
+	if err != nil {
+		return "", err
+	}
+	vpcPath := fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID)
+	vpcList := &v1alpha1.VPCList{}
+	if err := service.Client.List(context.TODO(), vpcList); err != nil {
+		return "", err
+	}
+	for _, vpc := range vpcList.Items {
+		if vpc.Status.NSXResourcePath == vpcPath {
+			return vpc.Status.LBSubnetCIDR, nil
+		}
+	}
+	return "", nil
+}
+
+// listLBBackendPorts returns the target ports of the LoadBalancer Services in the namespace of the policy which
+// have any backend Pod selected by the policy level 'Applied To'.
+func (service *SecurityPolicyService) listLBBackendPorts(obj *v1alpha1.SecurityPolicy) ([]v1alpha1.SecurityPolicyPort, error) {
+	var targetSelectors []labels.Selector
+	for _, target := range obj.Spec.AppliedTo {
+		if target.PodSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(target.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		targetSelectors = append(targetSelectors, selector)
+	}
+	if len(targetSelectors) == 0 {
+		return nil, nil
+	}
+
+	svcList := &v1.ServiceList{}
+	if err := service.Client.List(context.TODO(), svcList, client.InNamespace(obj.Namespace)); err != nil {
+		return nil, err
+	}
+	var ports []v1alpha1.SecurityPolicyPort
+	portSet := map[v1alpha1.SecurityPolicyPort]bool{}
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if !isLoadBalancerService(svc) {
+			continue
+		}
+		podList := &v1.PodList{}
+		if err := service.Client.List(context.TODO(), podList, client.InNamespace(obj.Namespace), client.MatchingLabels(svc.Spec.Selector)); err != nil {
+			return nil, err
+		}
+		if !anyPodSelected(podList.Items, targetSelectors) {
+			continue
+		}
+		for _, svcPort := range svc.Spec.Ports {
+			port := v1alpha1.SecurityPolicyPort{Protocol: svcPort.Protocol, Port: svcPort.TargetPort}
+			// The target port is the port of the Service if it's not set.
+			if port.Port.Type == intstr.Int && port.Port.IntVal == 0 {
+				port.Port = intstr.FromInt(int(svcPort.Port))
+			}
+			if !portSet[port] {
+				portSet[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports, nil
+}
+
+func anyPodSelected(pods []v1.Pod, selectors []labels.Selector) bool {
+	for _, pod := range pods {
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildHealthProbeRule builds the ingress rule allowing the traffic from the load balancer Subnet of the VPC to the
+// target ports of the LoadBalancer Services the policy targets are behind, so the health checks of the load balancer
+// are not dropped by the ingress rules of the policy. It returns nil if none of the targets is behind one.
+func (service *SecurityPolicyService) buildHealthProbeRule(obj *v1alpha1.SecurityPolicy) (*v1alpha1.SecurityPolicyRule, error) {
+	ports, err := service.listLBBackendPorts(obj)
+	if err != nil || len(ports) == 0 {
+		return nil, err
+	}
+	cidr, err := service.getLBSubnetCIDR(obj.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if cidr == "" {
+		log.V(1).Info("no load balancer Subnet found, skip the health probe rule", "namespace", obj.Namespace)
+		return nil, nil
+	}
+	action, direction := v1alpha1.RuleActionAllow, v1alpha1.RuleDirectionIn
+	return &v1alpha1.SecurityPolicyRule{
+		Name:      HealthProbeRuleName,
+		Action:    &action,
+		Direction: &direction,
+		Sources:   []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: cidr}}}},
+		Ports:     ports,
+	}, nil
+}
+
+// withHealthProbeRule returns a copy of the SecurityPolicy with the health probe rule appended to the rules if the
+// policy has ingress rules and its targets are behind LoadBalancer Services. The load balancer is managed by
+// nsx-operator with VPC only, the rules are returned as is otherwise. The policies converted from NetworkPolicy
+// get the rule in the conversion instead, the isolation policy doesn't need it.
+func (service *SecurityPolicyService) withHealthProbeRule(obj *v1alpha1.SecurityPolicy, createdFor string) (*v1alpha1.SecurityPolicy, error) {
+	if createdFor != common.ResourceTypeSecurityPolicy || !isVpcEnabled(service) || !hasIngressRule(obj) {
+		return obj, nil
+	}
+	rule, err := service.buildHealthProbeRule(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build health probe rule: %w", err)
+	}
+	if rule == nil {
+		return obj, nil
+	}
+	obj = obj.DeepCopy()
+	obj.Spec.Rules = append(obj.Spec.Rules, *rule)
+	return obj, nil
+}
+
+func hasIngressRule(obj *v1alpha1.SecurityPolicy) bool {
+	for _, rule := range obj.Spec.Rules {
+		if rule.Direction == nil {
+			continue
+		}
+		if direction, err := getRuleDirection(&rule); err == nil && direction == "IN" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_HealthProbeRule(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getVpcInfo",
+		func(s *SecurityPolicyService, ns string) (*common.VPCResourceInfo, error) {
+			return &common.VPCResourceInfo{OrgID: "default", ProjectID: "project1", VPCID: "vpc1"}, nil
+		})
+	defer patches.Reset()
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.AddToScheme(scheme)
+	objs := []runtime.Object{
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web-0", Labels: map[string]string{"app": "web"}}},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web"},
+			Spec: v1.ServiceSpec{
+				Type:     v1.ServiceTypeLoadBalancer,
+				Selector: map[string]string{"app": "web"},
+				Ports: []v1.ServicePort{
+					{Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(8080)},
+					{Protocol: v1.ProtocolTCP, Port: 443},
+				},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web-internal"},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "web"},
+				Ports:    []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 9090}},
+			},
+		},
+		&v1alpha1.VPC{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1"},
+			Status: v1alpha1.VPCStatus{
+				NSXResourcePath: "/orgs/default/projects/project1/vpcs/vpc1",
+				LBSubnetCIDR:    "100.64.0.0/24",
+			},
+		},
+	}
+	service := fakeService()
+	service.Client = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+
+	action, direction := v1alpha1.RuleActionDrop, v1alpha1.RuleDirectionIn
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+			Rules:     []v1alpha1.SecurityPolicyRule{{Name: "deny-all", Action: &action, Direction: &direction}},
+		},
+	}
+
+	// the rule allows the load balancer Subnet to the target ports of the LoadBalancer Service only
+	withRule, err := service.withHealthProbeRule(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, sp.Spec.Rules, 1)
+	assert.Len(t, withRule.Spec.Rules, 2)
+	rule := withRule.Spec.Rules[1]
+	assert.Equal(t, HealthProbeRuleName, rule.Name)
+	assert.Equal(t, []v1alpha1.IPBlock{{CIDR: "100.64.0.0/24"}}, rule.Sources[0].IPBlocks)
+	assert.Equal(t, []v1alpha1.SecurityPolicyPort{
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(8080)},
+		{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(443)},
+	}, rule.Ports)
+
+	// the rule is not needed without ingress rules or for the policies converted from NetworkPolicy
+	withRule, err = service.withHealthProbeRule(sp, common.ResourceTypeNetworkPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, withRule.Spec.Rules, 1)
+	egress := sp.DeepCopy()
+	direction = v1alpha1.RuleDirectionOut
+	egress.Spec.Rules[0].Direction = &direction
+	withRule, err = service.withHealthProbeRule(egress, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, withRule.Spec.Rules, 1)
+
+	// no rule if the targets are not behind any LoadBalancer Service
+	sp.Spec.AppliedTo[0].PodSelector.MatchLabels["app"] = "db"
+	rulePtr, err := service.buildHealthProbeRule(sp)
+	assert.NoError(t, err)
+	assert.Nil(t, rulePtr)
+}
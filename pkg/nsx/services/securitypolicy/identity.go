@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// identityGroupPathPrefix is the prefix of the paths of the NSX groups the Identity Firewall groups are in.
+const identityGroupPathPrefix = "/infra/domains/"
+
+// ValidateIdentityGroups rejects the identity groups in the rule destinations, the Identity Firewall only
+// matches the traffic from the sessions of the users, and the identity groups which are not NSX group paths.
+func ValidateIdentityGroups(obj *v1alpha1.SecurityPolicy) error {
+	for i, rule := range obj.Spec.Rules {
+		for j, peer := range rule.Destinations {
+			if len(peer.IdentityGroups) > 0 {
+				return fmt.Errorf("spec.rules[%d].destinations[%d].identityGroups is not allowed, identity groups are for rule sources only", i, j)
+			}
+		}
+		for j, peer := range rule.Sources {
+			for _, path := range peer.IdentityGroups {
+				if !strings.HasPrefix(path, identityGroupPathPrefix) {
+					return fmt.Errorf("spec.rules[%d].sources[%d].identityGroups has invalid NSX group path %q", i, j, path)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// buildPeerIdentityGroups returns the paths of the Identity Firewall groups of the peer. They're only supported
+// without VPC, the VPC groups can't refer to the infra groups.
+func (service *SecurityPolicyService) buildPeerIdentityGroups(peer *v1alpha1.SecurityPolicyPeer) ([]string, error) {
+	if len(peer.IdentityGroups) == 0 {
+		return nil, nil
+	}
+	if isVpcEnabled(service) {
+		return nil, errors.New("identityGroups are not supported in VPC mode")
+	}
+	return peer.IdentityGroups, nil
+}
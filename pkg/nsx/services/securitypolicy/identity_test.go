@@ -0,0 +1,63 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestSecurityPolicyService_IdentityGroups(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	identityGroup := "/infra/domains/default/groups/vdi-users"
+
+	// the identity groups are referred to by the peer group together with the selected Pods
+	peer := &v1alpha1.SecurityPolicyPeer{
+		IdentityGroups: []string{identityGroup},
+		PodSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vdi"}},
+	}
+	group := model.Group{}
+	_, _, err := service.updatePeerExpressions(&spWithPodSelector, peer, &group, 0, false)
+	assert.NoError(t, err)
+	resourceType, _ := group.Expression[0].Field("resource_type")
+	assert.Equal(t, data.NewStringValue("PathExpression"), resourceType)
+	paths, _ := group.Expression[0].Field("paths")
+	assert.Equal(t, []data.DataValue{data.NewStringValue(identityGroup)}, paths.(*data.ListValue).List())
+	assert.Greater(t, len(group.Expression), 2)
+
+	// the identity groups are not supported with VPC
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+	_, err = service.buildPeerIdentityGroups(peer)
+	assert.EqualError(t, err, "identityGroups are not supported in VPC mode")
+}
+
+func TestValidateIdentityGroups(t *testing.T) {
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules[0].Sources[0].IdentityGroups = []string{"/infra/domains/default/groups/vdi-users"}
+	assert.NoError(t, ValidateIdentityGroups(sp))
+
+	sp.Spec.Rules[0].Sources[0].IdentityGroups = []string{"vdi-users"}
+	assert.EqualError(t, ValidateIdentityGroups(sp), `spec.rules[0].sources[0].identityGroups has invalid NSX group path "vdi-users"`)
+
+	sp.Spec.Rules[0].Sources[0].IdentityGroups = nil
+	sp.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{{IdentityGroups: []string{"/infra/domains/default/groups/vdi-users"}}}
+	assert.EqualError(t, ValidateIdentityGroups(sp), "spec.rules[0].destinations[0].identityGroups is not allowed, identity groups are for rule sources only")
+}
@@ -0,0 +1,353 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// The IDSPolicies are realized as the NSX distributed IDS/IPS policies in the domain of the cluster, each with
+// an IDS profile of its severities. The rules and their groups are built like the ones of an internal
+// SecurityPolicy, the Detect rules as Allow rules and the DetectPrevent rules as Drop rules, then they're
+// converted to the IDS rules inspecting the traffic with the IDS profile. The groups share the store of the
+// SecurityPolicies, the IDS policies, rules and profiles have their own stores, all indexed by the IDSPolicy UID.
+
+var defaultIDSSeverities = []v1alpha1.IDSSeverity{v1alpha1.IDSSeverityCritical, v1alpha1.IDSSeverityHigh, v1alpha1.IDSSeverityMedium}
+
+var idsSeverities = sets.New[v1alpha1.IDSSeverity](v1alpha1.IDSSeverityCritical, v1alpha1.IDSSeverityHigh,
+	v1alpha1.IDSSeverityMedium, v1alpha1.IDSSeverityLow, v1alpha1.IDSSeveritySuspicious)
+
+// ValidateIDSPolicy rejects the IDSPolicy with the unknown severities or modes, and the peers which are not
+// supported by the IDS rules.
+func ValidateIDSPolicy(obj *v1alpha1.IDSPolicy) error {
+	if len(obj.Spec.AppliedTo) == 0 {
+		return fmt.Errorf("spec.appliedTo is required")
+	}
+	if len(obj.Spec.Rules) == 0 {
+		return fmt.Errorf("spec.rules is required")
+	}
+	for i, severity := range obj.Spec.Severities {
+		if !idsSeverities.Has(severity) {
+			return fmt.Errorf("spec.severities[%d] %s is not one of Critical, High, Medium, Low and Suspicious", i, severity)
+		}
+	}
+	for i := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[i]
+		if rule.Mode != "" && rule.Mode != v1alpha1.IDSModeDetect && rule.Mode != v1alpha1.IDSModeDetectPrevent {
+			return fmt.Errorf("spec.rules[%d].mode %s is not Detect or DetectPrevent", i, rule.Mode)
+		}
+		for _, peers := range [][]v1alpha1.SecurityPolicyPeer{rule.Sources, rule.Destinations} {
+			for _, peer := range peers {
+				if peer.FQDN != "" {
+					return fmt.Errorf("spec.rules[%d] FQDN of the peers is not supported by the IDS rules", i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// idsProfileSeverities returns the severities of the IDS profile of the IDSPolicy.
+func idsProfileSeverities(obj *v1alpha1.IDSPolicy) []string {
+	severities := obj.Spec.Severities
+	if len(severities) == 0 {
+		severities = defaultIDSSeverities
+	}
+	profileSeverities := make([]string, 0, len(severities))
+	seen := sets.New[v1alpha1.IDSSeverity]()
+	for _, severity := range severities {
+		if !seen.Has(severity) {
+			seen.Insert(severity)
+			profileSeverities = append(profileSeverities, util.ToUpper(string(severity)))
+		}
+	}
+	return profileSeverities
+}
+
+func idsProfilePath(id string) string {
+	return fmt.Sprintf("/infra/settings/firewall/security/intrusion-services/profiles/%s", id)
+}
+
+// idsRuleAction returns the action of the IDS rule from the action of the internal rule.
+func idsRuleAction(action *string) *string {
+	if action != nil && *action == util.ToUpper(v1alpha1.RuleActionDrop) {
+		return String(model.IdsRule_ACTION_DETECT_PREVENT)
+	}
+	return String(model.IdsRule_ACTION_DETECT)
+}
+
+// buildIDSPolicy builds the NSX IDS/IPS policy with rules, the IDS profile and the groups of the rules from the
+// IDSPolicy CR.
+func (service *SecurityPolicyService) buildIDSPolicy(obj *v1alpha1.IDSPolicy) (*model.IdsSecurityPolicy, *model.IdsProfile, []model.Group, error) {
+	allow, drop := v1alpha1.RuleActionAllow, v1alpha1.RuleActionDrop
+	internalSecurityPolicy := &v1alpha1.SecurityPolicy{
+		ObjectMeta: obj.ObjectMeta,
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: obj.Spec.AppliedTo,
+			Priority:  obj.Spec.Priority,
+		},
+	}
+	for _, rule := range obj.Spec.Rules {
+		action := &allow
+		if rule.Mode == v1alpha1.IDSModeDetectPrevent {
+			action = &drop
+		}
+		internalSecurityPolicy.Spec.Rules = append(internalSecurityPolicy.Spec.Rules, v1alpha1.SecurityPolicyRule{
+			Name:         rule.Name,
+			Action:       action,
+			Direction:    rule.Direction,
+			Sources:      rule.Sources,
+			Destinations: rule.Destinations,
+			Ports:        rule.Ports,
+		})
+	}
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(internalSecurityPolicy, common.ResourceTypeIDSPolicy)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	nsxIDSProfile := &model.IdsProfile{
+		Id:              nsxSecurityPolicy.Id,
+		DisplayName:     nsxSecurityPolicy.DisplayName,
+		ProfileSeverity: idsProfileSeverities(obj),
+		Tags:            nsxSecurityPolicy.Tags,
+	}
+	profiles := []string{idsProfilePath(*nsxIDSProfile.Id)}
+	nsxIDSPolicy := &model.IdsSecurityPolicy{
+		Id:             nsxSecurityPolicy.Id,
+		DisplayName:    nsxSecurityPolicy.DisplayName,
+		SequenceNumber: nsxSecurityPolicy.SequenceNumber,
+		Scope:          nsxSecurityPolicy.Scope,
+		Tags:           nsxSecurityPolicy.Tags,
+		Rules:          make([]model.IdsRule, 0, len(nsxSecurityPolicy.Rules)),
+	}
+	for _, rule := range nsxSecurityPolicy.Rules {
+		nsxIDSPolicy.Rules = append(nsxIDSPolicy.Rules, model.IdsRule{
+			Id:                rule.Id,
+			DisplayName:       rule.DisplayName,
+			SequenceNumber:    rule.SequenceNumber,
+			Direction:         rule.Direction,
+			IpProtocol:        rule.IpProtocol,
+			Scope:             rule.Scope,
+			SourceGroups:      rule.SourceGroups,
+			DestinationGroups: rule.DestinationGroups,
+			Services:          rule.Services,
+			ServiceEntries:    rule.ServiceEntries,
+			Action:            idsRuleAction(rule.Action),
+			IdsProfiles:       profiles,
+			Logged:            rule.Logged,
+			Tag:               rule.Tag,
+			Tags:              rule.Tags,
+		})
+	}
+	log.V(1).Info("built nsxIDSPolicy", "nsxIDSPolicy", nsxIDSPolicy, "nsxIDSProfile", nsxIDSProfile, "nsxGroups", nsxGroups)
+	return nsxIDSPolicy, nsxIDSProfile, *nsxGroups, nil
+}
+
+// idsPolicyReference returns an IdsSecurityPolicy which only refers to the existing one as the parent of the
+// changed and stale rules in the hierarchical patch.
+func idsPolicyReference(id *string) *model.IdsSecurityPolicy {
+	return &model.IdsSecurityPolicy{
+		Id:           id,
+		ResourceType: &common.ResourceTypeChildResourceReference,
+	}
+}
+
+// CreateOrUpdateIDSPolicy realizes the IDSPolicy CR. The IDS profile is patched before the rules referring to it,
+// then the changed and stale rules and groups are patched by the hierarchical API like the SecurityPolicies.
+func (service *SecurityPolicyService) CreateOrUpdateIDSPolicy(obj *v1alpha1.IDSPolicy) error {
+	if service.idsPolicyStore == nil {
+		return nsxutil.RestrictionError{Desc: "the IDSPolicies are not supported in VPC mode"}
+	}
+	if err := ValidateIDSPolicy(obj); err != nil {
+		return nsxutil.RestrictionError{Desc: err.Error()}
+	}
+	nsxIDSPolicy, nsxIDSProfile, nsxGroups, err := service.buildIDSPolicy(obj)
+	if err != nil {
+		log.Error(err, "failed to build IDSPolicy")
+		return err
+	}
+
+	indexScope := common.TagScopeIDSPolicyCRUID
+	existingProfiles := service.idsProfileStore.GetByIndex(indexScope, string(obj.UID))
+	if len(existingProfiles) == 0 || common.CompareResource((*IDSProfile)(existingProfiles[0]), (*IDSProfile)(nsxIDSProfile)) {
+		if err := service.patchIDSProfile(nsxIDSProfile); err != nil {
+			return err
+		}
+	}
+
+	existingIDSPolicy := service.idsPolicyStore.GetByKey(*nsxIDSPolicy.Id)
+	existingRules := service.idsRuleStore.GetByIndex(indexScope, string(obj.UID))
+	existingGroups := service.groupStore.GetByIndex(indexScope, string(obj.UID))
+
+	isChanged := true
+	if existingIDSPolicy != nil {
+		isChanged = common.CompareResource((*IDSPolicy)(existingIDSPolicy), (*IDSPolicy)(nsxIDSPolicy))
+	}
+	changed, stale := common.CompareResources(IDSRulesPtrToComparable(existingRules), IDSRulesToComparable(nsxIDSPolicy.Rules))
+	changedRules, staleRules := ComparableToIDSRules(changed), ComparableToIDSRules(stale)
+	changed, stale = common.CompareResources(GroupsPtrToComparable(existingGroups), GroupsToComparable(nsxGroups))
+	changedGroups, staleGroups := ComparableToGroups(changed), ComparableToGroups(stale)
+
+	if !isChanged && len(changedRules) == 0 && len(staleRules) == 0 && len(changedGroups) == 0 && len(staleGroups) == 0 {
+		log.Info("idsPolicy, rules and groups are not changed, skip updating them", "nsxIDSPolicy.Id", nsxIDSPolicy.Id)
+		return nil
+	}
+	// The IDS rules are not counted as the firewall rules by NSX, only the groups consume the capacity.
+	if err := common.Capacity.Reserve(map[string]int64{common.CapacityUsageGroups: int64(len(nsxGroups) - len(existingGroups))}); err != nil {
+		log.Error(err, "refuse to patch the IDSPolicy under NSX capacity pressure", "nsxIDSPolicy.Id", nsxIDSPolicy.Id)
+		return err
+	}
+
+	finalIDSPolicy := nsxIDSPolicy
+	if !isChanged {
+		finalIDSPolicy = idsPolicyReference(existingIDSPolicy.Id)
+	}
+	for i := range staleRules {
+		staleRules[i].MarkedForDelete = &MarkedForDelete
+	}
+	finalIDSPolicy.Rules = append(staleRules, changedRules...)
+	for i := range staleGroups {
+		staleGroups[i].MarkedForDelete = &MarkedForDelete
+	}
+	finalGroups := append(staleGroups, changedGroups...)
+
+	if err := service.patchIDSPolicy(finalIDSPolicy, finalGroups); err != nil {
+		return err
+	}
+	log.Info("successfully created or updated nsx IDSPolicy", "nsxIDSPolicy", finalIDSPolicy)
+	return nil
+}
+
+// DeleteIDSPolicy deletes the NSX IDS/IPS policy with rules, the groups and the IDS profile of the IDSPolicy CR by
+// its UID, the NSX resources are collected from the stores. The IDS profile is deleted after the rules referring
+// to it.
+func (service *SecurityPolicyService) DeleteIDSPolicy(uid types.UID) error {
+	if service.idsPolicyStore == nil {
+		return nil
+	}
+	indexScope := common.TagScopeIDSPolicyCRUID
+	existingIDSPolicies := service.idsPolicyStore.GetByIndex(indexScope, string(uid))
+	existingRules := service.idsRuleStore.GetByIndex(indexScope, string(uid))
+	existingGroups := service.groupStore.GetByIndex(indexScope, string(uid))
+	existingProfiles := service.idsProfileStore.GetByIndex(indexScope, string(uid))
+	if len(existingIDSPolicies) == 0 && len(existingRules) == 0 && len(existingGroups) == 0 && len(existingProfiles) == 0 {
+		log.Info("NSX IDSPolicy is not found in store, skip deleting it", "idsPolicyUID", uid)
+		return nil
+	}
+
+	if len(existingIDSPolicies) > 0 || len(existingRules) > 0 || len(existingGroups) > 0 {
+		var nsxIDSPolicy *model.IdsSecurityPolicy
+		if len(existingIDSPolicies) > 0 {
+			// Don't modify the IdsSecurityPolicy in store.
+			policy := *existingIDSPolicies[0]
+			policy.MarkedForDelete = &MarkedForDelete
+			nsxIDSPolicy = &policy
+		} else {
+			// The orphan rules are deleted under the reference of their policy.
+			nsxIDSPolicy = idsPolicyReference(String(util.GenerateID(string(uid), common.IDSPolicyPrefix, "", "")))
+		}
+		nsxIDSPolicy.Rules = make([]model.IdsRule, 0, len(existingRules))
+		for _, rule := range existingRules {
+			r := *rule
+			r.MarkedForDelete = &MarkedForDelete
+			nsxIDSPolicy.Rules = append(nsxIDSPolicy.Rules, r)
+		}
+		nsxGroups := make([]model.Group, 0, len(existingGroups))
+		for _, group := range existingGroups {
+			g := *group
+			g.MarkedForDelete = &MarkedForDelete
+			nsxGroups = append(nsxGroups, g)
+		}
+		if err := service.patchIDSPolicy(nsxIDSPolicy, nsxGroups); err != nil {
+			return err
+		}
+		log.Info("successfully deleted nsx IDSPolicy", "nsxIDSPolicy", nsxIDSPolicy)
+	}
+
+	for _, profile := range existingProfiles {
+		p := *profile
+		p.MarkedForDelete = &MarkedForDelete
+		if err := service.patchIDSProfile(&p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchIDSPolicy patches the IDS/IPS policy with rules and the groups by the hierarchical API, then updates the
+// stores. The NSX resources already deleted on NSX are only deleted from the stores.
+func (service *SecurityPolicyService) patchIDSPolicy(policy *model.IdsSecurityPolicy, groups []model.Group) error {
+	infra, err := service.WrapHierarchyIDSPolicy(policy, groups)
+	if err != nil {
+		log.Error(err, "failed to wrap IDSPolicy", "nsxIDSPolicy.Id", policy.Id)
+		return err
+	}
+	err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam)
+	if err != nil && !(policy.MarkedForDelete != nil && *policy.MarkedForDelete && nsxutil.IsNotFound(err)) {
+		log.Error(err, "failed to patch IDSPolicy", "nsxIDSPolicy.Id", policy.Id)
+		return err
+	}
+
+	if policy.ResourceType == nil || *policy.ResourceType != common.ResourceTypeChildResourceReference {
+		if err := service.idsPolicyStore.Apply(policy); err != nil {
+			log.Error(err, "failed to apply store", "nsxIDSPolicy", policy)
+			return err
+		}
+	}
+	if err := service.idsRuleStore.Apply(policy); err != nil {
+		log.Error(err, "failed to apply store", "nsxIDSRules", policy.Rules)
+		return err
+	}
+	if err := service.groupStore.Apply(&groups); err != nil {
+		log.Error(err, "failed to apply store", "nsxGroups", groups)
+		return err
+	}
+	return nil
+}
+
+// patchIDSProfile patches or deletes the IDS profile of an IDSPolicy, then updates the store. The IDS profile
+// already deleted on NSX is only deleted from the store.
+func (service *SecurityPolicyService) patchIDSProfile(profile *model.IdsProfile) error {
+	var err error
+	if profile.MarkedForDelete != nil && *profile.MarkedForDelete {
+		err = service.NSXClient.IDSProfileClient.Delete(*profile.Id)
+		if nsxutil.IsNotFound(err) {
+			err = nil
+		}
+	} else {
+		profile.ResourceType = &common.ResourceTypeIdsProfile
+		err = service.NSXClient.IDSProfileClient.Patch(*profile.Id, *profile)
+	}
+	if err != nil {
+		log.Error(err, "failed to patch IDS profile", "nsxIDSProfile.Id", profile.Id)
+		return err
+	}
+	if err := service.idsProfileStore.Apply(profile); err != nil {
+		log.Error(err, "failed to apply store", "nsxIDSProfile", profile)
+		return err
+	}
+	return nil
+}
+
+// ListIDSPolicyID lists the UIDs of the IDSPolicy CRs which the NSX resources in the stores are created for.
+func (service *SecurityPolicyService) ListIDSPolicyID() sets.Set[string] {
+	if service.idsPolicyStore == nil {
+		return sets.New[string]()
+	}
+	indexScope := common.TagScopeIDSPolicyCRUID
+	policySet := service.idsPolicyStore.ListIndexFuncValues(indexScope)
+	profileSet := service.idsProfileStore.ListIndexFuncValues(indexScope)
+	groupSet := service.groupStore.ListIndexFuncValues(indexScope)
+	return policySet.Union(profileSet).Union(groupSet)
+}
@@ -0,0 +1,126 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func idsPolicyWithRules() *v1alpha1.IDSPolicy {
+	rule := spWithPodSelector.Spec.Rules[1]
+	return &v1alpha1.IDSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ipA", UID: "uidIP"},
+		Spec: v1alpha1.IDSPolicySpec{
+			AppliedTo: spWithPodSelector.Spec.AppliedTo,
+			Priority:  3,
+			Rules: []v1alpha1.IDSPolicyRule{
+				{Name: "detect", Direction: rule.Direction, Sources: rule.Sources, Ports: rule.Ports},
+				{Name: "prevent", Mode: v1alpha1.IDSModeDetectPrevent, Direction: rule.Direction, Sources: rule.Sources},
+			},
+		},
+	}
+}
+
+func TestSecurityPolicyService_buildIDSPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	ip := idsPolicyWithRules()
+	assert.NoError(t, ValidateIDSPolicy(ip))
+
+	nsxIDSPolicy, nsxIDSProfile, nsxGroups, err := service.buildIDSPolicy(ip)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(*nsxIDSPolicy.Id, common.IDSPolicyPrefix))
+	assert.Equal(t, int64(3), *nsxIDSPolicy.SequenceNumber)
+	assert.NotEmpty(t, nsxIDSPolicy.Scope)
+	assert.NotEmpty(t, nsxGroups)
+
+	// the profile has the default severities and is referred to by all the rules
+	assert.Equal(t, nsxIDSPolicy.Id, nsxIDSProfile.Id)
+	assert.Equal(t, []string{"CRITICAL", "HIGH", "MEDIUM"}, nsxIDSProfile.ProfileSeverity)
+	assert.Len(t, nsxIDSPolicy.Rules, 2)
+	assert.Equal(t, model.IdsRule_ACTION_DETECT, *nsxIDSPolicy.Rules[0].Action)
+	assert.Equal(t, model.IdsRule_ACTION_DETECT_PREVENT, *nsxIDSPolicy.Rules[1].Action)
+	for _, rule := range nsxIDSPolicy.Rules {
+		assert.Equal(t, []string{idsProfilePath(*nsxIDSProfile.Id)}, rule.IdsProfiles)
+	}
+	for _, tags := range [][]string{
+		filterTag(nsxIDSPolicy.Tags, common.TagScopeIDSPolicyCRUID),
+		filterTag(nsxIDSPolicy.Rules[0].Tags, common.TagScopeIDSPolicyCRUID),
+		filterTag(nsxIDSProfile.Tags, common.TagScopeIDSPolicyCRUID),
+		filterTag(nsxGroups[0].Tags, common.TagScopeIDSPolicyCRUID),
+	} {
+		assert.Equal(t, []string{"uidIP"}, tags)
+	}
+
+	// the duplicated severities are only kept once
+	ip.Spec.Severities = []v1alpha1.IDSSeverity{v1alpha1.IDSSeverityLow, v1alpha1.IDSSeverityLow, v1alpha1.IDSSeveritySuspicious}
+	_, nsxIDSProfile, _, err = service.buildIDSPolicy(ip)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LOW", "SUSPICIOUS"}, nsxIDSProfile.ProfileSeverity)
+
+	_, err = service.WrapHierarchyIDSPolicy(nsxIDSPolicy, nsxGroups)
+	assert.NoError(t, err)
+	_, err = service.WrapHierarchyIDSPolicy(idsPolicyReference(nsxIDSPolicy.Id), nsxGroups)
+	assert.NoError(t, err)
+}
+
+func TestValidateIDSPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(ip *v1alpha1.IDSPolicy)
+		wantErr string
+	}{
+		{
+			name:    "no appliedTo",
+			mutate:  func(ip *v1alpha1.IDSPolicy) { ip.Spec.AppliedTo = nil },
+			wantErr: "spec.appliedTo is required",
+		},
+		{
+			name:    "no rules",
+			mutate:  func(ip *v1alpha1.IDSPolicy) { ip.Spec.Rules = nil },
+			wantErr: "spec.rules is required",
+		},
+		{
+			name:    "unknown severity",
+			mutate:  func(ip *v1alpha1.IDSPolicy) { ip.Spec.Severities = []v1alpha1.IDSSeverity{"Info"} },
+			wantErr: "spec.severities[0] Info is not one of Critical, High, Medium, Low and Suspicious",
+		},
+		{
+			name:    "unknown mode",
+			mutate:  func(ip *v1alpha1.IDSPolicy) { ip.Spec.Rules[1].Mode = "Prevent" },
+			wantErr: "spec.rules[1].mode Prevent is not Detect or DetectPrevent",
+		},
+		{
+			name: "FQDN",
+			mutate: func(ip *v1alpha1.IDSPolicy) {
+				ip.Spec.Rules[0].Destinations = []v1alpha1.SecurityPolicyPeer{{FQDN: "example.com"}}
+			},
+			wantErr: "spec.rules[0] FQDN of the peers is not supported by the IDS rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := idsPolicyWithRules()
+			tt.mutate(ip)
+			assert.EqualError(t, ValidateIDSPolicy(ip), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+const (
+	// MaxPolicyRules is the max count of NSX rules in one NSX SecurityPolicy.
+	MaxPolicyRules int = 1000
+	// MaxPolicyIPElements is the max count of IP elements in one NSX SecurityPolicy.
+	MaxPolicyIPElements int = 4000
+)
+
+// ValidateScaleLimits precomputes the NSX group criteria, rules and service entries the SecurityPolicy
+// expands to, and rejects the SecurityPolicy exceeding the NSX scale limits with the exact counts, so
+// that the error is returned on admission instead of failing the reconcile. A named port is counted
+// as one rule, since the port numbers it maps to are only resolved on reconcile.
+func (service *SecurityPolicyService) ValidateScaleLimits(obj *v1alpha1.SecurityPolicy) error {
+	appliedTo := obj.Spec.AppliedTo
+	err := validateGroupLimits("spec.appliedTo", len(appliedTo), func(group *model.Group, i int) (int, int, error) {
+		return service.updateTargetExpressions(obj, &appliedTo[i], group, -1)
+	})
+	if err != nil {
+		return err
+	}
+
+	rules, ipElements := 0, 0
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		path := fmt.Sprintf("spec.rules[%d]", ruleIdx)
+		err = validateGroupLimits(path+".appliedTo", len(rule.AppliedTo), func(group *model.Group, i int) (int, int, error) {
+			return service.updateTargetExpressions(obj, &rule.AppliedTo[i], group, ruleIdx)
+		})
+		if err != nil {
+			return err
+		}
+		for _, peers := range []struct {
+			path  string
+			peers []v1alpha1.SecurityPolicyPeer
+		}{{path + ".sources", rule.Sources}, {path + ".destinations", rule.Destinations}} {
+			groupShared := false
+			for i, peer := range peers.peers {
+				// the CIDRs of the networks are only in the peer group with VPC
+				networkCIDRs, _, _ := service.buildPeerNetworks(&peers.peers[i])
+				ipElements += len(networkCIDRs)
+				for _, block := range peer.IPBlocks {
+					// an IP block with excepts is split into the IP ranges around them
+					addresses, _ := ipBlockAddresses(block)
+					ipElements += len(addresses)
+				}
+				if peer.NamespaceSelector != nil {
+					groupShared = true
+				}
+			}
+			err = validateGroupLimits(peers.path, len(peers.peers), func(group *model.Group, i int) (int, int, error) {
+				return service.updatePeerExpressions(obj, &peers.peers[i], group, ruleIdx, groupShared)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// the rule with named ports expands to one rule per port, otherwise the ports are the
+		// service entries of one rule.
+		if service.hasNamedPort(rule) {
+			rules += len(rule.Ports)
+		} else {
+			rules++
+			if len(rule.Ports) > MaxRuleServiceEntries {
+				return fmt.Errorf("%s.ports: %d service entries exceed NSX limit of %d", path, len(rule.Ports), MaxRuleServiceEntries)
+			}
+		}
+	}
+	if rules > MaxPolicyRules {
+		return fmt.Errorf("spec.rules: %d NSX rules exceed NSX limit of %d", rules, MaxPolicyRules)
+	}
+	if ipElements > MaxPolicyIPElements {
+		return fmt.Errorf("spec.rules: %d IP elements exceed NSX limit of %d", ipElements, MaxPolicyIPElements)
+	}
+	return nil
+}
+
+// validateGroupLimits counts the criteria and the expressions of the group built from the count of
+// entries by update, with the same rules applied when building the group.
+func validateGroupLimits(path string, count int, update func(group *model.Group, i int) (int, int, error)) error {
+	group := &model.Group{}
+	criteria, expressions := 0, 0
+	for i := 0; i < count; i++ {
+		criteriaCount, exprCount, err := update(group, i)
+		if err != nil {
+			return fmt.Errorf("%s[%d]: %w", path, i, err)
+		}
+		criteria += criteriaCount
+		expressions += exprCount
+	}
+	if criteria > MaxCriteria {
+		return fmt.Errorf("%s: %d group criteria exceed NSX limit of %d", path, criteria, MaxCriteria)
+	}
+	if expressions > MaxTotalCriteriaExpressions {
+		return fmt.Errorf("%s: %d expressions in group criteria exceed NSX limit of %d", path, expressions, MaxTotalCriteriaExpressions)
+	}
+	return nil
+}
+
+// reserveCapacity reserves the NSX capacity consumed by the policy, rules and groups about to be patched, with the
+// counts of the existing ones. The DFW rules are counted by both the firewall rules and the SecurityPolicy rules of
+// the NSX capacity dashboard, the gateway rules by the firewall rules only.
+func reserveCapacity(newPolicy bool, rules, existingRules, groups, existingGroups int, dfw bool) error {
+	counts := map[string]int64{
+		common.CapacityUsageFirewallRules: int64(rules - existingRules),
+		common.CapacityUsageGroups:        int64(groups - existingGroups),
+	}
+	if dfw {
+		counts[common.CapacityUsageSecurityPolicyRules] = int64(rules - existingRules)
+		if newPolicy {
+			counts[common.CapacityUsageSecurityPolicies] = 1
+		}
+	}
+	return common.Capacity.Reserve(counts)
+}
@@ -0,0 +1,73 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestValidateScaleLimits(t *testing.T) {
+	service := fakeService()
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	// each target with two values of operator 'In' is built as two criteria
+	target := v1alpha1.SecurityPolicyTarget{
+		PodSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+			},
+		},
+	}
+	peer := v1alpha1.SecurityPolicyPeer{PodSelector: target.PodSelector}
+	newPolicy := func() *v1alpha1.SecurityPolicy {
+		return &v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "uid1"},
+			Spec: v1alpha1.SecurityPolicySpec{
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{target, target},
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{Action: &allowAction, Direction: &directionIn, Sources: []v1alpha1.SecurityPolicyPeer{peer, peer}},
+				},
+			},
+		}
+	}
+	assert.NoError(t, service.ValidateScaleLimits(newPolicy()))
+
+	obj := newPolicy()
+	obj.Spec.AppliedTo = append(obj.Spec.AppliedTo, target)
+	assert.EqualError(t, service.ValidateScaleLimits(obj), "spec.appliedTo: 6 group criteria exceed NSX limit of 5")
+
+	obj = newPolicy()
+	obj.Spec.Rules[0].Sources = append(obj.Spec.Rules[0].Sources, peer)
+	assert.EqualError(t, service.ValidateScaleLimits(obj), "spec.rules[0].sources: 6 group criteria exceed NSX limit of 5")
+
+	obj = newPolicy()
+	obj.Spec.Rules[0].AppliedTo = []v1alpha1.SecurityPolicyTarget{{PodSelector: target.PodSelector, VMSelector: target.PodSelector}}
+	assert.EqualError(t, service.ValidateScaleLimits(obj), "spec.rules[0].appliedTo[0]: PodSelector and VMSelector are not allowed to set in one group")
+
+	obj = newPolicy()
+	for i := 0; i <= MaxRuleServiceEntries; i++ {
+		obj.Spec.Rules[0].Ports = append(obj.Spec.Rules[0].Ports, v1alpha1.SecurityPolicyPort{Protocol: "TCP", Port: intstr.FromInt(1000 + i)})
+	}
+	assert.EqualError(t, service.ValidateScaleLimits(obj), "spec.rules[0].ports: 129 service entries exceed NSX limit of 128")
+
+	obj = newPolicy()
+	for i := 0; i < MaxPolicyRules; i++ {
+		obj.Spec.Rules = append(obj.Spec.Rules, v1alpha1.SecurityPolicyRule{Action: &allowAction, Direction: &directionIn})
+	}
+	assert.EqualError(t, service.ValidateScaleLimits(obj), "spec.rules: 1001 NSX rules exceed NSX limit of 1000")
+}
@@ -0,0 +1,197 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+const (
+	// lintMinIPv4PrefixLength and lintMinIPv6PrefixLength are the shortest prefixes of the CIDRs an allow rule
+	// is expected to allow, the shorter ones are reported as overly broad.
+	lintMinIPv4PrefixLength = 8
+	lintMinIPv6PrefixLength = 32
+)
+
+// LintSecurityPolicy reports the SecurityPolicy rules which are valid but most likely not what the author
+// meant: the rules shadowed by an earlier rule of the SecurityPolicy, the redundant peers of a rule, and the
+// overly broad CIDRs allowed by a rule. The warnings don't prevent the SecurityPolicy from being realized.
+func LintSecurityPolicy(obj *v1alpha1.SecurityPolicy) []string {
+	var warnings []string
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		for earlierIdx := 0; earlierIdx < ruleIdx; earlierIdx++ {
+			if ruleShadows(obj, &obj.Spec.Rules[earlierIdx], rule) {
+				warnings = append(warnings, fmt.Sprintf("%s is shadowed by %s and never matches any traffic",
+					lintRuleName(rule, ruleIdx), lintRuleName(&obj.Spec.Rules[earlierIdx], earlierIdx)))
+				break
+			}
+		}
+		for _, peers := range []struct {
+			kind  string
+			peers []v1alpha1.SecurityPolicyPeer
+		}{{"sources", rule.Sources}, {"destinations", rule.Destinations}} {
+			for _, warning := range lintPeers(peers.peers, rule.Action != nil && util.ToUpper(*rule.Action) == util.ToUpper(v1alpha1.RuleActionAllow)) {
+				warnings = append(warnings, fmt.Sprintf("%s %s: %s", lintRuleName(rule, ruleIdx), peers.kind, warning))
+			}
+		}
+	}
+	return warnings
+}
+
+func lintRuleName(rule *v1alpha1.SecurityPolicyRule, ruleIdx int) string {
+	if rule.Name != "" {
+		return fmt.Sprintf("rule %d %s", ruleIdx, rule.Name)
+	}
+	return fmt.Sprintf("rule %d", ruleIdx)
+}
+
+// ruleShadows checks if the earlier rule matches all the traffic the later rule matches, so the later rule is
+// never hit. The redirection and application rules are realized apart or matched on layer 7, they're skipped,
+// so are the earlier rules matching NSX services, whose ports are only known to NSX.
+func ruleShadows(obj *v1alpha1.SecurityPolicy, earlier, later *v1alpha1.SecurityPolicyRule) bool {
+	if earlier.RedirectTo != "" || later.RedirectTo != "" || len(earlier.AppIDs) > 0 || len(earlier.Services) > 0 {
+		return false
+	}
+	earlierDirection, err := getRuleDirection(earlier)
+	if err != nil {
+		return false
+	}
+	laterDirection, err := getRuleDirection(later)
+	if err != nil || earlierDirection != laterDirection {
+		return false
+	}
+	// the rule level appliedTo is ignored when the policy level one is set
+	if len(obj.Spec.AppliedTo) == 0 && !containsAll(earlier.AppliedTo, later.AppliedTo) {
+		return false
+	}
+	// the NSX services of the later rule are only covered by any port
+	if len(later.Services) > 0 && len(earlier.Ports) > 0 {
+		return false
+	}
+	return containsAll(earlier.Sources, later.Sources) && containsAll(earlier.Destinations, later.Destinations) &&
+		portsCover(earlier.Ports, later.Ports)
+}
+
+// containsAll checks if the earlier list matches all the items of the later list, the empty list matches any.
+func containsAll[T any](earlier, later []T) bool {
+	if len(earlier) == 0 {
+		return true
+	}
+	if len(later) == 0 {
+		return false
+	}
+	for i := range later {
+		found := false
+		for j := range earlier {
+			if reflect.DeepEqual(later[i], earlier[j]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// portsCover checks if the earlier ports match all the protocols and ports of the later ports, the empty ports
+// match any protocol and port. The named ports are only covered by the same named port.
+func portsCover(earlier, later []v1alpha1.SecurityPolicyPort) bool {
+	if len(earlier) == 0 {
+		return true
+	}
+	if len(later) == 0 {
+		return false
+	}
+	for _, laterPort := range later {
+		covered := false
+		for _, earlierPort := range earlier {
+			if portProtocol(earlierPort) != portProtocol(laterPort) {
+				continue
+			}
+			if laterPort.Port.Type == intstr.String || earlierPort.Port.Type == intstr.String {
+				covered = reflect.DeepEqual(earlierPort.Port, laterPort.Port)
+			} else {
+				start, end := portRange(earlierPort.Port.IntValue(), earlierPort.EndPort)
+				laterStart, laterEnd := portRange(laterPort.Port.IntValue(), laterPort.EndPort)
+				covered = start <= laterStart && laterEnd <= end
+			}
+			if covered {
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func portProtocol(port v1alpha1.SecurityPolicyPort) v1.Protocol {
+	if port.Protocol == "" {
+		return v1.ProtocolTCP
+	}
+	return port.Protocol
+}
+
+// lintPeers reports the duplicate peers, the IP blocks contained in another IP block of the peers, and the
+// overly broad IP blocks if the peers are allowed.
+func lintPeers(peers []v1alpha1.SecurityPolicyPeer, allowed bool) []string {
+	var warnings []string
+	type block struct {
+		cidr  string
+		ipNet *net.IPNet
+	}
+	// the IP blocks with excepts are left out, they don't contain all the IPs of their CIDR
+	var blocks []block
+	for i, peer := range peers {
+		if j := slices.IndexFunc(peers[:i], func(other v1alpha1.SecurityPolicyPeer) bool { return reflect.DeepEqual(peer, other) }); j >= 0 {
+			warnings = append(warnings, fmt.Sprintf("peer %d duplicates peer %d", i, j))
+			continue
+		}
+		for _, ipBlock := range peer.IPBlocks {
+			_, ipNet, err := net.ParseCIDR(ipBlock.CIDR)
+			if err != nil {
+				continue
+			}
+			ones, bits := ipNet.Mask.Size()
+			if allowed && (bits == 32 && ones < lintMinIPv4PrefixLength || bits == 128 && ones < lintMinIPv6PrefixLength) {
+				warnings = append(warnings, fmt.Sprintf("ipBlock %s is overly broad, consider narrowing it down", ipBlock.CIDR))
+			}
+			if len(ipBlock.Except) == 0 {
+				blocks = append(blocks, block{cidr: ipBlock.CIDR, ipNet: ipNet})
+			}
+		}
+	}
+	for i := range blocks {
+		ones, bits := blocks[i].ipNet.Mask.Size()
+		for j := range blocks {
+			if i == j {
+				continue
+			}
+			otherOnes, otherBits := blocks[j].ipNet.Mask.Size()
+			if otherBits != bits || otherOnes > ones || !blocks[j].ipNet.Contains(blocks[i].ipNet.IP) {
+				continue
+			}
+			// of the equal IP blocks, only the later one is reported
+			if otherOnes == ones && j > i {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("ipBlock %s is redundant, it's contained in ipBlock %s", blocks[i].cidr, blocks[j].cidr))
+			break
+		}
+	}
+	return warnings
+}
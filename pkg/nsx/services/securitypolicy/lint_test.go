@@ -0,0 +1,74 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestLintSecurityPolicy(t *testing.T) {
+	allow, drop := v1alpha1.RuleActionAllow, v1alpha1.RuleActionDrop
+	in, out := v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionOut
+	web := v1alpha1.SecurityPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}
+	db := v1alpha1.SecurityPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}}
+	sp := &v1alpha1.SecurityPolicy{
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{}}},
+			Rules: []v1alpha1.SecurityPolicyRule{
+				{
+					Action: &allow, Direction: &in, Sources: []v1alpha1.SecurityPolicyPeer{web, db},
+					Ports: []v1alpha1.SecurityPolicyPort{{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(8000), EndPort: 9000}},
+				},
+				{
+					Action: &drop, Direction: &in, Name: "drop-web", Sources: []v1alpha1.SecurityPolicyPeer{web},
+					Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(8080)}},
+				},
+				// the ports of the earlier rule don't cover UDP
+				{
+					Action: &drop, Direction: &in, Sources: []v1alpha1.SecurityPolicyPeer{web},
+					Ports: []v1alpha1.SecurityPolicyPort{{Protocol: corev1.ProtocolUDP, Port: intstr.FromInt(8080)}},
+				},
+				// the direction differs
+				{
+					Action: &drop, Direction: &out, Destinations: []v1alpha1.SecurityPolicyPeer{web},
+				},
+				{
+					Action: &allow, Direction: &out,
+					Destinations: []v1alpha1.SecurityPolicyPeer{
+						{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/16"}, {CIDR: "10.0.1.0/24"}}},
+						{IPBlocks: []v1alpha1.IPBlock{{CIDR: "0.0.0.0/0", Except: []string{"10.0.0.0/8"}}}},
+						{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/16"}, {CIDR: "10.0.1.0/24"}}},
+						{IPBlocks: []v1alpha1.IPBlock{{CIDR: "10.0.0.0/16"}}},
+					},
+				},
+				// the broad CIDR is dropped
+				{
+					Action: &drop, Direction: &out,
+					Destinations: []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "0.0.0.0/0"}}}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{
+		"rule 1 drop-web is shadowed by rule 0 and never matches any traffic",
+		"rule 4 destinations: ipBlock 0.0.0.0/0 is overly broad, consider narrowing it down",
+		"rule 4 destinations: peer 2 duplicates peer 0",
+		"rule 4 destinations: ipBlock 10.0.1.0/24 is redundant, it's contained in ipBlock 10.0.0.0/16",
+		"rule 4 destinations: ipBlock 10.0.0.0/16 is redundant, it's contained in ipBlock 10.0.0.0/16",
+	}, LintSecurityPolicy(sp))
+
+	// the rules applied to different targets don't shadow each other
+	sp.Spec.AppliedTo = nil
+	sp.Spec.Rules[1].AppliedTo = []v1alpha1.SecurityPolicyTarget{{VMSelector: &metav1.LabelSelector{}}}
+	sp.Spec.Rules[0].AppliedTo = []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{}}}
+	warnings := LintSecurityPolicy(sp)
+	assert.NotContains(t, warnings, "rule 1 drop-web is shadowed by rule 0 and never matches any traffic")
+}
@@ -0,0 +1,135 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// NSX prints the tag of a rule, a.k.a. the log label, in the DFW syslog events. Every NSX rule is labeled with
+// a correlation ID derived from the UID of the CR and the index of the rule in the CR, so the events can be
+// attributed to the CRs. The correlation ID is appended to the ruleTag of the rule if it's set.
+
+const (
+	// maxLogLabelLength is the length of the tag NSX keeps in the logs.
+	maxLogLabelLength = 32
+	// correlationUIDLength is the length of the hash of the CR UID in the correlation ID.
+	correlationUIDLength = 8
+	logLabelSeparator    = "|"
+)
+
+// LogLabelOwner is the CR rule a log label is correlated with.
+type LogLabelOwner struct {
+	// Kind is the kind of the CR, e.g. SecurityPolicy or NetworkPolicy.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	// RuleIndex is the index of the rule in the CR, or in the SecurityPolicy it's converted to.
+	RuleIndex int `json:"ruleIndex"`
+	// Rules are the IDs of the NSX rules realized from the rule.
+	Rules []string `json:"rules"`
+}
+
+// buildCorrelationID returns the correlation ID of the rule of the index in the CR of the UID, e.g. 3f9a1b2c.1.
+func buildCorrelationID(uid types.UID, ruleIdx int) string {
+	return fmt.Sprintf("%s.%d", util.Sha1(string(uid))[:correlationUIDLength], ruleIdx)
+}
+
+// buildLogLabel returns the log label of the NSX rules with the correlation ID, the ruleTag is truncated to keep
+// the correlation ID in the logs.
+func buildLogLabel(ruleTag string, correlationID string) string {
+	if ruleTag == "" {
+		return correlationID
+	}
+	if maxTagLength := maxLogLabelLength - len(correlationID) - len(logLabelSeparator); len(ruleTag) > maxTagLength {
+		ruleTag = ruleTag[:maxTagLength]
+	}
+	return ruleTag + logLabelSeparator + correlationID
+}
+
+// correlationIDOf returns the correlation ID in the log label.
+func correlationIDOf(label string) string {
+	return label[strings.LastIndex(label, logLabelSeparator)+1:]
+}
+
+// logLabelOwnerKinds are the kinds of the CRs the NSX rules are created for, in the order of their owner tag
+// scopes being matched.
+var logLabelOwnerKinds = []string{
+	common.ResourceTypeSecurityPolicy,
+	common.ResourceTypeNetworkPolicy,
+	common.ResourceTypeServiceExposure,
+	common.ResourceTypeAdminNetworkPolicy,
+	common.ResourceTypeNamespaceDefaultDeny,
+	common.ResourceTypeGatewayPolicy,
+}
+
+func logLabelOwnerOf(rule *model.Rule) *LogLabelOwner {
+	return ownerOfTags(rule.Tags)
+}
+
+// ownerOfTags returns the CR the NSX resource of the tags is created for, without the rules.
+func ownerOfTags(tags []model.Tag) *LogLabelOwner {
+	for _, kind := range logLabelOwnerKinds {
+		scopeOwnerName, scopeOwnerUID := ownerTagScopes(kind)
+		uids := filterTag(tags, scopeOwnerUID)
+		if len(uids) == 0 {
+			continue
+		}
+		owner := &LogLabelOwner{Kind: kind, UID: uids[0]}
+		if names := filterTag(tags, scopeOwnerName); len(names) > 0 {
+			owner.Name = names[0]
+		}
+		if namespaces := filterTag(tags, common.TagScopeNamespace); len(namespaces) > 0 {
+			owner.Namespace = namespaces[0]
+		}
+		return owner
+	}
+	return nil
+}
+
+// LookupLogLabel returns the CR rules the log label of a DFW syslog event is correlated with, by the NSX rules
+// in store. More than one CR rule is returned in the rare case of the hashes of their UIDs colliding.
+func (service *SecurityPolicyService) LookupLogLabel(label string) []LogLabelOwner {
+	correlationID := correlationIDOf(label)
+	ruleIdx, err := strconv.Atoi(correlationID[strings.LastIndex(correlationID, ".")+1:])
+	if err != nil {
+		return nil
+	}
+	_, ruleStore, _, _, _ := service.getStores()
+	ownersByUID := map[string]*LogLabelOwner{}
+	for _, obj := range ruleStore.List() {
+		rule := obj.(*model.Rule)
+		if rule.Tag == nil || correlationIDOf(*rule.Tag) != correlationID {
+			continue
+		}
+		owner := logLabelOwnerOf(rule)
+		if owner == nil {
+			continue
+		}
+		if existing, ok := ownersByUID[owner.UID]; ok {
+			owner = existing
+		} else {
+			owner.RuleIndex = ruleIdx
+			ownersByUID[owner.UID] = owner
+		}
+		owner.Rules = append(owner.Rules, *rule.Id)
+	}
+	owners := make([]LogLabelOwner, 0, len(ownersByUID))
+	for _, owner := range ownersByUID {
+		sort.Strings(owner.Rules)
+		owners = append(owners, *owner)
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i].UID < owners[j].UID })
+	return owners
+}
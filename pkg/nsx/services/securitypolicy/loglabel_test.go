@@ -0,0 +1,65 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestBuildLogLabel(t *testing.T) {
+	correlationID := buildCorrelationID("uidA", 12)
+	assert.Len(t, correlationID, correlationUIDLength+3)
+	assert.Equal(t, correlationID, buildLogLabel("", correlationID))
+	assert.Equal(t, "payments|"+correlationID, buildLogLabel("payments", correlationID))
+
+	// the ruleTag is truncated to keep the correlation ID in the logs
+	label := buildLogLabel(strings.Repeat("a", maxLogLabelLength), correlationID)
+	assert.Len(t, label, maxLogLabelLength)
+	assert.Equal(t, correlationID, correlationIDOf(label))
+}
+
+func TestSecurityPolicyService_LookupLogLabel(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	service.ruleStore = &RuleStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}),
+		BindingType: model.RuleBindingType(),
+	}}
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules[1].RuleTag = "dns"
+	nsxSecurityPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.NoError(t, service.ruleStore.Apply(nsxSecurityPolicy))
+
+	label := *nsxSecurityPolicy.Rules[1].Tag
+	assert.True(t, strings.HasPrefix(label, "dns|"))
+	assert.Equal(t, []LogLabelOwner{{
+		Kind:      common.ResourceTypeSecurityPolicy,
+		Namespace: "ns1",
+		Name:      "spA",
+		UID:       "uidA",
+		RuleIndex: 1,
+		Rules:     []string{*nsxSecurityPolicy.Rules[1].Id},
+	}}, service.LookupLogLabel(label))
+	// the correlation ID alone is looked up as well
+	assert.Len(t, service.LookupLogLabel(correlationIDOf(label)), 1)
+	assert.Empty(t, service.LookupLogLabel(buildCorrelationID("uidB", 1)))
+	assert.Empty(t, service.LookupLogLabel("payments"))
+}
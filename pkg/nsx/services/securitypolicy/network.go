@@ -0,0 +1,98 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+var clusterNetworks = []v1alpha1.ClusterNetwork{v1alpha1.ClusterNetworkPod, v1alpha1.ClusterNetworkNode, v1alpha1.ClusterNetworkService}
+
+// networkCIDRs returns the CIDRs configured for the cluster network.
+func (service *SecurityPolicyService) networkCIDRs(network v1alpha1.ClusterNetwork) []string {
+	k8sConfig := service.NSXConfig.K8sConfig
+	if k8sConfig == nil {
+		return nil
+	}
+	switch network {
+	case v1alpha1.ClusterNetworkPod:
+		return k8sConfig.ClusterCIDRs
+	case v1alpha1.ClusterNetworkNode:
+		return k8sConfig.NodeCIDRs
+	case v1alpha1.ClusterNetworkService:
+		return k8sConfig.ServiceCIDRs
+	}
+	return nil
+}
+
+func (service *SecurityPolicyService) buildNetworkGroupID(network v1alpha1.ClusterNetwork) string {
+	return util.GenerateID(getCluster(service), common.SecurityPolicyPrefix, "", strings.ToLower(string(network)))
+}
+
+func (service *SecurityPolicyService) buildNetworkGroupPath(network v1alpha1.ClusterNetwork) string {
+	return fmt.Sprintf("/infra/domains/%s/groups/%s", getDomain(service), service.buildNetworkGroupID(network))
+}
+
+// buildNetworkGroup builds the NSX group of the CIDRs of the cluster network, which is referred to by the
+// rule peers selecting the network.
+func (service *SecurityPolicyService) buildNetworkGroup(network v1alpha1.ClusterNetwork, cidrs []string) *model.Group {
+	return &model.Group{
+		Id:          String(service.buildNetworkGroupID(network)),
+		DisplayName: String(util.GenerateTruncName(common.MaxNameLength, getCluster(service), "", strings.ToLower(string(network)), "", "")),
+		Expression:  []*data.StructValue{expression.IPAddresses(cidrs)},
+		Tags: []model.Tag{
+			{Scope: String(common.TagScopeCluster), Tag: String(getCluster(service))},
+			{Scope: String(common.TagScopeVersion), Tag: String(strings.Join(common.TagValueVersion, "."))},
+			{Scope: String(common.TagScopeGroupType), Tag: String(common.TagValueGroupNetwork)},
+		},
+	}
+}
+
+// SyncNetworkGroups creates or updates the NSX groups of the cluster networks whose CIDRs are configured.
+// The groups are only maintained without VPC, the rule peers in VPC refer to the CIDRs directly since
+// the VPC groups can't refer to the infra groups.
+func (service *SecurityPolicyService) SyncNetworkGroups() error {
+	if isVpcEnabled(service) {
+		return nil
+	}
+	for _, network := range clusterNetworks {
+		cidrs := service.networkCIDRs(network)
+		if len(cidrs) == 0 {
+			continue
+		}
+		group := service.buildNetworkGroup(network, cidrs)
+		if err := service.NSXClient.GroupClient.Patch(getDomain(service), *group.Id, *group); err != nil {
+			return err
+		}
+		log.Info("synced NSX group of cluster network", "network", network, "group", *group.Id, "cidrs", cidrs)
+	}
+	return nil
+}
+
+// buildPeerNetworks returns the CIDRs of the cluster networks selected by the peer with VPC, and the paths
+// of their NSX groups without VPC.
+func (service *SecurityPolicyService) buildPeerNetworks(peer *v1alpha1.SecurityPolicyPeer) ([]string, []string, error) {
+	var cidrs, paths []string
+	for _, network := range peer.Networks {
+		networkCIDRs := service.networkCIDRs(network)
+		if len(networkCIDRs) == 0 {
+			return nil, nil, fmt.Errorf("no CIDRs of %s are configured for nsx-operator", network)
+		}
+		if isVpcEnabled(service) {
+			cidrs = append(cidrs, networkCIDRs...)
+		} else {
+			paths = append(paths, service.buildNetworkGroupPath(network))
+		}
+	}
+	return cidrs, paths, nil
+}
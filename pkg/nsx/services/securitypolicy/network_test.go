@@ -0,0 +1,79 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+type fakeGroupsClient struct {
+	domains.GroupsClient
+	patched map[string]model.Group
+}
+
+func (c *fakeGroupsClient) Patch(domainID string, groupID string, group model.Group) error {
+	c.patched[domainID+"/"+groupID] = group
+	return nil
+}
+
+func TestSecurityPolicyService_ClusterNetworks(t *testing.T) {
+	service := fakeService()
+	service.NSXConfig.K8sConfig = &config.K8sConfig{
+		ClusterCIDRs: []string{"10.244.0.0/16"},
+		ServiceCIDRs: []string{"10.96.0.0/12", "fd00::/108"},
+	}
+	groupClient := &fakeGroupsClient{patched: map[string]model.Group{}}
+	service.NSXClient.GroupClient = groupClient
+
+	// the groups of the networks configured are synced
+	assert.NoError(t, service.SyncNetworkGroups())
+	assert.Len(t, groupClient.patched, 2)
+	group := groupClient.patched["k8scl-one:test/sp_k8scl-one:test_servicenetwork"]
+	addresses, _ := group.Expression[0].Field("ip_addresses")
+	assert.Equal(t, 2, len(addresses.(*data.ListValue).List()))
+
+	// the peer refers to the groups of the networks
+	peer := &v1alpha1.SecurityPolicyPeer{
+		IPBlocks: []v1alpha1.IPBlock{{CIDR: "192.168.0.0/24"}},
+		Networks: []v1alpha1.ClusterNetwork{v1alpha1.ClusterNetworkPod, v1alpha1.ClusterNetworkService},
+	}
+	cidrs, paths, err := service.buildPeerNetworks(peer)
+	assert.NoError(t, err)
+	assert.Empty(t, cidrs)
+	assert.Equal(t, []string{
+		"/infra/domains/k8scl-one:test/groups/sp_k8scl-one:test_clusternetwork",
+		"/infra/domains/k8scl-one:test/groups/sp_k8scl-one:test_servicenetwork",
+	}, paths)
+	group = model.Group{}
+	_, _, err = service.updatePeerExpressions(&v1alpha1.SecurityPolicy{}, peer, &group, 0, false)
+	assert.NoError(t, err)
+	assert.Len(t, group.Expression, 3)
+	resourceType, _ := group.Expression[2].Field("resource_type")
+	assert.Equal(t, data.NewStringValue("PathExpression"), resourceType)
+
+	// the network not configured is rejected
+	peer.Networks = append(peer.Networks, v1alpha1.ClusterNetworkNode)
+	_, _, err = service.buildPeerNetworks(peer)
+	assert.EqualError(t, err, "no CIDRs of NodeNetwork are configured for nsx-operator")
+
+	// the peer refers to the CIDRs of the networks with VPC
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+	peer.Networks = []v1alpha1.ClusterNetwork{v1alpha1.ClusterNetworkService}
+	cidrs, paths, err = service.buildPeerNetworks(peer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.96.0.0/12", "fd00::/108"}, cidrs)
+	assert.Empty(t, paths)
+	groupClient.patched = map[string]model.Group{}
+	assert.NoError(t, service.SyncNetworkGroups())
+	assert.Empty(t, groupClient.patched)
+}
@@ -0,0 +1,221 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"path"
+	"slices"
+	"sort"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// The DFW evaluates the rules by the category of their NSX SecurityPolicy first, then by the sequence number of the
+// policy in the category, and then by the sequence number of the rule in the policy. The first rule matched
+// decides. The ordering of the rules applying to a namespace is built from the NSX SecurityPolicies and rules in
+// store, i.e. the ones realized for the SecurityPolicies, the NetworkPolicies, the AdminNetworkPolicies, the
+// default deny and the ServiceExposures of the namespace, and the baselines of the other namespaces applying to
+// all the workloads of the cluster. A SecurityPolicy can be previewed from its spec in place of the realized one.
+
+// policyCategoryRanks are the DFW categories in the order NSX evaluates them, an NSX SecurityPolicy without
+// category is in the Application category.
+var policyCategoryRanks = map[string]int{
+	"Ethernet":                   0,
+	"Emergency":                  1,
+	policyCategoryInfrastructure: 2,
+	policyCategoryEnvironment:    3,
+	policyCategoryApplication:    4,
+}
+
+const policyCategoryApplication = "Application"
+
+// RuleOrdering is the ordering of the NSX rules applying to a namespace as the DFW evaluates them.
+type RuleOrdering struct {
+	Namespace string `json:"namespace"`
+	// Preview is the name of the SecurityPolicy previewed from its spec, if any.
+	Preview string        `json:"preview,omitempty"`
+	Rules   []OrderedRule `json:"rules"`
+}
+
+// OrderedRule is an NSX rule at its position in the ordering.
+type OrderedRule struct {
+	// Position is the 1-based position of the rule in the ordering.
+	Position       int    `json:"position"`
+	Category       string `json:"category"`
+	PolicyID       string `json:"policyID"`
+	PolicySequence int64  `json:"policySequence"`
+	RuleID         string `json:"ruleID"`
+	RuleName       string `json:"ruleName,omitempty"`
+	RuleSequence   int64  `json:"ruleSequence"`
+	Action         string `json:"action,omitempty"`
+	Direction      string `json:"direction,omitempty"`
+	// Sources and Destinations are the IDs of the groups of the rule peers, or ANY.
+	Sources      []string `json:"sources,omitempty"`
+	Destinations []string `json:"destinations,omitempty"`
+	// OwnerKind, OwnerNamespace and OwnerName are the CR the rule is realized for.
+	OwnerKind      string `json:"ownerKind,omitempty"`
+	OwnerNamespace string `json:"ownerNamespace,omitempty"`
+	OwnerName      string `json:"ownerName,omitempty"`
+	// Baseline is true for the rules of the other namespaces applying to all the workloads of the cluster.
+	Baseline bool `json:"baseline,omitempty"`
+	// Preview is true for the rules built from the spec of the SecurityPolicy previewed, which are not realized.
+	Preview bool `json:"preview,omitempty"`
+}
+
+// orderingPolicy is an NSX SecurityPolicy with its rules in the ordering.
+type orderingPolicy struct {
+	policy   *model.SecurityPolicy
+	rules    []*model.Rule
+	baseline bool
+	preview  bool
+}
+
+// BuildRuleOrdering returns the ordering of the NSX rules applying to the namespace. If preview is set, the NSX
+// resources of the SecurityPolicy are built from its spec, without patching NSX, and replace the realized ones,
+// so the author can see where the rules land before they're realized.
+func (service *SecurityPolicyService) BuildRuleOrdering(namespace string, preview *v1alpha1.SecurityPolicy) (*RuleOrdering, error) {
+	securityPolicyStore, ruleStore, _, _, _ := service.getStores()
+	clusterGroupPath := ""
+	if !isVpcEnabled(service) {
+		clusterGroupPath = service.buildClusterGroupPath()
+	}
+	appliedToAll := func(scope []string) bool {
+		return clusterGroupPath != "" && slices.Contains(scope, clusterGroupPath)
+	}
+	rulesByOwner := map[string][]*model.Rule{}
+	for _, obj := range ruleStore.List() {
+		rule := obj.(*model.Rule)
+		if owner := ownerOfTags(rule.Tags); owner != nil {
+			rulesByOwner[owner.Kind+"/"+owner.UID] = append(rulesByOwner[owner.Kind+"/"+owner.UID], rule)
+		}
+	}
+	policies := map[string]*orderingPolicy{}
+	for _, obj := range securityPolicyStore.List() {
+		policy := obj.(*model.SecurityPolicy)
+		owner := ownerOfTags(policy.Tags)
+		if owner == nil {
+			continue
+		}
+		key := owner.Kind + "/" + owner.UID
+		switch {
+		case owner.Namespace == namespace:
+			policies[key] = &orderingPolicy{policy: policy, rules: rulesByOwner[key]}
+		case owner.Namespace == "" || appliedToAll(policy.Scope):
+			policies[key] = &orderingPolicy{policy: policy, rules: rulesByOwner[key], baseline: true}
+		default:
+			// only the rules applied to all the cluster workloads of the policies of the other namespaces
+			var rules []*model.Rule
+			for _, rule := range rulesByOwner[key] {
+				if appliedToAll(rule.Scope) {
+					rules = append(rules, rule)
+				}
+			}
+			if len(rules) > 0 {
+				policies[key] = &orderingPolicy{policy: policy, rules: rules, baseline: true}
+			}
+		}
+	}
+
+	ordering := &RuleOrdering{Namespace: namespace}
+	if preview != nil {
+		nsxSecurityPolicy, nsxGroups, err := service.buildUnrealizedSecurityPolicy(preview)
+		if err != nil {
+			return nil, err
+		}
+		service.shareGroups(nsxSecurityPolicy.Rules, *nsxGroups)
+		rules := make([]*model.Rule, len(nsxSecurityPolicy.Rules))
+		for i := range nsxSecurityPolicy.Rules {
+			rules[i] = &nsxSecurityPolicy.Rules[i]
+		}
+		owner := ownerOfTags(nsxSecurityPolicy.Tags)
+		if owner != nil {
+			policies[owner.Kind+"/"+owner.UID] = &orderingPolicy{policy: nsxSecurityPolicy, rules: rules, preview: true}
+		}
+		ordering.Preview = preview.Name
+	}
+
+	ordering.Rules = orderRules(policies)
+	return ordering, nil
+}
+
+// orderRules sorts the rules of the policies the way the DFW evaluates them. NSX doesn't define the order of the
+// policies with the same category and sequence number, they're sorted by ID.
+func orderRules(policies map[string]*orderingPolicy) []OrderedRule {
+	sorted := make([]*orderingPolicy, 0, len(policies))
+	for _, p := range policies {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		pi, pj := sorted[i].policy, sorted[j].policy
+		if ci, cj := categoryRank(pi.Category), categoryRank(pj.Category); ci != cj {
+			return ci < cj
+		}
+		if si, sj := int64Value(pi.SequenceNumber), int64Value(pj.SequenceNumber); si != sj {
+			return si < sj
+		}
+		return *pi.Id < *pj.Id
+	})
+	rules := []OrderedRule{}
+	for _, p := range sorted {
+		sort.Slice(p.rules, func(i, j int) bool {
+			if si, sj := int64Value(p.rules[i].SequenceNumber), int64Value(p.rules[j].SequenceNumber); si != sj {
+				return si < sj
+			}
+			return *p.rules[i].Id < *p.rules[j].Id
+		})
+		category := policyCategoryApplication
+		if p.policy.Category != nil && *p.policy.Category != "" {
+			category = *p.policy.Category
+		}
+		for _, rule := range p.rules {
+			ordered := OrderedRule{
+				Position:       len(rules) + 1,
+				Category:       category,
+				PolicyID:       *p.policy.Id,
+				PolicySequence: int64Value(p.policy.SequenceNumber),
+				RuleID:         *rule.Id,
+				RuleSequence:   int64Value(rule.SequenceNumber),
+				Sources:        groupIDs(rule.SourceGroups),
+				Destinations:   groupIDs(rule.DestinationGroups),
+				Baseline:       p.baseline,
+				Preview:        p.preview,
+			}
+			if rule.DisplayName != nil {
+				ordered.RuleName = *rule.DisplayName
+			}
+			if rule.Action != nil {
+				ordered.Action = *rule.Action
+			}
+			if rule.Direction != nil {
+				ordered.Direction = *rule.Direction
+			}
+			if owner := ownerOfTags(rule.Tags); owner != nil {
+				ordered.OwnerKind, ordered.OwnerNamespace, ordered.OwnerName = owner.Kind, owner.Namespace, owner.Name
+			}
+			rules = append(rules, ordered)
+		}
+	}
+	return rules
+}
+
+func categoryRank(category *string) int {
+	if category == nil || *category == "" {
+		return policyCategoryRanks[policyCategoryApplication]
+	}
+	if rank, ok := policyCategoryRanks[*category]; ok {
+		return rank
+	}
+	return len(policyCategoryRanks)
+}
+
+// groupIDs returns the IDs of the groups of the paths, ANY is kept.
+func groupIDs(paths []string) []string {
+	ids := make([]string, 0, len(paths))
+	for _, p := range paths {
+		ids = append(ids, path.Base(p))
+	}
+	return ids
+}
@@ -0,0 +1,60 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+func TestOrderRules(t *testing.T) {
+	sequence := func(n int64) *int64 { return &n }
+	newPolicy := func(id string, category string, seq int64, rules ...*model.Rule) *orderingPolicy {
+		policy := &model.SecurityPolicy{Id: String(id), SequenceNumber: sequence(seq)}
+		if category != "" {
+			policy.Category = String(category)
+		}
+		return &orderingPolicy{policy: policy, rules: rules}
+	}
+	newRule := func(id string, seq int64) *model.Rule {
+		return &model.Rule{Id: String(id), SequenceNumber: sequence(seq), SourceGroups: []string{"/infra/domains/default/groups/" + id + "_src"}, DestinationGroups: []string{"ANY"}}
+	}
+	baseline := newPolicy("baseline", policyCategoryEnvironment, 0, newRule("baseline_0", 0))
+	baseline.baseline = true
+	preview := newPolicy("preview", "", 5, newRule("preview_0", 0))
+	preview.preview = true
+	policies := map[string]*orderingPolicy{
+		"sp-b":     newPolicy("sp-b", "", 10, newRule("sp-b_1", 1), newRule("sp-b_0", 0)),
+		"sp-a":     newPolicy("sp-a", policyCategoryApplication, 10, newRule("sp-a_0", 0)),
+		"anp":      newPolicy("anp", "Emergency", 100, newRule("anp_0", 0)),
+		"baseline": baseline,
+		"preview":  preview,
+	}
+
+	rules := orderRules(policies)
+	var ids []string
+	for i, rule := range rules {
+		assert.Equal(t, i+1, rule.Position)
+		ids = append(ids, rule.RuleID)
+	}
+	// the category first, then the policy sequence number and the ID, then the rule sequence number
+	assert.Equal(t, []string{"anp_0", "baseline_0", "preview_0", "sp-a_0", "sp-b_0", "sp-b_1"}, ids)
+	assert.Equal(t, "Emergency", rules[0].Category)
+	assert.True(t, rules[1].Baseline)
+	assert.True(t, rules[2].Preview)
+	assert.Equal(t, policyCategoryApplication, rules[2].Category)
+	assert.Equal(t, []string{"preview_0_src"}, rules[2].Sources)
+	assert.Equal(t, []string{"ANY"}, rules[2].Destinations)
+	assert.Equal(t, int64(1), rules[5].RuleSequence)
+}
+
+func TestCategoryRank(t *testing.T) {
+	assert.Equal(t, categoryRank(String(policyCategoryApplication)), categoryRank(nil))
+	assert.Equal(t, categoryRank(String(policyCategoryApplication)), categoryRank(String("")))
+	assert.Less(t, categoryRank(String("Ethernet")), categoryRank(String("Emergency")))
+	assert.Less(t, categoryRank(String(policyCategoryInfrastructure)), categoryRank(String(policyCategoryEnvironment)))
+	assert.Less(t, categoryRank(String(policyCategoryApplication)), categoryRank(String("Unknown")))
+}
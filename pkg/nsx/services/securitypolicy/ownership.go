@@ -0,0 +1,84 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// ownerNow is the clock of the ownership leases, it's replaced in tests.
+var ownerNow = time.Now
+
+// The NSX SecurityPolicy written by an nsx-operator instance is tagged with the owner tag, whose value is
+// "<instance id>|<lease expiry in unix seconds>". The instance refuses to modify the NSX SecurityPolicy,
+// and its rules and groups, owned by another instance before the lease expires, or created by NCP.
+
+func (service *SecurityPolicyService) ownershipLocked() bool {
+	return service.NSXConfig != nil && service.NSXConfig.CoeConfig != nil && service.NSXConfig.InstanceID != ""
+}
+
+// parseOwnerTag returns the owner instance and the lease expiry in the owner tag, ok is false if there
+// is no valid owner tag.
+func parseOwnerTag(tags []model.Tag) (owner string, expiry time.Time, ok bool) {
+	for _, tag := range tags {
+		if tag.Scope == nil || tag.Tag == nil || *tag.Scope != common.TagScopeOwner {
+			continue
+		}
+		owner, lease, found := strings.Cut(*tag.Tag, "|")
+		if !found {
+			return "", time.Time{}, false
+		}
+		seconds, err := strconv.ParseInt(lease, 10, 64)
+		if err != nil {
+			return "", time.Time{}, false
+		}
+		return owner, time.Unix(seconds, 0), true
+	}
+	return "", time.Time{}, false
+}
+
+// checkOwnership returns an OwnershipConflictError if the existing NSX SecurityPolicy is created by NCP,
+// or owned by another instance whose lease hasn't expired.
+func (service *SecurityPolicyService) checkOwnership(existing *model.SecurityPolicy) error {
+	if existing == nil || !service.ownershipLocked() {
+		return nil
+	}
+	for _, tag := range existing.Tags {
+		if tag.Scope != nil && *tag.Scope == common.TagScopeNCPCreatedFor {
+			return nsxutil.OwnershipConflictError{Desc: fmt.Sprintf("NSX SecurityPolicy %s is owned by NCP", *existing.Id)}
+		}
+	}
+	owner, expiry, ok := parseOwnerTag(existing.Tags)
+	if !ok || owner == service.NSXConfig.InstanceID || !ownerNow().Before(expiry) {
+		return nil
+	}
+	return nsxutil.OwnershipConflictError{
+		Desc: fmt.Sprintf("NSX SecurityPolicy %s is owned by nsx-operator instance %s until %s", *existing.Id, owner, expiry.UTC().Format(time.RFC3339)),
+	}
+}
+
+// buildOwnerTag returns the owner tag of this instance for the NSX SecurityPolicy. The tag of the existing
+// NSX SecurityPolicy is kept until half of the lease elapses, so the lease renewal doesn't change the
+// NSX SecurityPolicy on every reconcile.
+func (service *SecurityPolicyService) buildOwnerTag(existing *model.SecurityPolicy) *model.Tag {
+	if !service.ownershipLocked() {
+		return nil
+	}
+	lease := service.NSXConfig.GetOwnershipLease()
+	now := ownerNow()
+	if existing != nil {
+		if owner, expiry, ok := parseOwnerTag(existing.Tags); ok && owner == service.NSXConfig.InstanceID && expiry.Sub(now) > lease/2 {
+			return &model.Tag{Scope: String(common.TagScopeOwner), Tag: String(fmt.Sprintf("%s|%d", owner, expiry.Unix()))}
+		}
+	}
+	return &model.Tag{Scope: String(common.TagScopeOwner), Tag: String(fmt.Sprintf("%s|%d", service.NSXConfig.InstanceID, now.Add(lease).Unix()))}
+}
@@ -0,0 +1,54 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func TestOwnership(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ownerNow = func() time.Time { return now }
+	defer func() { ownerNow = time.Now }()
+
+	s := &SecurityPolicyService{Service: common.Service{NSXConfig: &config.NSXOperatorConfig{
+		CoeConfig: &config.CoeConfig{Cluster: "k8scl-one", OwnershipLease: 600},
+	}}}
+	ownedBy := func(tag string) *model.SecurityPolicy {
+		return &model.SecurityPolicy{Id: String("sp1"), Tags: []model.Tag{{Scope: String(common.TagScopeOwner), Tag: String(tag)}}}
+	}
+
+	// the ownership isn't locked without the instance id
+	assert.Nil(t, s.buildOwnerTag(nil))
+	assert.NoError(t, s.checkOwnership(ownedBy("op-b|1700000300")))
+
+	s.NSXConfig.InstanceID = "op-a"
+	assert.Equal(t, "op-a|1700000600", *s.buildOwnerTag(nil).Tag)
+
+	// the lease of this instance is kept until half of it elapses
+	assert.NoError(t, s.checkOwnership(ownedBy("op-a|1700000400")))
+	assert.Equal(t, "op-a|1700000400", *s.buildOwnerTag(ownedBy("op-a|1700000400")).Tag)
+	assert.Equal(t, "op-a|1700000600", *s.buildOwnerTag(ownedBy("op-a|1700000200")).Tag)
+
+	// the object owned by another instance can't be modified until the lease expires
+	err := s.checkOwnership(ownedBy("op-b|1700000300"))
+	assert.True(t, errors.As(err, &nsxutil.OwnershipConflictError{}))
+	assert.Contains(t, err.Error(), "owned by nsx-operator instance op-b")
+	assert.NoError(t, s.checkOwnership(ownedBy("op-b|1700000000")))
+	assert.NoError(t, s.checkOwnership(ownedBy("invalid")))
+
+	// the object created by NCP can't be modified
+	ncp := &model.SecurityPolicy{Id: String("sp1"), Tags: []model.Tag{{Scope: String(common.TagScopeNCPCreatedFor), Tag: String("SecurityPolicy")}}}
+	err = s.checkOwnership(ncp)
+	assert.EqualError(t, err, "NSX SecurityPolicy sp1 is owned by NCP")
+}
@@ -12,6 +12,8 @@ var validRuleActions = []string{
 	util.ToUpper(v1alpha1.RuleActionAllow),
 	util.ToUpper(v1alpha1.RuleActionDrop),
 	util.ToUpper(v1alpha1.RuleActionReject),
+	util.ToUpper(v1alpha1.RuleActionRedirect),
+	util.ToUpper(v1alpha1.RuleActionPass),
 }
 
 var (
@@ -46,11 +48,18 @@ func getCluster(service *SecurityPolicyService) string {
 }
 
 func getDomain(service *SecurityPolicyService) string {
-	return getCluster(service)
+	return service.NSXConfig.GetDomain()
 }
 
-func getVpcProjectDomain() string {
-	return "default"
+// getVpcProjectDomain returns the domain of the NSX Project of the namespace, the VPCNetworkConfiguration
+// of the namespace may override the one of nsx-operator.
+func getVpcProjectDomain(service *SecurityPolicyService, namespace string) string {
+	if service.vpcService != nil {
+		if networkConfig := service.vpcService.GetVPCNetworkConfigByNamespace(namespace); networkConfig != nil && networkConfig.NSXDomain != "" {
+			return networkConfig.NSXDomain
+		}
+	}
+	return service.NSXConfig.GetProjectDomain()
 }
 
 func isVpcEnabled(service *SecurityPolicyService) bool {
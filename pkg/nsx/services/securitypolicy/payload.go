@@ -0,0 +1,51 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/bindings"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data/serializers/cleanjson"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// validateInfra validates the hierarchical payload under /infra against the OpenAPI schema of NSX Manager.
+func (service *SecurityPolicyService) validateInfra(infra *model.Infra) error {
+	return service.validatePayload(common.ResourceTypeInfra, *infra, model.InfraBindingType())
+}
+
+// validateOrgRoot validates the hierarchical payload under the VPCs against the OpenAPI schema of NSX Manager.
+func (service *SecurityPolicyService) validateOrgRoot(orgRoot *model.OrgRoot) error {
+	return service.validatePayload(common.ResourceTypeOrgRoot, *orgRoot, model.OrgRootBindingType())
+}
+
+// validatePayload validates the payload in JSON as it's sent to NSX, if the schema is loaded. The payload not
+// matching the schema, e.g. with a field the NSX version doesn't support, won't be accepted by NSX until it's
+// upgraded, so the error is a RestrictionError which is not retried.
+func (service *SecurityPolicyService) validatePayload(name string, payload interface{}, bindingType bindings.BindingType) error {
+	if service.NSXClient == nil || service.NSXClient.Schema == nil {
+		return nil
+	}
+	dataValue, errs := NewConverter().ConvertToVapi(payload, bindingType)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	encoded, err := cleanjson.NewDataValueToJsonEncoder().Encode(dataValue)
+	if err != nil {
+		return err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+		return err
+	}
+	if err := service.NSXClient.Schema.Validate(name, decoded); err != nil {
+		return nsxutil.RestrictionError{Desc: fmt.Sprintf("%s payload rejected by the NSX Manager schema: %s", name, err)}
+	}
+	return nil
+}
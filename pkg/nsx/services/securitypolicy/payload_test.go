@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/schema"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func TestSecurityPolicyService_validateInfra(t *testing.T) {
+	service := fakeService()
+	sp := &model.SecurityPolicy{Id: String("sp"), DisplayName: String("sp"), Rules: []model.Rule{{Id: String("rule"), Action: String("ALLOW")}}}
+	infra, err := service.WrapHierarchySecurityPolicy(sp, nil)
+	assert.NoError(t, err)
+
+	// the payload is not validated without the schema
+	assert.NoError(t, service.validateInfra(infra))
+
+	doc := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"definitions": {
+		"Infra": {"properties": {"resource_type": {"type": "string"}, "children": {"type": "array", "items": {"$ref": "#/definitions/ChildResourceReference"}}}},
+		"ChildResourceReference": {"properties": {"resource_type": {"type": "string"}, "id": {"type": "string"}, "target_type": {"type": "string"},
+			"children": {"type": "array", "items": {"type": "object"}}}}
+	}}`), &doc))
+	service.NSXClient.Schema, err = schema.Load(doc)
+	assert.NoError(t, err)
+	assert.NoError(t, service.validateInfra(infra))
+
+	// the field of an older NSX schema
+	doc["definitions"].(map[string]interface{})["ChildResourceReference"].(map[string]interface{})["properties"] = map[string]interface{}{
+		"resource_type": map[string]interface{}{"type": "string"},
+		"id":            map[string]interface{}{"type": "string"},
+	}
+	service.NSXClient.Schema, err = schema.Load(doc)
+	assert.NoError(t, err)
+	err = service.validateInfra(infra)
+	assert.True(t, errors.As(err, &nsxutil.RestrictionError{}))
+	assert.EqualError(t, err, "Infra payload rejected by the NSX Manager schema: field children[0].children is not defined for ChildResourceReference by NSX")
+}
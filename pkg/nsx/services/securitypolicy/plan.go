@@ -0,0 +1,70 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"sort"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// Plan describes the NSX resources which would be patched to realize the SecurityPolicy CR, compared
+// with the NSX resources realized in store.
+type Plan struct {
+	SecurityPolicyID      string   `json:"securityPolicyID"`
+	SecurityPolicyChanged bool     `json:"securityPolicyChanged"`
+	ChangedRules          []string `json:"changedRules,omitempty"`
+	StaleRules            []string `json:"staleRules,omitempty"`
+	ChangedGroups         []string `json:"changedGroups,omitempty"`
+	StaleGroups           []string `json:"staleGroups,omitempty"`
+}
+
+// PlanSecurityPolicy builds the NSX resources of the SecurityPolicy CR and compares them with the
+// realized ones the same way as the realization, without patching NSX.
+func (service *SecurityPolicyService) PlanSecurityPolicy(obj *v1alpha1.SecurityPolicy) (*Plan, error) {
+	securityPolicyStore, ruleStore, groupStore, _, _ := service.getStores()
+	nsxSecurityPolicy, nsxGroups, err := service.buildUnrealizedSecurityPolicy(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	existingSecurityPolicy := securityPolicyStore.GetByKey(*nsxSecurityPolicy.Id)
+	if ownerTag := service.buildOwnerTag(existingSecurityPolicy); ownerTag != nil {
+		nsxSecurityPolicy.Tags = append(nsxSecurityPolicy.Tags, *ownerTag)
+	}
+	plan := &Plan{SecurityPolicyID: *nsxSecurityPolicy.Id, SecurityPolicyChanged: true}
+	if existingSecurityPolicy != nil {
+		plan.SecurityPolicyChanged = common.CompareResource(SecurityPolicyPtrToComparable(existingSecurityPolicy), SecurityPolicyPtrToComparable(nsxSecurityPolicy))
+	}
+	indexScope := common.TagValueScopeSecurityPolicyUID
+	changed, stale := common.CompareResources(RulesPtrToComparable(ruleStore.GetByIndex(indexScope, string(obj.UID))), RulesToComparable(nsxSecurityPolicy.Rules))
+	plan.ChangedRules, plan.StaleRules = comparableKeys(changed), comparableKeys(stale)
+	changed, stale = common.CompareResources(GroupsPtrToComparable(groupStore.GetByIndex(indexScope, string(obj.UID))), GroupsToComparable(*nsxGroups))
+	plan.ChangedGroups, plan.StaleGroups = comparableKeys(changed), comparableKeys(stale)
+	return plan, nil
+}
+
+// buildUnrealizedSecurityPolicy builds the NSX resources of the SecurityPolicy CR which are not going to be
+// patched, it doesn't replace the rule budgets of the realized SecurityPolicy.
+func (service *SecurityPolicyService) buildUnrealizedSecurityPolicy(obj *v1alpha1.SecurityPolicy) (*model.SecurityPolicy, *[]model.Group, error) {
+	if budgets, ok := service.ruleBudgets.Load(obj.UID); ok {
+		defer service.ruleBudgets.Store(obj.UID, budgets)
+	} else {
+		defer service.ruleBudgets.Delete(obj.UID)
+	}
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(obj, common.ResourceTypeSecurityPolicy)
+	return nsxSecurityPolicy, nsxGroups, err
+}
+
+func comparableKeys(comparables []Comparable) []string {
+	keys := make([]string, 0, len(comparables))
+	for _, c := range comparables {
+		keys = append(keys, c.Key())
+	}
+	sort.Strings(keys)
+	return keys
+}
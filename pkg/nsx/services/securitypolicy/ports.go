@@ -0,0 +1,88 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// maxServiceEntryPorts is the max count of the ports and port ranges in the destination ports of one NSX
+// service entry.
+const maxServiceEntryPorts = 15
+
+// portInterval is the range of ports from start to end, both included.
+type portInterval struct {
+	start, end int
+}
+
+func (i portInterval) String() string {
+	if i.start == i.end {
+		return fmt.Sprint(i.start)
+	}
+	return fmt.Sprintf("%d-%d", i.start, i.end)
+}
+
+// mergePortIntervals sorts the port ranges and merges the overlapping and adjacent ones.
+func mergePortIntervals(intervals []portInterval) []portInterval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start < intervals[j].start || intervals[i].start == intervals[j].start && intervals[i].end < intervals[j].end
+	})
+	var merged []portInterval
+	for _, interval := range intervals {
+		if last := len(merged) - 1; last >= 0 && interval.start <= merged[last].end+1 {
+			if interval.end > merged[last].end {
+				merged[last].end = interval.end
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// buildRuleServiceEntriesOfPorts builds the service entries of the numeric ports of a rule. The overlapping
+// and adjacent port ranges of a protocol are merged, and the merged ranges of a protocol are put in as few
+// service entries as NSX allows, the protocols are kept in the order of the ports. The ports without number,
+// which match all the ports of the protocol, are built as they are.
+func (service *SecurityPolicyService) buildRuleServiceEntriesOfPorts(ports []v1alpha1.SecurityPolicyPort) []*data.StructValue {
+	var serviceEntries []*data.StructValue
+	var protocols []corev1.Protocol
+	intervals := map[corev1.Protocol][]portInterval{}
+	for _, port := range ports {
+		start := port.Port.IntValue()
+		if start == 0 {
+			serviceEntries = append(serviceEntries, service.buildRuleServiceEntries(port, nsxutil.PortAddress{Port: start}))
+			continue
+		}
+		end := port.EndPort
+		if end < start {
+			end = start
+		}
+		if _, ok := intervals[port.Protocol]; !ok {
+			protocols = append(protocols, port.Protocol)
+		}
+		intervals[port.Protocol] = append(intervals[port.Protocol], portInterval{start: start, end: end})
+	}
+	for _, protocol := range protocols {
+		merged := mergePortIntervals(intervals[protocol])
+		for len(merged) > 0 {
+			count := min(len(merged), maxServiceEntryPorts)
+			destinationPorts := data.NewListValue()
+			for _, interval := range merged[:count] {
+				destinationPorts.Add(data.NewStringValue(interval.String()))
+			}
+			serviceEntries = append(serviceEntries, newL4PortSetServiceEntry(data.NewListValue(), destinationPorts, protocol))
+			log.V(1).Info("built rule service entry", "destinationPorts", merged[:count], "protocol", protocol)
+			merged = merged[count:]
+		}
+	}
+	return serviceEntries
+}
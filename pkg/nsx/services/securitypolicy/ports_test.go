@@ -0,0 +1,65 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestMergePortIntervals(t *testing.T) {
+	assert.Equal(t, []portInterval{{1, 10}, {20, 30}, {40, 40}}, mergePortIntervals([]portInterval{
+		{40, 40}, {5, 10}, {20, 25}, {1, 6}, {22, 30}, {8, 9},
+	}))
+	// the adjacent ranges are merged
+	assert.Equal(t, []portInterval{{80, 90}}, mergePortIntervals([]portInterval{{85, 90}, {80, 84}}))
+	assert.Empty(t, mergePortIntervals(nil))
+}
+
+func serviceEntryPorts(t *testing.T, entry *data.StructValue) (string, []string) {
+	protocol, err := entry.String("l4_protocol")
+	assert.NoError(t, err)
+	ports, err := entry.List("destination_ports")
+	assert.NoError(t, err)
+	var destinationPorts []string
+	for _, port := range ports.List() {
+		destinationPorts = append(destinationPorts, port.(*data.StringValue).Value())
+	}
+	return protocol, destinationPorts
+}
+
+func TestBuildRuleServiceEntriesOfPorts(t *testing.T) {
+	ports := []v1alpha1.SecurityPolicyPort{
+		{Protocol: corev1.ProtocolUDP, Port: intstr.FromInt(53)},
+		{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(8080), EndPort: 8090},
+		{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(443)},
+		{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(8085), EndPort: 8100},
+		{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(8101)},
+		{Protocol: corev1.ProtocolUDP, Port: intstr.FromInt(53), EndPort: 53},
+	}
+	entries := service.buildRuleServiceEntriesOfPorts(ports)
+	assert.Len(t, entries, 2)
+	protocol, destinationPorts := serviceEntryPorts(t, entries[0])
+	assert.Equal(t, "UDP", protocol)
+	assert.Equal(t, []string{"53"}, destinationPorts)
+	protocol, destinationPorts = serviceEntryPorts(t, entries[1])
+	assert.Equal(t, "TCP", protocol)
+	assert.Equal(t, []string{"443", "8080-8101"}, destinationPorts)
+
+	// the ranges beyond the NSX limit of a service entry are put in another one
+	ports = nil
+	for i := 0; i <= maxServiceEntryPorts; i++ {
+		ports = append(ports, v1alpha1.SecurityPolicyPort{Protocol: corev1.ProtocolTCP, Port: intstr.FromInt(1000 + 10*i)})
+	}
+	entries = service.buildRuleServiceEntriesOfPorts(ports)
+	assert.Len(t, entries, 2)
+	_, destinationPorts = serviceEntryPorts(t, entries[1])
+	assert.Equal(t, []string{"1150"}, destinationPorts)
+}
@@ -0,0 +1,62 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// prioritySpreadFactor scales the priorities to the NSX sequence numbers when the SecurityPolicies with the same
+// priority are spread, up to 100 SecurityPolicies of a priority get a distinct sequence number.
+const prioritySpreadFactor = 100
+
+// PriorityConflicts returns the SecurityPolicies declaring the same priority as the SecurityPolicy, sorted by
+// namespace and name, and the rank of the SecurityPolicy among them. The SecurityPolicies being deleted are
+// left out.
+func PriorityConflicts(obj *v1alpha1.SecurityPolicy, policies []v1alpha1.SecurityPolicy) ([]types.NamespacedName, int) {
+	var conflicts []types.NamespacedName
+	for i := range policies {
+		policy := &policies[i]
+		if policy.UID == obj.UID || policy.Spec.Priority != obj.Spec.Priority || !policy.DeletionTimestamp.IsZero() {
+			continue
+		}
+		conflicts = append(conflicts, types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].String() < conflicts[j].String()
+	})
+	self := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+	rank := sort.Search(len(conflicts), func(i int) bool { return conflicts[i].String() > self })
+	return conflicts, rank
+}
+
+// PrioritySpreading returns whether the SecurityPolicies with the same priority get distinct sequence numbers.
+func (service *SecurityPolicyService) PrioritySpreading() bool {
+	return service.NSXConfig != nil && service.NSXConfig.K8sConfig != nil && service.NSXConfig.PrioritySpreading
+}
+
+// SetPriorityRank sets the rank of the SecurityPolicy CR among the CRs with the same priority, it returns whether
+// the rank has changed and the NSX SecurityPolicy has to be patched again.
+func (service *SecurityPolicyService) SetPriorityRank(uid types.UID, rank int) bool {
+	previous, loaded := service.priorityRanks.Swap(uid, rank)
+	return !loaded || previous.(int) != rank
+}
+
+// policySequenceNumber returns the NSX sequence number of the SecurityPolicy, which is its priority unless the
+// SecurityPolicies with the same priority are spread by their rank.
+func (service *SecurityPolicyService) policySequenceNumber(obj *v1alpha1.SecurityPolicy, createdFor string) int64 {
+	if createdFor != common.ResourceTypeSecurityPolicy || !service.PrioritySpreading() {
+		return int64(obj.Spec.Priority)
+	}
+	rank := 0
+	if value, ok := service.priorityRanks.Load(obj.UID); ok {
+		rank = min(value.(int), prioritySpreadFactor-1)
+	}
+	return int64(obj.Spec.Priority)*prioritySpreadFactor + int64(rank)
+}
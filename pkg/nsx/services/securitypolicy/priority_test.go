@@ -0,0 +1,62 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestPriorityConflicts(t *testing.T) {
+	newPolicy := func(namespace, name string, priority int) v1alpha1.SecurityPolicy {
+		return v1alpha1.SecurityPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "-" + name)},
+			Spec:       v1alpha1.SecurityPolicySpec{Priority: priority},
+		}
+	}
+	deleting := newPolicy("ns-0", "deleting", 5)
+	deletionTime := metav1.Now()
+	deleting.DeletionTimestamp = &deletionTime
+	policies := []v1alpha1.SecurityPolicy{
+		newPolicy("ns-2", "sp-a", 5),
+		newPolicy("ns-1", "sp-b", 5),
+		newPolicy("ns-1", "sp-a", 4),
+		newPolicy("ns-1", "sp-c", 5),
+		deleting,
+	}
+	conflicts, rank := PriorityConflicts(&policies[1], policies)
+	assert.Equal(t, []types.NamespacedName{{Namespace: "ns-1", Name: "sp-c"}, {Namespace: "ns-2", Name: "sp-a"}}, conflicts)
+	assert.Equal(t, 0, rank)
+	_, rank = PriorityConflicts(&policies[0], policies)
+	assert.Equal(t, 2, rank)
+	conflicts, rank = PriorityConflicts(&policies[2], policies)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, 0, rank)
+}
+
+func TestPolicySequenceNumber(t *testing.T) {
+	s := &SecurityPolicyService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{K8sConfig: &config.K8sConfig{}},
+		},
+	}
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Priority = 5
+	assert.True(t, s.SetPriorityRank(sp.UID, 2))
+	assert.False(t, s.SetPriorityRank(sp.UID, 2))
+	assert.Equal(t, int64(5), s.policySequenceNumber(sp, common.ResourceTypeSecurityPolicy))
+
+	s.NSXConfig.PrioritySpreading = true
+	assert.Equal(t, int64(502), s.policySequenceNumber(sp, common.ResourceTypeSecurityPolicy))
+	assert.Equal(t, int64(5), s.policySequenceNumber(sp, common.ResourceTypeNetworkPolicy))
+	assert.True(t, s.SetPriorityRank(sp.UID, 150))
+	assert.Equal(t, int64(599), s.policySequenceNumber(sp, common.ResourceTypeSecurityPolicy))
+}
@@ -0,0 +1,147 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// RealizedEntityType is the type of the entity the NSX SecurityPolicy is realized to.
+const RealizedEntityType = "RealizedFirewallSection"
+
+// RealizedResources are the IDs of the NSX resources realized for a SecurityPolicy CR.
+type RealizedResources struct {
+	SecurityPolicies []string `json:"securityPolicies,omitempty"`
+	Rules            []string `json:"rules,omitempty"`
+	Groups           []string `json:"groups,omitempty"`
+	Shares           []string `json:"shares,omitempty"`
+}
+
+// ListRealizedResources returns the IDs of the NSX resources in the store realized for each
+// SecurityPolicy CR, keyed by the CR UID they are tagged with.
+func (service *SecurityPolicyService) ListRealizedResources() map[string]*RealizedResources {
+	securityPolicyStore, ruleStore, groupStore, projectGroupStore, shareStore := service.getStores()
+	indexScope := common.TagValueScopeSecurityPolicyUID
+	realized := map[string]*RealizedResources{}
+	for uid := range service.ListSecurityPolicyID() {
+		resources := &RealizedResources{}
+		for _, policy := range securityPolicyStore.GetByIndex(indexScope, uid) {
+			resources.SecurityPolicies = append(resources.SecurityPolicies, *policy.Id)
+		}
+		for _, rule := range ruleStore.GetByIndex(indexScope, uid) {
+			resources.Rules = append(resources.Rules, *rule.Id)
+		}
+		for _, group := range groupStore.GetByIndex(indexScope, uid) {
+			resources.Groups = append(resources.Groups, *group.Id)
+		}
+		for _, group := range projectGroupStore.GetByIndex(indexScope, uid) {
+			resources.Groups = append(resources.Groups, *group.Id)
+		}
+		for _, share := range shareStore.GetByIndex(indexScope, uid) {
+			resources.Shares = append(resources.Shares, *share.Id)
+		}
+		sort.Strings(resources.SecurityPolicies)
+		sort.Strings(resources.Rules)
+		sort.Strings(resources.Groups)
+		sort.Strings(resources.Shares)
+		realized[uid] = resources
+	}
+	return realized
+}
+
+// IsRealizedFor checks whether the NSX SecurityPolicy tagged with the CR UID was realized for the
+// SecurityPolicy CR with the namespace and name, so a CR can only adopt the NSX resources realized
+// for a previous CR of the same namespace and name.
+func (service *SecurityPolicyService) IsRealizedFor(uid types.UID, namespace, name string) bool {
+	securityPolicyStore, _, _, _, _ := service.getStores()
+	for _, policy := range securityPolicyStore.GetByIndex(common.TagValueScopeSecurityPolicyUID, string(uid)) {
+		namespaces := filterTag(policy.Tags, common.TagScopeNamespace)
+		names := filterTag(policy.Tags, common.TagValueScopeSecurityPolicyName)
+		if len(namespaces) == 1 && namespaces[0] == namespace && len(names) == 1 && names[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RealizedName returns the namespace and name of the SecurityPolicy CR which the NSX SecurityPolicy tagged
+// with the CR UID was realized for, the CR may have been removed.
+func (service *SecurityPolicyService) RealizedName(uid types.UID) (types.NamespacedName, bool) {
+	securityPolicyStore, _, _, _, _ := service.getStores()
+	for _, policy := range securityPolicyStore.GetByIndex(common.TagValueScopeSecurityPolicyUID, string(uid)) {
+		namespaces := filterTag(policy.Tags, common.TagScopeNamespace)
+		names := filterTag(policy.Tags, common.TagValueScopeSecurityPolicyName)
+		if len(namespaces) == 1 && len(names) == 1 {
+			return types.NamespacedName{Namespace: namespaces[0], Name: names[0]}, true
+		}
+	}
+	return types.NamespacedName{}, false
+}
+
+// SupersededUIDs returns the UIDs of the previous SecurityPolicy CRs of the same namespace and name as the CR,
+// whose NSX resources are still realized, e.g. the CR or its Namespace was deleted and recreated with a new
+// UID while the deletion was missed. The UIDs are sorted.
+func (service *SecurityPolicyService) SupersededUIDs(obj *v1alpha1.SecurityPolicy) []types.UID {
+	var uids []types.UID
+	for uid := range service.ListSecurityPolicyID() {
+		if types.UID(uid) == obj.UID {
+			continue
+		}
+		if name, ok := service.RealizedName(types.UID(uid)); ok && name.Namespace == obj.Namespace && name.Name == obj.Name {
+			uids = append(uids, types.UID(uid))
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids
+}
+
+// RealizationPath returns the intent path of the NSX SecurityPolicy realized for the CR, whose realization
+// can be checked. It returns false out of VPC, where the realization isn't checked.
+func (service *SecurityPolicyService) RealizationPath(obj *v1alpha1.SecurityPolicy) (string, bool) {
+	if !isVpcEnabled(service) {
+		return "", false
+	}
+	vpcInfo, err := service.getVpcInfo(obj.Namespace)
+	if err != nil {
+		log.Error(err, "failed to get VPC of SecurityPolicy", "namespace", obj.Namespace, "name", obj.Name)
+		return "", false
+	}
+	return fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/security-policies/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID,
+		service.buildecurityPolicyID(obj, common.ResourceTypeSecurityPolicy)), true
+}
+
+// RealizationIntents returns the intent paths of the NSX SecurityPolicy, rules and groups in the stores
+// realized for the CR, whose realization is reported in the CR status. The groups shared by the Project
+// are not included.
+func (service *SecurityPolicyService) RealizationIntents(obj *v1alpha1.SecurityPolicy) ([]string, error) {
+	basePath := fmt.Sprintf("/infra/domains/%s", getDomain(service))
+	if isVpcEnabled(service) {
+		vpcInfo, err := service.getVpcInfo(obj.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		basePath = fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID)
+	}
+	securityPolicyStore, ruleStore, groupStore, _, _ := service.getStores()
+	indexScope := common.TagValueScopeSecurityPolicyUID
+	var intents []string
+	for _, policy := range securityPolicyStore.GetByIndex(indexScope, string(obj.UID)) {
+		policyPath := fmt.Sprintf("%s/security-policies/%s", basePath, *policy.Id)
+		intents = append(intents, policyPath)
+		for _, rule := range ruleStore.GetByIndex(indexScope, string(obj.UID)) {
+			intents = append(intents, fmt.Sprintf("%s/rules/%s", policyPath, *rule.Id))
+		}
+	}
+	for _, group := range groupStore.GetByIndex(indexScope, string(obj.UID)) {
+		intents = append(intents, fmt.Sprintf("%s/groups/%s", basePath, *group.Id))
+	}
+	sort.Strings(intents)
+	return intents, nil
+}
@@ -0,0 +1,68 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestListRealizedResources(t *testing.T) {
+	service := newDeleteTestService(&taggedQueryClient{})
+	indexers := cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}
+	service.projectGroupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.GroupBindingType(),
+	}}
+	service.shareStore = &ShareStore{ResourceStore: common.ResourceStore{
+		Indexer: cache.NewIndexer(keyFunc, indexers), BindingType: model.ShareBindingType(),
+	}}
+	uid, ns, name := "sp-uid", "ns1", "sp1"
+	tags := []model.Tag{
+		{Scope: &tagScopeSecurityPolicyCRUID, Tag: &uid},
+		{Scope: &tagScopeNamespace, Tag: &ns},
+		{Scope: &tagScopeSecurityPolicyCRName, Tag: &name},
+	}
+	service.securityPolicyStore.Add(&model.SecurityPolicy{Id: String("sp1-id"), Tags: tags})
+	service.ruleStore.Add(&model.Rule{Id: String("sp1-id-rule-2"), Tags: tags})
+	service.ruleStore.Add(&model.Rule{Id: String("sp1-id-rule-1"), Tags: tags})
+	service.groupStore.Add(&model.Group{Id: String("sp1-id-scope"), Tags: tags})
+	service.projectGroupStore.Add(&model.Group{Id: String("sp1-id-src"), Tags: tags})
+	service.shareStore.Add(&model.Share{Id: String("sp1-id-share"), Tags: tags})
+
+	assert.Equal(t, map[string]*RealizedResources{
+		uid: {
+			SecurityPolicies: []string{"sp1-id"},
+			Rules:            []string{"sp1-id-rule-1", "sp1-id-rule-2"},
+			Groups:           []string{"sp1-id-scope", "sp1-id-src"},
+			Shares:           []string{"sp1-id-share"},
+		},
+	}, service.ListRealizedResources())
+
+	assert.True(t, service.IsRealizedFor("sp-uid", "ns1", "sp1"))
+	assert.False(t, service.IsRealizedFor("sp-uid", "ns2", "sp1"))
+	assert.False(t, service.IsRealizedFor("sp-uid", "ns1", "sp2"))
+	assert.False(t, service.IsRealizedFor("other-uid", "ns1", "sp1"))
+
+	realizedName, ok := service.RealizedName("sp-uid")
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Namespace: "ns1", Name: "sp1"}, realizedName)
+	_, ok = service.RealizedName("other-uid")
+	assert.False(t, ok)
+
+	// the NSX resources of the previous CR are superseded by the CR recreated with a new UID
+	recreated := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp1", UID: "new-uid"}}
+	assert.Equal(t, []types.UID{"sp-uid"}, service.SupersededUIDs(recreated))
+	recreated.UID = "sp-uid"
+	assert.Empty(t, service.SupersededUIDs(recreated))
+	other := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "sp1", UID: "new-uid"}}
+	assert.Empty(t, service.SupersededUIDs(other))
+}
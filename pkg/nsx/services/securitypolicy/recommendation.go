@@ -0,0 +1,249 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// recommendationsAPI is the NSX Intelligence API listing the published micro-segmentation recommendations.
+const recommendationsAPI = "napp/api/v1/recommendation/recommendations"
+
+// Recommendation is a micro-segmentation recommendation of NSX Intelligence.
+type Recommendation struct {
+	ID    string
+	Name  string
+	Rules []RecommendedRule
+}
+
+// RecommendedRule is a rule of a Recommendation, the members are described by IPs.
+type RecommendedRule struct {
+	Name      string
+	Action    v1alpha1.RuleAction
+	Direction v1alpha1.RuleDirection
+	// AppliedTo are the IPs of the members the rule is applied to.
+	AppliedTo []string
+	// Peers are the IPs or CIDRs of the sources of an ingress rule or the destinations of an egress rule.
+	Peers []string
+	Ports []v1alpha1.SecurityPolicyPort
+}
+
+// RecommendationSource lists the recommendations ready to be ingested.
+type RecommendationSource interface {
+	ListRecommendations() ([]Recommendation, error)
+}
+
+type intelligenceRecommendationSource struct {
+	cluster *nsx.Cluster
+}
+
+// RecommendationSource returns the recommendations published by NSX Intelligence.
+func (service *SecurityPolicyService) RecommendationSource() RecommendationSource {
+	return &intelligenceRecommendationSource{cluster: service.NSXClient.Cluster}
+}
+
+func (s *intelligenceRecommendationSource) ListRecommendations() ([]Recommendation, error) {
+	var recommendations []Recommendation
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("status", "PUBLISHED")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := s.cluster.HttpGet(fmt.Sprintf("%s?%s", recommendationsAPI, query.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, parseRecommendations(resp)...)
+		cursor, _ = resp["cursor"].(string)
+		if cursor == "" {
+			return recommendations, nil
+		}
+	}
+}
+
+func parseRecommendations(resp map[string]interface{}) []Recommendation {
+	results, _ := resp["results"].([]interface{})
+	recommendations := make([]Recommendation, 0, len(results))
+	for _, result := range results {
+		item, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		recommendation := Recommendation{}
+		recommendation.ID, _ = item["id"].(string)
+		recommendation.Name, _ = item["display_name"].(string)
+		if recommendation.ID == "" {
+			continue
+		}
+		rules, _ := item["rules"].([]interface{})
+		for _, r := range rules {
+			ruleItem, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rule := RecommendedRule{
+				AppliedTo: parseStrings(ruleItem["applied_to_ips"]),
+				Peers:     parseStrings(ruleItem["peer_ips"]),
+			}
+			rule.Name, _ = ruleItem["display_name"].(string)
+			action, _ := ruleItem["action"].(string)
+			rule.Action = v1alpha1.RuleAction(util.Capitalize(strings.ToLower(action)))
+			direction, _ := ruleItem["direction"].(string)
+			rule.Direction = v1alpha1.RuleDirection(util.Capitalize(strings.ToLower(direction)))
+			services, _ := ruleItem["services"].([]interface{})
+			for _, svc := range services {
+				serviceItem, ok := svc.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				port := v1alpha1.SecurityPolicyPort{}
+				protocol, _ := serviceItem["protocol"].(string)
+				port.Protocol = v1.Protocol(protocol)
+				// JSON numbers are decoded as float64
+				if number, ok := serviceItem["port"].(float64); ok {
+					port.Port = intstr.FromInt(int(number))
+				}
+				if endPort, ok := serviceItem["end_port"].(float64); ok {
+					port.EndPort = int(endPort)
+				}
+				rule.Ports = append(rule.Ports, port)
+			}
+			recommendation.Rules = append(recommendation.Rules, rule)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations
+}
+
+func parseStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var result []string
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// RecommendedSecurityPolicyName returns the name of the SecurityPolicy materialized from the recommendation.
+func RecommendedSecurityPolicyName(recommendationID string) string {
+	return "recommendation-" + util.Sha1(recommendationID)[:12]
+}
+
+// BuildRecommendedSecurityPolicy materializes the recommendation as a SecurityPolicy. The members a rule
+// is applied to must be Pods in one Namespace of the cluster, they are selected by the labels shared by
+// the Pods, while the peers are kept as IP blocks. It returns error if the recommendation is not scoped
+// to the cluster.
+func BuildRecommendedSecurityPolicy(recommendation *Recommendation, resolve EndpointResolver) (*v1alpha1.SecurityPolicy, error) {
+	if len(recommendation.Rules) == 0 {
+		return nil, fmt.Errorf("recommendation %s has no rule", recommendation.ID)
+	}
+	namespace := ""
+	rules := make([]v1alpha1.SecurityPolicyRule, 0, len(recommendation.Rules))
+	for i := range recommendation.Rules {
+		recommended := &recommendation.Rules[i]
+		if len(recommended.AppliedTo) == 0 {
+			return nil, fmt.Errorf("rule %d of recommendation %s is not applied to any member", i, recommendation.ID)
+		}
+		var labels map[string]string
+		for _, ip := range recommended.AppliedTo {
+			endpoint := resolve(ip)
+			if endpoint == nil {
+				return nil, fmt.Errorf("member %s of recommendation %s is not a Pod in the cluster", ip, recommendation.ID)
+			}
+			if namespace == "" {
+				namespace = endpoint.Namespace
+			} else if namespace != endpoint.Namespace {
+				return nil, fmt.Errorf("recommendation %s spans Namespaces %s and %s", recommendation.ID, namespace, endpoint.Namespace)
+			}
+			labels = sharedLabels(labels, endpoint.Labels)
+		}
+		if len(labels) == 0 {
+			return nil, fmt.Errorf("members of rule %d of recommendation %s share no label", i, recommendation.ID)
+		}
+		action, direction := recommended.Action, recommended.Direction
+		rule := v1alpha1.SecurityPolicyRule{
+			Name:      recommended.Name,
+			Action:    &action,
+			Direction: &direction,
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{MatchLabels: labels}}},
+			Ports:     recommended.Ports,
+		}
+		ruleDirection, err := getRuleDirection(&rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d of recommendation %s: %w", i, recommendation.ID, err)
+		}
+		if len(recommended.Peers) > 0 {
+			peer := v1alpha1.SecurityPolicyPeer{}
+			for _, ip := range recommended.Peers {
+				cidr, err := hostCIDR(ip)
+				if err != nil {
+					return nil, fmt.Errorf("rule %d of recommendation %s: %w", i, recommendation.ID, err)
+				}
+				peer.IPBlocks = append(peer.IPBlocks, v1alpha1.IPBlock{CIDR: cidr})
+			}
+			if ruleDirection == "IN" {
+				rule.Sources = []v1alpha1.SecurityPolicyPeer{peer}
+			} else {
+				rule.Destinations = []v1alpha1.SecurityPolicyPeer{peer}
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        RecommendedSecurityPolicyName(recommendation.ID),
+			Annotations: map[string]string{common.AnnotationRecommendationID: recommendation.ID},
+		},
+		Spec: v1alpha1.SecurityPolicySpec{Rules: rules},
+	}, nil
+}
+
+// sharedLabels returns the labels in both shared and labels, shared is nil for the first member.
+func sharedLabels(shared, labels map[string]string) map[string]string {
+	if shared == nil {
+		result := make(map[string]string, len(labels))
+		for k, v := range labels {
+			result[k] = v
+		}
+		return result
+	}
+	for k, v := range shared {
+		if labels[k] != v {
+			delete(shared, k)
+		}
+	}
+	return shared
+}
+
+// hostCIDR returns the CIDR of an IP as a host route, a CIDR is returned as is.
+func hostCIDR(ip string) (string, error) {
+	if _, _, err := net.ParseCIDR(ip); err == nil {
+		return ip, nil
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("invalid IP %s", ip)
+	}
+	if parsed.To4() != nil {
+		return ip + "/32", nil
+	}
+	return ip + "/128", nil
+}
@@ -0,0 +1,122 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestParseRecommendations(t *testing.T) {
+	resp := map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{
+				"id":           "rec-1",
+				"display_name": "web",
+				"rules": []interface{}{
+					map[string]interface{}{
+						"display_name":   "allow-lb",
+						"action":         "ALLOW",
+						"direction":      "IN",
+						"applied_to_ips": []interface{}{"10.0.0.1", "10.0.0.2"},
+						"peer_ips":       []interface{}{"192.168.0.0/24"},
+						"services":       []interface{}{map[string]interface{}{"protocol": "TCP", "port": float64(8000), "end_port": float64(8080)}},
+					},
+					"invalid",
+				},
+			},
+			map[string]interface{}{"display_name": "no-id"},
+			"invalid",
+		},
+	}
+	expected := []Recommendation{{
+		ID:   "rec-1",
+		Name: "web",
+		Rules: []RecommendedRule{{
+			Name:      "allow-lb",
+			Action:    v1alpha1.RuleActionAllow,
+			Direction: v1alpha1.RuleDirectionIn,
+			AppliedTo: []string{"10.0.0.1", "10.0.0.2"},
+			Peers:     []string{"192.168.0.0/24"},
+			Ports:     []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolTCP, Port: intstr.FromInt(8000), EndPort: 8080}},
+		}},
+	}}
+	assert.Equal(t, expected, parseRecommendations(resp))
+	assert.Empty(t, parseRecommendations(map[string]interface{}{}))
+}
+
+func TestBuildRecommendedSecurityPolicy(t *testing.T) {
+	endpoints := map[string]*SimulationEndpoint{
+		"10.0.0.1": {Namespace: "ns1", Name: "web-1", Labels: map[string]string{"app": "web", "pod": "web-1"}},
+		"10.0.0.2": {Namespace: "ns1", Name: "web-2", Labels: map[string]string{"app": "web", "pod": "web-2"}},
+		"10.0.0.3": {Namespace: "ns2", Name: "db", Labels: map[string]string{"app": "db"}},
+		"10.0.0.4": {Namespace: "ns1", Name: "cache", Labels: map[string]string{"tier": "cache"}},
+	}
+	resolve := func(ip string) *SimulationEndpoint {
+		return endpoints[ip]
+	}
+	newRecommendation := func(direction v1alpha1.RuleDirection, appliedTo ...string) *Recommendation {
+		return &Recommendation{ID: "rec-1", Rules: []RecommendedRule{{
+			Name:      "rule",
+			Action:    v1alpha1.RuleActionAllow,
+			Direction: direction,
+			AppliedTo: appliedTo,
+			Peers:     []string{"192.168.0.1", "172.16.0.0/16"},
+		}}}
+	}
+
+	obj, err := BuildRecommendedSecurityPolicy(newRecommendation(v1alpha1.RuleDirectionIn, "10.0.0.1", "10.0.0.2"), resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", obj.Namespace)
+	assert.Equal(t, RecommendedSecurityPolicyName("rec-1"), obj.Name)
+	assert.Equal(t, "rec-1", obj.Annotations[common.AnnotationRecommendationID])
+	rule := obj.Spec.Rules[0]
+	assert.Equal(t, map[string]string{"app": "web"}, rule.AppliedTo[0].PodSelector.MatchLabels)
+	assert.Equal(t, []v1alpha1.IPBlock{{CIDR: "192.168.0.1/32"}, {CIDR: "172.16.0.0/16"}}, rule.Sources[0].IPBlocks)
+	assert.Empty(t, rule.Destinations)
+
+	obj, err = BuildRecommendedSecurityPolicy(newRecommendation(v1alpha1.RuleDirectionOut, "10.0.0.1"), resolve)
+	assert.NoError(t, err)
+	assert.Empty(t, obj.Spec.Rules[0].Sources)
+	assert.Len(t, obj.Spec.Rules[0].Destinations, 1)
+
+	tests := []struct {
+		name           string
+		recommendation *Recommendation
+		errMsg         string
+	}{
+		{name: "no rule", recommendation: &Recommendation{ID: "rec-1"}, errMsg: "has no rule"},
+		{name: "no member", recommendation: newRecommendation(v1alpha1.RuleDirectionIn), errMsg: "not applied to any member"},
+		{name: "unknown member", recommendation: newRecommendation(v1alpha1.RuleDirectionIn, "10.0.1.1"), errMsg: "not a Pod in the cluster"},
+		{name: "multiple namespaces", recommendation: newRecommendation(v1alpha1.RuleDirectionIn, "10.0.0.1", "10.0.0.3"), errMsg: "spans Namespaces"},
+		{name: "no shared label", recommendation: newRecommendation(v1alpha1.RuleDirectionIn, "10.0.0.1", "10.0.0.4"), errMsg: "share no label"},
+		{name: "invalid direction", recommendation: newRecommendation("Both", "10.0.0.1"), errMsg: "invalid rule direction"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildRecommendedSecurityPolicy(tt.recommendation, resolve)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHostCIDR(t *testing.T) {
+	cidr, err := hostCIDR("10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1/32", cidr)
+	cidr, err = hostCIDR("fd00::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "fd00::1/128", cidr)
+	cidr, err = hostCIDR("10.0.0.0/24")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/24", cidr)
+	_, err = hostCIDR("invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// NSX redirects the traffic to the partner services by the redirection policies, which are separated from the
+// DFW security policies. The Redirect rules of a SecurityPolicy are built like the other rules, then moved from
+// the NSX SecurityPolicy to a RedirectionPolicy per partner service, since NSX redirects the traffic of all the
+// rules of a RedirectionPolicy to the same service.
+
+func isRedirectRule(rule *v1alpha1.SecurityPolicyRule) bool {
+	return rule.Action != nil && util.ToUpper(*rule.Action) == util.ToUpper(v1alpha1.RuleActionRedirect)
+}
+
+// ValidateRedirect rejects the Redirect rules without the partner service to redirect the traffic to,
+// and the other rules with one.
+func ValidateRedirect(obj *v1alpha1.SecurityPolicy) error {
+	for i := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[i]
+		if isRedirectRule(rule) && rule.RedirectTo == "" {
+			return fmt.Errorf("spec.rules[%d].redirectTo is required by the Redirect action", i)
+		}
+		if !isRedirectRule(rule) && rule.RedirectTo != "" {
+			return fmt.Errorf("spec.rules[%d].redirectTo is only allowed with the Redirect action", i)
+		}
+	}
+	return nil
+}
+
+func (service *SecurityPolicyService) buildRedirectionPolicyPath(id string) string {
+	return fmt.Sprintf("/infra/domains/%s/redirection-policies/%s", getDomain(service), id)
+}
+
+// buildRedirectionPolicies moves the rules built from the Redirect rules out of the NSX SecurityPolicy,
+// and returns the RedirectionPolicies of the partner services they redirect the traffic to.
+func (service *SecurityPolicyService) buildRedirectionPolicies(obj *v1alpha1.SecurityPolicy, sp *model.SecurityPolicy, createdFor string) ([]model.RedirectionPolicy, error) {
+	// the NSX rules expanded from a rule are prefixed by the rule ID
+	redirectTo := make(map[string]string)
+	for ruleIdx := range obj.Spec.Rules {
+		rule := &obj.Spec.Rules[ruleIdx]
+		if isRedirectRule(rule) {
+			redirectTo[service.buildRuleID(obj, rule, ruleIdx, createdFor)+"_"] = rule.RedirectTo
+		}
+	}
+	if len(redirectTo) == 0 {
+		return nil, nil
+	}
+	if err := ValidateRedirect(obj); err != nil {
+		return nil, err
+	}
+	if isVpcEnabled(service) {
+		return nil, errors.New("the Redirect action is not supported in VPC mode")
+	}
+
+	var policies []model.RedirectionPolicy
+	policyIdx := make(map[string]int)
+	rules := make([]model.Rule, 0, len(sp.Rules))
+	for _, rule := range sp.Rules {
+		target := ""
+		for prefix, partnerService := range redirectTo {
+			if strings.HasPrefix(*rule.Id, prefix) {
+				target = partnerService
+				break
+			}
+		}
+		if target == "" {
+			rules = append(rules, rule)
+			continue
+		}
+		idx, ok := policyIdx[target]
+		if !ok {
+			idx = len(policies)
+			policyIdx[target] = idx
+			policies = append(policies, service.buildRedirectionPolicy(sp, target))
+		}
+		policies[idx].Rules = append(policies[idx].Rules, service.buildRedirectionRule(&rule, *policies[idx].Id))
+	}
+	sp.Rules = rules
+	log.V(1).Info("built redirection policies", "nsxSecurityPolicy.Id", sp.Id, "redirectionPolicies", policies)
+	return policies, nil
+}
+
+func (service *SecurityPolicyService) buildRedirectionPolicy(sp *model.SecurityPolicy, redirectTo string) model.RedirectionPolicy {
+	return model.RedirectionPolicy{
+		Id:             String(fmt.Sprintf("%s_%s", *sp.Id, util.Sha1(redirectTo)[:8])),
+		DisplayName:    String(util.GenerateTruncName(common.MaxNameLength, *sp.DisplayName, "", "redirect", "", "")),
+		SequenceNumber: sp.SequenceNumber,
+		Scope:          sp.Scope,
+		RedirectTo:     []string{redirectTo},
+		Tags:           sp.Tags,
+	}
+}
+
+func (service *SecurityPolicyService) buildRedirectionRule(rule *model.Rule, policyID string) model.RedirectionRule {
+	return model.RedirectionRule{
+		Id:                rule.Id,
+		DisplayName:       rule.DisplayName,
+		Direction:         rule.Direction,
+		SequenceNumber:    rule.SequenceNumber,
+		Action:            String(model.RedirectionRule_ACTION_REDIRECT),
+		Scope:             rule.Scope,
+		Services:          rule.Services,
+		ServiceEntries:    rule.ServiceEntries,
+		SourceGroups:      rule.SourceGroups,
+		DestinationGroups: rule.DestinationGroups,
+		Tags:              rule.Tags,
+		// the parent path is read only, it's used to find the policy of the stale rules in store
+		ParentPath: String(service.buildRedirectionPolicyPath(policyID)),
+	}
+}
+
+// redirectionPolicyReference returns a RedirectionPolicy which only refers to the existing one as the parent
+// of the changed and stale rules in the hierarchical patch.
+func redirectionPolicyReference(id string, rules []model.RedirectionRule) model.RedirectionPolicy {
+	return model.RedirectionPolicy{
+		Id:           String(id),
+		ResourceType: &common.ResourceTypeChildResourceReference,
+		Rules:        rules,
+	}
+}
+
+func isRedirectionPolicyReference(policy *model.RedirectionPolicy) bool {
+	return policy.ResourceType != nil && *policy.ResourceType == common.ResourceTypeChildResourceReference
+}
+
+// diffRedirectionPolicies compares the expected RedirectionPolicies of a CR with the ones in store. The stale
+// policies and rules are patched before the NSX SecurityPolicy, so the stale groups are no longer referred to
+// when they're deleted, the changed ones are patched after the new groups they refer to are created.
+func (service *SecurityPolicyService) diffRedirectionPolicies(indexScope string, uid types.UID, policies []model.RedirectionPolicy) (stale []model.RedirectionPolicy, changed []model.RedirectionPolicy) {
+	if service.redirectionPolicyStore == nil || service.redirectionRuleStore == nil {
+		return nil, nil
+	}
+	existingPolicies := service.redirectionPolicyStore.GetByIndex(indexScope, string(uid))
+	existingRules := service.redirectionRuleStore.GetByIndex(indexScope, string(uid))
+	var expectedRules []model.RedirectionRule
+	for _, policy := range policies {
+		expectedRules = append(expectedRules, policy.Rules...)
+	}
+
+	changedPolicies, stalePolicies := common.CompareResources(RedirectionPoliciesPtrToComparable(existingPolicies), RedirectionPoliciesToComparable(policies))
+	changedRules, staleRules := common.CompareResources(RedirectionRulesPtrToComparable(existingRules), RedirectionRulesToComparable(expectedRules))
+
+	staleRulesByPolicy := make(map[string][]model.RedirectionRule)
+	for _, rule := range ComparableToRedirectionRules(staleRules) {
+		if rule.ParentPath == nil {
+			continue
+		}
+		rule.MarkedForDelete = &MarkedForDelete
+		policyID := path.Base(*rule.ParentPath)
+		staleRulesByPolicy[policyID] = append(staleRulesByPolicy[policyID], rule)
+	}
+	for _, policy := range ComparableToRedirectionPolicies(stalePolicies) {
+		policy.MarkedForDelete = &MarkedForDelete // the rules are deleted together with the policy
+		policy.Rules = staleRulesByPolicy[*policy.Id]
+		delete(staleRulesByPolicy, *policy.Id)
+		stale = append(stale, policy)
+	}
+	for policyID, rules := range staleRulesByPolicy {
+		stale = append(stale, redirectionPolicyReference(policyID, rules))
+	}
+
+	changedRulesByPolicy := make(map[string][]model.RedirectionRule)
+	for _, rule := range ComparableToRedirectionRules(changedRules) {
+		policyID := path.Base(*rule.ParentPath)
+		changedRulesByPolicy[policyID] = append(changedRulesByPolicy[policyID], rule)
+	}
+	changedPolicyIDs := sets.New[string]()
+	for _, policy := range ComparableToRedirectionPolicies(changedPolicies) {
+		changedPolicyIDs.Insert(*policy.Id)
+	}
+	for _, policy := range policies {
+		rules := changedRulesByPolicy[*policy.Id]
+		if changedPolicyIDs.Has(*policy.Id) {
+			policy.Rules = rules
+			changed = append(changed, policy)
+		} else if len(rules) > 0 {
+			changed = append(changed, redirectionPolicyReference(*policy.Id, rules))
+		}
+	}
+	return stale, changed
+}
+
+// patchRedirectionPolicies patches the RedirectionPolicies with their rules by the hierarchical API,
+// then updates the stores.
+func (service *SecurityPolicyService) patchRedirectionPolicies(policies []model.RedirectionPolicy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	infra, err := service.WrapHierarchyRedirectionPolicies(policies)
+	if err != nil {
+		log.Error(err, "failed to wrap RedirectionPolicies")
+		return err
+	}
+	if err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam); err != nil {
+		log.Error(err, "failed to patch RedirectionPolicies")
+		return err
+	}
+	if err = service.redirectionPolicyStore.Apply(&policies); err != nil {
+		log.Error(err, "failed to apply store", "redirectionPolicies", policies)
+		return err
+	}
+	if err = service.redirectionRuleStore.Apply(&policies); err != nil {
+		log.Error(err, "failed to apply store", "redirectionPolicies", policies)
+		return err
+	}
+	log.Info("successfully patched nsx RedirectionPolicies", "redirectionPolicies", policies)
+	return nil
+}
+
+// deleteRedirectionPolicies deletes the RedirectionPolicies of a CR.
+func (service *SecurityPolicyService) deleteRedirectionPolicies(indexScope string, uid types.UID) error {
+	stale, _ := service.diffRedirectionPolicies(indexScope, uid, nil)
+	return service.patchRedirectionPolicies(stale)
+}
@@ -0,0 +1,118 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestSecurityPolicyService_RedirectionPolicies(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	indexScope := common.TagValueScopeSecurityPolicyUID
+	service.redirectionPolicyStore = &RedirectionPolicyStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{indexScope: indexBySecurityPolicyUID}),
+		BindingType: model.RedirectionPolicyBindingType(),
+	}}
+	service.redirectionRuleStore = &RedirectionRuleStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{indexScope: indexBySecurityPolicyUID}),
+		BindingType: model.RedirectionRuleBindingType(),
+	}}
+
+	sp := spWithPodSelector.DeepCopy()
+	redirectAction := v1alpha1.RuleActionRedirect
+	sp.Spec.Rules[1].Action = &redirectAction
+	sp.Spec.Rules[1].RedirectTo = "/infra/service-chains/chain1"
+	nsxSecurityPolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, nsxSecurityPolicy.Rules, 2)
+
+	// the Redirect rule is moved to the RedirectionPolicy of the partner service
+	policies, err := service.buildRedirectionPolicies(sp, nsxSecurityPolicy, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, nsxSecurityPolicy.Rules, 1)
+	assert.Equal(t, "ALLOW", *nsxSecurityPolicy.Rules[0].Action)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, []string{"/infra/service-chains/chain1"}, policies[0].RedirectTo)
+	assert.Equal(t, nsxSecurityPolicy.Scope, policies[0].Scope)
+	assert.Len(t, policies[0].Rules, 1)
+	assert.Equal(t, model.RedirectionRule_ACTION_REDIRECT, *policies[0].Rules[0].Action)
+	assert.Equal(t, "rule-with-ns-selector-ingress-redirect", *policies[0].Rules[0].DisplayName)
+
+	// the new policy is patched with its rules, then nothing is changed
+	stale, changed := service.diffRedirectionPolicies(indexScope, sp.UID, policies)
+	assert.Empty(t, stale)
+	assert.Len(t, changed, 1)
+	assert.Len(t, changed[0].Rules, 1)
+	_, err = service.WrapHierarchyRedirectionPolicies(changed)
+	assert.NoError(t, err)
+	assert.NoError(t, service.redirectionPolicyStore.Apply(&changed))
+	assert.NoError(t, service.redirectionRuleStore.Apply(&changed))
+	stale, changed = service.diffRedirectionPolicies(indexScope, sp.UID, policies)
+	assert.Empty(t, stale)
+	assert.Empty(t, changed)
+
+	// the rule changed in place is deleted then created in the existing policy
+	sp.Spec.Rules[1].Name = "redirect"
+	nsxSecurityPolicy, _, _, err = service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	policies, err = service.buildRedirectionPolicies(sp, nsxSecurityPolicy, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	stale, changed = service.diffRedirectionPolicies(indexScope, sp.UID, policies)
+	assert.Len(t, stale, 1)
+	assert.True(t, isRedirectionPolicyReference(&stale[0]))
+	assert.True(t, *stale[0].Rules[0].MarkedForDelete)
+	assert.Len(t, changed, 1)
+	assert.True(t, isRedirectionPolicyReference(&changed[0]))
+	_, err = service.WrapHierarchyRedirectionPolicies(append(stale, changed...))
+	assert.NoError(t, err)
+
+	// all the policies are deleted with the CR
+	stale, changed = service.diffRedirectionPolicies(indexScope, sp.UID, nil)
+	assert.Len(t, stale, 1)
+	assert.False(t, isRedirectionPolicyReference(&stale[0]))
+	assert.True(t, *stale[0].MarkedForDelete)
+	assert.Empty(t, changed)
+	assert.NoError(t, service.redirectionPolicyStore.Apply(&stale))
+	assert.NoError(t, service.redirectionRuleStore.Apply(&stale))
+	assert.Empty(t, service.redirectionPolicyStore.ListKeys())
+	assert.Empty(t, service.redirectionRuleStore.ListKeys())
+
+	// the Redirect rules are not supported in VPC mode
+	service.NSXConfig.EnableVPCNetwork = true
+	defer func() { service.NSXConfig.EnableVPCNetwork = false }()
+	_, err = service.buildRedirectionPolicies(sp, nsxSecurityPolicy, common.ResourceTypeSecurityPolicy)
+	assert.EqualError(t, err, "the Redirect action is not supported in VPC mode")
+}
+
+func TestValidateRedirect(t *testing.T) {
+	sp := spWithPodSelector.DeepCopy()
+	assert.NoError(t, ValidateRedirect(sp))
+
+	sp.Spec.Rules[0].RedirectTo = "/infra/service-chains/chain1"
+	assert.EqualError(t, ValidateRedirect(sp), "spec.rules[0].redirectTo is only allowed with the Redirect action")
+
+	redirectAction := v1alpha1.RuleActionRedirect
+	sp.Spec.Rules[0].Action = &redirectAction
+	assert.NoError(t, ValidateRedirect(sp))
+
+	sp.Spec.Rules[0].RedirectTo = ""
+	assert.EqualError(t, ValidateRedirect(sp), "spec.rules[0].redirectTo is required by the Redirect action")
+}
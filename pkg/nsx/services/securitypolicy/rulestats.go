@@ -0,0 +1,94 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The hit statistics of the NSX rules are kept by NSX per enforcement point, they're read for the whole NSX
+// SecurityPolicy at once and summed up by the rules of the CR. The NSX rule IDs are built from the CR UID and
+// the rule index, so the NSX rules expanded from a rule, e.g. for the named ports, are summed up by the index.
+
+// CollectRuleStats reads the hit statistics of the NSX rules of the SecurityPolicy from NSX, they're reported for
+// each rule of the spec. It returns nil if the SecurityPolicy is not realized.
+func (service *SecurityPolicyService) CollectRuleStats(obj *v1alpha1.SecurityPolicy) ([]v1alpha1.RuleStats, error) {
+	securityPolicyStore, _, _, _, _ := service.getStores()
+	policies := securityPolicyStore.GetByIndex(common.TagValueScopeSecurityPolicyUID, string(obj.UID))
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	result, err := service.listPolicyStatistics(namespaceOfTags(policies[0].Tags), *policies[0].Id)
+	if err = common.TransError(err); err != nil {
+		return nil, err
+	}
+	return summarizeRuleStats(obj, result), nil
+}
+
+func (service *SecurityPolicyService) listPolicyStatistics(namespace, policyID string) (model.SecurityPolicyStatisticsListResult, error) {
+	if isVpcEnabled(service) {
+		vpcInfo, err := service.getVpcInfo(namespace)
+		if err != nil {
+			return model.SecurityPolicyStatisticsListResult{}, err
+		}
+		return service.NSXClient.VPCSecurityStatisticsClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, policyID, nil, nil)
+	}
+	return service.NSXClient.SecurityStatisticsClient.List(getDomain(service), policyID, nil, nil)
+}
+
+// summarizeRuleStats sums the statistics of the NSX rules of all the enforcement points by the rules of the spec,
+// the rules injected in the NSX SecurityPolicy, e.g. the DNS rule, are left out.
+func summarizeRuleStats(obj *v1alpha1.SecurityPolicy, result model.SecurityPolicyStatisticsListResult) []v1alpha1.RuleStats {
+	stats := make([]v1alpha1.RuleStats, len(obj.Spec.Rules))
+	for i := range obj.Spec.Rules {
+		stats[i] = v1alpha1.RuleStats{Name: obj.Spec.Rules[i].Name, Index: i}
+	}
+	for _, point := range result.Results {
+		if point.Statistics == nil {
+			continue
+		}
+		for _, ruleStats := range point.Statistics.Results {
+			if ruleStats.Rule == nil {
+				continue
+			}
+			idx, ok := ruleIndexOfID(string(obj.UID), (*ruleStats.Rule)[strings.LastIndex(*ruleStats.Rule, "/")+1:])
+			if !ok || idx >= len(stats) {
+				continue
+			}
+			stats[idx].HitCount += int64Value(ruleStats.HitCount)
+			stats[idx].PacketCount += int64Value(ruleStats.PacketCount)
+			stats[idx].ByteCount += int64Value(ruleStats.ByteCount)
+			stats[idx].SessionCount += int64Value(ruleStats.SessionCount)
+		}
+	}
+	return stats
+}
+
+// ruleIndexOfID returns the index of the rule of the spec the NSX rule ID is built for, i.e. the part following the
+// CR UID in the ID.
+func ruleIndexOfID(uid, id string) (int, bool) {
+	_, suffix, found := strings.Cut(id, uid+"_")
+	if !found {
+		return 0, false
+	}
+	index, _, _ := strings.Cut(suffix, "_")
+	idx, err := strconv.Atoi(index)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func int64Value(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,56 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestSummarizeRuleStats(t *testing.T) {
+	obj := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{UID: "sp-uid"},
+		Spec: v1alpha1.SecurityPolicySpec{Rules: []v1alpha1.SecurityPolicyRule{
+			{Name: "allow-web"},
+			{Name: "drop-all"},
+		}},
+	}
+	ruleStatistics := func(id string, hits, bytes int64) model.RuleStatistics {
+		path := "/infra/domains/default/security-policies/sp/rules/" + id
+		return model.RuleStatistics{Rule: &path, HitCount: &hits, ByteCount: &bytes}
+	}
+	result := model.SecurityPolicyStatisticsListResult{Results: []model.SecurityPolicyStatisticsForEnforcementPoint{
+		{Statistics: &model.SecurityPolicyStatistics{Results: []model.RuleStatistics{
+			// the rule with named ports expanded into two NSX rules
+			ruleStatistics("sp-uid_0_1a2b3c_80", 3, 300),
+			ruleStatistics("sp-uid_0_1a2b3c_8080", 2, 200),
+			// the injected DNS rule
+			ruleStatistics("sp-uid_2_4d5e6f", 10, 1000),
+			ruleStatistics("manual", 1, 100),
+		}}},
+		{Statistics: &model.SecurityPolicyStatistics{Results: []model.RuleStatistics{
+			ruleStatistics("sp-uid_0_1a2b3c_80", 1, 100),
+		}}},
+		{},
+	}}
+	assert.Equal(t, []v1alpha1.RuleStats{
+		{Name: "allow-web", Index: 0, HitCount: 6, ByteCount: 600},
+		{Name: "drop-all", Index: 1},
+	}, summarizeRuleStats(obj, result))
+}
+
+func TestRuleIndexOfID(t *testing.T) {
+	idx, ok := ruleIndexOfID("sp-uid", "np_sp-uid_12_1a2b3c")
+	assert.True(t, ok)
+	assert.Equal(t, 12, idx)
+	_, ok = ruleIndexOfID("sp-uid", "other-uid_1_1a2b3c")
+	assert.False(t, ok)
+	_, ok = ruleIndexOfID("sp-uid", "sp-uid_x_1a2b3c")
+	assert.False(t, ok)
+}
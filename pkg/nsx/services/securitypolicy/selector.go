@@ -0,0 +1,158 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// normalizeNegatedExpressions drops the 'NotIn' expression of a key which also has a 'DoesNotExist'
+// expression. In K8s, 'DoesNotExist' implies 'NotIn', while in NSX the 'NOTIN' condition only matches
+// the members tagged with the key, so ANDing it with the 'NOTEQUALS' scope condition never matches.
+// The expressions are sorted by key and operator to generate stable NSX group criteria.
+func normalizeNegatedExpressions(matchExpressions []v1.LabelSelectorRequirement) []v1.LabelSelectorRequirement {
+	notExist := sets.New[string]()
+	for _, expr := range matchExpressions {
+		if expr.Operator == v1.LabelSelectorOpDoesNotExist {
+			notExist.Insert(expr.Key)
+		}
+	}
+	normalized := make([]v1.LabelSelectorRequirement, 0, len(matchExpressions))
+	for _, expr := range matchExpressions {
+		if expr.Operator == v1.LabelSelectorOpNotIn && notExist.Has(expr.Key) {
+			continue
+		}
+		normalized = append(normalized, expr)
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		if normalized[i].Key != normalized[j].Key {
+			return normalized[i].Key < normalized[j].Key
+		}
+		return normalized[i].Operator < normalized[j].Operator
+	})
+	return normalized
+}
+
+// validateSelectorConflicts checks the merged matchExpressions and matchLabels of a selector, it rejects
+// the operators with invalid values and the combinations which never match any member, since NSX
+// would create a group with no member silently for them.
+func validateSelectorConflicts(matchExpressions []v1.LabelSelectorRequirement, matchLabels map[string]string) error {
+	exprs := map[v1.LabelSelectorOperator]map[string][]string{}
+	for _, expr := range matchExpressions {
+		switch expr.Operator {
+		case v1.LabelSelectorOpIn, v1.LabelSelectorOpNotIn:
+			if len(expr.Values) == 0 {
+				return fmt.Errorf("operator '%s' of key %s requires at least one value", expr.Operator, expr.Key)
+			}
+		case v1.LabelSelectorOpExists, v1.LabelSelectorOpDoesNotExist:
+			if len(expr.Values) != 0 {
+				return fmt.Errorf("operator '%s' of key %s does not accept values", expr.Operator, expr.Key)
+			}
+		default:
+			return fmt.Errorf("invalid operator %s in matchExpressions", expr.Operator)
+		}
+		if _, ok := exprs[expr.Operator]; !ok {
+			exprs[expr.Operator] = map[string][]string{}
+		}
+		exprs[expr.Operator][expr.Key] = append(exprs[expr.Operator][expr.Key], expr.Values...)
+	}
+
+	for key := range exprs[v1.LabelSelectorOpDoesNotExist] {
+		if _, ok := exprs[v1.LabelSelectorOpExists][key]; ok {
+			return fmt.Errorf("key %s is required by both operator 'Exists' and 'DoesNotExist'", key)
+		}
+		if _, ok := exprs[v1.LabelSelectorOpIn][key]; ok {
+			return fmt.Errorf("key %s is required by both operator 'In' and 'DoesNotExist'", key)
+		}
+		if _, ok := matchLabels[key]; ok {
+			return fmt.Errorf("key %s is required by both matchLabels and operator 'DoesNotExist'", key)
+		}
+	}
+	for key, values := range exprs[v1.LabelSelectorOpNotIn] {
+		excluded := sets.New[string](values...)
+		if value, ok := matchLabels[key]; ok && excluded.Has(value) {
+			return fmt.Errorf("%s:%s is required by matchLabels but excluded by operator 'NotIn'", key, value)
+		}
+		if inValues, ok := exprs[v1.LabelSelectorOpIn][key]; ok && excluded.HasAll(inValues...) {
+			return fmt.Errorf("all values of key %s for operator 'In' are excluded by operator 'NotIn'", key)
+		}
+	}
+	return nil
+}
+
+// ValidateSelectors validates the label selectors of the SecurityPolicy, it rejects the selectors
+// which can't be expressed by NSX group criteria, with the same rules applied when building the groups.
+func ValidateSelectors(obj *v1alpha1.SecurityPolicy) error {
+	// the selector validations don't depend on NSX
+	service := &SecurityPolicyService{}
+	validate := func(selector *v1.LabelSelector, isNamespaceSelector bool) (int, error) {
+		if selector == nil {
+			return 0, nil
+		}
+		if isNamespaceSelector {
+			if err := service.validateNsSelectorOpNotIn(selector.MatchExpressions); err != nil {
+				return 0, err
+			}
+		}
+		merged := service.mergeSelectorMatchExpression(selector.MatchExpressions)
+		if err := validateSelectorConflicts(*merged, selector.MatchLabels); err != nil {
+			return 0, err
+		}
+		return service.validateSelectorOpIn(*merged, selector.MatchLabels)
+	}
+	validateTargets := func(targets []v1alpha1.SecurityPolicyTarget, path string) error {
+		for i, target := range targets {
+			if _, err := validate(target.VMSelector, false); err != nil {
+				return fmt.Errorf("%s[%d].vmSelector: %w", path, i, err)
+			}
+			if _, err := validate(target.PodSelector, false); err != nil {
+				return fmt.Errorf("%s[%d].podSelector: %w", path, i, err)
+			}
+		}
+		return nil
+	}
+	validatePeers := func(peers []v1alpha1.SecurityPolicyPeer, path string) error {
+		for i, peer := range peers {
+			vmInCount, err := validate(peer.VMSelector, false)
+			if err != nil {
+				return fmt.Errorf("%s[%d].vmSelector: %w", path, i, err)
+			}
+			podInCount, err := validate(peer.PodSelector, false)
+			if err != nil {
+				return fmt.Errorf("%s[%d].podSelector: %w", path, i, err)
+			}
+			nsInCount, err := validate(peer.NamespaceSelector, true)
+			if err != nil {
+				return fmt.Errorf("%s[%d].namespaceSelector: %w", path, i, err)
+			}
+			if nsInCount > 0 && vmInCount+podInCount > 0 {
+				return fmt.Errorf("%s[%d]: operator 'In' is set in both Pod/VM selector and NamespaceSelector", path, i)
+			}
+		}
+		return nil
+	}
+
+	if err := validateTargets(obj.Spec.AppliedTo, "spec.appliedTo"); err != nil {
+		return err
+	}
+	for i, rule := range obj.Spec.Rules {
+		path := fmt.Sprintf("spec.rules[%d]", i)
+		if err := validateTargets(rule.AppliedTo, path+".appliedTo"); err != nil {
+			return err
+		}
+		if err := validatePeers(rule.Sources, path+".sources"); err != nil {
+			return err
+		}
+		if err := validatePeers(rule.Destinations, path+".destinations"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
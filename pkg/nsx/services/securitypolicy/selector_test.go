@@ -0,0 +1,135 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestNormalizeNegatedExpressions(t *testing.T) {
+	matchExpressions := []v1.LabelSelectorRequirement{
+		{Key: "k2", Operator: v1.LabelSelectorOpExists},
+		{Key: "k1", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1"}},
+		{Key: "k1", Operator: v1.LabelSelectorOpDoesNotExist},
+		{Key: "k0", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1"}},
+	}
+	normalized := normalizeNegatedExpressions(matchExpressions)
+	assert.Equal(t, []v1.LabelSelectorRequirement{
+		{Key: "k0", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1"}},
+		{Key: "k1", Operator: v1.LabelSelectorOpDoesNotExist},
+		{Key: "k2", Operator: v1.LabelSelectorOpExists},
+	}, normalized)
+}
+
+func TestValidateSelectorConflicts(t *testing.T) {
+	tests := []struct {
+		name             string
+		matchExpressions []v1.LabelSelectorRequirement
+		matchLabels      map[string]string
+		errMsg           string
+	}{
+		{
+			name: "valid",
+			matchExpressions: []v1.LabelSelectorRequirement{
+				{Key: "k1", Operator: v1.LabelSelectorOpIn, Values: []string{"a1", "a2"}},
+				{Key: "k1", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a2"}},
+				{Key: "k2", Operator: v1.LabelSelectorOpDoesNotExist},
+			},
+			matchLabels: map[string]string{"k3": "a3"},
+		},
+		{
+			name:             "NotIn without values",
+			matchExpressions: []v1.LabelSelectorRequirement{{Key: "k1", Operator: v1.LabelSelectorOpNotIn}},
+			errMsg:           "operator 'NotIn' of key k1 requires at least one value",
+		},
+		{
+			name:             "Exists with values",
+			matchExpressions: []v1.LabelSelectorRequirement{{Key: "k1", Operator: v1.LabelSelectorOpExists, Values: []string{"a1"}}},
+			errMsg:           "operator 'Exists' of key k1 does not accept values",
+		},
+		{
+			name:             "invalid operator",
+			matchExpressions: []v1.LabelSelectorRequirement{{Key: "k1", Operator: "Equals"}},
+			errMsg:           "invalid operator Equals in matchExpressions",
+		},
+		{
+			name: "Exists and DoesNotExist",
+			matchExpressions: []v1.LabelSelectorRequirement{
+				{Key: "k1", Operator: v1.LabelSelectorOpExists},
+				{Key: "k1", Operator: v1.LabelSelectorOpDoesNotExist},
+			},
+			errMsg: "key k1 is required by both operator 'Exists' and 'DoesNotExist'",
+		},
+		{
+			name:             "matchLabels and DoesNotExist",
+			matchExpressions: []v1.LabelSelectorRequirement{{Key: "k1", Operator: v1.LabelSelectorOpDoesNotExist}},
+			matchLabels:      map[string]string{"k1": "a1"},
+			errMsg:           "key k1 is required by both matchLabels and operator 'DoesNotExist'",
+		},
+		{
+			name:             "matchLabels and NotIn",
+			matchExpressions: []v1.LabelSelectorRequirement{{Key: "k1", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1"}}},
+			matchLabels:      map[string]string{"k1": "a1"},
+			errMsg:           "k1:a1 is required by matchLabels but excluded by operator 'NotIn'",
+		},
+		{
+			name: "In and NotIn",
+			matchExpressions: []v1.LabelSelectorRequirement{
+				{Key: "k1", Operator: v1.LabelSelectorOpIn, Values: []string{"a1"}},
+				{Key: "k1", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1", "a2"}},
+			},
+			errMsg: "all values of key k1 for operator 'In' are excluded by operator 'NotIn'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSelectorConflicts(tt.matchExpressions, tt.matchLabels)
+			if tt.errMsg == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidateSelectors(t *testing.T) {
+	obj := &v1alpha1.SecurityPolicy{
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{
+				PodSelector: &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+					{Key: "k1", Operator: v1.LabelSelectorOpDoesNotExist},
+				}},
+			}},
+			Rules: []v1alpha1.SecurityPolicyRule{{
+				Sources: []v1alpha1.SecurityPolicyPeer{{
+					NamespaceSelector: &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+						{Key: "k1", Operator: v1.LabelSelectorOpExists},
+					}},
+				}},
+			}},
+		},
+	}
+	assert.Nil(t, ValidateSelectors(obj))
+
+	obj.Spec.Rules[0].Sources[0].NamespaceSelector.MatchExpressions = []v1.LabelSelectorRequirement{
+		{Key: "k1", Operator: v1.LabelSelectorOpNotIn, Values: []string{"a1"}},
+	}
+	assert.EqualError(t, ValidateSelectors(obj),
+		"spec.rules[0].sources[0].namespaceSelector: operator 'NotIn' for NamespaceSelector is not supported in NSX-T since its member type is Segment")
+
+	obj.Spec.Rules[0].Sources[0].NamespaceSelector = &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+		{Key: "k1", Operator: v1.LabelSelectorOpIn, Values: []string{"a1"}},
+	}}
+	obj.Spec.Rules[0].Sources[0].PodSelector = &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+		{Key: "k2", Operator: v1.LabelSelectorOpIn, Values: []string{"a2"}},
+	}}
+	assert.EqualError(t, ValidateSelectors(obj),
+		"spec.rules[0].sources[0]: operator 'In' is set in both Pod/VM selector and NamespaceSelector")
+}
@@ -0,0 +1,143 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/catalog"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// The services of a rule refer to the existing NSX services, e.g. the system service HTTPS or the custom ones
+// created by the NSX admin, by path or display name. They're set in the services of the NSX rules, which match
+// the traffic of any of the services and the service entries built from the ports.
+
+const (
+	// infraServicePathPrefix is the prefix of the paths of the NSX infra services.
+	infraServicePathPrefix = "/infra/services/"
+	// servicePathTTL is how long the path a service display name is resolved to is cached.
+	servicePathTTL = 10 * time.Minute
+)
+
+// servicePathPattern matches the paths of the NSX infra and project services.
+var servicePathPattern = regexp.MustCompile(`^(/orgs/[^/]+/projects/[^/]+)?/infra/services/[^/]+$`)
+
+// searchEscaper escapes the reserved characters of the NSX search query syntax.
+var searchEscaper = strings.NewReplacer(
+	`\`, `\\`, "/", `\/`, ":", `\:`, " ", `\ `, `"`, `\"`, "(", `\(`, ")", `\)`, "[", `\[`, "]", `\]`,
+	"{", `\{`, "}", `\}`, "+", `\+`, "-", `\-`, "!", `\!`, "^", `\^`, "~", `\~`, "*", `\*`, "?", `\?`,
+	"&", `\&`, "|", `\|`,
+)
+
+type servicePath struct {
+	path       string
+	resolvedAt time.Time
+}
+
+func isServicePath(ref string) bool {
+	return strings.HasPrefix(ref, "/")
+}
+
+// ValidateServices rejects the empty services and the service paths which are not NSX service paths.
+func ValidateServices(obj *v1alpha1.SecurityPolicy) error {
+	for i := range obj.Spec.Rules {
+		for j, ref := range obj.Spec.Rules[i].Services {
+			if strings.TrimSpace(ref) == "" {
+				return fmt.Errorf("spec.rules[%d].services[%d] is empty", i, j)
+			}
+			if isServicePath(ref) && !servicePathPattern.MatchString(ref) {
+				return fmt.Errorf("spec.rules[%d].services[%d] has invalid NSX service path %q", i, j, ref)
+			}
+		}
+	}
+	return nil
+}
+
+// buildRuleServices returns the sorted unique paths of the services of the rule, the display names are resolved
+// to the paths of the NSX services.
+func (service *SecurityPolicyService) buildRuleServices(rule *v1alpha1.SecurityPolicyRule) ([]string, error) {
+	paths := sets.New[string]()
+	for _, ref := range rule.Services {
+		if isServicePath(ref) {
+			if !servicePathPattern.MatchString(ref) {
+				return nil, fmt.Errorf("invalid NSX service path %q", ref)
+			}
+			paths.Insert(ref)
+			continue
+		}
+		path, err := service.resolveServicePath(ref)
+		if err != nil {
+			return nil, err
+		}
+		paths.Insert(path)
+	}
+	return sets.List(paths), nil
+}
+
+// resolveServicePath resolves the display name to the path of the predefined NSX service in the catalog, or
+// else searches the custom NSX service with the display name, the infra service is preferred if a project has a
+// service with the same name. The searched paths are cached for servicePathTTL, the services are rarely renamed.
+func (service *SecurityPolicyService) resolveServicePath(displayName string) (string, error) {
+	if catalogService := catalog.LoadedCatalogService(); catalogService != nil {
+		if entry := catalogService.GetService(displayName); entry != nil {
+			return entry.Path, nil
+		}
+	}
+	if cached, ok := service.servicePaths.Load(displayName); ok && time.Since(cached.(servicePath).resolvedAt) < servicePathTTL {
+		return cached.(servicePath).path, nil
+	}
+	queryParam := fmt.Sprintf("%s:%s AND display_name:%s AND marked_for_delete:false",
+		common.ResourceType, catalog.ResourceTypeService, searchEscaper.Replace(displayName))
+	response, err := service.NSXClient.QueryClient.List(queryParam, nil, nil, Int64(common.PageSize), nil, nil)
+	if err = common.TransError(err); err != nil {
+		return "", err
+	}
+	// the search matches the display names by tokens, only the exact ones are kept
+	var paths []string
+	for _, result := range response.Results {
+		if result == nil || structString(result, "display_name") != displayName || structString(result, "path") == "" {
+			continue
+		}
+		paths = append(paths, structString(result, "path"))
+	}
+	path := ""
+	for _, p := range paths {
+		if strings.HasPrefix(p, infraServicePathPrefix) {
+			path = p
+			break
+		}
+	}
+	if path == "" {
+		switch len(paths) {
+		case 0:
+			return "", fmt.Errorf("NSX service %q not found", displayName)
+		case 1:
+			path = paths[0]
+		default:
+			return "", fmt.Errorf("NSX service %q is ambiguous, it matches %s, refer to it by path", displayName, strings.Join(paths, ", "))
+		}
+	}
+	service.servicePaths.Store(displayName, servicePath{path: path, resolvedAt: time.Now()})
+	return path, nil
+}
+
+// structString returns the string field of the search result, empty if it's not set.
+func structString(result *data.StructValue, field string) string {
+	value, err := result.Field(field)
+	if err != nil {
+		return ""
+	}
+	if str, ok := value.(*data.StringValue); ok {
+		return str.Value()
+	}
+	return ""
+}
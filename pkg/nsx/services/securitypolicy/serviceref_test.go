@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type serviceQueryClient struct {
+	results []*data.StructValue
+	queries []string
+}
+
+func (c *serviceQueryClient) List(queryParam string, _ *string, _ *string, _ *int64, _ *bool, _ *string) (model.SearchResponse, error) {
+	c.queries = append(c.queries, queryParam)
+	count := int64(len(c.results))
+	return model.SearchResponse{Results: c.results, ResultCount: &count}, nil
+}
+
+func serviceResult(name, path string) *data.StructValue {
+	return data.NewStructValue("", map[string]data.DataValue{
+		"display_name": data.NewStringValue(name),
+		"path":         data.NewStringValue(path),
+	})
+}
+
+func TestSecurityPolicyService_Services(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeService()
+	queryClient := &serviceQueryClient{results: []*data.StructValue{
+		serviceResult("web app", "/orgs/default/projects/p1/infra/services/web-app"),
+		serviceResult("web app", "/infra/services/web-app"),
+		serviceResult("web app legacy", "/infra/services/web-app-legacy"),
+	}}
+	service.NSXClient.QueryClient = queryClient
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules = sp.Spec.Rules[:1]
+	sp.Spec.Rules[0].Services = []string{"web app", "/infra/services/HTTPS", "web app"}
+	servicePolicy, _, _, err := service.buildSecurityPolicy(sp, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	for _, rule := range servicePolicy.Rules {
+		assert.Equal(t, []string{"/infra/services/HTTPS", "/infra/services/web-app"}, rule.Services)
+	}
+	assert.Equal(t, []string{`resource_type:Service AND display_name:web\ app AND marked_for_delete:false`}, queryClient.queries)
+
+	// the resolved path is cached
+	_, err = service.buildRuleServices(&sp.Spec.Rules[0])
+	assert.NoError(t, err)
+	assert.Len(t, queryClient.queries, 1)
+
+	// the project services with the same name are ambiguous
+	queryClient.results = []*data.StructValue{
+		serviceResult("db", "/orgs/default/projects/p1/infra/services/db"),
+		serviceResult("db", "/orgs/default/projects/p2/infra/services/db"),
+	}
+	_, err = service.buildRuleServices(&v1alpha1.SecurityPolicyRule{Services: []string{"db"}})
+	assert.EqualError(t, err, `NSX service "db" is ambiguous, it matches /orgs/default/projects/p1/infra/services/db, /orgs/default/projects/p2/infra/services/db, refer to it by path`)
+
+	_, err = service.buildRuleServices(&v1alpha1.SecurityPolicyRule{Services: []string{"ftp"}})
+	assert.EqualError(t, err, `NSX service "ftp" not found`)
+}
+
+func TestValidateServices(t *testing.T) {
+	sp := spWithPodSelector.DeepCopy()
+	sp.Spec.Rules[0].Services = []string{"HTTPS", "/infra/services/SSH", "/orgs/default/projects/p1/infra/services/web"}
+	assert.NoError(t, ValidateServices(sp))
+
+	sp.Spec.Rules[0].Services = []string{" "}
+	assert.EqualError(t, ValidateServices(sp), "spec.rules[0].services[0] is empty")
+
+	sp.Spec.Rules[0].Services = []string{"HTTPS", "/infra/context-profiles/HTTP"}
+	assert.EqualError(t, ValidateServices(sp), `spec.rules[0].services[1] has invalid NSX service path "/infra/context-profiles/HTTP"`)
+}
@@ -0,0 +1,256 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data/serializers/cleanjson"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// With shared_peer_groups, the rule peer groups with the same criteria are shared by all the SecurityPolicies
+// in the domain of the cluster, instead of one group per rule peer of each policy, so that the selectors
+// repeated across the policies of a large cluster don't hit the NSX group limit. A shared group is identified by the hash of its
+// criteria and isn't owned by any CR. It's reference counted by the rules referring to it in the rule store,
+// which is rebuilt from NSX on restart, and the rules being patched. It's created before the first rule
+// referring to it, and deleted after the last one. The groups are only shared without VPC, the VPC groups are
+// in the VPCs or shared by the project shares. The shared groups left once shared_peer_groups is disabled
+// are still deleted after the last rule referring to them.
+
+// sharedGroupIndexKey is the index of the rules by the IDs of the shared groups they refer to.
+const sharedGroupIndexKey = "sharedGroup"
+
+// sharedGroupRefs counts the references to the shared groups from the rules being patched, which are not in
+// the rule store yet. The lock guards the counts and the groups being deleted, it isn't held while NSX is
+// called.
+type sharedGroupRefs struct {
+	lock    sync.Mutex
+	pending map[string]int
+	// deleting is the shared groups being deleted, the rules referring to them wait for the deletion to end
+	// before the groups are created again
+	deleting sets.Set[string]
+	deleted  *sync.Cond
+}
+
+// init initializes the references, the lock must be held.
+func (refs *sharedGroupRefs) init() {
+	if refs.pending == nil {
+		refs.pending = map[string]int{}
+		refs.deleting = sets.New[string]()
+		refs.deleted = sync.NewCond(&refs.lock)
+	}
+}
+
+// groupCriteriaHash returns the hash of the criteria of a group, or empty if it has no criteria.
+func groupCriteriaHash(group *model.Group) string {
+	if len(group.Expression) == 0 {
+		return ""
+	}
+	criteria := model.Group{Expression: group.Expression}
+	dataValue, errs := criteria.GetDataValue__()
+	if len(errs) != 0 {
+		return ""
+	}
+	encoded, err := cleanjson.NewDataValueToJsonEncoder().Encode(dataValue)
+	if err != nil {
+		return ""
+	}
+	return util.Sha1(encoded)
+}
+
+func buildSharedGroupID(hash string) string {
+	return util.GenerateID(hash, common.SecurityPolicyPrefix, common.SharedGroupSuffix, "")
+}
+
+// isSharedGroupID returns whether the group ID is the one of a shared group.
+func isSharedGroupID(id string) bool {
+	return strings.HasPrefix(id, common.SecurityPolicyPrefix+"_") && strings.HasSuffix(id, "_"+common.SharedGroupSuffix)
+}
+
+// sharedGroupIDs returns the IDs of the shared groups the paths refer to.
+func sharedGroupIDs(paths ...[]string) sets.Set[string] {
+	ids := sets.New[string]()
+	for _, ps := range paths {
+		for _, path := range ps {
+			id := path[strings.LastIndex(path, "/")+1:]
+			if isSharedGroupID(id) {
+				ids.Insert(id)
+			}
+		}
+	}
+	return ids
+}
+
+func indexBySharedGroup(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.Rule:
+		return sets.List(sharedGroupIDs(o.SourceGroups, o.DestinationGroups)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// buildSharedGroup builds the shared group of the criteria of a rule peer group, without the tags of the CR
+// and the rule the peer group is built for.
+func (service *SecurityPolicyService) buildSharedGroup(group *model.Group, hash string) model.Group {
+	id := buildSharedGroupID(hash)
+	return model.Group{
+		Id:          String(id),
+		DisplayName: String(id),
+		Expression:  group.Expression,
+		Tags: []model.Tag{
+			{Scope: String(common.TagScopeCluster), Tag: String(getCluster(service))},
+			{Scope: String(common.TagScopeVersion), Tag: String(strings.Join(common.TagValueVersion, "."))},
+			{Scope: String(common.TagScopeGroupType), Tag: String(common.TagValueGroupShared)},
+			{Scope: String(common.TagScopeSelectorHash), Tag: String(hash)},
+		},
+	}
+}
+
+// sharedPeerGroupsEnabled returns whether the rule peer groups are shared, it's never the case in VPC mode.
+func (service *SecurityPolicyService) sharedPeerGroupsEnabled() bool {
+	return service.sharedGroupStore != nil && service.NSXConfig.NsxConfig != nil && service.NSXConfig.SharedPeerGroups
+}
+
+// shareGroups replaces the rule peer groups of a policy with the shared groups of the same criteria, and
+// refers the rules to the shared groups. It returns the groups of the policy left and the shared groups the
+// rules refer to. Nothing is shared unless shared_peer_groups is enabled.
+func (service *SecurityPolicyService) shareGroups(rules []model.Rule, groups []model.Group) ([]model.Group, []model.Group) {
+	if !service.sharedPeerGroupsEnabled() {
+		return groups, nil
+	}
+	replaced := map[string]string{}
+	kept := make([]model.Group, 0, len(groups))
+	var shared []model.Group
+	sharedIDs := sets.New[string]()
+	for i := range groups {
+		hash := ""
+		if peerSelectorHash(&groups[i]) != "" {
+			hash = groupCriteriaHash(&groups[i])
+		}
+		if hash == "" {
+			kept = append(kept, groups[i])
+			continue
+		}
+		sharedGroup := service.buildSharedGroup(&groups[i], hash)
+		replaced[*groups[i].Id] = *sharedGroup.Id
+		if !sharedIDs.Has(*sharedGroup.Id) {
+			sharedIDs.Insert(*sharedGroup.Id)
+			shared = append(shared, sharedGroup)
+		}
+	}
+	if len(replaced) == 0 {
+		return groups, nil
+	}
+	log.V(1).Info("replaced rule peer groups with shared groups", "groups", replaced)
+	for i := range rules {
+		reusePeerGroupPaths(rules[i].SourceGroups, replaced)
+		reusePeerGroupPaths(rules[i].DestinationGroups, replaced)
+	}
+	return kept, shared
+}
+
+// acquireSharedGroups references the shared groups the rules being patched refer to, so that they're not
+// deleted by another policy meanwhile, and creates the ones which don't exist yet. The references are
+// released by releaseSharedGroups after the rules are patched, whether the patch succeeds or not.
+func (service *SecurityPolicyService) acquireSharedGroups(groups []model.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	refs := &service.sharedGroupRefs
+	var created []model.Group
+	refs.lock.Lock()
+	refs.init()
+	for _, group := range groups {
+		for refs.deleting.Has(*group.Id) {
+			refs.deleted.Wait()
+		}
+		refs.pending[*group.Id]++
+		if service.sharedGroupStore.GetByKey(*group.Id) == nil {
+			created = append(created, group)
+		}
+	}
+	refs.lock.Unlock()
+	if len(created) == 0 {
+		return nil
+	}
+	if err := service.patchSharedGroups(created); err != nil {
+		return err
+	}
+	log.Info("created shared groups", "count", len(created))
+	return nil
+}
+
+// releaseSharedGroups releases the references acquired by acquireSharedGroups, then deletes the shared
+// groups which are no longer referred to by any rule. All of them are checked, so that the ones left by a
+// failed deletion or a restart are deleted as well. The groups failed to delete are retried next time.
+func (service *SecurityPolicyService) releaseSharedGroups(acquired []model.Group) {
+	if service.sharedGroupStore == nil {
+		return
+	}
+	refs := &service.sharedGroupRefs
+	refs.lock.Lock()
+	refs.init()
+	for _, group := range acquired {
+		if refs.pending[*group.Id]--; refs.pending[*group.Id] <= 0 {
+			delete(refs.pending, *group.Id)
+		}
+	}
+	var unreferenced []model.Group
+	for _, obj := range service.sharedGroupStore.List() {
+		group := *obj.(*model.Group)
+		if refs.pending[*group.Id] > 0 || refs.deleting.Has(*group.Id) ||
+			len(service.ruleStore.GetByIndex(sharedGroupIndexKey, *group.Id)) > 0 {
+			continue
+		}
+		group.MarkedForDelete = &MarkedForDelete
+		unreferenced = append(unreferenced, group)
+		refs.deleting.Insert(*group.Id)
+	}
+	refs.lock.Unlock()
+	if len(unreferenced) == 0 {
+		return
+	}
+	defer func() {
+		refs.lock.Lock()
+		defer refs.lock.Unlock()
+		for _, group := range unreferenced {
+			refs.deleting.Delete(*group.Id)
+		}
+		refs.deleted.Broadcast()
+	}()
+	if err := service.patchSharedGroups(unreferenced); err != nil {
+		return
+	}
+	log.Info("deleted unreferenced shared groups", "count", len(unreferenced))
+}
+
+// patchSharedGroups patches the shared groups in the domain of the cluster, or stages them in the DFW draft
+// if it's enabled, then updates the store.
+func (service *SecurityPolicyService) patchSharedGroups(groups []model.Group) error {
+	children, err := service.wrapGroups(groups)
+	if err != nil {
+		return err
+	}
+	infra, err := service.wrapDomainInfra(children)
+	if err != nil {
+		return err
+	}
+	if service.DraftEnabled() {
+		err = service.stageInDraft(infra)
+	} else {
+		err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam)
+	}
+	if err != nil {
+		log.Error(err, "failed to patch shared groups", "groups", len(groups))
+		return err
+	}
+	return service.sharedGroupStore.Apply(&groups)
+}
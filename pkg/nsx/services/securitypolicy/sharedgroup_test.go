@@ -0,0 +1,152 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func fakeSharedGroupService() *SecurityPolicyService {
+	service := fakeService()
+	service.ruleStore = &RuleStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{sharedGroupIndexKey: indexBySharedGroup}),
+		BindingType: model.RuleBindingType(),
+	}}
+	service.sharedGroupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{}),
+		BindingType: model.GroupBindingType(),
+	}}
+	service.NSXConfig.NsxConfig = &config.NsxConfig{SharedPeerGroups: true}
+	return service
+}
+
+func TestSecurityPolicyService_shareGroups(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	service := fakeSharedGroupService()
+	spA := spWithPodSelector.DeepCopy()
+	spB := spWithPodSelector.DeepCopy()
+	spB.Name, spB.UID = "spB", "uidB"
+
+	var sharedIDs [][]string
+	for _, sp := range []string{"spA", "spB"} {
+		obj := spA
+		if sp == "spB" {
+			obj = spB
+		}
+		nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(obj, common.ResourceTypeSecurityPolicy)
+		assert.NoError(t, err)
+		own, shared := service.shareGroups(nsxSecurityPolicy.Rules, *nsxGroups)
+		assert.NotEmpty(t, shared)
+		// only the policy group is left to the policy
+		for _, group := range own {
+			assert.Empty(t, peerSelectorHash(&group))
+		}
+		var ids []string
+		for _, group := range shared {
+			assert.True(t, isSharedGroupID(*group.Id))
+			assert.Empty(t, filterTag(group.Tags, common.TagScopeSecurityPolicyCRUID))
+			ids = append(ids, *group.Id)
+		}
+		for _, rule := range nsxSecurityPolicy.Rules {
+			for _, path := range append(rule.SourceGroups, rule.DestinationGroups...) {
+				if path != "ANY" {
+					assert.True(t, isSharedGroupID(path[strings.LastIndex(path, "/")+1:]), path)
+				}
+			}
+		}
+		sharedIDs = append(sharedIDs, ids)
+	}
+	// the policies with the same peers refer to the same shared groups
+	assert.Equal(t, sharedIDs[0], sharedIDs[1])
+
+	// nothing is shared unless shared_peer_groups is enabled
+	nsxSecurityPolicy, nsxGroups, _, err := service.buildSecurityPolicy(spA, common.ResourceTypeSecurityPolicy)
+	assert.NoError(t, err)
+	service.NSXConfig.SharedPeerGroups = false
+	own, shared := service.shareGroups(nsxSecurityPolicy.Rules, *nsxGroups)
+	assert.Equal(t, *nsxGroups, own)
+	assert.Nil(t, shared)
+
+	// nothing is shared in VPC mode
+	service.NSXConfig.SharedPeerGroups = true
+	service.sharedGroupStore = nil
+	groups := []model.Group{{Id: String("g1")}}
+	own, shared = service.shareGroups(nil, groups)
+	assert.Equal(t, groups, own)
+	assert.Nil(t, shared)
+}
+
+func TestSecurityPolicyService_releaseSharedGroups(t *testing.T) {
+	var s *SecurityPolicyService
+	var deleted []string
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "patchSharedGroups",
+		func(s *SecurityPolicyService, groups []model.Group) error {
+			// the references are not locked while NSX is called
+			assert.True(t, s.sharedGroupRefs.lock.TryLock())
+			s.sharedGroupRefs.lock.Unlock()
+			for _, group := range groups {
+				if group.MarkedForDelete != nil && *group.MarkedForDelete {
+					deleted = append(deleted, *group.Id)
+				}
+			}
+			return s.sharedGroupStore.Apply(&groups)
+		})
+	defer patches.Reset()
+
+	service := fakeSharedGroupService()
+	referred, pending, unreferenced := buildSharedGroupID("a"), buildSharedGroupID("b"), buildSharedGroupID("c")
+	groups := []model.Group{{Id: String(referred)}, {Id: String(pending)}, {Id: String(unreferenced)}}
+	assert.NoError(t, service.sharedGroupStore.Apply(&groups))
+	rule := &model.Rule{
+		Id:           String("rule"),
+		SourceGroups: []string{"/infra/domains/default/groups/" + referred},
+	}
+	assert.NoError(t, service.ruleStore.Add(rule))
+
+	// the groups referred to by the rules in store or being patched are kept
+	acquired := []model.Group{{Id: String(pending)}}
+	assert.NoError(t, service.acquireSharedGroups(acquired))
+	service.releaseSharedGroups(nil)
+	assert.Equal(t, []string{unreferenced}, deleted)
+
+	deleted = nil
+	service.releaseSharedGroups(acquired)
+	assert.Equal(t, []string{pending}, deleted)
+	assert.NotNil(t, service.sharedGroupStore.GetByKey(referred))
+}
+
+func TestSecurityPolicyService_patchSharedGroups(t *testing.T) {
+	service := fakeSharedGroupService()
+	service.NSXConfig.DFWDraft = true
+	var staged *model.Infra
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(service), "stageInDraft",
+		func(s *SecurityPolicyService, infra *model.Infra) error {
+			staged = infra
+			return nil
+		})
+	defer patches.Reset()
+
+	// the shared groups are staged in the DFW draft with the rules referring to them
+	id := buildSharedGroupID("a")
+	assert.NoError(t, service.patchSharedGroups([]model.Group{{Id: String(id)}}))
+	assert.NotNil(t, staged)
+	assert.NotNil(t, service.sharedGroupStore.GetByKey(id))
+}
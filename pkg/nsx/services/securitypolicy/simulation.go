@@ -0,0 +1,320 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+)
+
+const (
+	// MaxSimulationHours is the max count of hours of observed flows a SecurityPolicy is simulated against.
+	MaxSimulationHours = 7 * 24
+	// MaxSimulatedBlockedFlows is the max count of blocked flows listed in the SecurityPolicy status.
+	MaxSimulatedBlockedFlows = 50
+	// flowRecordsAPI is the NSX Intelligence API exporting the observed flow records.
+	flowRecordsAPI = "napp/api/v1/flows/records"
+)
+
+// FlowRecord is a flow observed by NSX.
+type FlowRecord struct {
+	SourceIP        string
+	DestinationIP   string
+	Protocol        v1.Protocol
+	DestinationPort int
+}
+
+// FlowSource lists the flows observed by NSX in a time window.
+type FlowSource interface {
+	ListFlows(start, end time.Time) ([]FlowRecord, error)
+}
+
+// SimulationEndpoint is the Pod which an IP of an observed flow belongs to.
+type SimulationEndpoint struct {
+	Namespace       string
+	Name            string
+	Labels          map[string]string
+	NamespaceLabels map[string]string
+	// NamedPorts maps the names of the container ports to the port numbers.
+	NamedPorts map[string]int
+}
+
+// EndpointResolver returns the endpoint of an IP, or nil if the IP doesn't belong to a Pod.
+type EndpointResolver func(ip string) *SimulationEndpoint
+
+type intelligenceFlowSource struct {
+	cluster *nsx.Cluster
+}
+
+// FlowSource returns the flow records exported by NSX Intelligence.
+func (service *SecurityPolicyService) FlowSource() FlowSource {
+	return &intelligenceFlowSource{cluster: service.NSXClient.Cluster}
+}
+
+func (s *intelligenceFlowSource) ListFlows(start, end time.Time) ([]FlowRecord, error) {
+	var flows []FlowRecord
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("start_time", strconv.FormatInt(start.UnixMilli(), 10))
+		query.Set("end_time", strconv.FormatInt(end.UnixMilli(), 10))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := s.cluster.HttpGet(fmt.Sprintf("%s?%s", flowRecordsAPI, query.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, parseFlowRecords(resp)...)
+		cursor, _ = resp["cursor"].(string)
+		if cursor == "" {
+			return flows, nil
+		}
+	}
+}
+
+// parseFlowRecords parses the flow records in a page of the NSX Intelligence response, the records
+// without source or destination IP are skipped.
+func parseFlowRecords(resp map[string]interface{}) []FlowRecord {
+	results, _ := resp["results"].([]interface{})
+	flows := make([]FlowRecord, 0, len(results))
+	for _, result := range results {
+		record, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		flow := FlowRecord{}
+		flow.SourceIP, _ = record["source_ip"].(string)
+		flow.DestinationIP, _ = record["destination_ip"].(string)
+		if flow.SourceIP == "" || flow.DestinationIP == "" {
+			continue
+		}
+		protocol, _ := record["protocol"].(string)
+		flow.Protocol = v1.Protocol(protocol)
+		// JSON numbers are decoded as float64
+		if port, ok := record["destination_port"].(float64); ok {
+			flow.DestinationPort = int(port)
+		}
+		flows = append(flows, flow)
+	}
+	return flows
+}
+
+// Simulate evaluates the SecurityPolicy against the flows observed by source in the last hours,
+// the failure is reported in the Error of the returned status.
+func (service *SecurityPolicyService) Simulate(obj *v1alpha1.SecurityPolicy, hours int, source FlowSource, resolve EndpointResolver) *v1alpha1.SimulationStatus {
+	end := metav1.Now()
+	start := metav1.NewTime(end.Add(-time.Duration(hours) * time.Hour))
+	status := &v1alpha1.SimulationStatus{
+		ObservedGeneration: obj.Generation,
+		Hours:              hours,
+		StartTime:          start,
+		EndTime:            end,
+	}
+	flows, err := source.ListFlows(start.Time, end.Time)
+	if err != nil {
+		log.Error(err, "failed to list observed flows", "securitypolicy", obj.Name, "namespace", obj.Namespace)
+		status.Error = fmt.Sprintf("failed to list observed flows: %v", err)
+		return status
+	}
+	status.Flows = len(flows)
+	status.BlockedFlowCount, status.BlockedFlows, err = simulateSecurityPolicy(obj, flows, resolve)
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// simulateSecurityPolicy matches each flow with the rules in order, the flow is blocked if the first
+// matched rule drops or rejects it. It returns the count of blocked flows and the blocked flows
+// truncated to MaxSimulatedBlockedFlows.
+func simulateSecurityPolicy(obj *v1alpha1.SecurityPolicy, flows []FlowRecord, resolve EndpointResolver) (int, []v1alpha1.SimulatedFlow, error) {
+	blockedCount := 0
+	var blocked []v1alpha1.SimulatedFlow
+	for _, flow := range flows {
+		src, dst := resolve(flow.SourceIP), resolve(flow.DestinationIP)
+		for ruleIdx := range obj.Spec.Rules {
+			rule := &obj.Spec.Rules[ruleIdx]
+			matched, err := matchSimulatedRule(obj, rule, flow, src, dst)
+			if err != nil {
+				return 0, nil, fmt.Errorf("rule %d: %w", ruleIdx, err)
+			}
+			if !matched {
+				continue
+			}
+			if rule.Action != nil && *rule.Action != v1alpha1.RuleActionAllow {
+				blockedCount++
+				if len(blocked) < MaxSimulatedBlockedFlows {
+					blocked = append(blocked, v1alpha1.SimulatedFlow{
+						Rule:        simulatedRuleName(rule, ruleIdx),
+						Source:      simulatedEndpointName(src, flow.SourceIP),
+						Destination: simulatedEndpointName(dst, flow.DestinationIP),
+						Protocol:    flow.Protocol,
+						Port:        flow.DestinationPort,
+					})
+				}
+			}
+			break
+		}
+	}
+	return blockedCount, blocked, nil
+}
+
+func simulatedRuleName(rule *v1alpha1.SecurityPolicyRule, ruleIdx int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return strconv.Itoa(ruleIdx)
+}
+
+func simulatedEndpointName(endpoint *SimulationEndpoint, ip string) string {
+	if endpoint == nil {
+		return ip
+	}
+	return endpoint.Namespace + "/" + endpoint.Name
+}
+
+// matchSimulatedRule checks if the flow matches the rule. For the ingress rule, the destination is
+// the target selected by appliedTo and the source is the peer, and vice versa for the egress rule.
+func matchSimulatedRule(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, flow FlowRecord, src, dst *SimulationEndpoint) (bool, error) {
+	ruleDirection, err := getRuleDirection(rule)
+	if err != nil {
+		return false, err
+	}
+	target, peer, peerIP, peers := dst, src, flow.SourceIP, rule.Sources
+	if ruleDirection == "OUT" {
+		target, peer, peerIP, peers = src, dst, flow.DestinationIP, rule.Destinations
+	}
+	// The rule's appliedTo takes precedence over the policy's appliedTo, as in the built NSX rule.
+	targets := rule.AppliedTo
+	if len(targets) == 0 {
+		targets = obj.Spec.AppliedTo
+	}
+	if matched, err := matchSimulatedTargets(obj.Namespace, targets, target); err != nil || !matched {
+		return false, err
+	}
+	if len(peers) > 0 {
+		matched := false
+		for i := range peers {
+			if matched, err = matchSimulatedPeer(obj.Namespace, &peers[i], peer, peerIP); err != nil {
+				return false, err
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return matchSimulatedPorts(rule.Ports, flow, dst), nil
+}
+
+// matchSimulatedTargets checks if the endpoint is selected by the pod selectors of the targets in the
+// namespace of the SecurityPolicy, the VMs are not resolved from the flows so the vm selectors never match.
+func matchSimulatedTargets(namespace string, targets []v1alpha1.SecurityPolicyTarget, endpoint *SimulationEndpoint) (bool, error) {
+	if endpoint == nil || endpoint.Namespace != namespace {
+		return false, nil
+	}
+	for _, target := range targets {
+		if target.PodSelector == nil {
+			continue
+		}
+		if matched, err := matchSimulatedLabels(target.PodSelector, endpoint.Labels); err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+func matchSimulatedPeer(namespace string, peer *v1alpha1.SecurityPolicyPeer, endpoint *SimulationEndpoint, ip string) (bool, error) {
+	for _, block := range peer.IPBlocks {
+		_, ipNet, err := net.ParseCIDR(block.CIDR)
+		if err != nil {
+			return false, err
+		}
+		parsedIP := net.ParseIP(ip)
+		if parsedIP == nil || !ipNet.Contains(parsedIP) {
+			continue
+		}
+		excepted, err := exceptsOverlap(block.Except, &net.IPNet{IP: parsedIP, Mask: net.CIDRMask(len(parsedIP)*8, len(parsedIP)*8)})
+		if err != nil {
+			return false, err
+		}
+		if !excepted {
+			return true, nil
+		}
+	}
+	if endpoint == nil || (peer.PodSelector == nil && peer.NamespaceSelector == nil) {
+		return false, nil
+	}
+	if peer.NamespaceSelector != nil {
+		if matched, err := matchSimulatedLabels(peer.NamespaceSelector, endpoint.NamespaceLabels); err != nil || !matched {
+			return false, err
+		}
+	} else if endpoint.Namespace != namespace {
+		return false, nil
+	}
+	if peer.PodSelector != nil {
+		return matchSimulatedLabels(peer.PodSelector, endpoint.Labels)
+	}
+	return true, nil
+}
+
+func matchSimulatedLabels(labelSelector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(set)), nil
+}
+
+// matchSimulatedPorts checks if the flow matches any of the ports, the named port is resolved from
+// the container ports of the destination.
+func matchSimulatedPorts(ports []v1alpha1.SecurityPolicyPort, flow FlowRecord, dst *SimulationEndpoint) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		if protocol != flow.Protocol {
+			continue
+		}
+		portNumber := port.Port.IntValue()
+		if port.Port.Type == intstr.String {
+			if dst == nil {
+				continue
+			}
+			portNumber = dst.NamedPorts[port.Port.StrVal]
+			if portNumber == 0 {
+				continue
+			}
+		}
+		if portNumber == 0 {
+			return true
+		}
+		endPort := port.EndPort
+		if endPort < portNumber {
+			endPort = portNumber
+		}
+		if flow.DestinationPort >= portNumber && flow.DestinationPort <= endPort {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+type fakeFlowSource struct {
+	flows []FlowRecord
+	err   error
+}
+
+func (s *fakeFlowSource) ListFlows(_, _ time.Time) ([]FlowRecord, error) {
+	return s.flows, s.err
+}
+
+func TestParseFlowRecords(t *testing.T) {
+	resp := map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"source_ip": "10.0.0.1", "destination_ip": "10.0.0.2", "protocol": "TCP", "destination_port": float64(80)},
+			map[string]interface{}{"source_ip": "10.0.0.1"},
+			"invalid",
+		},
+	}
+	flows := parseFlowRecords(resp)
+	assert.Equal(t, []FlowRecord{{SourceIP: "10.0.0.1", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 80}}, flows)
+	assert.Empty(t, parseFlowRecords(map[string]interface{}{}))
+}
+
+func TestSimulate(t *testing.T) {
+	in, out := v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionOut
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp", Generation: 2},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}}},
+			Rules: []v1alpha1.SecurityPolicyRule{
+				{
+					Name:      "allow-web",
+					Action:    &allowAction,
+					Direction: &in,
+					Sources:   []v1alpha1.SecurityPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+					Ports:     []v1alpha1.SecurityPolicyPort{{Port: intstr.FromString("db")}},
+				},
+				{
+					Action:    &allowDrop,
+					Direction: &in,
+				},
+				{
+					Name:         "drop-external",
+					Action:       &allowDrop,
+					Direction:    &out,
+					Destinations: []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "192.168.0.0/16"}}}},
+					Ports:        []v1alpha1.SecurityPolicyPort{{Protocol: v1.ProtocolUDP, Port: intstr.FromInt(53), EndPort: 60}},
+				},
+			},
+		},
+	}
+	endpoints := map[string]*SimulationEndpoint{
+		"10.0.0.1": {Namespace: "ns1", Name: "web", Labels: map[string]string{"app": "web"}},
+		"10.0.0.2": {Namespace: "ns1", Name: "db", Labels: map[string]string{"app": "db"}, NamedPorts: map[string]int{"db": 5432}},
+		"10.0.0.3": {Namespace: "ns2", Name: "web", Labels: map[string]string{"app": "web"}},
+	}
+	resolve := func(ip string) *SimulationEndpoint { return endpoints[ip] }
+	flows := []FlowRecord{
+		// allowed by the named port
+		{SourceIP: "10.0.0.1", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 5432},
+		// dropped by the second rule
+		{SourceIP: "10.0.0.1", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 22},
+		{SourceIP: "10.0.0.3", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 5432},
+		// dropped by the egress rule
+		{SourceIP: "10.0.0.2", DestinationIP: "192.168.1.1", Protocol: v1.ProtocolUDP, DestinationPort: 55},
+		// not applied
+		{SourceIP: "10.0.0.2", DestinationIP: "192.168.1.1", Protocol: v1.ProtocolUDP, DestinationPort: 61},
+		{SourceIP: "10.0.0.2", DestinationIP: "10.0.0.1", Protocol: v1.ProtocolTCP, DestinationPort: 80},
+	}
+
+	service := &SecurityPolicyService{}
+	status := service.Simulate(sp, 24, &fakeFlowSource{flows: flows}, resolve)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, 24, status.Hours)
+	assert.Equal(t, 24*time.Hour, status.EndTime.Sub(status.StartTime.Time))
+	assert.Equal(t, 6, status.Flows)
+	assert.Equal(t, 3, status.BlockedFlowCount)
+	assert.Equal(t, []v1alpha1.SimulatedFlow{
+		{Rule: "1", Source: "ns1/web", Destination: "ns1/db", Protocol: v1.ProtocolTCP, Port: 22},
+		{Rule: "1", Source: "ns2/web", Destination: "ns1/db", Protocol: v1.ProtocolTCP, Port: 5432},
+		{Rule: "drop-external", Source: "ns1/db", Destination: "192.168.1.1", Protocol: v1.ProtocolUDP, Port: 55},
+	}, status.BlockedFlows)
+	assert.Empty(t, status.Error)
+
+	status = service.Simulate(sp, 1, &fakeFlowSource{err: errors.New("unavailable")}, resolve)
+	assert.Contains(t, status.Error, "unavailable")
+	assert.Zero(t, status.Flows)
+}
+
+func TestSimulateTruncatesBlockedFlows(t *testing.T) {
+	in := v1alpha1.RuleDirectionIn
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sp"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: &metav1.LabelSelector{}}},
+			Rules:     []v1alpha1.SecurityPolicyRule{{Action: &allowDrop, Direction: &in}},
+		},
+	}
+	resolve := func(ip string) *SimulationEndpoint {
+		return &SimulationEndpoint{Namespace: "ns1", Name: ip}
+	}
+	flows := make([]FlowRecord, MaxSimulatedBlockedFlows+1)
+	for i := range flows {
+		flows[i] = FlowRecord{SourceIP: "10.0.0.1", DestinationIP: "10.0.0.2", Protocol: v1.ProtocolTCP, DestinationPort: 80}
+	}
+	count, blocked, err := simulateSecurityPolicy(sp, flows, resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, MaxSimulatedBlockedFlows+1, count)
+	assert.Equal(t, MaxSimulatedBlockedFlows, len(blocked))
+}
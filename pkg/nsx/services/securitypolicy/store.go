@@ -19,11 +19,55 @@ func keyFunc(obj interface{}) (string, error) {
 		return *v.Id, nil
 	case *model.Share:
 		return *v.Id, nil
+	case *model.RedirectionPolicy:
+		return *v.Id, nil
+	case *model.RedirectionRule:
+		return *v.Id, nil
+	case *model.PolicyContextProfile:
+		return *v.Id, nil
+	case *model.GatewayPolicy:
+		return *v.Id, nil
+	case *model.IdsSecurityPolicy:
+		return *v.Id, nil
+	case *model.IdsRule:
+		return *v.Id, nil
+	case *model.IdsProfile:
+		return *v.Id, nil
 	default:
 		return "", errors.New("keyFunc doesn't support unknown type")
 	}
 }
 
+// tagsOf returns the tags of a resource in the stores, it is used to count the resources by namespace
+func tagsOf(obj interface{}) []model.Tag {
+	switch v := obj.(type) {
+	case *model.Group:
+		return v.Tags
+	case *model.SecurityPolicy:
+		return v.Tags
+	case *model.Rule:
+		return v.Tags
+	case *model.Share:
+		return v.Tags
+	case *model.RedirectionPolicy:
+		return v.Tags
+	case *model.RedirectionRule:
+		return v.Tags
+	case *model.PolicyContextProfile:
+		return v.Tags
+	case *model.GatewayPolicy:
+		return v.Tags
+	case *model.IdsSecurityPolicy:
+		return v.Tags
+	case *model.IdsRule:
+		return v.Tags
+	case *model.IdsProfile:
+		return v.Tags
+	default:
+		return nil
+	}
+}
+
 func filterTag(tags []model.Tag, tagScope string) []string {
 	var res []string
 	for _, tag := range tags {
@@ -46,6 +90,12 @@ func indexBySecurityPolicyUID(obj interface{}) ([]string, error) {
 		return filterTag(o.Tags, common.TagValueScopeSecurityPolicyUID), nil
 	case *model.Share:
 		return filterTag(o.Tags, common.TagValueScopeSecurityPolicyUID), nil
+	case *model.RedirectionPolicy:
+		return filterTag(o.Tags, common.TagValueScopeSecurityPolicyUID), nil
+	case *model.RedirectionRule:
+		return filterTag(o.Tags, common.TagValueScopeSecurityPolicyUID), nil
+	case *model.PolicyContextProfile:
+		return filterTag(o.Tags, common.TagValueScopeSecurityPolicyUID), nil
 	default:
 		return nil, errors.New("indexBySecurityPolicyUID doesn't support unknown type")
 	}
@@ -66,6 +116,79 @@ func indexByNetworkPolicyUID(obj interface{}) ([]string, error) {
 	}
 }
 
+func indexByServiceExposureUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.SecurityPolicy:
+		return filterTag(o.Tags, common.TagScopeServiceExposureUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeServiceExposureUID), nil
+	case *model.Rule:
+		return filterTag(o.Tags, common.TagScopeServiceExposureUID), nil
+	case *model.Share:
+		return filterTag(o.Tags, common.TagScopeServiceExposureUID), nil
+	default:
+		return nil, errors.New("indexByServiceExposureUID doesn't support unknown type")
+	}
+}
+
+func indexByAdminNetworkPolicyUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.SecurityPolicy:
+		return filterTag(o.Tags, common.TagScopeAdminNetworkPolicyUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeAdminNetworkPolicyUID), nil
+	case *model.Rule:
+		return filterTag(o.Tags, common.TagScopeAdminNetworkPolicyUID), nil
+	case *model.Share:
+		return filterTag(o.Tags, common.TagScopeAdminNetworkPolicyUID), nil
+	default:
+		return nil, errors.New("indexByAdminNetworkPolicyUID doesn't support unknown type")
+	}
+}
+
+func indexByDefaultDenyNamespaceUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.SecurityPolicy:
+		return filterTag(o.Tags, common.TagScopeDefaultDenyNamespaceUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeDefaultDenyNamespaceUID), nil
+	case *model.Rule:
+		return filterTag(o.Tags, common.TagScopeDefaultDenyNamespaceUID), nil
+	case *model.Share:
+		return filterTag(o.Tags, common.TagScopeDefaultDenyNamespaceUID), nil
+	default:
+		return nil, errors.New("indexByDefaultDenyNamespaceUID doesn't support unknown type")
+	}
+}
+
+func indexByGatewayPolicyUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.GatewayPolicy:
+		return filterTag(o.Tags, common.TagScopeGatewayPolicyCRUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeGatewayPolicyCRUID), nil
+	case *model.Rule:
+		return filterTag(o.Tags, common.TagScopeGatewayPolicyCRUID), nil
+	default:
+		return nil, errors.New("indexByGatewayPolicyUID doesn't support unknown type")
+	}
+}
+
+func indexByIDSPolicyUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.IdsSecurityPolicy:
+		return filterTag(o.Tags, common.TagScopeIDSPolicyCRUID), nil
+	case *model.IdsRule:
+		return filterTag(o.Tags, common.TagScopeIDSPolicyCRUID), nil
+	case *model.IdsProfile:
+		return filterTag(o.Tags, common.TagScopeIDSPolicyCRUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeIDSPolicyCRUID), nil
+	default:
+		return nil, errors.New("indexByIDSPolicyUID doesn't support unknown type")
+	}
+}
+
 func indexGroupFunc(obj interface{}) ([]string, error) {
 	res := make([]string, 0, 5)
 	switch o := obj.(type) {
@@ -106,6 +229,41 @@ type ShareStore struct {
 	common.ResourceStore
 }
 
+// RedirectionPolicyStore is a store for redirection policies built from the Redirect rules of security policy
+type RedirectionPolicyStore struct {
+	common.ResourceStore
+}
+
+// RedirectionRuleStore is a store for rules of redirection policies
+type RedirectionRuleStore struct {
+	common.ResourceStore
+}
+
+// ContextProfileStore is a store for context profiles built from the FQDN destinations of security policy rules
+type ContextProfileStore struct {
+	common.ResourceStore
+}
+
+// GatewayPolicyStore is a store for gateway policies built from GatewayPolicy CRs
+type GatewayPolicyStore struct {
+	common.ResourceStore
+}
+
+// IDSPolicyStore is a store for IDS/IPS policies built from IDSPolicy CRs
+type IDSPolicyStore struct {
+	common.ResourceStore
+}
+
+// IDSRuleStore is a store for rules of IDS/IPS policies
+type IDSRuleStore struct {
+	common.ResourceStore
+}
+
+// IDSProfileStore is a store for IDS profiles built from IDSPolicy CRs
+type IDSProfileStore struct {
+	common.ResourceStore
+}
+
 func (securityPolicyStore *SecurityPolicyStore) Apply(i interface{}) error {
 	if i == nil {
 		return nil
@@ -146,8 +304,14 @@ func (securityPolicyStore *SecurityPolicyStore) GetByIndex(key string, value str
 }
 
 func (ruleStore *RuleStore) Apply(i interface{}) error {
-	sp := i.(*model.SecurityPolicy)
-	for _, rule := range sp.Rules {
+	var rules []model.Rule
+	switch p := i.(type) {
+	case *model.SecurityPolicy:
+		rules = p.Rules
+	case *model.GatewayPolicy:
+		rules = p.Rules
+	}
+	for _, rule := range rules {
 		tempRule := rule
 		if rule.MarkedForDelete != nil && *rule.MarkedForDelete {
 			err := ruleStore.Delete(&tempRule)
@@ -243,3 +407,241 @@ func (shareStore *ShareStore) GetByIndex(key string, value string) []*model.Shar
 	}
 	return shares
 }
+
+func (redirectionPolicyStore *RedirectionPolicyStore) Apply(i interface{}) error {
+	policies := i.(*[]model.RedirectionPolicy)
+	for _, policy := range *policies {
+		tempPolicy := policy
+		if isRedirectionPolicyReference(&tempPolicy) {
+			continue
+		}
+		if policy.MarkedForDelete != nil && *policy.MarkedForDelete {
+			err := redirectionPolicyStore.Delete(&tempPolicy)
+			log.V(1).Info("delete redirection policy from store", "redirectionPolicy", tempPolicy)
+			if err != nil {
+				return err
+			}
+		} else {
+			err := redirectionPolicyStore.Add(&tempPolicy)
+			log.V(1).Info("add redirection policy to store", "redirectionPolicy", tempPolicy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (redirectionPolicyStore *RedirectionPolicyStore) GetByIndex(key string, value string) []*model.RedirectionPolicy {
+	policies := make([]*model.RedirectionPolicy, 0)
+	objs := redirectionPolicyStore.ResourceStore.GetByIndex(key, value)
+	for _, policy := range objs {
+		policies = append(policies, policy.(*model.RedirectionPolicy))
+	}
+	return policies
+}
+
+func (redirectionRuleStore *RedirectionRuleStore) Apply(i interface{}) error {
+	policies := i.(*[]model.RedirectionPolicy)
+	for _, policy := range *policies {
+		deletePolicy := policy.MarkedForDelete != nil && *policy.MarkedForDelete
+		for _, rule := range policy.Rules {
+			tempRule := rule
+			// the rules are deleted together with their redirection policy
+			if deletePolicy || (rule.MarkedForDelete != nil && *rule.MarkedForDelete) {
+				err := redirectionRuleStore.Delete(&tempRule)
+				log.V(1).Info("delete redirection rule from store", "redirectionRule", tempRule)
+				if err != nil {
+					return err
+				}
+			} else {
+				err := redirectionRuleStore.Add(&tempRule)
+				log.V(1).Info("add redirection rule to store", "redirectionRule", tempRule)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (redirectionRuleStore *RedirectionRuleStore) GetByIndex(key string, value string) []*model.RedirectionRule {
+	rules := make([]*model.RedirectionRule, 0)
+	objs := redirectionRuleStore.ResourceStore.GetByIndex(key, value)
+	for _, rule := range objs {
+		rules = append(rules, rule.(*model.RedirectionRule))
+	}
+	return rules
+}
+
+func (contextProfileStore *ContextProfileStore) Apply(i interface{}) error {
+	profiles := i.(*[]model.PolicyContextProfile)
+	for _, profile := range *profiles {
+		tempProfile := profile
+		if profile.MarkedForDelete != nil && *profile.MarkedForDelete {
+			err := contextProfileStore.Delete(&tempProfile)
+			log.V(1).Info("delete context profile from store", "contextProfile", tempProfile)
+			if err != nil {
+				return err
+			}
+		} else {
+			err := contextProfileStore.Add(&tempProfile)
+			log.V(1).Info("add context profile to store", "contextProfile", tempProfile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (contextProfileStore *ContextProfileStore) GetByIndex(key string, value string) []*model.PolicyContextProfile {
+	profiles := make([]*model.PolicyContextProfile, 0)
+	objs := contextProfileStore.ResourceStore.GetByIndex(key, value)
+	for _, profile := range objs {
+		profiles = append(profiles, profile.(*model.PolicyContextProfile))
+	}
+	return profiles
+}
+
+func (gatewayPolicyStore *GatewayPolicyStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	gp := i.(*model.GatewayPolicy)
+	if gp.MarkedForDelete != nil && *gp.MarkedForDelete {
+		err := gatewayPolicyStore.Delete(gp)
+		log.V(1).Info("delete gateway policy from store", "gatewayPolicy", gp)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := gatewayPolicyStore.Add(gp)
+		log.V(1).Info("add gateway policy to store", "gatewayPolicy", gp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gatewayPolicyStore *GatewayPolicyStore) GetByKey(key string) *model.GatewayPolicy {
+	var gatewayPolicy *model.GatewayPolicy
+	obj := gatewayPolicyStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		gatewayPolicy = obj.(*model.GatewayPolicy)
+	}
+	return gatewayPolicy
+}
+
+func (gatewayPolicyStore *GatewayPolicyStore) GetByIndex(key string, value string) []*model.GatewayPolicy {
+	policies := make([]*model.GatewayPolicy, 0)
+	objs := gatewayPolicyStore.ResourceStore.GetByIndex(key, value)
+	for _, policy := range objs {
+		policies = append(policies, policy.(*model.GatewayPolicy))
+	}
+	return policies
+}
+
+func (idsPolicyStore *IDSPolicyStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	policy := i.(*model.IdsSecurityPolicy)
+	if policy.MarkedForDelete != nil && *policy.MarkedForDelete {
+		err := idsPolicyStore.Delete(policy)
+		log.V(1).Info("delete IDS policy from store", "idsPolicy", policy)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := idsPolicyStore.Add(policy)
+		log.V(1).Info("add IDS policy to store", "idsPolicy", policy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idsPolicyStore *IDSPolicyStore) GetByKey(key string) *model.IdsSecurityPolicy {
+	var policy *model.IdsSecurityPolicy
+	obj := idsPolicyStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		policy = obj.(*model.IdsSecurityPolicy)
+	}
+	return policy
+}
+
+func (idsPolicyStore *IDSPolicyStore) GetByIndex(key string, value string) []*model.IdsSecurityPolicy {
+	policies := make([]*model.IdsSecurityPolicy, 0)
+	objs := idsPolicyStore.ResourceStore.GetByIndex(key, value)
+	for _, policy := range objs {
+		policies = append(policies, policy.(*model.IdsSecurityPolicy))
+	}
+	return policies
+}
+
+func (idsRuleStore *IDSRuleStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	policy := i.(*model.IdsSecurityPolicy)
+	for _, rule := range policy.Rules {
+		tempRule := rule
+		if rule.MarkedForDelete != nil && *rule.MarkedForDelete {
+			err := idsRuleStore.Delete(&tempRule)
+			log.V(1).Info("delete IDS rule from store", "idsRule", tempRule)
+			if err != nil {
+				return err
+			}
+		} else {
+			err := idsRuleStore.Add(&tempRule)
+			log.V(1).Info("add IDS rule to store", "idsRule", tempRule)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (idsRuleStore *IDSRuleStore) GetByIndex(key string, value string) []*model.IdsRule {
+	rules := make([]*model.IdsRule, 0)
+	objs := idsRuleStore.ResourceStore.GetByIndex(key, value)
+	for _, rule := range objs {
+		rules = append(rules, rule.(*model.IdsRule))
+	}
+	return rules
+}
+
+func (idsProfileStore *IDSProfileStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	profile := i.(*model.IdsProfile)
+	if profile.MarkedForDelete != nil && *profile.MarkedForDelete {
+		err := idsProfileStore.Delete(profile)
+		log.V(1).Info("delete IDS profile from store", "idsProfile", profile)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := idsProfileStore.Add(profile)
+		log.V(1).Info("add IDS profile to store", "idsProfile", profile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idsProfileStore *IDSProfileStore) GetByIndex(key string, value string) []*model.IdsProfile {
+	profiles := make([]*model.IdsProfile, 0)
+	objs := idsProfileStore.ResourceStore.GetByIndex(key, value)
+	for _, profile := range objs {
+		profiles = append(profiles, profile.(*model.IdsProfile))
+	}
+	return profiles
+}
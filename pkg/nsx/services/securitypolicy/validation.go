@@ -0,0 +1,77 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// maxPortNumber is the max port number of a service entry.
+const maxPortNumber = 65535
+
+// ValidateRules rejects the rules which NSX would refuse or which would be ambiguous once realized: the
+// duplicate rule names, since the NSX rules are named and reported after them, the invalid ports and the
+// excepts of the IP blocks out of their CIDRs.
+func ValidateRules(obj *v1alpha1.SecurityPolicy) error {
+	names := map[string]int{}
+	for ruleIdx, rule := range obj.Spec.Rules {
+		path := fmt.Sprintf("spec.rules[%d]", ruleIdx)
+		if rule.Name != "" {
+			if idx, ok := names[rule.Name]; ok {
+				return fmt.Errorf("%s.name: duplicate rule name %s of spec.rules[%d]", path, rule.Name, idx)
+			}
+			names[rule.Name] = ruleIdx
+		}
+		for portIdx, port := range rule.Ports {
+			if err := validatePort(port); err != nil {
+				return fmt.Errorf("%s.ports[%d]: %w", path, portIdx, err)
+			}
+		}
+		for _, peers := range []struct {
+			path  string
+			peers []v1alpha1.SecurityPolicyPeer
+		}{{path + ".sources", rule.Sources}, {path + ".destinations", rule.Destinations}} {
+			for peerIdx, peer := range peers.peers {
+				for blockIdx, block := range peer.IPBlocks {
+					if err := util.ValidateCIDRExcept(block.CIDR, block.Except); err != nil {
+						return fmt.Errorf("%s[%d].ipBlocks[%d]: %w", peers.path, peerIdx, blockIdx, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validatePort checks the port number and the port range, a range is only allowed with a port number and
+// ends at the port or above.
+func validatePort(port v1alpha1.SecurityPolicyPort) error {
+	if port.Port.Type == intstr.String {
+		if port.EndPort != 0 {
+			return fmt.Errorf("endPort %d is not allowed with named port %s", port.EndPort, port.Port.StrVal)
+		}
+		return nil
+	}
+	if port.Port.IntVal < 0 || port.Port.IntVal > maxPortNumber {
+		return fmt.Errorf("port %d is out of range [1, %d]", port.Port.IntVal, maxPortNumber)
+	}
+	if port.EndPort == 0 {
+		return nil
+	}
+	if port.EndPort < 0 || port.EndPort > maxPortNumber {
+		return fmt.Errorf("endPort %d is out of range [1, %d]", port.EndPort, maxPortNumber)
+	}
+	if port.Port.IntVal == 0 {
+		return fmt.Errorf("endPort %d requires a port", port.EndPort)
+	}
+	if port.EndPort < int(port.Port.IntVal) {
+		return fmt.Errorf("endPort %d is less than port %d", port.EndPort, port.Port.IntVal)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []v1alpha1.SecurityPolicyRule
+		wantErr string
+	}{
+		{
+			name: "valid",
+			rules: []v1alpha1.SecurityPolicyRule{
+				{Name: "r1", Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(80)}, {Port: intstr.FromInt(1000), EndPort: 2000}, {Port: intstr.FromInt(443), EndPort: 443}}},
+				{Name: "r2", Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromString("http")}, {}}},
+				{},
+				{},
+			},
+		},
+		{
+			name:    "duplicate-name",
+			rules:   []v1alpha1.SecurityPolicyRule{{Name: "r1"}, {Name: "r2"}, {Name: "r1"}},
+			wantErr: "spec.rules[2].name: duplicate rule name r1 of spec.rules[0]",
+		},
+		{
+			name:    "port-out-of-range",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(70000)}}}},
+			wantErr: "spec.rules[0].ports[0]: port 70000 is out of range [1, 65535]",
+		},
+		{
+			name:    "inverted-range",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(2000), EndPort: 1000}}}},
+			wantErr: "spec.rules[0].ports[0]: endPort 1000 is less than port 2000",
+		},
+		{
+			name:    "range-out-of-range",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(2000), EndPort: 70000}}}},
+			wantErr: "spec.rules[0].ports[0]: endPort 70000 is out of range [1, 65535]",
+		},
+		{
+			name:    "negative-range",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromInt(2000), EndPort: -1}}}},
+			wantErr: "spec.rules[0].ports[0]: endPort -1 is out of range [1, 65535]",
+		},
+		{
+			name:    "range-without-port",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{EndPort: 1000}}}},
+			wantErr: "spec.rules[0].ports[0]: endPort 1000 requires a port",
+		},
+		{
+			name:    "range-with-named-port",
+			rules:   []v1alpha1.SecurityPolicyRule{{Ports: []v1alpha1.SecurityPolicyPort{{Port: intstr.FromString("http"), EndPort: 1000}}}},
+			wantErr: "spec.rules[0].ports[0]: endPort 1000 is not allowed with named port http",
+		},
+		{
+			name: "except-out-of-cidr",
+			rules: []v1alpha1.SecurityPolicyRule{{Destinations: []v1alpha1.SecurityPolicyPeer{{}, {IPBlocks: []v1alpha1.IPBlock{
+				{CIDR: "10.0.0.0/24", Except: []string{"10.0.0.1/32"}},
+				{CIDR: "10.0.1.0/24", Except: []string{"10.0.0.0/28"}},
+			}}}}},
+			wantErr: "spec.rules[0].destinations[1].ipBlocks[1]: except 10.0.0.0/28 is not strictly within CIDR 10.0.1.0/24",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(&v1alpha1.SecurityPolicy{Spec: v1alpha1.SecurityPolicySpec{Rules: tt.rules}})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
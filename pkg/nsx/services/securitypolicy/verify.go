@@ -0,0 +1,105 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// IntentNotRealizedError is returned when a hierarchical call succeeds, but NSX doesn't store some of the
+// children in it, e.g. they're rejected asynchronously.
+type IntentNotRealizedError struct {
+	// Missing are the paths of the created or updated objects which are not found in NSX.
+	Missing []string
+	// Unexpected are the paths of the deleted objects which are still found in NSX.
+	Unexpected []string
+}
+
+func (e *IntentNotRealizedError) Error() string {
+	return fmt.Sprintf("NSX accepted the hierarchical call but didn't realize the intent, missing: [%s], not deleted: [%s]",
+		strings.Join(e.Missing, ", "), strings.Join(e.Unexpected, ", "))
+}
+
+// verifyRealizedIntent reads back the rules of the SecurityPolicy and the groups after a successful hierarchical
+// call, so that the stores never record the objects NSX silently rejected. The rules are passed separately
+// since wrapping the SecurityPolicy moves them into its children. Nothing is verified if nothing is created
+// or updated, e.g. the SecurityPolicy is deleted, and the deleted groups aren't verified since NSX may still
+// be deleting them.
+func verifyRealizedIntent(sp *model.SecurityPolicy, rules []model.Rule, groups []model.Group, listRules func(cursor *string) (model.RuleListResult, error), getGroup func(id string) error) error {
+	if !hasRealizedIntent(sp, rules, groups) {
+		return nil
+	}
+	intentErr := &IntentNotRealizedError{}
+	realizedRules := sets.New[string]()
+	var cursor *string
+	for {
+		result, err := listRules(cursor)
+		if nsxutil.IsNotFound(err) {
+			intentErr.Missing = append(intentErr.Missing, "SecurityPolicy/"+*sp.Id)
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, rule := range result.Results {
+			if rule.Id != nil && (rule.MarkedForDelete == nil || !*rule.MarkedForDelete) {
+				realizedRules.Insert(*rule.Id)
+			}
+		}
+		if result.Cursor == nil || *result.Cursor == "" || len(result.Results) == 0 {
+			break
+		}
+		cursor = result.Cursor
+	}
+	for _, rule := range rules {
+		deleted := rule.MarkedForDelete != nil && *rule.MarkedForDelete
+		if !deleted && !realizedRules.Has(*rule.Id) {
+			intentErr.Missing = append(intentErr.Missing, "Rule/"+*rule.Id)
+		} else if deleted && realizedRules.Has(*rule.Id) {
+			intentErr.Unexpected = append(intentErr.Unexpected, "Rule/"+*rule.Id)
+		}
+	}
+	for _, group := range groups {
+		if group.MarkedForDelete != nil && *group.MarkedForDelete {
+			continue
+		}
+		err := getGroup(*group.Id)
+		if nsxutil.IsNotFound(err) {
+			intentErr.Missing = append(intentErr.Missing, "Group/"+*group.Id)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(intentErr.Missing) == 0 && len(intentErr.Unexpected) == 0 {
+		return nil
+	}
+	log.Error(intentErr, "failed to verify the realized SecurityPolicy", "nsxSecurityPolicy.Id", sp.Id)
+	return intentErr
+}
+
+func hasRealizedIntent(sp *model.SecurityPolicy, rules []model.Rule, groups []model.Group) bool {
+	if sp.MarkedForDelete != nil && *sp.MarkedForDelete {
+		return false
+	}
+	if !isSecurityPolicyReference(sp) {
+		return true
+	}
+	for _, rule := range rules {
+		if rule.MarkedForDelete == nil || !*rule.MarkedForDelete {
+			return true
+		}
+	}
+	for _, group := range groups {
+		if group.MarkedForDelete == nil || !*group.MarkedForDelete {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func TestVerifyRealizedIntent(t *testing.T) {
+	deleted := true
+	sp := &model.SecurityPolicy{Id: String("sp1")}
+	rules := []model.Rule{{Id: String("rule1")}, {Id: String("rule2")}, {Id: String("rule3"), MarkedForDelete: &deleted}}
+	groups := []model.Group{{Id: String("group1")}, {Id: String("group2")}, {Id: String("group3"), MarkedForDelete: &deleted}}
+	notFound := nsxutil.CreateResourceNotFound("10.0.0.1", "get")
+
+	pages := map[string]model.RuleListResult{
+		"":      {Results: []model.Rule{{Id: String("rule1")}}, Cursor: String("page2")},
+		"page2": {Results: []model.Rule{{Id: String("rule2")}}},
+	}
+	listRules := func(cursor *string) (model.RuleListResult, error) {
+		if cursor == nil {
+			return pages[""], nil
+		}
+		return pages[*cursor], nil
+	}
+	getGroup := func(id string) error {
+		return nil
+	}
+
+	// all the rules over the pages and the groups are realized
+	assert.NoError(t, verifyRealizedIntent(sp, rules, groups, listRules, getGroup))
+
+	// a rule and a group are silently rejected, a deleted rule is still found
+	pages["page2"] = model.RuleListResult{Results: []model.Rule{{Id: String("rule3")}}}
+	err := verifyRealizedIntent(sp, rules, groups, listRules, func(id string) error {
+		if id == "group2" {
+			return notFound
+		}
+		return nil
+	})
+	var intentErr *IntentNotRealizedError
+	assert.True(t, errors.As(err, &intentErr))
+	assert.Equal(t, []string{"Rule/rule2", "Group/group2"}, intentErr.Missing)
+	assert.Equal(t, []string{"Rule/rule3"}, intentErr.Unexpected)
+
+	// the SecurityPolicy is missing
+	err = verifyRealizedIntent(sp, nil, nil, func(_ *string) (model.RuleListResult, error) {
+		return model.RuleListResult{}, notFound
+	}, getGroup)
+	assert.True(t, errors.As(err, &intentErr))
+	assert.Equal(t, []string{"SecurityPolicy/sp1"}, intentErr.Missing)
+
+	// nothing is verified if only the stale rules and groups are deleted under the unchanged SecurityPolicy
+	failed := func(_ *string) (model.RuleListResult, error) {
+		return model.RuleListResult{}, errors.New("unexpected call")
+	}
+	assert.NoError(t, verifyRealizedIntent(securityPolicyReference(sp), rules[2:], groups[2:], failed, getGroup))
+	sp.MarkedForDelete = &deleted
+	assert.NoError(t, verifyRealizedIntent(sp, rules, groups, failed, getGroup))
+}
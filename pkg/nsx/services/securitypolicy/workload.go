@@ -0,0 +1,125 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// UsesWorkloadPeers returns whether any peer of the SecurityPolicy refers to Services or StatefulSets, the policy
+// needs to be reconciled when the IPs of their Pods are changed.
+func UsesWorkloadPeers(obj *v1alpha1.SecurityPolicy) bool {
+	for _, rule := range obj.Spec.Rules {
+		for _, peer := range rule.Sources {
+			if len(peer.Workloads) > 0 {
+				return true
+			}
+		}
+		for _, peer := range rule.Destinations {
+			if len(peer.Workloads) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func workloadKey(namespace string, workload v1alpha1.WorkloadReference) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, workload.Kind, workload.Name)
+}
+
+// getWorkloadSelector returns the selector of the Pods of the workload. A Service without selector, e.g.
+// an ExternalName Service, selects nothing, its IPs are resolved by resolveExternalServiceIPs.
+func (service *SecurityPolicyService) getWorkloadSelector(namespace string, workload v1alpha1.WorkloadReference) (labels.Selector, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: workload.Name}
+	switch workload.Kind {
+	case v1alpha1.WorkloadKindService:
+		svc := &v1.Service{}
+		if err := service.Client.Get(context.TODO(), key, svc); err != nil {
+			return nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return labels.Nothing(), nil
+		}
+		return labels.SelectorFromSet(svc.Spec.Selector), nil
+	case v1alpha1.WorkloadKindStatefulSet:
+		sts := &appsv1.StatefulSet{}
+		if err := service.Client.Get(context.TODO(), key, sts); err != nil {
+			return nil, err
+		}
+		return metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s", workload.Kind)
+	}
+}
+
+// resolveWorkloadIPs returns the IPs of the Pods of the workload, or the IPs of a Service without selector. The
+// Pods of headless Services and StatefulSets keep their names when they're restarted, the last known IPs of a Pod
+// are kept while it has none, so the group members are not removed and added back on every restart.
+func (service *SecurityPolicyService) resolveWorkloadIPs(namespace string, workload v1alpha1.WorkloadReference) ([]string, error) {
+	if workload.Kind == v1alpha1.WorkloadKindService {
+		if ips, external, err := service.resolveExternalServiceIPs(namespace, workload.Name); err != nil || external {
+			return ips, err
+		}
+	}
+	selector, err := service.getWorkloadSelector(namespace, workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", workload.Kind, namespace, workload.Name, err)
+	}
+	podList := &v1.PodList{}
+	if err := service.Client.List(context.TODO(), podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	lastKnown := map[string][]string{}
+	if cached, ok := service.workloadIPs.Load(workloadKey(namespace, workload)); ok {
+		lastKnown = cached.(map[string][]string)
+	}
+	known := map[string][]string{}
+	var ips []string
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		var podIPs []string
+		for _, podIP := range pod.Status.PodIPs {
+			podIPs = append(podIPs, podIP.IP)
+		}
+		if len(podIPs) == 0 {
+			podIPs = lastKnown[pod.Name]
+		}
+		if len(podIPs) > 0 {
+			known[pod.Name] = podIPs
+			ips = append(ips, podIPs...)
+		}
+	}
+	// The Pods which are gone are dropped from the cache.
+	service.workloadIPs.Store(workloadKey(namespace, workload), known)
+	return ips, nil
+}
+
+// buildPeerWorkloadIPs returns the IPs of the Pods of the workloads referred to by the peer, sorted to keep the
+// group expression stable.
+func (service *SecurityPolicyService) buildPeerWorkloadIPs(obj *v1alpha1.SecurityPolicy, peer *v1alpha1.SecurityPolicyPeer) ([]string, error) {
+	ips := sets.New[string]()
+	for _, workload := range peer.Workloads {
+		workloadIPs, err := service.resolveWorkloadIPs(obj.Namespace, workload)
+		if err != nil {
+			return nil, err
+		}
+		ips.Insert(workloadIPs...)
+	}
+	return sets.List(ips), nil
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestSecurityPolicyService_WorkloadPeers(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	dbLabels := map[string]string{"app": "db"}
+	newPod := func(name, ip string) *v1.Pod {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, Labels: dbLabels}}
+		if ip != "" {
+			pod.Status.PodIPs = []v1.PodIP{{IP: ip}}
+		}
+		return pod
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db"},
+			Spec:       v1.ServiceSpec{ClusterIP: v1.ClusterIPNone, Selector: dbLabels},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db"},
+			Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: dbLabels}},
+		},
+		newPod("db-0", "10.0.0.2"),
+		newPod("db-1", "10.0.0.1"),
+	).Build()
+	service := fakeService()
+	service.Client = k8sClient
+
+	sp := spWithPodSelector.DeepCopy()
+	peer := &v1alpha1.SecurityPolicyPeer{Workloads: []v1alpha1.WorkloadReference{
+		{Kind: v1alpha1.WorkloadKindService, Name: "db"},
+		{Kind: v1alpha1.WorkloadKindStatefulSet, Name: "db"},
+	}}
+	assert.False(t, UsesWorkloadPeers(sp))
+	sp.Spec.Rules[0].Sources = []v1alpha1.SecurityPolicyPeer{*peer}
+	assert.True(t, UsesWorkloadPeers(sp))
+
+	// the IPs of the Pods are sorted and deduplicated
+	group := model.Group{}
+	_, _, err := service.updatePeerExpressions(sp, peer, &group, 0, false)
+	assert.NoError(t, err)
+	assert.Len(t, group.Expression, 1)
+	addresses, _ := group.Expression[0].Field("ip_addresses")
+	assert.Equal(t, []data.DataValue{data.NewStringValue("10.0.0.1"), data.NewStringValue("10.0.0.2")}, addresses.(*data.ListValue).List())
+
+	// the last known IPs of a restarting Pod are kept, the Pods which are gone are dropped
+	pod := &v1.Pod{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "db-0"}, pod))
+	pod.Status.PodIPs = nil
+	assert.NoError(t, k8sClient.Update(context.TODO(), pod))
+	assert.NoError(t, k8sClient.Delete(context.TODO(), newPod("db-1", "")))
+	ips, err := service.buildPeerWorkloadIPs(sp, peer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2"}, ips)
+	assert.NoError(t, k8sClient.Create(context.TODO(), newPod("db-1", "")))
+	ips, err = service.buildPeerWorkloadIPs(sp, peer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2"}, ips)
+
+	_, err = service.buildPeerWorkloadIPs(sp, &v1alpha1.SecurityPolicyPeer{Workloads: []v1alpha1.WorkloadReference{
+		{Kind: v1alpha1.WorkloadKindService, Name: "web"},
+	}})
+	assert.ErrorContains(t, err, "failed to get Service ns1/web")
+}
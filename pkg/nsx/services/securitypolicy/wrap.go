@@ -15,13 +15,24 @@ import (
 
 // WrapHierarchySecurityPolicy wrap the security policy with groups and rules into a hierarchy security policy for InfraClient to patch.
 func (service *SecurityPolicyService) WrapHierarchySecurityPolicy(sp *model.SecurityPolicy, gs []model.Group) (*model.Infra, error) {
+	resourceReferenceChildren, err := service.wrapDomainChildren(sp, gs)
+	if err != nil {
+		return nil, err
+	}
+	return service.wrapDomainInfra(resourceReferenceChildren)
+}
+
+// wrapDomainChildren wraps the security policy with rules and the groups into the children of the domain.
+func (service *SecurityPolicyService) wrapDomainChildren(sp *model.SecurityPolicy, gs []model.Group) ([]*data.StructValue, error) {
 	rulesChildren, err := service.wrapRules(sp.Rules)
 	if err != nil {
 		return nil, err
 	}
 	sp.Rules = nil
 	sp.Children = rulesChildren
-	sp.ResourceType = &common.ResourceTypeSecurityPolicy // InfraClient need this field to identify the resource type
+	if !isSecurityPolicyReference(sp) {
+		sp.ResourceType = &common.ResourceTypeSecurityPolicy // InfraClient need this field to identify the resource type
+	}
 
 	securityPolicyChildren, err := service.wrapSecurityPolicy(sp)
 	if err != nil {
@@ -34,16 +45,16 @@ func (service *SecurityPolicyService) WrapHierarchySecurityPolicy(sp *model.Secu
 		return nil, err
 	}
 	resourceReferenceChildren = append(resourceReferenceChildren, groupsChildren...)
-	domainId := getDomain(service)
-	infraChildren, err := service.wrapDomainResource(resourceReferenceChildren, domainId)
-	if err != nil {
-		return nil, err
-	}
-	infra, err := service.wrapInfra(infraChildren)
+	return resourceReferenceChildren, nil
+}
+
+// wrapDomainInfra wraps the children of the domain into the infra for InfraClient to patch.
+func (service *SecurityPolicyService) wrapDomainInfra(children []*data.StructValue) (*model.Infra, error) {
+	infraChildren, err := service.wrapDomainResource(children, getDomain(service))
 	if err != nil {
 		return nil, err
 	}
-	return infra, nil
+	return service.wrapInfra(infraChildren)
 }
 
 func (service *SecurityPolicyService) wrapInfra(children []*data.StructValue) (*model.Infra, error) {
@@ -121,9 +132,222 @@ func (service *SecurityPolicyService) wrapGroups(groups []model.Group) ([]*data.
 	return groupsChildren, nil
 }
 
+// WrapHierarchyRedirectionPolicies wraps the redirection policies with their rules into a hierarchy for InfraClient to patch.
+func (service *SecurityPolicyService) WrapHierarchyRedirectionPolicies(policies []model.RedirectionPolicy) (*model.Infra, error) {
+	var policiesChildren []*data.StructValue
+	for _, p := range policies {
+		policy := p
+		rulesChildren, err := service.wrapRedirectionRules(policy.Rules)
+		if err != nil {
+			return nil, err
+		}
+		policy.Rules = nil
+		var dataValue data.DataValue
+		var errors []error
+		if isRedirectionPolicyReference(&policy) {
+			targetType := common.ResourceTypeRedirectionPolicy
+			childReference := model.ChildResourceReference{
+				Id:           policy.Id,
+				ResourceType: common.ResourceTypeChildResourceReference,
+				TargetType:   &targetType,
+				Children:     rulesChildren,
+			}
+			dataValue, errors = NewConverter().ConvertToVapi(childReference, model.ChildResourceReferenceBindingType())
+		} else {
+			if policy.MarkedForDelete == nil || !*policy.MarkedForDelete {
+				policy.Children = rulesChildren
+			}
+			policy.ResourceType = &common.ResourceTypeRedirectionPolicy // InfraClient need this field to identify the resource type
+			childPolicy := model.ChildRedirectionPolicy{
+				Id:                policy.Id,
+				MarkedForDelete:   policy.MarkedForDelete,
+				ResourceType:      common.ResourceTypeChildRedirectionPolicy,
+				RedirectionPolicy: &policy,
+			}
+			dataValue, errors = NewConverter().ConvertToVapi(childPolicy, model.ChildRedirectionPolicyBindingType())
+		}
+		if len(errors) > 0 {
+			return nil, errors[0]
+		}
+		policiesChildren = append(policiesChildren, dataValue.(*data.StructValue))
+	}
+	infraChildren, err := service.wrapDomainResource(policiesChildren, getDomain(service))
+	if err != nil {
+		return nil, err
+	}
+	return service.wrapInfra(infraChildren)
+}
+
+func (service *SecurityPolicyService) wrapRedirectionRules(rules []model.RedirectionRule) ([]*data.StructValue, error) {
+	var rulesChildren []*data.StructValue
+	for _, r := range rules {
+		rule := r
+		rule.ResourceType = &common.ResourceTypeRedirectionRule
+		childRule := model.ChildRedirectionRule{
+			ResourceType:    common.ResourceTypeChildRedirectionRule,
+			Id:              rule.Id,
+			RedirectionRule: &rule,
+			MarkedForDelete: rule.MarkedForDelete,
+		}
+		dataValue, errors := NewConverter().ConvertToVapi(childRule, model.ChildRedirectionRuleBindingType())
+		if len(errors) > 0 {
+			return nil, errors[0]
+		}
+		rulesChildren = append(rulesChildren, dataValue.(*data.StructValue))
+	}
+	return rulesChildren, nil
+}
+
+// WrapHierarchyGatewayPolicy wraps the gateway policy with rules and the groups into a hierarchy for InfraClient to patch.
+// The unchanged gateway policy is only referred to as the parent of the changed and stale rules.
+func (service *SecurityPolicyService) WrapHierarchyGatewayPolicy(gp *model.GatewayPolicy, gs []model.Group) (*model.Infra, error) {
+	rulesChildren, err := service.wrapRules(gp.Rules)
+	if err != nil {
+		return nil, err
+	}
+	policy := *gp
+	policy.Rules = nil
+	var children []*data.StructValue
+	var dataValue data.DataValue
+	var errors []error
+	if policy.ResourceType != nil && *policy.ResourceType == common.ResourceTypeChildResourceReference {
+		if len(rulesChildren) > 0 {
+			targetType := common.ResourceTypeGatewayPolicy
+			childReference := model.ChildResourceReference{
+				Id:           policy.Id,
+				ResourceType: common.ResourceTypeChildResourceReference,
+				TargetType:   &targetType,
+				Children:     rulesChildren,
+			}
+			dataValue, errors = NewConverter().ConvertToVapi(childReference, model.ChildResourceReferenceBindingType())
+		}
+	} else {
+		if policy.MarkedForDelete == nil || !*policy.MarkedForDelete {
+			policy.Children = rulesChildren
+		}
+		policy.ResourceType = &common.ResourceTypeGatewayPolicy // InfraClient need this field to identify the resource type
+		childPolicy := model.ChildGatewayPolicy{
+			Id:              policy.Id,
+			MarkedForDelete: policy.MarkedForDelete,
+			ResourceType:    common.ResourceTypeChildGatewayPolicy,
+			GatewayPolicy:   &policy,
+		}
+		dataValue, errors = NewConverter().ConvertToVapi(childPolicy, model.ChildGatewayPolicyBindingType())
+	}
+	if len(errors) > 0 {
+		return nil, errors[0]
+	}
+	if dataValue != nil {
+		children = append(children, dataValue.(*data.StructValue))
+	}
+	groupsChildren, err := service.wrapGroups(gs)
+	if err != nil {
+		return nil, err
+	}
+	children = append(children, groupsChildren...)
+	return service.wrapDomainInfra(children)
+}
+
+// WrapHierarchyIDSPolicy wraps the IDS/IPS policy with rules and the groups into a hierarchy for InfraClient to patch.
+// The unchanged IDS/IPS policy is only referred to as the parent of the changed and stale rules.
+func (service *SecurityPolicyService) WrapHierarchyIDSPolicy(ip *model.IdsSecurityPolicy, gs []model.Group) (*model.Infra, error) {
+	var rulesChildren []*data.StructValue
+	for i := range ip.Rules {
+		rule := ip.Rules[i]
+		rule.ResourceType = &common.ResourceTypeIdsRule // InfraClient need this field to identify the resource type
+		childRule := model.ChildIdsRule{
+			Id:              rule.Id,
+			MarkedForDelete: rule.MarkedForDelete,
+			ResourceType:    common.ResourceTypeChildIdsRule,
+			IdsRule:         &rule,
+		}
+		dataValue, errs := NewConverter().ConvertToVapi(childRule, model.ChildIdsRuleBindingType())
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+		rulesChildren = append(rulesChildren, dataValue.(*data.StructValue))
+	}
+	policy := *ip
+	policy.Rules = nil
+	var children []*data.StructValue
+	var dataValue data.DataValue
+	var errors []error
+	if policy.ResourceType != nil && *policy.ResourceType == common.ResourceTypeChildResourceReference {
+		if len(rulesChildren) > 0 {
+			targetType := common.ResourceTypeIdsSecurityPolicy
+			childReference := model.ChildResourceReference{
+				Id:           policy.Id,
+				ResourceType: common.ResourceTypeChildResourceReference,
+				TargetType:   &targetType,
+				Children:     rulesChildren,
+			}
+			dataValue, errors = NewConverter().ConvertToVapi(childReference, model.ChildResourceReferenceBindingType())
+		}
+	} else {
+		if policy.MarkedForDelete == nil || !*policy.MarkedForDelete {
+			policy.Children = rulesChildren
+		}
+		policy.ResourceType = &common.ResourceTypeIdsSecurityPolicy // InfraClient need this field to identify the resource type
+		childPolicy := model.ChildIdsSecurityPolicy{
+			Id:                policy.Id,
+			MarkedForDelete:   policy.MarkedForDelete,
+			ResourceType:      common.ResourceTypeChildIdsSecurityPolicy,
+			IdsSecurityPolicy: &policy,
+		}
+		dataValue, errors = NewConverter().ConvertToVapi(childPolicy, model.ChildIdsSecurityPolicyBindingType())
+	}
+	if len(errors) > 0 {
+		return nil, errors[0]
+	}
+	if dataValue != nil {
+		children = append(children, dataValue.(*data.StructValue))
+	}
+	groupsChildren, err := service.wrapGroups(gs)
+	if err != nil {
+		return nil, err
+	}
+	children = append(children, groupsChildren...)
+	return service.wrapDomainInfra(children)
+}
+
+// securityPolicyReference returns a SecurityPolicy which only refers to the existing one as the parent of
+// the changed and stale rules in the hierarchical patch, so the unchanged SecurityPolicy is not patched again.
+func securityPolicyReference(sp *model.SecurityPolicy) *model.SecurityPolicy {
+	return &model.SecurityPolicy{
+		Id:           sp.Id,
+		ResourceType: &common.ResourceTypeChildResourceReference,
+	}
+}
+
+func isSecurityPolicyReference(sp *model.SecurityPolicy) bool {
+	return sp.ResourceType != nil && *sp.ResourceType == common.ResourceTypeChildResourceReference
+}
+
+func (service *SecurityPolicyService) wrapSecurityPolicyReference(sp *model.SecurityPolicy) ([]*data.StructValue, error) {
+	// nothing to patch under the SecurityPolicy, e.g. only the groups are changed
+	if len(sp.Children) == 0 {
+		return nil, nil
+	}
+	targetType := common.ResourceTypeSecurityPolicy
+	childReference := model.ChildResourceReference{
+		Id:           sp.Id,
+		ResourceType: common.ResourceTypeChildResourceReference,
+		TargetType:   &targetType,
+		Children:     sp.Children,
+	}
+	dataValue, errors := NewConverter().ConvertToVapi(childReference, model.ChildResourceReferenceBindingType())
+	if len(errors) > 0 {
+		return nil, errors[0]
+	}
+	return []*data.StructValue{dataValue.(*data.StructValue)}, nil
+}
+
 func (service *SecurityPolicyService) wrapSecurityPolicy(sp *model.SecurityPolicy) ([]*data.StructValue, error) {
 	var securityPolicyChildren []*data.StructValue
 	resourceType := common.ResourceTypeChildSecurityPolicy
+	if isSecurityPolicyReference(sp) {
+		return service.wrapSecurityPolicyReference(sp)
+	}
 
 	childPolicy := model.ChildSecurityPolicy{
 		Id:              sp.Id,
@@ -230,7 +454,9 @@ func (service *SecurityPolicyService) wrapVPC(sp *model.SecurityPolicy, gs []mod
 	}
 	sp.Rules = nil
 	sp.Children = rulesChildren
-	sp.ResourceType = &common.ResourceTypeSecurityPolicy
+	if !isSecurityPolicyReference(sp) {
+		sp.ResourceType = &common.ResourceTypeSecurityPolicy
+	}
 
 	securityPolicyChildren, err := service.wrapSecurityPolicy(sp)
 	if err != nil {
@@ -301,7 +527,7 @@ func (service *SecurityPolicyService) wrapChildTargetInfra(children []*data.Stru
 }
 
 // wrapHierarchyProjectResources wrap the project shares and groups into a project infra children in VPC mode.
-func (service *SecurityPolicyService) wrapHierarchyProjectResources(shares []model.Share, groups []model.Group) ([]*data.StructValue, error) {
+func (service *SecurityPolicyService) wrapHierarchyProjectResources(namespace string, shares []model.Share, groups []model.Group) ([]*data.StructValue, error) {
 	var domainReferenceChildren []*data.StructValue
 	var infraChildren []*data.StructValue
 
@@ -316,7 +542,7 @@ func (service *SecurityPolicyService) wrapHierarchyProjectResources(shares []mod
 		return nil, err
 	}
 	domainReferenceChildren = append(domainReferenceChildren, groupsChildren...)
-	domainId := getVpcProjectDomain()
+	domainId := getVpcProjectDomain(service, namespace)
 	domainTargetChildren, err := service.wrapDomainResource(domainReferenceChildren, domainId)
 	if err != nil {
 		return nil, err
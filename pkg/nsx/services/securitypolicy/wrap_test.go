@@ -29,7 +29,7 @@ func fakeService() *SecurityPolicyService {
 	c := nsx.NewConfig("localhost", "1", "1", []string{}, 10, 3, 20, 20, true, true, true, ratelimiter.AIMD, nil, nil, []string{})
 	cluster, _ := nsx.NewCluster(c)
 	rc, _ := cluster.NewRestConnector()
-	service = &SecurityPolicyService{
+	return &SecurityPolicyService{
 		Service: common.Service{
 			NSXClient: &nsx.Client{
 				QueryClient:   &fakeQueryClient{},
@@ -47,7 +47,6 @@ func fakeService() *SecurityPolicyService {
 			},
 		},
 	}
-	return service
 }
 
 func TestSecurityPolicyService_wrapSecurityPolicy(t *testing.T) {
@@ -186,3 +185,33 @@ func TestSecurityPolicyService_wrapResourceReference(t *testing.T) {
 		})
 	}
 }
+
+func TestSecurityPolicyService_wrapSecurityPolicyReference(t *testing.T) {
+	Converter := bindings.NewTypeConverter()
+	service := fakeService()
+	mId, ruleId := "sp-1", "rule-1"
+	existing := &model.SecurityPolicy{Id: &mId, DisplayName: &mId, Scope: []string{"/infra/domains/default/groups/g1"}}
+
+	sp := securityPolicyReference(existing)
+	assert.True(t, isSecurityPolicyReference(sp))
+	assert.False(t, isSecurityPolicyReference(existing))
+
+	// only groups are changed, the SecurityPolicy is not in the hierarchy
+	got, err := service.wrapSecurityPolicy(sp)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	sp.Rules = []model.Rule{{Id: &ruleId}}
+	infra, err := service.WrapHierarchySecurityPolicy(sp, nil)
+	assert.NoError(t, err)
+	domain, _ := Converter.ConvertToGolang(infra.Children[0], model.ChildResourceReferenceBindingType())
+	children := domain.(model.ChildResourceReference).Children
+	assert.Equal(t, 1, len(children))
+	ref, _ := Converter.ConvertToGolang(children[0], model.ChildResourceReferenceBindingType())
+	spRef := ref.(model.ChildResourceReference)
+	assert.Equal(t, mId, *spRef.Id)
+	assert.Equal(t, common.ResourceTypeSecurityPolicy, *spRef.TargetType)
+	assert.Equal(t, 1, len(spRef.Children))
+	// the existing SecurityPolicy is untouched
+	assert.Nil(t, existing.Children)
+}
@@ -0,0 +1,189 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+var (
+	ruleActions = map[string]string{
+		util.ToUpper(v1alpha1.RuleActionAllow):  "ALLOW",
+		util.ToUpper(v1alpha1.RuleActionDrop):   "DROP",
+		util.ToUpper(v1alpha1.RuleActionReject): "REJECT",
+	}
+	ruleDirections = map[string]string{
+		util.ToUpper(v1alpha1.RuleDirectionIn):      "IN",
+		util.ToUpper(v1alpha1.RuleDirectionIngress): "IN",
+		util.ToUpper(v1alpha1.RuleDirectionOut):     "OUT",
+		util.ToUpper(v1alpha1.RuleDirectionEgress):  "OUT",
+	}
+)
+
+func validateSubnetPolicy(obj *v1alpha1.SubnetPolicy) error {
+	if len(obj.Spec.Subnets) == 0 && len(obj.Spec.SubnetSets) == 0 {
+		return fmt.Errorf("neither subnets nor subnetSets is set")
+	}
+	for i, rule := range obj.Spec.Rules {
+		if rule.Action == nil || ruleActions[util.ToUpper(*rule.Action)] == "" {
+			return fmt.Errorf("spec.rules[%d] has invalid action", i)
+		}
+		if rule.Direction == nil || ruleDirections[util.ToUpper(*rule.Direction)] == "" {
+			return fmt.Errorf("spec.rules[%d] has invalid direction", i)
+		}
+		for _, ipBlock := range rule.IPBlocks {
+			if _, _, err := net.ParseCIDR(ipBlock.CIDR); err != nil {
+				return fmt.Errorf("spec.rules[%d] has invalid CIDR %s", i, ipBlock.CIDR)
+			}
+			if err := util.ValidateCIDRExcept(ipBlock.CIDR, ipBlock.Except); err != nil {
+				return fmt.Errorf("spec.rules[%d] has invalid IP block: %w", i, err)
+			}
+		}
+		for _, port := range rule.Ports {
+			if port.Port.Type == intstr.String {
+				return fmt.Errorf("spec.rules[%d] has named port %s, named ports are not supported", i, port.Port.StrVal)
+			}
+		}
+	}
+	return nil
+}
+
+func buildPolicyID(uid types.UID) string {
+	return util.GenerateID(string(uid), common.SubnetPolicyPrefix, "", "")
+}
+
+func buildGroupID(uid types.UID) string {
+	return util.GenerateID(string(uid), common.SubnetPolicyPrefix, common.TargetGroupSuffix, "")
+}
+
+// buildSubnetPolicyGroup builds the VPC group of the Subnets the SubnetPolicy is applied to, the Subnets are
+// referred to by path, so the group covers all the VIFs on the Subnets regardless of the Pods or VMs.
+func (service *SubnetPolicyService) buildSubnetPolicyGroup(obj *v1alpha1.SubnetPolicy, subnetPaths []string, vpcInfo *common.VPCResourceInfo) *model.Group {
+	groupID := buildGroupID(obj.UID)
+	return &model.Group{
+		Id:          String(groupID),
+		DisplayName: String(util.GenerateTruncName(common.MaxNameLength, obj.Name, common.SubnetPolicyPrefix, common.TargetGroupSuffix, "", "")),
+		Path:        String(fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/groups/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, groupID)),
+		Expression:  []*data.StructValue{expression.Paths(subnetPaths)},
+		Tags:        service.buildBasicTags(obj),
+	}
+}
+
+// buildSubnetPolicy builds the VPC security policy of the SubnetPolicy, both the policy and its rules are scoped
+// to the group of the Subnets.
+func (service *SubnetPolicyService) buildSubnetPolicy(obj *v1alpha1.SubnetPolicy, groupPath string, vpcInfo *common.VPCResourceInfo) (*model.SecurityPolicy, error) {
+	if err := validateSubnetPolicy(obj); err != nil {
+		return nil, err
+	}
+	policyID := buildPolicyID(obj.UID)
+	policyPath := fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/security-policies/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, policyID)
+	policy := &model.SecurityPolicy{
+		Id:             String(policyID),
+		DisplayName:    String(util.GenerateTruncName(common.MaxNameLength, obj.Name, common.SubnetPolicyPrefix, "", "", "")),
+		Path:           String(policyPath),
+		SequenceNumber: Int64(int64(obj.Spec.Priority)),
+		Scope:          []string{groupPath},
+		Tags:           service.buildBasicTags(obj),
+	}
+	for i := range obj.Spec.Rules {
+		rule, err := service.buildRule(obj, &obj.Spec.Rules[i], i, policyPath, groupPath)
+		if err != nil {
+			return nil, err
+		}
+		policy.Rules = append(policy.Rules, *rule)
+	}
+	return policy, nil
+}
+
+func (service *SubnetPolicyService) buildRule(obj *v1alpha1.SubnetPolicy, rule *v1alpha1.SubnetPolicyRule, ruleIdx int, policyPath string, groupPath string) (*model.Rule, error) {
+	ruleID := util.GenerateID(string(obj.UID), common.SubnetPolicyPrefix, "", fmt.Sprint(ruleIdx))
+	displayName := rule.Name
+	if displayName == "" {
+		displayName = util.GenerateTruncName(common.MaxNameLength, obj.Name, common.SubnetPolicyPrefix, fmt.Sprint(ruleIdx), "", "")
+	}
+	direction := ruleDirections[util.ToUpper(*rule.Direction)]
+	peers := []string{"ANY"}
+	if len(rule.IPBlocks) > 0 {
+		peers = make([]string, 0, len(rule.IPBlocks))
+		for _, ipBlock := range rule.IPBlocks {
+			if len(ipBlock.Except) == 0 {
+				peers = append(peers, ipBlock.CIDR)
+				continue
+			}
+			// the rule peers can't exclude addresses, the IP block is split into the IP ranges around the excepts
+			ranges, err := util.GetCIDRRangesWithExcept(ipBlock.CIDR, ipBlock.Except)
+			if err != nil {
+				return nil, err
+			}
+			peers = append(peers, ranges...)
+		}
+	}
+	nsxRule := &model.Rule{
+		Id:                String(ruleID),
+		DisplayName:       String(displayName),
+		Path:              String(fmt.Sprintf("%s/rules/%s", policyPath, ruleID)),
+		Direction:         String(direction),
+		SequenceNumber:    Int64(int64(ruleIdx)),
+		Action:            String(ruleActions[util.ToUpper(*rule.Action)]),
+		Scope:             []string{groupPath},
+		Services:          []string{"ANY"},
+		SourceGroups:      []string{"ANY"},
+		DestinationGroups: []string{"ANY"},
+		Tags:              service.buildBasicTags(obj),
+	}
+	// The IP blocks are the peers of the Subnets, the traffic is from them for ingress rule, or to them for egress rule.
+	if direction == "IN" {
+		nsxRule.SourceGroups = peers
+	} else {
+		nsxRule.DestinationGroups = peers
+	}
+	for _, port := range rule.Ports {
+		nsxRule.ServiceEntries = append(nsxRule.ServiceEntries, buildRuleServiceEntry(port))
+	}
+	return nsxRule, nil
+}
+
+func buildRuleServiceEntry(port v1alpha1.SecurityPolicyPort) *data.StructValue {
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	destinationPorts := data.NewListValue()
+	// The traffic of all the ports of the protocol is matched if the port is not set.
+	if port.Port.IntVal != 0 {
+		if port.EndPort == 0 {
+			destinationPorts.Add(data.NewStringValue(fmt.Sprint(port.Port.IntVal)))
+		} else {
+			destinationPorts.Add(data.NewStringValue(fmt.Sprintf("%d-%d", port.Port.IntVal, port.EndPort)))
+		}
+	}
+	return data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			"source_ports":      data.NewListValue(),
+			"destination_ports": destinationPorts,
+			"l4_protocol":       data.NewStringValue(string(protocol)),
+			"resource_type":     data.NewStringValue("L4PortSetServiceEntry"),
+			// Adding the following default values to make it easy when compare the
+			// existing object from store and the new built object
+			"marked_for_delete": data.NewBooleanValue(false),
+			"overridden":        data.NewBooleanValue(false),
+		},
+	)
+}
+
+func (service *SubnetPolicyService) buildBasicTags(obj *v1alpha1.SubnetPolicy) []model.Tag {
+	return util.BuildBasicTags(service.NSXConfig.Cluster, obj, "")
+}
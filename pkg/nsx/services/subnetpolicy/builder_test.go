@@ -0,0 +1,114 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func fakeService() *SubnetPolicyService {
+	service := &SubnetPolicyService{}
+	service.NSXConfig = &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"}}
+	return service
+}
+
+func fakeSubnetPolicy() *v1alpha1.SubnetPolicy {
+	allow, drop := v1alpha1.RuleActionAllow, v1alpha1.RuleActionDrop
+	in, out := v1alpha1.RuleDirectionIn, v1alpha1.RuleDirectionEgress
+	return &v1alpha1.SubnetPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "acl1", UID: "uid1"},
+		Spec: v1alpha1.SubnetPolicySpec{
+			Priority: 10,
+			Subnets:  []string{"subnet1"},
+			Rules: []v1alpha1.SubnetPolicyRule{
+				{
+					Name:      "allow-ssh",
+					Action:    &allow,
+					Direction: &in,
+					IPBlocks:  []v1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}},
+					Ports:     []v1alpha1.SecurityPolicyPort{{Protocol: "TCP", Port: intstr.FromInt(22)}},
+				},
+				{
+					Action:    &drop,
+					Direction: &out,
+				},
+			},
+		},
+	}
+}
+
+func TestValidateSubnetPolicy(t *testing.T) {
+	obj := fakeSubnetPolicy()
+	assert.NoError(t, validateSubnetPolicy(obj))
+
+	obj.Spec.Rules[0].Ports[0].Port = intstr.FromString("ssh")
+	assert.EqualError(t, validateSubnetPolicy(obj), "spec.rules[0] has named port ssh, named ports are not supported")
+
+	obj.Spec.Rules[0].IPBlocks[0].CIDR = "10.0.0.0"
+	assert.EqualError(t, validateSubnetPolicy(obj), "spec.rules[0] has invalid CIDR 10.0.0.0")
+
+	obj.Spec.Rules[0].IPBlocks[0] = v1alpha1.IPBlock{CIDR: "10.0.0.0/24", Except: []string{"10.0.1.0/28"}}
+	assert.EqualError(t, validateSubnetPolicy(obj), "spec.rules[0] has invalid IP block: except 10.0.1.0/28 is not strictly within CIDR 10.0.0.0/24")
+
+	redirect := v1alpha1.RuleActionRedirect
+	obj.Spec.Rules[1].Action = &redirect
+	obj.Spec.Rules[0] = fakeSubnetPolicy().Spec.Rules[0]
+	assert.EqualError(t, validateSubnetPolicy(obj), "spec.rules[1] has invalid action")
+
+	obj.Spec.Subnets = nil
+	assert.EqualError(t, validateSubnetPolicy(obj), "neither subnets nor subnetSets is set")
+}
+
+func TestBuildSubnetPolicy(t *testing.T) {
+	service := fakeService()
+	obj := fakeSubnetPolicy()
+	vpcInfo := &common.VPCResourceInfo{OrgID: "default", ProjectID: "project1", VPCID: "vpc1"}
+	subnetPath := "/orgs/default/projects/project1/vpcs/vpc1/subnets/subnet1"
+
+	group := service.buildSubnetPolicyGroup(obj, []string{subnetPath}, vpcInfo)
+	assert.Equal(t, "subnetpolicy_uid1_scope", *group.Id)
+	assert.Equal(t, "/orgs/default/projects/project1/vpcs/vpc1/groups/subnetpolicy_uid1_scope", *group.Path)
+	paths, _ := group.Expression[0].Field("paths")
+	assert.Equal(t, []data.DataValue{data.NewStringValue(subnetPath)}, paths.(*data.ListValue).List())
+
+	policy, err := service.buildSubnetPolicy(obj, *group.Path, vpcInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, "subnetpolicy_uid1", *policy.Id)
+	assert.Equal(t, int64(10), *policy.SequenceNumber)
+	assert.Equal(t, []string{*group.Path}, policy.Scope)
+	assert.Len(t, policy.Rules, 2)
+
+	ingress := policy.Rules[0]
+	assert.Equal(t, "subnetpolicy_uid1_0", *ingress.Id)
+	assert.Equal(t, "allow-ssh", *ingress.DisplayName)
+	assert.Equal(t, "ALLOW", *ingress.Action)
+	assert.Equal(t, "IN", *ingress.Direction)
+	assert.Equal(t, []string{"10.0.0.0/24"}, ingress.SourceGroups)
+	assert.Equal(t, []string{"ANY"}, ingress.DestinationGroups)
+	assert.Len(t, ingress.ServiceEntries, 1)
+	ports, _ := ingress.ServiceEntries[0].Field("destination_ports")
+	assert.Equal(t, []data.DataValue{data.NewStringValue("22")}, ports.(*data.ListValue).List())
+
+	egress := policy.Rules[1]
+	assert.Equal(t, "DROP", *egress.Action)
+	assert.Equal(t, "OUT", *egress.Direction)
+	assert.Equal(t, []string{"ANY"}, egress.SourceGroups)
+	assert.Equal(t, []string{"ANY"}, egress.DestinationGroups)
+	assert.Empty(t, egress.ServiceEntries)
+
+	// the IP block is split into the IP ranges around the excepts
+	obj.Spec.Rules[0].IPBlocks[0].Except = []string{"10.0.0.0/28"}
+	policy, err = service.buildSubnetPolicy(obj, *group.Path, vpcInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.16-10.0.0.255"}, policy.Rules[0].SourceGroups)
+}
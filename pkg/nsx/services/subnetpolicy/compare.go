@@ -0,0 +1,80 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type (
+	SecurityPolicy model.SecurityPolicy
+	Rule           model.Rule
+	Group          model.Group
+)
+
+type Comparable = common.Comparable
+
+func (sp *SecurityPolicy) Key() string {
+	return *sp.Id
+}
+
+func (rule *Rule) Key() string {
+	return *rule.Id
+}
+
+func (group *Group) Key() string {
+	return *group.Id
+}
+
+func (sp *SecurityPolicy) Value() data.DataValue {
+	s := &model.SecurityPolicy{
+		Id:             sp.Id,
+		DisplayName:    sp.DisplayName,
+		SequenceNumber: sp.SequenceNumber,
+		Scope:          sp.Scope,
+		Tags:           sp.Tags,
+	}
+	dataValue, _ := s.GetDataValue__()
+	return dataValue
+}
+
+func (rule *Rule) Value() data.DataValue {
+	r := &model.Rule{
+		Id:                rule.Id,
+		DisplayName:       rule.DisplayName,
+		Tags:              rule.Tags,
+		Direction:         rule.Direction,
+		Scope:             rule.Scope,
+		SequenceNumber:    rule.SequenceNumber,
+		Action:            rule.Action,
+		Services:          rule.Services,
+		ServiceEntries:    rule.ServiceEntries,
+		DestinationGroups: rule.DestinationGroups,
+		SourceGroups:      rule.SourceGroups,
+	}
+	dataValue, _ := r.GetDataValue__()
+	return dataValue
+}
+
+func (group *Group) Value() data.DataValue {
+	g := &model.Group{
+		Id:          group.Id,
+		DisplayName: group.DisplayName,
+		Tags:        group.Tags,
+		Expression:  group.Expression,
+	}
+	dataValue, _ := g.GetDataValue__()
+	return dataValue
+}
+
+func rulesPtrToComparable(rules []*model.Rule) []Comparable {
+	res := make([]Comparable, 0, len(rules))
+	for i := range rules {
+		res = append(res, (*Rule)(rules[i]))
+	}
+	return res
+}
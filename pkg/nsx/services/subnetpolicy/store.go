@@ -0,0 +1,105 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// SecurityPolicyStore is a store for the security policies realizing SubnetPolicy
+type SecurityPolicyStore struct {
+	common.ResourceStore
+}
+
+// RuleStore is a store for the rules of the security policies realizing SubnetPolicy
+type RuleStore struct {
+	common.ResourceStore
+}
+
+// GroupStore is a store for the groups of the Subnets SubnetPolicy is applied to
+type GroupStore struct {
+	common.ResourceStore
+}
+
+// keyFunc is used to get the key of a resource, usually, which is the ID of the resource
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *model.SecurityPolicy:
+		return *v.Id, nil
+	case *model.Rule:
+		return *v.Id, nil
+	case *model.Group:
+		return *v.Id, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+// indexFunc is used to get index of a resource, usually, which is the UID of the CR controller reconciles,
+// index is used to filter out resources which are related to the CR
+func indexFunc(obj interface{}) ([]string, error) {
+	switch v := obj.(type) {
+	case *model.SecurityPolicy:
+		return filterTag(v.Tags), nil
+	case *model.Rule:
+		return filterTag(v.Tags), nil
+	case *model.Group:
+		return filterTag(v.Tags), nil
+	default:
+		return nil, errors.New("indexFunc doesn't support unknown type")
+	}
+}
+
+var filterTag = func(v []model.Tag) []string {
+	res := make([]string, 0, 5)
+	for _, tag := range v {
+		if *tag.Scope == common.TagScopeSubnetPolicyCRUID {
+			res = append(res, *tag.Tag)
+		}
+	}
+	return res
+}
+
+func (securityPolicyStore *SecurityPolicyStore) Apply(i interface{}) error {
+	// not used by subnetpolicy since subnetpolicy doesn't use hierarchy API
+	return nil
+}
+
+func (securityPolicyStore *SecurityPolicyStore) GetByKey(key string) *model.SecurityPolicy {
+	obj := securityPolicyStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		return obj.(*model.SecurityPolicy)
+	}
+	return nil
+}
+
+func (ruleStore *RuleStore) Apply(i interface{}) error {
+	// not used by subnetpolicy since subnetpolicy doesn't use hierarchy API
+	return nil
+}
+
+func (ruleStore *RuleStore) GetByIndex(key string, value string) []*model.Rule {
+	rules := make([]*model.Rule, 0)
+	for _, rule := range ruleStore.ResourceStore.GetByIndex(key, value) {
+		rules = append(rules, rule.(*model.Rule))
+	}
+	return rules
+}
+
+func (groupStore *GroupStore) Apply(i interface{}) error {
+	// not used by subnetpolicy since subnetpolicy doesn't use hierarchy API
+	return nil
+}
+
+func (groupStore *GroupStore) GetByKey(key string) *model.Group {
+	obj := groupStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		return obj.(*model.Group)
+	}
+	return nil
+}
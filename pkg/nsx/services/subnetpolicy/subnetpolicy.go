@@ -0,0 +1,202 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// SubnetPolicyService realizes SubnetPolicy as a VPC security policy scoped to the group of the Subnets.
+type SubnetPolicyService struct {
+	common.Service
+	SecurityPolicyStore *SecurityPolicyStore
+	RuleStore           *RuleStore
+	GroupStore          *GroupStore
+	VPCService          common.VPCServiceProvider
+}
+
+var (
+	log    = logger.Log
+	String = common.String
+	Int64  = common.Int64
+)
+
+// InitializeSubnetPolicy sync NSX resources
+func InitializeSubnetPolicy(commonService common.Service, vpcService common.VPCServiceProvider) (*SubnetPolicyService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(3)
+	subnetPolicyService := &SubnetPolicyService{Service: commonService, VPCService: vpcService}
+	subnetPolicyService.SecurityPolicyStore = &SecurityPolicyStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeSubnetPolicyCRUID: indexFunc}),
+		BindingType: model.SecurityPolicyBindingType(),
+	}}
+	subnetPolicyService.RuleStore = &RuleStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeSubnetPolicyCRUID: indexFunc}),
+		BindingType: model.RuleBindingType(),
+	}}
+	subnetPolicyService.GroupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeSubnetPolicyCRUID: indexFunc}),
+		BindingType: model.GroupBindingType(),
+	}}
+
+	tags := []model.Tag{{Scope: String(common.TagScopeSubnetPolicyCRUID)}}
+	go subnetPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeSecurityPolicy, tags, subnetPolicyService.SecurityPolicyStore)
+	go subnetPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeRule, tags, subnetPolicyService.RuleStore)
+	go subnetPolicyService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeGroup, tags, subnetPolicyService.GroupStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return subnetPolicyService, err
+	}
+
+	return subnetPolicyService, nil
+}
+
+// CreateOrUpdateSubnetPolicy realizes the SubnetPolicy on the Subnets of the given NSX paths.
+func (service *SubnetPolicyService) CreateOrUpdateSubnetPolicy(obj *v1alpha1.SubnetPolicy, subnetPaths []string) error {
+	vpcInfo := service.VPCService.ListVPCInfo(obj.Namespace)
+	if len(vpcInfo) == 0 {
+		return fmt.Errorf("no vpc found for ns %s", obj.Namespace)
+	}
+	group := service.buildSubnetPolicyGroup(obj, subnetPaths, &vpcInfo[0])
+	policy, err := service.buildSubnetPolicy(obj, *group.Path, &vpcInfo[0])
+	if err != nil {
+		return err
+	}
+	orgID, projectID, vpcID := vpcInfo[0].OrgID, vpcInfo[0].ProjectID, vpcInfo[0].VPCID
+
+	existingGroup := service.GroupStore.GetByKey(*group.Id)
+	if existingGroup == nil || common.CompareResource((*Group)(existingGroup), (*Group)(group)) {
+		if err := service.NSXClient.VpcGroupClient.Patch(orgID, projectID, vpcID, *group.Id, *group); err != nil {
+			return err
+		}
+		if err := service.GroupStore.Add(group); err != nil {
+			return err
+		}
+		log.Info("successfully patched NSX group for SubnetPolicy", "group", *group.Id)
+	}
+
+	rules := make([]*model.Rule, 0, len(policy.Rules))
+	for i := range policy.Rules {
+		rules = append(rules, &policy.Rules[i])
+	}
+	existingPolicy := service.SecurityPolicyStore.GetByKey(*policy.Id)
+	existingRules := service.RuleStore.GetByIndex(common.TagScopeSubnetPolicyCRUID, string(obj.UID))
+	changedRules, staleRules := common.CompareResources(rulesPtrToComparable(existingRules), rulesPtrToComparable(rules))
+	if existingPolicy == nil || common.CompareResource((*SecurityPolicy)(existingPolicy), (*SecurityPolicy)(policy)) || len(changedRules) > 0 {
+		if err := service.NSXClient.VPCSecurityClient.Patch(orgID, projectID, vpcID, *policy.Id, *policy); err != nil {
+			return err
+		}
+		policyInStore := *policy
+		policyInStore.Rules = nil
+		if err := service.SecurityPolicyStore.Add(&policyInStore); err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err := service.RuleStore.Add(rule); err != nil {
+				return err
+			}
+		}
+		log.Info("successfully patched NSX security policy for SubnetPolicy", "securityPolicy", *policy.Id, "rules", len(rules))
+	}
+	for _, stale := range staleRules {
+		rule := (*model.Rule)(stale.(*Rule))
+		if err := service.NSXClient.VPCRuleClient.Delete(orgID, projectID, vpcID, *policy.Id, *rule.Id); err != nil {
+			return err
+		}
+		if err := service.RuleStore.Delete(rule); err != nil {
+			return err
+		}
+		log.Info("successfully deleted stale NSX rule for SubnetPolicy", "rule", *rule.Id)
+	}
+	return nil
+}
+
+// DeleteSubnetPolicy deletes the NSX security policy and group realizing the SubnetPolicy of the UID, the VPC
+// of them is got from their paths, so they can be deleted after the Namespace is gone.
+func (service *SubnetPolicyService) DeleteSubnetPolicy(uid types.UID) error {
+	policyID := buildPolicyID(uid)
+	if policy := service.SecurityPolicyStore.GetByKey(policyID); policy != nil {
+		vpcInfo, err := common.ParseVPCResourcePath(*policy.Path)
+		if err != nil {
+			return err
+		}
+		if err := service.NSXClient.VPCSecurityClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, policyID); err != nil {
+			return err
+		}
+		for _, rule := range service.RuleStore.GetByIndex(common.TagScopeSubnetPolicyCRUID, string(uid)) {
+			if err := service.RuleStore.Delete(rule); err != nil {
+				return err
+			}
+		}
+		if err := service.SecurityPolicyStore.Delete(policy); err != nil {
+			return err
+		}
+		log.Info("successfully deleted NSX security policy for SubnetPolicy", "securityPolicy", policyID)
+	}
+
+	groupID := buildGroupID(uid)
+	if group := service.GroupStore.GetByKey(groupID); group != nil {
+		vpcInfo, err := common.ParseVPCResourcePath(*group.Path)
+		if err != nil {
+			return err
+		}
+		if err := service.NSXClient.VpcGroupClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, groupID); err != nil {
+			return err
+		}
+		if err := service.GroupStore.Delete(group); err != nil {
+			return err
+		}
+		log.Info("successfully deleted NSX group for SubnetPolicy", "group", groupID)
+	}
+	return nil
+}
+
+// ListSubnetPolicyID returns the UIDs of the SubnetPolicies which have NSX resources realized.
+func (service *SubnetPolicyService) ListSubnetPolicyID() sets.Set[string] {
+	policySet := service.SecurityPolicyStore.ListIndexFuncValues(common.TagScopeSubnetPolicyCRUID)
+	groupSet := service.GroupStore.ListIndexFuncValues(common.TagScopeSubnetPolicyCRUID)
+	return policySet.Union(groupSet)
+}
+
+func (service *SubnetPolicyService) Cleanup(ctx context.Context) error {
+	uids := service.ListSubnetPolicyID()
+	log.Info("cleaning up subnetpolicy", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteSubnetPolicy(types.UID(uid)); err != nil {
+				log.Error(err, "remove subnetpolicy failed", "uid", uid)
+				return err
+			}
+		}
+	}
+	return nil
+}
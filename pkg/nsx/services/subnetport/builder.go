@@ -76,9 +76,28 @@ func (service *SubnetPortService) buildSubnetPort(obj interface{}, nsxSubnetPath
 		nsxSubnetPort.Attachment.AppId = &appId
 		nsxSubnetPort.Attachment.ContextId = &contextID
 	}
+	if pod, ok := obj.(*corev1.Pod); ok {
+		nsxSubnetPort.AddressBindings = buildSecondaryAddressBindings(pod)
+	}
 	return nsxSubnetPort, nil
 }
 
+// buildSecondaryAddressBindings binds the addresses of the secondary interfaces of the Pod to its port,
+// so that the groups of the port and the SpoofGuard cover all the interfaces of the Pod.
+func buildSecondaryAddressBindings(pod *corev1.Pod) []model.PortAddressBindingEntry {
+	var bindings []model.PortAddressBindingEntry
+	for _, network := range util.GetPodSecondaryNetworks(pod) {
+		for _, ip := range network.IPs {
+			binding := model.PortAddressBindingEntry{IpAddress: String(ip)}
+			if network.Mac != "" {
+				binding.MacAddress = String(network.Mac)
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings
+}
+
 func getCluster(service *SubnetPortService) string {
 	return service.NSXConfig.Cluster
 }
@@ -19,10 +19,11 @@ func (sp *SubnetPort) Key() string {
 
 func (sp *SubnetPort) Value() data.DataValue {
 	s := &SubnetPort{
-		Id:          sp.Id,
-		DisplayName: sp.DisplayName,
-		Tags:        sp.Tags,
-		Attachment:  sp.Attachment,
+		Id:              sp.Id,
+		DisplayName:     sp.DisplayName,
+		Tags:            sp.Tags,
+		Attachment:      sp.Attachment,
+		AddressBindings: sp.AddressBindings,
 	}
 	if sp.Attachment != nil {
 		// Ignoring the fields BmsInterfaceConfig, ContextType, EvpnVlans, HyperbusMode
@@ -21,6 +21,14 @@ func keyFunc(obj interface{}) (string, error) {
 	}
 }
 
+// tagsOf returns the tags of a resource in the store, it is used to count the resources by namespace
+func tagsOf(obj interface{}) []model.Tag {
+	if v, ok := obj.(*model.VpcSubnetPort); ok {
+		return v.Tags
+	}
+	return nil
+}
+
 func filterTag(tags []model.Tag, tagScope string) []string {
 	var res []string
 	for _, tag := range tags {
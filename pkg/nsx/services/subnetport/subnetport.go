@@ -211,6 +211,13 @@ func (service *SubnetPortService) DeleteSubnetPort(uid types.UID) error {
 	return nil
 }
 
+// CountObjects implements common.ObjectCounter.
+func (service *SubnetPortService) CountObjects() map[string]servicecommon.ObjectCounts {
+	return map[string]servicecommon.ObjectCounts{
+		servicecommon.ObjectTypeSubnetPort: service.SubnetPortStore.CountByNamespace(tagsOf),
+	}
+}
+
 func (service *SubnetPortService) ListNSXSubnetPortIDForCR() sets.Set[string] {
 	log.V(2).Info("listing subnet port CR UIDs")
 	subnetPortSet := service.SubnetPortStore.ListIndexFuncValues(servicecommon.TagScopeSubnetPortCRUID)
@@ -49,11 +49,14 @@ func buildPrivateIpBlock(vpc *v1alpha1.VPC, cidr, ip, project, cluster string) m
 	return block
 }
 
-func buildNSXVPC(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo, cluster string, pathMap map[string]string, nsxVPC *model.Vpc) (*model.Vpc, error) {
+// buildNSXVPC builds the NSX VPC for the VPC CR, the labels of the namespace are appended to the basic
+// tags as nsLabelTags, so that the namespace-level metadata is queryable from NSX inventory.
+func buildNSXVPC(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo, cluster string, pathMap map[string]string, nsxVPC *model.Vpc, nsLabelTags []model.Tag) (*model.Vpc, error) {
 	vpc := &model.Vpc{}
+	tags := append(util.BuildBasicTags(cluster, obj, ""), nsLabelTags...)
 	if nsxVPC != nil {
-		// for upgrade case, only check public/private ip block size changing
-		if !IsVPCChanged(nc, nsxVPC) {
+		// for upgrade case, only check public/private ip block size and tags changing
+		if !IsVPCChanged(nc, nsxVPC) && !IsVPCTagsChanged(tags, nsxVPC) {
 			log.Info("no changes on current NSX VPC, skip updating", "VPC", nsxVPC.Id)
 			return nil, nil
 		}
@@ -74,8 +77,8 @@ func buildNSXVPC(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo, cluster stri
 		}
 		vpc.SiteInfos = siteInfos
 		vpc.LoadBalancerVpcEndpoint = &model.LoadBalancerVPCEndpoint{Enabled: &DefaultLoadBalancerVPCEndpointEnabled}
-		vpc.Tags = util.BuildBasicTags(cluster, obj, "")
 	}
+	vpc.Tags = tags
 
 	// update private/public blocks
 	vpc.ExternalIpv4Blocks = nc.ExternalIPv4Blocks
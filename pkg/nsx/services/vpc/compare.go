@@ -19,3 +19,21 @@ func IsVPCChanged(nc common.VPCNetworkConfigInfo, vpc *model.Vpc) bool {
 
 	return false
 }
+
+// IsVPCTagsChanged checks if the tags of the NSX VPC differ from the expected ones regardless of the order,
+// e.g. the labels of the namespace are changed.
+func IsVPCTagsChanged(tags []model.Tag, vpc *model.Vpc) bool {
+	if len(tags) != len(vpc.Tags) {
+		return true
+	}
+	existing := make(map[string]string, len(vpc.Tags))
+	for _, tag := range vpc.Tags {
+		existing[*tag.Scope] = *tag.Tag
+	}
+	for _, tag := range tags {
+		if value, ok := existing[*tag.Scope]; !ok || value != *tag.Tag {
+			return true
+		}
+	}
+	return false
+}
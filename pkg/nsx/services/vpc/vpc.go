@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/expression"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/realizestate"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
@@ -392,6 +394,41 @@ func (s *VPCService) getNetworkconfigNameFromNS(ns string) (string, error) {
 	return ncName, nil
 }
 
+// getNamespaceLabelTags builds the NSX tags from the labels of the namespace sorted by the keys, the labels
+// with the scope prefix of nsx-operator are skipped to not override the basic tags, and the labels exceeding
+// the NSX tags limit are not synced.
+func (s *VPCService) getNamespaceLabelTags(ns string, basicTagCount int) ([]model.Tag, error) {
+	obj := &v1.Namespace{}
+	if err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      ns,
+		Namespace: ns,
+	}, obj); err != nil {
+		log.Error(err, "failed to fetch namespace", "Namespace", ns)
+		return nil, err
+	}
+	return buildNamespaceLabelTags(obj.Labels, basicTagCount), nil
+}
+
+func buildNamespaceLabelTags(labels map[string]string, basicTagCount int) []model.Tag {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if strings.HasPrefix(k, common.TagScopePrefix) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if limit := common.TagsCountMax - basicTagCount; len(keys) > limit {
+		log.Info("namespace labels exceed the NSX tags limit, skip syncing the rest", "Labels", len(keys), "Limit", limit)
+		keys = keys[:max(limit, 0)]
+	}
+	tags := make([]model.Tag, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, model.Tag{Scope: common.String(util.NormalizeLabelKey(k)), Tag: common.String(util.NormalizeName(labels[k]))})
+	}
+	return tags
+}
+
 func (s *VPCService) GetDefaultSNATIP(vpc model.Vpc) (string, error) {
 	ruleClient := s.NSXClient.NATRuleClient
 	info, err := common.ParseVPCResourcePath(*vpc.Path)
@@ -491,7 +528,13 @@ func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.V
 		nsxVPC = nil
 	}
 
-	createdVpc, err := buildNSXVPC(obj, nc, s.NSXConfig.Cluster, paths, nsxVPC)
+	// sync the labels of the namespace to the tags of the NSX VPC
+	nsLabelTags, err := s.getNamespaceLabelTags(obj.Namespace, len(util.BuildBasicTags(s.NSXConfig.Cluster, obj, "")))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createdVpc, err := buildNSXVPC(obj, nc, s.NSXConfig.Cluster, paths, nsxVPC, nsLabelTags)
 	if err != nil {
 		log.Error(err, "failed to build NSX VPC object")
 		return nil, nil, err
@@ -727,8 +770,7 @@ func (service *VPCService) buildAVIGroupTag(vpcId string) []model.Tag {
 func (service *VPCService) createAVIGroup(orgId string, projectId string, vpcId string, groupId string) (*model.Group, error) {
 	group := model.Group{}
 	group.Tags = service.buildAVIGroupTag(vpcId)
-	expression := service.buildExpression("Condition", "VpcSubnet", "AVI_SUBNET_LB|", "Tag", "EQUALS", "EQUALS")
-	group.Expression = []*data.StructValue{expression}
+	group.Expression = []*data.StructValue{expression.TagCondition("VpcSubnet", "AVI_SUBNET_LB|", "EQUALS", "EQUALS").Build()}
 	group.DisplayName = common.String(groupId)
 
 	err := service.NSXClient.VpcGroupClient.Patch(orgId, projectId, vpcId, groupId, group)
@@ -739,20 +781,6 @@ func (service *VPCService) createAVIGroup(orgId string, projectId string, vpcId
 	return &nsxgroup, err
 }
 
-func (service *VPCService) buildExpression(resource_type, member_type, value, key, operator, scope_op string) *data.StructValue {
-	return data.NewStructValue(
-		"",
-		map[string]data.DataValue{
-			"resource_type":  data.NewStringValue(resource_type),
-			"member_type":    data.NewStringValue(member_type),
-			"value":          data.NewStringValue(value),
-			"key":            data.NewStringValue(key),
-			"operator":       data.NewStringValue(operator),
-			"scope_operator": data.NewStringValue(scope_op),
-		},
-	)
-}
-
 func (service *VPCService) buildAVIAllowRule(obj *model.Vpc, externalCIDRs []string, groupId, ruleId, projectId string) (*model.Rule, error) {
 	rule := &model.Rule{}
 	rule.Action = common.String(model.Rule_ACTION_ALLOW)
@@ -12,9 +12,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	mock_client "github.com/vmware-tanzu/nsx-operator/pkg/mock/controller-runtime/client"
 	mocks "github.com/vmware-tanzu/nsx-operator/pkg/mock/vpcclient"
@@ -541,3 +543,41 @@ func TestCreateOrUpdateAVIRule(t *testing.T) {
 	err = service.CreateOrUpdateAVIRule(&vpc1, ns1)
 	assert.Equal(t, err, nil)
 }
+
+func TestBuildNamespaceLabelTags(t *testing.T) {
+	labels := map[string]string{"team": "db", "env": "prod", common.TagScopeCluster: "fake"}
+	tags := buildNamespaceLabelTags(labels, 5)
+	assert.Equal(t, []model.Tag{
+		{Scope: common.String("env"), Tag: common.String("prod")},
+		{Scope: common.String("team"), Tag: common.String("db")},
+	}, tags)
+
+	// the labels exceeding the NSX tags limit are not synced
+	tags = buildNamespaceLabelTags(labels, common.TagsCountMax-1)
+	assert.Equal(t, []model.Tag{{Scope: common.String("env"), Tag: common.String("prod")}}, tags)
+	assert.Empty(t, buildNamespaceLabelTags(labels, common.TagsCountMax))
+}
+
+func TestBuildNSXVPCTags(t *testing.T) {
+	obj := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "uid1"}}
+	nc := common.VPCNetworkConfigInfo{ExternalIPv4Blocks: []string{"block1"}, PrivateIPv4CIDRs: []string{"10.0.0.0/16"}}
+	pathMap := map[string]string{"10.0.0.0/16": "/infra/ip-blocks/block2"}
+	labelTags := []model.Tag{{Scope: common.String("env"), Tag: common.String("prod")}}
+
+	created, err := buildNSXVPC(obj, nc, cluster, pathMap, nil, labelTags)
+	assert.NoError(t, err)
+	assert.Contains(t, created.Tags, labelTags[0])
+	assert.False(t, IsVPCTagsChanged(created.Tags, created))
+
+	// no change on the existing VPC
+	updated, err := buildNSXVPC(obj, nc, cluster, pathMap, created, labelTags)
+	assert.NoError(t, err)
+	assert.Nil(t, updated)
+
+	// the labels of namespace are changed
+	labelTags = []model.Tag{{Scope: common.String("env"), Tag: common.String("dev")}}
+	updated, err = buildNSXVPC(obj, nc, cluster, pathMap, created, labelTags)
+	assert.NoError(t, err)
+	assert.Contains(t, updated.Tags, labelTags[0])
+	assert.NotContains(t, updated.Tags, model.Tag{Scope: common.String("env"), Tag: common.String("prod")})
+}
@@ -0,0 +1,66 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+// SiteClients holds the NSX clients of the additional NSX endpoints, keyed by site name.
+// The empty site name refers to the default NSX endpoint in [nsx_v3].
+type SiteClients struct {
+	defaultClient *Client
+	clients       map[string]*Client
+	sites         []string
+}
+
+// NewSiteClients creates one client per site configured in cf, every client has its own
+// connection pool, health and version checker.
+func NewSiteClients(cf *config.NSXOperatorConfig, defaultClient *Client) *SiteClients {
+	siteClients := &SiteClients{
+		defaultClient: defaultClient,
+		clients:       make(map[string]*Client),
+		sites:         cf.SiteNames(),
+	}
+	for _, site := range siteClients.sites {
+		log.Info("creating NSX client", "site", site)
+		siteClients.clients[site] = GetClient(cf.SiteConfig(site))
+	}
+	return siteClients
+}
+
+// Get returns the client of site, the default client for empty site.
+func (s *SiteClients) Get(site string) (*Client, error) {
+	if site == "" {
+		return s.defaultClient, nil
+	}
+	client, ok := s.clients[site]
+	if !ok {
+		return nil, fmt.Errorf("NSX site %s is not configured", site)
+	}
+	return client, nil
+}
+
+// Sites returns the sorted names of the additional sites.
+func (s *SiteClients) Sites() []string {
+	return s.sites
+}
+
+// CheckSitesHealth is the healthz checker of the additional sites, it fails if any site is down.
+func (s *SiteClients) CheckSitesHealth(_ *http.Request) error {
+	var down []string
+	for _, site := range s.sites {
+		if err := s.clients[site].NSXChecker.CheckNSXHealth(nil); err != nil {
+			down = append(down, site)
+		}
+	}
+	if len(down) > 0 {
+		return fmt.Errorf("NSX sites are down: %s", strings.Join(down, ","))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package nsx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteClients_Get(t *testing.T) {
+	defaultClient := &Client{}
+	siteClient := &Client{}
+	siteClients := &SiteClients{
+		defaultClient: defaultClient,
+		clients:       map[string]*Client{"site-a": siteClient},
+		sites:         []string{"site-a"},
+	}
+
+	client, err := siteClients.Get("")
+	assert.Nil(t, err)
+	assert.Same(t, defaultClient, client)
+	client, err = siteClients.Get("site-a")
+	assert.Nil(t, err)
+	assert.Same(t, siteClient, client)
+	_, err = siteClients.Get("site-b")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"site-a"}, siteClients.Sites())
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/third_party/retry"
 )
@@ -21,6 +22,18 @@ type Transport struct {
 	Base      http.RoundTripper
 	endpoints []*Endpoint
 	config    *Config
+	// partitioner partitions the rate limiter tokens between the subsystems, nil if it's not configured.
+	partitioner *ratelimiter.Partitioner
+}
+
+// subsystemTransport accounts the requests sent through it to the subsystem.
+type subsystemTransport struct {
+	base      http.RoundTripper
+	subsystem string
+}
+
+func (t *subsystemTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(r.WithContext(ratelimiter.WithSubsystem(r.Context(), t.subsystem)))
 }
 
 // RoundTrip is the core of the transport. It accepts a request,
@@ -43,12 +56,16 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 			defer ep.decreaseConnNumber()
 
 			util.UpdateRequestURL(r.URL, ep.Host(), ep.Thumbprint)
+			if t.usesSession() && ep.sessionRenewalDue(t.config.SessionRenewInterval) {
+				ep.renewAuthSession(t.config.ClientCertProvider, t.config.TokenProvider, t.config.Username, t.config.Password, jarCache, time.Now().Add(-t.config.SessionRenewInterval))
+			}
 			ep.UpdateHttpRequestAuth(r)
 			ep.UpdateCAforEnvoy(r)
 			start := time.Now()
-			ep.wait()
+			t.wait(ep, r)
 			util.DumpHttpRequest(r)
 			waitTime := time.Since(start)
+			countRequest(r.Method)
 			if resp, resul = t.base().RoundTrip(r); resul != nil {
 				ep.setStatus(DOWN)
 				return handleRoundTripError(resul, ep)
@@ -76,7 +93,7 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 				if t.config.TokenProvider != nil {
 					t.config.TokenProvider.GetToken(true)
 				} else {
-					ep.createAuthSession(t.config.ClientCertProvider, t.config.TokenProvider, t.config.Username, t.config.Password, jarCache)
+					ep.renewAuthSession(t.config.ClientCertProvider, t.config.TokenProvider, t.config.Username, t.config.Password, jarCache, start)
 				}
 			}
 			return err
@@ -95,6 +112,28 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return resp, resul
 }
 
+// usesSession returns whether the requests are authenticated by the NSX auth session, which is created with
+// the user and password unless the client certificate or JWT is used.
+func (t *Transport) usesSession() bool {
+	return t.config != nil && t.config.ClientCertProvider == nil && t.config.TokenProvider == nil
+}
+
+// wait blocks the request until a token of the endpoint rate limiter is gained. If the partitioner is
+// configured, it waits for the turn of its subsystem first, the deletions are accounted to SubsystemGC.
+func (t *Transport) wait(ep *Endpoint, r *http.Request) {
+	if t.partitioner == nil {
+		ep.wait()
+		return
+	}
+	subsystem := ratelimiter.SubsystemFrom(r.Context())
+	if r.Method == http.MethodDelete {
+		subsystem = ratelimiter.SubsystemGC
+	}
+	t.partitioner.Acquire(subsystem)
+	defer t.partitioner.Release()
+	ep.wait()
+}
+
 func handleRoundTripError(err error, ep *Endpoint) error {
 	log.Error(err, "request failed")
 	errString := err.Error()
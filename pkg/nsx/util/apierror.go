@@ -0,0 +1,160 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	vapierrors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
+)
+
+// ObjectNotFoundErrorCode is the NSX error code returned when the requested object is not found.
+const ObjectNotFoundErrorCode int64 = 600
+
+// APIErrorDetail is the structured error returned in the body of NSX API response,
+// i.e. error_code, module_name, error_message and related_errors.
+type APIErrorDetail struct {
+	ErrorCode     int64
+	ModuleName    string
+	ErrorMessage  string
+	RelatedErrors []APIErrorDetail
+}
+
+func (d *APIErrorDetail) String() string {
+	msg := fmt.Sprintf("error_code %d", d.ErrorCode)
+	if d.ModuleName != "" {
+		msg = fmt.Sprintf("%s, module %s", msg, d.ModuleName)
+	}
+	if d.ErrorMessage != "" {
+		msg = fmt.Sprintf("%s: %s", msg, d.ErrorMessage)
+	}
+	if len(d.RelatedErrors) > 0 {
+		related := make([]string, 0, len(d.RelatedErrors))
+		for i := range d.RelatedErrors {
+			related = append(related, d.RelatedErrors[i].String())
+		}
+		msg = fmt.Sprintf("%s; related errors: [%s]", msg, strings.Join(related, "; "))
+	}
+	return msg
+}
+
+// APIError wraps the error returned by an NSX API call with its structured detail.
+type APIError struct {
+	APIErrorDetail
+	// ErrorType is the type of the vAPI error, e.g. INVALID_REQUEST, empty for errors from HTTP response
+	ErrorType string
+	err       error
+}
+
+func (e *APIError) Error() string {
+	if e.ErrorType != "" {
+		return fmt.Sprintf("NSX API error %s, %s", e.ErrorType, e.APIErrorDetail.String())
+	}
+	return fmt.Sprintf("NSX API error, %s", e.APIErrorDetail.String())
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// ErrorCodeLabel returns the error code as a string, used as metrics label.
+func (e *APIError) ErrorCodeLabel() string {
+	return strconv.FormatInt(e.ErrorCode, 10)
+}
+
+// ParseAPIError returns the structured detail of an error returned by NSX API, either the vAPI
+// error of the SDK clients or the NsxError initialized from HTTP response, nil for other errors.
+func ParseAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if nsxErr, ok := err.(interface{ GetDetail() *ErrorDetail }); ok {
+		detail := nsxErr.GetDetail()
+		if detail.ErrorCode == 0 {
+			return nil
+		}
+		result := &APIError{err: err}
+		result.ErrorCode = int64(detail.ErrorCode)
+		result.ModuleName = detail.ModuleName
+		result.ErrorMessage = detail.ErrorMessage
+		for i, code := range detail.RelatedErrorCodes {
+			related := APIErrorDetail{ErrorCode: int64(code)}
+			if i < len(detail.RelatedErrorMessages) {
+				related.ErrorMessage = detail.RelatedErrorMessages[i]
+			}
+			result.RelatedErrors = append(result.RelatedErrors, related)
+		}
+		return result
+	}
+	if dump, errorType := DumpAPIError(err); dump != nil {
+		result := &APIError{err: err}
+		if errorType != nil {
+			result.ErrorType = string(*errorType)
+		}
+		if dump.ErrorCode != nil {
+			result.ErrorCode = *dump.ErrorCode
+		}
+		if dump.ModuleName != nil {
+			result.ModuleName = *dump.ModuleName
+		}
+		if dump.ErrorMessage != nil {
+			result.ErrorMessage = *dump.ErrorMessage
+		}
+		for _, related := range dump.RelatedErrors {
+			detail := APIErrorDetail{}
+			if related.ErrorCode != nil {
+				detail.ErrorCode = *related.ErrorCode
+			}
+			if related.ModuleName != nil {
+				detail.ModuleName = *related.ModuleName
+			}
+			if related.ErrorMessage != nil {
+				detail.ErrorMessage = *related.ErrorMessage
+			}
+			result.RelatedErrors = append(result.RelatedErrors, detail)
+		}
+		return result
+	}
+	return nil
+}
+
+// IsNotFound returns true if the error returned by NSX API indicates the object is not found,
+// either a 404 response, a vAPI NOT_FOUND error or the NSX object not found error code.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var notFound vapierrors.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var resourceNotFound *ResourceNotFound
+	var backendResourceNotFound *BackendResourceNotFound
+	if errors.As(err, &resourceNotFound) || errors.As(err, &backendResourceNotFound) {
+		return true
+	}
+	apiErr := ParseAPIError(err)
+	if apiErr == nil {
+		return false
+	}
+	if apiErr.ErrorType == string(vapierrors.ErrorType_NOT_FOUND) || apiErr.ErrorCode == ObjectNotFoundErrorCode {
+		return true
+	}
+	if len(apiErr.RelatedErrors) == 0 {
+		return false
+	}
+	for _, related := range apiErr.RelatedErrors {
+		if related.ErrorCode != ObjectNotFoundErrorCode {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAPIError(t *testing.T) {
+	assert.Nil(t, ParseAPIError(nil))
+	assert.Nil(t, ParseAPIError(errors.New("not an NSX error")))
+
+	body := `{"httpStatus": "BAD_REQUEST", "error_code": 500012, "module_name": "Policy", "error_message": "Invalid path",
+"related_errors": [{"httpStatus": "BAD_REQUEST", "error_code": 505, "module_name": "Policy", "error_message": "Invalid license"}]}`
+	nsxErr := InitErrorFromResponse("10.0.0.1", 400, []byte(body))
+	apiErr := ParseAPIError(nsxErr)
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, int64(500012), apiErr.ErrorCode)
+	assert.Equal(t, "500012", apiErr.ErrorCodeLabel())
+	assert.Equal(t, "Policy", apiErr.ModuleName)
+	assert.Equal(t, "Invalid path", apiErr.ErrorMessage)
+	assert.Equal(t, []APIErrorDetail{{ErrorCode: 505, ErrorMessage: "Invalid license"}}, apiErr.RelatedErrors)
+	assert.Equal(t, "NSX API error, error_code 500012, module Policy: Invalid path; related errors: [error_code 505: Invalid license]", apiErr.Error())
+	assert.True(t, errors.Is(apiErr, nsxErr))
+
+	// wrapped APIError is returned as it is
+	wrapped := fmt.Errorf("failed to patch: %w", apiErr)
+	assert.Equal(t, apiErr, ParseAPIError(wrapped))
+
+	// NsxError without error code
+	assert.Nil(t, ParseAPIError(CreateResourceNotFound("10.0.0.1", "ippool")))
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.False(t, IsNotFound(nil))
+	assert.False(t, IsNotFound(errors.New("not an NSX error")))
+	assert.True(t, IsNotFound(CreateResourceNotFound("10.0.0.1", "ippool")))
+	assert.True(t, IsNotFound(fmt.Errorf("failed to patch: %w", InitErrorFromResponse("10.0.0.1", 404, []byte(`{"error_code": 202}`)))))
+
+	body := `{"httpStatus": "BAD_REQUEST", "error_code": 500012, "module_name": "Policy", "error_message": "Invalid path",
+"related_errors": [{"httpStatus": "NOT_FOUND", "error_code": 600, "module_name": "Policy", "error_message": "object not found"}]}`
+	assert.True(t, IsNotFound(InitErrorFromResponse("10.0.0.1", 400, []byte(body))))
+
+	body = `{"httpStatus": "BAD_REQUEST", "error_code": 500012, "module_name": "Policy", "error_message": "Invalid path",
+"related_errors": [{"error_code": 600, "error_message": "object not found"}, {"error_code": 505, "error_message": "Invalid license"}]}`
+	assert.False(t, IsNotFound(InitErrorFromResponse("10.0.0.1", 400, []byte(body))))
+}
@@ -41,6 +41,11 @@ func (impl *nsxErrorImpl) setDetail(detail *ErrorDetail) {
 	}
 }
 
+// GetDetail returns the detail extracted from HTTP response
+func (impl *nsxErrorImpl) GetDetail() *ErrorDetail {
+	return &impl.ErrorDetail
+}
+
 func (impl *nsxErrorImpl) Error() string {
 	if impl.ErrorDetail.StatusCode != 0 {
 		return impl.msg + impl.ErrorDetail.Error()
@@ -571,6 +576,15 @@ func (err RestrictionError) Error() string {
 	return err.Desc
 }
 
+// OwnershipConflictError is returned when the NSX object is owned by another controller.
+type OwnershipConflictError struct {
+	Desc string
+}
+
+func (err OwnershipConflictError) Error() string {
+	return err.Desc
+}
+
 type IPBlockAllExhaustedError struct {
 	Desc string
 }
@@ -40,6 +40,10 @@ type ErrorDetail struct {
 	RelatedErrorCodes  []int
 	RelatedStatusCodes []string
 	Details            string
+	// ModuleName, ErrorMessage and RelatedErrorMessages keep the body fields for APIError
+	ModuleName           string
+	ErrorMessage         string
+	RelatedErrorMessages []string
 }
 
 // PortAddress is used when named port is specified.
@@ -71,6 +75,7 @@ type responseBody struct {
 	ErrorCode  int             `json:"error_code"`
 	RelatedErr []relatedErrors `json:"related_errors"`
 	ErrorMsg   string          `json:"error_message"`
+	ModuleName string          `json:"module_name"`
 }
 
 type relatedErrors struct {
@@ -147,10 +152,13 @@ func extractHTTPDetailFromBody(host string, statusCode int, body []byte) (ErrorD
 	}
 
 	ec.ErrorCode = res.ErrorCode
+	ec.ModuleName = res.ModuleName
+	ec.ErrorMessage = res.ErrorMsg
 	msg := []string{res.ErrorMsg}
 	for _, a := range res.RelatedErr {
 		ec.RelatedErrorCodes = append(ec.RelatedErrorCodes, a.ErrorCode)
 		ec.RelatedStatusCodes = append(ec.RelatedStatusCodes, a.HTTPStatus)
+		ec.RelatedErrorMessages = append(ec.RelatedErrorMessages, a.ErrorMessage)
 		msg = append(msg, a.ErrorMessage)
 	}
 	ec.Details = res.ErrorMsg
@@ -510,6 +518,10 @@ func CasttoPointer(obj interface{}) interface{} {
 		return &v
 	case model.SecurityPolicy:
 		return &v
+	case model.RedirectionPolicy:
+		return &v
+	case model.RedirectionRule:
+		return &v
 	case model.Share:
 		return &v
 	case model.SegmentPort:
@@ -24,11 +24,11 @@ import (
 func TestHttpErrortoNSXError(t *testing.T) {
 	assert := assert.New(t)
 	testdatas := []ErrorDetail{
-		{404, 202, []int{}, []string{}, ""},
-		{404, 0, []int{}, []string{}, ""},
-		{409, 202, []int{}, []string{}, ""},
-		{500, 0, []int{99}, []string{}, ""},
-		{505, 0, []int{}, []string{}, ""},
+		{StatusCode: 404, ErrorCode: 202, RelatedErrorCodes: []int{}, RelatedStatusCodes: []string{}},
+		{StatusCode: 404, ErrorCode: 0, RelatedErrorCodes: []int{}, RelatedStatusCodes: []string{}},
+		{StatusCode: 409, ErrorCode: 202, RelatedErrorCodes: []int{}, RelatedStatusCodes: []string{}},
+		{StatusCode: 500, ErrorCode: 0, RelatedErrorCodes: []int{99}, RelatedStatusCodes: []string{}},
+		{StatusCode: 505, ErrorCode: 0, RelatedErrorCodes: []int{}, RelatedStatusCodes: []string{}},
 	}
 
 	err := httpErrortoNSXError(&testdatas[0])
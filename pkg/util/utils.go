@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/sha1" // #nosec G505: not used for security purposes
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -40,8 +41,11 @@ var (
 	basicTags = []string{
 		common.TagScopeCluster, common.TagScopeVersion,
 		common.TagScopeStaticRouteCRName, common.TagScopeStaticRouteCRUID,
+		common.TagScopeSubnetPolicyCRName, common.TagScopeSubnetPolicyCRUID,
+		common.TagScopeSecurityExclusionCRName, common.TagScopeSecurityExclusionCRUID,
 		common.TagValueScopeSecurityPolicyName, common.TagValueScopeSecurityPolicyUID,
 		common.TagScopeNetworkPolicyName, common.TagScopeNetworkPolicyUID,
+		common.TagScopeServiceExposureName, common.TagScopeServiceExposureUID,
 		common.TagScopeSubnetCRName, common.TagScopeSubnetCRUID,
 		common.TagScopeSubnetPortCRName, common.TagScopeSubnetPortCRUID,
 		common.TagScopeVPCCRName, common.TagScopeVPCCRUID,
@@ -163,6 +167,46 @@ func CheckPodHasNamedPort(pod v1.Pod, reason string) bool {
 	return false
 }
 
+// PodNetworkStatus is the status of an interface of the Pod in the Multus network-status annotation.
+type PodNetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Mac       string   `json:"mac,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// GetPodSecondaryNetworks returns the secondary interfaces of the Pod which have addresses, the
+// default interface is the one of the Pod IPs. The annotation which can't be parsed is ignored.
+func GetPodSecondaryNetworks(pod *v1.Pod) []PodNetworkStatus {
+	annotation, ok := pod.Annotations[common.AnnotationPodNetworkStatus]
+	if !ok {
+		return nil
+	}
+	var statuses []PodNetworkStatus
+	if err := json.Unmarshal([]byte(annotation), &statuses); err != nil {
+		log.Error(err, "failed to parse network status of pod", "namespace", pod.Namespace, "name", pod.Name)
+		return nil
+	}
+	var secondaryNetworks []PodNetworkStatus
+	for _, status := range statuses {
+		if status.Default || len(status.IPs) == 0 {
+			continue
+		}
+		secondaryNetworks = append(secondaryNetworks, status)
+	}
+	return secondaryNetworks
+}
+
+// GetPodSecondaryIPs returns the addresses of the secondary interfaces of the Pod.
+func GetPodSecondaryIPs(pod *v1.Pod) []string {
+	var ips []string
+	for _, network := range GetPodSecondaryNetworks(pod) {
+		ips = append(ips, network.IPs...)
+	}
+	return ips
+}
+
 func Contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
@@ -270,41 +314,46 @@ func rangesAbstractRange(ranges [][]net.IP, except []net.IP) [][]net.IP {
 	except[0] = except[0].To4()
 	except[1] = except[1].To4()
 	for _, r := range ranges {
-		rng := r
-		rng[0] = rng[0].To4()
-		rng[1] = rng[1].To4()
-		exceptPrev, _ := calculateOffsetIP(except[0], -1)
-		exceptNext, _ := calculateOffsetIP(except[1], 1)
-		if compareIP(except[0], rng[0]) && compareIP(rng[1], except[1]) {
-		} else if compareIP(rng[0], except[0]) && compareIP(except[1], rng[1]) {
-			results = append(results, []net.IP{rng[0], exceptPrev}, []net.IP{exceptNext, rng[1]})
-		} else if compareIP(rng[0], except[0]) && compareIP(except[0], rng[1]) && compareIP(rng[1], except[1]) {
+		rng := []net.IP{r[0].To4(), r[1].To4()}
+		if compareIP(except[1], rng[0]) || compareIP(rng[1], except[0]) {
+			// the except doesn't overlap the range
+			results = append(results, rng)
+			continue
+		}
+		// the except may start or end at the bounds of the range, e.g. it's the first subnet of the CIDR
+		if compareIP(rng[0], except[0]) {
+			exceptPrev, _ := calculateOffsetIP(except[0], -1)
 			results = append(results, []net.IP{rng[0], exceptPrev})
-		} else if compareIP(except[0], rng[0]) && compareIP(rng[0], except[1]) && compareIP(except[1], rng[1]) {
+		}
+		if compareIP(except[1], rng[1]) {
+			exceptNext, _ := calculateOffsetIP(except[1], 1)
 			results = append(results, []net.IP{exceptNext, rng[1]})
-		} else if compareIP(except[1], rng[0]) {
-			results = append(results, []net.IP{rng[0], rng[1]})
-		} else if compareIP(rng[1], except[0]) {
-			results = append(results, []net.IP{rng[0], rng[1]})
 		}
 	}
 	return results
 }
 
+// GetCIDRRangesWithExcept returns the IP ranges, formatted as start-end, of the IPv4 CIDR without the excepts.
 func GetCIDRRangesWithExcept(cidr string, excepts []string) ([]string, error) {
 	var calculatedRanges [][]net.IP
 	var resultRanges []string
 	mainStartIP, mainEndIP, err := parseCIDRRange(cidr)
-	calculatedRanges = append(calculatedRanges, []net.IP{mainStartIP, mainEndIP})
 	if err != nil {
 		return nil, err
 	}
+	if mainStartIP.To4() == nil {
+		return nil, fmt.Errorf("except is only supported with IPv4 CIDR, got %s", cidr)
+	}
+	calculatedRanges = append(calculatedRanges, []net.IP{mainStartIP, mainEndIP})
 	for _, ept := range excepts {
 		except := ept
 		exceptStartIP, exceptEndIP, err := parseCIDRRange(except)
 		if err != nil {
 			return nil, err
 		}
+		if exceptStartIP.To4() == nil {
+			return nil, fmt.Errorf("except is only supported with IPv4 CIDR, got %s", except)
+		}
 		calculatedRanges = rangesAbstractRange(calculatedRanges, []net.IP{exceptStartIP, exceptEndIP})
 	}
 	for _, rng := range calculatedRanges {
@@ -313,6 +362,40 @@ func GetCIDRRangesWithExcept(cidr string, excepts []string) ([]string, error) {
 	return resultRanges, nil
 }
 
+// ValidateCIDRExcept checks the excepts of the CIDR are IPv4 CIDRs strictly within it, which don't exclude
+// all its IPs together.
+func ValidateCIDRExcept(cidr string, excepts []string) error {
+	if len(excepts) == 0 {
+		return nil
+	}
+	_, cidrNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %s", cidr)
+	}
+	if cidrNet.IP.To4() == nil {
+		return fmt.Errorf("except is only supported with IPv4 CIDR, got %s", cidr)
+	}
+	ones, _ := cidrNet.Mask.Size()
+	for _, except := range excepts {
+		_, exceptNet, err := net.ParseCIDR(except)
+		if err != nil {
+			return fmt.Errorf("invalid except CIDR %s", except)
+		}
+		exceptOnes, _ := exceptNet.Mask.Size()
+		if exceptNet.IP.To4() == nil || exceptOnes <= ones || !cidrNet.Contains(exceptNet.IP) {
+			return fmt.Errorf("except %s is not strictly within CIDR %s", except, cidr)
+		}
+	}
+	ranges, err := GetCIDRRangesWithExcept(cidr, excepts)
+	if err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return fmt.Errorf("except excludes all the IPs of CIDR %s", cidr)
+	}
+	return nil
+}
+
 func If(condition bool, trueVal, falseVal interface{}) interface{} {
 	if condition {
 		return trueVal
@@ -453,6 +536,13 @@ func BuildBasicTags(cluster string, obj interface{}, namespaceID types.UID) []mo
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeStaticRouteCRName), Tag: String(i.ObjectMeta.Name)})
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeStaticRouteCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.SubnetPolicy:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeSubnetPolicyCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeSubnetPolicyCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.SecurityExclusion:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeSecurityExclusionCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeSecurityExclusionCRUID), Tag: String(string(i.UID))})
 	case *v1alpha1.SecurityPolicy:
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
 	case *networkingv1.NetworkPolicy:
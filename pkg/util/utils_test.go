@@ -155,6 +155,27 @@ func Test_CheckPodHasNamedPort(t *testing.T) {
 	}
 }
 
+func TestGetPodSecondaryNetworks(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "test-namespace"}}
+	assert.Nil(t, GetPodSecondaryNetworks(pod))
+
+	pod.Annotations = map[string]string{"k8s.v1.cni.cncf.io/network-status": `[
+		{"name": "nsx", "interface": "eth0", "ips": ["10.0.0.5"], "mac": "04:50:56:00:00:01", "default": true},
+		{"name": "test-namespace/storage", "interface": "net1", "ips": ["192.168.10.5", "fd00::5"], "mac": "04:50:56:00:00:02"},
+		{"name": "test-namespace/bridge", "interface": "net2"}
+	]`}
+	assert.Equal(t, []PodNetworkStatus{{
+		Name:      "test-namespace/storage",
+		Interface: "net1",
+		IPs:       []string{"192.168.10.5", "fd00::5"},
+		Mac:       "04:50:56:00:00:02",
+	}}, GetPodSecondaryNetworks(pod))
+	assert.Equal(t, []string{"192.168.10.5", "fd00::5"}, GetPodSecondaryIPs(pod))
+
+	pod.Annotations["k8s.v1.cni.cncf.io/network-status"] = "invalid"
+	assert.Nil(t, GetPodSecondaryIPs(pod))
+}
+
 func TestToUpper(t *testing.T) {
 	type args struct {
 		obj interface{}
@@ -591,6 +612,10 @@ func TestGetCIDRRangesWithExcept(t *testing.T) {
 	cidr2 := "172.0.0.0/16"
 	excepts2 := []string{"172.0.100.0/24", "172.0.102.0/24"}
 	want2 := []string{"172.0.0.0-172.0.99.255", "172.0.101.0-172.0.101.255", "172.0.103.0-172.0.255.255"}
+	// the excepts at the bounds of the CIDR
+	cidr3 := "10.0.0.0/24"
+	excepts3 := []string{"10.0.0.0/28", "10.0.0.240/28"}
+	want3 := []string{"10.0.0.16-10.0.0.239"}
 	type args struct {
 		cidr    string
 		excepts []string
@@ -602,6 +627,7 @@ func TestGetCIDRRangesWithExcept(t *testing.T) {
 	}{
 		{"1", args{cidr1, excepts1}, want1},
 		{"2", args{cidr2, excepts2}, want2},
+		{"3", args{cidr3, excepts3}, want3},
 	}
 	for _, tt := range tests {
 		got, err := GetCIDRRangesWithExcept(tt.args.cidr, tt.args.excepts)
@@ -614,6 +640,33 @@ func TestGetCIDRRangesWithExcept(t *testing.T) {
 	}
 }
 
+func TestValidateCIDRExcept(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		excepts []string
+		wantErr string
+	}{
+		{name: "no-except", cidr: "fd00::/64"},
+		{name: "valid", cidr: "10.0.0.0/24", excepts: []string{"10.0.0.0/28", "10.0.0.1/32"}},
+		{name: "invalid-except", cidr: "10.0.0.0/24", excepts: []string{"10.0.0.300/32"}, wantErr: "invalid except CIDR 10.0.0.300/32"},
+		{name: "ipv6", cidr: "fd00::/64", excepts: []string{"fd00::/80"}, wantErr: "except is only supported with IPv4 CIDR, got fd00::/64"},
+		{name: "outside", cidr: "10.0.0.0/24", excepts: []string{"10.0.1.0/28"}, wantErr: "except 10.0.1.0/28 is not strictly within CIDR 10.0.0.0/24"},
+		{name: "same", cidr: "10.0.0.0/24", excepts: []string{"10.0.0.0/24"}, wantErr: "except 10.0.0.0/24 is not strictly within CIDR 10.0.0.0/24"},
+		{name: "all", cidr: "10.0.0.0/24", excepts: []string{"10.0.0.0/25", "10.0.0.128/25"}, wantErr: "except excludes all the IPs of CIDR 10.0.0.0/24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCIDRExcept(tt.cidr, tt.excepts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
 func Test_calculateOffsetIP(t *testing.T) {
 	ip := net.ParseIP("192.168.0.1")
 	offset1 := 1